@@ -135,7 +135,7 @@ func TrackUserLoginSuccess(ctx context.Context, login string, uid string, md map
 		md["usr.id"] = uid
 	}
 
-	TrackCustomEvent(ctx, "users.login.success", md)
+	trackEvent(ctx, "users.login.success", md)
 	return setUser(ctx, uid, usersec.UserLoginSuccess, CollectionModeSDK, append(opts, tracer.WithUserLogin(login)))
 }
 
@@ -164,7 +164,9 @@ func TrackUserLoginFailure(ctx context.Context, login string, exists bool, md ma
 	md["usr.exists"] = strconv.FormatBool(exists)
 	md["usr.login"] = login
 
-	TrackCustomEvent(ctx, "users.login.failure", md)
+	if span := trackEvent(ctx, "users.login.failure", md); span != nil {
+		span.SetTag("_dd.appsec.user.collection_mode", CollectionModeSDK)
+	}
 
 	op, _ := usersec.StartUserLoginOperation(ctx, usersec.UserLoginFailure, usersec.UserLoginOperationArgs{})
 	op.Finish(usersec.UserLoginOperationRes{UserLogin: login})
@@ -178,10 +180,15 @@ func TrackUserLoginFailure(ctx context.Context, login string, exists bool, md ma
 // the IP address and/or user id associated to them.
 func TrackCustomEvent(ctx context.Context, name string, md map[string]string) {
 	telemetry.Count(telemetry.NamespaceAppSec, "sdk.event", []string{"event_type:custom", "sdk_version:v1"}).Submit(1)
+	trackEvent(ctx, name, md)
+}
 
+// trackEvent sets the event as service entry span tags, without reporting it as a custom event
+// in telemetry, and returns the service entry span, or nil when no span is found in ctx.
+func trackEvent(ctx context.Context, name string, md map[string]string) *tracer.Span {
 	span := getRootSpan(ctx)
 	if span == nil {
-		return
+		return nil
 	}
 
 	tagPrefix := "appsec.events." + name + "."
@@ -191,6 +198,7 @@ func TrackCustomEvent(ctx context.Context, name string, md map[string]string) {
 	for k, v := range md {
 		span.SetTag(tagPrefix+k, v)
 	}
+	return span
 }
 
 // Return the root span from the span stored in the given Go context.
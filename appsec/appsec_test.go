@@ -141,10 +141,13 @@ func TestTrackUserLoginFailure(t *testing.T) {
 				assertTag(t, finished, expectedEventPrefix+"usr.login", "user login")
 				assertTag(t, finished, expectedEventPrefix+"usr.exists", strconv.FormatBool(userExists))
 				assertTag(t, finished, expectedEventPrefix+"region", "us-east-1")
+				assertTag(t, finished, "_dd.appsec.user.collection_mode", "sdk")
 
 				metric := telemetryRecorder.Metrics[telemetrytest.MetricKey{Namespace: telemetry.NamespaceAppSec, Name: "sdk.event", Kind: "count", Tags: "event_type:login_failure,sdk_version:v2"}]
 				require.NotNil(t, metric)
 				assert.EqualValues(t, 1, metric.Get())
+				// login events aren't reported as custom events
+				assert.Nil(t, telemetryRecorder.Metrics[telemetrytest.MetricKey{Namespace: telemetry.NamespaceAppSec, Name: "sdk.event", Kind: "count", Tags: "event_type:custom,sdk_version:v1"}])
 			}
 		}
 		t.Run("user-exists", test(true))
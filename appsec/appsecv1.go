@@ -63,7 +63,7 @@ func TrackUserLoginFailureEvent(ctx context.Context, uid string, exists bool, md
 	span.SetTag("appsec.events.users.login.failure.usr.exists", strconv.FormatBool(exists))
 	span.SetTag("appsec.events.users.login.failure.usr.id", uid)
 
-	TrackCustomEvent(ctx, "users.login.failure", md)
+	trackEvent(ctx, "users.login.failure", md)
 
 	op, _ := usersec.StartUserLoginOperation(ctx, usersec.UserLoginFailure, usersec.UserLoginOperationArgs{})
 	op.Finish(usersec.UserLoginOperationRes{UserID: uid})
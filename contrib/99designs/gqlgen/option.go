@@ -19,6 +19,8 @@ type config struct {
 	withoutTraceTrivialResolvedFields bool
 	tags                              map[string]interface{}
 	errExtensions                     []string
+	fieldSpanLimit                    int
+	fieldEvents                       bool
 }
 
 // An Option describes options for the gqlgen integration.
@@ -38,6 +40,7 @@ func defaults(cfg *config) {
 	cfg.analyticsRate = instr.AnalyticsRate(false)
 	cfg.tags = make(map[string]interface{})
 	cfg.errExtensions = instrgraphql.ErrorExtensionsFromEnv()
+	cfg.fieldSpanLimit = -1
 }
 
 // WithAnalytics enables or disables Trace Analytics for all started spans.
@@ -93,3 +96,22 @@ func WithErrorExtensions(errExtensions ...string) OptionFn {
 		cfg.errExtensions = instrgraphql.ParseErrorExtensions(errExtensions)
 	}
 }
+
+// WithFieldSpanLimit limits the number of fields traced for each operation to n, so that
+// large queries don't produce a span explosion. The number of fields which were resolved
+// without being traced is reported in the graphql.fields.dropped tag of the operation span.
+// A negative n, the default, traces all the fields.
+func WithFieldSpanLimit(n int) OptionFn {
+	return func(cfg *config) {
+		cfg.fieldSpanLimit = n
+	}
+}
+
+// WithFieldEvents records the resolved fields as span events on the span of their operation,
+// with their path and duration, instead of creating a span for each of them. The limit set
+// with WithFieldSpanLimit applies to the events.
+func WithFieldEvents() OptionFn {
+	return func(cfg *config) {
+		cfg.fieldEvents = true
+	}
+}
@@ -26,6 +26,7 @@ import (
 	instrgraphql "github.com/DataDog/dd-trace-go/v2/instrumentation/graphql"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
@@ -77,6 +78,15 @@ func (t *gqlTracer) Validate(_ graphql.ExecutableSchema) error {
 func (t *gqlTracer) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
 	opCtx := graphql.GetOperationContext(ctx)
 	span, ctx := t.createRootSpan(ctx, opCtx)
+	sampler := instrgraphql.NewFieldSampler(t.cfg.fieldSpanLimit, t.cfg.fieldEvents && span != nil)
+	ctx = context.WithValue(ctx, operationKey{}, &operation{span: span, sampler: sampler})
+	if span != nil {
+		span.SetTag(instrgraphql.TagOperationDepth, selectionDepth(opCtx.Operation.SelectionSet, nil))
+		// the complexity is only known when the complexity extension is used
+		if stats := extension.GetComplexityStats(ctx); stats != nil {
+			span.SetTag(instrgraphql.TagOperationComplexity, stats.Complexity)
+		}
+	}
 	ctx, req := graphqlsec.StartRequestOperation(ctx, span, graphqlsec.RequestOperationArgs{
 		RawQuery:      opCtx.RawQuery,
 		OperationName: opCtx.OperationName,
@@ -96,6 +106,7 @@ func (t *gqlTracer) InterceptOperation(ctx context.Context, next graphql.Operati
 				spanErr = response.Errors
 				instrgraphql.AddErrorsAsSpanEvents(span, toGraphqlErrors(response.Errors), t.cfg.errExtensions)
 			}
+			sampler.Finish(span)
 			defer span.Finish(tracer.WithError(spanErr))
 		}
 
@@ -131,6 +142,20 @@ func (t *gqlTracer) InterceptField(ctx context.Context, next graphql.Resolver) (
 		return
 	}
 
+	if op, ok := ctx.Value(operationKey{}).(*operation); ok {
+		if !op.sampler.Sample() {
+			return t.resolveField(ctx, fieldCtx, isTrivial, next)
+		}
+		if op.sampler.Events() {
+			start := time.Now()
+			defer func() {
+				resource := fmt.Sprintf("%s.%s", fieldCtx.Object, fieldCtx.Field.Name)
+				op.sampler.RecordField(op.span, resource, fieldCtx.Path().String(), start, err)
+			}()
+			return t.resolveField(ctx, fieldCtx, isTrivial, next)
+		}
+	}
+
 	opts := make([]tracer.StartSpanOption, 0, 6+len(t.cfg.tags))
 	for k, v := range t.cfg.tags {
 		opts = append(opts, tracer.Tag(k, v))
@@ -148,6 +173,11 @@ func (t *gqlTracer) InterceptField(ctx context.Context, next graphql.Resolver) (
 
 	span, ctx := tracer.StartSpanFromContext(ctx, fieldOp, opts...)
 	defer func() { span.Finish(tracer.WithError(err)) }()
+	return t.resolveField(ctx, fieldCtx, isTrivial, next)
+}
+
+// resolveField resolves a field, monitored by AppSec.
+func (*gqlTracer) resolveField(ctx context.Context, fieldCtx *graphql.FieldContext, isTrivial bool, next graphql.Resolver) (res any, err error) {
 	ctx, op := graphqlsec.StartResolveOperation(ctx, graphqlsec.ResolveOperationArgs{
 		Arguments: fieldCtx.Args,
 		TypeName:  fieldCtx.Object,
@@ -160,6 +190,43 @@ func (t *gqlTracer) InterceptField(ctx context.Context, next graphql.Resolver) (
 	return
 }
 
+// operationKey is the context key of the operation of the resolved fields.
+type operationKey struct{}
+
+// operation holds the span of an operation, nil for subscriptions, and the sampler of its
+// traced fields.
+type operation struct {
+	span    *tracer.Span
+	sampler *instrgraphql.FieldSampler
+}
+
+// selectionDepth returns the depth of set, fragments included. visited holds the fragments
+// being expanded, to guard against cycles.
+func selectionDepth(set ast.SelectionSet, visited map[string]bool) int {
+	depth := 0
+	for _, sel := range set {
+		var d int
+		switch sel := sel.(type) {
+		case *ast.Field:
+			d = selectionDepth(sel.SelectionSet, visited) + 1
+		case *ast.InlineFragment:
+			d = selectionDepth(sel.SelectionSet, visited)
+		case *ast.FragmentSpread:
+			if sel.Definition == nil || visited[sel.Name] {
+				continue
+			}
+			if visited == nil {
+				visited = make(map[string]bool)
+			}
+			visited[sel.Name] = true
+			d = selectionDepth(sel.Definition.SelectionSet, visited)
+			delete(visited, sel.Name)
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
+
 func (*gqlTracer) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
 	return next(ctx)
 }
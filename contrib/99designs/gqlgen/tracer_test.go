@@ -10,6 +10,7 @@ import (
 
 	"github.com/99designs/gqlgen/client"
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/testserver"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/stretchr/testify/assert"
@@ -366,3 +367,75 @@ func TestErrorsAsSpanEvents(t *testing.T) {
 		assert.Emptyf(t, s.Events(), "span %s should not have span events", s.OperationName())
 	}
 }
+
+func TestFieldSampling(t *testing.T) {
+	rootAndFields := func(spans []*mocktracer.Span) (root *mocktracer.Span, fields []*mocktracer.Span) {
+		for _, span := range spans {
+			if span.ParentID() == 0 {
+				root = span
+			}
+			if span.OperationName() == fieldOp {
+				fields = append(fields, span)
+			}
+		}
+		return root, fields
+	}
+
+	t.Run("default", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		c := newTestClient(t, testserver.New(), NewTracer())
+		c.MustPost(`{ name }`, &testServerResponse{})
+
+		root, fields := rootAndFields(mt.FinishedSpans())
+		require.NotNil(t, root)
+		assert.Len(t, fields, 1)
+		assert.Equal(t, float64(1), root.Tag("graphql.operation.depth"))
+		// the complexity is unknown without the complexity extension
+		assert.Nil(t, root.Tag("graphql.operation.complexity"))
+		assert.Nil(t, root.Tag("graphql.fields.dropped"))
+		assert.Empty(t, root.Events())
+	})
+
+	t.Run("complexity", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		srv := testserver.New()
+		srv.Use(extension.FixedComplexityLimit(100))
+		c := newTestClient(t, srv, NewTracer())
+		c.MustPost(`{ name }`, &testServerResponse{})
+
+		root, _ := rootAndFields(mt.FinishedSpans())
+		require.NotNil(t, root)
+		assert.NotNil(t, root.Tag("graphql.operation.complexity"))
+	})
+
+	t.Run("WithFieldSpanLimit", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		c := newTestClient(t, testserver.New(), NewTracer(WithFieldSpanLimit(0)))
+		c.MustPost(`{ name }`, &testServerResponse{})
+
+		root, fields := rootAndFields(mt.FinishedSpans())
+		require.NotNil(t, root)
+		assert.Empty(t, fields)
+		assert.Equal(t, float64(1), root.Tag("graphql.fields.dropped"))
+	})
+
+	t.Run("WithFieldEvents", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		c := newTestClient(t, testserver.New(), NewTracer(WithFieldEvents()))
+		c.MustPost(`{ name }`, &testServerResponse{})
+
+		root, fields := rootAndFields(mt.FinishedSpans())
+		require.NotNil(t, root)
+		assert.Empty(t, fields)
+		events := root.Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, "graphql.field", events[0].Name)
+		assert.Equal(t, "Query.name", events[0].Attributes["resource"])
+		assert.Equal(t, "name", events[0].Attributes["path"])
+		assert.NotNil(t, events[0].Attributes["duration_ms"])
+	})
+}
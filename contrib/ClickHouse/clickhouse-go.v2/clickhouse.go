@@ -0,0 +1,245 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package clickhouse provides functions to trace the ClickHouse/clickhouse-go/v2 package.
+//
+// The queries, statements, batches and asynchronous inserts made through a connection
+// returned by Open or WrapConn are traced, and their spans are tagged with the compression
+// and asynchronous insert settings of the connection.
+package clickhouse // import "github.com/DataDog/dd-trace-go/contrib/ClickHouse/clickhouse-go.v2/v2"
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+const componentName = instrumentation.PackageClickHouseGoV2
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageClickHouseGoV2)
+}
+
+// Names of the spans of the operations of a connection.
+const (
+	opQuery       = "clickhouse.query"
+	opExec        = "clickhouse.exec"
+	opBatch       = "clickhouse.batch"
+	opAsyncInsert = "clickhouse.async_insert"
+	opPing        = "clickhouse.ping"
+)
+
+const (
+	// tagProtocol is the protocol of the connection, native or http.
+	tagProtocol = "clickhouse.protocol"
+	// tagCompressionMethod is the method used to compress the data sent and received.
+	tagCompressionMethod = "clickhouse.compression.method"
+	// tagCompressionLevel is the level of the compression of the data sent.
+	tagCompressionLevel = "clickhouse.compression.level"
+	// tagAsyncInsert is set on the spans of the inserts buffered by the server before being
+	// written, either with AsyncInsert or with the async_insert setting.
+	tagAsyncInsert = "clickhouse.async_insert"
+	// tagAsyncInsertWait reports whether an asynchronous insert waited for the data to be
+	// written.
+	tagAsyncInsertWait = "clickhouse.async_insert.wait"
+	// tagBatchRows is the number of rows sent in a batch.
+	tagBatchRows = "clickhouse.batch.rows"
+	// tagRows is the number of rows read from the result of a query.
+	tagRows = "clickhouse.rows"
+)
+
+// WrapConn returns a driver.Conn tracing the operations of conn. The options used to open
+// conn are used to tag the spans, and may be nil.
+func WrapConn(conn driver.Conn, opt *clickhouse.Options, opts ...Option) driver.Conn {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/ClickHouse/clickhouse-go.v2: Wrapping Conn: %#v", cfg)
+	return &tracedConn{
+		Conn:        conn,
+		cfg:         cfg,
+		tags:        connTags(opt),
+		asyncInsert: opt != nil && isEnabled(opt.Settings["async_insert"]),
+	}
+}
+
+// Open opens a connection to ClickHouse with the given options, with clickhouse.Open, and
+// returns it traced.
+func Open(opt *clickhouse.Options, opts ...Option) (driver.Conn, error) {
+	conn, err := clickhouse.Open(opt)
+	if err != nil {
+		return nil, err
+	}
+	return WrapConn(conn, opt, opts...), nil
+}
+
+// connTags returns the tags of the spans of a connection opened with opt.
+func connTags(opt *clickhouse.Options) map[string]any {
+	tags := map[string]any{}
+	if opt == nil {
+		return tags
+	}
+	tags[tagProtocol] = opt.Protocol.String()
+	if len(opt.Addr) > 0 {
+		if host, port, err := net.SplitHostPort(opt.Addr[0]); err == nil {
+			tags[ext.NetworkDestinationName] = host
+			tags[ext.NetworkDestinationPort] = port
+		} else {
+			tags[ext.NetworkDestinationName] = opt.Addr[0]
+		}
+	}
+	if opt.Auth.Database != "" {
+		tags[ext.DBName] = opt.Auth.Database
+	}
+	if opt.Auth.Username != "" {
+		tags[ext.DBUser] = opt.Auth.Username
+	}
+	if c := opt.Compression; c != nil {
+		tags[tagCompressionMethod] = c.Method.String()
+		if c.Level != 0 {
+			tags[tagCompressionLevel] = c.Level
+		}
+	}
+	return tags
+}
+
+// isEnabled reports whether the value of a boolean setting enables it.
+func isEnabled(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		s := fmt.Sprint(v)
+		return s == "1" || strings.EqualFold(s, "true")
+	}
+}
+
+// isInsert reports whether query is an INSERT statement.
+func isInsert(query string) bool {
+	query = strings.TrimSpace(query)
+	return len(query) >= len("INSERT") && strings.EqualFold(query[:len("INSERT")], "INSERT")
+}
+
+type tracedConn struct {
+	driver.Conn
+	cfg         *config
+	tags        map[string]any
+	asyncInsert bool
+}
+
+// startSpan starts the span of an operation, with the given query if any as resource.
+func (c *tracedConn) startSpan(ctx context.Context, op, query string, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context) {
+	opts = append([]tracer.StartSpanOption{
+		tracer.ServiceName(c.cfg.serviceName),
+		tracer.SpanType(ext.SpanTypeSQL),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		tracer.Tag(ext.DBSystem, ext.DBSystemClickHouse),
+	}, opts...)
+	if query != "" {
+		opts = append(opts, tracer.ResourceName(query), tracer.Tag(ext.DBStatement, query))
+		if c.asyncInsert && isInsert(query) {
+			opts = append(opts, tracer.Tag(tagAsyncInsert, true))
+		}
+	} else {
+		opts = append(opts, tracer.ResourceName(op))
+	}
+	for k, v := range c.tags {
+		opts = append(opts, tracer.Tag(k, v))
+	}
+	opts = append(opts, c.cfg.spanOpts...)
+	return tracer.StartSpanFromContext(ctx, op, opts...)
+}
+
+// startQuerySpan starts the span of a query or statement, collecting its statistics if
+// enabled.
+func (c *tracedConn) startQuerySpan(ctx context.Context, op, query string, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context, *queryStats) {
+	span, ctx := c.startSpan(ctx, op, query, opts...)
+	if !c.cfg.statistics {
+		return span, ctx, nil
+	}
+	stats := new(queryStats)
+	return span, stats.context(ctx), stats
+}
+
+// finishSpan finishes span with the given error, tagging it with the statistics of its
+// query, if collected.
+func finishSpan(span *tracer.Span, stats *queryStats, err error) {
+	stats.tag(span)
+	span.Finish(tracer.WithError(err))
+}
+
+func (c *tracedConn) Select(ctx context.Context, dest any, query string, args ...any) (err error) {
+	span, ctx, stats := c.startQuerySpan(ctx, opQuery, query)
+	defer func() { finishSpan(span, stats, err) }()
+	return c.Conn.Select(ctx, dest, query, args...)
+}
+
+// Query runs query, tracing it until the returned rows are closed.
+func (c *tracedConn) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	span, ctx, stats := c.startQuerySpan(ctx, opQuery, query)
+	rows, err := c.Conn.Query(ctx, query, args...)
+	if err != nil {
+		finishSpan(span, stats, err)
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span, stats: stats}, nil
+}
+
+// QueryRow runs query, tracing it until the returned row is scanned.
+func (c *tracedConn) QueryRow(ctx context.Context, query string, args ...any) driver.Row {
+	span, ctx, stats := c.startQuerySpan(ctx, opQuery, query)
+	row := c.Conn.QueryRow(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		finishSpan(span, stats, err)
+		return row
+	}
+	return &tracedRow{Row: row, span: span, stats: stats}
+}
+
+// PrepareBatch prepares a batch for the given INSERT statement, tracing it until it's sent
+// or aborted.
+func (c *tracedConn) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	span, ctx, stats := c.startQuerySpan(ctx, opBatch, query)
+	batch, err := c.Conn.PrepareBatch(ctx, query, opts...)
+	if err != nil {
+		finishSpan(span, stats, err)
+		return nil, err
+	}
+	return &tracedBatch{Batch: batch, span: span, stats: stats}, nil
+}
+
+func (c *tracedConn) Exec(ctx context.Context, query string, args ...any) (err error) {
+	span, ctx, stats := c.startQuerySpan(ctx, opExec, query)
+	defer func() { finishSpan(span, stats, err) }()
+	return c.Conn.Exec(ctx, query, args...)
+}
+
+// AsyncInsert runs the given INSERT statement as an asynchronous insert, buffered by the
+// server before being written, tracing it.
+func (c *tracedConn) AsyncInsert(ctx context.Context, query string, wait bool, args ...any) (err error) {
+	span, ctx, stats := c.startQuerySpan(ctx, opAsyncInsert, query,
+		tracer.Tag(tagAsyncInsert, true),
+		tracer.Tag(tagAsyncInsertWait, wait),
+	)
+	defer func() { finishSpan(span, stats, err) }()
+	return c.Conn.AsyncInsert(ctx, query, wait, args...)
+}
+
+func (c *tracedConn) Ping(ctx context.Context) (err error) {
+	span, ctx := c.startSpan(ctx, opPing, "")
+	defer func() { span.Finish(tracer.WithError(err)) }()
+	return c.Conn.Ping(ctx)
+}
@@ -0,0 +1,197 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package clickhouse
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOptions() *clickhouse.Options {
+	return &clickhouse.Options{
+		Addr: []string{"127.0.0.1:9000"},
+		Auth: clickhouse.Auth{
+			Database: "default",
+			Username: "default",
+		},
+		Compression: &clickhouse.Compression{
+			Method: clickhouse.CompressionLZ4,
+		},
+	}
+}
+
+func openTestConn(t *testing.T, opt *clickhouse.Options, opts ...Option) driver.Conn {
+	if _, ok := os.LookupEnv("INTEGRATION"); !ok {
+		t.Skip("to enable integration test, set the INTEGRATION environment variable")
+	}
+	conn, err := Open(opt, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestConnTags(t *testing.T) {
+	opt := testOptions()
+	opt.Compression.Level = 3
+	tags := connTags(opt)
+	assert.Equal(t, "127.0.0.1", tags[ext.NetworkDestinationName])
+	assert.Equal(t, "9000", tags[ext.NetworkDestinationPort])
+	assert.Equal(t, "default", tags[ext.DBName])
+	assert.Equal(t, "default", tags[ext.DBUser])
+	assert.Equal(t, "lz4", tags[tagCompressionMethod])
+	assert.Equal(t, 3, tags[tagCompressionLevel])
+	assert.Equal(t, "native", tags[tagProtocol])
+
+	assert.Empty(t, connTags(nil))
+}
+
+func TestIsEnabled(t *testing.T) {
+	for _, v := range []any{1, "1", true, "true", uint8(1)} {
+		assert.True(t, isEnabled(v), "%#v", v)
+	}
+	for _, v := range []any{nil, 0, "0", false, "false"} {
+		assert.False(t, isEnabled(v), "%#v", v)
+	}
+}
+
+func TestIsInsert(t *testing.T) {
+	assert.True(t, isInsert("INSERT INTO t VALUES (1)"))
+	assert.True(t, isInsert("  insert into t"))
+	assert.False(t, isInsert("SELECT 1"))
+	assert.False(t, isInsert("INS"))
+}
+
+func TestQueryStats(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c := WrapConn(nil, nil, WithQueryStatistics()).(*tracedConn)
+	span, _, stats := c.startQuerySpan(context.Background(), opQuery, "SELECT 1")
+	require.NotNil(t, stats)
+	stats.onProgress(&clickhouse.Progress{Rows: 2, Bytes: 16})
+	stats.onProgress(&clickhouse.Progress{Rows: 3, Bytes: 24})
+	stats.onProfileInfo(&clickhouse.ProfileInfo{Rows: 1, Bytes: 8, Blocks: 1})
+	finishSpan(span, stats, nil)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Equal(t, float64(5), s.Tag(tagReadRows))
+	assert.Equal(t, float64(40), s.Tag(tagReadBytes))
+	assert.Equal(t, float64(0), s.Tag(tagWrittenRows))
+	assert.Equal(t, float64(1), s.Tag(tagResultBlocks))
+	assert.Equal(t, float64(1), s.Tag(tagResultRows))
+	assert.Equal(t, float64(8), s.Tag(tagResultBytes))
+}
+
+func TestAsyncInsertSetting(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	opt := testOptions()
+	opt.Settings = clickhouse.Settings{"async_insert": 1}
+	c := WrapConn(nil, opt).(*tracedConn)
+	span, _ := c.startSpan(context.Background(), opExec, "INSERT INTO t VALUES (1)")
+	span.Finish()
+	span, _ = c.startSpan(context.Background(), opQuery, "SELECT 1")
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "true", spans[0].Tag(tagAsyncInsert))
+	assert.Nil(t, spans[1].Tag(tagAsyncInsert))
+}
+
+func TestQuery(t *testing.T) {
+	conn := openTestConn(t, testOptions(), WithQueryStatistics())
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	rows, err := conn.Query(context.Background(), "SELECT number FROM system.numbers LIMIT 3")
+	require.NoError(t, err)
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	require.NoError(t, rows.Close())
+	assert.Equal(t, 3, n)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Equal(t, "clickhouse.query", s.OperationName())
+	assert.Equal(t, "SELECT number FROM system.numbers LIMIT 3", s.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanTypeSQL, s.Tag(ext.SpanType))
+	assert.Equal(t, "clickhouse", s.Tag(ext.ServiceName))
+	assert.Equal(t, ext.DBSystemClickHouse, s.Tag(ext.DBSystem))
+	assert.Equal(t, string(instrumentation.PackageClickHouseGoV2), s.Tag(ext.Component))
+	assert.Equal(t, ext.SpanKindClient, s.Tag(ext.SpanKind))
+	assert.Equal(t, "lz4", s.Tag(tagCompressionMethod))
+	assert.Equal(t, float64(3), s.Tag(tagRows))
+	assert.NotNil(t, s.Tag(tagReadRows))
+}
+
+func TestQueryRow(t *testing.T) {
+	conn := openTestConn(t, testOptions())
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var v uint8
+	require.NoError(t, conn.QueryRow(context.Background(), "SELECT toUInt8(1)").Scan(&v))
+	assert.Equal(t, uint8(1), v)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "SELECT toUInt8(1)", spans[0].Tag(ext.ResourceName))
+}
+
+func TestBatchAndAsyncInsert(t *testing.T) {
+	conn := openTestConn(t, testOptions())
+	ctx := context.Background()
+	require.NoError(t, conn.Exec(ctx, "CREATE TABLE IF NOT EXISTS dd_trace_test (id UInt64) ENGINE = Memory"))
+	t.Cleanup(func() { conn.Exec(ctx, "DROP TABLE IF EXISTS dd_trace_test") })
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO dd_trace_test")
+	require.NoError(t, err)
+	require.NoError(t, batch.Append(uint64(1)))
+	require.NoError(t, batch.Append(uint64(2)))
+	require.NoError(t, batch.Send())
+	require.NoError(t, conn.AsyncInsert(ctx, "INSERT INTO dd_trace_test VALUES (3)", true))
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "clickhouse.batch", spans[0].OperationName())
+	assert.Equal(t, float64(2), spans[0].Tag(tagBatchRows))
+	assert.Equal(t, "clickhouse.async_insert", spans[1].OperationName())
+	assert.Equal(t, "true", spans[1].Tag(tagAsyncInsert))
+	assert.Equal(t, "true", spans[1].Tag(tagAsyncInsertWait))
+}
+
+func TestError(t *testing.T) {
+	conn := openTestConn(t, testOptions())
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	err := conn.Exec(context.Background(), "SELECT * FROM unknown_table")
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "clickhouse.exec", spans[0].OperationName())
+	assert.NotNil(t, spans[0].Tag(ext.ErrorMsg))
+}
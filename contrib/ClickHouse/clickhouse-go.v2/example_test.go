@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package clickhouse_test
+
+import (
+	"context"
+	"log"
+
+	clickhousetrace "github.com/DataDog/dd-trace-go/contrib/ClickHouse/clickhouse-go.v2/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	conn, err := clickhousetrace.Open(&clickhouse.Options{
+		Addr: []string{"127.0.0.1:9000"},
+		Compression: &clickhouse.Compression{
+			Method: clickhouse.CompressionLZ4,
+		},
+	}, clickhousetrace.WithService("events-db"), clickhousetrace.WithQueryStatistics())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	var count uint64
+	if err := conn.QueryRow(context.Background(), "SELECT count() FROM events").Scan(&count); err != nil {
+		log.Fatal(err)
+	}
+}
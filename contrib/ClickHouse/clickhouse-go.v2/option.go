@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package clickhouse
+
+import (
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	serviceName string
+	statistics  bool
+	spanOpts    []tracer.StartSpanOption
+}
+
+// Option represents an option that can be used to trace a ClickHouse connection.
+type Option func(*config)
+
+func defaultConfig() *config {
+	cfg := &config{
+		serviceName: instr.ServiceName(instrumentation.ComponentDefault, nil),
+	}
+	if rate := instr.AnalyticsRate(false); !math.IsNaN(rate) {
+		cfg.spanOpts = append(cfg.spanOpts, tracer.AnalyticsRate(rate))
+	}
+	return cfg
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the spans of the connection.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithQueryStatistics tags the spans of the queries with the statistics reported by the
+// server while running them: the rows and bytes read and written, and the blocks, rows and
+// bytes of the result. Statistics are only reported over the native protocol.
+//
+// The statistics are collected by setting the progress and profile info callbacks of the
+// queries, so this replaces the callbacks set with clickhouse.WithProgress and
+// clickhouse.WithProfileInfo in the context of the queries.
+func WithQueryStatistics() Option {
+	return func(cfg *config) {
+		cfg.statistics = true
+	}
+}
+
+// WithSpanOptions defines a set of additional tracer.StartSpanOption to be added
+// to the spans of the connection.
+func WithSpanOptions(opts ...tracer.StartSpanOption) Option {
+	return func(cfg *config) {
+		cfg.spanOpts = append(cfg.spanOpts, opts...)
+	}
+}
@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package clickhouse
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// tracedRows finishes the span of a query when its rows are closed.
+type tracedRows struct {
+	driver.Rows
+	span  *tracer.Span
+	stats *queryStats
+	rows  int
+}
+
+func (r *tracedRows) Next() bool {
+	if !r.Rows.Next() {
+		return false
+	}
+	r.rows++
+	return true
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	if r.span != nil {
+		r.span.SetTag(tagRows, r.rows)
+		if err == nil {
+			err = r.Rows.Err()
+		}
+		finishSpan(r.span, r.stats, err)
+		r.span = nil
+	}
+	return err
+}
+
+// tracedRow finishes the span of a query when its row is scanned.
+type tracedRow struct {
+	driver.Row
+	span  *tracer.Span
+	stats *queryStats
+}
+
+func (r *tracedRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	r.finish(err)
+	return err
+}
+
+func (r *tracedRow) ScanStruct(dest any) error {
+	err := r.Row.ScanStruct(dest)
+	r.finish(err)
+	return err
+}
+
+func (r *tracedRow) finish(err error) {
+	if r.span == nil {
+		return
+	}
+	finishSpan(r.span, r.stats, err)
+	r.span = nil
+}
+
+// tracedBatch finishes the span of a batch when it's sent or aborted.
+type tracedBatch struct {
+	driver.Batch
+	span  *tracer.Span
+	stats *queryStats
+}
+
+func (b *tracedBatch) Send() error {
+	rows := b.Batch.Rows()
+	err := b.Batch.Send()
+	if b.span != nil {
+		b.span.SetTag(tagBatchRows, rows)
+	}
+	b.finish(err)
+	return err
+}
+
+func (b *tracedBatch) Abort() error {
+	err := b.Batch.Abort()
+	b.finish(err)
+	return err
+}
+
+func (b *tracedBatch) finish(err error) {
+	if b.span == nil {
+		return
+	}
+	finishSpan(b.span, b.stats, err)
+	b.span = nil
+}
@@ -0,0 +1,92 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package clickhouse
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// Tags of the statistics of queries, set with WithQueryStatistics.
+const (
+	tagReadRows     = "clickhouse.read_rows"
+	tagReadBytes    = "clickhouse.read_bytes"
+	tagWrittenRows  = "clickhouse.written_rows"
+	tagWrittenBytes = "clickhouse.written_bytes"
+	tagResultBlocks = "clickhouse.result.blocks"
+	tagResultRows   = "clickhouse.result.rows"
+	tagResultBytes  = "clickhouse.result.bytes"
+)
+
+// queryStats accumulates the progress and profile info packets sent by the server while
+// running a query. The packets are received by the goroutine reading the result of the
+// query, so the statistics are guarded by a mutex.
+type queryStats struct {
+	mu           sync.Mutex
+	progress     bool
+	readRows     uint64
+	readBytes    uint64
+	writtenRows  uint64
+	writtenBytes uint64
+	profile      bool
+	blocks       uint64
+	rows         uint64
+	bytes        uint64
+}
+
+// context returns a context setting the callbacks collecting the statistics of the query
+// run with it.
+func (s *queryStats) context(ctx context.Context) context.Context {
+	return clickhouse.Context(ctx,
+		clickhouse.WithProgress(s.onProgress),
+		clickhouse.WithProfileInfo(s.onProfileInfo),
+	)
+}
+
+func (s *queryStats) onProgress(p *clickhouse.Progress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// progress packets carry the increments since the previous packet
+	s.progress = true
+	s.readRows += p.Rows
+	s.readBytes += p.Bytes
+	s.writtenRows += p.WrittenRows
+	s.writtenBytes += p.WrittenBytes
+}
+
+func (s *queryStats) onProfileInfo(p *clickhouse.ProfileInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profile = true
+	s.blocks += p.Blocks
+	s.rows += p.Rows
+	s.bytes += p.Bytes
+}
+
+// tag sets the statistics of the query on span. It does nothing if s is nil, as when the
+// statistics aren't collected.
+func (s *queryStats) tag(span *tracer.Span) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.progress {
+		span.SetTag(tagReadRows, s.readRows)
+		span.SetTag(tagReadBytes, s.readBytes)
+		span.SetTag(tagWrittenRows, s.writtenRows)
+		span.SetTag(tagWrittenBytes, s.writtenBytes)
+	}
+	if s.profile {
+		span.SetTag(tagResultBlocks, s.blocks)
+		span.SetTag(tagResultRows, s.rows)
+		span.SetTag(tagResultBytes, s.bytes)
+	}
+}
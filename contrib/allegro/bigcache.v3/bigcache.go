@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package bigcache provides functions to trace the allegro/bigcache package (https://github.com/allegro/bigcache).
+//
+// The hits and misses of the lookups made with the Get method of a BigCache are recorded as
+// span events on the span found in their context, so that the effectiveness of the cache can
+// be analyzed per endpoint. Hits, misses and evictions can also be sent as statsd metrics.
+package bigcache // import "github.com/DataDog/dd-trace-go/contrib/allegro/bigcache.v3/v2"
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageAllegroBigcacheV3)
+}
+
+// Option describes options for the bigcache integration, set with the options of the
+// cachetrace package, e.g. cachetrace.WithName.
+type Option = cachetrace.Option
+
+// BigCache is a bigcache.BigCache recording the hits and misses of its lookups.
+type BigCache struct {
+	*bigcache.BigCache
+	rec *cachetrace.Recorder
+}
+
+// New returns a new BigCache with the given configuration. When the metrics are enabled, the
+// removal callbacks of the configuration are wrapped to count the entries removed because they
+// expired or for lack of space. The evictions aren't counted when OnRemoveWithMetadata is set,
+// since bigcache doesn't call the other callbacks then.
+func New(ctx context.Context, config bigcache.Config, opts ...Option) (*BigCache, error) {
+	cfg := cachetrace.NewConfig("bigcache", "", opts...)
+	instr.Logger().Debug("contrib/allegro/bigcache.v3: Configuring BigCache: %#v", cfg)
+	rec := cachetrace.NewRecorder(instr, cfg)
+	if rec.MetricsEnabled() && config.OnRemoveWithMetadata == nil {
+		config = withEvictions(config, rec)
+	}
+	cache, err := bigcache.New(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &BigCache{BigCache: cache, rec: rec}, nil
+}
+
+// withEvictions returns config with an OnRemoveWithReason callback recording the evictions
+// with rec, calling the removal callback of config.
+func withEvictions(config bigcache.Config, rec *cachetrace.Recorder) bigcache.Config {
+	onRemove, onRemoveWithReason := config.OnRemove, config.OnRemoveWithReason
+	// bigcache ignores OnRemoveWithReason when OnRemove is set.
+	config.OnRemove = nil
+	config.OnRemoveWithReason = func(key string, entry []byte, reason bigcache.RemoveReason) {
+		switch reason {
+		case bigcache.Expired:
+			rec.Evicted("expired", 1)
+		case bigcache.NoSpace:
+			rec.Evicted("no_space", 1)
+		}
+		if onRemove != nil {
+			onRemove(key, entry)
+		} else if onRemoveWithReason != nil {
+			onRemoveWithReason(key, entry, reason)
+		}
+	}
+	return config
+}
+
+// Get returns the entry of key, recording the lookup as a hit or a miss on the span found in
+// ctx. bigcache.ErrEntryNotFound is returned when the entry isn't found.
+func (c *BigCache) Get(ctx context.Context, key string) ([]byte, error) {
+	entry, err := c.BigCache.Get(key)
+	switch {
+	case err == nil:
+		c.rec.Hit(ctx)
+	case errors.Is(err, bigcache.ErrEntryNotFound):
+		c.rec.Miss(ctx)
+	}
+	return entry, err
+}
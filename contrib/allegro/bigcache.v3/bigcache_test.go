@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package bigcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withStatsdClient(s instrumentation.StatsdClient) Option {
+	return func(c *cachetrace.Config) {
+		c.Metrics = true
+		c.Statsd = s
+	}
+}
+
+func TestGet(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	statsd := testutils.NewMockStatsdClient()
+	cache, err := New(context.Background(), bigcache.DefaultConfig(time.Minute), cachetrace.WithName("users"), withStatsdClient(statsd))
+	require.NoError(t, err)
+	defer cache.Close()
+	require.NoError(t, cache.Set("a", []byte("1")))
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "request")
+	entry, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), entry)
+	_, err = cache.Get(ctx, "b")
+	assert.ErrorIs(t, err, bigcache.ErrEntryNotFound)
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, cachetrace.EventHit, events[0].Name)
+	assert.Equal(t, cachetrace.EventMiss, events[1].Name)
+	events[0].AssertAttributes(t, map[string]any{cachetrace.AttributeName: "users", cachetrace.AttributeSystem: "bigcache"})
+
+	counts := statsd.Counts()
+	assert.Equal(t, int64(1), counts[cachetrace.MetricHits])
+	assert.Equal(t, int64(1), counts[cachetrace.MetricMisses])
+}
+
+func TestEvictions(t *testing.T) {
+	statsd := testutils.NewMockStatsdClient()
+	rec := cachetrace.NewRecorder(nil, cachetrace.Config{System: "bigcache", Metrics: true, Statsd: statsd})
+	var removed []string
+	config := withEvictions(bigcache.Config{
+		OnRemove: func(key string, _ []byte) { removed = append(removed, key) },
+	}, rec)
+	assert.Nil(t, config.OnRemove)
+
+	config.OnRemoveWithReason("a", nil, bigcache.Expired)
+	config.OnRemoveWithReason("b", nil, bigcache.NoSpace)
+	config.OnRemoveWithReason("c", nil, bigcache.Deleted)
+	assert.Equal(t, []string{"a", "b", "c"}, removed)
+	assert.Equal(t, int64(2), statsd.Counts()[cachetrace.MetricEvictions])
+}
@@ -0,0 +1,39 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package bigcache_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	bigcachetrace "github.com/DataDog/dd-trace-go/contrib/allegro/bigcache.v3/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	cache, err := bigcachetrace.New(context.Background(), bigcache.DefaultConfig(10*time.Minute),
+		cachetrace.WithName("sessions"), cachetrace.WithMetrics(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cache.Close()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "web.request")
+	defer span.Finish()
+
+	// The lookup is recorded as a "cache.hit" or "cache.miss" event of the span.
+	if _, err := cache.Get(ctx, "session:42"); errors.Is(err, bigcache.ErrEntryNotFound) {
+		cache.Set("session:42", []byte("{}"))
+	}
+}
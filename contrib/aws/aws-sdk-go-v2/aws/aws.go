@@ -21,11 +21,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sfn"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/aws/smithy-go/middleware"
 	smithyhttp "github.com/aws/smithy-go/transport/http"
 
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
+	dynamoDBTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/dynamodb"
 	eventBridgeTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/eventbridge"
+	s3Tracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/s3"
 	sfnTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/sfn"
 	snsTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/sns"
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/spanpointers"
@@ -41,6 +44,10 @@ var instr = internal.Instr
 
 type spanTimestampKey struct{}
 
+// operationParamsKey is the key of the input parameters of the operation in the context of
+// the request, for the services whose response attributes depend on them.
+type operationParamsKey struct{}
+
 // AppendMiddleware takes the aws.Config and adds the Datadog tracing middleware into the APIOptions middleware stack.
 // See https://aws.github.io/aws-sdk-go-v2/docs/middleware for more information.
 func AppendMiddleware(awsCfg *aws.Config, opts ...Option) {
@@ -55,6 +62,21 @@ func AppendMiddleware(awsCfg *aws.Config, opts ...Option) {
 	awsCfg.APIOptions = append(awsCfg.APIOptions, tm.initTraceMiddleware, tm.startTraceMiddleware, tm.deserializeTraceMiddleware)
 }
 
+// ExtractSQSMessageContext returns the trace context propagated in msg, a message received
+// from SQS, by the span which sent it to SQS or published it to an SNS topic the queue is
+// subscribed to. It can be used to trace the processing of the message as a child of that span:
+//
+//	spanCtx, err := aws.ExtractSQSMessageContext(msg)
+//	if err == nil {
+//		span := tracer.StartSpan("process", tracer.ChildOf(spanCtx))
+//		defer span.Finish()
+//	}
+//
+// The spans of ReceiveMessage requests are linked to the spans which sent the received messages.
+func ExtractSQSMessageContext(msg sqstypes.Message) (*tracer.SpanContext, error) {
+	return sqsTracer.ExtractSpanContext(msg)
+}
+
 type traceMiddleware struct {
 	cfg *config
 }
@@ -118,6 +140,10 @@ func (mw *traceMiddleware) startTraceMiddleware(stack *middleware.Stack) error {
 			sfnTracer.EnrichOperation(span, in, operation)
 		case "DynamoDB":
 			spanctx = spanpointers.SetDynamoDbParamsOnContext(spanctx, in.Parameters)
+		case "S3":
+			if mw.cfg.s3Attributes {
+				spanctx = context.WithValue(spanctx, operationParamsKey{}, in.Parameters)
+			}
 		}
 
 		// Handle initialize and continue through the middleware chain.
@@ -356,6 +382,10 @@ func (mw *traceMiddleware) deserializeTraceMiddleware(stack *middleware.Stack) e
 		// Create span pointers
 		spanpointers.AddSpanPointers(ctx, in, out, span)
 
+		if err == nil {
+			mw.enrichResponse(ctx, span, out.Result)
+		}
+
 		if err != nil && (mw.cfg.errCheck == nil || mw.cfg.errCheck(err)) {
 			span.SetTag(ext.Error, err)
 		}
@@ -365,6 +395,26 @@ func (mw *traceMiddleware) deserializeTraceMiddleware(stack *middleware.Stack) e
 	}), middleware.Before)
 }
 
+// enrichResponse links the span to the spans which sent the received messages, and tags it
+// with the attributes of the response of the services for which they are enabled.
+func (mw *traceMiddleware) enrichResponse(ctx context.Context, span *tracer.Span, result interface{}) {
+	switch awsmiddleware.GetServiceID(ctx) {
+	case "DynamoDB":
+		if mw.cfg.dynamoDBAttributes {
+			dynamoDBTracer.EnrichResponse(span, result)
+		}
+	case "S3":
+		if mw.cfg.s3Attributes {
+			s3Tracer.EnrichResponse(span, ctx.Value(operationParamsKey{}), result)
+		}
+	case "SQS":
+		sqsTracer.LinkMessages(span, result)
+		if mw.cfg.sqsAttributes {
+			sqsTracer.EnrichResponse(span, result)
+		}
+	}
+}
+
 func spanName(awsService, awsOperation string) string {
 	return instr.OperationName(instrumentation.ComponentDefault, instrumentation.OperationContext{
 		ext.AWSService:   awsService,
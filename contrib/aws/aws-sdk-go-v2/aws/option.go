@@ -10,9 +10,12 @@ import (
 )
 
 type config struct {
-	serviceName   string
-	analyticsRate float64
-	errCheck      func(err error) bool
+	serviceName        string
+	analyticsRate      float64
+	errCheck           func(err error) bool
+	dynamoDBAttributes bool
+	s3Attributes       bool
+	sqsAttributes      bool
 }
 
 // Option describes options for the AWS integration.
@@ -63,6 +66,32 @@ func WithAnalyticsRate(rate float64) OptionFn {
 	}
 }
 
+// WithDynamoDBAttributes enables or disables tagging the spans of DynamoDB requests with
+// the capacity units they consumed, when requested with ReturnConsumedCapacity. It is
+// disabled by default.
+func WithDynamoDBAttributes(enabled bool) OptionFn {
+	return func(cfg *config) {
+		cfg.dynamoDBAttributes = enabled
+	}
+}
+
+// WithS3Attributes enables or disables tagging the spans of S3 GetObject, HeadObject and
+// PutObject requests with the size and storage class of the object. It is disabled by default.
+func WithS3Attributes(enabled bool) OptionFn {
+	return func(cfg *config) {
+		cfg.s3Attributes = enabled
+	}
+}
+
+// WithSQSAttributes enables or disables tagging the spans of SQS ReceiveMessage requests with
+// the highest approximate receive count of the received messages, when requested with the
+// ApproximateReceiveCount attribute. It is disabled by default.
+func WithSQSAttributes(enabled bool) OptionFn {
+	return func(cfg *config) {
+		cfg.sqsAttributes = enabled
+	}
+}
+
 // WithErrorCheck specifies a function fn which determines whether the passed
 // error should be marked as an error. The fn is called whenever an aws operation
 // finishes with an error.
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package dynamodb
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// EnrichResponse tags the span of a DynamoDB request with the capacity it consumed, as
+// returned when the request sets ReturnConsumedCapacity.
+func EnrichResponse(span *tracer.Span, result interface{}) {
+	var consumed []types.ConsumedCapacity
+	switch res := result.(type) {
+	case *dynamodb.GetItemOutput:
+		consumed = single(res.ConsumedCapacity)
+	case *dynamodb.PutItemOutput:
+		consumed = single(res.ConsumedCapacity)
+	case *dynamodb.UpdateItemOutput:
+		consumed = single(res.ConsumedCapacity)
+	case *dynamodb.DeleteItemOutput:
+		consumed = single(res.ConsumedCapacity)
+	case *dynamodb.QueryOutput:
+		consumed = single(res.ConsumedCapacity)
+	case *dynamodb.ScanOutput:
+		consumed = single(res.ConsumedCapacity)
+	case *dynamodb.BatchGetItemOutput:
+		consumed = res.ConsumedCapacity
+	case *dynamodb.BatchWriteItemOutput:
+		consumed = res.ConsumedCapacity
+	case *dynamodb.TransactGetItemsOutput:
+		consumed = res.ConsumedCapacity
+	case *dynamodb.TransactWriteItemsOutput:
+		consumed = res.ConsumedCapacity
+	}
+	if len(consumed) == 0 {
+		return
+	}
+	var total, read, write float64
+	var hasRead, hasWrite bool
+	for _, c := range consumed {
+		if c.CapacityUnits != nil {
+			total += *c.CapacityUnits
+		}
+		if c.ReadCapacityUnits != nil {
+			read += *c.ReadCapacityUnits
+			hasRead = true
+		}
+		if c.WriteCapacityUnits != nil {
+			write += *c.WriteCapacityUnits
+			hasWrite = true
+		}
+	}
+	span.SetTag(ext.DynamoDBConsumedCapacity, total)
+	if hasRead {
+		span.SetTag(ext.DynamoDBConsumedReadCapacity, read)
+	}
+	if hasWrite {
+		span.SetTag(ext.DynamoDBConsumedWriteCapacity, write)
+	}
+}
+
+func single(c *types.ConsumedCapacity) []types.ConsumedCapacity {
+	if c == nil {
+		return nil
+	}
+	return []types.ConsumedCapacity{*c}
+}
@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    interface{}
+		wantTotal interface{}
+		wantRead  interface{}
+		wantWrite interface{}
+	}{
+		{
+			name:      "GetItem",
+			result:    &dynamodb.GetItemOutput{ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)}},
+			wantTotal: 0.5,
+		},
+		{
+			name: "BatchWriteItem",
+			result: &dynamodb.BatchWriteItemOutput{ConsumedCapacity: []types.ConsumedCapacity{
+				{CapacityUnits: aws.Float64(2), WriteCapacityUnits: aws.Float64(2)},
+				{CapacityUnits: aws.Float64(3), WriteCapacityUnits: aws.Float64(3)},
+			}},
+			wantTotal: float64(5),
+			wantWrite: float64(5),
+		},
+		{
+			name:   "no consumed capacity",
+			result: &dynamodb.QueryOutput{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			span := tracer.StartSpan("test-span")
+			EnrichResponse(span, tt.result)
+			span.Finish()
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tt.wantTotal, spans[0].Tag("aws.dynamodb.consumed_capacity"))
+			assert.Equal(t, tt.wantRead, spans[0].Tag("aws.dynamodb.consumed_read_capacity"))
+			assert.Equal(t, tt.wantWrite, spans[0].Tag("aws.dynamodb.consumed_write_capacity"))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package s3
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// EnrichResponse tags the span of an S3 request with the size and storage class of the
+// object it reads or writes.
+func EnrichResponse(span *tracer.Span, params, result interface{}) {
+	var (
+		size         *int64
+		storageClass string
+	)
+	switch res := result.(type) {
+	case *s3.GetObjectOutput:
+		size, storageClass = res.ContentLength, readStorageClass(res.StorageClass)
+	case *s3.HeadObjectOutput:
+		size, storageClass = res.ContentLength, readStorageClass(res.StorageClass)
+	case *s3.PutObjectOutput:
+		// the size and storage class of written objects are only known from the request
+		p, ok := params.(*s3.PutObjectInput)
+		if !ok {
+			return
+		}
+		size, storageClass = p.ContentLength, readStorageClass(p.StorageClass)
+	default:
+		return
+	}
+	if size != nil {
+		span.SetTag(ext.S3ObjectSize, *size)
+	}
+	if storageClass != "" {
+		span.SetTag(ext.S3StorageClass, storageClass)
+	}
+}
+
+// readStorageClass returns the given storage class of an object, which is only set by S3
+// and by requests for objects which aren't stored in the STANDARD class.
+func readStorageClass(c types.StorageClass) string {
+	if c == "" {
+		return string(types.StorageClassStandard)
+	}
+	return string(c)
+}
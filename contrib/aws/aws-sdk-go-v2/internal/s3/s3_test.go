@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package s3
+
+import (
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichResponse(t *testing.T) {
+	tests := []struct {
+		name             string
+		params           interface{}
+		result           interface{}
+		wantSize         interface{}
+		wantStorageClass interface{}
+	}{
+		{
+			name:             "GetObject",
+			params:           &s3.GetObjectInput{},
+			result:           &s3.GetObjectOutput{ContentLength: aws.Int64(42)},
+			wantSize:         float64(42),
+			wantStorageClass: "STANDARD",
+		},
+		{
+			name:             "HeadObject",
+			params:           &s3.HeadObjectInput{},
+			result:           &s3.HeadObjectOutput{ContentLength: aws.Int64(7), StorageClass: types.StorageClassGlacier},
+			wantSize:         float64(7),
+			wantStorageClass: "GLACIER",
+		},
+		{
+			name:             "PutObject",
+			params:           &s3.PutObjectInput{ContentLength: aws.Int64(12), StorageClass: types.StorageClassStandardIa},
+			result:           &s3.PutObjectOutput{},
+			wantSize:         float64(12),
+			wantStorageClass: "STANDARD_IA",
+		},
+		{
+			name:   "ListObjects",
+			params: &s3.ListObjectsInput{},
+			result: &s3.ListObjectsOutput{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			span := tracer.StartSpan("test-span")
+			EnrichResponse(span, tt.params, tt.result)
+			span.Finish()
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tt.wantSize, spans[0].Tag("aws.s3.object_size"))
+			assert.Equal(t, tt.wantStorageClass, spans[0].Tag("aws.s3.storage_class"))
+		})
+	}
+}
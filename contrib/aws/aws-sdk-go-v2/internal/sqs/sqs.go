@@ -6,9 +6,15 @@
 package sqs
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
@@ -29,6 +35,130 @@ func EnrichOperation(span *tracer.Span, in middleware.InitializeInput, operation
 		handleSendMessage(span, in)
 	case "SendMessageBatch":
 		handleSendMessageBatch(span, in)
+	case "ReceiveMessage":
+		handleReceiveMessage(in)
+	}
+}
+
+// handleReceiveMessage requests the message attribute holding the trace context of the
+// messages, so that it can be extracted from the received messages.
+func handleReceiveMessage(in middleware.InitializeInput) {
+	params, ok := in.Parameters.(*sqs.ReceiveMessageInput)
+	if !ok {
+		instr.Logger().Debug("Unable to read ReceiveMessage params")
+		return
+	}
+	for _, name := range params.MessageAttributeNames {
+		if name == datadogKey || name == "All" || name == ".*" {
+			return
+		}
+	}
+	// clone the names, so that appending doesn't write to the backing array of the caller's slice.
+	params.MessageAttributeNames = append(slices.Clone(params.MessageAttributeNames), datadogKey)
+}
+
+// LinkMessages links the span of a ReceiveMessage request to the spans which sent the
+// received messages, as propagated in their message attributes or in the envelope of
+// the messages delivered by SNS.
+func LinkMessages(span *tracer.Span, result interface{}) {
+	res, ok := result.(*sqs.ReceiveMessageOutput)
+	if !ok {
+		return
+	}
+	for _, msg := range res.Messages {
+		spanCtx, err := ExtractSpanContext(msg)
+		if err != nil {
+			continue
+		}
+		link := tracer.SpanLink{
+			TraceID:     spanCtx.TraceIDLower(),
+			TraceIDHigh: spanCtx.TraceIDUpper(),
+			SpanID:      spanCtx.SpanID(),
+		}
+		if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+			link.Flags = 1
+		}
+		if msg.MessageId != nil {
+			link.Attributes = map[string]string{"messaging.message.id": *msg.MessageId}
+		}
+		span.AddLink(link)
+	}
+}
+
+// errNoTraceContext is returned when a message doesn't hold a trace context.
+var errNoTraceContext = errors.New("message has no trace context")
+
+// snsEnvelope is the body of the messages delivered by SNS to SQS queues, unless raw
+// message delivery is enabled.
+type snsEnvelope struct {
+	Type              string `json:"Type"`
+	MessageAttributes map[string]struct {
+		Type  string `json:"Type"`
+		Value string `json:"Value"`
+	} `json:"MessageAttributes"`
+}
+
+// ExtractSpanContext returns the trace context propagated in msg, either in its message
+// attributes, or in the message attributes of the SNS notification it holds.
+func ExtractSpanContext(msg types.Message) (*tracer.SpanContext, error) {
+	var data []byte
+	if attr, ok := msg.MessageAttributes[datadogKey]; ok {
+		switch {
+		case attr.StringValue != nil:
+			data = []byte(*attr.StringValue)
+		default:
+			// the trace context of messages published with raw delivery by SNS is binary
+			data = attr.BinaryValue
+		}
+	} else if msg.Body != nil && strings.HasPrefix(*msg.Body, "{") {
+		var env snsEnvelope
+		if err := json.Unmarshal([]byte(*msg.Body), &env); err != nil || env.Type != "Notification" {
+			return nil, errNoTraceContext
+		}
+		attr, ok := env.MessageAttributes[datadogKey]
+		if !ok {
+			return nil, errNoTraceContext
+		}
+		if attr.Type == "Binary" {
+			b, err := base64.StdEncoding.DecodeString(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			data = b
+		} else {
+			data = []byte(attr.Value)
+		}
+	}
+	if len(data) == 0 {
+		return nil, errNoTraceContext
+	}
+	carrier := tracer.TextMapCarrier{}
+	if err := json.Unmarshal(data, &carrier); err != nil {
+		return nil, err
+	}
+	return tracer.Extract(carrier)
+}
+
+// EnrichResponse tags the span of a ReceiveMessage request with the highest approximate
+// receive count of the received messages, as returned when the request asks for the
+// ApproximateReceiveCount attribute.
+func EnrichResponse(span *tracer.Span, result interface{}) {
+	res, ok := result.(*sqs.ReceiveMessageOutput)
+	if !ok {
+		return
+	}
+	count := -1
+	for _, msg := range res.Messages {
+		v, ok := msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(v); err == nil && n > count {
+			count = n
+		}
+	}
+	if count >= 0 {
+		span.SetTag(ext.SQSApproximateReceiveCount, count)
 	}
 }
 
@@ -7,6 +7,7 @@ package sqs
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -180,3 +181,108 @@ func TestInjectTraceContext(t *testing.T) {
 		})
 	}
 }
+
+func TestEnrichResponse(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span := tracer.StartSpan("test-span")
+	EnrichResponse(span, &sqs.ReceiveMessageOutput{
+		Messages: []types.Message{
+			{Attributes: map[string]string{"ApproximateReceiveCount": "3"}},
+			{Attributes: map[string]string{"ApproximateReceiveCount": "5"}},
+			{},
+		},
+	})
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, float64(5), spans[0].Tag("aws.sqs.approximate_receive_count"))
+
+	mt.Reset()
+	span = tracer.StartSpan("test-span")
+	EnrichResponse(span, &sqs.ReceiveMessageOutput{Messages: []types.Message{{}}})
+	span.Finish()
+	assert.Nil(t, mt.FinishedSpans()[0].Tag("aws.sqs.approximate_receive_count"))
+}
+
+func TestExtractSpanContext(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span := tracer.StartSpan("producer")
+	carrier := tracer.TextMapCarrier{}
+	require.NoError(t, tracer.Inject(span.Context(), carrier))
+	data, err := json.Marshal(carrier)
+	require.NoError(t, err)
+	span.Finish()
+
+	envelope := fmt.Sprintf(`{"Type":"Notification","Message":"hello","MessageAttributes":{"_datadog":{"Type":"Binary","Value":"%s"}}}`,
+		base64.StdEncoding.EncodeToString(data))
+	tests := []struct {
+		name string
+		msg  types.Message
+	}{
+		{
+			name: "string attribute",
+			msg: types.Message{MessageAttributes: map[string]types.MessageAttributeValue{
+				datadogKey: {DataType: aws.String("String"), StringValue: aws.String(string(data))},
+			}},
+		},
+		{
+			name: "binary attribute",
+			msg: types.Message{MessageAttributes: map[string]types.MessageAttributeValue{
+				datadogKey: {DataType: aws.String("Binary"), BinaryValue: data},
+			}},
+		},
+		{
+			name: "SNS envelope",
+			msg:  types.Message{Body: aws.String(envelope)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spanCtx, err := ExtractSpanContext(tt.msg)
+			require.NoError(t, err)
+			assert.Equal(t, span.Context().TraceID(), spanCtx.TraceID())
+			assert.Equal(t, span.Context().SpanID(), spanCtx.SpanID())
+		})
+	}
+
+	_, err = ExtractSpanContext(types.Message{Body: aws.String("hello")})
+	assert.Error(t, err)
+
+	t.Run("LinkMessages", func(t *testing.T) {
+		mt.Reset()
+		receive := tracer.StartSpan("receive")
+		LinkMessages(receive, &sqs.ReceiveMessageOutput{Messages: []types.Message{
+			{MessageId: aws.String("1"), Body: aws.String(envelope)},
+			{MessageId: aws.String("2"), Body: aws.String("hello")},
+		}})
+		receive.Finish()
+
+		links := mt.FinishedSpans()[0].Links()
+		require.Len(t, links, 1)
+		assert.Equal(t, span.Context().SpanID(), links[0].SpanID)
+		assert.Equal(t, "1", links[0].Attributes["messaging.message.id"])
+	})
+}
+
+func TestHandleReceiveMessage(t *testing.T) {
+	params := &sqs.ReceiveMessageInput{MessageAttributeNames: []string{"foo"}}
+	handleReceiveMessage(middleware.InitializeInput{Parameters: params})
+	assert.Equal(t, []string{"foo", datadogKey}, params.MessageAttributeNames)
+
+	// the backing array of the names given by the caller isn't written to
+	names := make([]string, 1, 2)
+	names[0] = "foo"
+	params = &sqs.ReceiveMessageInput{MessageAttributeNames: names}
+	handleReceiveMessage(middleware.InitializeInput{Parameters: params})
+	assert.Equal(t, []string{"foo", datadogKey}, params.MessageAttributeNames)
+	assert.Equal(t, []string{"foo", ""}, names[:2])
+
+	params = &sqs.ReceiveMessageInput{MessageAttributeNames: []string{"All"}}
+	handleReceiveMessage(middleware.InitializeInput{Parameters: params})
+	assert.Equal(t, []string{"All"}, params.MessageAttributeNames)
+}
@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package lambda
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+const datadogKey = "_datadog"
+
+// Event sources of invocations, reported in the function_trigger.event_source tag.
+const (
+	sourceAPIGateway  = "api-gateway"
+	sourceSQS         = "sqs"
+	sourceEventBridge = "eventbridge"
+)
+
+// event holds the fields of the payloads of the supported event sources which are used to
+// extract the trace context of invocations and tag their span.
+type event struct {
+	// API Gateway REST (v1) and HTTP (v2) APIs
+	Headers        map[string]string `json:"headers"`
+	HTTPMethod     string            `json:"httpMethod"`
+	Path           string            `json:"path"`
+	Resource       string            `json:"resource"`
+	RawPath        string            `json:"rawPath"`
+	RouteKey       string            `json:"routeKey"`
+	RequestContext *struct {
+		APIID string `json:"apiId"`
+		HTTP  *struct {
+			Method string `json:"method"`
+		} `json:"http"`
+	} `json:"requestContext"`
+
+	// SQS
+	Records []struct {
+		EventSource       string `json:"eventSource"`
+		EventSourceARN    string `json:"eventSourceARN"`
+		Body              string `json:"body"`
+		MessageAttributes map[string]struct {
+			StringValue *string `json:"stringValue"`
+			BinaryValue []byte  `json:"binaryValue"`
+		} `json:"messageAttributes"`
+	} `json:"Records"`
+
+	// EventBridge
+	DetailType string          `json:"detail-type"`
+	Source     string          `json:"source"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// trigger describes the event source of an invocation.
+type trigger struct {
+	source  string
+	arn     string
+	spanCtx *tracer.SpanContext
+	tags    map[string]any
+}
+
+// parseTrigger returns the event source of the invocation with the given payload, and the
+// trace context it propagates, if any. It returns false if the event source isn't supported.
+func parseTrigger(payload []byte) (trigger, bool) {
+	var ev event
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return trigger{}, false
+	}
+	switch {
+	case ev.RequestContext != nil && (ev.HTTPMethod != "" || ev.RequestContext.HTTP != nil):
+		return apiGatewayTrigger(&ev), true
+	case len(ev.Records) > 0 && ev.Records[0].EventSource == "aws:sqs":
+		return sqsTrigger(&ev), true
+	case ev.DetailType != "" && ev.Source != "":
+		return eventBridgeTrigger(&ev), true
+	}
+	return trigger{}, false
+}
+
+func apiGatewayTrigger(ev *event) trigger {
+	t := trigger{source: sourceAPIGateway, tags: map[string]any{}}
+	headers := http.Header{}
+	for k, v := range ev.Headers {
+		headers.Set(k, v)
+	}
+	if spanCtx, err := tracer.Extract(tracer.HTTPHeadersCarrier(headers)); err == nil {
+		t.spanCtx = spanCtx
+	}
+	method, path, route := ev.HTTPMethod, ev.Path, ev.Resource
+	if ev.RequestContext.HTTP != nil {
+		// HTTP APIs (v2) have their route in the form "GET /items/{id}"
+		method, path, route = ev.RequestContext.HTTP.Method, ev.RawPath, ev.RouteKey
+		if _, r, ok := strings.Cut(route, " "); ok {
+			route = r
+		}
+	}
+	t.tags[ext.HTTPMethod] = method
+	t.tags[ext.HTTPURL] = path
+	if route != "" {
+		t.tags[ext.HTTPRoute] = route
+	}
+	if ev.RequestContext.APIID != "" {
+		t.tags["apiid"] = ev.RequestContext.APIID
+	}
+	return t
+}
+
+func sqsTrigger(ev *event) trigger {
+	record := ev.Records[0]
+	t := trigger{source: sourceSQS, arn: record.EventSourceARN}
+	var data []byte
+	if attr, ok := record.MessageAttributes[datadogKey]; ok {
+		if attr.StringValue != nil {
+			data = []byte(*attr.StringValue)
+		} else {
+			data = attr.BinaryValue
+		}
+	} else {
+		data = snsEnvelopeContext(record.Body)
+	}
+	t.spanCtx = extractJSON(data)
+	return t
+}
+
+func eventBridgeTrigger(ev *event) trigger {
+	t := trigger{source: sourceEventBridge, tags: map[string]any{"eventbridge.source": ev.Source}}
+	var detail map[string]json.RawMessage
+	if err := json.Unmarshal(ev.Detail, &detail); err == nil {
+		t.spanCtx = extractJSON(detail[datadogKey])
+	}
+	return t
+}
+
+// snsEnvelopeContext returns the trace context propagated in the message attributes of the
+// SNS notification in body, as delivered to SQS without raw message delivery.
+func snsEnvelopeContext(body string) []byte {
+	var env struct {
+		Type              string `json:"Type"`
+		MessageAttributes map[string]struct {
+			Type  string `json:"Type"`
+			Value string `json:"Value"`
+		} `json:"MessageAttributes"`
+	}
+	if err := json.Unmarshal([]byte(body), &env); err != nil || env.Type != "Notification" {
+		return nil
+	}
+	attr, ok := env.MessageAttributes[datadogKey]
+	if !ok {
+		return nil
+	}
+	if attr.Type == "Binary" {
+		b, err := base64.StdEncoding.DecodeString(attr.Value)
+		if err != nil {
+			return nil
+		}
+		return b
+	}
+	return []byte(attr.Value)
+}
+
+// extractJSON extracts the trace context from a JSON object holding the propagation headers.
+func extractJSON(data []byte) *tracer.SpanContext {
+	if len(data) == 0 {
+		return nil
+	}
+	carrier := tracer.TextMapCarrier{}
+	if err := json.Unmarshal(data, &carrier); err != nil {
+		return nil
+	}
+	spanCtx, err := tracer.Extract(carrier)
+	if err != nil {
+		return nil
+	}
+	return spanCtx
+}
@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package lambda traces the invocations of AWS Lambda functions, for functions which aren't
+// wrapped with the datadog-lambda-go library.
+//
+// The trace context of invocations is extracted from the payload of events sent by API
+// Gateway, SQS (including the notifications of SNS topics delivered to SQS queues) and
+// EventBridge, and the traces are flushed before the invocation returns, so that they
+// aren't lost when the execution environment is frozen. The tracer must be started before
+// the function, typically in main:
+//
+//	func main() {
+//		tracer.Start()
+//		defer tracer.Stop()
+//		lambda.StartHandler(ddlambda.WrapHandler(lambda.NewHandler(handle)))
+//	}
+package lambda // import "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/lambda"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+const (
+	componentName      = "aws/aws-sdk-go-v2/lambda"
+	spanTypeServerless = "serverless"
+)
+
+var instr = internal.Instr
+
+// Handler is the interface of Lambda function handlers, as defined by the
+// github.com/aws/aws-lambda-go/lambda package.
+type Handler interface {
+	Invoke(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// HandlerFunc is an adapter to use a function as a Handler.
+type HandlerFunc func(ctx context.Context, payload []byte) ([]byte, error)
+
+// Invoke calls f(ctx, payload).
+func (f HandlerFunc) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	return f(ctx, payload)
+}
+
+// coldStart reports whether the next invocation is the first of the execution environment.
+var coldStart atomic.Bool
+
+func init() {
+	coldStart.Store(true)
+}
+
+// WrapHandler returns a Handler tracing the invocations of h with an "aws.lambda" span,
+// child of the span which triggered the invocation when its event source propagates the
+// trace context. The traces are flushed before the invocation returns.
+func WrapHandler(h Handler, opts ...Option) Handler {
+	cfg := new(config)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn.apply(cfg)
+	}
+	instr.Logger().Debug("contrib/aws/aws-sdk-go-v2/lambda: Wrapping Handler: %#v", cfg)
+	return HandlerFunc(func(ctx context.Context, payload []byte) (resp []byte, err error) {
+		span, ctx := startInvocationSpan(ctx, cfg, payload)
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+				finishInvocationSpan(span, resp, err)
+				panic(r)
+			}
+			finishInvocationSpan(span, resp, err)
+		}()
+		return h.Invoke(ctx, payload)
+	})
+}
+
+func startInvocationSpan(ctx context.Context, cfg *config, payload []byte) (*tracer.Span, context.Context) {
+	opts := []tracer.StartSpanOption{
+		tracer.ResourceName(cfg.functionName),
+		tracer.SpanType(spanTypeServerless),
+		tracer.Tag(ext.SpanKind, ext.SpanKindServer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag("cold_start", strconv.FormatBool(coldStart.Swap(false))),
+	}
+	if cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(cfg.serviceName))
+	}
+	if cfg.functionName != "" {
+		opts = append(opts, tracer.Tag("functionname", cfg.functionName))
+	}
+	if t, ok := parseTrigger(payload); ok {
+		opts = append(opts, tracer.Tag("function_trigger.event_source", t.source))
+		if t.arn != "" {
+			opts = append(opts, tracer.Tag("function_trigger.event_source_arn", t.arn))
+		}
+		for k, v := range t.tags {
+			opts = append(opts, tracer.Tag(k, v))
+		}
+		if t.spanCtx != nil {
+			opts = append(opts, tracer.ChildOf(t.spanCtx))
+		}
+	}
+	return tracer.StartSpanFromContext(ctx, "aws.lambda", opts...)
+}
+
+// finishInvocationSpan finishes the span of an invocation and flushes the traces.
+func finishInvocationSpan(span *tracer.Span, resp []byte, err error) {
+	var res struct {
+		StatusCode int `json:"statusCode"`
+	}
+	if err == nil && json.Unmarshal(resp, &res) == nil && res.StatusCode != 0 {
+		// the response of functions invoked by API Gateway
+		span.SetTag(ext.HTTPCode, strconv.Itoa(res.StatusCode))
+		if res.StatusCode >= 500 {
+			span.SetTag(ext.Error, fmt.Errorf("%d", res.StatusCode))
+		}
+	}
+	span.Finish(tracer.WithError(err))
+	// the execution environment may be frozen as soon as the invocation returns
+	tracer.Flush()
+}
+
+func functionName() string {
+	return os.Getenv("AWS_LAMBDA_FUNCTION_NAME")
+}
@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func TestWrapHandler(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	parent := tracer.StartSpan("producer")
+	carrier := tracer.TextMapCarrier{}
+	require.NoError(t, tracer.Inject(parent.Context(), carrier))
+	parent.Finish()
+	headers, err := json.Marshal(carrier)
+	require.NoError(t, err)
+
+	snsEnvelope, err := json.Marshal(map[string]any{
+		"Type":    "Notification",
+		"Message": "hello",
+		"MessageAttributes": map[string]any{
+			"_datadog": map[string]string{"Type": "Binary", "Value": base64.StdEncoding.EncodeToString(headers)},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		payload  string
+		response string
+		source   string
+		tags     map[string]any
+	}{
+		{
+			name:     "API Gateway REST API",
+			payload:  fmt.Sprintf(`{"resource":"/items/{id}","path":"/items/1","httpMethod":"GET","headers":%s,"requestContext":{"apiId":"abc"}}`, headers),
+			response: `{"statusCode":404}`,
+			source:   "api-gateway",
+			tags: map[string]any{
+				ext.HTTPMethod: "GET",
+				ext.HTTPURL:    "/items/1",
+				ext.HTTPRoute:  "/items/{id}",
+				ext.HTTPCode:   "404",
+				"apiid":        "abc",
+			},
+		},
+		{
+			name:    "API Gateway HTTP API",
+			payload: fmt.Sprintf(`{"routeKey":"POST /items","rawPath":"/items","headers":%s,"requestContext":{"http":{"method":"POST"}}}`, headers),
+			source:  "api-gateway",
+			tags: map[string]any{
+				ext.HTTPMethod: "POST",
+				ext.HTTPURL:    "/items",
+				ext.HTTPRoute:  "/items",
+			},
+		},
+		{
+			name:    "SQS",
+			payload: fmt.Sprintf(`{"Records":[{"eventSource":"aws:sqs","eventSourceARN":"arn:aws:sqs:us-east-1:123:queue","body":"hello","messageAttributes":{"_datadog":{"stringValue":%q,"dataType":"String"}}}]}`, headers),
+			source:  "sqs",
+			tags:    map[string]any{"function_trigger.event_source_arn": "arn:aws:sqs:us-east-1:123:queue"},
+		},
+		{
+			name:    "SNS to SQS",
+			payload: fmt.Sprintf(`{"Records":[{"eventSource":"aws:sqs","body":%q}]}`, snsEnvelope),
+			source:  "sqs",
+		},
+		{
+			name:    "EventBridge",
+			payload: fmt.Sprintf(`{"detail-type":"order","source":"shop","detail":{"id":1,"_datadog":%s}}`, headers),
+			source:  "eventbridge",
+			tags:    map[string]any{"eventbridge.source": "shop"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt.Reset()
+			h := WrapHandler(HandlerFunc(func(ctx context.Context, _ []byte) ([]byte, error) {
+				_, ok := tracer.SpanFromContext(ctx)
+				assert.True(t, ok)
+				return []byte(tt.response), nil
+			}), WithService("my-function"))
+			_, err := h.Invoke(context.Background(), []byte(tt.payload))
+			require.NoError(t, err)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			s := spans[0]
+			assert.Equal(t, "aws.lambda", s.OperationName())
+			assert.Equal(t, "my-function", s.Tag(ext.ServiceName))
+			assert.Equal(t, tt.source, s.Tag("function_trigger.event_source"))
+			assert.Equal(t, parent.Context().TraceID(), s.Context().TraceID())
+			assert.Equal(t, parent.Context().SpanID(), s.ParentID())
+			for k, v := range tt.tags {
+				assert.Equal(t, v, s.Tag(k), k)
+			}
+		})
+	}
+}
+
+func TestWrapHandlerError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	h := WrapHandler(HandlerFunc(func(context.Context, []byte) ([]byte, error) {
+		return nil, errors.New("oops")
+	}))
+	_, err := h.Invoke(context.Background(), []byte(`{}`))
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "oops", spans[0].Tag(ext.ErrorMsg))
+	assert.Nil(t, spans[0].Tag("function_trigger.event_source"))
+
+	mt.Reset()
+	h = WrapHandler(HandlerFunc(func(context.Context, []byte) ([]byte, error) {
+		panic("boom")
+	}))
+	assert.Panics(t, func() { h.Invoke(context.Background(), []byte(`{}`)) })
+	spans = mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "panic: boom", spans[0].Tag(ext.ErrorMsg))
+}
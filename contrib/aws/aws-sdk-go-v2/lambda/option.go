@@ -0,0 +1,41 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package lambda
+
+import "github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+type config struct {
+	serviceName  string
+	functionName string
+}
+
+// Option describes options for the Lambda integration.
+type Option interface {
+	apply(*config)
+}
+
+// OptionFn represents options applicable to WrapHandler.
+type OptionFn func(*config)
+
+func (fn OptionFn) apply(cfg *config) {
+	fn(cfg)
+}
+
+func defaults(cfg *config) {
+	cfg.functionName = functionName()
+	cfg.serviceName = cfg.functionName
+	if svc := instr.ServiceName(instrumentation.ComponentServer, nil); svc != "" {
+		cfg.serviceName = svc
+	}
+}
+
+// WithService sets the given service name for the invocation spans. It defaults to the
+// global service name, or to the name of the function.
+func WithService(name string) OptionFn {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
@@ -24,6 +24,21 @@ import (
 
 const componentName = instrumentation.PackageGCPPubsub
 
+const (
+	// tagOrderingKeyPaused is set on the span of a message which failed to be published with
+	// an ordering key, as the publishing of the messages with this key is then paused until
+	// Topic.ResumePublish is called.
+	tagOrderingKeyPaused = "ordering_key_paused"
+	// tagFlowControlWait is the time spent waiting for the flow control of a topic before a
+	// message is added to a bundle, in milliseconds.
+	tagFlowControlWait = "flow_control_wait_ms"
+	// tagAckResult is the acknowledgement of a received message, "ack" or "nack".
+	tagAckResult = "ack_result"
+	// tagAckStatus is the status of the acknowledgement of a received message, as reported
+	// by subscriptions with exactly-once delivery.
+	tagAckStatus = "ack_status"
+)
+
 type Message struct {
 	ID              string
 	Data            []byte
@@ -78,12 +93,86 @@ func TracePublish(ctx context.Context, topic Topic, msg *Message, opts ...Option
 	closeSpan := func(serverID string, err error) {
 		once.Do(func() {
 			span.SetTag("server_id", serverID)
+			if err != nil && msg.OrderingKey != "" {
+				span.SetTag(tagOrderingKeyPaused, true)
+			}
 			span.Finish(tracer.WithError(err))
 		})
 	}
 	return ctx, closeSpan
 }
 
+// TagFlowControlWait tags the publish span in ctx with the time spent in Topic.Publish, which
+// is the time spent waiting for the flow control of the topic to accept the message.
+func TagFlowControlWait(ctx context.Context, d time.Duration) {
+	if span, ok := tracer.SpanFromContext(ctx); ok {
+		span.SetTag(tagFlowControlWait, d.Milliseconds())
+	}
+}
+
+// TracePublishBatch starts the span of the publishing of a batch of n messages. The span is
+// linked to the publish spans of the messages, added with the returned link function from
+// the contexts returned by TracePublish, and is finished by the returned finish function
+// with the number of messages which failed to be published and the first error.
+func TracePublishBatch(ctx context.Context, topic Topic, n int, opts ...Option) (link func(msgCtx context.Context), finish func(failed int, err error)) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	spanOpts := []tracer.StartSpanOption{
+		tracer.ResourceName(topic.String()),
+		tracer.SpanType(ext.SpanTypeMessageProducer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindProducer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemGCPPubsub),
+		tracer.Tag(ext.MessagingBatchMessageCount, n),
+	}
+	if cfg.serviceName != "" {
+		spanOpts = append(spanOpts, tracer.ServiceName(cfg.serviceName))
+	}
+	if cfg.measured {
+		spanOpts = append(spanOpts, tracer.Measured())
+	}
+	span, _ := tracer.StartSpanFromContext(ctx, cfg.publishSpanName, spanOpts...)
+	link = func(msgCtx context.Context) {
+		msgSpan, ok := tracer.SpanFromContext(msgCtx)
+		if !ok {
+			return
+		}
+		spanCtx := msgSpan.Context()
+		l := tracer.SpanLink{
+			TraceID:     spanCtx.TraceIDLower(),
+			TraceIDHigh: spanCtx.TraceIDUpper(),
+			SpanID:      spanCtx.SpanID(),
+			Attributes:  map[string]string{"reason": "batch_message"},
+		}
+		if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+			l.Flags = 1
+		}
+		span.AddLink(l)
+	}
+	finish = func(failed int, err error) {
+		span.SetTag("failed_messages", failed)
+		span.Finish(tracer.WithError(err))
+	}
+	return link, finish
+}
+
+// TagAckResult tags the receive span in ctx with the acknowledgement of its message, "ack" or
+// "nack", and the status of the acknowledgement. The span is marked as failed if the
+// acknowledgement failed.
+func TagAckResult(ctx context.Context, result, status string, err error) {
+	span, ok := tracer.SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	span.SetTag(tagAckResult, result)
+	span.SetTag(tagAckStatus, status)
+	if err != nil {
+		span.SetTag(ext.Error, err)
+	}
+}
+
 func TraceReceiveFunc(s Subscription, opts ...Option) func(ctx context.Context, msg *Message) (context.Context, func()) {
 	cfg := defaultConfig()
 	for _, opt := range opts {
@@ -8,6 +8,7 @@ package pubsub
 
 import (
 	"context"
+	"time"
 
 	"github.com/DataDog/dd-trace-go/contrib/cloud.google.com/go/pubsub.v1/v2/internal/tracing"
 	_ "github.com/DataDog/dd-trace-go/v2/instrumentation" // Blank import to pass TestIntegrationEnabled test
@@ -22,14 +23,55 @@ import (
 // It is required to call (*PublishResult).Get(ctx) on the value returned by Publish to complete
 // the span.
 func Publish(ctx context.Context, t *pubsub.Topic, msg *pubsub.Message, opts ...Option) *PublishResult {
+	r, _ := publish(ctx, t, msg, opts...)
+	return r
+}
+
+// PublishBatch publishes the given messages on the specified topic, as Publish does, and
+// starts a batch span linked to the publish spans of the messages. The publish spans and the
+// batch span are completed once the messages are published, without calling
+// (*PublishResult).Get(ctx) on the returned values.
+func PublishBatch(ctx context.Context, t *pubsub.Topic, msgs []*pubsub.Message, opts ...Option) []*PublishResult {
+	link, finish := tracing.TracePublishBatch(ctx, t, len(msgs), opts...)
+	results := make([]*PublishResult, len(msgs))
+	for i, msg := range msgs {
+		r, msgCtx := publish(ctx, t, msg, opts...)
+		link(msgCtx)
+		results[i] = r
+	}
+	go func() {
+		var (
+			failed   int
+			firstErr error
+		)
+		for _, r := range results {
+			<-r.Ready()
+			if _, err := r.Get(context.Background()); err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		finish(failed, firstErr)
+	}()
+	return results
+}
+
+// publish publishes msg and returns its result, and the context holding its publish span.
+func publish(ctx context.Context, t *pubsub.Topic, msg *pubsub.Message, opts ...Option) (*PublishResult, context.Context) {
 	traceMsg := newTraceMessage(msg)
 	ctx, closeSpan := tracing.TracePublish(ctx, t, traceMsg, opts...)
 	msg.Attributes = traceMsg.Attributes
 
+	// Topic.Publish blocks while the flow control of the topic doesn't accept the message.
+	start := time.Now()
+	r := t.Publish(ctx, msg)
+	tracing.TagFlowControlWait(ctx, time.Since(start))
 	return &PublishResult{
-		PublishResult: t.Publish(ctx, msg),
+		PublishResult: r,
 		closeSpan:     closeSpan,
-	}
+	}, ctx
 }
 
 // PublishResult wraps *pubsub.PublishResult
@@ -46,6 +88,49 @@ func (r *PublishResult) Get(ctx context.Context) (string, error) {
 	return serverID, err
 }
 
+// AckResult wraps *pubsub.AckResult.
+type AckResult struct {
+	*pubsub.AckResult
+	ctx    context.Context
+	result string
+}
+
+// AckWithResult acknowledges msg with msg.AckWithResult(). The receive span in ctx, started
+// by WrapReceiveHandler, is tagged with the outcome of the acknowledgement when
+// (*AckResult).Get(ctx) is called on the returned value, which must be done before the
+// receive handler returns. The outcome is only meaningful for subscriptions with
+// exactly-once delivery, as the acknowledgements always succeed otherwise.
+func AckWithResult(ctx context.Context, msg *pubsub.Message) *AckResult {
+	return &AckResult{AckResult: msg.AckWithResult(), ctx: ctx, result: "ack"}
+}
+
+// NackWithResult negatively acknowledges msg with msg.NackWithResult(), tagging the receive
+// span in ctx as AckWithResult does.
+func NackWithResult(ctx context.Context, msg *pubsub.Message) *AckResult {
+	return &AckResult{AckResult: msg.NackWithResult(), ctx: ctx, result: "nack"}
+}
+
+// Get wraps (pubsub.AckResult).Get(ctx), tagging the receive span with the returned status.
+func (r *AckResult) Get(ctx context.Context) (pubsub.AcknowledgeStatus, error) {
+	status, err := r.AckResult.Get(ctx)
+	tracing.TagAckResult(r.ctx, r.result, ackStatusName(status), err)
+	return status, err
+}
+
+func ackStatusName(s pubsub.AcknowledgeStatus) string {
+	switch s {
+	case pubsub.AcknowledgeStatusSuccess:
+		return "success"
+	case pubsub.AcknowledgeStatusPermissionDenied:
+		return "permission_denied"
+	case pubsub.AcknowledgeStatusFailedPrecondition:
+		return "failed_precondition"
+	case pubsub.AcknowledgeStatusInvalidAckID:
+		return "invalid_ack_id"
+	}
+	return "other"
+}
+
 // WrapReceiveHandler returns a receive handler that wraps the supplied handler,
 // extracts any tracing metadata attached to the received message, and starts a
 // receive span.
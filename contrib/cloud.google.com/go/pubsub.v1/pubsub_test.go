@@ -255,6 +255,77 @@ func TestPropagationNoPublisherSpan(t *testing.T) {
 	assert.Equal("cloud.google.com/go/pubsub.v1", spans[0].Integration())
 }
 
+func TestPublishFlowControl(t *testing.T) {
+	ctx, _, mt, topic, _ := setup(t)
+	topic.PublishSettings.FlowControlSettings = pubsub.FlowControlSettings{
+		MaxOutstandingBytes:    1,
+		LimitExceededBehavior:  pubsub.FlowControlSignalError,
+		MaxOutstandingMessages: 1,
+	}
+
+	_, err := Publish(ctx, topic, &pubsub.Message{Data: []byte("hello"), OrderingKey: "xxx"}).Get(ctx)
+	require.ErrorIs(t, err, pubsub.ErrFlowControllerMaxOutstandingBytes)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "pubsub.publish", spans[0].OperationName())
+	assert.NotNil(t, spans[0].Tag("flow_control_wait_ms"))
+	assert.Equal(t, "true", spans[0].Tag("ordering_key_paused"))
+	assert.NotNil(t, spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestPublishBatch(t *testing.T) {
+	ctx, _, mt, topic, _ := setup(t)
+
+	results := PublishBatch(ctx, topic, []*pubsub.Message{
+		{Data: []byte("hello")},
+		{Data: []byte("world")},
+	})
+	require.Len(t, results, 2)
+	for _, r := range results {
+		_, err := r.Get(ctx)
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 3 }, time.Second, 10*time.Millisecond)
+
+	spans := mt.FinishedSpans()
+	batch := spans[2]
+	assert.Equal(t, "pubsub.publish", batch.OperationName())
+	assert.Equal(t, float64(2), batch.Tag(ext.MessagingBatchMessageCount))
+	assert.Equal(t, float64(0), batch.Tag("failed_messages"))
+	require.Len(t, batch.Links(), 2)
+	msgSpans := map[uint64]bool{spans[0].SpanID(): true, spans[1].SpanID(): true}
+	for _, l := range batch.Links() {
+		assert.True(t, msgSpans[l.SpanID])
+		assert.Equal(t, "batch_message", l.Attributes["reason"])
+	}
+	for _, s := range spans[:2] {
+		assert.Nil(t, s.Tag(ext.MessagingBatchMessageCount))
+		assert.NotNil(t, s.Tag("server_id"))
+	}
+}
+
+func TestAckWithResult(t *testing.T) {
+	ctx, cancel, mt, topic, sub := setup(t)
+
+	_, err := Publish(ctx, topic, &pubsub.Message{Data: []byte("hello")}).Get(ctx)
+	require.NoError(t, err)
+
+	err = sub.Receive(ctx, WrapReceiveHandler(sub, func(ctx context.Context, msg *pubsub.Message) {
+		status, err := AckWithResult(ctx, msg).Get(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, pubsub.AcknowledgeStatusSuccess, status)
+		cancel()
+	}))
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "pubsub.receive", spans[1].OperationName())
+	assert.Equal(t, "ack", spans[1].Tag("ack_result"))
+	assert.Equal(t, "success", spans[1].Tag("ack_status"))
+}
+
 func filterTags(m map[string]interface{}) map[string]interface{} {
 	delete(m, "_dd.p.tid")
 	delete(m, "_dd.profiling.enabled")
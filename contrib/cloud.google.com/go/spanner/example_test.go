@@ -0,0 +1,41 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package spanner_test
+
+import (
+	"context"
+	"log"
+
+	spannertrace "github.com/DataDog/dd-trace-go/contrib/cloud.google.com/go/spanner/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"cloud.google.com/go/spanner"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	ctx := context.Background()
+	// the client options trace the sessions of the client
+	c, err := spanner.NewClient(ctx, "projects/my-project/instances/my-instance/databases/my-db", spannertrace.ClientOptions()...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+	client := spannertrace.WrapClient(c, spannertrace.WithService("my-spanner"))
+
+	_, err = client.ReadWriteTransaction(ctx, func(ctx context.Context, tx *spannertrace.ReadWriteTransaction) error {
+		_, err := tx.Update(ctx, spanner.Statement{
+			SQL:    "UPDATE Singers SET Name = @name WHERE SingerId = @id",
+			Params: map[string]any{"name": "Marc", "id": 1},
+		})
+		return err
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package spanner
+
+import (
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	serviceName string
+	obfuscate   bool
+	spanOpts    []tracer.StartSpanOption
+}
+
+// Option represents an option that can be used to trace a Spanner client.
+type Option func(*config)
+
+func defaultConfig() *config {
+	cfg := &config{
+		serviceName: instr.ServiceName(instrumentation.ComponentDefault, nil),
+		obfuscate:   true,
+	}
+	if rate := instr.AnalyticsRate(false); !math.IsNaN(rate) {
+		cfg.spanOpts = append(cfg.spanOpts, tracer.AnalyticsRate(rate))
+	}
+	return cfg
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the spans of the client.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithRawStatements disables the obfuscation of the SQL statements used as the resource of
+// the spans of the queries and DML statements. Literals in statements aren't replaced, so
+// this should only be used when statements don't carry sensitive data, for example when
+// all values are passed as parameters.
+func WithRawStatements() Option {
+	return func(cfg *config) {
+		cfg.obfuscate = false
+	}
+}
+
+// WithSpanOptions defines a set of additional tracer.StartSpanOption to be added
+// to the spans of the client.
+func WithSpanOptions(opts ...tracer.StartSpanOption) Option {
+	return func(cfg *config) {
+		cfg.spanOpts = append(cfg.spanOpts, opts...)
+	}
+}
@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package spanner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"cloud.google.com/go/spanner/apiv1/spannerpb"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// sessionMethodPrefix is the prefix of the full names of the gRPC methods of Spanner.
+	sessionMethodPrefix = "/google.spanner.v1.Spanner/"
+	// tagSessionCount is the number of sessions requested or created by a session operation.
+	tagSessionCount = "spanner.session.count"
+	// tagSessionsCreated is the number of sessions created by a batch session operation,
+	// which may be less than the number of requested sessions.
+	tagSessionsCreated = "spanner.session.created"
+)
+
+// ClientOptions returns the options to pass to spanner.NewClient to trace the creation and
+// deletion of the sessions of the client's session pool with "spanner.session" spans.
+// Sessions are mostly created in the background, so their spans are usually the root of
+// their own trace.
+func ClientOptions(opts ...Option) []option.ClientOption {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/cloud.google.com/go/spanner: Tracing sessions: %#v", cfg)
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(sessionInterceptor(cfg))),
+	}
+}
+
+// sessionInterceptor returns a gRPC interceptor tracing the session methods of Spanner.
+func sessionInterceptor(cfg *config) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		name, ok := strings.CutPrefix(method, sessionMethodPrefix)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		var database string
+		switch r := req.(type) {
+		case *spannerpb.CreateSessionRequest:
+			database = r.GetDatabase()
+		case *spannerpb.BatchCreateSessionsRequest:
+			database = r.GetDatabase()
+		case *spannerpb.DeleteSessionRequest:
+			database = r.GetName()
+		default:
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		c := &Client{cfg: cfg}
+		c.project, c.instance, c.database = parseDatabaseName(database)
+		span, ctx := c.startSpan(ctx, opSession, name, tracer.Tag(ext.RPCMethod, name))
+		if r, ok := req.(*spannerpb.BatchCreateSessionsRequest); ok {
+			span.SetTag(tagSessionCount, r.GetSessionCount())
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if r, ok := reply.(*spannerpb.BatchCreateSessionsResponse); ok && err == nil {
+			span.SetTag(tagSessionsCreated, len(r.GetSession()))
+		}
+		if err != nil {
+			span.SetTag(tagErrorCode, status.Code(err).String())
+		}
+		span.Finish(tracer.WithError(err))
+		return err
+	}
+}
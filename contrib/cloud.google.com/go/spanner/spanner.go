@@ -0,0 +1,242 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package spanner provides functions to trace the cloud.google.com/go/spanner package.
+//
+// The SQL statements of queries and DML statements are obfuscated before being used as
+// the resource of their spans, the same way the Datadog Agent obfuscates the queries of
+// Database Monitoring, so that literals in statements aren't sent to Datadog.
+package spanner // import "github.com/DataDog/dd-trace-go/contrib/cloud.google.com/go/spanner/v2"
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"cloud.google.com/go/spanner"
+	"github.com/DataDog/datadog-agent/pkg/obfuscate"
+	"google.golang.org/grpc/codes"
+)
+
+const componentName = instrumentation.PackageGCPSpanner
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageGCPSpanner)
+}
+
+// Names of the spans of the operations of a client.
+const (
+	opQuery             = "spanner.query"
+	opRead              = "spanner.read"
+	opUpdate            = "spanner.update"
+	opBatchUpdate       = "spanner.batch_update"
+	opTransaction       = "spanner.transaction"
+	opApply             = "spanner.apply"
+	opPartitionedUpdate = "spanner.partitioned_update"
+	opSession           = "spanner.session"
+)
+
+const (
+	// tagTables is the comma-separated list of the tables found in an obfuscated statement.
+	tagTables = "sql.tables"
+	// tagRowCount is the number of rows returned or modified by an operation.
+	tagRowCount = "spanner.row_count"
+	// tagStatementCount is the number of statements of a batch of DML statements.
+	tagStatementCount = "spanner.statement_count"
+	// tagMutationCount is the number of mutations applied or buffered by an operation.
+	tagMutationCount = "spanner.mutation_count"
+	// tagErrorCode is the gRPC code of the error of an operation.
+	tagErrorCode = "spanner.error_code"
+	// tagAttempt is the attempt of the read/write transaction a statement is run in,
+	// starting from 1.
+	tagAttempt = "spanner.transaction.attempt"
+	// tagAttempts is the number of times the function of a read/write transaction was
+	// run, as transactions aborted by Spanner are retried.
+	tagAttempts = "spanner.transaction.attempts"
+	// tagRetried is set on the span of a read/write transaction which was retried.
+	tagRetried = "spanner.transaction.retried"
+)
+
+// nonParsableResource is the resource of the spans of statements which can't be obfuscated,
+// as used by the Datadog Agent.
+const nonParsableResource = "Non-parsable SQL query"
+
+// obfuscator returns the obfuscator of SQL statements, created on first use.
+var obfuscator = sync.OnceValue(func() *obfuscate.Obfuscator {
+	return obfuscate.NewObfuscator(obfuscate.Config{
+		SQL: obfuscate.SQLConfig{
+			TableNames:    true,
+			ReplaceDigits: true,
+		},
+	})
+})
+
+// Client wraps a *spanner.Client, tracing its queries, reads, DML statements, mutations and
+// read/write transactions.
+type Client struct {
+	*spanner.Client
+	cfg      *config
+	database string
+	instance string
+	project  string
+}
+
+// WrapClient returns a Client tracing the operations of c. Pass the options returned by
+// ClientOptions to spanner.NewClient to also trace the sessions of the client.
+func WrapClient(c *spanner.Client, opts ...Option) *Client {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/cloud.google.com/go/spanner: Wrapping Client: %#v", cfg)
+	project, instance, database := parseDatabaseName(c.DatabaseName())
+	return &Client{
+		Client:   c,
+		cfg:      cfg,
+		database: database,
+		instance: instance,
+		project:  project,
+	}
+}
+
+// parseDatabaseName returns the project, instance and database IDs of a database name in
+// the form "projects/P/instances/I/databases/D".
+func parseDatabaseName(name string) (project, instance, database string) {
+	parts := strings.Split(name, "/")
+	for i := 0; i+1 < len(parts); i += 2 {
+		switch parts[i] {
+		case "projects":
+			project = parts[i+1]
+		case "instances":
+			instance = parts[i+1]
+		case "databases":
+			database = parts[i+1]
+		}
+	}
+	return project, instance, database
+}
+
+// Single returns a read-only transaction traced by c, for a single read or query.
+func (c *Client) Single() *ReadOnlyTransaction {
+	return &ReadOnlyTransaction{ReadOnlyTransaction: c.Client.Single(), c: c}
+}
+
+// ReadOnlyTransaction returns a read-only transaction traced by c, for multiple reads and
+// queries from a consistent snapshot. It must be closed when no longer used.
+func (c *Client) ReadOnlyTransaction() *ReadOnlyTransaction {
+	return &ReadOnlyTransaction{ReadOnlyTransaction: c.Client.ReadOnlyTransaction(), c: c}
+}
+
+// ReadWriteTransaction runs f in a traced read/write transaction. The reads, queries and
+// DML statements of f are traced when they are made through the given ReadWriteTransaction.
+// As Spanner retries f when the transaction is aborted, the span of the transaction is
+// tagged with the number of times f was run.
+func (c *Client) ReadWriteTransaction(ctx context.Context, f func(context.Context, *ReadWriteTransaction) error) (time.Time, error) {
+	resp, err := c.ReadWriteTransactionWithOptions(ctx, f, spanner.TransactionOptions{})
+	return resp.CommitTs, err
+}
+
+// ReadWriteTransactionWithOptions runs f in a traced read/write transaction with the given
+// options, like ReadWriteTransaction.
+func (c *Client) ReadWriteTransactionWithOptions(ctx context.Context, f func(context.Context, *ReadWriteTransaction) error, opts spanner.TransactionOptions) (resp spanner.CommitResponse, err error) {
+	span, ctx := c.startSpan(ctx, opTransaction, "ReadWriteTransaction")
+	attempts := 0
+	defer func() {
+		span.SetTag(tagAttempts, attempts)
+		if attempts > 1 {
+			span.SetTag(tagRetried, true)
+		}
+		c.finishSpan(span, err)
+	}()
+	return c.Client.ReadWriteTransactionWithOptions(ctx, func(ctx context.Context, tx *spanner.ReadWriteTransaction) error {
+		attempts++
+		return f(ctx, &ReadWriteTransaction{ReadWriteTransaction: tx, c: c, attempt: attempts})
+	}, opts)
+}
+
+// Apply applies the given mutations atomically, tracing the operation.
+func (c *Client) Apply(ctx context.Context, ms []*spanner.Mutation, opts ...spanner.ApplyOption) (commitTimestamp time.Time, err error) {
+	span, ctx := c.startSpan(ctx, opApply, "Apply", tracer.Tag(tagMutationCount, len(ms)))
+	defer func() { c.finishSpan(span, err) }()
+	return c.Client.Apply(ctx, ms, opts...)
+}
+
+// PartitionedUpdate runs the given DML statement as a partitioned update, tracing the
+// operation.
+func (c *Client) PartitionedUpdate(ctx context.Context, stmt spanner.Statement) (count int64, err error) {
+	span, ctx := c.startStatementSpan(ctx, opPartitionedUpdate, stmt.SQL, 0)
+	defer func() {
+		if err == nil {
+			span.SetTag(tagRowCount, count)
+		}
+		c.finishSpan(span, err)
+	}()
+	return c.Client.PartitionedUpdate(ctx, stmt)
+}
+
+// startSpan starts a span of an operation of c with the given name and resource.
+func (c *Client) startSpan(ctx context.Context, op, resource string, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context) {
+	opts = append([]tracer.StartSpanOption{
+		tracer.ServiceName(c.cfg.serviceName),
+		tracer.ResourceName(resource),
+		tracer.SpanType(ext.SpanTypeSQL),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		tracer.Tag(ext.DBSystem, ext.DBSystemSpanner),
+	}, opts...)
+	if c.database != "" {
+		opts = append(opts, tracer.Tag(ext.DBName, c.database))
+	}
+	if c.instance != "" {
+		opts = append(opts, tracer.Tag(ext.DBInstance, c.instance))
+	}
+	if c.project != "" {
+		opts = append(opts, tracer.Tag("gcp.project_id", c.project))
+	}
+	opts = append(opts, c.cfg.spanOpts...)
+	return tracer.StartSpanFromContext(ctx, op, opts...)
+}
+
+// startStatementSpan starts the span of a SQL statement, using the obfuscated statement as
+// resource. The attempt of the read/write transaction the statement is run in is tagged
+// when not zero.
+func (c *Client) startStatementSpan(ctx context.Context, op, sql string, attempt int) (*tracer.Span, context.Context) {
+	resource, tables := c.statementResource(sql)
+	var opts []tracer.StartSpanOption
+	if tables != "" {
+		opts = append(opts, tracer.Tag(tagTables, tables))
+	}
+	if attempt > 0 {
+		opts = append(opts, tracer.Tag(tagAttempt, attempt))
+	}
+	return c.startSpan(ctx, op, resource, opts...)
+}
+
+// statementResource returns the resource of the span of the given SQL statement, and the
+// tables it refers to, if known.
+func (c *Client) statementResource(sql string) (resource, tables string) {
+	if !c.cfg.obfuscate {
+		return sql, ""
+	}
+	oq, err := obfuscator().ObfuscateSQLString(sql)
+	if err != nil {
+		return nonParsableResource, ""
+	}
+	return oq.Query, oq.Metadata.TablesCSV
+}
+
+// finishSpan finishes span with the given error, tagging its gRPC code.
+func (c *Client) finishSpan(span *tracer.Span, err error) {
+	if err != nil {
+		if code := spanner.ErrCode(err); code != codes.OK {
+			span.SetTag(tagErrorCode, code.String())
+		}
+	}
+	span.Finish(tracer.WithError(err))
+}
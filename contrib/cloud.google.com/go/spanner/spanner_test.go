@@ -0,0 +1,281 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package spanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/spannertest"
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const testDatabase = "projects/test-project/instances/test-instance/databases/test-db"
+
+func setup(t *testing.T, opts ...Option) *Client {
+	srv, err := spannertest.NewServer("localhost:0")
+	require.NoError(t, err)
+	t.Cleanup(srv.Close)
+
+	ddl, err := spansql.ParseDDL("", `CREATE TABLE Singers (
+		SingerId INT64 NOT NULL,
+		Name STRING(MAX),
+	) PRIMARY KEY (SingerId)`)
+	require.NoError(t, err)
+	require.NoError(t, srv.UpdateDDL(ddl))
+
+	clientOpts := append(ClientOptions(opts...),
+		option.WithEndpoint(srv.Addr),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	c, err := spanner.NewClient(context.Background(), testDatabase, clientOpts...)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	_, err = c.Apply(context.Background(), []*spanner.Mutation{
+		spanner.Insert("Singers", []string{"SingerId", "Name"}, []any{1, "Marc"}),
+		spanner.Insert("Singers", []string{"SingerId", "Name"}, []any{2, "Catalina"}),
+	})
+	require.NoError(t, err)
+	return WrapClient(c, opts...)
+}
+
+// spansNamed returns the finished spans with the given operation name.
+func spansNamed(mt mocktracer.Tracer, name string) []*mocktracer.Span {
+	var spans []*mocktracer.Span
+	for _, s := range mt.FinishedSpans() {
+		if s.OperationName() == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+func TestQuery(t *testing.T) {
+	c := setup(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	iter := c.Single().Query(context.Background(), spanner.Statement{SQL: "SELECT Name FROM Singers WHERE SingerId = 1"})
+	var names []string
+	err := iter.Do(func(r *spanner.Row) error {
+		var name string
+		if err := r.Columns(&name); err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Marc"}, names)
+
+	spans := spansNamed(mt, "spanner.query")
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Equal(t, "SELECT Name FROM Singers WHERE SingerId = ?", s.Tag(ext.ResourceName))
+	assert.Equal(t, "Singers", s.Tag("sql.tables"))
+	assert.Equal(t, ext.SpanTypeSQL, s.Tag(ext.SpanType))
+	assert.Equal(t, "spanner", s.Tag(ext.ServiceName))
+	assert.Equal(t, ext.DBSystemSpanner, s.Tag(ext.DBSystem))
+	assert.Equal(t, "test-db", s.Tag(ext.DBName))
+	assert.Equal(t, "test-instance", s.Tag(ext.DBInstance))
+	assert.Equal(t, "test-project", s.Tag("gcp.project_id"))
+	assert.Equal(t, ext.SpanKindClient, s.Tag(ext.SpanKind))
+	assert.Equal(t, string(instrumentation.PackageGCPSpanner), s.Tag(ext.Component))
+	assert.Equal(t, float64(1), s.Tag("spanner.row_count"))
+	assert.Nil(t, s.Tag(ext.ErrorMsg))
+}
+
+func TestQueryStop(t *testing.T) {
+	c := setup(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	iter := c.Single().Query(context.Background(), spanner.Statement{SQL: "SELECT Name FROM Singers"})
+	_, err := iter.Next()
+	require.NoError(t, err)
+	assert.Empty(t, spansNamed(mt, "spanner.query"))
+	iter.Stop()
+
+	spans := spansNamed(mt, "spanner.query")
+	require.Len(t, spans, 1)
+	assert.Equal(t, float64(1), spans[0].Tag("spanner.row_count"))
+}
+
+func TestQueryError(t *testing.T) {
+	c := setup(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	iter := c.Single().Query(context.Background(), spanner.Statement{SQL: "SELECT Name FROM Unknown"})
+	defer iter.Stop()
+	_, err := iter.Next()
+	require.Error(t, err)
+	assert.NotEqual(t, iterator.Done, err)
+
+	spans := spansNamed(mt, "spanner.query")
+	require.Len(t, spans, 1)
+	assert.NotNil(t, spans[0].Tag(ext.ErrorMsg))
+	assert.NotEmpty(t, spans[0].Tag("spanner.error_code"))
+}
+
+func TestRawStatements(t *testing.T) {
+	c := setup(t, WithRawStatements())
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	iter := c.Single().Query(context.Background(), spanner.Statement{SQL: "SELECT Name FROM Singers WHERE SingerId = 2"})
+	_, err := iter.Next()
+	require.NoError(t, err)
+	iter.Stop()
+
+	spans := spansNamed(mt, "spanner.query")
+	require.Len(t, spans, 1)
+	assert.Equal(t, "SELECT Name FROM Singers WHERE SingerId = 2", spans[0].Tag(ext.ResourceName))
+	assert.Nil(t, spans[0].Tag("sql.tables"))
+}
+
+func TestRead(t *testing.T) {
+	c := setup(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	row, err := c.Single().ReadRow(context.Background(), "Singers", spanner.Key{2}, []string{"Name"})
+	require.NoError(t, err)
+	var name string
+	require.NoError(t, row.Columns(&name))
+	assert.Equal(t, "Catalina", name)
+
+	spans := spansNamed(mt, "spanner.read")
+	require.Len(t, spans, 1)
+	assert.Equal(t, "Singers", spans[0].Tag(ext.ResourceName))
+	assert.Equal(t, "Singers", spans[0].Tag("sql.tables"))
+}
+
+func TestReadWriteTransaction(t *testing.T) {
+	c := setup(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	attempts := 0
+	_, err := c.ReadWriteTransaction(ctx, func(ctx context.Context, tx *ReadWriteTransaction) error {
+		attempts++
+		if _, err := tx.Update(ctx, spanner.Statement{SQL: "UPDATE Singers SET Name = 'Marco' WHERE SingerId = 1"}); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			// make the transaction retried, as if it was aborted by Spanner
+			return status.Error(codes.Aborted, "aborted")
+		}
+		return tx.BufferWrite([]*spanner.Mutation{
+			spanner.Insert("Singers", []string{"SingerId", "Name"}, []any{3, "Lea"}),
+		})
+	})
+	require.NoError(t, err)
+	root.Finish()
+	assert.Equal(t, 2, attempts)
+
+	txSpans := spansNamed(mt, "spanner.transaction")
+	require.Len(t, txSpans, 1)
+	tx := txSpans[0]
+	assert.Equal(t, "ReadWriteTransaction", tx.Tag(ext.ResourceName))
+	assert.Equal(t, float64(2), tx.Tag("spanner.transaction.attempts"))
+	assert.Equal(t, "true", tx.Tag("spanner.transaction.retried"))
+	assert.Equal(t, root.Context().SpanID(), tx.ParentID())
+
+	updates := spansNamed(mt, "spanner.update")
+	require.Len(t, updates, 2)
+	for i, s := range updates {
+		assert.Equal(t, tx.SpanID(), s.ParentID())
+		assert.Equal(t, "UPDATE Singers SET Name = ? WHERE SingerId = ?", s.Tag(ext.ResourceName))
+		assert.Equal(t, float64(i+1), s.Tag("spanner.transaction.attempt"))
+		assert.Equal(t, float64(1), s.Tag("spanner.row_count"))
+	}
+}
+
+func TestBatchUpdate(t *testing.T) {
+	c := setup(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	_, err := c.ReadWriteTransaction(context.Background(), func(ctx context.Context, tx *ReadWriteTransaction) error {
+		_, err := tx.BatchUpdate(ctx, []spanner.Statement{
+			{SQL: "UPDATE Singers SET Name = 'A' WHERE SingerId = 1"},
+			{SQL: "UPDATE Singers SET Name = 'B' WHERE SingerId = 2"},
+		})
+		return err
+	})
+	require.NoError(t, err)
+
+	spans := spansNamed(mt, "spanner.batch_update")
+	require.Len(t, spans, 1)
+	assert.Equal(t, "UPDATE Singers SET Name = ? WHERE SingerId = ?", spans[0].Tag(ext.ResourceName))
+	assert.Equal(t, float64(2), spans[0].Tag("spanner.statement_count"))
+	assert.Equal(t, float64(2), spans[0].Tag("spanner.row_count"))
+	assert.Equal(t, float64(1), spans[0].Tag("spanner.transaction.attempt"))
+}
+
+func TestApply(t *testing.T) {
+	c := setup(t, WithService("my-spanner"))
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	_, err := c.Apply(context.Background(), []*spanner.Mutation{
+		spanner.Delete("Singers", spanner.Key{1}),
+	})
+	require.NoError(t, err)
+
+	spans := spansNamed(mt, "spanner.apply")
+	require.Len(t, spans, 1)
+	assert.Equal(t, "Apply", spans[0].Tag(ext.ResourceName))
+	assert.Equal(t, "my-spanner", spans[0].Tag(ext.ServiceName))
+	assert.Equal(t, float64(1), spans[0].Tag("spanner.mutation_count"))
+}
+
+func TestSessions(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c := setup(t)
+	_, err := c.Single().ReadRow(context.Background(), "Singers", spanner.Key{1}, []string{"Name"})
+	require.NoError(t, err)
+
+	spans := spansNamed(mt, "spanner.session")
+	require.NotEmpty(t, spans)
+	for _, s := range spans {
+		assert.Contains(t, []string{"CreateSession", "BatchCreateSessions"}, s.Tag(ext.ResourceName))
+		assert.Equal(t, "test-db", s.Tag(ext.DBName))
+		assert.Equal(t, string(instrumentation.PackageGCPSpanner), s.Tag(ext.Component))
+	}
+}
+
+func TestParseDatabaseName(t *testing.T) {
+	project, instance, database := parseDatabaseName("projects/p/instances/i/databases/d/sessions/s")
+	assert.Equal(t, "p", project)
+	assert.Equal(t, "i", instance)
+	assert.Equal(t, "d", database)
+
+	project, instance, database = parseDatabaseName("")
+	assert.Empty(t, project)
+	assert.Empty(t, instance)
+	assert.Empty(t, database)
+}
@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package spanner
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
+)
+
+// ReadOnlyTransaction wraps a *spanner.ReadOnlyTransaction, tracing its queries and reads.
+type ReadOnlyTransaction struct {
+	*spanner.ReadOnlyTransaction
+	c *Client
+}
+
+// WithTimestampBound specifies the timestamp bound of the transaction, and returns t.
+func (t *ReadOnlyTransaction) WithTimestampBound(tb spanner.TimestampBound) *ReadOnlyTransaction {
+	t.ReadOnlyTransaction.WithTimestampBound(tb)
+	return t
+}
+
+// Query runs the given query in the transaction, tracing it until the returned iterator is
+// done or stopped.
+func (t *ReadOnlyTransaction) Query(ctx context.Context, stmt spanner.Statement) *RowIterator {
+	span, ctx := t.c.startStatementSpan(ctx, opQuery, stmt.SQL, 0)
+	return t.c.wrapIterator(span, t.ReadOnlyTransaction.Query(ctx, stmt))
+}
+
+// Read reads the given columns of the rows of table with the given keys, tracing the read
+// until the returned iterator is done or stopped.
+func (t *ReadOnlyTransaction) Read(ctx context.Context, table string, keys spanner.KeySet, columns []string) *RowIterator {
+	span, ctx := t.c.startReadSpan(ctx, table, 0)
+	return t.c.wrapIterator(span, t.ReadOnlyTransaction.Read(ctx, table, keys, columns))
+}
+
+// ReadRow reads the given columns of the row of table with the given key, tracing the read.
+func (t *ReadOnlyTransaction) ReadRow(ctx context.Context, table string, key spanner.Key, columns []string) (row *spanner.Row, err error) {
+	span, ctx := t.c.startReadSpan(ctx, table, 0)
+	defer func() { t.c.finishSpan(span, err) }()
+	return t.ReadOnlyTransaction.ReadRow(ctx, table, key, columns)
+}
+
+// ReadWriteTransaction wraps a *spanner.ReadWriteTransaction, tracing its queries, reads and
+// DML statements. The spans of the statements are tagged with the attempt of the
+// transaction they are run in.
+type ReadWriteTransaction struct {
+	*spanner.ReadWriteTransaction
+	c       *Client
+	attempt int
+}
+
+// Query runs the given query in the transaction, tracing it until the returned iterator is
+// done or stopped.
+func (t *ReadWriteTransaction) Query(ctx context.Context, stmt spanner.Statement) *RowIterator {
+	span, ctx := t.c.startStatementSpan(ctx, opQuery, stmt.SQL, t.attempt)
+	return t.c.wrapIterator(span, t.ReadWriteTransaction.Query(ctx, stmt))
+}
+
+// Read reads the given columns of the rows of table with the given keys, tracing the read
+// until the returned iterator is done or stopped.
+func (t *ReadWriteTransaction) Read(ctx context.Context, table string, keys spanner.KeySet, columns []string) *RowIterator {
+	span, ctx := t.c.startReadSpan(ctx, table, t.attempt)
+	return t.c.wrapIterator(span, t.ReadWriteTransaction.Read(ctx, table, keys, columns))
+}
+
+// ReadRow reads the given columns of the row of table with the given key, tracing the read.
+func (t *ReadWriteTransaction) ReadRow(ctx context.Context, table string, key spanner.Key, columns []string) (row *spanner.Row, err error) {
+	span, ctx := t.c.startReadSpan(ctx, table, t.attempt)
+	defer func() { t.c.finishSpan(span, err) }()
+	return t.ReadWriteTransaction.ReadRow(ctx, table, key, columns)
+}
+
+// Update runs the given DML statement in the transaction, tracing it.
+func (t *ReadWriteTransaction) Update(ctx context.Context, stmt spanner.Statement) (rowCount int64, err error) {
+	span, ctx := t.c.startStatementSpan(ctx, opUpdate, stmt.SQL, t.attempt)
+	defer func() {
+		if err == nil {
+			span.SetTag(tagRowCount, rowCount)
+		}
+		t.c.finishSpan(span, err)
+	}()
+	return t.ReadWriteTransaction.Update(ctx, stmt)
+}
+
+// BatchUpdate runs the given DML statements in the transaction, tracing them with a single
+// span. The resource of the span is the first statement of the batch.
+func (t *ReadWriteTransaction) BatchUpdate(ctx context.Context, stmts []spanner.Statement) (rowCounts []int64, err error) {
+	var sql string
+	if len(stmts) > 0 {
+		sql = stmts[0].SQL
+	}
+	span, ctx := t.c.startStatementSpan(ctx, opBatchUpdate, sql, t.attempt)
+	span.SetTag(tagStatementCount, len(stmts))
+	defer func() {
+		var total int64
+		for _, n := range rowCounts {
+			total += n
+		}
+		span.SetTag(tagRowCount, total)
+		t.c.finishSpan(span, err)
+	}()
+	return t.ReadWriteTransaction.BatchUpdate(ctx, stmts)
+}
+
+// startReadSpan starts the span of a read of table.
+func (c *Client) startReadSpan(ctx context.Context, table string, attempt int) (*tracer.Span, context.Context) {
+	opts := []tracer.StartSpanOption{tracer.Tag(tagTables, table)}
+	if attempt > 0 {
+		opts = append(opts, tracer.Tag(tagAttempt, attempt))
+	}
+	return c.startSpan(ctx, opRead, table, opts...)
+}
+
+// RowIterator wraps a *spanner.RowIterator, finishing the span of its query or read when
+// it's done, fails or is stopped.
+type RowIterator struct {
+	*spanner.RowIterator
+	c    *Client
+	span *tracer.Span
+	rows int
+}
+
+func (c *Client) wrapIterator(span *tracer.Span, it *spanner.RowIterator) *RowIterator {
+	return &RowIterator{RowIterator: it, c: c, span: span}
+}
+
+// Next returns the next row, or iterator.Done when there are no more rows.
+func (it *RowIterator) Next() (*spanner.Row, error) {
+	row, err := it.RowIterator.Next()
+	switch {
+	case err == iterator.Done:
+		it.finish(nil)
+	case err != nil:
+		it.finish(err)
+	default:
+		it.rows++
+	}
+	return row, err
+}
+
+// Do calls f for each row, until there are no more rows or f returns an error. The
+// iterator is stopped when Do returns.
+func (it *RowIterator) Do(f func(r *spanner.Row) error) error {
+	defer it.Stop()
+	for {
+		row, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(row); err != nil {
+			return err
+		}
+	}
+}
+
+// Stop terminates the iteration, finishing the span if it isn't finished already.
+func (it *RowIterator) Stop() {
+	it.RowIterator.Stop()
+	it.finish(nil)
+}
+
+func (it *RowIterator) finish(err error) {
+	if it.span == nil {
+		return
+	}
+	it.span.SetTag(tagRowCount, it.rows)
+	it.c.finishSpan(it.span, err)
+	it.span = nil
+}
@@ -7,6 +7,7 @@
 package kafka // import "github.com/DataDog/dd-trace-go/contrib/confluentinc/confluent-kafka-go/kafka/v2"
 
 import (
+	"context"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
@@ -126,6 +127,17 @@ func (c *Consumer) ReadMessage(timeout time.Duration) (*kafka.Message, error) {
 	return msg, nil
 }
 
+// StartBatchSpan starts a span for the processing of a batch of messages read from c, linking
+// to the trace of each message, and returns it along with a context holding it. The caller
+// must finish the span once the batch is processed.
+func (c *Consumer) StartBatchSpan(ctx context.Context, msgs []*kafka.Message) (*tracer.Span, context.Context) {
+	tMsgs := make([]kafkatrace.Message, len(msgs))
+	for i, msg := range msgs {
+		tMsgs[i] = wrapMessage(msg)
+	}
+	return c.tracer.StartConsumeBatchSpan(ctx, tMsgs)
+}
+
 // Commit commits current offsets and tracks the commit offsets if data streams is enabled.
 func (c *Consumer) Commit() ([]kafka.TopicPartition, error) {
 	tps, err := c.Consumer.Commit()
@@ -6,6 +6,8 @@
 package kafka
 
 import (
+	"time"
+
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 
 	"github.com/DataDog/dd-trace-go/v2/contrib/confluentinc/confluent-kafka-go/kafkatrace"
@@ -57,6 +59,10 @@ func (w *wMessage) GetTopicPartition() kafkatrace.TopicPartition {
 	return wrapTopicPartition(w.Message.TopicPartition)
 }
 
+func (w *wMessage) GetTimestamp() time.Time {
+	return w.Message.Timestamp
+}
+
 type wHeader struct {
 	kafka.Header
 }
@@ -6,10 +6,12 @@
 package kafkatrace
 
 import (
+	"context"
 	"math"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/kafkabatch"
 )
 
 func WrapConsumeEventsChannel[E any, TE Event](tr *Tracer, in chan E, consumer Consumer, translateFn func(E) TE) chan E {
@@ -88,3 +90,39 @@ func (tr *Tracer) StartConsumeSpan(msg Message) *tracer.Span {
 	tracer.Inject(span.Context(), carrier)
 	return span
 }
+
+// StartConsumeBatchSpan starts a span for the processing of a batch of messages, linking to
+// the trace of each message, see kafkabatch.SpanOptions.
+func (tr *Tracer) StartConsumeBatchSpan(ctx context.Context, msgs []Message) (*tracer.Span, context.Context) {
+	opts := kafkabatch.SpanOptions(len(msgs), func(i int) kafkabatch.Message {
+		tp := msgs[i].GetTopicPartition()
+		m := kafkabatch.Message{
+			Topic:     tp.GetTopic(),
+			Partition: int(tp.GetPartition()),
+			Offset:    tp.GetOffset(),
+			Carrier:   MessageCarrier{msg: msgs[i]},
+		}
+		if tm, ok := msgs[i].(TimestampedMessage); ok {
+			m.Time = tm.GetTimestamp()
+		}
+		return m
+	})
+	opts = append(opts,
+		tracer.ServiceName(tr.consumerServiceName),
+		tracer.SpanType(ext.SpanTypeMessageConsumer),
+		tracer.Tag(ext.Component, ComponentName(tr.ckgoVersion)),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemKafka),
+		tracer.Measured(),
+	)
+	if tr.bootstrapServers != "" {
+		opts = append(opts, tracer.Tag(ext.KafkaBootstrapServers, tr.bootstrapServers))
+	}
+	if !math.IsNaN(tr.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, tr.analyticsRate))
+	}
+	if ctx == nil {
+		ctx = tr.ctx
+	}
+	return tracer.StartSpanFromContext(ctx, tr.consumerSpanName, opts...)
+}
@@ -6,12 +6,20 @@
 package kafkatrace
 
 import (
+	"context"
 	"math"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/kafkabatch"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
 )
 
@@ -64,3 +72,87 @@ func TestAnalyticsSettings(t *testing.T) {
 		assert.Equal(t, 0.2, tr.analyticsRate)
 	})
 }
+
+type testTopicPartition struct {
+	topic     string
+	partition int32
+	offset    int64
+}
+
+func (tp testTopicPartition) GetTopic() string    { return tp.topic }
+func (tp testTopicPartition) GetPartition() int32 { return tp.partition }
+func (tp testTopicPartition) GetOffset() int64    { return tp.offset }
+func (tp testTopicPartition) GetError() error     { return nil }
+
+type testMessage struct {
+	tp        testTopicPartition
+	headers   []Header
+	timestamp time.Time
+}
+
+func (m *testMessage) GetValue() []byte                  { return nil }
+func (m *testMessage) GetKey() []byte                    { return nil }
+func (m *testMessage) GetHeaders() []Header              { return m.headers }
+func (m *testMessage) SetHeaders(hs []Header)            { m.headers = hs }
+func (m *testMessage) GetTopicPartition() TopicPartition { return m.tp }
+func (m *testMessage) Unwrap() any                       { return m }
+func (m *testMessage) GetTimestamp() time.Time           { return m.timestamp }
+
+func TestStartConsumeBatchSpan(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var (
+		msgs    []Message
+		parents []*tracer.Span
+	)
+	for i := 0; i < 3; i++ {
+		parent := tracer.StartSpan("produce")
+		parents = append(parents, parent)
+		msg := &testMessage{
+			tp:        testTopicPartition{topic: "topic", partition: int32(i), offset: int64(10 + i)},
+			timestamp: time.Now().Add(-time.Duration(3-i) * time.Second),
+		}
+		require.NoError(t, tracer.Inject(parent.Context(), NewMessageCarrier(msg)))
+		msgs = append(msgs, msg)
+		parent.Finish()
+	}
+	// messages without trace context are counted but not linked
+	msgs = append(msgs, &testMessage{tp: testTopicPartition{topic: "topic", offset: 20}})
+
+	tr := NewKafkaTracer(testInstr, 0, 0)
+	span, ctx := tr.StartConsumeBatchSpan(context.Background(), msgs)
+	got, ok := tracer.SpanFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, span, got)
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 4)
+	s := spans[3]
+	assert.Equal(t, "Consume Batch Topic topic", s.Tag(ext.ResourceName))
+	assert.Equal(t, "topic", s.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, float64(4), s.Tag(ext.MessagingBatchMessageCount))
+	assert.Equal(t, ext.SpanKindConsumer, s.Tag(ext.SpanKind))
+	assert.GreaterOrEqual(t, s.Tag(kafkabatch.TagLag), float64(3000))
+	assert.Zero(t, s.ParentID())
+	links := s.Links()
+	require.Len(t, links, 3)
+	for i, link := range links {
+		assert.Equal(t, parents[i].Context().SpanID(), link.SpanID)
+		assert.Equal(t, parents[i].Context().TraceIDLower(), link.TraceID)
+		assert.Equal(t, map[string]string{ext.MessagingKafkaPartition: strconv.Itoa(i), "offset": strconv.Itoa(10 + i)}, link.Attributes)
+	}
+
+	t.Run("topics", func(t *testing.T) {
+		span, _ := tr.StartConsumeBatchSpan(context.Background(), []Message{
+			&testMessage{tp: testTopicPartition{topic: "a"}},
+			&testMessage{tp: testTopicPartition{topic: "b"}},
+		})
+		span.Finish()
+		s := mt.FinishedSpans()[4]
+		assert.Equal(t, "Consume Batch", s.Tag(ext.ResourceName))
+		assert.Nil(t, s.Tag(ext.MessagingDestinationName))
+		assert.Nil(t, s.Tag(kafkabatch.TagLag))
+	})
+}
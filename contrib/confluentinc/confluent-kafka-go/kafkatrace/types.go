@@ -5,7 +5,11 @@
 
 package kafkatrace
 
-import "github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+import (
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
 
 type Message interface {
 	GetValue() []byte
@@ -16,6 +20,11 @@ type Message interface {
 	Unwrap() any
 }
 
+// TimestampedMessage is implemented by messages which carry their timestamp.
+type TimestampedMessage interface {
+	GetTimestamp() time.Time
+}
+
 type Header interface {
 	GetKey() string
 	GetValue() []byte
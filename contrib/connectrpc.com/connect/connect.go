@@ -0,0 +1,265 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package connect provides an interceptor tracing the RPCs of connectrpc.com/connect clients and handlers.
+//
+// The spans are named and tagged as the spans of the google.golang.org/grpc integration, and the
+// trace context is propagated in the HTTP headers of the requests, whatever the protocol: Connect,
+// gRPC or gRPC-Web.
+package connect // import "github.com/DataDog/dd-trace-go/contrib/connectrpc.com/connect/v2"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"connectrpc.com/connect"
+)
+
+const componentName = instrumentation.PackageConnectRPC
+
+// Tags of the spans of RPCs. The gRPC ones are shared with the google.golang.org/grpc integration.
+const (
+	tagMethodKind = "grpc.method.kind"
+	tagCode       = "grpc.code"
+	tagProtocol   = "rpc.connect_rpc.protocol"
+	tagErrorCode  = "rpc.connect_rpc.error_code"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageConnectRPC)
+}
+
+// NewInterceptor returns an interceptor tracing the RPCs of the clients and handlers it is
+// added to with connect.WithInterceptors.
+func NewInterceptor(opts ...Option) connect.Interceptor {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	instr.Logger().Debug("contrib/connectrpc.com/connect: Configuring Interceptor: %#v", cfg)
+	return &interceptor{cfg: cfg}
+}
+
+type interceptor struct {
+	cfg *config
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		spec := req.Spec()
+		if _, ok := i.cfg.untracedProcedures[spec.Procedure]; ok {
+			return next(ctx, req)
+		}
+		span, ctx := i.startSpan(ctx, spec, req.Peer(), req.Header())
+		resp, err := next(ctx, req)
+		i.finish(span, err)
+		return resp, err
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		if _, ok := i.cfg.untracedProcedures[spec.Procedure]; ok {
+			return next(ctx, spec)
+		}
+		span, ctx := i.startSpan(ctx, spec, connect.Peer{}, nil)
+		conn := next(ctx, spec)
+		setPeerTags(span, spec, conn.Peer())
+		// the request headers are sent with the first message
+		inject(span, conn.RequestHeader())
+		return &clientConn{StreamingClientConn: conn, i: i, span: span}
+	}
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		spec := conn.Spec()
+		if _, ok := i.cfg.untracedProcedures[spec.Procedure]; ok {
+			return next(ctx, conn)
+		}
+		span, ctx := i.startSpan(ctx, spec, conn.Peer(), conn.RequestHeader())
+		err := next(ctx, conn)
+		i.finish(span, err)
+		return err
+	}
+}
+
+// startSpan starts the span of an RPC. Client spans inject their context in header, while
+// handler spans continue the trace extracted from it.
+func (i *interceptor) startSpan(ctx context.Context, spec connect.Spec, peer connect.Peer, header http.Header) (*tracer.Span, context.Context) {
+	service, method := splitProcedure(spec.Procedure)
+	opts := []tracer.StartSpanOption{
+		tracer.ResourceName(spec.Procedure),
+		tracer.SpanType(ext.AppTypeRPC),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.RPCSystem, ext.RPCSystemConnectRPC),
+		tracer.Tag(ext.RPCService, service),
+		tracer.Tag(ext.RPCMethod, method),
+		tracer.Tag(ext.GRPCFullMethod, spec.Procedure),
+		tracer.Tag(tagMethodKind, methodKind(spec.StreamType)),
+	}
+	opts = append(opts, i.cfg.spanOpts...)
+	spanName := i.cfg.clientSpanName
+	if spec.IsClient {
+		opts = append(opts,
+			tracer.ServiceName(i.cfg.clientServiceName),
+			tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		)
+	} else {
+		spanName = i.cfg.serverSpanName
+		opts = append(opts,
+			tracer.ServiceName(i.cfg.serverServiceName),
+			tracer.Tag(ext.SpanKind, ext.SpanKindServer),
+			tracer.Measured(),
+		)
+		if spanCtx, err := tracer.Extract(tracer.HTTPHeadersCarrier(header)); err == nil {
+			if links := spanCtx.SpanLinks(); links != nil {
+				opts = append(opts, tracer.WithSpanLinks(links))
+			}
+			opts = append(opts, tracer.ChildOf(spanCtx))
+		}
+	}
+	span, ctx := tracer.StartSpanFromContext(ctx, spanName, opts...)
+	setPeerTags(span, spec, peer)
+	if spec.IsClient && header != nil {
+		inject(span, header)
+	}
+	return span, ctx
+}
+
+// finish finishes the span of an RPC with its status code.
+func (i *interceptor) finish(span *tracer.Span, err error) {
+	if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	code := codeOK
+	if err != nil {
+		code = connect.CodeOf(err)
+		span.SetTag(tagErrorCode, code.String())
+		if i.cfg.nonErrorCodes[code] {
+			err = nil
+		}
+	}
+	span.SetTag(tagCode, grpcCodeName(code))
+	span.Finish(tracer.WithError(err))
+}
+
+// setPeerTags tags span with the protocol of the RPC and the address of the server it is sent to.
+func setPeerTags(span *tracer.Span, spec connect.Spec, peer connect.Peer) {
+	if peer.Protocol != "" {
+		span.SetTag(tagProtocol, peer.Protocol)
+	}
+	if !spec.IsClient || peer.Addr == "" {
+		return
+	}
+	host, port, err := net.SplitHostPort(peer.Addr)
+	if err != nil {
+		// the address of clients may not have a port
+		host = peer.Addr
+	}
+	span.SetTag(ext.NetworkDestinationName, host)
+	if port != "" {
+		span.SetTag(ext.NetworkDestinationPort, port)
+	}
+}
+
+func inject(span *tracer.Span, header http.Header) {
+	if err := tracer.Inject(span.Context(), tracer.HTTPHeadersCarrier(header)); err != nil {
+		instr.Logger().Debug("contrib/connectrpc.com/connect: failed to inject span context in request headers: %v", err)
+	}
+}
+
+// clientConn finishes the span of a streaming RPC once its response is fully received.
+type clientConn struct {
+	connect.StreamingClientConn
+	i    *interceptor
+	span *tracer.Span
+	once sync.Once
+}
+
+func (c *clientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	if err != nil {
+		c.finish(err)
+	}
+	return err
+}
+
+func (c *clientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.finish(nil)
+	return err
+}
+
+func (c *clientConn) finish(err error) {
+	c.once.Do(func() {
+		c.i.finish(c.span, err)
+	})
+}
+
+// splitProcedure returns the service and the method of a procedure such as
+// "/acme.foo.v1.FooService/Bar".
+func splitProcedure(procedure string) (service, method string) {
+	service, method, _ = strings.Cut(strings.TrimPrefix(procedure, "/"), "/")
+	return service, method
+}
+
+func methodKind(t connect.StreamType) string {
+	switch t {
+	case connect.StreamTypeClient:
+		return "client_streaming"
+	case connect.StreamTypeServer:
+		return "server_streaming"
+	case connect.StreamTypeBidi:
+		return "bidi_streaming"
+	}
+	return "unary"
+}
+
+// codeOK is the code of successful RPCs, which connect doesn't define.
+const codeOK connect.Code = 0
+
+// grpcCodeNames are the names of the gRPC codes, which have the same values as Connect codes,
+// as reported by the google.golang.org/grpc integration.
+var grpcCodeNames = [...]string{
+	"OK",
+	"Canceled",
+	"Unknown",
+	"InvalidArgument",
+	"DeadlineExceeded",
+	"NotFound",
+	"AlreadyExists",
+	"PermissionDenied",
+	"ResourceExhausted",
+	"FailedPrecondition",
+	"Aborted",
+	"OutOfRange",
+	"Unimplemented",
+	"Internal",
+	"Unavailable",
+	"DataLoss",
+	"Unauthenticated",
+}
+
+func grpcCodeName(c connect.Code) string {
+	if int(c) < len(grpcCodeNames) {
+		return grpcCodeNames[c]
+	}
+	return c.String()
+}
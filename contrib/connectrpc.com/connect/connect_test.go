@@ -0,0 +1,186 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	unaryProcedure  = "/test.v1.TestService/Ping"
+	streamProcedure = "/test.v1.TestService/Stream"
+)
+
+func newServer(t *testing.T, opts ...Option) *httptest.Server {
+	interceptors := connect.WithInterceptors(NewInterceptor(opts...))
+	mux := http.NewServeMux()
+	mux.Handle(unaryProcedure, connect.NewUnaryHandler(unaryProcedure,
+		func(_ context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			if req.Msg.Value == "fail" {
+				return nil, connect.NewError(connect.CodeNotFound, errors.New("not found"))
+			}
+			return connect.NewResponse(wrapperspb.String(req.Msg.Value)), nil
+		}, interceptors))
+	mux.Handle(streamProcedure, connect.NewServerStreamHandler(streamProcedure,
+		func(_ context.Context, req *connect.Request[wrapperspb.StringValue], stream *connect.ServerStream[wrapperspb.StringValue]) error {
+			for i := 0; i < 3; i++ {
+				if err := stream.Send(wrapperspb.String(req.Msg.Value)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, interceptors))
+	srv := httptest.NewUnstartedServer(mux)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestUnary(t *testing.T) {
+	for name, clientOpt := range map[string]connect.ClientOption{
+		"connect": connect.WithProtoJSON(),
+		"grpc":    connect.WithGRPC(),
+		"grpcweb": connect.WithGRPCWeb(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			srv := newServer(t)
+			client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+unaryProcedure,
+				clientOpt, connect.WithInterceptors(NewInterceptor()))
+			resp, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("hello")))
+			require.NoError(t, err)
+			assert.Equal(t, "hello", resp.Msg.Value)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 2)
+			server, client_ := spans[0], spans[1]
+			if server.Tag(ext.SpanKind) != ext.SpanKindServer {
+				server, client_ = client_, server
+			}
+			assert.Equal(t, "grpc.server", server.OperationName())
+			assert.Equal(t, "grpc.client", client_.OperationName())
+			assert.Equal(t, client_.SpanID(), server.ParentID())
+			assert.Equal(t, client_.TraceID(), server.TraceID())
+			for _, s := range spans {
+				assert.Equal(t, unaryProcedure, s.Tag(ext.ResourceName))
+				assert.Equal(t, ext.RPCSystemConnectRPC, s.Tag(ext.RPCSystem))
+				assert.Equal(t, "test.v1.TestService", s.Tag(ext.RPCService))
+				assert.Equal(t, "Ping", s.Tag(ext.RPCMethod))
+				assert.Equal(t, unaryProcedure, s.Tag(ext.GRPCFullMethod))
+				assert.Equal(t, "unary", s.Tag(tagMethodKind))
+				assert.Equal(t, "OK", s.Tag(tagCode))
+				assert.Equal(t, name, s.Tag(tagProtocol))
+				assert.Equal(t, "connectrpc.com/connect", s.Tag(ext.Component))
+			}
+			assert.Equal(t, "127.0.0.1", client_.Tag(ext.NetworkDestinationName))
+		})
+	}
+}
+
+func TestUnaryError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	srv := newServer(t)
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+unaryProcedure,
+		connect.WithInterceptors(NewInterceptor()))
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("fail")))
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	for _, s := range spans {
+		assert.Equal(t, "NotFound", s.Tag(tagCode))
+		assert.Equal(t, "not_found", s.Tag(tagErrorCode))
+		assert.NotNil(t, s.Tag(ext.ErrorMsg))
+	}
+}
+
+func TestNonErrorCodes(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	srv := newServer(t, NonErrorCodes(connect.CodeNotFound))
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+unaryProcedure)
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("fail")))
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "NotFound", spans[0].Tag(tagCode))
+	assert.Nil(t, spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestServerStream(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	srv := newServer(t)
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+streamProcedure,
+		connect.WithInterceptors(NewInterceptor()))
+	stream, err := client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.String("hello")))
+	require.NoError(t, err)
+	n := 0
+	for stream.Receive() {
+		n++
+	}
+	require.NoError(t, stream.Err())
+	require.NoError(t, stream.Close())
+	assert.Equal(t, 3, n)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	server, client_ := spans[0], spans[1]
+	if server.Tag(ext.SpanKind) != ext.SpanKindServer {
+		server, client_ = client_, server
+	}
+	assert.Equal(t, client_.SpanID(), server.ParentID())
+	for _, s := range spans {
+		assert.Equal(t, "server_streaming", s.Tag(tagMethodKind))
+		assert.Equal(t, "OK", s.Tag(tagCode))
+		assert.Nil(t, s.Tag(ext.ErrorMsg))
+	}
+}
+
+func TestUntracedProcedures(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	srv := newServer(t, WithUntracedProcedures(unaryProcedure))
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+unaryProcedure)
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("hello")))
+	require.NoError(t, err)
+	assert.Empty(t, mt.FinishedSpans())
+}
+
+func TestWithService(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	srv := newServer(t, WithService("pinger"))
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](srv.Client(), srv.URL+unaryProcedure)
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("hello")))
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "pinger", spans[0].Tag(ext.ServiceName))
+}
@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package connect_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	connecttrace "github.com/DataDog/dd-trace-go/contrib/connectrpc.com/connect/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func Example_client() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	// Create the interceptor and add it to the options of the client.
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+		http.DefaultClient,
+		"http://localhost:8080/acme.ping.v1.PingService/Ping",
+		connect.WithInterceptors(connecttrace.NewInterceptor(connecttrace.WithService("ping-client"))),
+	)
+	if _, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("ping"))); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Example_server() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	// Create the interceptor and add it to the options of the handlers.
+	mux := http.NewServeMux()
+	mux.Handle("/acme.ping.v1.PingService/Ping", connect.NewUnaryHandler(
+		"/acme.ping.v1.PingService/Ping",
+		func(_ context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			return connect.NewResponse(wrapperspb.String("pong")), nil
+		},
+		connect.WithInterceptors(connecttrace.NewInterceptor(connecttrace.WithService("ping-server"))),
+	))
+	log.Fatal(http.ListenAndServe(":8080", mux))
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package connect
+
+import (
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"connectrpc.com/connect"
+)
+
+type config struct {
+	clientServiceName  string
+	serverServiceName  string
+	clientSpanName     string
+	serverSpanName     string
+	nonErrorCodes      map[connect.Code]bool
+	untracedProcedures map[string]struct{}
+	spanOpts           []tracer.StartSpanOption
+}
+
+// Option describes options for the Connect integration.
+type Option func(*config)
+
+func defaultConfig() *config {
+	cfg := &config{
+		clientServiceName:  instr.ServiceName(instrumentation.ComponentClient, nil),
+		serverServiceName:  instr.ServiceName(instrumentation.ComponentServer, nil),
+		clientSpanName:     instr.OperationName(instrumentation.ComponentClient, nil),
+		serverSpanName:     instr.OperationName(instrumentation.ComponentServer, nil),
+		nonErrorCodes:      map[connect.Code]bool{connect.CodeCanceled: true},
+		untracedProcedures: make(map[string]struct{}),
+	}
+	if rate := instr.AnalyticsRate(false); !math.IsNaN(rate) {
+		cfg.spanOpts = append(cfg.spanOpts, tracer.AnalyticsRate(rate))
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the spans of the RPCs.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.clientServiceName = name
+		cfg.serverServiceName = name
+	}
+}
+
+// NonErrorCodes determines the list of codes which will not be considered errors in instrumentation.
+// This call overrides the default handling of connect.CodeCanceled as a non-error.
+func NonErrorCodes(cs ...connect.Code) Option {
+	return func(cfg *config) {
+		cfg.nonErrorCodes = make(map[connect.Code]bool, len(cs))
+		for _, c := range cs {
+			cfg.nonErrorCodes[c] = true
+		}
+	}
+}
+
+// WithUntracedProcedures specifies full procedures to be ignored by the interceptor,
+// e.g. "/grpc.health.v1.Health/Check".
+func WithUntracedProcedures(procedures ...string) Option {
+	return func(cfg *config) {
+		for _, p := range procedures {
+			cfg.untracedProcedures[p] = struct{}{}
+		}
+	}
+}
+
+// WithSpanOptions defines a set of additional tracer.StartSpanOption to be added
+// to spans started by the integration.
+func WithSpanOptions(opts ...tracer.StartSpanOption) Option {
+	return func(cfg *config) {
+		cfg.spanOpts = append(cfg.spanOpts, opts...)
+	}
+}
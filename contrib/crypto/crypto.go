@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package crypto provides [context.Context]-aware wrappers of the weak hashing algorithms and
+// ciphers of the standard library's crypto packages, reporting their usage to Datadog Code
+// Security.
+//
+// The detection of code vulnerabilities is enabled with DD_IAST_ENABLED=true. When enabled,
+// each call site of the wrappers is reported once as a vulnerability on the root span of the
+// trace of the given context. The wrappers behave as the functions they wrap otherwise.
+package crypto // import "github.com/DataDog/dd-trace-go/v2/contrib/crypto"
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/md5"
+	"crypto/rc4"
+	"crypto/sha1"
+	"hash"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/internal/iast"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageCrypto)
+}
+
+// NewMD5 returns a new [hash.Hash] computing the MD5 checksum, as [md5.New], and reports the
+// usage of a weak hashing algorithm.
+func NewMD5(ctx context.Context) hash.Hash {
+	iast.Report(ctx, iast.WeakHash, "md5", 0)
+	return md5.New()
+}
+
+// MD5Sum returns the MD5 checksum of data, as [md5.Sum], and reports the usage of a weak
+// hashing algorithm.
+func MD5Sum(ctx context.Context, data []byte) [md5.Size]byte {
+	iast.Report(ctx, iast.WeakHash, "md5", 0)
+	return md5.Sum(data)
+}
+
+// NewSHA1 returns a new [hash.Hash] computing the SHA1 checksum, as [sha1.New], and reports
+// the usage of a weak hashing algorithm.
+func NewSHA1(ctx context.Context) hash.Hash {
+	iast.Report(ctx, iast.WeakHash, "sha1", 0)
+	return sha1.New()
+}
+
+// SHA1Sum returns the SHA1 checksum of data, as [sha1.Sum], and reports the usage of a weak
+// hashing algorithm.
+func SHA1Sum(ctx context.Context, data []byte) [sha1.Size]byte {
+	iast.Report(ctx, iast.WeakHash, "sha1", 0)
+	return sha1.Sum(data)
+}
+
+// NewDESCipher creates and returns a new DES [cipher.Block], as [des.NewCipher], and reports
+// the usage of a weak cipher.
+func NewDESCipher(ctx context.Context, key []byte) (cipher.Block, error) {
+	iast.Report(ctx, iast.WeakCipher, "des", 0)
+	return des.NewCipher(key)
+}
+
+// NewTripleDESCipher creates and returns a new TripleDES [cipher.Block], as
+// [des.NewTripleDESCipher], and reports the usage of a weak cipher.
+func NewTripleDESCipher(ctx context.Context, key []byte) (cipher.Block, error) {
+	iast.Report(ctx, iast.WeakCipher, "tripledes", 0)
+	return des.NewTripleDESCipher(key)
+}
+
+// NewRC4Cipher creates and returns a new RC4 [rc4.Cipher], as [rc4.NewCipher], and reports the
+// usage of a weak cipher.
+func NewRC4Cipher(ctx context.Context, key []byte) (*rc4.Cipher, error) {
+	iast.Report(ctx, iast.WeakCipher, "rc4", 0)
+	return rc4.NewCipher(key)
+}
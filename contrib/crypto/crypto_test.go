@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package crypto
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/internal/iast"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type vulnerabilities struct {
+	Vulnerabilities []struct {
+		Type     string `json:"type"`
+		Evidence struct {
+			Value string `json:"value"`
+		} `json:"evidence"`
+		Location struct {
+			Path string `json:"path"`
+			Line int    `json:"line"`
+		} `json:"location"`
+	} `json:"vulnerabilities"`
+}
+
+func TestWeakCrypto(t *testing.T) {
+	t.Setenv(iast.EnvEnabled, "true")
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "http.request")
+	child, cctx := tracer.StartSpanFromContext(ctx, "child")
+	assert.Equal(t, md5.Sum([]byte("data")), MD5Sum(cctx, []byte("data")))
+	_, err := NewDESCipher(cctx, []byte("12345678"))
+	require.NoError(t, err)
+	child.Finish()
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Nil(t, spans[0].Tag("_dd.iast.json"))
+
+	var v vulnerabilities
+	require.NoError(t, json.Unmarshal([]byte(spans[1].Tag("_dd.iast.json").(string)), &v))
+	require.Len(t, v.Vulnerabilities, 2)
+	assert.Equal(t, iast.WeakHash, v.Vulnerabilities[0].Type)
+	assert.Equal(t, "md5", v.Vulnerabilities[0].Evidence.Value)
+	assert.Contains(t, v.Vulnerabilities[0].Location.Path, "crypto_test.go")
+	assert.Equal(t, iast.WeakCipher, v.Vulnerabilities[1].Type)
+	assert.Equal(t, "des", v.Vulnerabilities[1].Evidence.Value)
+	assert.Equal(t, v.Vulnerabilities[0].Location.Line+1, v.Vulnerabilities[1].Location.Line)
+}
+
+func TestWeakCryptoDisabled(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "http.request")
+	NewSHA1(ctx).Write([]byte("data"))
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Nil(t, spans[0].Tag("_dd.iast.json"))
+}
@@ -104,6 +104,18 @@ func TestRASPSQLi(t *testing.T) {
 		}
 		w.Write([]byte("Hello World!\n"))
 	})
+	mux.HandleFunc("/prepare", func(w http.ResponseWriter, r *http.Request) {
+		// Subsequent spans inherit their parent from context.
+		q := r.URL.Query().Get("query")
+		stmt, err := db.PrepareContext(r.Context(), q)
+		if events.IsSecurityError(err) {
+			return
+		}
+		if err == nil {
+			stmt.Close()
+		}
+		w.Write([]byte("Hello World!\n"))
+	})
 	srv := httptest.NewServer(mux)
 	defer srv.Close()
 
@@ -127,7 +139,7 @@ func TestRASPSQLi(t *testing.T) {
 			err:   &events.BlockingSecurityEvent{},
 		},
 	} {
-		for _, endpoint := range []string{"/query", "/exec"} {
+		for _, endpoint := range []string{"/query", "/exec", "/prepare"} {
 			t.Run(name+endpoint, func(t *testing.T) {
 				// Start tracer and appsec
 				mt := mocktracer.Start()
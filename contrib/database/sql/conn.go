@@ -9,6 +9,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/appsec/events"
@@ -52,6 +53,9 @@ const (
 type TracedConn struct {
 	driver.Conn
 	*traceParams
+
+	// sessionContextSet reports whether a trace context is set on the session of the connection.
+	sessionContextSet bool
 }
 
 // checkQuerySafety runs ASM RASP SQLi checks on the query to verify if it can safely be run.
@@ -119,7 +123,9 @@ func (tc *TracedConn) PrepareContext(ctx context.Context, query string) (stmt dr
 	if connPrepareCtx, ok := tc.Conn.(driver.ConnPrepareContext); ok {
 		ctx, end := startTraceTask(ctx, QueryTypePrepare)
 		defer end()
-		stmt, err := connPrepareCtx.PrepareContext(ctx, cquery)
+		if err = checkQuerySecurity(ctx, query, tc.driverName); !events.IsSecurityError(err) {
+			stmt, err = connPrepareCtx.PrepareContext(ctx, cquery)
+		}
 		tc.tryTrace(ctx, QueryTypePrepare, query, start, err, append(withDBMTraceInjectedTag(mode), tracer.WithSpanID(spanID))...)
 		if err != nil {
 			return nil, err
@@ -128,7 +134,9 @@ func (tc *TracedConn) PrepareContext(ctx context.Context, query string) (stmt dr
 	}
 	ctx, end := startTraceTask(ctx, QueryTypePrepare)
 	defer end()
-	stmt, err = tc.Prepare(cquery)
+	if err = checkQuerySecurity(ctx, query, tc.driverName); !events.IsSecurityError(err) {
+		stmt, err = tc.Prepare(cquery)
+	}
 	tc.tryTrace(ctx, QueryTypePrepare, query, start, err, append(withDBMTraceInjectedTag(mode), tracer.WithSpanID(spanID))...)
 	if err != nil {
 		return nil, err
@@ -234,6 +242,9 @@ var _ driver.SessionResetter = (*TracedConn)(nil)
 
 // ResetSession implements driver.SessionResetter
 func (tc *TracedConn) ResetSession(ctx context.Context) error {
+	// the connection was put back in the pool, and is being acquired again
+	tc.acquired.Store(true)
+	tc.clearSessionContext(ctx)
 	if resetter, ok := tc.Conn.(driver.SessionResetter); ok {
 		return resetter.ResetSession(ctx)
 	}
@@ -246,6 +257,10 @@ type traceParams struct {
 	cfg        *config
 	driverName string
 	meta       map[string]string
+	waits      *poolWaits // nil unless DB stats are enabled
+	// acquired reports whether the connection was acquired from the pool since its
+	// last traced operation.
+	acquired atomic.Bool
 }
 
 type contextKey int
@@ -283,7 +298,8 @@ func (tc *TracedConn) providedPeerService(ctx context.Context) string {
 
 // injectComments returns the query with SQL comments injected according to the comment injection mode along
 // with a span ID injected into SQL comments. The returned span ID should be used when the SQL span is created
-// following the traced database call.
+// following the traced database call. For databases which can't carry the trace context in SQL comments, it is
+// set on the session of the connection instead.
 func (tc *TracedConn) injectComments(ctx context.Context, query string, mode tracer.DBMPropagationMode) (cquery string, spanID uint64) {
 	// The sql span only gets created after the call to the database because we need to be able to skip spans
 	// when a driver returns driver.ErrSkip. In order to work with those constraints, a new span id is generated and
@@ -294,11 +310,18 @@ func (tc *TracedConn) injectComments(ctx context.Context, query string, mode tra
 		spanCtx = span.Context()
 	}
 
-	carrier := tracer.SQLCommentCarrier{Query: query, Mode: mode, DBServiceName: tc.cfg.serviceName, PeerDBHostname: tc.meta[ext.TargetHost], PeerDBName: tc.meta[ext.DBName], PeerService: tc.providedPeerService(ctx)}
+	commentMode := mode
+	if mode == tracer.DBMPropagationModeFull && tc.cfg.dbmSessionDriver != nil {
+		commentMode = tracer.DBMPropagationModeService
+	}
+	carrier := tracer.SQLCommentCarrier{Query: query, Mode: commentMode, DBServiceName: tc.cfg.serviceName, PeerDBHostname: tc.meta[ext.TargetHost], PeerDBName: tc.meta[ext.DBName], PeerService: tc.providedPeerService(ctx)}
 	if err := carrier.Inject(spanCtx); err != nil {
 		// this should never happen
 		instr.Logger().Warn("contrib/database/sql: failed to inject query comments: %v", err)
 	}
+	if commentMode != mode {
+		tc.setSessionContext(ctx, spanCtx, carrier.SpanID)
+	}
 	return carrier.Query, carrier.SpanID
 }
 
@@ -360,6 +383,11 @@ func (tp *traceParams) tryTrace(ctx context.Context, qtype QueryType, query stri
 			span.SetTag(k, v)
 		}
 	}
+	if tp.waits != nil && tp.acquired.Swap(false) {
+		if wait, ok := tp.waits.take(); ok {
+			span.SetTag(keyPoolWaitDuration, wait.Nanoseconds())
+		}
+	}
 	if err != nil && !events.IsSecurityError(err) && (tp.cfg.errCheck == nil || tp.cfg.errCheck(err)) {
 		span.SetTag(ext.Error, err)
 	}
@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// dbmDriver describes how the trace context of queries is propagated to Database Monitoring
+// for the drivers of a database.
+type dbmDriver struct {
+	// dbSystem is the name of the database, as reported in logs.
+	dbSystem string
+	// pkgs, dsnPrefixes and names identify the drivers of the database by their package path,
+	// the prefix of their DSN or the name they were registered with.
+	pkgs        []string
+	dsnPrefixes []string
+	names       []string
+	// sessionContext returns the statement setting the trace context of the next query on the
+	// session of the connection, for databases where SQL comments can't carry it, and
+	// clearSessionContext is the statement clearing it. If nil, the full propagation mode isn't
+	// supported and is downgraded to the service mode.
+	sessionContext      func(traceID [16]byte, spanID uint64, sampled bool) string
+	clearSessionContext string
+}
+
+// dbmDrivers is the registry of the drivers which can't propagate the trace context in SQL comments.
+// The drivers of other databases propagate it in the traceparent comment.
+var dbmDrivers = []dbmDriver{
+	{
+		dbSystem:            "SQL Server",
+		pkgs:                []string{"github.com/denisenkom/go-mssqldb", "github.com/microsoft/go-mssqldb"},
+		dsnPrefixes:         []string{"sqlserver://"},
+		names:               []string{"sqlserver", "mssql", "azuresql"},
+		sessionContext:      sqlServerSessionContext,
+		clearSessionContext: "SET CONTEXT_INFO 0x",
+	},
+	{
+		dbSystem:            "Oracle",
+		pkgs:                []string{"github.com/sijms/go-ora", "github.com/godror/godror"},
+		dsnPrefixes:         []string{"oracle://"},
+		names:               []string{"oracle", "godror"},
+		sessionContext:      oracleSessionContext,
+		clearSessionContext: "BEGIN DBMS_APPLICATION_INFO.SET_ACTION(NULL); END;",
+	},
+}
+
+// lookupDBMDriver returns the entry of the dbmDrivers registry matching the given driver, if any.
+func lookupDBMDriver(driverName string, driver driver.Driver, dsn string) (*dbmDriver, bool) {
+	// check if the driver package path is one of the registered ones.
+	if tp := reflect.TypeOf(driver); tp != nil && (tp.Kind() == reflect.Pointer || tp.Kind() == reflect.Struct) {
+		pkgPath := ""
+		switch tp.Kind() {
+		case reflect.Pointer:
+			pkgPath = tp.Elem().PkgPath()
+		case reflect.Struct:
+			pkgPath = tp.PkgPath()
+		}
+		for i := range dbmDrivers {
+			for _, pkg := range dbmDrivers[i].pkgs {
+				// compare without the host to make it work for vendoring.
+				// also, compare only the prefix to make the comparison work when using major versions
+				// of the libraries or subpackages.
+				host, pkgName, _ := strings.Cut(pkg, "/")
+				if strings.HasPrefix(strings.TrimPrefix(pkgPath, host+"/"), pkgName) {
+					return &dbmDrivers[i], true
+				}
+			}
+		}
+	}
+
+	// check the DSN if provided.
+	if dsn != "" {
+		for i := range dbmDrivers {
+			for _, prefix := range dbmDrivers[i].dsnPrefixes {
+				if strings.HasPrefix(dsn, prefix) {
+					return &dbmDrivers[i], true
+				}
+			}
+		}
+	}
+
+	// lastly, check if the registered driver name is one of the registered ones.
+	for i := range dbmDrivers {
+		for _, name := range dbmDrivers[i].names {
+			if name == driverName {
+				return &dbmDrivers[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// sqlServerSessionContext sets the trace context in the CONTEXT_INFO of the session. It holds a
+// byte with the version in the high nibble and the sampling decision in the lowest bit, followed
+// by the span ID and the trace ID.
+func sqlServerSessionContext(traceID [16]byte, spanID uint64, sampled bool) string {
+	var b [25]byte
+	if sampled {
+		b[0] = 1
+	}
+	binary.BigEndian.PutUint64(b[1:9], spanID)
+	copy(b[9:], traceID[:])
+	return "SET CONTEXT_INFO 0x" + hex.EncodeToString(b[:])
+}
+
+// oracleSessionContext sets the trace context as a traceparent in the action of the session,
+// reported in V$SESSION.ACTION.
+func oracleSessionContext(traceID [16]byte, spanID uint64, sampled bool) string {
+	var flags int
+	if sampled {
+		flags = 1
+	}
+	return fmt.Sprintf("BEGIN DBMS_APPLICATION_INFO.SET_ACTION('00-%x-%016x-%02x'); END;", traceID, spanID, flags)
+}
+
+// setSessionContext sets the trace context of the query with the given span ID on the session of
+// the connection, when the database doesn't support carrying it in SQL comments.
+func (tc *TracedConn) setSessionContext(ctx context.Context, spanCtx *tracer.SpanContext, spanID uint64) {
+	d := tc.cfg.dbmSessionDriver
+	if d == nil {
+		return
+	}
+	var (
+		traceID [16]byte
+		sampled bool
+	)
+	if spanCtx != nil {
+		traceID = spanCtx.TraceIDBytes()
+		if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+			sampled = true
+		}
+	} else {
+		// the query span is the root of its trace, as done in SQL comments
+		binary.BigEndian.PutUint64(traceID[8:], spanID)
+	}
+	if err := tc.execSession(ctx, d.sessionContext(traceID, spanID, sampled)); err != nil {
+		instr.Logger().Debug("contrib/database/sql: failed to set the trace context on the session: %v", err)
+		return
+	}
+	tc.sessionContextSet = true
+}
+
+// clearSessionContext clears the trace context set on the session of the connection by
+// setSessionContext, so that it isn't reported for the queries of the next user of the
+// connection.
+func (tc *TracedConn) clearSessionContext(ctx context.Context) {
+	d := tc.cfg.dbmSessionDriver
+	if d == nil || !tc.sessionContextSet {
+		return
+	}
+	if err := tc.execSession(ctx, d.clearSessionContext); err != nil {
+		instr.Logger().Debug("contrib/database/sql: failed to clear the trace context of the session: %v", err)
+		return
+	}
+	tc.sessionContextSet = false
+}
+
+// execSession executes stmt on the connection, without tracing it.
+func (tc *TracedConn) execSession(ctx context.Context, stmt string) error {
+	var err error
+	switch conn := tc.Conn.(type) {
+	case driver.ExecerContext:
+		_, err = conn.ExecContext(ctx, stmt, nil)
+	case driver.Execer:
+		_, err = conn.Exec(stmt, nil)
+	default:
+		return driver.ErrSkip
+	}
+	return err
+}
@@ -7,8 +7,11 @@ package sql // import "github.com/DataDog/dd-trace-go/contrib/database/sql/v2"
 
 import (
 	"database/sql"
+	"sync"
 	"time"
 
+	sqlinternal "github.com/DataDog/dd-trace-go/contrib/database/sql/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
 )
 
@@ -27,11 +30,17 @@ const (
 	MaxLifetimeClosed  = tracerPrefix + "sql.db.connections.closed.max_lifetime"
 )
 
+// keyPoolWaitDuration holds the average duration, in nanoseconds, spent waiting for a
+// connection from the pool, on the spans of the queries made on connections acquired
+// from the pool after a wait.
+const keyPoolWaitDuration = "sql.db.pool.wait_duration"
+
 var interval = 10 * time.Second
 
-// pollDBStats calls (*DB).Stats on the db at a predetermined interval. It pushes the DBStats off to the statsd client.
-// the caller should always ensure that db & statsd are non-nil
-func pollDBStats(statsd instrumentation.StatsdClient, db *sql.DB, stop chan struct{}) {
+// pollDBStats calls (*DB).Stats on the db at a predetermined interval. It pushes the DBStats off to the statsd client,
+// tagged with tags, and records the waits for a connection in waits, if not nil. The caller should always ensure that
+// db & statsd are non-nil.
+func pollDBStats(statsd instrumentation.StatsdClient, db *sql.DB, tags []string, waits *poolWaits, stop chan struct{}) {
 	instr.Logger().Debug("DB stats will be gathered and sent every %v.", interval)
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -40,15 +49,18 @@ func pollDBStats(statsd instrumentation.StatsdClient, db *sql.DB, stop chan stru
 		case <-ticker.C:
 			instr.Logger().Debug("Reporting DB.Stats metrics...")
 			stat := db.Stats()
-			statsd.Gauge(MaxOpenConnections, float64(stat.MaxOpenConnections), []string{}, 1)
-			statsd.Gauge(OpenConnections, float64(stat.OpenConnections), []string{}, 1)
-			statsd.Gauge(InUse, float64(stat.InUse), []string{}, 1)
-			statsd.Gauge(Idle, float64(stat.Idle), []string{}, 1)
-			statsd.Gauge(WaitCount, float64(stat.WaitCount), []string{}, 1)
-			statsd.Timing(WaitDuration, stat.WaitDuration, []string{}, 1)
-			statsd.Gauge(MaxIdleClosed, float64(stat.MaxIdleClosed), []string{}, 1)
-			statsd.Gauge(MaxIdleTimeClosed, float64(stat.MaxIdleTimeClosed), []string{}, 1)
-			statsd.Gauge(MaxLifetimeClosed, float64(stat.MaxLifetimeClosed), []string{}, 1)
+			statsd.Gauge(MaxOpenConnections, float64(stat.MaxOpenConnections), tags, 1)
+			statsd.Gauge(OpenConnections, float64(stat.OpenConnections), tags, 1)
+			statsd.Gauge(InUse, float64(stat.InUse), tags, 1)
+			statsd.Gauge(Idle, float64(stat.Idle), tags, 1)
+			statsd.Gauge(WaitCount, float64(stat.WaitCount), tags, 1)
+			statsd.Timing(WaitDuration, stat.WaitDuration, tags, 1)
+			statsd.Gauge(MaxIdleClosed, float64(stat.MaxIdleClosed), tags, 1)
+			statsd.Gauge(MaxIdleTimeClosed, float64(stat.MaxIdleTimeClosed), tags, 1)
+			statsd.Gauge(MaxLifetimeClosed, float64(stat.MaxLifetimeClosed), tags, 1)
+			if waits != nil {
+				waits.sample(stat)
+			}
 		case <-stop:
 			return
 		}
@@ -67,3 +79,55 @@ func (c *config) statsdExtraTags() []string {
 	}
 	return tags
 }
+
+// dbStatsTags returns the tags identifying the database of the given driver and DSN
+// in the DBStats metrics.
+func dbStatsTags(driverName, dsn string) []string {
+	dbSystem, _ := normalizeDBSystem(driverName)
+	tags := []string{ext.DBSystem + ":" + dbSystem}
+	if dsn == "" {
+		return tags
+	}
+	if meta, _ := sqlinternal.ParseDSN(driverName, dsn); meta[ext.DBName] != "" {
+		tags = append(tags, ext.DBName+":"+meta[ext.DBName])
+	}
+	return tags
+}
+
+// poolWaits keeps track of the waits for a connection from the pool of a *sql.DB, sampled
+// by pollDBStats, so that they can be reported on the spans of the queries made on the
+// connections acquired from the pool. Since the pool doesn't tell which requests waited,
+// each wait is reported once, on the first span of a connection acquired after it was
+// sampled, and the result is approximate.
+type poolWaits struct {
+	mu       sync.Mutex
+	count    int64         // number of waits sampled so far
+	duration time.Duration // total duration of the waits sampled so far
+	pending  int64         // number of sampled waits not reported yet
+	average  time.Duration // average duration of the waits sampled last
+}
+
+// sample records the waits for a connection which ended since the previous sample.
+func (p *poolWaits) sample(stats sql.DBStats) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	count, duration := stats.WaitCount-p.count, stats.WaitDuration-p.duration
+	p.count, p.duration = stats.WaitCount, stats.WaitDuration
+	if count <= 0 || duration <= 0 {
+		return
+	}
+	p.pending = count
+	p.average = duration / time.Duration(count)
+}
+
+// take returns the average duration of the sampled waits, and whether one of them was
+// still to be reported, in which case it is now considered reported.
+func (p *poolWaits) take() (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending == 0 {
+		return 0, false
+	}
+	p.pending--
+	return p.average, true
+}
@@ -9,8 +9,6 @@ import (
 	"database/sql/driver"
 	"math"
 	"os"
-	"reflect"
-	"strings"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -27,6 +25,8 @@ type config struct {
 	errCheck           func(err error) bool
 	tags               map[string]interface{}
 	dbmPropagationMode tracer.DBMPropagationMode
+	dbmSessionContext  bool
+	dbmSessionDriver   *dbmDriver
 	dbStats            bool
 	statsdClient       instrumentation.StatsdClient
 }
@@ -52,75 +52,25 @@ func (c *config) checkDBMPropagation(driverName string, driver driver.Driver, ds
 		if dsn == "" {
 			dsn = c.dsn
 		}
-		if dbSystem, ok := dbmFullModeUnsupported(driverName, driver, dsn); ok {
-			instr.Logger().Warn("Using DBM_PROPAGATION_MODE in 'full' mode is not supported for %s, downgrading to 'service' mode. "+
-				"See https://docs.datadoghq.com/database_monitoring/connect_dbm_and_apm/ for more info.",
-				dbSystem,
-			)
-			c.dbmPropagationMode = tracer.DBMPropagationModeService
+		d, ok := lookupDBMDriver(driverName, driver, dsn)
+		if !ok {
+			return
 		}
-	}
-}
-
-func dbmFullModeUnsupported(driverName string, driver driver.Driver, dsn string) (string, bool) {
-	const (
-		sqlServer = "SQL Server"
-		oracle    = "Oracle"
-	)
-	// check if the driver package path is one of the unsupported ones.
-	if tp := reflect.TypeOf(driver); tp != nil && (tp.Kind() == reflect.Pointer || tp.Kind() == reflect.Struct) {
-		pkgPath := ""
-		switch tp.Kind() {
-		case reflect.Pointer:
-			pkgPath = tp.Elem().PkgPath()
-		case reflect.Struct:
-			pkgPath = tp.PkgPath()
-		}
-		driverPkgs := [][3]string{
-			{"github.com", "denisenkom/go-mssqldb", sqlServer},
-			{"github.com", "microsoft/go-mssqldb", sqlServer},
-			{"github.com", "sijms/go-ora", oracle},
-		}
-		for _, dp := range driverPkgs {
-			prefix, pkgName, dbSystem := dp[0], dp[1], dp[2]
-
-			// compare without the prefix to make it work for vendoring.
-			// also, compare only the prefix to make the comparison work when using major versions
-			// of the libraries or subpackages.
-			if strings.HasPrefix(strings.TrimPrefix(pkgPath, prefix+"/"), pkgName) {
-				return dbSystem, true
-			}
-		}
-	}
-
-	// check the DSN if provided.
-	if dsn != "" {
-		prefixes := [][2]string{
-			{"oracle://", oracle},
-			{"sqlserver://", sqlServer},
+		if d.sessionContext != nil && c.dbmSessionContext {
+			// the trace context is set on the session, SQL comments only carry the service
+			c.dbmSessionDriver = d
+			return
 		}
-		for _, pr := range prefixes {
-			prefix, dbSystem := pr[0], pr[1]
-			if strings.HasPrefix(dsn, prefix) {
-				return dbSystem, true
-			}
+		var hint string
+		if d.sessionContext != nil {
+			hint = " without WithDBMSessionContext"
 		}
+		instr.Logger().Warn("Using DBM_PROPAGATION_MODE in 'full' mode is not supported for %s%s, downgrading to 'service' mode. "+
+			"See https://docs.datadoghq.com/database_monitoring/connect_dbm_and_apm/ for more info.",
+			d.dbSystem, hint,
+		)
+		c.dbmPropagationMode = tracer.DBMPropagationModeService
 	}
-
-	// lastly, check if the registered driver name is one of the unsupported ones.
-	driverNames := [][2]string{
-		{"sqlserver", sqlServer},
-		{"mssql", sqlServer},
-		{"azuresql", sqlServer},
-		{"oracle", oracle},
-	}
-	for _, dn := range driverNames {
-		name, dbSystem := dn[0], dn[1]
-		if name == driverName {
-			return dbSystem, true
-		}
-	}
-	return "", false
 }
 
 // Option describes options for the database/sql integration.
@@ -277,9 +227,29 @@ func WithDBMPropagation(mode tracer.DBMPropagationMode) OptionFn {
 	}
 }
 
+// WithDBMSessionContext propagates the trace context of the queries to Database Monitoring for
+// SQL Server and Oracle, whose queries can't carry it in SQL comments, when the full propagation
+// mode is set with WithDBMPropagation. Without it, the full mode is downgraded to the service mode
+// for these databases.
+//
+// The trace context is set on the session of the connection, in CONTEXT_INFO for SQL Server and
+// in the action of the session for Oracle, with an extra statement sent before each traced query,
+// which costs a round trip to the database. It is cleared when the connection is returned to the
+// pool, but the queries not going through ExecContext or QueryContext on the connection, such as
+// prepared statements, are reported with the context of the last traced query.
+func WithDBMSessionContext() OptionFn {
+	return func(cfg *config) {
+		cfg.dbmSessionContext = true
+	}
+}
+
 // WithDBStats enables polling of DBStats metrics
 // ref: https://pkg.go.dev/database/sql#DBStats
-// These metrics are submitted to Datadog and are not billed as custom metrics
+// These metrics are submitted to Datadog and are not billed as custom metrics.
+// They are tagged with the db.system and, when found in the DSN, the db.name.
+// The waits for a connection from the pool polled with them are also reported on the
+// first span of the connections acquired from the pool afterwards, one span per wait,
+// with the average wait duration in the "sql.db.pool.wait_duration" metric, in nanoseconds.
 func WithDBStats() OptionFn {
 	return func(cfg *config) {
 		cfg.dbStats = true
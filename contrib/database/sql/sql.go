@@ -155,6 +155,7 @@ type tracedConnector struct {
 	driverName string
 	cfg        *config
 	dbClose    chan struct{}
+	waits      *poolWaits // nil unless DB stats are enabled
 }
 
 func (t *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
@@ -168,6 +169,7 @@ func (t *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
 	tp := &traceParams{
 		driverName: t.driverName,
 		cfg:        t.cfg,
+		waits:      t.waits,
 	}
 	if dsn != "" {
 		tp.meta, _ = sqlinternal.ParseDSN(t.driverName, dsn)
@@ -180,7 +182,8 @@ func (t *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &TracedConn{conn, tp}, err
+	tp.acquired.Store(true)
+	return &TracedConn{Conn: conn, traceParams: tp}, err
 }
 
 func (t *tracedConnector) Driver() driver.Driver {
@@ -233,9 +236,15 @@ func OpenDB(c driver.Connector, opts ...Option) *sql.DB {
 		cfg:        cfg,
 		dbClose:    make(chan struct{}),
 	}
-	db := sql.OpenDB(tc)
 	if cfg.dbStats && cfg.statsdClient != nil {
-		go pollDBStats(cfg.statsdClient, db, tc.dbClose)
+		tc.waits = new(poolWaits)
+	}
+	db := sql.OpenDB(tc)
+	if tc.waits != nil {
+		if dsn == "" {
+			dsn = cfg.dsn
+		}
+		go pollDBStats(cfg.statsdClient, db, dbStatsTags(driverName, dsn), tc.waits, tc.dbClose)
 	}
 	return db
 }
@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ristretto_test
+
+import (
+	"context"
+	"log"
+
+	ristrettotrace "github.com/DataDog/dd-trace-go/contrib/dgraph-io/ristretto/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+
+	"github.com/dgraph-io/ristretto"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	cache, err := ristrettotrace.NewCache(&ristretto.Config{
+		NumCounters: 1e7,
+		MaxCost:     1 << 30,
+		BufferItems: 64,
+	}, cachetrace.WithName("users"), cachetrace.WithMetrics(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cache.Close()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "web.request")
+	defer span.Finish()
+
+	// The lookup is recorded as a "cache.hit" or "cache.miss" event of the span.
+	if _, ok := cache.Get(ctx, "user:42"); !ok {
+		cache.Set("user:42", "Jane", 1)
+	}
+}
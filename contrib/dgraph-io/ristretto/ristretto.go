@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package ristretto provides functions to trace the dgraph-io/ristretto package (https://github.com/dgraph-io/ristretto).
+//
+// The hits and misses of the lookups made with the Get method of a Cache are recorded as span
+// events on the span found in their context, so that the effectiveness of the cache can be
+// analyzed per endpoint. Hits, misses and evictions can also be sent as statsd metrics.
+package ristretto // import "github.com/DataDog/dd-trace-go/contrib/dgraph-io/ristretto/v2"
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+
+	"github.com/dgraph-io/ristretto"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageDgraphIoRistretto)
+}
+
+// Option describes options for the ristretto integration, set with the options of the
+// cachetrace package, e.g. cachetrace.WithName.
+type Option = cachetrace.Option
+
+// Cache is a ristretto.Cache recording the hits and misses of its lookups.
+type Cache struct {
+	*ristretto.Cache
+	rec *cachetrace.Recorder
+}
+
+// NewCache returns a new Cache with the given configuration. The OnEvict and OnReject
+// callbacks of the configuration are wrapped to count the evictions and rejections when the
+// metrics are enabled, the given configuration isn't modified.
+func NewCache(config *ristretto.Config, opts ...Option) (*Cache, error) {
+	cfg := cachetrace.NewConfig("ristretto", "", opts...)
+	instr.Logger().Debug("contrib/dgraph-io/ristretto: Configuring Cache: %#v", cfg)
+	rec := cachetrace.NewRecorder(instr, cfg)
+	rcfg := *config
+	if rec.MetricsEnabled() {
+		onEvict, onReject := rcfg.OnEvict, rcfg.OnReject
+		rcfg.OnEvict = func(item *ristretto.Item) {
+			reason := "capacity"
+			if !item.Expiration.IsZero() && !item.Expiration.After(time.Now()) {
+				reason = "expired"
+			}
+			rec.Evicted(reason, 1)
+			if onEvict != nil {
+				onEvict(item)
+			}
+		}
+		rcfg.OnReject = func(item *ristretto.Item) {
+			rec.Evicted("rejected", 1)
+			if onReject != nil {
+				onReject(item)
+			}
+		}
+	}
+	cache, err := ristretto.NewCache(&rcfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{Cache: cache, rec: rec}, nil
+}
+
+// Get returns the value of key and whether it was found, recording the lookup as a hit or a
+// miss on the span found in ctx.
+func (c *Cache) Get(ctx context.Context, key interface{}) (interface{}, bool) {
+	value, ok := c.Cache.Get(key)
+	if ok {
+		c.rec.Hit(ctx)
+	} else {
+		c.rec.Miss(ctx)
+	}
+	return value, ok
+}
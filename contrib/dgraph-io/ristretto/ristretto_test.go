@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ristretto
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+
+	"github.com/dgraph-io/ristretto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withStatsdClient(s instrumentation.StatsdClient) Option {
+	return func(c *cachetrace.Config) {
+		c.Metrics = true
+		c.Statsd = s
+	}
+}
+
+func newTestCache(t *testing.T, opts ...Option) *Cache {
+	cache, err := NewCache(&ristretto.Config{
+		NumCounters:        100,
+		MaxCost:            10,
+		BufferItems:        64,
+		IgnoreInternalCost: true,
+	}, opts...)
+	require.NoError(t, err)
+	t.Cleanup(cache.Close)
+	return cache
+}
+
+func TestGet(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	statsd := testutils.NewMockStatsdClient()
+	cache := newTestCache(t, cachetrace.WithName("users"), withStatsdClient(statsd))
+	require.True(t, cache.Set("a", 1, 1))
+	cache.Wait()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "request")
+	v, ok := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	_, ok = cache.Get(ctx, "b")
+	assert.False(t, ok)
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, cachetrace.EventHit, events[0].Name)
+	assert.Equal(t, cachetrace.EventMiss, events[1].Name)
+	events[0].AssertAttributes(t, map[string]any{cachetrace.AttributeName: "users", cachetrace.AttributeSystem: "ristretto"})
+
+	counts := statsd.Counts()
+	assert.Equal(t, int64(1), counts[cachetrace.MetricHits])
+	assert.Equal(t, int64(1), counts[cachetrace.MetricMisses])
+}
+
+func TestEvictions(t *testing.T) {
+	statsd := testutils.NewMockStatsdClient()
+	var evicted, rejected atomic.Int64
+	cache, err := NewCache(&ristretto.Config{
+		NumCounters: 100,
+		MaxCost:     1,
+		BufferItems: 64,
+		OnEvict:     func(*ristretto.Item) { evicted.Add(1) },
+		OnReject:    func(*ristretto.Item) { rejected.Add(1) },
+	}, withStatsdClient(statsd))
+	require.NoError(t, err)
+	defer cache.Close()
+
+	// items are evicted or rejected to make room for new ones
+	for i := 0; i < 10; i++ {
+		cache.Set(i, i, 1)
+		cache.Wait()
+	}
+	assert.NotZero(t, evicted.Load()+rejected.Load())
+	assert.Equal(t, evicted.Load()+rejected.Load(), statsd.Counts()[cachetrace.MetricEvictions])
+}
+
+func TestSpanEventsDisabled(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	cache := newTestCache(t, cachetrace.WithSpanEvents(false))
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "request")
+	cache.Get(ctx, "a")
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Empty(t, spans[0].Events())
+}
@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package elastic
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+const (
+	// tagBulkItems is the number of items of a bulk request.
+	tagBulkItems = "elasticsearch.bulk.items"
+	// tagBulkFailedItems is the number of items of a bulk request which failed.
+	tagBulkFailedItems = "elasticsearch.bulk.failed_items"
+)
+
+// isBulk reports whether the request with the given path is a bulk request.
+func isBulk(path string) bool {
+	return path == "/_bulk" || strings.HasSuffix(path, "/_bulk")
+}
+
+// bulkResponse is the part of the response of a bulk request holding the results of its items.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	// Items holds the result of each item under its action, such as "index" or "delete".
+	Items []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// tagBulkResponse tags span with the number of items of the successful bulk request of res,
+// and the number of them which failed, marking the span as errored if any did. The body of res
+// is read and replaced with a reader holding the same content.
+func tagBulkResponse(span *tracer.Span, res *http.Response) {
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = &bufferedBody{Reader: bytes.NewReader(body), err: err}
+	if err != nil {
+		return
+	}
+	var r io.Reader = bytes.NewReader(body)
+	if res.Header.Get("Content-Encoding") == "gzip" {
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return
+		}
+		defer gzr.Close()
+		r = gzr
+	}
+	var bulk bulkResponse
+	if err := json.NewDecoder(r).Decode(&bulk); err != nil {
+		return
+	}
+	span.SetTag(tagBulkItems, len(bulk.Items))
+	if !bulk.Errors {
+		span.SetTag(tagBulkFailedItems, 0)
+		return
+	}
+	failed := 0
+	var firstErr string
+	for _, item := range bulk.Items {
+		for _, result := range item {
+			if result.Error == nil {
+				continue
+			}
+			if failed == 0 {
+				firstErr = fmt.Sprintf("%s: %s", result.Error.Type, result.Error.Reason)
+			}
+			failed++
+		}
+	}
+	span.SetTag(tagBulkFailedItems, failed)
+	if failed > 0 {
+		span.SetTag(ext.Error, fmt.Errorf("%d of %d bulk items failed, first error: %s", failed, len(bulk.Items), firstErr))
+	}
+}
+
+// bufferedBody is a response body read in memory, returning the error which interrupted its
+// reading, if any, once its content is consumed.
+type bufferedBody struct {
+	*bytes.Reader
+	err error
+}
+
+func (b *bufferedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF && b.err != nil {
+		err = b.err
+	}
+	return n, err
+}
+
+func (*bufferedBody) Close() error {
+	return nil
+}
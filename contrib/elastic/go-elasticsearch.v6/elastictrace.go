@@ -17,6 +17,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -73,6 +74,17 @@ func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	if !math.IsNaN(t.config.analyticsRate) {
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, t.config.analyticsRate))
 	}
+	n := 0
+	attempts, trackAttempts := attemptsFromContext(req.Context())
+	if trackAttempts {
+		if prev, ok := attempts.previous(req); ok {
+			n = prev.n + 1
+			opts = append(opts,
+				tracer.Tag(tagRetryAttempt, n),
+				tracer.Tag(tagRetryBackoff, time.Since(prev.end).Milliseconds()),
+			)
+		}
+	}
 	span, _ := tracer.StartSpanFromContext(req.Context(), t.config.operationName, opts...)
 	defer span.Finish()
 
@@ -84,6 +96,9 @@ func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	req.Body = rc
 	// process using the standard transport
 	res, err := t.config.transport.RoundTrip(req)
+	if trackAttempts {
+		attempts.done(req, n, isRetryable(res, err))
+	}
 	if err != nil {
 		// roundtrip error
 		span.SetTag(ext.Error, err)
@@ -95,6 +110,8 @@ func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 		span.SetTag(ext.Error, errors.New(snip))
 		res.Body = rc
+	} else if t.config.bulkItems && isBulk(url) {
+		tagBulkResponse(span, res)
 	}
 	if res != nil {
 		span.SetTag(ext.HTTPCode, strconv.Itoa(res.StatusCode))
@@ -7,13 +7,22 @@ package elastic
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
 )
 
 const debug = false
@@ -136,3 +145,147 @@ func TestPeek(t *testing.T) {
 		}
 	}
 }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func respond(status int, body string) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}
+}
+
+func TestBulkItems(t *testing.T) {
+	const failedBody = `{"took":3,"errors":true,"items":[` +
+		`{"index":{"_index":"logs","status":201}},` +
+		`{"index":{"_index":"logs","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}},` +
+		`{"delete":{"_index":"logs","status":404,"error":{"type":"document_missing_exception","reason":"missing"}}}]}`
+
+	for _, tt := range []struct {
+		name      string
+		path      string
+		body      string
+		opts      []ClientOption
+		items     any
+		failed    any
+		wantError string
+	}{
+		{
+			name:   "success",
+			path:   "/_bulk",
+			body:   `{"took":3,"errors":false,"items":[{"index":{"status":201}},{"index":{"status":201}}]}`,
+			opts:   []ClientOption{WithBulkItems(true)},
+			items:  float64(2),
+			failed: float64(0),
+		},
+		{
+			name:      "failed items",
+			path:      "/logs/_bulk",
+			body:      failedBody,
+			opts:      []ClientOption{WithBulkItems(true)},
+			items:     float64(3),
+			failed:    float64(2),
+			wantError: "2 of 3 bulk items failed, first error: es_rejected_execution_exception: rejected",
+		},
+		{
+			name: "default",
+			path: "/_bulk",
+			body: failedBody,
+		},
+		{
+			name: "disabled",
+			path: "/_bulk",
+			body: failedBody,
+			opts: []ClientOption{WithBulkItems(false)},
+		},
+		{
+			name: "not bulk",
+			path: "/logs/_search",
+			body: failedBody,
+			opts: []ClientOption{WithBulkItems(true)},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			rt := NewRoundTripper(append(tt.opts, WithTransport(respond(http.StatusOK, tt.body)))...)
+			req := httptest.NewRequest("POST", "http://127.0.0.1:9200"+tt.path, strings.NewReader("{}"))
+			res, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			s := spans[0]
+			assert.Equal(t, tt.items, s.Tag(tagBulkItems))
+			assert.Equal(t, tt.failed, s.Tag(tagBulkFailedItems))
+			if tt.wantError != "" {
+				assert.Equal(t, tt.wantError, s.Tag(ext.ErrorMsg))
+			} else {
+				assert.Nil(t, s.Tag(ext.ErrorMsg))
+			}
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	statuses := []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}
+	rt := NewRoundTripper(WithTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[0]
+		statuses = statuses[1:]
+		return respond(status, "{}")(req)
+	})))
+	ctx := ContextWithRetryTracking(context.Background())
+	req := httptest.NewRequest("GET", "http://127.0.0.1:9200/logs/_doc/1", nil).WithContext(ctx)
+	for range 3 {
+		res, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		time.Sleep(time.Millisecond)
+	}
+	// a new request isn't a retry
+	res, err := rt.RoundTrip(httptest.NewRequest("GET", "http://127.0.0.1:9200/logs/_doc/1", nil).WithContext(ctx))
+	require.NoError(t, err)
+	res.Body.Close()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 4)
+	assert.Nil(t, spans[0].Tag(tagRetryAttempt))
+	assert.Equal(t, float64(1), spans[1].Tag(tagRetryAttempt))
+	assert.NotNil(t, spans[1].Tag(tagRetryBackoff))
+	assert.Equal(t, float64(2), spans[2].Tag(tagRetryAttempt))
+	assert.Nil(t, spans[3].Tag(tagRetryAttempt))
+}
+
+func TestRetriesUntracked(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	rt := NewRoundTripper(WithTransport(respond(http.StatusServiceUnavailable, "{}")))
+	req := httptest.NewRequest("GET", "http://127.0.0.1:9200/logs/_doc/1", nil)
+	for range 2 {
+		res, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+
+	// the attempts are only tracked with ContextWithRetryTracking
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Nil(t, spans[0].Tag(tagRetryAttempt))
+	assert.Nil(t, spans[1].Tag(tagRetryAttempt))
+}
@@ -17,6 +17,7 @@ type clientConfig struct {
 	transport     http.RoundTripper
 	analyticsRate float64
 	resourceNamer func(url, method string) string
+	bulkItems     bool
 }
 
 // ClientOption describes options for the client.
@@ -85,3 +86,13 @@ func WithResourceNamer(namer func(url, method string) string) ClientOptionFn {
 		cfg.resourceNamer = namer
 	}
 }
+
+// WithBulkItems enables or disables the parsing of the responses of bulk requests, disabled
+// by default, to tag their spans with the number of items and failed items, and mark the
+// spans of requests with failed items as errored. The responses of bulk requests are read
+// in memory when enabled.
+func WithBulkItems(enabled bool) ClientOptionFn {
+	return func(cfg *clientConfig) {
+		cfg.bulkItems = enabled
+	}
+}
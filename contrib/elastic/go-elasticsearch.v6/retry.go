@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package elastic
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// tagRetryAttempt is the number of the attempt of a request retried by the client,
+	// starting from 1 for the first retry.
+	tagRetryAttempt = "elasticsearch.retry.attempt"
+	// tagRetryBackoff is the time waited by the client between the end of the previous
+	// attempt of a request and its retry.
+	tagRetryBackoff = "elasticsearch.retry.backoff_ms"
+)
+
+// ContextWithRetryTracking returns a copy of ctx tracking the attempts of the requests
+// made with it, so that the spans of the requests retried by the Elasticsearch client
+// are tagged with their attempt number and the time waited before them:
+//
+//	res, err := es.Search(es.Search.WithContext(elastictrace.ContextWithRetryTracking(ctx)))
+//
+// The attempts are only tracked during the lifetime of the returned context.
+func ContextWithRetryTracking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptsKey{}, &attemptTracker{})
+}
+
+// attemptsKey is the context key of the attemptTracker of the requests.
+type attemptsKey struct{}
+
+// attemptsFromContext returns the attemptTracker of the requests made with ctx, if any.
+func attemptsFromContext(ctx context.Context) (*attemptTracker, bool) {
+	t, ok := ctx.Value(attemptsKey{}).(*attemptTracker)
+	return t, ok
+}
+
+// attempt is the last failed attempt of a request.
+type attempt struct {
+	n   int
+	end time.Time
+}
+
+// attemptTracker remembers the last failed attempt of the requests made with a context.
+// The Elasticsearch clients retry requests by sending the same *http.Request again through
+// the transport, so that the attempts of a request are identified by its address.
+type attemptTracker struct {
+	mu     sync.Mutex
+	failed map[*http.Request]attempt
+}
+
+// previous returns the last failed attempt of req, if any.
+func (t *attemptTracker) previous(req *http.Request) (attempt, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	a, ok := t.failed[req]
+	return a, ok
+}
+
+// done records the end of the attempt n of req, which may be retried if it failed.
+func (t *attemptTracker) done(req *http.Request, n int, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !failed {
+		delete(t.failed, req)
+		return
+	}
+	if t.failed == nil {
+		t.failed = make(map[*http.Request]attempt)
+	}
+	t.failed[req] = attempt{n: n, end: time.Now()}
+}
+
+// isRetryable reports whether a request ending with the given response and error may be
+// retried by the client.
+func isRetryable(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -34,6 +34,10 @@ func FilterFunc(configOpts ...Option) restful.FilterFunction {
 	instr.Logger().Debug("contrib/emicklei/go-restful/v3: Creating tracing filter: %#v", cfg)
 	spanOpts := []tracer.StartSpanOption{tracer.ServiceName(cfg.serviceName)}
 	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		if httptrace.IgnoreRequest(req.Request) {
+			chain.ProcessFilter(req, resp)
+			return
+		}
 		spanOpts := append(
 			spanOpts,
 			tracer.ResourceName(req.SelectedRoutePath()),
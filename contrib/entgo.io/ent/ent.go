@@ -0,0 +1,212 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package ent provides functions to trace the entgo.io/ent package (https://entgo.io).
+//
+// The statements run by a dialect.Driver wrapped with WrapDriver are traced, using their
+// obfuscated query as resource. Registering the Interceptor and the Hook on an ent client
+// additionally tags the spans with the schema type and the operation they are run for.
+package ent // import "github.com/DataDog/dd-trace-go/contrib/entgo.io/ent/v2"
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"entgo.io/ent/dialect"
+	"github.com/DataDog/datadog-agent/pkg/obfuscate"
+)
+
+const componentName = instrumentation.PackageEntgoEnt
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageEntgoEnt)
+}
+
+const (
+	// tagModel is the schema type a statement is run for.
+	tagModel = "ent.model"
+	// tagOperation is the ent operation a statement is run for, such as query or create.
+	tagOperation = "ent.operation"
+	// tagTables is the comma-separated list of the tables found in an obfuscated query.
+	tagTables = "sql.tables"
+	// tagRowsAffected is the number of rows affected by a statement.
+	tagRowsAffected = "db.result.rows_affected"
+)
+
+// nonParsableResource is the resource of the spans of the queries which can't be
+// obfuscated, as set by the Datadog Agent.
+const nonParsableResource = "Non-parsable SQL query"
+
+var obfuscator = sync.OnceValue(func() *obfuscate.Obfuscator {
+	return obfuscate.NewObfuscator(obfuscate.Config{
+		SQL: obfuscate.SQLConfig{
+			TableNames:    true,
+			ReplaceDigits: true,
+		},
+	})
+})
+
+// WrapDriver returns a dialect.Driver tracing the statements run by drv, to be passed to the
+// ent.Driver option when creating an ent client.
+func WrapDriver(drv dialect.Driver, opts ...Option) dialect.Driver {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/entgo.io/ent: Wrapping Driver: %#v", cfg)
+	return &driver{Driver: drv, cfg: cfg, dbSystem: dbSystem(drv.Dialect())}
+}
+
+// dbSystem returns the value of the db.system tag of the spans of a driver of the given
+// dialect.
+func dbSystem(name string) string {
+	switch name {
+	case dialect.MySQL:
+		return ext.DBSystemMySQL
+	case dialect.Postgres:
+		return ext.DBSystemPostgreSQL
+	default:
+		return ext.DBSystemOtherSQL
+	}
+}
+
+type driver struct {
+	dialect.Driver
+	cfg      *config
+	dbSystem string
+}
+
+var _ dialect.Driver = (*driver)(nil)
+
+// Exec traces the execution of query.
+func (d *driver) Exec(ctx context.Context, query string, args, v any) (err error) {
+	span := d.startSpan(ctx, query)
+	defer func() { finishSpan(span, v, err) }()
+	return d.Driver.Exec(ctx, query, args, v)
+}
+
+// Query traces the execution of query.
+func (d *driver) Query(ctx context.Context, query string, args, v any) (err error) {
+	span := d.startSpan(ctx, query)
+	defer func() { finishSpan(span, nil, err) }()
+	return d.Driver.Query(ctx, query, args, v)
+}
+
+// Tx starts a transaction tracing its statements.
+func (d *driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{Tx: tx, drv: d, ctx: ctx}, nil
+}
+
+// BeginTx starts a transaction with the given options tracing its statements, if the
+// wrapped driver supports it.
+func (d *driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("Driver.BeginTx is not supported")
+	}
+	tx, err := drv.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{Tx: tx, drv: d, ctx: ctx}, nil
+}
+
+// transaction traces the statements of a transaction, and its commit or rollback.
+type transaction struct {
+	dialect.Tx
+	drv *driver
+	ctx context.Context // context the transaction was started with
+}
+
+var _ dialect.Tx = (*transaction)(nil)
+
+// Exec traces the execution of query.
+func (tx *transaction) Exec(ctx context.Context, query string, args, v any) (err error) {
+	span := tx.drv.startSpan(ctx, query)
+	defer func() { finishSpan(span, v, err) }()
+	return tx.Tx.Exec(ctx, query, args, v)
+}
+
+// Query traces the execution of query.
+func (tx *transaction) Query(ctx context.Context, query string, args, v any) (err error) {
+	span := tx.drv.startSpan(ctx, query)
+	defer func() { finishSpan(span, nil, err) }()
+	return tx.Tx.Query(ctx, query, args, v)
+}
+
+// Commit traces the commit of the transaction.
+func (tx *transaction) Commit() (err error) {
+	span := tx.drv.startSpanWithResource(tx.ctx, "COMMIT")
+	defer func() { finishSpan(span, nil, err) }()
+	return tx.Tx.Commit()
+}
+
+// Rollback traces the rollback of the transaction.
+func (tx *transaction) Rollback() (err error) {
+	span := tx.drv.startSpanWithResource(tx.ctx, "ROLLBACK")
+	defer func() { finishSpan(span, nil, err) }()
+	return tx.Tx.Rollback()
+}
+
+// startSpan starts the span of query. The queries of the Gremlin dialect are used as is,
+// as they aren't SQL.
+func (d *driver) startSpan(ctx context.Context, query string) *tracer.Span {
+	if d.Dialect() == dialect.Gremlin {
+		return d.startSpanWithResource(ctx, query)
+	}
+	oq, err := obfuscator().ObfuscateSQLString(query)
+	if err != nil {
+		return d.startSpanWithResource(ctx, nonParsableResource)
+	}
+	span := d.startSpanWithResource(ctx, oq.Query)
+	if oq.Metadata.TablesCSV != "" {
+		span.SetTag(tagTables, oq.Metadata.TablesCSV)
+	}
+	return span
+}
+
+func (d *driver) startSpanWithResource(ctx context.Context, resource string) *tracer.Span {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(d.cfg.serviceName),
+		tracer.ResourceName(resource),
+		tracer.SpanType(ext.SpanTypeSQL),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		tracer.Tag(ext.DBSystem, d.dbSystem),
+		tracer.Measured(),
+	}
+	if !math.IsNaN(d.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, d.cfg.analyticsRate))
+	}
+	if op, ok := operationFromContext(ctx); ok {
+		opts = append(opts, tracer.Tag(tagModel, op.model), tracer.Tag(tagOperation, op.name))
+	}
+	opts = append(opts, d.cfg.spanOpts...)
+	span, _ := tracer.StartSpanFromContext(ctx, "ent.query", opts...)
+	return span
+}
+
+// finishSpan finishes span with err, tagging it with the number of rows affected by the
+// statement if v holds its result.
+func finishSpan(span *tracer.Span, v any, err error) {
+	if res, ok := v.(*sql.Result); ok && err == nil && *res != nil {
+		if n, err := (*res).RowsAffected(); err == nil {
+			span.SetTag(tagRowsAffected, n)
+		}
+	}
+	span.Finish(tracer.WithError(err))
+}
@@ -0,0 +1,178 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ent
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newDriver(t *testing.T, opts ...Option) dialect.Driver {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	drv := WrapDriver(entsql.OpenDB(dialect.SQLite, db), opts...)
+	t.Cleanup(func() { drv.Close() })
+	return drv
+}
+
+func createTable(t *testing.T, drv dialect.Driver) {
+	t.Helper()
+	require.NoError(t, drv.Exec(context.Background(), "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)", []any{}, nil))
+}
+
+func TestExecQuery(t *testing.T) {
+	drv := newDriver(t)
+	createTable(t, drv)
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	var res sql.Result
+	require.NoError(t, drv.Exec(ctx, "INSERT INTO users (id, name) VALUES (1, 'alice')", []any{}, &res))
+	var rows entsql.Rows
+	require.NoError(t, drv.Query(ctx, "SELECT name FROM users WHERE id = 42", []any{}, &rows))
+	require.NoError(t, rows.Close())
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	insert, query := spans[0], spans[1]
+	for _, s := range []*mocktracer.Span{insert, query} {
+		assert.Equal(t, "ent.query", s.OperationName())
+		assert.Equal(t, root.Context().SpanID(), s.ParentID())
+		assert.Equal(t, "ent.db", s.Tag(ext.ServiceName))
+		assert.Equal(t, ext.SpanTypeSQL, s.Tag(ext.SpanType))
+		assert.Equal(t, ext.SpanKindClient, s.Tag(ext.SpanKind))
+		assert.Equal(t, ext.DBSystemOtherSQL, s.Tag(ext.DBSystem))
+		assert.Equal(t, string(instrumentation.PackageEntgoEnt), s.Tag(ext.Component))
+		assert.Equal(t, "users", s.Tag(tagTables))
+		assert.Nil(t, s.Tag(tagModel))
+	}
+	assert.NotContains(t, insert.Tag(ext.ResourceName), "alice")
+	assert.Equal(t, float64(1), insert.Tag(tagRowsAffected))
+	assert.Equal(t, "SELECT name FROM users WHERE id = ?", query.Tag(ext.ResourceName))
+	assert.Nil(t, query.Tag(tagRowsAffected))
+}
+
+func TestError(t *testing.T) {
+	drv := newDriver(t, WithService("my-db"))
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var rows entsql.Rows
+	require.Error(t, drv.Query(context.Background(), "SELECT name FROM missing", []any{}, &rows))
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "my-db", spans[0].Tag(ext.ServiceName))
+	assert.NotNil(t, spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestTx(t *testing.T) {
+	drv := newDriver(t)
+	createTable(t, drv)
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	ctx := context.Background()
+	tx, err := drv.Tx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Exec(ctx, "INSERT INTO users (id, name) VALUES (1, 'alice')", []any{}, nil))
+	require.NoError(t, tx.Commit())
+
+	tx, err = drv.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	}).BeginTx(ctx, &sql.TxOptions{})
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "users", spans[0].Tag(tagTables))
+	assert.Equal(t, "COMMIT", spans[1].Tag(ext.ResourceName))
+	assert.Equal(t, "ROLLBACK", spans[2].Tag(ext.ResourceName))
+}
+
+type mutation struct {
+	ent.Mutation
+	op ent.Op
+}
+
+func (m *mutation) Op() ent.Op   { return m.op }
+func (m *mutation) Type() string { return "User" }
+
+func TestHook(t *testing.T) {
+	drv := newDriver(t)
+	createTable(t, drv)
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	mutator := Hook()(ent.MutateFunc(func(ctx context.Context, _ ent.Mutation) (ent.Value, error) {
+		return nil, drv.Exec(ctx, "DELETE FROM users WHERE id = 1", []any{}, nil)
+	}))
+	_, err := mutator.Mutate(context.Background(), &mutation{op: ent.OpDeleteOne})
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "User", spans[0].Tag(tagModel))
+	assert.Equal(t, "delete_one", spans[0].Tag(tagOperation))
+}
+
+func TestInterceptor(t *testing.T) {
+	drv := newDriver(t)
+	createTable(t, drv)
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	querier := Interceptor().Intercept(ent.QuerierFunc(func(ctx context.Context, _ ent.Query) (ent.Value, error) {
+		var rows entsql.Rows
+		if err := drv.Query(ctx, "SELECT name FROM users", []any{}, &rows); err != nil {
+			return nil, err
+		}
+		return nil, rows.Close()
+	}))
+	ctx := ent.NewQueryContext(context.Background(), &ent.QueryContext{Type: "User"})
+	_, err := querier.Query(ctx, nil)
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "User", spans[0].Tag(tagModel))
+	assert.Equal(t, "query", spans[0].Tag(tagOperation))
+}
+
+func TestOperationName(t *testing.T) {
+	for op, want := range map[ent.Op]string{
+		ent.OpCreate:    "create",
+		ent.OpUpdate:    "update",
+		ent.OpUpdateOne: "update_one",
+		ent.OpDelete:    "delete",
+		ent.OpDeleteOne: "delete_one",
+	} {
+		assert.Equal(t, want, operationName(op))
+	}
+}
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ent_test
+
+import (
+	"context"
+	"database/sql"
+
+	enttrace "github.com/DataDog/dd-trace-go/contrib/entgo.io/ent/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	_ "modernc.org/sqlite"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	db, err := sql.Open("sqlite", "file::memory:?cache=shared")
+	if err != nil {
+		panic(err)
+	}
+	// Wrap the driver of the ent client, as in:
+	//
+	//	client := ent.NewClient(ent.Driver(drv))
+	//	client.Intercept(enttrace.Interceptor())
+	//	client.Use(enttrace.Hook())
+	drv := enttrace.WrapDriver(entsql.OpenDB(dialect.SQLite, db), enttrace.WithService("users-db"))
+	defer drv.Close()
+
+	_ = drv.Exec(context.Background(), "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)", []any{}, nil)
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ent
+
+import (
+	"context"
+
+	"entgo.io/ent"
+)
+
+// operation is the ent operation the statements run with a context are run for.
+type operation struct {
+	model string // schema type of the operation, such as User
+	name  string // name of the operation, such as query or create
+}
+
+type operationKey struct{}
+
+func contextWithOperation(ctx context.Context, op operation) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+func operationFromContext(ctx context.Context) (operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(operation)
+	return op, ok
+}
+
+// Interceptor returns an ent.Interceptor tagging the spans of the statements run by the
+// queries of a client with the schema type they query. It is registered with the
+// Intercept method of the client, whose driver must be wrapped with WrapDriver.
+func Interceptor() ent.Interceptor {
+	return ent.InterceptFunc(func(next ent.Querier) ent.Querier {
+		return ent.QuerierFunc(func(ctx context.Context, q ent.Query) (ent.Value, error) {
+			if qc := ent.QueryFromContext(ctx); qc != nil && qc.Type != "" {
+				ctx = contextWithOperation(ctx, operation{model: qc.Type, name: "query"})
+			}
+			return next.Query(ctx, q)
+		})
+	})
+}
+
+// Hook returns an ent.Hook tagging the spans of the statements run by the mutations of a
+// client with the schema type and the operation of the mutation. It is registered with the
+// Use method of the client, whose driver must be wrapped with WrapDriver.
+func Hook() ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			ctx = contextWithOperation(ctx, operation{model: m.Type(), name: operationName(m.Op())})
+			return next.Mutate(ctx, m)
+		})
+	}
+}
+
+// operationName returns the name of the operation of a mutation.
+func operationName(op ent.Op) string {
+	switch {
+	case op.Is(ent.OpCreate):
+		return "create"
+	case op.Is(ent.OpUpdateOne):
+		return "update_one"
+	case op.Is(ent.OpUpdate):
+		return "update"
+	case op.Is(ent.OpDeleteOne):
+		return "delete_one"
+	case op.Is(ent.OpDelete):
+		return "delete"
+	default:
+		return op.String()
+	}
+}
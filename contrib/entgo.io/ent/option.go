@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ent
+
+import (
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	serviceName   string
+	analyticsRate float64
+	spanOpts      []tracer.StartSpanOption
+}
+
+// Option represents an option that can be used to wrap a driver.
+type Option func(*config)
+
+func defaultConfig() *config {
+	return &config{
+		serviceName:   instr.ServiceName(instrumentation.ComponentDefault, nil),
+		analyticsRate: instr.AnalyticsRate(false),
+	}
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the driver.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithAnalytics enables Trace Analytics for all started spans.
+func WithAnalytics(on bool) Option {
+	return func(cfg *config) {
+		if on {
+			cfg.analyticsRate = 1.0
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithAnalyticsRate sets the sampling rate for Trace Analytics events
+// correlated to started spans.
+func WithAnalyticsRate(rate float64) Option {
+	return func(cfg *config) {
+		if rate >= 0.0 && rate <= 1.0 {
+			cfg.analyticsRate = rate
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithSpanOptions appends the given options to the options used to start the spans of the
+// driver.
+func WithSpanOptions(opts ...tracer.StartSpanOption) Option {
+	return func(cfg *config) {
+		cfg.spanOpts = append(cfg.spanOpts, opts...)
+	}
+}
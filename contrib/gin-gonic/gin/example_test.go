@@ -65,3 +65,44 @@ func Example_spanFromContext() {
 		c.String(200, "ok!")
 	})
 }
+
+func ExampleWrapMiddleware() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	auth := func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(401)
+		}
+	}
+
+	r := gin.New()
+	r.Use(gintrace.Middleware("my-web-app"))
+	// trace the time spent in the authentication middleware with its own span.
+	r.Use(gintrace.WrapMiddleware(auth))
+	r.GET("/hello", func(c *gin.Context) {
+		c.String(200, "hello world!")
+	})
+	r.Run(":8080")
+}
+
+func ExampleUse() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	auth := func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(401)
+		}
+	}
+	logger := gin.Logger()
+
+	r := gin.New()
+	r.Use(gintrace.Middleware("my-web-app"))
+	// trace each middleware with its own span.
+	gintrace.Use(r, auth, logger)
+	r.GET("/hello", func(c *gin.Context) {
+		c.String(200, "hello world!")
+	})
+	r.Run(":8080")
+}
@@ -41,7 +41,7 @@ func Middleware(service string, opts ...Option) gin.HandlerFunc {
 		tracer.Tag(ext.SpanKind, ext.SpanKindServer),
 	}
 	return func(c *gin.Context) {
-		if cfg.ignoreRequest(c) {
+		if cfg.ignoreRequest(c) || httptrace.IgnoreRequest(c.Request) {
 			return
 		}
 		opts := options.Expand(spanOpts, 0, 4) // opts must be a copy of cfg.spanOpts, locally scoped, to avoid races.
@@ -6,6 +6,7 @@
 package gin
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"html/template"
@@ -644,3 +645,117 @@ func TestServiceName(t *testing.T) {
 		assert.Equal("my-service", span.Tag(ext.ServiceName))
 	})
 }
+
+func authMiddleware(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" {
+		c.AbortWithError(http.StatusUnauthorized, errors.New("unauthorized"))
+	}
+}
+
+func TestWrapMiddleware(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	router := gin.New()
+	router.Use(Middleware("foobar"))
+	router.Use(WrapMiddleware(authMiddleware))
+	router.Use(WrapMiddleware(func(c *gin.Context) {
+		c.Next()
+	}))
+	router.GET("/user/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	t.Run("next", func(t *testing.T) {
+		mt.Reset()
+		r := httptest.NewRequest("GET", "/user/123", nil)
+		r.Header.Set("Authorization", "secret")
+		router.ServeHTTP(httptest.NewRecorder(), r)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 3)
+		auth, next, root := spans[0], spans[1], spans[2]
+		assert.Equal(t, "http.request", root.OperationName())
+		assert.Equal(t, "gin.middleware", auth.OperationName())
+		assert.Equal(t, "github.com/DataDog/dd-trace-go/contrib/gin-gonic/gin/v2.authMiddleware", auth.Tag(ext.ResourceName))
+		assert.Equal(t, componentName, auth.Tag(ext.Component))
+		assert.Equal(t, root.SpanID(), auth.ParentID())
+		// handlers run after a middleware returns aren't nested in its span
+		assert.Equal(t, "gin.middleware", next.OperationName())
+		assert.Equal(t, root.SpanID(), next.ParentID())
+		assert.Nil(t, auth.Tag("gin.aborted"))
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		mt.Reset()
+		r := httptest.NewRequest("GET", "/user/123", nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		auth := spans[0]
+		assert.Equal(t, "true", auth.Tag("gin.aborted"))
+		assert.Equal(t, "Error #01: unauthorized\n", auth.Tag("gin.errors"))
+		assert.Equal(t, "401", spans[1].Tag(ext.HTTPCode))
+	})
+
+	t.Run("untraced", func(t *testing.T) {
+		mt.Reset()
+		router := gin.New()
+		router.Use(WrapMiddleware(authMiddleware))
+		r := httptest.NewRequest("GET", "/", nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+		assert.Empty(t, mt.FinishedSpans())
+	})
+
+	t.Run("context", func(t *testing.T) {
+		mt.Reset()
+		type userKey struct{}
+		router := gin.New()
+		router.Use(Middleware("foobar"))
+		router.Use(WrapMiddleware(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), userKey{}, "123"))
+		}))
+		var user any
+		router.GET("/", func(c *gin.Context) {
+			user = c.Request.Context().Value(userKey{})
+			span, _ := tracer.StartSpanFromContext(c.Request.Context(), "handler")
+			span.Finish()
+		})
+		r := httptest.NewRequest("GET", "/", nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+
+		// the values added by the middleware are kept, and the span of the request restored.
+		assert.Equal(t, "123", user)
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 3)
+		middleware, handler, root := spans[0], spans[1], spans[2]
+		assert.Equal(t, "gin.middleware", middleware.OperationName())
+		assert.Equal(t, "handler", handler.OperationName())
+		assert.Equal(t, root.SpanID(), handler.ParentID())
+	})
+}
+
+func TestUse(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	router := gin.New()
+	router.Use(Middleware("foobar"))
+	Use(router, authMiddleware, func(c *gin.Context) {
+		c.Next()
+	})
+	router.GET("/user/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("Authorization", "secret")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "gin.middleware", spans[0].OperationName())
+	assert.Equal(t, "github.com/DataDog/dd-trace-go/contrib/gin-gonic/gin/v2.authMiddleware", spans[0].Tag(ext.ResourceName))
+	assert.Equal(t, "gin.middleware", spans[1].OperationName())
+	assert.Equal(t, "http.request", spans[2].OperationName())
+}
@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package gin
+
+import (
+	"reflect"
+	"runtime"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// WrapMiddleware returns a handler which traces the execution of the middleware h with a
+// "gin.middleware" span, child of the span of the request and named after the function of h.
+// Wrapping each middleware registered after Middleware shows which of them, such as
+// authentication, rate limiting or rendering, dominates the latency of requests:
+//
+//	r.Use(gintrace.Middleware("my-web-app"))
+//	r.Use(gintrace.WrapMiddleware(auth), gintrace.WrapMiddleware(rateLimit))
+//
+// The handlers run by h through c.Next are traced as children of its span.
+func WrapMiddleware(h gin.HandlerFunc) gin.HandlerFunc {
+	name := runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		parent, ok := tracer.SpanFromContext(ctx)
+		if !ok {
+			// the request isn't traced
+			h(c)
+			return
+		}
+		span, spanCtx := tracer.StartSpanFromContext(ctx, "gin.middleware",
+			tracer.ResourceName(name),
+			tracer.Tag(ext.Component, componentName),
+			tracer.Tag("gin.handler", name),
+		)
+		errs := len(c.Errors)
+		defer func() {
+			if c.IsAborted() {
+				span.SetTag("gin.aborted", true)
+			}
+			if len(c.Errors) > errs {
+				span.SetTag("gin.errors", c.Errors[errs:].String())
+			}
+			span.Finish()
+			// the handlers run after h returns are siblings of its span. Only the span is
+			// restored, to keep the values h added to the context of the request.
+			c.Request = c.Request.WithContext(tracer.ContextWithSpan(c.Request.Context(), parent))
+		}()
+		c.Request = c.Request.WithContext(spanCtx)
+		h(c)
+	}
+}
+
+// Use registers the middlewares on r, each wrapped with WrapMiddleware, so that they are all
+// traced without wrapping them one by one:
+//
+//	r.Use(gintrace.Middleware("my-web-app"))
+//	gintrace.Use(r, auth, rateLimit)
+func Use(r gin.IRoutes, middleware ...gin.HandlerFunc) gin.IRoutes {
+	wrapped := make([]gin.HandlerFunc, len(middleware))
+	for i, h := range middleware {
+		wrapped[i] = WrapMiddleware(h)
+	}
+	return r.Use(wrapped...)
+}
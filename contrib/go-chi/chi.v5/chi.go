@@ -41,7 +41,7 @@ func Middleware(opts ...Option) func(next http.Handler) http.Handler {
 		tracer.Tag(ext.SpanKind, ext.SpanKindServer))
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if cfg.ignoreRequest(r) {
+			if cfg.ignoreRequest(r) || httptrace.IgnoreRequest(r) {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -72,6 +72,11 @@ func Middleware(opts ...Option) func(next http.Handler) http.Handler {
 
 			routePattern := cfg.modifyResourceName(chi.RouteContext(r.Context()).RoutePattern())
 			span.SetTag(ext.HTTPRoute, routePattern)
+			for _, name := range cfg.routeParams {
+				if v := chi.URLParam(r, name); v != "" {
+					span.SetTag(ext.HTTPRouteParams+"."+name, v)
+				}
+			}
 			var resourceName string
 			if cfg.resourceNamer != nil {
 				resourceName = cfg.resourceNamer(r)
@@ -423,6 +423,37 @@ func TestIgnoreRequest(t *testing.T) {
 	}
 }
 
+func TestIgnoreRules(t *testing.T) {
+	t.Setenv("DD_TRACE_HTTP_SERVER_IGNORE_PATHS", "/healthz")
+	t.Setenv("DD_TRACE_HTTP_SERVER_IGNORE_USER_AGENTS", "kube-probe")
+	httptrace.ResetCfg()
+	defer httptrace.ResetCfg()
+
+	router := chi.NewRouter()
+	router.Use(Middleware())
+	router.Get("/*", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	for _, tc := range []struct {
+		path, userAgent string
+		traced          bool
+	}{
+		{path: "/ok", traced: true},
+		{path: "/healthz", traced: false},
+		{path: "/ok", userAgent: "kube-probe/1.29", traced: false},
+	} {
+		mt := mocktracer.Start()
+		r := httptest.NewRequest("GET", "http://localhost"+tc.path, nil)
+		r.Header.Set("User-Agent", tc.userAgent)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, "ok", w.Body.String())
+		assert.Equal(t, tc.traced, len(mt.FinishedSpans()) == 1, "path %q, user agent %q", tc.path, tc.userAgent)
+		mt.Stop()
+	}
+}
+
 func TestAppSec(t *testing.T) {
 	testutils.StartAppSec(t)
 
@@ -715,3 +746,27 @@ func TestConcurrency(t *testing.T) {
 	// All the others config data are internal to the closures in Middleware and cannot be tested.
 	// Running this test with -race is the best chance to find a concurrency issue.
 }
+
+func TestWithRouteParams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	router := chi.NewRouter()
+	router.Use(Middleware(WithRouteParams("tenant", "id", "missing")))
+	router.Route("/tenants/{tenant}", func(r chi.Router) {
+		r.Get("/users/{id}/{secret}", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+
+	r := httptest.NewRequest("GET", "/tenants/acme/users/123/hunter2", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	tags := spans[0].Tags()
+	assert.Equal(t, "acme", tags["http.route.params.tenant"])
+	assert.Equal(t, "123", tags["http.route.params.id"])
+	assert.NotContains(t, tags, "http.route.params.secret")
+	assert.NotContains(t, tags, "http.route.params.missing")
+}
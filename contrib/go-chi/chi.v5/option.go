@@ -21,6 +21,7 @@ type config struct {
 	isStatusError      func(statusCode int) bool
 	ignoreRequest      func(r *http.Request) bool
 	modifyResourceName func(resourceName string) string
+	routeParams        []string
 	headerTags         instrumentation.HeaderTags
 	resourceNamer      func(r *http.Request) string
 	appsecDisabled     bool
@@ -122,6 +123,16 @@ func WithHeaderTags(headers []string) OptionFn {
 	}
 }
 
+// WithRouteParams enables the integration to tag spans with the values of the given named
+// route parameters, as http.route.params.<name>. Only the parameters in the list are tagged.
+// Warning:
+// Using this feature can risk exposing sensitive data held in paths to Datadog.
+func WithRouteParams(names ...string) OptionFn {
+	return func(cfg *config) {
+		cfg.routeParams = names
+	}
+}
+
 // WithResourceNamer specifies a function to use for determining the resource
 // name of the span.
 func WithResourceNamer(fn func(r *http.Request) string) OptionFn {
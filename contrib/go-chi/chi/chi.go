@@ -41,7 +41,7 @@ func Middleware(opts ...Option) func(next http.Handler) http.Handler {
 		tracer.Tag(ext.SpanKind, ext.SpanKindServer))
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if cfg.ignoreRequest(r) {
+			if cfg.ignoreRequest(r) || httptrace.IgnoreRequest(r) {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -70,6 +70,11 @@ func Middleware(opts ...Option) func(next http.Handler) http.Handler {
 			// pass the span through the request context and serve the request to the next middleware
 			next.ServeHTTP(ww, r)
 			span.SetTag(ext.HTTPRoute, chi.RouteContext(r.Context()).RoutePattern())
+			for _, name := range cfg.routeParams {
+				if v := chi.URLParam(r, name); v != "" {
+					span.SetTag(ext.HTTPRouteParams+"."+name, v)
+				}
+			}
 			span.SetTag(ext.ResourceName, cfg.resourceNamer(r))
 		})
 	}
@@ -633,3 +633,27 @@ func TestUnknownResourceName(t *testing.T) {
 	require.Equal(t, "service-name", spans[0].Tag(ext.ServiceName))
 	require.Equal(t, "GET unknown", spans[0].Tag(ext.ResourceName))
 }
+
+func TestWithRouteParams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	router := chi.NewRouter()
+	router.Use(Middleware(WithRouteParams("tenant", "id", "missing")))
+	router.Route("/tenants/{tenant}", func(r chi.Router) {
+		r.Get("/users/{id}/{secret}", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+
+	r := httptest.NewRequest("GET", "/tenants/acme/users/123/hunter2", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	tags := spans[0].Tags()
+	assert.Equal(t, "acme", tags["http.route.params.tenant"])
+	assert.Equal(t, "123", tags["http.route.params.id"])
+	assert.NotContains(t, tags, "http.route.params.secret")
+	assert.NotContains(t, tags, "http.route.params.missing")
+}
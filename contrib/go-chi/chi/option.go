@@ -22,6 +22,7 @@ type config struct {
 	isStatusError func(statusCode int) bool
 	ignoreRequest func(r *http.Request) bool
 	resourceNamer func(r *http.Request) string
+	routeParams   []string
 	headerTags    instrumentation.HeaderTags
 }
 
@@ -116,6 +117,16 @@ func WithIgnoreRequest(fn func(r *http.Request) bool) OptionFn {
 	}
 }
 
+// WithRouteParams enables the integration to tag spans with the values of the given named
+// route parameters, as http.route.params.<name>. Only the parameters in the list are tagged.
+// Warning:
+// Using this feature can risk exposing sensitive data held in paths to Datadog.
+func WithRouteParams(names ...string) OptionFn {
+	return func(cfg *config) {
+		cfg.routeParams = names
+	}
+}
+
 // WithResourceNamer specifies a function to use for determining the resource
 // name of the span.
 func WithResourceNamer(fn func(r *http.Request) string) OptionFn {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -80,3 +81,51 @@ func Test(t *testing.T) {
 	assert.Equal(t, ext.SpanKindClient, s.Tag(ext.SpanKind))
 	assert.Equal(t, "mongodb", s.Tag(ext.DBSystem))
 }
+
+func TestCommandDetails(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	opts := options.Client()
+	opts.Monitor = NewMonitor(WithSanitizedQuery())
+	opts.PoolMonitor = NewPoolMonitor()
+	opts.ApplyURI("mongodb://localhost:27017/?connect=direct")
+	client, err := mongo.Connect(opts)
+	require.NoError(t, err)
+	defer client.Disconnect(context.Background())
+
+	_, err = client.
+		Database("test-database").
+		Collection("test-collection").
+		InsertOne(ctx, bson.D{{Key: "test-item", Value: "test-value"}})
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Contains(t, s.Tag("mongodb.query"), `"insert":"test-collection"`)
+	assert.Contains(t, s.Tag("mongodb.query"), `"test-item":"?"`)
+	assert.NotContains(t, s.Tag("mongodb.query"), "test-value")
+	assert.Greater(t, s.Tag(tagCommandSize), float64(0))
+	assert.Greater(t, s.Tag(tagReplySize), float64(0))
+	assert.NotNil(t, s.Tag(tagCheckoutDuration))
+}
+
+func TestSanitizeCommand(t *testing.T) {
+	cmd, err := bson.Marshal(bson.D{
+		{Key: "find", Value: "users"},
+		{Key: "filter", Value: bson.D{
+			{Key: "name", Value: "alice"},
+			{Key: "age", Value: bson.D{{Key: "$in", Value: bson.A{30, 31}}}},
+		}},
+		{Key: "limit", Value: 10},
+	})
+	require.NoError(t, err)
+
+	b, err := bson.MarshalExtJSON(sanitizeCommand(cmd), false, false)
+	require.NoError(t, err)
+	assert.Equal(t, `{"find":"users","filter":{"name":"?","age":{"$in":["?","?"]}},"limit":"?"}`, string(b))
+}
@@ -10,8 +10,9 @@ import (
 )
 
 type config struct {
-	serviceName string
-	spanName    string
+	serviceName   string
+	spanName      string
+	sanitizeQuery bool
 }
 
 // Option describes options for the Mongo integration.
@@ -37,3 +38,12 @@ func WithService(name string) OptionFn {
 		cfg.serviceName = name
 	}
 }
+
+// WithSanitizedQuery replaces the values of the command documents recorded in the
+// mongodb.query tag with "?", keeping their keys and structure, so that the documents
+// read and written by commands aren't sent to Datadog.
+func WithSanitizedQuery() OptionFn {
+	return func(cfg *config) {
+		cfg.sanitizeQuery = true
+	}
+}
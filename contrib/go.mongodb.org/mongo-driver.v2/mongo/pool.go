@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mongo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// tagCheckoutDuration is the time spent checking out the connection of a command from the pool,
+// including the wait for an available connection.
+const tagCheckoutDuration = "mongodb.connection.checkout_ms"
+
+// maxCheckouts is the maximum number of checkout durations waiting for the command of their
+// connection, bounding the memory used when the pool monitor is set without the command monitor.
+const maxCheckouts = 1000
+
+// checkouts records the checkouts of connections observed by the pool monitors.
+var checkouts = checkoutTracker{durations: make(map[string]time.Duration)}
+
+// NewPoolMonitor returns an event.PoolMonitor recording the time spent checking out connections
+// from the pools of a client, reported in the mongodb.connection.checkout_ms tag of the spans of
+// the commands run on them, which tells the time queued in the driver apart from the execution
+// time of the commands. It must be set on clients along with the monitor returned by NewMonitor:
+//
+//	opts := options.Client().SetMonitor(mongotrace.NewMonitor()).SetPoolMonitor(mongotrace.NewPoolMonitor())
+func NewPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: checkouts.handle}
+}
+
+// checkoutTracker holds the checkout duration of checked out connections, until the command
+// run on them starts.
+type checkoutTracker struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+func (t *checkoutTracker) handle(evt *event.PoolEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch evt.Type {
+	case event.ConnectionCheckedOut:
+		if len(t.durations) >= maxCheckouts {
+			clear(t.durations)
+		}
+		t.durations[connectionID(evt)] = evt.Duration
+	case event.ConnectionCheckedIn:
+		delete(t.durations, connectionID(evt))
+	}
+}
+
+// take removes and returns the checkout duration of the connection with the given ID, as
+// reported in command events.
+func (t *checkoutTracker) take(connID string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.durations[connID]
+	if ok {
+		delete(t.durations, connID)
+	}
+	return d, ok
+}
+
+// connectionID returns the ID of the connection of evt, in the format of command events.
+func connectionID(evt *event.PoolEvent) string {
+	return fmt.Sprintf("%s[-%d]", evt.Address, evt.ConnectionID)
+}
@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// sanitizedValue replaces the values of sanitized command documents.
+const sanitizedValue = "?"
+
+// sanitizeCommand returns cmd with its values replaced with "?", keeping its keys and the
+// structure of its documents and arrays. The value of the first key, the command name, is
+// kept, as it holds the name of the collection the command runs on.
+func sanitizeCommand(cmd bson.Raw) bson.D {
+	elems, err := cmd.Elements()
+	if err != nil {
+		return nil
+	}
+	doc := make(bson.D, 0, len(elems))
+	for i, elem := range elems {
+		v := elem.Value()
+		if i == 0 && v.Type == bson.TypeString {
+			doc = append(doc, bson.E{Key: elem.Key(), Value: v.StringValue()})
+			continue
+		}
+		doc = append(doc, bson.E{Key: elem.Key(), Value: sanitizeValue(v)})
+	}
+	return doc
+}
+
+func sanitizeValue(v bson.RawValue) any {
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		elems, err := v.Document().Elements()
+		if err != nil {
+			return sanitizedValue
+		}
+		doc := make(bson.D, 0, len(elems))
+		for _, elem := range elems {
+			doc = append(doc, bson.E{Key: elem.Key(), Value: sanitizeValue(elem.Value())})
+		}
+		return doc
+	case bson.TypeArray:
+		values, err := v.Array().Values()
+		if err != nil {
+			return sanitizedValue
+		}
+		arr := make(bson.A, 0, len(values))
+		for _, elem := range values {
+			arr = append(arr, sanitizeValue(elem))
+		}
+		return arr
+	}
+	return sanitizedValue
+}
@@ -32,6 +32,13 @@ func init() {
 	instr = instrumentation.Load(instrumentation.PackageMongoDriver)
 }
 
+const (
+	// tagCommandSize is the size in bytes of the BSON document of a command.
+	tagCommandSize = "mongodb.command.size"
+	// tagReplySize is the size in bytes of the BSON document of the reply to a command.
+	tagReplySize = "mongodb.reply.size"
+)
+
 type spanKey struct {
 	ConnectionID string
 	RequestID    int64
@@ -45,7 +52,12 @@ type monitor struct {
 
 func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 	hostname, port := peerInfo(evt)
-	b, _ := bson.MarshalExtJSON(evt.Command, false, false)
+	var b []byte
+	if m.cfg.sanitizeQuery {
+		b, _ = bson.MarshalExtJSON(sanitizeCommand(evt.Command), false, false)
+	} else {
+		b, _ = bson.MarshalExtJSON(evt.Command, false, false)
+	}
 	opts := []tracer.StartSpanOption{
 		tracer.SpanType(ext.SpanTypeMongoDB),
 		tracer.ServiceName(m.cfg.serviceName),
@@ -59,6 +71,10 @@ func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 		tracer.Tag(ext.Component, componentName),
 		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
 		tracer.Tag(ext.DBSystem, ext.DBSystemMongoDB),
+		tracer.Tag(tagCommandSize, len(evt.Command)),
+	}
+	if d, ok := checkouts.take(evt.ConnectionID); ok {
+		opts = append(opts, tracer.Tag(tagCheckoutDuration, float64(d.Microseconds())/1000))
 	}
 	if !math.IsNaN(m.cfg.analyticsRate) {
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, m.cfg.analyticsRate))
@@ -74,14 +90,19 @@ func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 }
 
 func (m *monitor) Succeeded(_ context.Context, evt *event.CommandSucceededEvent) {
-	m.Finished(&evt.CommandFinishedEvent, nil)
+	m.finish(&evt.CommandFinishedEvent, nil, len(evt.Reply))
 }
 
 func (m *monitor) Failed(_ context.Context, evt *event.CommandFailedEvent) {
-	m.Finished(&evt.CommandFinishedEvent, fmt.Errorf("%s", evt.Failure))
+	m.finish(&evt.CommandFinishedEvent, fmt.Errorf("%s", evt.Failure), 0)
 }
 
 func (m *monitor) Finished(evt *event.CommandFinishedEvent, err error) {
+	m.finish(evt, err, 0)
+}
+
+// finish finishes the span of the command of evt, tagging the size of its reply if not zero.
+func (m *monitor) finish(evt *event.CommandFinishedEvent, err error, replySize int) {
 	key := spanKey{
 		ConnectionID: evt.ConnectionID,
 		RequestID:    evt.RequestID,
@@ -95,6 +116,9 @@ func (m *monitor) Finished(evt *event.CommandFinishedEvent, err error) {
 	if !ok {
 		return
 	}
+	if replySize > 0 {
+		span.SetTag(tagReplySize, replySize)
+	}
 	span.Finish(tracer.WithError(err))
 }
 
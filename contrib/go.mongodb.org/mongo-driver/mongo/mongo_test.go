@@ -13,7 +13,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
@@ -136,3 +138,71 @@ func TestAnalyticsSettings(t *testing.T) {
 		assertRate(t, mt, 0.23, WithAnalyticsRate(0.23))
 	})
 }
+
+func TestCommandDetails(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	opts := options.Client()
+	opts.Monitor = NewMonitor(WithSanitizedQuery())
+	opts.PoolMonitor = NewPoolMonitor()
+	opts.ApplyURI("mongodb://localhost:27017/?connect=direct")
+	client, err := mongo.Connect(ctx, opts)
+	require.NoError(t, err)
+	defer client.Disconnect(context.Background())
+
+	_, err = client.
+		Database("test-database").
+		Collection("test-collection").
+		InsertOne(ctx, bson.D{{Key: "test-item", Value: "test-value"}})
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Contains(t, s.Tag("mongodb.query"), `"insert":"test-collection"`)
+	assert.Contains(t, s.Tag("mongodb.query"), `"test-item":"?"`)
+	assert.NotContains(t, s.Tag("mongodb.query"), "test-value")
+	assert.Greater(t, s.Tag(tagCommandSize), float64(0))
+	assert.Greater(t, s.Tag(tagReplySize), float64(0))
+	assert.NotNil(t, s.Tag(tagCheckoutDuration))
+}
+
+func TestSanitizeCommand(t *testing.T) {
+	cmd, err := bson.Marshal(bson.D{
+		{Key: "find", Value: "users"},
+		{Key: "filter", Value: bson.D{
+			{Key: "name", Value: "alice"},
+			{Key: "age", Value: bson.D{{Key: "$in", Value: bson.A{30, 31}}}},
+		}},
+		{Key: "limit", Value: 10},
+	})
+	require.NoError(t, err)
+
+	b, err := bson.MarshalExtJSON(sanitizeCommand(cmd), false, false)
+	require.NoError(t, err)
+	assert.Equal(t, `{"find":"users","filter":{"name":"?","age":{"$in":["?","?"]}},"limit":"?"}`, string(b))
+}
+
+func TestCheckoutTracker(t *testing.T) {
+	tracker := checkoutTracker{
+		pending:   make(map[string][]time.Time),
+		durations: make(map[string]time.Duration),
+	}
+	const addr = "localhost:27017"
+	tracker.handle(&event.PoolEvent{Type: event.GetStarted, Address: addr})
+	tracker.handle(&event.PoolEvent{Type: event.GetStarted, Address: addr})
+	time.Sleep(time.Millisecond)
+	tracker.handle(&event.PoolEvent{Type: event.GetSucceeded, Address: addr, ConnectionID: 1})
+	tracker.handle(&event.PoolEvent{Type: event.GetFailed, Address: addr})
+	assert.Empty(t, tracker.pending)
+
+	d, ok := tracker.take("localhost:27017[-1]")
+	assert.True(t, ok)
+	assert.GreaterOrEqual(t, d, time.Millisecond)
+	_, ok = tracker.take("localhost:27017[-1]")
+	assert.False(t, ok)
+}
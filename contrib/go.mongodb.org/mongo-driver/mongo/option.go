@@ -15,6 +15,7 @@ type config struct {
 	serviceName   string
 	spanName      string
 	analyticsRate float64
+	sanitizeQuery bool
 }
 
 // Option describes options for the Mongo integration.
@@ -64,3 +65,12 @@ func WithAnalyticsRate(rate float64) OptionFn {
 		}
 	}
 }
+
+// WithSanitizedQuery replaces the values of the command documents recorded in the
+// mongodb.query tag with "?", keeping their keys and structure, so that the documents
+// read and written by commands aren't sent to Datadog.
+func WithSanitizedQuery() OptionFn {
+	return func(cfg *config) {
+		cfg.sanitizeQuery = true
+	}
+}
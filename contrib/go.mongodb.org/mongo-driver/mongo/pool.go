@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mongo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// tagCheckoutDuration is the time spent checking out the connection of a command from the pool,
+// including the wait for an available connection.
+const tagCheckoutDuration = "mongodb.connection.checkout_ms"
+
+// maxCheckouts is the maximum number of checkout durations waiting for the command of their
+// connection, bounding the memory used when the pool monitor is set without the command monitor.
+const maxCheckouts = 1000
+
+// checkouts records the checkouts of connections observed by the pool monitors.
+var checkouts = checkoutTracker{
+	pending:   make(map[string][]time.Time),
+	durations: make(map[string]time.Duration),
+}
+
+// NewPoolMonitor returns an event.PoolMonitor recording the time spent checking out connections
+// from the pools of a client, reported in the mongodb.connection.checkout_ms tag of the spans of
+// the commands run on them, which tells the time queued in the driver apart from the execution
+// time of the commands. It must be set on clients along with the monitor returned by NewMonitor:
+//
+//	opts := options.Client().SetMonitor(mongotrace.NewMonitor()).SetPoolMonitor(mongotrace.NewPoolMonitor())
+func NewPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: checkouts.handle}
+}
+
+// checkoutTracker measures the checkouts of connections. The checkout events don't identify
+// the checkout they are part of, so pending checkouts of an address are assumed to complete in
+// the order they started, as served by the wait queue of the pool.
+type checkoutTracker struct {
+	mu sync.Mutex
+	// pending holds the start time of the pending checkouts of each address.
+	pending map[string][]time.Time
+	// durations holds the checkout duration of checked out connections, by connection ID.
+	durations map[string]time.Duration
+}
+
+func (t *checkoutTracker) handle(evt *event.PoolEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch evt.Type {
+	case event.GetStarted:
+		t.pending[evt.Address] = append(t.pending[evt.Address], time.Now())
+	case event.GetSucceeded, event.GetFailed:
+		start, ok := t.pop(evt.Address)
+		if !ok || evt.Type == event.GetFailed {
+			return
+		}
+		if len(t.durations) >= maxCheckouts {
+			clear(t.durations)
+		}
+		t.durations[connectionID(evt)] = time.Since(start)
+	case event.ConnectionReturned:
+		delete(t.durations, connectionID(evt))
+	case event.PoolClosedEvent:
+		delete(t.pending, evt.Address)
+	}
+}
+
+// pop removes and returns the start time of the oldest pending checkout of addr.
+func (t *checkoutTracker) pop(addr string) (time.Time, bool) {
+	pending := t.pending[addr]
+	if len(pending) == 0 {
+		return time.Time{}, false
+	}
+	if len(pending) == 1 {
+		delete(t.pending, addr)
+	} else {
+		t.pending[addr] = pending[1:]
+	}
+	return pending[0], true
+}
+
+// take removes and returns the checkout duration of the connection with the given ID, as
+// reported in command events.
+func (t *checkoutTracker) take(connID string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.durations[connID]
+	if ok {
+		delete(t.durations, connID)
+	}
+	return d, ok
+}
+
+// connectionID returns the ID of the connection of evt, in the format of command events.
+func connectionID(evt *event.PoolEvent) string {
+	return fmt.Sprintf("%s[-%d]", evt.Address, evt.ConnectionID)
+}
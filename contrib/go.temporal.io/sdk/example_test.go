@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package temporal_test
+
+import (
+	"log"
+
+	temporaltrace "github.com/DataDog/dd-trace-go/contrib/go.temporal.io/sdk/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/worker"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	// The interceptor traces the workflows and activities started by the client, and run by
+	// the workers created with it.
+	c, err := client.Dial(client.Options{
+		Interceptors: []interceptor.ClientInterceptor{temporaltrace.NewTracingInterceptor()},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	w := worker.New(c, "orders", worker.Options{})
+	if err := w.Run(worker.InterruptCh()); err != nil {
+		log.Fatal(err)
+	}
+}
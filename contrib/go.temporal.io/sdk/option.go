@@ -0,0 +1,48 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package temporal
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	serviceName  string
+	traceSignals bool
+	traceQueries bool
+}
+
+// Option represents an option that can be used to create a tracer or a tracing interceptor.
+type Option func(*config)
+
+func defaultConfig() *config {
+	return &config{
+		serviceName:  instr.ServiceName(instrumentation.ComponentDefault, nil),
+		traceSignals: true,
+		traceQueries: true,
+	}
+}
+
+// WithService sets the given service name for the spans of workflows and activities.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithTraceSignals enables tracing the signals sent to and handled by workflows.
+func WithTraceSignals(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.traceSignals = enabled
+	}
+}
+
+// WithTraceQueries enables tracing the queries sent to and handled by workflows.
+func WithTraceQueries(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.traceQueries = enabled
+	}
+}
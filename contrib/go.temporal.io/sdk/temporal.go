@@ -0,0 +1,210 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package temporal provides a tracer for the interceptors of the Temporal Go SDK (https://github.com/temporalio/sdk-go),
+// tracing the workflows and activities started by clients and run by workers.
+//
+// The trace context is propagated in the headers of workflows, activities, signals and queries,
+// so that their spans belong to the trace of the client which started them.
+//
+// Workflows are replayed by workers from their history, for instance after being evicted from
+// the cache of a worker or when another worker takes them over. The span of a workflow is
+// reported again when it is replayed, with a span ID derived from the workflow run and the
+// position of the span in the workflow history, so that it is the same on every worker and the
+// spans started before the replay keep their parent.
+package temporal // import "github.com/DataDog/dd-trace-go/contrib/go.temporal.io/sdk/v2"
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/log"
+)
+
+const componentName = instrumentation.PackageTemporalSDK
+
+// headerKey is the key of the trace context in Temporal headers.
+const headerKey = "_dd-tracer-data"
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageTemporalSDK)
+}
+
+// spanContextKey is the key of the span in workflow contexts.
+type spanContextKey struct{}
+
+// NewTracingInterceptor returns an interceptor tracing workflows and activities, to be set in
+// the Interceptors of the client options. Workers created with the client use it as well.
+func NewTracingInterceptor(opts ...Option) interceptor.Interceptor {
+	return interceptor.NewTracingInterceptor(NewTracer(opts...))
+}
+
+// NewTracer returns an implementation of the interceptor.Tracer interface of the Temporal
+// SDK reporting Datadog spans, for use with interceptor.NewTracingInterceptor.
+func NewTracer(opts ...Option) interceptor.Tracer {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	instr.Logger().Debug("contrib/go.temporal.io/sdk: Creating Tracer: %#v", cfg)
+	return &temporalTracer{cfg: cfg}
+}
+
+type temporalTracer struct {
+	interceptor.BaseTracer
+	cfg *config
+}
+
+var _ interceptor.Tracer = (*temporalTracer)(nil)
+
+// Options implements interceptor.Tracer.
+func (t *temporalTracer) Options() interceptor.TracerOptions {
+	return interceptor.TracerOptions{
+		SpanContextKey:          spanContextKey{},
+		HeaderKey:               headerKey,
+		DisableSignalTracing:    !t.cfg.traceSignals,
+		DisableQueryTracing:     !t.cfg.traceQueries,
+		AllowInvalidParentSpans: true,
+	}
+}
+
+// UnmarshalSpan implements interceptor.Tracer, extracting the span context propagated in headers.
+func (t *temporalTracer) UnmarshalSpan(m map[string]string) (interceptor.TracerSpanRef, error) {
+	spanCtx, err := tracer.Extract(tracer.TextMapCarrier(m))
+	if err != nil {
+		return nil, err
+	}
+	return spanCtx, nil
+}
+
+// MarshalSpan implements interceptor.Tracer, injecting the context of span to propagate it in headers.
+func (t *temporalTracer) MarshalSpan(span interceptor.TracerSpan) (map[string]string, error) {
+	s, ok := span.(*tracerSpan)
+	if !ok {
+		return nil, nil
+	}
+	m := map[string]string{}
+	if err := tracer.Inject(s.Context(), tracer.TextMapCarrier(m)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SpanFromContext implements interceptor.Tracer.
+func (t *temporalTracer) SpanFromContext(ctx context.Context) interceptor.TracerSpan {
+	span, ok := tracer.SpanFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &tracerSpan{Span: span}
+}
+
+// ContextWithSpan implements interceptor.Tracer.
+func (t *temporalTracer) ContextWithSpan(ctx context.Context, span interceptor.TracerSpan) context.Context {
+	s, ok := span.(*tracerSpan)
+	if !ok {
+		return ctx
+	}
+	return tracer.ContextWithSpan(ctx, s.Span)
+}
+
+// StartSpan implements interceptor.Tracer. The spans with an idempotency key, started by the
+// workers for the workflows they run, get a span ID derived from the key. The key is made of the
+// workflow run ID and of a counter replayed from the workflow history, so that the span keeps
+// its ID when the workflow is replayed, whichever worker replays it.
+func (t *temporalTracer) StartSpan(options *interceptor.TracerStartSpanOptions) (interceptor.TracerSpan, error) {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(t.cfg.serviceName),
+		tracer.ResourceName(options.Name),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, spanKind(options.Operation)),
+		tracer.Tag("temporal.operation", options.Operation),
+	}
+	if !options.Time.IsZero() {
+		opts = append(opts, tracer.StartTime(options.Time))
+	}
+	switch parent := options.Parent.(type) {
+	case *tracerSpan:
+		opts = append(opts, tracer.ChildOf(parent.Context()))
+	case *tracer.SpanContext:
+		opts = append(opts, tracer.ChildOf(parent))
+	}
+	for k, v := range options.Tags {
+		opts = append(opts, tracer.Tag(k, v))
+	}
+	if options.IdempotencyKey != "" {
+		h := fnv.New64()
+		h.Write([]byte(options.IdempotencyKey))
+		opts = append(opts, tracer.WithSpanID(h.Sum64()))
+	}
+	return &tracerSpan{Span: tracer.StartSpan(operationName(options.Operation), opts...)}, nil
+}
+
+// GetLogger implements interceptor.Tracer, adding the trace and span IDs of the span to the
+// log entries of workflows and activities.
+func (t *temporalTracer) GetLogger(logger log.Logger, ref interceptor.TracerSpanRef) log.Logger {
+	var spanCtx *tracer.SpanContext
+	switch s := ref.(type) {
+	case *tracerSpan:
+		spanCtx = s.Context()
+	case *tracer.SpanContext:
+		spanCtx = s
+	default:
+		return logger
+	}
+	return log.With(logger,
+		ext.LogKeyTraceID, strconv.FormatUint(spanCtx.TraceIDLower(), 10),
+		ext.LogKeySpanID, strconv.FormatUint(spanCtx.SpanID(), 10),
+	)
+}
+
+// operationName returns the name of the spans of the given Temporal operation, e.g.
+// "temporal.run_workflow" for RunWorkflow.
+func operationName(op string) string {
+	var b strings.Builder
+	b.WriteString("temporal.")
+	for i, r := range op {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// spanKind returns the kind of the spans of the given Temporal operation: server for the
+// workflows, activities, signals and queries run by workers, client for the others.
+func spanKind(op string) string {
+	if strings.HasPrefix(op, "Run") || strings.HasPrefix(op, "Handle") || strings.HasPrefix(op, "Validate") {
+		return ext.SpanKindServer
+	}
+	return ext.SpanKindClient
+}
+
+// tracerSpan implements interceptor.TracerSpan.
+type tracerSpan struct {
+	*tracer.Span
+}
+
+// Finish implements interceptor.TracerSpan.
+func (s *tracerSpan) Finish(options *interceptor.TracerFinishSpanOptions) {
+	var err error
+	if options != nil {
+		err = options.Error
+	}
+	s.Span.Finish(tracer.WithError(err))
+}
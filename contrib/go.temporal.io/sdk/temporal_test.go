@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package temporal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+)
+
+func TestStartSpan(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := NewTracer(WithService("orders"))
+	start := time.Now().Add(-time.Minute)
+	client, err := tr.StartSpan(&interceptor.TracerStartSpanOptions{
+		Operation: "StartWorkflow",
+		Name:      "ProcessOrder",
+		Tags:      map[string]string{"temporalWorkflowID": "order-1"},
+		ToHeader:  true,
+	})
+	require.NoError(t, err)
+
+	// the context is propagated through the workflow headers
+	header, err := tr.MarshalSpan(client)
+	require.NoError(t, err)
+	parent, err := tr.UnmarshalSpan(header)
+	require.NoError(t, err)
+
+	run, err := tr.StartSpan(&interceptor.TracerStartSpanOptions{
+		Parent:     parent,
+		Operation:  "RunWorkflow",
+		Name:       "ProcessOrder",
+		Time:       start,
+		FromHeader: true,
+	})
+	require.NoError(t, err)
+	run.Finish(&interceptor.TracerFinishSpanOptions{Error: errors.New("failed")})
+	client.Finish(&interceptor.TracerFinishSpanOptions{})
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	r, c := spans[0], spans[1]
+	assert.Equal(t, "temporal.start_workflow", c.OperationName())
+	assert.Equal(t, "ProcessOrder", c.Tag(ext.ResourceName))
+	assert.Equal(t, "orders", c.Tag(ext.ServiceName))
+	assert.Equal(t, ext.SpanKindClient, c.Tag(ext.SpanKind))
+	assert.Equal(t, "go.temporal.io/sdk", c.Tag(ext.Component))
+	assert.Equal(t, "StartWorkflow", c.Tag("temporal.operation"))
+	assert.Equal(t, "order-1", c.Tag("temporalWorkflowID"))
+
+	assert.Equal(t, "temporal.run_workflow", r.OperationName())
+	assert.Equal(t, ext.SpanKindServer, r.Tag(ext.SpanKind))
+	assert.Equal(t, c.SpanID(), r.ParentID())
+	assert.Equal(t, c.TraceID(), r.TraceID())
+	assert.Equal(t, start.UnixNano(), r.StartTime().UnixNano())
+	assert.Equal(t, "failed", r.Tag(ext.ErrorMsg))
+}
+
+func TestReplay(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	opts := &interceptor.TracerStartSpanOptions{
+		Operation:      "RunWorkflow",
+		Name:           "ProcessOrder",
+		IdempotencyKey: "WorkflowInboundInterceptor:run-1:1",
+	}
+	first, err := NewTracer().StartSpan(opts)
+	require.NoError(t, err)
+	first.Finish(&interceptor.TracerFinishSpanOptions{})
+
+	// another worker replays the workflow from its history
+	replayed, err := NewTracer().StartSpan(opts)
+	require.NoError(t, err)
+	replayed.Finish(&interceptor.TracerFinishSpanOptions{})
+
+	other, err := NewTracer().StartSpan(&interceptor.TracerStartSpanOptions{
+		Operation:      "RunWorkflow",
+		Name:           "ProcessOrder",
+		IdempotencyKey: "WorkflowInboundInterceptor:run-2:1",
+	})
+	require.NoError(t, err)
+	other.Finish(&interceptor.TracerFinishSpanOptions{})
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, spans[0].SpanID(), spans[1].SpanID())
+	assert.NotEqual(t, spans[0].SpanID(), spans[2].SpanID())
+}
+
+func TestContext(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := NewTracer()
+	assert.Nil(t, tr.SpanFromContext(context.Background()))
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	defer span.Finish()
+	ref := tr.SpanFromContext(ctx)
+	require.NotNil(t, ref)
+
+	activity, err := tr.StartSpan(&interceptor.TracerStartSpanOptions{Parent: ref, Operation: "StartActivity", Name: "Charge"})
+	require.NoError(t, err)
+	ctx = tr.ContextWithSpan(context.Background(), activity)
+	got, ok := tracer.SpanFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, span.Context().SpanID(), mocktracer.MockSpan(got).ParentID())
+}
+
+func TestOptions(t *testing.T) {
+	opts := NewTracer(WithTraceSignals(false)).Options()
+	assert.True(t, opts.DisableSignalTracing)
+	assert.False(t, opts.DisableQueryTracing)
+	assert.Equal(t, headerKey, opts.HeaderKey)
+}
+
+func TestOperationName(t *testing.T) {
+	for op, want := range map[string]string{
+		"StartWorkflow":       "temporal.start_workflow",
+		"RunActivity":         "temporal.run_activity",
+		"HandleSignal":        "temporal.handle_signal",
+		"SignalChildWorkflow": "temporal.signal_child_workflow",
+	} {
+		assert.Equal(t, want, operationName(op))
+	}
+}
@@ -4,6 +4,12 @@
 // Copyright 2016 Datadog, Inc.
 
 // Package gocql provides functions to trace the gocql/gocql package (https://github.com/gocql/gocql).
+//
+// Sessions are traced with an Observer implementing the query, batch and connect observer
+// interfaces of gocql, as set up by CreateTracedSession. The spans of the attempts of a
+// query are tagged with their coordinator node, their attempt index, the decision of the
+// retry policy of the cluster when they fail and, optionally, the token of their partition.
+// The wrapped ClusterConfig, Session, Query and Batch types are deprecated.
 package gocql // import "github.com/DataDog/dd-trace-go/contrib/gocql/gocql/v2"
 
 import (
@@ -0,0 +1,87 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package gocql
+
+import "encoding/binary"
+
+// murmur3Token returns the token of a partition key with the Murmur3Partitioner of
+// Cassandra, which is the h1 half of the 128 bits murmur3 hash of the key. The hash is
+// computed with signed arithmetic, like the Java implementation of Cassandra.
+func murmur3Token(data []byte) int64 {
+	const (
+		c1 int64 = -8663945395140668459 // 0x87c37b91114253d5
+		c2 int64 = 5545529020109919103  // 0x4cf5ad432745937f
+	)
+	length := len(data)
+	var h1, h2, k1, k2 int64
+
+	nBlocks := length / 16
+	for i := 0; i < nBlocks; i++ {
+		k1 = int64(binary.LittleEndian.Uint64(data[i*16:]))
+		k2 = int64(binary.LittleEndian.Uint64(data[i*16+8:]))
+
+		k1 *= c1
+		k1 = rotl(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+
+		h1 = rotl(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+
+		k2 *= c2
+		k2 = rotl(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+
+		h2 = rotl(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	tail := data[nBlocks*16:]
+	k1, k2 = 0, 0
+	for i := len(tail) - 1; i >= 8; i-- {
+		k2 ^= int64(int8(tail[i])) << (8 * (i - 8))
+	}
+	if len(tail) > 8 {
+		k2 *= c2
+		k2 = rotl(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+	}
+	for i := min(len(tail), 8) - 1; i >= 0; i-- {
+		k1 ^= int64(int8(tail[i])) << (8 * i)
+	}
+	if len(tail) > 0 {
+		k1 *= c1
+		k1 = rotl(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+	}
+
+	h1 ^= int64(length)
+	h2 ^= int64(length)
+	h1 += h2
+	h2 += h1
+	h1 = fmix(h1)
+	h2 = fmix(h2)
+	return h1 + h2
+}
+
+func rotl(x int64, r uint8) int64 {
+	// logical right shift, as in Cassandra
+	return (x << r) | int64(uint64(x)>>(64-r))
+}
+
+func fmix(n int64) int64 {
+	n ^= int64(uint64(n) >> 33)
+	n *= -49064778989728563 // 0xff51afd7ed558ccd
+	n ^= int64(uint64(n) >> 33)
+	n *= -4265267296055464877 // 0xc4ceb9fe1a85ec53
+	n ^= int64(uint64(n) >> 33)
+	return n
+}
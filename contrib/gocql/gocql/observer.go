@@ -7,7 +7,11 @@ package gocql
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gocql/gocql"
 
@@ -29,7 +33,12 @@ func CreateTracedSession(cluster *gocql.ClusterConfig, opts ...WrapOption) (*goc
 	if cfg.traceConnect {
 		cluster.ConnectObserver = obs
 	}
-	return cluster.CreateSession()
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, err
+	}
+	obs.session = session
+	return session, nil
 }
 
 // NewObserver creates a new Observer to trace gocql.
@@ -43,6 +52,7 @@ func NewObserver(cluster *gocql.ClusterConfig, opts ...WrapOption) *Observer {
 	return &Observer{
 		cfg:                  cfg,
 		clusterContactPoints: strings.Join(cluster.Hosts, ","),
+		retryPolicy:          cluster.RetryPolicy,
 	}
 }
 
@@ -56,6 +66,8 @@ var (
 type Observer struct {
 	cfg                  *config
 	clusterContactPoints string
+	retryPolicy          gocql.RetryPolicy
+	session              *gocql.Session // set by CreateTracedSession, to compute the token of the queries
 }
 
 // ObserveQuery implements gocql.QueryObserver.
@@ -76,6 +88,10 @@ func (o *Observer) ObserveQuery(ctx context.Context, query gocql.ObservedQuery)
 	}
 	span.SetTag(ext.ResourceName, resource)
 	span.SetTag(ext.CassandraRowCount, query.Rows)
+	o.tagAttempt(ctx, span, query.Host, query.Attempt, query.Start, query.End, query.Err)
+	if o.cfg.traceToken {
+		o.tagToken(ctx, span, query.Host, query.Statement, query.Values)
+	}
 	finishSpan(span, query.Err, p)
 }
 
@@ -91,6 +107,11 @@ func (o *Observer) ObserveBatch(ctx context.Context, batch gocql.ObservedBatch)
 		finishTime:           batch.End,
 	}
 	span := startBatchSpan(ctx, p)
+	o.tagAttempt(ctx, span, batch.Host, batch.Attempt, batch.Start, batch.End, batch.Err)
+	// the routing key of a batch is the one of its first statement
+	if o.cfg.traceToken && len(batch.Statements) > 0 {
+		o.tagToken(ctx, span, batch.Host, batch.Statements[0], batch.Values[0])
+	}
 	finishSpan(span, batch.Err, p)
 }
 
@@ -110,3 +131,117 @@ func (o *Observer) ObserveConnect(connect gocql.ObservedConnect) {
 	span := tracer.StartSpan("cassandra.connect", opts...)
 	finishSpan(span, connect.Err, p)
 }
+
+const (
+	// tagCoordinator is the address of the node coordinating a query.
+	tagCoordinator = "cassandra.coordinator"
+	// tagRack is the rack of the node coordinating a query.
+	tagRack = "cassandra.rack"
+	// tagAttempt is the index of the attempt of a query, starting at zero. Attempts after the
+	// first one are retries or speculative executions.
+	tagAttempt = "cassandra.attempt"
+	// tagRetryDecision is the decision of the retry policy of the cluster for the error of a
+	// failed attempt. It applies if the policy allows another attempt.
+	tagRetryDecision = "cassandra.retry.decision"
+	// tagSpeculative marks the attempts which ran concurrently with another attempt of the same
+	// query, as speculative executions.
+	tagSpeculative = "cassandra.speculative_execution"
+	// tagToken is the token of the partition of a query, when the cluster uses the
+	// Murmur3Partitioner.
+	tagToken = "cassandra.token"
+)
+
+// tagAttempt tags span with the coordinator of an attempt of a query, its index, the
+// decision of the retry policy if it failed, and whether it was a speculative execution.
+func (o *Observer) tagAttempt(ctx context.Context, span *tracer.Span, host *gocql.HostInfo, attempt int, start, end time.Time, err error) {
+	if host != nil {
+		span.SetTag(tagCoordinator, host.ConnectAddressAndPort())
+		if rack := host.Rack(); rack != "" {
+			span.SetTag(tagRack, rack)
+		}
+	}
+	span.SetTag(tagAttempt, attempt)
+	if err != nil && o.retryPolicy != nil && isRetryable(err) {
+		span.SetTag(tagRetryDecision, retryDecision(o.retryPolicy.GetRetryType(err)))
+	}
+	if e, ok := ctx.Value(executionKey{}).(*execution); ok && e.observe(start, end) {
+		span.SetTag(tagSpeculative, true)
+	}
+}
+
+// isRetryable reports whether gocql asks the retry policy whether to retry a query failing
+// with err.
+func isRetryable(err error) bool {
+	return !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.Is(err, gocql.ErrNotFound)
+}
+
+func retryDecision(rt gocql.RetryType) string {
+	switch rt {
+	case gocql.Retry:
+		return "retry"
+	case gocql.RetryNextHost:
+		return "retry_next_host"
+	case gocql.Ignore:
+		return "ignore"
+	case gocql.Rethrow:
+		return "rethrow"
+	default:
+		return "unknown"
+	}
+}
+
+// tagToken tags span with the token of the partition of the given statement. The routing key
+// of the statement is resolved by the session, which prepares the statement the first time
+// unless a token aware host selection policy already did.
+func (o *Observer) tagToken(ctx context.Context, span *tracer.Span, host *gocql.HostInfo, stmt string, values []interface{}) {
+	if o.session == nil || host == nil || !strings.HasSuffix(host.Partitioner(), "Murmur3Partitioner") {
+		return
+	}
+	q := o.session.Query(stmt, values...).WithContext(ctx)
+	defer q.Release()
+	key, err := q.GetRoutingKey()
+	if err != nil || key == nil {
+		return
+	}
+	span.SetTag(tagToken, strconv.FormatInt(murmur3Token(key), 10))
+}
+
+// SetSpeculativeExecutionPolicy sets the speculative execution policy of q, and records the
+// attempts of its executions so that the spans of the attempts running concurrently with
+// another one are tagged as speculative executions. It must be called after the context of
+// q is set with WithContext.
+func SetSpeculativeExecutionPolicy(q *gocql.Query, sp gocql.SpeculativeExecutionPolicy) *gocql.Query {
+	ctx := context.WithValue(q.Context(), executionKey{}, new(execution))
+	return q.SetSpeculativeExecutionPolicy(sp).WithContext(ctx)
+}
+
+type executionKey struct{}
+
+// maxExecutionAttempts is the maximum number of attempts recorded for a query.
+const maxExecutionAttempts = 16
+
+// execution records the attempts of a query.
+type execution struct {
+	mu       sync.Mutex
+	attempts [][2]time.Time
+}
+
+// observe records an attempt of the query, and reports whether it ran concurrently with a
+// previously recorded attempt.
+func (e *execution) observe(start, end time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	concurrent := false
+	for _, a := range e.attempts {
+		if start.Before(a[1]) && a[0].Before(end) {
+			concurrent = true
+			break
+		}
+	}
+	if len(e.attempts) < maxExecutionAttempts {
+		e.attempts = append(e.attempts, [2]time.Time{start, end})
+	}
+	return concurrent
+}
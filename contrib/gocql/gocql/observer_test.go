@@ -7,7 +7,11 @@ package gocql
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gocql/gocql"
 	"github.com/stretchr/testify/assert"
@@ -153,6 +157,9 @@ func TestObserver_Query(t *testing.T) {
 			assert.Equal(t, wantResource, querySpan.Tag(ext.ResourceName))
 			assert.Equal(t, wantService, querySpan.Tag(ext.ServiceName))
 			assert.Equal(t, wantRowCount, querySpan.Tag(ext.CassandraRowCount))
+			assert.Equal(t, cassandraHost, querySpan.Tag(tagCoordinator))
+			assert.Equal(t, float64(0), querySpan.Tag(tagAttempt))
+			assert.Nil(t, querySpan.Tag(tagToken))
 
 			if tc.wantErrTag {
 				assert.NotNil(t, querySpan.Tag(ext.ErrorMsg))
@@ -426,6 +433,78 @@ func TestObserver_Connect(t *testing.T) {
 	}
 }
 
+func TestObserver_Attempts(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	cluster := newCassandraCluster()
+	cluster.Keyspace = "trace"
+	cluster.RetryPolicy = &gocql.SimpleRetryPolicy{NumRetries: 2}
+	sess, err := CreateTracedSession(cluster, WithTraceBatch(false), WithTraceConnect(false), WithTraceToken(true))
+	require.NoError(t, err)
+	defer sess.Close()
+
+	require.NoError(t, sess.Query("SELECT * FROM trace.person WHERE name = ?", "Cassandra").Exec())
+	require.Error(t, sess.Query("SELECT name, age FRM trace.person").Exec())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	query, failed := spans[0], spans[1]
+	assert.Equal(t, strconv.FormatInt(murmur3Token([]byte("Cassandra")), 10), query.Tag(tagToken))
+	assert.Nil(t, query.Tag(tagRetryDecision))
+	// the syntax error is to be retried on the next host, but the cluster has a single host
+	assert.Equal(t, float64(0), failed.Tag(tagAttempt))
+	assert.Equal(t, "retry_next_host", failed.Tag(tagRetryDecision))
+	assert.Nil(t, failed.Tag(tagSpeculative))
+}
+
+func TestSetSpeculativeExecutionPolicy(t *testing.T) {
+	cluster := newCassandraCluster()
+	sess, err := cluster.CreateSession()
+	require.NoError(t, err)
+	defer sess.Close()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	q := sess.Query("SELECT * FROM trace.person").WithContext(ctx)
+	q = SetSpeculativeExecutionPolicy(q, &gocql.SimpleSpeculativeExecution{NumAttempts: 1, TimeoutDelay: time.Millisecond})
+	assert.Equal(t, "value", q.Context().Value(ctxKey{}))
+	assert.IsType(t, new(execution), q.Context().Value(executionKey{}))
+}
+
+func TestExecutionObserve(t *testing.T) {
+	start := time.Now()
+	e := new(execution)
+	assert.False(t, e.observe(start, start.Add(10*time.Millisecond)))
+	assert.False(t, e.observe(start.Add(20*time.Millisecond), start.Add(30*time.Millisecond)))
+	assert.True(t, e.observe(start.Add(5*time.Millisecond), start.Add(15*time.Millisecond)))
+}
+
+func TestRetryDecision(t *testing.T) {
+	assert.Equal(t, "retry", retryDecision(gocql.Retry))
+	assert.Equal(t, "retry_next_host", retryDecision(gocql.RetryNextHost))
+	assert.Equal(t, "ignore", retryDecision(gocql.Ignore))
+	assert.Equal(t, "rethrow", retryDecision(gocql.Rethrow))
+	assert.False(t, isRetryable(gocql.ErrNotFound))
+	assert.False(t, isRetryable(context.Canceled))
+	assert.True(t, isRetryable(errors.New("unavailable")))
+}
+
+func TestMurmur3Token(t *testing.T) {
+	// values computed by the murmur3 partitioner of Cassandra
+	assert.Equal(t, int64(0), murmur3Token(nil))
+	assert.Equal(t, int64(-3758069500696749310), murmur3Token([]byte("hello")))
+	assert.Equal(t, int64(0x2d0338c1ca87d132), murmur3Token([]byte("0123456789012345678")))
+	assert.Equal(t, int64(-9223371632693506265), murmur3Token(mustDecodeHex(t, "00104327529fb645dd00b883ec39ae448bb800000400066a6b00")))
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
 func assertCommonTags(t *testing.T, span *mocktracer.Span) {
 	t.Helper()
 
@@ -23,6 +23,7 @@ type config struct {
 	customTags                           map[string]interface{}
 	clusterTagLegacyMode                 bool
 	traceQuery, traceBatch, traceConnect bool
+	traceToken                           bool
 }
 
 // WrapOption describes options for the Cassandra integration.
@@ -166,3 +167,14 @@ func WithTraceConnect(enabled bool) WrapOptionFn {
 		cfg.traceConnect = enabled
 	}
 }
+
+// WithTraceToken will enable tagging the spans of queries and batches with the token of their
+// partition (default is false). Computing the token requires the routing key of the
+// statements, which gocql resolves by preparing them once, unless the cluster uses a token
+// aware host selection policy which already did.
+// This option only takes effect in CreateTracedSession.
+func WithTraceToken(enabled bool) WrapOptionFn {
+	return func(cfg *config) {
+		cfg.traceToken = enabled
+	}
+}
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package groupcache_test
+
+import (
+	"context"
+	"log"
+
+	groupcachetrace "github.com/DataDog/dd-trace-go/contrib/golang/groupcache/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+
+	"github.com/golang/groupcache"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	thumbnails := groupcachetrace.NewGroup("thumbnails", 64<<20, groupcache.GetterFunc(
+		func(ctx context.Context, key string, dest groupcache.Sink) error {
+			// Load the thumbnail from its source.
+			return dest.SetBytes([]byte(key))
+		}), cachetrace.WithMetrics(true))
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "web.request")
+	defer span.Finish()
+
+	// The lookup is recorded as a "cache.hit" or "cache.miss" event of the span.
+	var data []byte
+	if err := thumbnails.Get(ctx, "big-file.jpg", groupcache.AllocatingByteSliceSink(&data)); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,95 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package groupcache provides functions to trace the golang/groupcache package (https://github.com/golang/groupcache).
+//
+// The hits and misses of the lookups made with the Get method of a Group are recorded as span
+// events on the span found in their context, so that the effectiveness of the cache can be
+// analyzed per endpoint. A lookup is a miss when the value is loaded with the getter of the
+// group by this call, and a hit when it is served by the caches of the group or by a peer.
+// Hits, misses and evictions can also be sent as statsd metrics.
+package groupcache // import "github.com/DataDog/dd-trace-go/contrib/golang/groupcache/v2"
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+
+	"github.com/golang/groupcache"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageGolangGroupcache)
+}
+
+// Option describes options for the groupcache integration, set with the options of the
+// cachetrace package, e.g. cachetrace.WithName.
+type Option = cachetrace.Option
+
+// Group is a groupcache.Group recording the hits and misses of its lookups.
+type Group struct {
+	*groupcache.Group
+	rec       *cachetrace.Recorder
+	metrics   bool
+	evictions atomic.Int64 // evictions already recorded
+}
+
+// loadKey is the key of the context value set by Get to know whether the getter was called.
+type loadKey struct{}
+
+// NewGroup creates a coordinated group-aware Getter from a Getter, as groupcache.NewGroup does,
+// and returns the Group recording its lookups.
+func NewGroup(name string, cacheBytes int64, getter groupcache.Getter, opts ...Option) *Group {
+	cfg := cachetrace.NewConfig("groupcache", name, opts...)
+	instr.Logger().Debug("contrib/golang/groupcache: Configuring Group: %#v", cfg)
+	rec := cachetrace.NewRecorder(instr, cfg)
+	g := &Group{rec: rec, metrics: rec.MetricsEnabled()}
+	g.Group = groupcache.NewGroup(name, cacheBytes, groupcache.GetterFunc(func(ctx context.Context, key string, dest groupcache.Sink) error {
+		if loaded, ok := ctx.Value(loadKey{}).(*atomic.Bool); ok {
+			loaded.Store(true)
+		}
+		return getter.Get(ctx, key, dest)
+	}))
+	return g
+}
+
+// Get populates dest with the value of key, recording the lookup as a hit or a miss on the
+// span found in ctx. Lookups waiting for the value loaded by a concurrent lookup of the same
+// key are hits.
+func (g *Group) Get(ctx context.Context, key string, dest groupcache.Sink) error {
+	loaded := new(atomic.Bool)
+	err := g.Group.Get(context.WithValue(ctx, loadKey{}, loaded), key, dest)
+	if g.metrics {
+		g.recordEvictions()
+	}
+	if err != nil {
+		return err
+	}
+	if loaded.Load() {
+		g.rec.Miss(ctx)
+	} else {
+		g.rec.Hit(ctx)
+	}
+	return nil
+}
+
+// recordEvictions records the evictions of the caches of the group since the last call.
+func (g *Group) recordEvictions() {
+	n := g.CacheStats(groupcache.MainCache).Evictions + g.CacheStats(groupcache.HotCache).Evictions
+	for {
+		prev := g.evictions.Load()
+		if n <= prev {
+			return
+		}
+		if g.evictions.CompareAndSwap(prev, n) {
+			g.rec.Evicted("capacity", n-prev)
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package groupcache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/cachetrace"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+
+	"github.com/golang/groupcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withStatsdClient(s instrumentation.StatsdClient) Option {
+	return func(c *cachetrace.Config) {
+		c.Metrics = true
+		c.Statsd = s
+	}
+}
+
+var echo = groupcache.GetterFunc(func(_ context.Context, key string, dest groupcache.Sink) error {
+	return dest.SetString(key)
+})
+
+func TestGet(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	statsd := testutils.NewMockStatsdClient()
+	g := NewGroup(t.Name(), 1<<20, echo, withStatsdClient(statsd))
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "request")
+	var v string
+	require.NoError(t, g.Get(ctx, "a", groupcache.StringSink(&v)))
+	assert.Equal(t, "a", v)
+	require.NoError(t, g.Get(ctx, "a", groupcache.StringSink(&v)))
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, cachetrace.EventMiss, events[0].Name)
+	assert.Equal(t, cachetrace.EventHit, events[1].Name)
+	events[0].AssertAttributes(t, map[string]any{cachetrace.AttributeName: t.Name(), cachetrace.AttributeSystem: "groupcache"})
+
+	counts := statsd.Counts()
+	assert.Equal(t, int64(1), counts[cachetrace.MetricHits])
+	assert.Equal(t, int64(1), counts[cachetrace.MetricMisses])
+}
+
+func TestEvictions(t *testing.T) {
+	statsd := testutils.NewMockStatsdClient()
+	// the cache is only large enough for a few entries
+	g := NewGroup(t.Name(), 64, echo, withStatsdClient(statsd))
+
+	var v string
+	for i := 0; i < 10; i++ {
+		require.NoError(t, g.Get(context.Background(), "key"+strconv.Itoa(i), groupcache.StringSink(&v)))
+	}
+	evictions := g.CacheStats(groupcache.MainCache).Evictions
+	assert.NotZero(t, evictions)
+	assert.Equal(t, evictions, statsd.Counts()[cachetrace.MetricEvictions])
+}
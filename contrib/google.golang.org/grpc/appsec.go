@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/grpcsec"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/actions"
@@ -80,7 +81,7 @@ func appsecUnaryHandlerMiddleware(method string, span *tracer.Span, handler grpc
 }
 
 // StreamHandler wrapper to use when AppSec is enabled to monitor its execution.
-func appsecStreamHandlerMiddleware(method string, span *tracer.Span, handler grpc.StreamHandler) grpc.StreamHandler {
+func appsecStreamHandlerMiddleware(method string, span *tracer.Span, handler grpc.StreamHandler, cfg *config) grpc.StreamHandler {
 	return func(srv any, stream grpc.ServerStream) (rpcErr error) {
 		ctx := stream.Context()
 		md, _ := metadata.FromIncomingContext(ctx)
@@ -120,6 +121,8 @@ func appsecStreamHandlerMiddleware(method string, span *tracer.Span, handler grp
 			ctx:              ctx,
 			action:           blockAtomic,
 			rpcErr:           &rpcErr,
+			maxMessages:      cfg.appsecMaxStreamMessages,
+			maxMessageSize:   cfg.appsecMaxMessageSize,
 		})
 	}
 }
@@ -130,26 +133,56 @@ type appsecServerStream struct {
 	ctx              context.Context
 	action           *atomic.Pointer[actions.BlockGRPC]
 	rpcErr           *error
+	// maxMessages and maxMessageSize limit the messages inspected by AppSec, see
+	// WithAppSecStreamMessages.
+	maxMessages    int
+	maxMessageSize int
+	received, sent atomic.Int64
 }
 
 // RecvMsg implements grpc.ServerStream interface method to monitor its
 // execution with AppSec.
 func (ss *appsecServerStream) RecvMsg(msg any) (err error) {
-	defer func() {
+	if err = ss.ServerStream.RecvMsg(msg); err != nil {
+		return err
+	}
+	if ss.inspect(msg, &ss.received) {
 		if _ = grpcsec.MonitorRequestMessage(ss.ctx, msg); applyAction(ss.action, ss.rpcErr) {
-			err = *ss.rpcErr
+			return *ss.rpcErr
 		}
-	}()
-	return ss.ServerStream.RecvMsg(msg)
+	}
+	return nil
 }
 
 func (ss *appsecServerStream) SendMsg(msg any) error {
-	if _ = grpcsec.MonitorResponseMessage(ss.ctx, msg); applyAction(ss.action, ss.rpcErr) {
-		return *ss.rpcErr
+	if ss.inspect(msg, &ss.sent) {
+		if _ = grpcsec.MonitorResponseMessage(ss.ctx, msg); applyAction(ss.action, ss.rpcErr) {
+			return *ss.rpcErr
+		}
 	}
 	return ss.ServerStream.SendMsg(msg)
 }
 
+// inspect reports whether the message msg, counted by count, is inspected by AppSec. The first
+// maxMessages messages of each direction are inspected, then one out of maxMessages. Messages
+// larger than maxMessageSize are never inspected.
+func (ss *appsecServerStream) inspect(msg any, count *atomic.Int64) bool {
+	n := count.Add(1)
+	if ss.maxMessageSize > 0 {
+		if p, ok := msg.(proto.Message); ok && proto.Size(p) > ss.maxMessageSize {
+			return false
+		}
+	}
+	switch {
+	case ss.maxMessages < 0:
+		return true
+	case ss.maxMessages == 0:
+		return false
+	default:
+		return n <= int64(ss.maxMessages) || n%int64(ss.maxMessages) == 0
+	}
+}
+
 func (ss *appsecServerStream) Context() context.Context {
 	return ss.ctx
 }
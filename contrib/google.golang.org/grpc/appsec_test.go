@@ -12,6 +12,7 @@ import (
 	"io"
 	"net"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/DataDog/dd-trace-go/instrumentation/testutils/grpc/v2/fixturepb"
@@ -135,6 +136,47 @@ func TestAppSec(t *testing.T) {
 }
 
 // Test that http blocking works by using custom rules/rules data
+func TestAppSecStreamMessagesLimits(t *testing.T) {
+	inspected := func(ss *appsecServerStream, msgs ...any) []int {
+		var count atomic.Int64
+		var ids []int
+		for i, msg := range msgs {
+			if ss.inspect(msg, &count) {
+				ids = append(ids, i+1)
+			}
+		}
+		return ids
+	}
+	msgs := func(n int) []any {
+		msgs := make([]any, n)
+		for i := range msgs {
+			msgs[i] = &fixturepb.FixtureRequest{Name: "pass"}
+		}
+		return msgs
+	}
+
+	t.Run("sampling", func(t *testing.T) {
+		ss := &appsecServerStream{maxMessages: 3, maxMessageSize: defaultAppSecMaxMessageSize}
+		assert.Equal(t, []int{1, 2, 3, 6, 9}, inspected(ss, msgs(10)...))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		ss := &appsecServerStream{maxMessages: -1}
+		assert.Len(t, inspected(ss, msgs(10)...), 10)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ss := &appsecServerStream{maxMessages: 0}
+		assert.Empty(t, inspected(ss, msgs(10)...))
+	})
+
+	t.Run("size", func(t *testing.T) {
+		ss := &appsecServerStream{maxMessages: -1, maxMessageSize: 16}
+		large := &fixturepb.FixtureRequest{Name: strings.Repeat("a", 32)}
+		assert.Equal(t, []int{1, 3}, inspected(ss, msgs(1)[0], large, msgs(1)[0]))
+	})
+}
+
 func TestBlocking(t *testing.T) {
 	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/blocking.json")
 	testutils.StartAppSec(t)
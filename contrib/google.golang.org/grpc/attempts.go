@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type callAttemptsKey struct{}
+
+// callAttempts tracks the attempts of a logical client call, which the gRPC client makes when
+// it retries or hedges the call. The client interceptors track the logical call, while the client
+// stats handler is notified of each attempt.
+type callAttempts struct {
+	mu       sync.Mutex
+	count    int        // guarded by mu
+	lastCode codes.Code // guarded by mu
+	ended    bool       // guarded by mu
+}
+
+// withCallAttempts returns a context tracking the attempts of the call made with it.
+func withCallAttempts(ctx context.Context) (context.Context, *callAttempts) {
+	a := new(callAttempts)
+	return context.WithValue(ctx, callAttemptsKey{}, a), a
+}
+
+// callAttemptsFromContext returns the attempts of the call made with ctx, if tracked.
+func callAttemptsFromContext(ctx context.Context) (*callAttempts, bool) {
+	a, ok := ctx.Value(callAttemptsKey{}).(*callAttempts)
+	return a, ok
+}
+
+// start records the start of an attempt and tags its span with its number and the status
+// of the last attempt which ended before it, if any.
+func (a *callAttempts) start(span *tracer.Span) {
+	a.mu.Lock()
+	a.count++
+	attempt, lastCode, ended := a.count, a.lastCode, a.ended
+	a.mu.Unlock()
+	span.SetTag(tagAttempt, attempt)
+	if ended {
+		span.SetTag(tagAttemptPreviousCode, lastCode.String())
+	}
+}
+
+// end records the status of an attempt.
+func (a *callAttempts) end(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastCode = status.Code(err)
+	a.ended = true
+}
+
+// setTags tags the span of the logical call with its number of attempts, if any were made.
+func (a *callAttempts) setTags(span *tracer.Span) {
+	a.mu.Lock()
+	count := a.count
+	a.mu.Unlock()
+	if count > 0 {
+		span.SetTag(tagAttempts, count)
+	}
+}
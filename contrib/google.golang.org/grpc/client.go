@@ -23,6 +23,7 @@ type clientStream struct {
 	ctx    context.Context
 	cfg    *config
 	method string
+	stats  *streamStats
 }
 
 func (cs *clientStream) Context() context.Context {
@@ -30,7 +31,8 @@ func (cs *clientStream) Context() context.Context {
 }
 
 func (cs *clientStream) RecvMsg(m interface{}) (err error) {
-	if _, ok := cs.cfg.untracedMethods[cs.method]; cs.cfg.traceStreamMessages && !ok {
+	size := -1
+	if cs.cfg.traceStreamMessages && !cs.cfg.untraced(cs.method) {
 		span, _ := startSpanFromContext(
 			cs.Context(),
 			cs.method,
@@ -42,14 +44,21 @@ func (cs *clientStream) RecvMsg(m interface{}) (err error) {
 		if p, ok := peer.FromContext(cs.Context()); ok {
 			setSpanTargetFromPeer(span, *p)
 		}
-		defer func() { finishWithError(span, err, cs.cfg) }()
+		defer func() {
+			withMessageSize(span, size)
+			finishWithError(span, err, cs.cfg)
+		}()
 	}
 	err = cs.ClientStream.RecvMsg(m)
+	if err == nil {
+		size = cs.stats.recordReceived(m)
+	}
 	return err
 }
 
 func (cs *clientStream) SendMsg(m interface{}) (err error) {
-	if _, ok := cs.cfg.untracedMethods[cs.method]; cs.cfg.traceStreamMessages && !ok {
+	size := -1
+	if cs.cfg.traceStreamMessages && !cs.cfg.untraced(cs.method) {
 		span, _ := startSpanFromContext(
 			cs.Context(),
 			cs.method,
@@ -61,9 +70,15 @@ func (cs *clientStream) SendMsg(m interface{}) (err error) {
 		if p, ok := peer.FromContext(cs.Context()); ok {
 			setSpanTargetFromPeer(span, *p)
 		}
-		defer func() { finishWithError(span, err, cs.cfg) }()
+		defer func() {
+			withMessageSize(span, size)
+			finishWithError(span, err, cs.cfg)
+		}()
 	}
 	err = cs.ClientStream.SendMsg(m)
+	if err == nil {
+		size = cs.stats.recordSent(m)
+	}
 	return err
 }
 
@@ -88,8 +103,11 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 				methodKind = methodKindClientStream
 			}
 		}
-		var stream grpc.ClientStream
-		if _, ok := cfg.untracedMethods[method]; cfg.traceStreamCalls && !ok {
+		var (
+			stream grpc.ClientStream
+			stats  *streamStats
+		)
+		if cfg.traceStreamCalls && !cfg.untraced(method) {
 			var (
 				span *tracer.Span
 				err  error
@@ -111,8 +129,15 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 				setSpanTargetFromPeer(span, *p)
 			}
 
+			stats = newStreamStats(cfg, span)
+			attempts, _ := callAttemptsFromContext(stream.Context())
 			go func() {
 				<-stream.Context().Done()
+				stats.setTags()
+				if attempts != nil {
+					// streams may be retried until the first response is received
+					attempts.setTags(span)
+				}
 				finishWithError(span, stream.Context().Err(), cfg)
 			}()
 		} else {
@@ -134,6 +159,7 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 			cfg:          cfg,
 			method:       method,
 			ctx:          ctx,
+			stats:        stats,
 		}, nil
 	}
 }
@@ -148,7 +174,7 @@ func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
 	}
 	instr.Logger().Debug("contrib/google.golang.org/grpc: Configuring UnaryClientInterceptor: %#v", cfg)
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		if _, ok := cfg.untracedMethods[method]; ok {
+		if cfg.untraced(method) {
 			return invoker(ctx, method, req, reply, cc, opts...)
 		}
 		span, _, err := doClientRequest(ctx, cfg, method, methodKindUnary, cc, opts,
@@ -188,10 +214,11 @@ func doClientRequest(
 	var p peer.Peer
 	opts = append(opts, grpc.Peer(&p))
 
-	handlerCtx := injectSpanIntoContext(ctx)
+	handlerCtx, attempts := withCallAttempts(injectSpanIntoContext(ctx))
 	err := handler(handlerCtx, opts)
 
 	setSpanTargetFromPeer(span, p)
+	attempts.setTags(span)
 
 	return span, ctx, err
 }
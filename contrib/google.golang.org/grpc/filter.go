@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// envIgnoredMethods is the name of the env var holding the comma-separated list of the full
+// methods of the calls that aren't traced. The methods prefixed with "!" are traced even when
+// ignored by another pattern.
+const envIgnoredMethods = "DD_TRACE_GRPC_IGNORED_METHODS"
+
+// healthCheckMethods are the methods ignored by WithHealthCheckFilter: the health checks and the
+// server reflection calls.
+var healthCheckMethods = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.reflection.v1.ServerReflection/*",
+	"/grpc.reflection.v1alpha.ServerReflection/*",
+}
+
+// methodFilter holds the patterns of the methods of the calls that aren't traced. A pattern is
+// either a full method, such as "/grpc.health.v1.Health/Check", or a prefix followed by "*",
+// such as "/grpc.reflection.v1.ServerReflection/*".
+type methodFilter struct {
+	ignored []string
+	allowed []string
+}
+
+// parseMethodFilter parses the comma-separated list of patterns of the DD_TRACE_GRPC_IGNORED_METHODS
+// env var.
+func parseMethodFilter(v string) methodFilter {
+	var f methodFilter
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimSpace(p)
+		if allowed, ok := strings.CutPrefix(p, "!"); ok {
+			if allowed = strings.TrimSpace(allowed); allowed != "" {
+				f.allowed = append(f.allowed, allowed)
+			}
+		} else if p != "" {
+			f.ignored = append(f.ignored, p)
+		}
+	}
+	return f
+}
+
+func defaultMethodFilter() methodFilter {
+	return parseMethodFilter(os.Getenv(envIgnoredMethods))
+}
+
+// ignores reports whether the calls of the given full method aren't traced.
+func (f *methodFilter) ignores(method string) bool {
+	return matchMethod(f.ignored, method) && !matchMethod(f.allowed, method)
+}
+
+func matchMethod(patterns []string, method string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+		} else if p == method {
+			return true
+		}
+	}
+	return false
+}
+
+// untraced reports whether the calls of the given full method aren't traced, either because
+// the method was given to WithUntracedMethods or because it's filtered out.
+func (cfg *config) untraced(method string) bool {
+	if _, ok := cfg.untracedMethods[method]; ok {
+		return true
+	}
+	return cfg.methodFilter.ignores(method)
+}
+
+// untracedRPCKey marks the context of an RPC which isn't traced by a stats handler, so that the
+// handler doesn't finish the span of the caller found in the context.
+type untracedRPCKey struct{}
+
+func withUntracedRPC(ctx context.Context) context.Context {
+	return context.WithValue(ctx, untracedRPCKey{}, true)
+}
+
+func isUntracedRPC(ctx context.Context) bool {
+	return ctx.Value(untracedRPCKey{}) != nil
+}
@@ -294,6 +294,54 @@ func TestStreaming(t *testing.T) {
 			len(spans))
 		checkSpans(t, rig, spans)
 	})
+
+	t.Run("MessageEvents", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		rig, err := newRig(true, WithService("grpc"), WithStreamMessageEvents(true))
+		require.NoError(t, err, "error setting up rig")
+		defer func() { assert.NoError(t, rig.Close()) }()
+
+		span, ctx := tracer.StartSpanFromContext(context.Background(), "a",
+			tracer.ServiceName("b"),
+			tracer.ResourceName("c"))
+
+		runPings(t, ctx, rig.client)
+
+		span.Finish()
+
+		waitForSpans(mt, 13)
+
+		spans := mt.FinishedSpans()
+		checkSpans(t, rig, spans)
+		var calls, sized int
+		for _, span := range spans {
+			switch span.OperationName() {
+			case "grpc.client", "grpc.server":
+				calls++
+				assert.Equal(t, float64(2), span.Tag(tagStreamMessagesSent))
+				assert.Equal(t, float64(2), span.Tag(tagStreamMessagesReceived))
+				assert.Greater(t, span.Tag(tagStreamBytesSent), float64(0))
+				assert.Greater(t, span.Tag(tagStreamBytesReceived), float64(0))
+				events := span.Events()
+				require.Len(t, events, 4)
+				for _, e := range events {
+					assert.Equal(t, "message", e.Name)
+					assert.Contains(t, []any{"SENT", "RECEIVED"}, e.Attributes["message.type"])
+					assert.Contains(t, e.Attributes, "message.uncompressed_size")
+				}
+			case "grpc.message":
+				if size := span.Tag(tagMessageSize); size != nil {
+					sized++
+					assert.Greater(t, size, float64(0))
+				}
+			}
+		}
+		assert.Equal(t, 2, calls)
+		// the empty recv closing the stream has no message
+		assert.Equal(t, 8, sized)
+	})
 }
 
 func TestSpanTree(t *testing.T) {
@@ -759,6 +807,54 @@ func TestUntracedMethods(t *testing.T) {
 	})
 }
 
+func TestIgnoredMethods(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		env  string
+		opts []Option
+		exp  int
+	}{
+		{name: "default", exp: 2},
+		{name: "option", opts: []Option{WithIgnoredMethods("/grpc.Fixture/*")}, exp: 0},
+		{name: "health-check-filter", opts: []Option{WithHealthCheckFilter()}, exp: 2},
+		{name: "env", env: "/grpc.Fixture/Ping", exp: 0},
+		{name: "env-allowed", env: "/grpc.Fixture/*, !/grpc.Fixture/Ping", exp: 2},
+		{name: "env-allowed-option", env: "!/grpc.Fixture/Ping", opts: []Option{WithIgnoredMethods("/grpc.Fixture/*")}, exp: 2},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(envIgnoredMethods, c.env)
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			rig, err := newRig(true, c.opts...)
+			require.NoError(t, err)
+			defer rig.Close()
+			resp, err := rig.client.Ping(context.Background(), &fixturepb.FixtureRequest{Name: "pass"})
+			require.NoError(t, err)
+			assert.Equal(t, "passed", resp.Message)
+
+			assert.Len(t, mt.FinishedSpans(), c.exp)
+		})
+	}
+}
+
+func TestMethodFilter(t *testing.T) {
+	f := parseMethodFilter(" /a.Service/Get ,/b.Service/*,!/b.Service/Keep, ,!")
+	assert.Equal(t, []string{"/a.Service/Get", "/b.Service/*"}, f.ignored)
+	assert.Equal(t, []string{"/b.Service/Keep"}, f.allowed)
+	assert.True(t, f.ignores("/a.Service/Get"))
+	assert.False(t, f.ignores("/a.Service/GetAll"))
+	assert.True(t, f.ignores("/b.Service/Delete"))
+	assert.False(t, f.ignores("/b.Service/Keep"))
+
+	f = methodFilter{ignored: healthCheckMethods}
+	assert.True(t, f.ignores("/grpc.health.v1.Health/Check"))
+	assert.False(t, f.ignores("/grpc.health.v1.Health/Watch"))
+	assert.True(t, f.ignores("/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"))
+	assert.True(t, f.ignores("/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"))
+	assert.False(t, f.ignores("/grpc.Fixture/Ping"))
+}
+
 func TestIgnoredMetadata(t *testing.T) {
 	mt := mocktracer.Start()
 	defer mt.Stop()
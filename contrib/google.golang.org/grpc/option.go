@@ -58,20 +58,29 @@ type config struct {
 	nonErrorCodes       map[codes.Code]bool
 	traceStreamCalls    bool
 	traceStreamMessages bool
-	noDebugStack        bool
-	untracedMethods     map[string]struct{}
-	withMetadataTags    bool
-	ignoredMetadata     map[string]struct{}
-	withRequestTags     bool
-	withErrorDetailTags bool
-	spanOpts            []tracer.StartSpanOption
-	tags                map[string]interface{}
+	streamMessageEvents bool
+	// appsecMaxStreamMessages and appsecMaxMessageSize limit the stream messages inspected by
+	// AppSec, see WithAppSecStreamMessages.
+	appsecMaxStreamMessages int
+	appsecMaxMessageSize    int
+	noDebugStack            bool
+	untracedMethods         map[string]struct{}
+	methodFilter            methodFilter
+	withMetadataTags        bool
+	ignoredMetadata         map[string]struct{}
+	withRequestTags         bool
+	withErrorDetailTags     bool
+	spanOpts                []tracer.StartSpanOption
+	tags                    map[string]interface{}
 }
 
 func defaults(cfg *config) {
 	cfg.traceStreamCalls = true
 	cfg.traceStreamMessages = true
 	cfg.nonErrorCodes = map[codes.Code]bool{codes.Canceled: true}
+	cfg.methodFilter = defaultMethodFilter()
+	cfg.appsecMaxStreamMessages = defaultAppSecMaxStreamMessages
+	cfg.appsecMaxMessageSize = defaultAppSecMaxMessageSize
 	if rate := instr.AnalyticsRate(false); !math.IsNaN(rate) {
 		cfg.spanOpts = append(cfg.spanOpts, tracer.AnalyticsRate(rate))
 	}
@@ -123,6 +132,39 @@ func WithStreamMessages(enabled bool) OptionFn {
 	}
 }
 
+// WithStreamMessageEvents enables or disables recording of streaming messages as "message" events
+// on the span of the stream call, with their type, sequence number and size. The span of the call
+// is also tagged with the number of messages and bytes sent and received, and message spans with
+// the size of their message. Message sizes are only known for protobuf messages. Only the first
+// 1000 messages of a stream are recorded as events. This option does not apply to the stats handler.
+func WithStreamMessageEvents(enabled bool) OptionFn {
+	return func(cfg *config) {
+		cfg.streamMessageEvents = enabled
+	}
+}
+
+const (
+	// defaultAppSecMaxStreamMessages is the default number of messages of each direction of a
+	// stream fully inspected by AppSec.
+	defaultAppSecMaxStreamMessages = 100
+	// defaultAppSecMaxMessageSize is the default size above which stream messages aren't inspected
+	// by AppSec.
+	defaultAppSecMaxMessageSize = 1 << 20
+)
+
+// WithAppSecStreamMessages limits the messages of server streams inspected by AppSec when it is
+// enabled. The first maxMessages messages received and sent on a stream are inspected, 100 by
+// default, then only one out of maxMessages, so that long-lived streams keep being monitored at a
+// bounded cost. No stream message is inspected when maxMessages is zero, and all of them are when
+// it is negative. The protobuf messages larger than maxSize bytes, 1MiB by default, are never
+// inspected, unless maxSize is zero or negative. The messages of unary calls are always inspected.
+func WithAppSecStreamMessages(maxMessages, maxSize int) OptionFn {
+	return func(cfg *config) {
+		cfg.appsecMaxStreamMessages = maxMessages
+		cfg.appsecMaxMessageSize = maxSize
+	}
+}
+
 // NoDebugStack disables debug stacks for traces with errors. This is useful in situations
 // where errors are frequent, and the overhead of calling debug.Stack may affect performance.
 func NoDebugStack() OptionFn {
@@ -173,6 +215,25 @@ func WithUntracedMethods(ms ...string) OptionFn {
 	}
 }
 
+// WithIgnoredMethods specifies patterns of the full methods to be ignored by the server side and
+// client side interceptors and stats handlers, in addition to the methods of the
+// DD_TRACE_GRPC_IGNORED_METHODS environment variable. A pattern is either a full method, such as
+// "/grpc.health.v1.Health/Check", or a prefix followed by "*", such as "/grpc.reflection.*". When
+// a request's full method matches a pattern, no spans will be created, unless the method is
+// allowed with a pattern prefixed with "!" in DD_TRACE_GRPC_IGNORED_METHODS.
+func WithIgnoredMethods(patterns ...string) OptionFn {
+	return func(cfg *config) {
+		cfg.methodFilter.ignored = append(cfg.methodFilter.ignored, patterns...)
+	}
+}
+
+// WithHealthCheckFilter ignores the health checks (/grpc.health.v1.Health/Check) and the server
+// reflection calls, which usually dominate the number of spans of a service without value. See
+// WithIgnoredMethods.
+func WithHealthCheckFilter() OptionFn {
+	return WithIgnoredMethods(healthCheckMethods...)
+}
+
 // WithMetadataTags specifies whether gRPC metadata should be added to spans as tags.
 func WithMetadataTags() OptionFn {
 	return func(cfg *config) {
@@ -22,6 +22,7 @@ type serverStream struct {
 	cfg    *config
 	method string
 	ctx    context.Context
+	stats  *streamStats
 }
 
 // Context returns the ServerStream Context.
@@ -37,7 +38,8 @@ func (ss *serverStream) Context() context.Context {
 }
 
 func (ss *serverStream) RecvMsg(m interface{}) (err error) {
-	_, um := ss.cfg.untracedMethods[ss.method]
+	size := -1
+	um := ss.cfg.untraced(ss.method)
 	if ss.cfg.traceStreamMessages && !um {
 		span, _ := startSpanFromContext(
 			ss.ctx,
@@ -50,15 +52,20 @@ func (ss *serverStream) RecvMsg(m interface{}) (err error) {
 		defer func() {
 			withMetadataTags(ss.ctx, ss.cfg, span)
 			withRequestTags(ss.cfg, m, span)
+			withMessageSize(span, size)
 			finishWithError(span, err, ss.cfg)
 		}()
 	}
 	err = ss.ServerStream.RecvMsg(m)
+	if err == nil {
+		size = ss.stats.recordReceived(m)
+	}
 	return err
 }
 
 func (ss *serverStream) SendMsg(m interface{}) (err error) {
-	_, um := ss.cfg.untracedMethods[ss.method]
+	size := -1
+	um := ss.cfg.untraced(ss.method)
 	if ss.cfg.traceStreamMessages && !um {
 		span, _ := startSpanFromContext(
 			ss.ctx,
@@ -68,9 +75,15 @@ func (ss *serverStream) SendMsg(m interface{}) (err error) {
 			ss.cfg.startSpanOptions(tracer.Measured())...,
 		)
 		span.SetTag(ext.Component, componentName)
-		defer func() { finishWithError(span, err, ss.cfg) }()
+		defer func() {
+			withMessageSize(span, size)
+			finishWithError(span, err, ss.cfg)
+		}()
 	}
 	err = ss.ServerStream.SendMsg(m)
+	if err == nil {
+		size = ss.stats.recordSent(m)
+	}
 	return err
 }
 
@@ -84,8 +97,9 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 	instr.Logger().Debug("contrib/google.golang.org/grpc: Configuring StreamServerInterceptor: %#v", cfg)
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 		ctx := ss.Context()
+		var stats *streamStats
 		// if we've enabled call tracing, create a span
-		_, um := cfg.untracedMethods[info.FullMethod]
+		um := cfg.untraced(info.FullMethod)
 		if cfg.traceStreamCalls && !um {
 			var span *tracer.Span
 			span, ctx = startSpanFromContext(
@@ -105,9 +119,13 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 			case info.IsClientStream:
 				span.SetTag(tagMethodKind, methodKindClientStream)
 			}
-			defer func() { finishWithError(span, err, cfg) }()
+			stats = newStreamStats(cfg, span)
+			defer func() {
+				stats.setTags()
+				finishWithError(span, err, cfg)
+			}()
 			if instr.AppSecEnabled() {
-				handler = appsecStreamHandlerMiddleware(info.FullMethod, span, handler)
+				handler = appsecStreamHandlerMiddleware(info.FullMethod, span, handler, cfg)
 			}
 		}
 
@@ -118,6 +136,7 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 			cfg:          cfg,
 			method:       info.FullMethod,
 			ctx:          ctx,
+			stats:        stats,
 		})
 	}
 }
@@ -131,7 +150,7 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 	}
 	instr.Logger().Debug("contrib/google.golang.org/grpc: Configuring UnaryServerInterceptor: %#v", cfg)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		_, um := cfg.untracedMethods[info.FullMethod]
+		um := cfg.untraced(info.FullMethod)
 		if um {
 			return handler(ctx, req)
 		}
@@ -167,6 +186,13 @@ func withMetadataTags(ctx context.Context, cfg *config, span *tracer.Span) {
 	}
 }
 
+// withMessageSize tags the span of a streaming message with its size, if known.
+func withMessageSize(span *tracer.Span, size int) {
+	if size >= 0 {
+		span.SetTag(tagMessageSize, size)
+	}
+}
+
 func withRequestTags(cfg *config, req interface{}, span *tracer.Span) {
 	if cfg.withRequestTags {
 		if p, ok := req.(proto.Message); ok {
@@ -29,27 +29,44 @@ func NewClientStatsHandler(opts ...Option) stats.Handler {
 
 type clientStatsHandler struct{ cfg *config }
 
-// TagRPC starts a new span for the initiated RPC request.
+// TagRPC starts a new span for the initiated RPC request. The gRPC client calls it for each
+// attempt of a call it retries or hedges. When the call is also traced by a client interceptor,
+// the span of each attempt is a child of the span of the call, tagged with the attempt number
+// and the status of the previous attempt.
 func (h *clientStatsHandler) TagRPC(ctx context.Context, rti *stats.RPCTagInfo) context.Context {
+	if h.cfg.untraced(rti.FullMethodName) {
+		return withUntracedRPC(ctx)
+	}
 	spanOpts := append([]tracer.StartSpanOption{tracer.Tag(ext.SpanKind, ext.SpanKindClient)}, h.cfg.spanOpts...)
-	_, ctx = startSpanFromContext(
+	var span *tracer.Span
+	span, ctx = startSpanFromContext(
 		ctx,
 		rti.FullMethodName,
 		h.cfg.spanName,
 		h.cfg.serviceName.String(),
 		spanOpts...,
 	)
+	if attempts, ok := callAttemptsFromContext(ctx); ok {
+		attempts.start(span)
+	}
 	ctx = injectSpanIntoContext(ctx)
 	return ctx
 }
 
 // HandleRPC processes the RPC ending event by finishing the span from the context.
 func (h *clientStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	if isUntracedRPC(ctx) {
+		return
+	}
 	span, ok := tracer.SpanFromContext(ctx)
 	if !ok {
 		return
 	}
 	switch rs := rs.(type) {
+	case *stats.Begin:
+		if rs.IsTransparentRetryAttempt {
+			span.SetTag(tagAttemptTransparent, true)
+		}
 	case *stats.OutHeader:
 		host, port, err := net.SplitHostPort(rs.RemoteAddr.String())
 		if err == nil {
@@ -59,6 +76,9 @@ func (h *clientStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
 			span.SetTag(ext.TargetPort, port)
 		}
 	case *stats.End:
+		if attempts, ok := callAttemptsFromContext(ctx); ok {
+			attempts.end(rs.Error)
+		}
 		finishWithError(span, rs.Error, h.cfg)
 	}
 }
@@ -7,6 +7,7 @@ package grpc
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 
 	"github.com/DataDog/dd-trace-go/instrumentation/testutils/grpc/v2/fixturepb"
@@ -15,9 +16,11 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
 )
 
 func TestClientStatsHandler(t *testing.T) {
@@ -61,6 +64,85 @@ func TestClientStatsHandler(t *testing.T) {
 	assert.Equal(ext.SpanKindClient, tags[ext.SpanKind])
 }
 
+func TestClientStatsHandlerRetries(t *testing.T) {
+	var calls atomic.Int32
+	failFirst := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if calls.Add(1) == 1 {
+			return nil, status.Error(codes.Unavailable, "unavailable")
+		}
+		return handler(ctx, req)
+	}
+	serviceConfig := `{"methodConfig": [{
+		"name": [{"service": "grpc.Fixture"}],
+		"retryPolicy": {
+			"maxAttempts": 3,
+			"initialBackoff": "0.01s",
+			"maxBackoff": "0.01s",
+			"backoffMultiplier": 1,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]}`
+	rig, err := newRigWithInterceptors(
+		[]grpc.ServerOption{grpc.UnaryInterceptor(failFirst)},
+		[]grpc.DialOption{
+			grpc.WithInsecure(),
+			grpc.WithDefaultServiceConfig(serviceConfig),
+			grpc.WithUnaryInterceptor(UnaryClientInterceptor(WithService("grpc"))),
+			grpc.WithStatsHandler(NewClientStatsHandler(WithService("grpc"))),
+		},
+	)
+	require.NoError(t, err)
+	defer rig.Close()
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	_, err = rig.client.Ping(context.Background(), &fixturepb.FixtureRequest{Name: "name"})
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	var call *mocktracer.Span
+	attempts := make(map[float64]*mocktracer.Span)
+	for _, s := range spans {
+		if n, ok := s.Tag(tagAttempt).(float64); ok {
+			attempts[n] = s
+		} else {
+			call = s
+		}
+	}
+	require.NotNil(t, call)
+	assert.Equal(t, float64(2), call.Tag(tagAttempts))
+	require.Len(t, attempts, 2)
+	assert.Equal(t, call.SpanID(), attempts[1].ParentID())
+	assert.Equal(t, codes.Unavailable.String(), attempts[1].Tag(tagCode))
+	assert.Nil(t, attempts[1].Tag(tagAttemptPreviousCode))
+	assert.Equal(t, call.SpanID(), attempts[2].ParentID())
+	assert.Equal(t, codes.OK.String(), attempts[2].Tag(tagCode))
+	assert.Equal(t, codes.Unavailable.String(), attempts[2].Tag(tagAttemptPreviousCode))
+}
+
+func TestClientStatsHandlerIgnoredMethods(t *testing.T) {
+	statsHandler := NewClientStatsHandler(WithIgnoredMethods("/grpc.Fixture/Ping"))
+	server, err := newClientStatsHandlerTestServer(statsHandler)
+	require.NoError(t, err)
+	defer server.Close()
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	rootSpan, ctx := tracer.StartSpanFromContext(context.Background(), "a")
+	_, err = server.client.Ping(ctx, &fixturepb.FixtureRequest{Name: "name"})
+	require.NoError(t, err)
+	// the span of the caller isn't finished by the stats handler
+	assert.Empty(t, mt.FinishedSpans())
+	rootSpan.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "a", spans[0].OperationName())
+}
+
 func newClientStatsHandlerTestServer(statsHandler stats.Handler) (*rig, error) {
 	return newRigWithInterceptors(
 		nil,
@@ -32,6 +32,9 @@ type serverStatsHandler struct {
 
 // TagRPC starts a new span for the initiated RPC request.
 func (h *serverStatsHandler) TagRPC(ctx context.Context, rti *stats.RPCTagInfo) context.Context {
+	if h.cfg.untraced(rti.FullMethodName) {
+		return withUntracedRPC(ctx)
+	}
 	spanOpts := append([]tracer.StartSpanOption{
 		tracer.Measured(),
 		tracer.Tag(ext.SpanKind, ext.SpanKindServer)},
@@ -49,6 +52,9 @@ func (h *serverStatsHandler) TagRPC(ctx context.Context, rti *stats.RPCTagInfo)
 
 // HandleRPC processes the RPC ending event by finishing the span from the context.
 func (h *serverStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	if isUntracedRPC(ctx) {
+		return
+	}
 	span, ok := tracer.SpanFromContext(ctx)
 	if !ok {
 		return
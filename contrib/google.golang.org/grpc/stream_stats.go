@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package grpc
+
+import (
+	"sync/atomic"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// maxStreamMessageEvents is the maximum number of message events added to the span of a
+// stream, so that long-lived streams don't grow unbounded spans. Messages are still counted
+// past this limit.
+const maxStreamMessageEvents = 1000
+
+// streamStats counts the messages sent and received on a stream, and records them as
+// events on the span of the stream call. A nil *streamStats records nothing.
+type streamStats struct {
+	span          *tracer.Span // span of the stream call, nil if calls aren't traced
+	sent          atomic.Int64
+	received      atomic.Int64
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+}
+
+// newStreamStats returns the stats of a stream traced by span, or nil if message events are
+// disabled in cfg.
+func newStreamStats(cfg *config, span *tracer.Span) *streamStats {
+	if !cfg.streamMessageEvents {
+		return nil
+	}
+	return &streamStats{span: span}
+}
+
+// recordSent records a message sent on the stream and returns its size, or -1 if unknown.
+func (s *streamStats) recordSent(m interface{}) int {
+	if s == nil {
+		return -1
+	}
+	return s.record("SENT", m, &s.sent, &s.bytesSent)
+}
+
+// recordReceived records a message received on the stream and returns its size, or -1 if unknown.
+func (s *streamStats) recordReceived(m interface{}) int {
+	if s == nil {
+		return -1
+	}
+	return s.record("RECEIVED", m, &s.received, &s.bytesReceived)
+}
+
+func (s *streamStats) record(typ string, m interface{}, count, bytes *atomic.Int64) int {
+	id := count.Add(1)
+	size := -1
+	if p, ok := m.(proto.Message); ok {
+		size = proto.Size(p)
+		bytes.Add(int64(size))
+	}
+	if s.span == nil || s.sent.Load()+s.received.Load() > maxStreamMessageEvents {
+		return size
+	}
+	attrs := map[string]any{
+		"message.type": typ,
+		"message.id":   id,
+	}
+	if size >= 0 {
+		attrs["message.uncompressed_size"] = size
+	}
+	s.span.AddEvent("message", tracer.WithSpanEventAttributes(attrs))
+	return size
+}
+
+// setTags sets the message counts and sizes of the stream on its span.
+func (s *streamStats) setTags() {
+	if s == nil || s.span == nil {
+		return
+	}
+	s.span.SetTag(tagStreamMessagesSent, s.sent.Load())
+	s.span.SetTag(tagStreamMessagesReceived, s.received.Load())
+	s.span.SetTag(tagStreamBytesSent, s.bytesSent.Load())
+	s.span.SetTag(tagStreamBytesReceived, s.bytesReceived.Load())
+}
@@ -13,6 +13,16 @@ const (
 	tagMetadataPrefix      = "grpc.metadata."
 	tagRequest             = "grpc.request"
 	tagStatusDetailsPrefix = "grpc.status_details."
+	tagAttempts            = "grpc.attempts"
+	tagAttempt             = "grpc.attempt"
+	tagAttemptPreviousCode = "grpc.attempt.previous_code"
+	tagAttemptTransparent  = "grpc.attempt.transparent"
+
+	tagMessageSize            = "grpc.message.size"
+	tagStreamMessagesSent     = "grpc.stream.messages_sent"
+	tagStreamMessagesReceived = "grpc.stream.messages_received"
+	tagStreamBytesSent        = "grpc.stream.bytes_sent"
+	tagStreamBytesReceived    = "grpc.stream.bytes_received"
 )
 
 const (
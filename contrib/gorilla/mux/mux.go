@@ -7,6 +7,7 @@
 package mux // import "github.com/DataDog/dd-trace-go/contrib/gorilla/mux/v2"
 
 import (
+	"errors"
 	"net/http"
 
 	httptrace "github.com/DataDog/dd-trace-go/contrib/net/http/v2"
@@ -104,6 +105,8 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			spanopts = append(spanopts, tracer.Tag("mux.host", h))
 		}
 		route, _ = match.Route.GetPathTemplate()
+	} else {
+		route = unmatchedRoute(&match)
 	}
 	spanopts = append(spanopts, instrhttptrace.HeaderTagsFromRequest(req, r.config.headerTags))
 	resource := r.config.resourceNamer(r, req)
@@ -142,6 +145,16 @@ func defaultResourceNamer(router *Router, req *http.Request) string {
 		if r, err := match.Route.GetPathTemplate(); err == nil {
 			return req.Method + " " + r
 		}
+		return req.Method + " unknown"
 	}
-	return req.Method + " unknown"
+	return req.Method + " " + unmatchedRoute(&match)
+}
+
+// unmatchedRoute returns the route reported for a request which doesn't match any route,
+// instead of its path.
+func unmatchedRoute(match *mux.RouteMatch) string {
+	if errors.Is(match.MatchErr, mux.ErrMethodMismatch) {
+		return instrhttptrace.RouteMethodNotAllowed
+	}
+	return instrhttptrace.RouteNotFound
 }
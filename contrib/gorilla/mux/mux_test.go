@@ -56,16 +56,16 @@ func TestHttpTracer(t *testing.T) {
 			code:         http.StatusNotFound,
 			method:       "GET",
 			url:          "/not_a_real_route",
-			wantResource: "GET unknown",
-			wantRoute:    "",
+			wantResource: "GET not_found",
+			wantRoute:    "not_found",
 		},
 		{
 			name:         "405",
 			code:         http.StatusMethodNotAllowed,
 			method:       "POST",
 			url:          "/405",
-			wantResource: "POST unknown",
-			wantRoute:    "",
+			wantResource: "POST method_not_allowed",
+			wantRoute:    "method_not_allowed",
 		},
 		{
 			name:         "500",
@@ -8,6 +8,7 @@ package gorm
 
 import (
 	"math"
+	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -159,6 +160,9 @@ func before(db *gorm.DB, operationName string, cfg *config) {
 
 	_, ctx := tracer.StartSpanFromContext(db.Statement.Context, operationName, opts...)
 	db.Statement.Context = ctx
+	if cfg.phaseSpans && db.Statement.ConnPool != nil {
+		db.Statement.ConnPool = &tracedConnPool{ConnPool: db.Statement.ConnPool, start: time.Now()}
+	}
 }
 
 func after(db *gorm.DB, cfg *config) {
@@ -175,6 +179,10 @@ func after(db *gorm.DB, cfg *config) {
 			dbErr = db.Error
 		}
 		span.SetTag(ext.ResourceName, db.Statement.SQL.String())
+		if pool, ok := db.Statement.ConnPool.(*tracedConnPool); ok {
+			db.Statement.ConnPool = pool.ConnPool
+			pool.finish(db, span, cfg)
+		}
 		span.Finish(tracer.WithError(dbErr))
 	}
 }
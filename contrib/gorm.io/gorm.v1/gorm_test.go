@@ -579,6 +579,45 @@ func TestCustomTags(t *testing.T) {
 	assert.Equal("bar", s.Tag("foo"))
 }
 
+func TestPhaseSpans(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	sqltrace.Register("pgx", &stdlib.Driver{})
+	sqlDb, err := sqltrace.Open("pgx", pgConnString)
+	require.NoError(t, err)
+
+	db, err := Open(postgres.New(postgres.Config{Conn: sqlDb}), &gorm.Config{}, WithPhaseSpans())
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+	mt.Reset()
+
+	parentSpan, ctx := tracer.StartSpanFromContext(context.Background(), "http.request")
+	var products []Product
+	db.WithContext(ctx).Find(&products)
+	parentSpan.Finish()
+
+	spans := make(map[string]*mocktracer.Span)
+	for _, s := range mt.FinishedSpans() {
+		spans[s.OperationName()] = s
+	}
+	require.Contains(t, spans, "gorm.query")
+	query := spans["gorm.query"]
+	for _, name := range []string{"gorm.build", "gorm.exec", "gorm.scan"} {
+		require.Contains(t, spans, name)
+		s := spans[name]
+		assert.Equal(t, query.SpanID(), s.ParentID())
+		assert.Equal(t, query.Tag(ext.ResourceName), s.Tag(ext.ResourceName))
+		assert.Equal(t, "gorm.io/gorm.v1", s.Tag(ext.Component))
+		assert.False(t, s.StartTime().Before(query.StartTime()))
+	}
+	assert.False(t, spans["gorm.exec"].StartTime().Before(spans["gorm.build"].FinishTime()))
+	assert.False(t, spans["gorm.scan"].StartTime().Before(spans["gorm.exec"].FinishTime()))
+	// the connection pool is restored once the operation is done
+	_, ok := db.Statement.ConnPool.(*tracedConnPool)
+	assert.False(t, ok)
+}
+
 func TestPlugin(t *testing.T) {
 	db, err := gorm.Open(&tests.DummyDialector{})
 	require.NoError(t, err)
@@ -17,6 +17,7 @@ type config struct {
 	dsn           string
 	errCheck      func(err error) bool
 	tagFns        map[string]func(db *gorm.DB) interface{}
+	phaseSpans    bool
 }
 
 // Option describes options for the Gorm.io integration.
@@ -89,3 +90,15 @@ func WithCustomTag(tag string, tagFn func(db *gorm.DB) interface{}) OptionFn {
 		}
 	}
 }
+
+// WithPhaseSpans enables the creation of child spans for each phase of a gorm operation:
+// "gorm.build" for building the statement, "gorm.exec" for executing it, including the
+// time spent waiting for a connection from the pool, and "gorm.scan" for scanning the
+// returned rows. The time spent waiting for a connection is reported on the "gorm.exec"
+// span in the "gorm.pool.wait_duration" metric, in nanoseconds, when the connection pool
+// of the *gorm.DB is a *sql.DB.
+func WithPhaseSpans() OptionFn {
+	return func(cfg *config) {
+		cfg.phaseSpans = true
+	}
+}
@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"gorm.io/gorm"
+)
+
+// keyPoolWaitDuration holds the time spent waiting for a connection from the pool while
+// executing the statements of an operation, in nanoseconds.
+const keyPoolWaitDuration = "gorm.pool.wait_duration"
+
+// tracedConnPool wraps the connection pool of a statement for the duration of an operation
+// to time the execution of its queries, which split the operation into phases.
+type tracedConnPool struct {
+	gorm.ConnPool
+
+	start     time.Time // start of the operation
+	execStart time.Time // start of the first query
+	execEnd   time.Time // end of the last query
+	queried   bool      // whether the last query returned rows
+	wait      time.Duration
+}
+
+func (p *tracedConnPool) PrepareContext(ctx context.Context, query string) (stmt *sql.Stmt, err error) {
+	p.exec(false, func() { stmt, err = p.ConnPool.PrepareContext(ctx, query) })
+	return stmt, err
+}
+
+func (p *tracedConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	p.exec(false, func() { res, err = p.ConnPool.ExecContext(ctx, query, args...) })
+	return res, err
+}
+
+func (p *tracedConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	p.exec(true, func() { rows, err = p.ConnPool.QueryContext(ctx, query, args...) })
+	return rows, err
+}
+
+func (p *tracedConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) (row *sql.Row) {
+	p.exec(true, func() { row = p.ConnPool.QueryRowContext(ctx, query, args...) })
+	return row
+}
+
+// exec times the execution of a query by fn. The time spent waiting for a connection is
+// taken from the statistics of the pool, so waits of concurrent queries may be included.
+func (p *tracedConnPool) exec(queried bool, fn func()) {
+	db, ok := p.ConnPool.(*sql.DB)
+	var stats sql.DBStats
+	if ok {
+		stats = db.Stats()
+	}
+	start := time.Now()
+	fn()
+	end := time.Now()
+	if p.execStart.IsZero() {
+		p.execStart = start
+	}
+	p.execEnd = end
+	p.queried = queried
+	if ok {
+		wait := db.Stats().WaitDuration - stats.WaitDuration
+		p.wait += min(wait, end.Sub(start))
+	}
+}
+
+// finish creates the spans of the phases of the operation traced by span.
+func (p *tracedConnPool) finish(db *gorm.DB, span *tracer.Span, cfg *config) {
+	if p.execStart.IsZero() {
+		// no query was executed
+		return
+	}
+	resource := db.Statement.SQL.String()
+	phase := func(name string, start, end time.Time, opts ...tracer.StartSpanOption) {
+		opts = append(opts,
+			tracer.ChildOf(span.Context()),
+			tracer.StartTime(start),
+			tracer.ServiceName(cfg.serviceName),
+			tracer.ResourceName(resource),
+			tracer.SpanType(ext.SpanTypeSQL),
+			tracer.Tag(ext.Component, instrumentation.PackageGormIOGormV1),
+		)
+		tracer.StartSpan(name, opts...).Finish(tracer.FinishTime(end))
+	}
+	phase("gorm.build", p.start, p.execStart)
+	var execOpts []tracer.StartSpanOption
+	if p.wait > 0 {
+		execOpts = append(execOpts, tracer.Tag(keyPoolWaitDuration, p.wait.Nanoseconds()))
+	}
+	phase("gorm.exec", p.execStart, p.execEnd, execOpts...)
+	switch db.Statement.Dest.(type) {
+	case *sql.Rows, *sql.Row:
+		// the rows are scanned by the caller after the operation
+	default:
+		if p.queried {
+			phase("gorm.scan", p.execEnd, time.Now())
+		}
+	}
+}
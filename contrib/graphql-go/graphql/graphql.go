@@ -10,6 +10,9 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -65,11 +68,22 @@ type contextData struct {
 	variables     map[string]any
 	query         string
 	operationName string
+	fields        *fieldStats
+}
+
+// fieldStats holds the sampler of the traced fields of an operation, and computes the depth
+// and complexity of the operation once its first field is resolved.
+type fieldStats struct {
+	sampler *instrgraphql.FieldSampler
+	once    sync.Once
 }
 
 // finish closes the top-level request operation, as well as the server span.
 func (c *contextData) finish(data any, err error) {
 	defer c.serverSpan.Finish(tracer.WithError(err))
+	if c.fields != nil {
+		c.fields.sampler.Finish(c.serverSpan)
+	}
 	c.requestOp.Finish(graphqlsec.RequestOperationRes{Data: data, Error: err})
 }
 
@@ -106,6 +120,7 @@ func (i datadogExtension) Init(ctx context.Context, params *graphql.Params) cont
 		variables:     params.VariableValues,
 		serverSpan:    span,
 		requestOp:     request,
+		fields:        &fieldStats{sampler: instrgraphql.NewFieldSampler(i.config.fieldSpanLimit, i.config.fieldEvents)},
 	})
 }
 
@@ -208,6 +223,25 @@ func (i datadogExtension) ExecutionDidStart(ctx context.Context) (context.Contex
 
 // ResolveFieldDidStart notifies about the start of the resolving of a field
 func (i datadogExtension) ResolveFieldDidStart(ctx context.Context, info *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	resource := fmt.Sprintf("%s.%s", info.ParentType.Name(), info.FieldName)
+	if data, ok := ctx.Value(contextKey{}).(contextData); ok && data.fields != nil {
+		data.fields.once.Do(func() {
+			if def, ok := info.Operation.(*ast.OperationDefinition); ok {
+				depth, fields := selectionStats(def.SelectionSet, info.Fragments, nil)
+				data.serverSpan.SetTag(instrgraphql.TagOperationDepth, depth)
+				data.serverSpan.SetTag(instrgraphql.TagOperationComplexity, fields)
+			}
+		})
+		if !data.fields.sampler.Sample() {
+			return i.resolveField(ctx, info, nil)
+		}
+		if data.fields.sampler.Events() {
+			start := time.Now()
+			return i.resolveField(ctx, info, func(err error) {
+				data.fields.sampler.RecordField(data.serverSpan, resource, fieldPath(info.Path), start, err)
+			})
+		}
+	}
 	var operationName string
 	switch def := info.Operation.(type) {
 	case *ast.OperationDefinition:
@@ -228,7 +262,7 @@ func (i datadogExtension) ResolveFieldDidStart(ctx context.Context, info *graphq
 		tracer.Tag(tagGraphqlField, info.FieldName),
 		tracer.Tag(tagGraphqlOperationType, info.Operation.GetOperation()),
 		tracer.Tag(ext.Component, instrumentation.PackageGraphQLGoGraphQL),
-		tracer.Tag(ext.ResourceName, fmt.Sprintf("%s.%s", info.ParentType.Name(), info.FieldName)),
+		tracer.Tag(ext.ResourceName, resource),
 		tracer.Measured(),
 	}
 	if operationName != "" {
@@ -238,17 +272,75 @@ func (i datadogExtension) ResolveFieldDidStart(ctx context.Context, info *graphq
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, i.config.analyticsRate))
 	}
 	span, ctx := tracer.StartSpanFromContext(ctx, spanResolve, opts...)
+	return i.resolveField(ctx, info, func(err error) { span.Finish(tracer.WithError(err)) })
+}
+
+// resolveField starts the AppSec monitoring of the resolution of a field, calling done, if
+// not nil, once the field is resolved.
+func (i datadogExtension) resolveField(ctx context.Context, info *graphql.ResolveInfo, done func(err error)) (context.Context, graphql.ResolveFieldFinishFunc) {
 	ctx, op := graphqlsec.StartResolveOperation(ctx, graphqlsec.ResolveOperationArgs{
 		TypeName:  info.ParentType.Name(),
 		FieldName: info.FieldName,
 		Arguments: collectArguments(info),
 	})
 	return ctx, func(result any, err error) {
-		defer span.Finish(tracer.WithError(err))
+		if done != nil {
+			defer done(err)
+		}
 		op.Finish(graphqlsec.ResolveOperationRes{Error: err, Data: result})
 	}
 }
 
+// selectionStats returns the depth of set, and its number of fields, fragments included.
+// visited holds the fragments being expanded, to guard against cycles.
+func selectionStats(set *ast.SelectionSet, fragments map[string]ast.Definition, visited map[string]bool) (depth, fields int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, sel := range set.Selections {
+		var d, f int
+		switch sel := sel.(type) {
+		case *ast.Field:
+			d, f = selectionStats(sel.SelectionSet, fragments, visited)
+			d, f = d+1, f+1
+		case *ast.InlineFragment:
+			d, f = selectionStats(sel.SelectionSet, fragments, visited)
+		case *ast.FragmentSpread:
+			if sel.Name == nil || visited[sel.Name.Value] {
+				continue
+			}
+			def, ok := fragments[sel.Name.Value].(*ast.FragmentDefinition)
+			if !ok {
+				continue
+			}
+			if visited == nil {
+				visited = make(map[string]bool)
+			}
+			visited[sel.Name.Value] = true
+			d, f = selectionStats(def.SelectionSet, fragments, visited)
+			delete(visited, sel.Name.Value)
+		}
+		depth = max(depth, d)
+		fields += f
+	}
+	return depth, fields
+}
+
+// fieldPath returns the path of a field in the response, such as "users.0.name".
+func fieldPath(path *graphql.ResponsePath) string {
+	if path == nil {
+		return ""
+	}
+	var b strings.Builder
+	for i, key := range path.AsArray() {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		fmt.Fprint(&b, key)
+	}
+	return b.String()
+}
+
 // HasResult returns if the extension wants to add data to the result
 func (i datadogExtension) HasResult() bool {
 	return false
@@ -379,3 +379,87 @@ func hasNoTag(name string) spanMatcher {
 		_ = assert.Nil(t, span.Tag(name))
 	}
 }
+
+func TestFieldSampling(t *testing.T) {
+	user := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"name": {Type: graphql.String},
+		},
+	})
+	user.AddFieldConfig("friends", &graphql.Field{Type: graphql.NewList(user)})
+	rootQuery := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"user": {
+				Type: user,
+				Resolve: func(_ graphql.ResolveParams) (any, error) {
+					return map[string]any{
+						"name":    "alice",
+						"friends": []any{map[string]any{"name": "bob"}},
+					}, nil
+				},
+			},
+		},
+	})
+	query := `{ user { name ...Friends } } fragment Friends on User { friends { name } }`
+	do := func(t *testing.T, opts ...Option) {
+		schema, err := NewSchema(graphql.SchemaConfig{Query: rootQuery}, opts...)
+		require.NoError(t, err)
+		resp := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+		require.Empty(t, resp.Errors)
+	}
+	collect := func(mt mocktracer.Tracer) (server *mocktracer.Span, resolves int) {
+		for _, s := range mt.FinishedSpans() {
+			switch s.OperationName() {
+			case spanServer:
+				server = s
+			case spanResolve:
+				resolves++
+			}
+		}
+		return server, resolves
+	}
+
+	t.Run("default", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		do(t)
+
+		server, resolves := collect(mt)
+		require.NotNil(t, server)
+		assert.Equal(t, 4, resolves)
+		assert.Equal(t, float64(3), server.Tag("graphql.operation.depth"))
+		assert.Equal(t, float64(4), server.Tag("graphql.operation.complexity"))
+		assert.Nil(t, server.Tag("graphql.fields.dropped"))
+	})
+
+	t.Run("WithFieldSpanLimit", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		do(t, WithFieldSpanLimit(1))
+
+		server, resolves := collect(mt)
+		require.NotNil(t, server)
+		assert.Equal(t, 1, resolves)
+		assert.Equal(t, float64(3), server.Tag("graphql.fields.dropped"))
+	})
+
+	t.Run("WithFieldEvents", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		do(t, WithFieldEvents())
+
+		server, resolves := collect(mt)
+		require.NotNil(t, server)
+		assert.Zero(t, resolves)
+		events := server.Events()
+		require.Len(t, events, 4)
+		var paths []any
+		for _, evt := range events {
+			assert.Equal(t, "graphql.field", evt.Name)
+			paths = append(paths, evt.Attributes["path"])
+		}
+		assert.ElementsMatch(t, []any{"user", "user.name", "user.friends", "user.friends.0.name"}, paths)
+	})
+}
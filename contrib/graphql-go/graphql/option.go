@@ -15,9 +15,11 @@ import (
 const defaultServiceName = "graphql.server"
 
 type config struct {
-	serviceName   string
-	analyticsRate float64
-	errExtensions []string
+	serviceName    string
+	analyticsRate  float64
+	errExtensions  []string
+	fieldSpanLimit int
+	fieldEvents    bool
 }
 
 // Option describes options for the GraphQL integration.
@@ -36,6 +38,7 @@ func defaults(cfg *config) {
 	cfg.serviceName = instr.ServiceName(instrumentation.ComponentDefault, nil)
 	cfg.analyticsRate = instr.AnalyticsRate(false)
 	cfg.errExtensions = instrgraphql.ErrorExtensionsFromEnv()
+	cfg.fieldSpanLimit = -1
 }
 
 // WithAnalytics enables Trace Analytics for all started spans.
@@ -74,3 +77,22 @@ func WithErrorExtensions(errExtensions ...string) OptionFn {
 		cfg.errExtensions = instrgraphql.ParseErrorExtensions(errExtensions)
 	}
 }
+
+// WithFieldSpanLimit limits the number of fields traced for each operation to n, so that
+// large queries don't produce a span explosion. The number of fields which were resolved
+// without being traced is reported in the graphql.fields.dropped tag of the server span.
+// A negative n, the default, traces all the fields.
+func WithFieldSpanLimit(n int) OptionFn {
+	return func(cfg *config) {
+		cfg.fieldSpanLimit = n
+	}
+}
+
+// WithFieldEvents records the resolved fields as span events on the server span, with their
+// path and duration, instead of creating a span for each of them. The limit set with
+// WithFieldSpanLimit applies to the events.
+func WithFieldEvents() OptionFn {
+	return func(cfg *config) {
+		cfg.fieldEvents = true
+	}
+}
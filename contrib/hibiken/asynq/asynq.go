@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package asynq provides functions to trace the hibiken/asynq package (https://github.com/hibiken/asynq).
+//
+// The trace context of enqueued tasks is propagated in their headers, so that the spans of
+// the attempts to process them continue the trace of the enqueuing. Only the tasks created
+// with headers, with NewTask or asynq.NewTaskWithHeaders, can propagate the trace context.
+package asynq // import "github.com/DataDog/dd-trace-go/contrib/hibiken/asynq/v2"
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/hibiken/asynq"
+)
+
+const componentName = instrumentation.PackageHibikenAsynq
+
+const (
+	tagTaskID    = "asynq.task.id"
+	tagTaskType  = "asynq.task.type"
+	tagAttempt   = "asynq.task.attempt"
+	tagMaxRetry  = "asynq.task.max_retry"
+	tagProcessAt = "asynq.task.process_at"
+	// tagQueueLatency is the time elapsed between the enqueuing of a task and the start of
+	// an attempt to process it, in milliseconds.
+	tagQueueLatency = "asynq.task.queue_latency_ms"
+)
+
+// headerEnqueuedAt is the header of tasks holding the time they were enqueued at, in Unix
+// milliseconds.
+const headerEnqueuedAt = "x-datadog-asynq-enqueued-at"
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageHibikenAsynq)
+}
+
+// NewTask returns a task with headers, which can carry the trace context of the span
+// enqueuing it.
+func NewTask(typename string, payload []byte, opts ...asynq.Option) *asynq.Task {
+	return asynq.NewTaskWithHeaders(typename, payload, make(map[string]string), opts...)
+}
+
+// Client wraps an *asynq.Client so that the tasks it enqueues are traced.
+type Client struct {
+	*asynq.Client
+	cfg *config
+}
+
+// WrapClient wraps an *asynq.Client so that enqueued tasks are traced.
+func WrapClient(c *asynq.Client, opts ...Option) *Client {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/hibiken/asynq: Wrapping Client: %#v", cfg)
+	return &Client{Client: c, cfg: cfg}
+}
+
+// Enqueue calls asynq.Client.Enqueue and traces the task.
+func (c *Client) Enqueue(task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	return c.EnqueueContext(context.Background(), task, opts...)
+}
+
+// EnqueueContext calls asynq.Client.EnqueueContext and traces the task with a span child of
+// the span in ctx, if any.
+func (c *Client) EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	span, ctx := tracer.StartSpanFromContext(ctx, c.cfg.producerSpanName,
+		tracer.ServiceName(c.cfg.producerServiceName),
+		tracer.ResourceName(task.Type()),
+		tracer.SpanType(ext.SpanTypeMessageProducer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindProducer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemAsynq),
+		tracer.Tag(tagTaskType, task.Type()),
+		tracer.Tag("message_size", len(task.Payload())),
+	)
+	injectHeaders(span, task)
+	info, err := c.Client.EnqueueContext(ctx, task, opts...)
+	if info != nil {
+		span.SetTag(ext.MessagingDestinationName, info.Queue)
+		span.SetTag(tagTaskID, info.ID)
+		span.SetTag(tagMaxRetry, info.MaxRetry)
+		if !info.NextProcessAt.IsZero() {
+			span.SetTag(tagProcessAt, info.NextProcessAt.UTC().Format(time.RFC3339))
+		}
+	}
+	span.Finish(tracer.WithError(err))
+	return info, err
+}
+
+// Middleware returns a middleware tracing the attempts to process tasks, to be added to the
+// asynq.ServeMux of a server with its Use method.
+func Middleware(opts ...Option) asynq.MiddlewareFunc {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/hibiken/asynq: Configuring Middleware: %#v", cfg)
+	return func(h asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			span, ctx := startProcessSpan(ctx, cfg, task)
+			err := h.ProcessTask(ctx, task)
+			span.Finish(tracer.WithError(err))
+			return err
+		})
+	}
+}
+
+// startProcessSpan starts the span of an attempt to process task, continuing the trace
+// propagated in its headers.
+func startProcessSpan(ctx context.Context, cfg *config, task *asynq.Task) (*tracer.Span, context.Context) {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(cfg.consumerServiceName),
+		tracer.ResourceName(task.Type()),
+		tracer.SpanType(ext.SpanTypeMessageConsumer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemAsynq),
+		tracer.Tag(tagTaskType, task.Type()),
+		tracer.Tag("message_size", len(task.Payload())),
+		tracer.Measured(),
+	}
+	if queue, ok := asynq.GetQueueName(ctx); ok {
+		opts = append(opts, tracer.Tag(ext.MessagingDestinationName, queue))
+	}
+	if id, ok := asynq.GetTaskID(ctx); ok {
+		opts = append(opts, tracer.Tag(tagTaskID, id))
+	}
+	if n, ok := asynq.GetRetryCount(ctx); ok {
+		opts = append(opts, tracer.Tag(tagAttempt, n+1))
+	}
+	if n, ok := asynq.GetMaxRetry(ctx); ok {
+		opts = append(opts, tracer.Tag(tagMaxRetry, n))
+	}
+	headers := task.Headers()
+	if ms, err := strconv.ParseInt(headers[headerEnqueuedAt], 10, 64); err == nil {
+		opts = append(opts, tracer.Tag(tagQueueLatency, time.Since(time.UnixMilli(ms)).Milliseconds()))
+	}
+	if spanCtx, err := tracer.Extract(tracer.TextMapCarrier(headers)); err == nil {
+		if cfg.linkedTraces {
+			opts = append(opts, tracer.WithSpanLinks([]tracer.SpanLink{spanLink(spanCtx)}))
+		} else {
+			opts = append(opts, tracer.ChildOf(spanCtx))
+		}
+	}
+	return tracer.StartSpanFromContext(ctx, cfg.consumerSpanName, opts...)
+}
+
+// injectHeaders injects the context of span and the current time in the headers of task.
+func injectHeaders(span *tracer.Span, task *asynq.Task) {
+	headers := task.Headers()
+	if headers == nil {
+		instr.Logger().Debug("contrib/hibiken/asynq: task %q has no headers, its trace context isn't propagated", task.Type())
+		return
+	}
+	if err := tracer.Inject(span.Context(), tracer.TextMapCarrier(headers)); err != nil {
+		instr.Logger().Debug("contrib/hibiken/asynq: failed to inject span context in task headers: %v", err)
+	}
+	headers[headerEnqueuedAt] = strconv.FormatInt(time.Now().UnixMilli(), 10)
+}
+
+// spanLink returns a link to the span which enqueued a task.
+func spanLink(spanCtx *tracer.SpanContext) tracer.SpanLink {
+	link := tracer.SpanLink{
+		TraceID:     spanCtx.TraceIDLower(),
+		TraceIDHigh: spanCtx.TraceIDUpper(),
+		SpanID:      spanCtx.SpanID(),
+		Attributes:  map[string]string{"reason": "asynq.enqueue"},
+	}
+	if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+		link.Flags = 1
+	}
+	return link
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package asynq
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redisAddr = "127.0.0.1:6379"
+
+// enqueue injects the context of a producer span in the headers of task, as done by
+// Client.EnqueueContext, and returns the span.
+func enqueue(task *asynq.Task) *tracer.Span {
+	span := tracer.StartSpan("asynq.enqueue")
+	injectHeaders(span, task)
+	span.Finish()
+	return span
+}
+
+func TestMiddleware(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	task := NewTask("email:send", []byte(`{"to":"alice@example.com"}`))
+	producer := enqueue(task)
+	task.Headers()[headerEnqueuedAt] = strconv.FormatInt(time.Now().Add(-time.Second).UnixMilli(), 10)
+
+	var processed bool
+	h := Middleware()(asynq.HandlerFunc(func(ctx context.Context, _ *asynq.Task) error {
+		_, processed = tracer.SpanFromContext(ctx)
+		return nil
+	}))
+	require.NoError(t, h.ProcessTask(context.Background(), task))
+	assert.True(t, processed)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[1]
+	assert.Equal(t, "asynq.process", s.OperationName())
+	assert.Equal(t, "asynq", s.Tag(ext.ServiceName))
+	assert.Equal(t, "email:send", s.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindConsumer, s.Tag(ext.SpanKind))
+	assert.Equal(t, ext.MessagingSystemAsynq, s.Tag(ext.MessagingSystem))
+	assert.Equal(t, "hibiken/asynq", s.Tag(ext.Component))
+	assert.GreaterOrEqual(t, s.Tag(tagQueueLatency), float64(1000))
+	assert.Equal(t, producer.Context().SpanID(), s.ParentID())
+	assert.Equal(t, producer.Context().TraceIDLower(), s.TraceID())
+}
+
+func TestMiddlewareError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	h := Middleware()(asynq.HandlerFunc(func(context.Context, *asynq.Task) error {
+		return errors.New("smtp unavailable")
+	}))
+	require.Error(t, h.ProcessTask(context.Background(), asynq.NewTask("email:send", nil)))
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "smtp unavailable", spans[0].Tag(ext.ErrorMsg))
+	assert.Zero(t, spans[0].ParentID())
+}
+
+func TestWithLinkedTraces(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	task := NewTask("email:send", nil)
+	producer := enqueue(task)
+	h := Middleware(WithLinkedTraces())(asynq.HandlerFunc(func(context.Context, *asynq.Task) error {
+		return nil
+	}))
+	require.NoError(t, h.ProcessTask(context.Background(), task))
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[1]
+	assert.NotEqual(t, producer.Context().TraceIDLower(), s.TraceID())
+	require.Len(t, s.Links(), 1)
+	assert.Equal(t, producer.Context().SpanID(), s.Links()[0].SpanID)
+}
+
+func TestEnqueueAndProcess(t *testing.T) {
+	if _, ok := os.LookupEnv("INTEGRATION"); !ok {
+		t.Skip("to enable integration test, set the INTEGRATION environment variable")
+	}
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	redisOpt := asynq.RedisClientOpt{Addr: redisAddr}
+	client := WrapClient(asynq.NewClient(redisOpt))
+	defer client.Close()
+
+	mux := asynq.NewServeMux()
+	mux.Use(Middleware())
+	processed := make(chan struct{})
+	mux.HandleFunc("email:send", func(context.Context, *asynq.Task) error {
+		close(processed)
+		return nil
+	})
+	srv := asynq.NewServer(redisOpt, asynq.Config{Queues: map[string]int{"dd-trace-go-test": 1}})
+	require.NoError(t, srv.Start(mux))
+	defer srv.Shutdown()
+
+	info, err := client.Enqueue(NewTask("email:send", []byte("{}")), asynq.Queue("dd-trace-go-test"))
+	require.NoError(t, err)
+	select {
+	case <-processed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("task not processed")
+	}
+	// the span of the attempt is finished after the handler returns
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 2 }, time.Second, 10*time.Millisecond)
+
+	spans := mt.FinishedSpans()
+	enqueued, processedSpan := spans[0], spans[1]
+	assert.Equal(t, "asynq.enqueue", enqueued.OperationName())
+	assert.Equal(t, ext.SpanKindProducer, enqueued.Tag(ext.SpanKind))
+	assert.Equal(t, "dd-trace-go-test", enqueued.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, info.ID, enqueued.Tag(tagTaskID))
+
+	assert.Equal(t, "asynq.process", processedSpan.OperationName())
+	assert.Equal(t, "dd-trace-go-test", processedSpan.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, info.ID, processedSpan.Tag(tagTaskID))
+	assert.Equal(t, float64(1), processedSpan.Tag(tagAttempt))
+	assert.Equal(t, enqueued.SpanID(), processedSpan.ParentID())
+}
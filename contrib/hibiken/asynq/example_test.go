@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package asynq_test
+
+import (
+	"context"
+	"log"
+
+	asynqtrace "github.com/DataDog/dd-trace-go/contrib/hibiken/asynq/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/hibiken/asynq"
+)
+
+func Example_client() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	client := asynqtrace.WrapClient(asynq.NewClient(asynq.RedisClientOpt{Addr: "localhost:6379"}))
+	defer client.Close()
+
+	// Create the task with headers, so that it carries the trace context.
+	task := asynqtrace.NewTask("email:send", []byte(`{"to":"alice@example.com"}`))
+	if _, err := client.EnqueueContext(context.Background(), task); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Example_server() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	mux := asynq.NewServeMux()
+	mux.Use(asynqtrace.Middleware())
+	mux.HandleFunc("email:send", func(ctx context.Context, task *asynq.Task) error {
+		// The span of the attempt is in ctx.
+		return nil
+	})
+	srv := asynq.NewServer(asynq.RedisClientOpt{Addr: "localhost:6379"}, asynq.Config{Concurrency: 10})
+	if err := srv.Run(mux); err != nil {
+		log.Fatal(err)
+	}
+}
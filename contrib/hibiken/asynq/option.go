@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package asynq
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	consumerServiceName string
+	producerServiceName string
+	consumerSpanName    string
+	producerSpanName    string
+	linkedTraces        bool
+}
+
+// Option represents an option that can be used to wrap a client or to create a middleware.
+type Option func(*config)
+
+func defaultConfig() *config {
+	return &config{
+		consumerServiceName: instr.ServiceName(instrumentation.ComponentConsumer, nil),
+		producerServiceName: instr.ServiceName(instrumentation.ComponentProducer, nil),
+		consumerSpanName:    instr.OperationName(instrumentation.ComponentConsumer, nil),
+		producerSpanName:    instr.OperationName(instrumentation.ComponentProducer, nil),
+	}
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the enqueued and processed tasks.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.consumerServiceName = name
+		cfg.producerServiceName = name
+	}
+}
+
+// WithLinkedTraces makes the span of each attempt to process a task the root of a new
+// trace, linked to the span which enqueued the task, instead of a child of it. This avoids
+// traces lasting as long as the tasks are scheduled or retried for.
+func WithLinkedTraces() Option {
+	return func(cfg *config) {
+		cfg.linkedTraces = true
+	}
+}
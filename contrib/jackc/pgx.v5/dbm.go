@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package pgx
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const keyDBMTraceInjected = "_dd.dbm_trace_injected"
+
+// InjectDBMComment is a pgx.QueryRewriter injecting the SQL comment used by Database Monitoring
+// to correlate queries with traces, according to the mode set with WithDBMPropagation. pgx
+// tracers can't rewrite queries, so it must be passed as the first argument of the queries
+// to propagate the trace context to, e.g.:
+//
+//	conn.Query(ctx, "SELECT * FROM users WHERE id = $1", pgxtrace.InjectDBMComment, id)
+//
+// The same goes for the queries of batches, which are correlated with the span of the batch:
+//
+//	batch.Queue("SELECT * FROM users WHERE id = $1", pgxtrace.InjectDBMComment, id)
+var InjectDBMComment pgx.QueryRewriter = dbmRewriter{}
+
+type dbmRewriter struct{}
+
+// RewriteQuery prepends the SQL comment computed when the span of the query, or of its batch,
+// was started.
+func (dbmRewriter) RewriteQuery(ctx context.Context, _ *pgx.Conn, sql string, args []any) (string, []any, error) {
+	if c, ok := ctx.Value(dbmCommentKey{}).(dbmComment); ok && c.sql == sql {
+		return c.comment + " " + sql, args, nil
+	}
+	if comment, ok := ctx.Value(dbmBatchCommentKey{}).(string); ok {
+		return comment + " " + sql, args, nil
+	}
+	return sql, args, nil
+}
+
+// dbmCommentKey is the context key of the dbmComment of the traced query.
+type dbmCommentKey struct{}
+
+// dbmBatchCommentKey is the context key of the SQL comment to inject in the queries of the
+// traced batch.
+type dbmBatchCommentKey struct{}
+
+// dbmComment is the SQL comment to inject in a traced query.
+type dbmComment struct {
+	sql     string
+	comment string
+}
+
+// dbmEnabled reports whether a SQL comment should be injected in queries.
+func (t *pgxTracer) dbmEnabled() bool {
+	mode := t.cfg.dbmPropagationMode
+	return mode != tracer.DBMPropagationModeUndefined && mode != tracer.DBMPropagationModeDisabled
+}
+
+// dbmComment returns the SQL comment to inject in the queries made with ctx, and the span
+// options of the span they must be correlated with. In full mode, that span gets the span ID
+// propagated in the traceparent of the comment.
+//
+// The full mode falls back to the service mode on connections caching their prepared
+// statements, as a comment unique to each query would prepare and cache a new statement for
+// every one of them.
+func (t *pgxTracer) dbmComment(ctx context.Context, connConfig *pgx.ConnConfig) (string, []tracer.StartSpanOption) {
+	var spanCtx *tracer.SpanContext
+	if span, ok := tracer.SpanFromContext(ctx); ok {
+		spanCtx = span.Context()
+	}
+	mode := t.cfg.dbmPropagationMode
+	if mode == tracer.DBMPropagationModeFull && cachesStatements(connConfig.DefaultQueryExecMode) {
+		mode = tracer.DBMPropagationModeService
+	}
+	carrier := tracer.SQLCommentCarrier{
+		Mode:           mode,
+		DBServiceName:  t.cfg.serviceName,
+		PeerDBHostname: connConfig.Host,
+		PeerDBName:     connConfig.Database,
+	}
+	if err := carrier.Inject(spanCtx); err != nil {
+		// this should never happen
+		instr.Logger().Warn("contrib/jackc/pgx.v5: failed to inject query comments: %v", err)
+	}
+	if mode != tracer.DBMPropagationModeFull {
+		return carrier.Query, nil
+	}
+	return carrier.Query, []tracer.StartSpanOption{
+		tracer.WithSpanID(carrier.SpanID),
+		tracer.Tag(keyDBMTraceInjected, true),
+	}
+}
+
+// cachesStatements reports whether the queries executed in mode are prepared and cached by
+// their SQL.
+func cachesStatements(mode pgx.QueryExecMode) bool {
+	return mode == pgx.QueryExecModeCacheStatement || mode == pgx.QueryExecModeCacheDescribe
+}
@@ -6,6 +6,9 @@
 package pgx
 
 import (
+	"os"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
 )
 
@@ -19,9 +22,15 @@ type config struct {
 	traceAcquire  bool
 	poolStats     bool
 	statsdClient  instrumentation.StatsdClient
+
+	dbmPropagationMode tracer.DBMPropagationMode
 }
 
 func defaultConfig() *config {
+	mode := os.Getenv("DD_DBM_PROPAGATION_MODE")
+	if mode == "" {
+		mode = os.Getenv("DD_TRACE_SQL_COMMENT_INJECTION_MODE")
+	}
 	return &config{
 		serviceName:   instr.ServiceName(instrumentation.ComponentDefault, nil),
 		traceQuery:    true,
@@ -30,6 +39,8 @@ func defaultConfig() *config {
 		tracePrepare:  true,
 		traceConnect:  true,
 		traceAcquire:  true,
+
+		dbmPropagationMode: tracer.DBMPropagationMode(mode),
 	}
 }
 
@@ -107,3 +118,20 @@ func WithPoolStats() Option {
 		cfg.poolStats = true
 	}
 }
+
+// WithDBMPropagation enables injection of tags as SQL comments on traced queries, to correlate
+// them with traces in Database Monitoring. The comment is injected in the queries, batched or
+// not, passed InjectDBMComment as first argument.
+// The full mode includes dynamic values like span id, trace id and the sampled flag which make
+// queries unique. It falls back to the service mode on connections whose DefaultQueryExecMode
+// caches prepared statements, the default, so that it doesn't defeat the statement cache: set
+// it to pgx.QueryExecModeExec or pgx.QueryExecModeSimpleProtocol to propagate the trace context.
+//
+// Note that enabling sql comment propagation results in potentially confidential data (service names)
+// being stored in the databases which can then be accessed by other 3rd parties that have been granted
+// access to the database.
+func WithDBMPropagation(mode tracer.DBMPropagationMode) Option {
+	return func(cfg *config) {
+		cfg.dbmPropagationMode = mode
+	}
+}
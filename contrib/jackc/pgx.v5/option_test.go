@@ -8,6 +8,8 @@ package pgx
 import (
 	"testing"
 
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,3 +24,21 @@ func TestWithPoolStats(t *testing.T) {
 		assert.True(t, cfg.poolStats)
 	})
 }
+
+func TestWithDBMPropagation(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		cfg := defaultConfig()
+		assert.Equal(t, tracer.DBMPropagationModeUndefined, cfg.dbmPropagationMode)
+	})
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("DD_DBM_PROPAGATION_MODE", "full")
+		cfg := defaultConfig()
+		assert.Equal(t, tracer.DBMPropagationModeFull, cfg.dbmPropagationMode)
+	})
+	t.Run("option", func(t *testing.T) {
+		t.Setenv("DD_DBM_PROPAGATION_MODE", "full")
+		cfg := defaultConfig()
+		WithDBMPropagation(tracer.DBMPropagationModeService)(cfg)
+		assert.Equal(t, tracer.DBMPropagationModeService, cfg.dbmPropagationMode)
+	})
+}
@@ -7,6 +7,7 @@ package pgx
 
 import (
 	"context"
+	"strings"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -109,6 +110,11 @@ func (t *pgxTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pg
 		ctx = t.wrapped.query.TraceQueryStart(ctx, conn, data)
 	}
 	opts := t.spanOptions(conn.Config(), operationTypeQuery, data.SQL)
+	if t.dbmEnabled() {
+		comment, dbmOpts := t.dbmComment(ctx, conn.Config())
+		opts = append(opts, dbmOpts...)
+		ctx = context.WithValue(ctx, dbmCommentKey{}, dbmComment{sql: data.SQL, comment: comment})
+	}
 	_, ctx = tracer.StartSpanFromContext(ctx, "pgx.query", opts...)
 	return ctx
 }
@@ -137,6 +143,13 @@ func (t *pgxTracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pg
 	opts := t.spanOptions(conn.Config(), operationTypeBatch, "",
 		tracer.Tag(tagBatchNumQueries, data.Batch.Len()),
 	)
+	if t.dbmEnabled() {
+		// the queries of the batch are correlated with the span of the batch, as their own
+		// spans are only started once they are sent.
+		comment, dbmOpts := t.dbmComment(ctx, conn.Config())
+		opts = append(opts, dbmOpts...)
+		ctx = context.WithValue(ctx, dbmBatchCommentKey{}, comment)
+	}
 	_, ctx = tracer.StartSpanFromContext(ctx, "pgx.batch", opts...)
 	return ctx
 }
@@ -154,7 +167,11 @@ func (t *pgxTracer) TraceBatchQuery(ctx context.Context, conn *pgx.Conn, data pg
 	if t.prevBatchQuery != nil {
 		t.prevBatchQuery.finish()
 	}
-	opts := t.spanOptions(conn.Config(), operationTypeQuery, data.SQL,
+	sql := data.SQL
+	if comment, ok := ctx.Value(dbmBatchCommentKey{}).(string); ok {
+		sql = strings.TrimPrefix(sql, comment+" ")
+	}
+	opts := t.spanOptions(conn.Config(), operationTypeQuery, sql,
 		tracer.Tag(tagRowsAffected, data.CommandTag.RowsAffected()),
 	)
 	span, _ := tracer.StartSpanFromContext(ctx, "pgx.batch.query", opts...)
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -334,6 +335,92 @@ func TestWrapTracer(t *testing.T) {
 	}
 }
 
+func TestDBMPropagation(t *testing.T) {
+	for _, mode := range []tracer.DBMPropagationMode{tracer.DBMPropagationModeService, tracer.DBMPropagationModeFull} {
+		t.Run(string(mode), func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			opts := append(tracingAllDisabled(), WithTraceQuery(true), WithTraceBatch(true), WithDBMPropagation(mode))
+			ctx := context.Background()
+			cfg, err := pgx.ParseConfig(postgresDSN)
+			require.NoError(t, err)
+			cfg.DefaultQueryExecMode = pgx.QueryExecModeExec
+			conn := newConnCreator(cfg, nil, opts...)(t, ctx)
+
+			var query string
+			err = conn.QueryRow(ctx, "SELECT current_query()", InjectDBMComment).Scan(&query)
+			require.NoError(t, err)
+
+			batch := &pgx.Batch{}
+			batch.Queue("SELECT current_query()", InjectDBMComment)
+			var batchQuery string
+			err = conn.SendBatch(ctx, batch).QueryRow().Scan(&batchQuery)
+			require.NoError(t, err)
+
+			// queries not given InjectDBMComment are left as is
+			var plainQuery string
+			err = conn.QueryRow(ctx, "SELECT current_query()").Scan(&plainQuery)
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT current_query()", plainQuery)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 4)
+			querySpan, batchQuerySpan, batchSpan := spans[0], spans[1], spans[2]
+
+			assert.Contains(t, query, "dddbs='postgres.db'")
+			assert.Contains(t, query, "ddh='127.0.0.1'")
+			assert.Contains(t, query, "dddb='postgres'")
+			assert.Contains(t, batchQuery, "dddbs='postgres.db'")
+			assert.Regexp(t, `^/\*.*\*/ SELECT current_query\(\)$`, query)
+			assert.Regexp(t, `^/\*.*\*/ SELECT current_query\(\)$`, batchQuery)
+			// the spans are tagged with the query as it was given to pgx
+			assert.Equal(t, "SELECT current_query()", querySpan.Tag(ext.DBStatement))
+			assert.Equal(t, "SELECT current_query()", batchQuerySpan.Tag(ext.DBStatement))
+
+			if mode == tracer.DBMPropagationModeFull {
+				assert.Contains(t, query, fmt.Sprintf("%016s", strconv.FormatUint(querySpan.SpanID(), 16)))
+				assert.Contains(t, batchQuery, fmt.Sprintf("%016s", strconv.FormatUint(batchSpan.SpanID(), 16)))
+				assert.Equal(t, "true", querySpan.Tag(keyDBMTraceInjected))
+				assert.Equal(t, "true", batchSpan.Tag(keyDBMTraceInjected))
+			} else {
+				assert.NotContains(t, query, "traceparent")
+				assert.Nil(t, querySpan.Tag(keyDBMTraceInjected))
+			}
+		})
+	}
+}
+
+func TestDBMPropagationStatementCache(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	opts := append(tracingAllDisabled(), WithTraceBatch(true), WithDBMPropagation(tracer.DBMPropagationModeFull))
+	ctx := context.Background()
+	conn := newConnCreator(nil, nil, opts...)(t, ctx)
+
+	batch := &pgx.Batch{}
+	batch.Queue("SELECT current_query()", InjectDBMComment)
+	batch.Queue("SELECT current_query()")
+	var commented, plain string
+	br := conn.SendBatch(ctx, batch)
+	require.NoError(t, br.QueryRow().Scan(&commented))
+	require.NoError(t, br.QueryRow().Scan(&plain))
+	require.NoError(t, br.Close())
+
+	// the connection caches its statements: the comment doesn't include the trace context.
+	assert.Regexp(t, `^/\*.*\*/ SELECT current_query\(\)$`, commented)
+	assert.Contains(t, commented, "dddbs='postgres.db'")
+	assert.NotContains(t, commented, "traceparent")
+	assert.Equal(t, "SELECT current_query()", plain)
+	// the queries not passed InjectDBMComment are left as is.
+	assert.Equal(t, "SELECT current_query()", batch.QueuedQueries[1].SQL)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	assert.Nil(t, spans[2].Tag(keyDBMTraceInjected))
+}
+
 func tracingAllDisabled() []Option {
 	return []Option{
 		WithTraceConnect(false),
@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/httptrace"
 
 	"github.com/julienschmidt/httprouter"
 
@@ -37,6 +38,10 @@ func New(opts ...RouterOption) *Router {
 
 // ServeHTTP implements http.Handler.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if httptrace.IgnoreRequest(req) {
+		r.Router.ServeHTTP(w, req)
+		return
+	}
 	tw, treq, afterHandle, handled := tracing.BeforeHandle(r.config, r.Router, wrapRouter, w, req)
 	defer afterHandle()
 	if handled {
@@ -54,8 +59,12 @@ func wrapRouter(r *httprouter.Router) tracing.Router {
 }
 
 func (w wRouter) Lookup(method string, path string) (any, []tracing.Param, bool) {
-	h, params, ok := w.Router.Lookup(method, path)
-	return h, wrapParams(params), ok
+	h, params, _ := w.Router.Lookup(method, path)
+	return h, wrapParams(params), h != nil
+}
+
+func (w wRouter) HandleMethodNotAllowed() bool {
+	return w.Router.HandleMethodNotAllowed
 }
 
 type wParam struct {
@@ -270,3 +270,59 @@ func TestWithHeaderTags(t *testing.T) {
 		assert.NotContains(s.Tags(), "http.request.headers.3header")
 	})
 }
+
+func TestUnmatchedRoutes(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		method    string
+		url       string
+		code      int
+		wantRoute string
+	}{
+		{
+			name:      "not found",
+			method:    "GET",
+			url:       "/not/a/real/route/123",
+			code:      http.StatusNotFound,
+			wantRoute: "not_found",
+		},
+		{
+			name:      "method not allowed",
+			method:    "POST",
+			url:       "/200/value",
+			code:      http.StatusMethodNotAllowed,
+			wantRoute: "method_not_allowed",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			r := httptest.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+			router().ServeHTTP(w, r)
+			assert.Equal(t, tt.code, w.Code)
+
+			spans := mt.FinishedSpans()
+			assert.Len(t, spans, 1)
+			s := spans[0]
+			assert.Equal(t, tt.wantRoute, s.Tag(ext.HTTPRoute))
+			assert.Equal(t, tt.method+" "+tt.wantRoute, s.Tag(ext.ResourceName))
+		})
+	}
+
+	t.Run("method not allowed disabled", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		router := New()
+		router.HandleMethodNotAllowed = false
+		router.GET("/200", handler200)
+		r := httptest.NewRequest("POST", "/200", nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+
+		spans := mt.FinishedSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "not_found", spans[0].Tag(ext.HTTPRoute))
+	})
+}
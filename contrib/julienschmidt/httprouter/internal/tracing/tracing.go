@@ -23,7 +23,19 @@ func init() {
 }
 
 type Router interface {
+	// Lookup returns the handle and the parameters of the route matching the given method
+	// and path, and whether there is such a route.
 	Lookup(method string, path string) (any, []Param, bool)
+	// HandleMethodNotAllowed reports whether the router replies with 405 Method Not Allowed
+	// to the requests matching a route with another method.
+	HandleMethodNotAllowed() bool
+}
+
+// methods are the methods looked up to tell whether a request not matching any route has
+// a method which isn't allowed.
+var methods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
 }
 
 type Param interface {
@@ -42,9 +54,12 @@ func BeforeHandle[T any, WT Router](
 	wRouter := wrapRouter(router)
 	// get the resource associated to this request
 	route := req.URL.Path
-	_, ps, _ := wRouter.Lookup(req.Method, route)
-	for _, param := range ps {
-		route = strings.Replace(route, param.GetValue(), ":"+param.GetKey(), 1)
+	if _, ps, found := wRouter.Lookup(req.Method, route); found {
+		for _, param := range ps {
+			route = strings.Replace(route, param.GetValue(), ":"+param.GetKey(), 1)
+		}
+	} else {
+		route = unmatchedRoute(wRouter, req)
 	}
 
 	resource := req.Method + " " + route
@@ -61,3 +76,20 @@ func BeforeHandle[T any, WT Router](
 	}
 	return httptrace.BeforeHandle(serveCfg, w, req)
 }
+
+// unmatchedRoute returns the route reported for a request which doesn't match any route,
+// instead of its path.
+func unmatchedRoute(r Router, req *http.Request) string {
+	if !r.HandleMethodNotAllowed() {
+		return httptrace.RouteNotFound
+	}
+	for _, method := range methods {
+		if method == req.Method {
+			continue
+		}
+		if _, _, found := r.Lookup(method, req.URL.Path); found {
+			return httptrace.RouteMethodNotAllowed
+		}
+	}
+	return httptrace.RouteNotFound
+}
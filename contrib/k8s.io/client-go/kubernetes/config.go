@@ -0,0 +1,227 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	httptrace "github.com/DataDog/dd-trace-go/contrib/net/http/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	tagVerb        = "kubernetes.verb"
+	tagAPIGroup    = "kubernetes.api_group"
+	tagAPIVersion  = "kubernetes.api_version"
+	tagNamespace   = "kubernetes.namespace"
+	tagResource    = "kubernetes.resource"
+	tagSubresource = "kubernetes.subresource"
+)
+
+// throttleSpanName is the name of the spans of the requests delayed by the client-side rate
+// limiter.
+const throttleSpanName = "kubernetes.client.throttle"
+
+// throttleThreshold is the minimum wait of a request in the client-side rate limiter for the
+// wait to be traced.
+const throttleThreshold = time.Millisecond
+
+// WrapConfig traces the requests made by the clients created from the given config. Unlike
+// WrapRoundTripper, the resource names of the spans are made of the verb of the request and of
+// the kind of resource it is applied to, such as "GET pods" or "LIST deployments", and the
+// spans are tagged with the verb, API group, API version, namespace, resource and subresource of
+// the request.
+//
+// The rate limiter of the config is wrapped, defaulting to the one client-go would create from
+// its QPS and Burst, so that the requests delayed by client-side throttling are traced with a
+// "kubernetes.client.throttle" span lasting the wait, child of the span in the context of the
+// request. Waits shorter than a millisecond aren't traced.
+func WrapConfig(cfg *rest.Config, opts ...httptrace.RoundTripperOption) {
+	localOpts := make([]httptrace.RoundTripperOption, len(opts))
+	copy(localOpts, opts) // make a copy of the opts, to avoid data races and side effects.
+	localOpts = append(localOpts, httptrace.WithBefore(func(req *http.Request, span *tracer.Span) {
+		info := parseRequest(req)
+		span.SetTag(ext.ResourceName, info.resource())
+		span.SetTag(ext.Component, componentName)
+		span.SetTag(ext.SpanKind, ext.SpanKindClient)
+		setTag(span, tagVerb, info.verb)
+		setTag(span, tagAPIGroup, info.group)
+		setTag(span, tagAPIVersion, info.version)
+		setTag(span, tagNamespace, info.namespace)
+		setTag(span, tagResource, info.kind)
+		setTag(span, tagSubresource, info.subresource)
+	}))
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		instr.Logger().Debug("contrib/k8s.io/client-go/kubernetes: Wrapping RoundTripper of Config.")
+		return httptrace.WrapRoundTripper(rt, localOpts...)
+	})
+	if rl := defaultRateLimiter(cfg); rl != nil {
+		cfg.RateLimiter = &rateLimiter{RateLimiter: rl}
+	}
+}
+
+func setTag(span *tracer.Span, key, value string) {
+	if value != "" {
+		span.SetTag(key, value)
+	}
+}
+
+// defaultRateLimiter returns the rate limiter of cfg, or the one created by client-go when cfg
+// has none.
+func defaultRateLimiter(cfg *rest.Config) flowcontrol.RateLimiter {
+	if cfg.RateLimiter != nil {
+		return cfg.RateLimiter
+	}
+	qps := cfg.QPS
+	if qps == 0 {
+		qps = rest.DefaultQPS
+	}
+	burst := cfg.Burst
+	if burst == 0 {
+		burst = rest.DefaultBurst
+	}
+	if qps <= 0 {
+		// client-side throttling is disabled
+		return nil
+	}
+	return flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+}
+
+// rateLimiter traces the waits of the requests delayed by a rate limiter.
+type rateLimiter struct {
+	flowcontrol.RateLimiter
+}
+
+// Wait calls flowcontrol.RateLimiter.Wait and traces the wait when it lasts longer than
+// throttleThreshold.
+func (r *rateLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := r.RateLimiter.Wait(ctx)
+	if time.Since(start) < throttleThreshold {
+		return err
+	}
+	span, _ := tracer.StartSpanFromContext(ctx, throttleSpanName,
+		tracer.StartTime(start),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindInternal),
+	)
+	span.Finish(tracer.WithError(err))
+	return err
+}
+
+// requestInfo describes a request to the Kubernetes API.
+type requestInfo struct {
+	verb        string
+	group       string
+	version     string
+	namespace   string
+	kind        string
+	subresource string
+	name        string
+	path        string
+}
+
+// resource returns the resource name of the span of the request.
+func (i requestInfo) resource() string {
+	if i.kind == "" {
+		// not a resource request, such as /version or /healthz
+		return strings.ToUpper(i.verb) + " " + i.path
+	}
+	var out strings.Builder
+	out.WriteString(strings.ToUpper(i.verb))
+	out.WriteByte(' ')
+	out.WriteString(i.kind)
+	if i.subresource != "" {
+		out.WriteByte('/')
+		out.WriteString(i.subresource)
+	}
+	return out.String()
+}
+
+// namespaceSubresources are the subresources of namespaces, which can't be confused with the
+// resources of a namespace.
+var namespaceSubresources = map[string]bool{
+	"status":   true,
+	"finalize": true,
+}
+
+// parseRequest parses a request to the Kubernetes API, as the API server does. See
+// https://kubernetes.io/docs/reference/using-api/api-concepts/#resource-uris.
+func parseRequest(req *http.Request) requestInfo {
+	info := requestInfo{verb: strings.ToLower(req.Method), path: req.URL.Path}
+	var parts []string
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/api/"):
+		parts = strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/api/"), "/"), "/")
+	case strings.HasPrefix(req.URL.Path, "/apis/"):
+		parts = strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/apis/"), "/"), "/")
+		if len(parts) < 3 {
+			// API discovery
+			return info
+		}
+		info.group, parts = parts[0], parts[1:]
+	default:
+		return info
+	}
+	if len(parts) < 2 {
+		// API discovery
+		return info
+	}
+	info.version, parts = parts[0], parts[1:]
+
+	watch := false
+	if parts[0] == "watch" {
+		// deprecated watch paths
+		watch, parts = true, parts[1:]
+	}
+	if len(parts) == 0 {
+		return info
+	}
+	if parts[0] == "namespaces" && len(parts) > 1 {
+		info.namespace = parts[1]
+		if len(parts) > 2 && !namespaceSubresources[parts[2]] {
+			parts = parts[2:]
+		}
+	}
+	info.kind = parts[0]
+	if len(parts) > 1 {
+		info.name = parts[1]
+	}
+	if len(parts) > 2 {
+		info.subresource = parts[2]
+	}
+
+	switch q := req.URL.Query().Get("watch"); {
+	case watch || q == "true" || q == "1":
+		info.verb = "watch"
+	case req.Method == http.MethodGet || req.Method == http.MethodHead:
+		if info.name == "" {
+			info.verb = "list"
+		} else {
+			info.verb = "get"
+		}
+	case req.Method == http.MethodPost:
+		info.verb = "create"
+	case req.Method == http.MethodPut:
+		info.verb = "update"
+	case req.Method == http.MethodPatch:
+		info.verb = "patch"
+	case req.Method == http.MethodDelete:
+		if info.name == "" {
+			info.verb = "deletecollection"
+		} else {
+			info.verb = "delete"
+		}
+	}
+	return info
+}
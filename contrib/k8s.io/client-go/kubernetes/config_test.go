@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func TestParseRequest(t *testing.T) {
+	for _, tc := range []struct {
+		method, url string
+		resource    string
+		info        requestInfo
+	}{
+		{
+			method:   "GET",
+			url:      "/api/v1/namespaces/default/pods/pod-1234",
+			resource: "GET pods",
+			info:     requestInfo{verb: "get", version: "v1", namespace: "default", kind: "pods", name: "pod-1234"},
+		},
+		{
+			method:   "GET",
+			url:      "/apis/apps/v1/namespaces/default/deployments",
+			resource: "LIST deployments",
+			info:     requestInfo{verb: "list", group: "apps", version: "v1", namespace: "default", kind: "deployments"},
+		},
+		{
+			method:   "GET",
+			url:      "/apis/apps/v1/deployments?watch=true",
+			resource: "WATCH deployments",
+			info:     requestInfo{verb: "watch", group: "apps", version: "v1", kind: "deployments"},
+		},
+		{
+			method:   "GET",
+			url:      "/api/v1/watch/namespaces/default/configmaps",
+			resource: "WATCH configmaps",
+			info:     requestInfo{verb: "watch", version: "v1", namespace: "default", kind: "configmaps"},
+		},
+		{
+			method:   "GET",
+			url:      "/api/v1/namespaces/default/pods/pod-1234/log",
+			resource: "GET pods/log",
+			info:     requestInfo{verb: "get", version: "v1", namespace: "default", kind: "pods", name: "pod-1234", subresource: "log"},
+		},
+		{
+			method:   "POST",
+			url:      "/apis/coordination.k8s.io/v1/namespaces/kube-system/leases",
+			resource: "CREATE leases",
+			info:     requestInfo{verb: "create", group: "coordination.k8s.io", version: "v1", namespace: "kube-system", kind: "leases"},
+		},
+		{
+			method:   "PUT",
+			url:      "/api/v1/namespaces/default/finalize",
+			resource: "UPDATE namespaces/finalize",
+			info:     requestInfo{verb: "update", version: "v1", namespace: "default", kind: "namespaces", name: "default", subresource: "finalize"},
+		},
+		{
+			method:   "PATCH",
+			url:      "/api/v1/nodes/node-1",
+			resource: "PATCH nodes",
+			info:     requestInfo{verb: "patch", version: "v1", kind: "nodes", name: "node-1"},
+		},
+		{
+			method:   "DELETE",
+			url:      "/api/v1/namespaces/default/secrets",
+			resource: "DELETECOLLECTION secrets",
+			info:     requestInfo{verb: "deletecollection", version: "v1", namespace: "default", kind: "secrets"},
+		},
+		{
+			method:   "GET",
+			url:      "/version",
+			resource: "GET /version",
+			info:     requestInfo{verb: "get"},
+		},
+		{
+			method:   "GET",
+			url:      "/apis/apps/v1",
+			resource: "GET /apis/apps/v1",
+			info:     requestInfo{verb: "get"},
+		},
+	} {
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.url, nil)
+			info := parseRequest(req)
+			assert.Equal(t, tc.resource, info.resource())
+			info.path = ""
+			assert.Equal(t, tc.info, info)
+		})
+	}
+}
+
+// slowRateLimiter is a rate limiter delaying all the requests.
+type slowRateLimiter struct {
+	flowcontrol.RateLimiter
+}
+
+func (slowRateLimiter) Wait(context.Context) error {
+	time.Sleep(5 * time.Millisecond)
+	return nil
+}
+
+func TestWrapConfig(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"DeploymentList","apiVersion":"apps/v1","items":[]}`))
+	}))
+	defer s.Close()
+
+	cfg := &rest.Config{Host: s.URL, RateLimiter: slowRateLimiter{flowcontrol.NewFakeAlwaysRateLimiter()}}
+	WrapConfig(cfg)
+	client, err := kubernetes.NewForConfig(cfg)
+	require.NoError(t, err)
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	_, err = client.AppsV1().Deployments("default").List(ctx, meta_v1.ListOptions{})
+	require.NoError(t, err)
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	throttle, request := spans[0], spans[1]
+
+	assert.Equal(t, throttleSpanName, throttle.OperationName())
+	assert.Equal(t, root.Context().SpanID(), throttle.ParentID())
+	assert.GreaterOrEqual(t, throttle.Duration(), 5*time.Millisecond)
+	assert.Equal(t, componentName, throttle.Tag(ext.Component))
+
+	assert.Equal(t, "http.request", request.OperationName())
+	assert.Equal(t, root.Context().SpanID(), request.ParentID())
+	assert.Equal(t, "LIST deployments", request.Tag(ext.ResourceName))
+	assert.Equal(t, "list", request.Tag(tagVerb))
+	assert.Equal(t, "apps", request.Tag(tagAPIGroup))
+	assert.Equal(t, "v1", request.Tag(tagAPIVersion))
+	assert.Equal(t, "default", request.Tag(tagNamespace))
+	assert.Equal(t, "deployments", request.Tag(tagResource))
+	assert.Nil(t, request.Tag(tagSubresource))
+	assert.Equal(t, componentName, request.Tag(ext.Component))
+	assert.Equal(t, ext.SpanKindClient, request.Tag(ext.SpanKind))
+}
+
+func TestWrapConfigRateLimiter(t *testing.T) {
+	cfg := &rest.Config{}
+	WrapConfig(cfg)
+	require.IsType(t, &rateLimiter{}, cfg.RateLimiter)
+	assert.Equal(t, rest.DefaultQPS, cfg.RateLimiter.QPS())
+
+	// client-side throttling is disabled by a negative QPS
+	cfg = &rest.Config{QPS: -1}
+	WrapConfig(cfg)
+	assert.Nil(t, cfg.RateLimiter)
+}
@@ -41,3 +41,28 @@ func Example() {
 
 	fmt.Println(pods.Items)
 }
+
+func ExampleWrapConfig() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		panic(err.Error())
+	}
+	// Use this to trace all calls made to the Kubernetes API, with resource names such as
+	// "LIST pods", and the waits due to client-side throttling.
+	kubernetestrace.WrapConfig(cfg)
+
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	pods, err := client.CoreV1().Pods("default").List(context.TODO(), meta_v1.ListOptions{})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(pods.Items)
+}
@@ -47,7 +47,7 @@ func Middleware(opts ...Option) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// If we have an ignoreRequestFunc, use it to see if we proceed with tracing
-			if cfg.ignoreRequestFunc != nil && cfg.ignoreRequestFunc(c) {
+			if (cfg.ignoreRequestFunc != nil && cfg.ignoreRequestFunc(c)) || httptrace.IgnoreRequest(c.Request()) {
 				return next(c)
 			}
 
@@ -62,6 +62,11 @@ func Middleware(opts ...Option) echo.MiddlewareFunc {
 				tracer.ResourceName(resource),
 				tracer.Tag(ext.HTTPRoute, route),
 				httptrace.HeaderTagsFromRequest(request, cfg.headerTags))
+			for _, name := range cfg.routeParams {
+				if v := c.Param(name); v != "" {
+					opts = append(opts, tracer.Tag(ext.HTTPRouteParams+"."+name, v))
+				}
+			}
 
 			var finishOpts []tracer.FinishOption
 			if cfg.noDebugStack {
@@ -737,3 +737,25 @@ func BenchmarkEchoWithTracing(b *testing.B) {
 		mux.ServeHTTP(w, r)
 	}
 }
+
+func TestWithRouteParams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	router := echo.New()
+	router.Use(Middleware(WithRouteParams("tenant", "id", "missing")))
+	router.GET("/tenants/:tenant/users/:id/:secret", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	r := httptest.NewRequest("GET", "/tenants/acme/users/123/hunter2", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	tags := spans[0].Tags()
+	assert.Equal(t, "acme", tags["http.route.params.tenant"])
+	assert.Equal(t, "123", tags["http.route.params.id"])
+	assert.NotContains(t, tags, "http.route.params.secret")
+	assert.NotContains(t, tags, "http.route.params.missing")
+}
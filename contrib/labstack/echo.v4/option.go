@@ -29,6 +29,7 @@ type config struct {
 	headerTags        instrumentation.HeaderTags
 	errCheck          func(error) bool
 	tags              map[string]interface{}
+	routeParams       []string
 }
 
 // Option describes options for the Echo.v4 integration.
@@ -143,6 +144,16 @@ func WithHeaderTags(headers []string) OptionFn {
 	}
 }
 
+// WithRouteParams enables the integration to tag spans with the values of the given named
+// route parameters, as http.route.params.<name>. Only the parameters in the list are tagged.
+// Warning:
+// Using this feature can risk exposing sensitive data held in paths to Datadog.
+func WithRouteParams(names ...string) OptionFn {
+	return func(cfg *config) {
+		cfg.routeParams = names
+	}
+}
+
 // WithErrorCheck sets the func which determines if err would be ignored (if it returns true, the error is not tagged).
 // This function also checks the errors created from the WithStatusCheck option.
 func WithErrorCheck(errCheck func(error) bool) OptionFn {
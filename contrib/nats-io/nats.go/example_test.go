@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats_test
+
+import (
+	"context"
+	"log"
+
+	natstrace "github.com/DataDog/dd-trace-go/contrib/nats-io/nats.go/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	nc, err := nats.Connect(nats.DefaultURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer nc.Close()
+	conn := natstrace.WrapConn(nc, natstrace.WithService("orders"))
+
+	// Messages handled by traced subscriptions continue the trace of their publisher.
+	_, err = conn.Subscribe("orders.created", func(msg *nats.Msg) {
+		spanCtx, _ := natstrace.ExtractSpanContext(msg)
+		span := tracer.StartSpan("process.order", tracer.ChildOf(spanCtx))
+		defer span.Finish()
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "create.order")
+	defer span.Finish()
+	if err := conn.PublishMsgWithContext(ctx, &nats.Msg{Subject: "orders.created", Data: []byte("{}")}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleConsumer() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	nc, err := nats.Connect(nats.DefaultURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer nc.Close()
+	js, err := natstrace.NewJetStream(nc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c, err := js.Consumer(ctx, "ORDERS", "worker")
+	if err != nil {
+		log.Fatal(err)
+	}
+	batch, err := c.Fetch(10)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for msg := range batch.Messages() {
+		// The span of the message finishes when it is acknowledged.
+		span, _ := natstrace.MessageSpan(msg)
+		child := tracer.StartSpan("process.order", tracer.ChildOf(span.Context()))
+		child.Finish()
+		msg.Ack()
+	}
+}
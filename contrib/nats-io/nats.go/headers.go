@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/nats-io/nats.go"
+)
+
+// headerCarrier implements TextMapReader/TextMapWriter for extracting/injecting traces on NATS message headers.
+type headerCarrier nats.Header
+
+var _ interface {
+	tracer.TextMapReader
+	tracer.TextMapWriter
+} = (headerCarrier)(nil)
+
+// ForeachKey conforms to the TextMapReader interface.
+func (c headerCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vs := range c {
+		for _, v := range vs {
+			if err := handler(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Set implements TextMapWriter.
+func (c headerCarrier) Set(key, val string) {
+	nats.Header(c).Set(key, val)
+}
+
+// ExtractSpanContext retrieves the SpanContext from the headers of a NATS message.
+func ExtractSpanContext(msg *nats.Msg) (*tracer.SpanContext, error) {
+	return tracer.Extract(headerCarrier(msg.Header))
+}
+
+// injectSpanContext injects the context of span in the headers of msg, creating them if needed.
+func injectSpanContext(span *tracer.Span, msg *nats.Msg) {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	if err := tracer.Inject(span.Context(), headerCarrier(msg.Header)); err != nil {
+		instr.Logger().Debug("contrib/nats-io/nats.go: failed to inject span context in message headers: %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// tagStream is the stream a message was published to or consumed from.
+	tagStream = "messaging.nats.jetstream.stream"
+	// tagConsumer is the consumer a message was consumed by.
+	tagConsumer = "messaging.nats.jetstream.consumer"
+	// tagStreamSequence is the sequence number of a message in its stream.
+	tagStreamSequence = "messaging.nats.jetstream.stream_sequence"
+	// tagNumDelivered is the number of times a message was delivered to the consumer.
+	tagNumDelivered = "messaging.nats.jetstream.num_delivered"
+	// tagAck is the acknowledgement of a consumed message: ack, nak or term.
+	tagAck = "messaging.nats.jetstream.ack"
+	// tagAckLatency is the time elapsed between the reception of a message and its
+	// acknowledgement, in milliseconds.
+	tagAckLatency = "messaging.nats.jetstream.ack_latency_ms"
+)
+
+// JetStream wraps a jetstream.JetStream so that the messages it publishes are traced, and the
+// consumers it returns are wrapped with WrapConsumer.
+type JetStream struct {
+	jetstream.JetStream
+	cfg *config
+}
+
+// NewJetStream calls jetstream.New and wraps the resulting JetStream context.
+func NewJetStream(nc *nats.Conn, opts ...Option) (*JetStream, error) {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, err
+	}
+	return WrapJetStream(js, opts...), nil
+}
+
+// WrapJetStream wraps a jetstream.JetStream so that published messages are traced.
+func WrapJetStream(js jetstream.JetStream, opts ...Option) *JetStream {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/nats-io/nats.go: Wrapping JetStream: %#v", cfg)
+	return &JetStream{JetStream: js, cfg: cfg}
+}
+
+// Publish calls jetstream.JetStream.Publish and traces the message.
+func (js *JetStream) Publish(ctx context.Context, subj string, data []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	return js.PublishMsg(ctx, &nats.Msg{Subject: subj, Data: data}, opts...)
+}
+
+// PublishMsg calls jetstream.JetStream.PublishMsg and traces the message, tagging its span with
+// the stream and the sequence number acknowledged by the server.
+func (js *JetStream) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	span := startPublishSpan(ctx, js.cfg, js.Conn(), msg)
+	ack, err := js.JetStream.PublishMsg(ctx, msg, opts...)
+	if ack != nil {
+		span.SetTag(tagStream, ack.Stream)
+		span.SetTag(tagStreamSequence, ack.Sequence)
+	}
+	span.Finish(tracer.WithError(err))
+	return ack, err
+}
+
+// Consumer calls jetstream.JetStream.Consumer and wraps the resulting consumer.
+func (js *JetStream) Consumer(ctx context.Context, stream, consumer string) (jetstream.Consumer, error) {
+	c, err := js.JetStream.Consumer(ctx, stream, consumer)
+	if err != nil {
+		return nil, err
+	}
+	return js.wrapConsumer(c), nil
+}
+
+// CreateConsumer calls jetstream.JetStream.CreateConsumer and wraps the resulting consumer.
+func (js *JetStream) CreateConsumer(ctx context.Context, stream string, cfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+	c, err := js.JetStream.CreateConsumer(ctx, stream, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return js.wrapConsumer(c), nil
+}
+
+// CreateOrUpdateConsumer calls jetstream.JetStream.CreateOrUpdateConsumer and wraps the resulting consumer.
+func (js *JetStream) CreateOrUpdateConsumer(ctx context.Context, stream string, cfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+	c, err := js.JetStream.CreateOrUpdateConsumer(ctx, stream, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return js.wrapConsumer(c), nil
+}
+
+func (js *JetStream) wrapConsumer(c jetstream.Consumer) *Consumer {
+	return &Consumer{Consumer: c, cfg: js.cfg, ackNone: isAckNone(c)}
+}
+
+// Consumer wraps a jetstream.Consumer so that the messages it pulls are traced. The span of
+// a message starts when it is received and finishes when it is acknowledged, with the
+// acknowledgement and its latency as tags. Use MessageSpan to get the span of a message.
+type Consumer struct {
+	jetstream.Consumer
+	cfg *config
+	// ackNone reports whether the messages aren't acknowledged, in which case their span
+	// finishes once they are received, or handled when using Consume.
+	ackNone bool
+}
+
+// WrapConsumer wraps a jetstream.Consumer so that pulled messages are traced.
+func WrapConsumer(c jetstream.Consumer, opts ...Option) *Consumer {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/nats-io/nats.go: Wrapping Consumer: %#v", cfg)
+	return &Consumer{Consumer: c, cfg: cfg, ackNone: isAckNone(c)}
+}
+
+// isAckNone reports whether c is configured with the AckNone policy.
+func isAckNone(c jetstream.Consumer) bool {
+	info := c.CachedInfo()
+	return info != nil && info.Config.AckPolicy == jetstream.AckNonePolicy
+}
+
+// Fetch calls jetstream.Consumer.Fetch, tracing the messages of the batch.
+func (c *Consumer) Fetch(batch int, opts ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+	b, err := c.Consumer.Fetch(batch, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrapBatch(b), nil
+}
+
+// FetchBytes calls jetstream.Consumer.FetchBytes, tracing the messages of the batch.
+func (c *Consumer) FetchBytes(maxBytes int, opts ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+	b, err := c.Consumer.FetchBytes(maxBytes, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrapBatch(b), nil
+}
+
+// FetchNoWait calls jetstream.Consumer.FetchNoWait, tracing the messages of the batch.
+func (c *Consumer) FetchNoWait(batch int) (jetstream.MessageBatch, error) {
+	b, err := c.Consumer.FetchNoWait(batch)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrapBatch(b), nil
+}
+
+// Next calls jetstream.Consumer.Next and traces the message.
+func (c *Consumer) Next(opts ...jetstream.FetchOpt) (jetstream.Msg, error) {
+	msg, err := c.Consumer.Next(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.startMsg(msg, true), nil
+}
+
+// Consume calls jetstream.Consumer.Consume, tracing the messages passed to handler.
+func (c *Consumer) Consume(handler jetstream.MessageHandler, opts ...jetstream.PullConsumeOpt) (jetstream.ConsumeContext, error) {
+	return c.Consumer.Consume(func(msg jetstream.Msg) {
+		tm := c.startMsg(msg, false)
+		handler(tm)
+		if c.ackNone {
+			tm.finishNoAck()
+		}
+	}, opts...)
+}
+
+// wrapBatch returns a batch forwarding the messages of b once their span is started.
+func (c *Consumer) wrapBatch(b jetstream.MessageBatch) jetstream.MessageBatch {
+	tb := &tracedBatch{MessageBatch: b, msgs: make(chan jetstream.Msg, cap(b.Messages()))}
+	go func() {
+		defer close(tb.msgs)
+		for msg := range b.Messages() {
+			tb.msgs <- c.startMsg(msg, true)
+		}
+	}()
+	return tb
+}
+
+// startMsg starts the span of msg and returns the message finishing it when acknowledged.
+// When the consumer doesn't acknowledge messages and finishNoAck is true, the span is
+// finished right away.
+func (c *Consumer) startMsg(msg jetstream.Msg, finishNoAck bool) *tracedMsg {
+	span := startConsumeSpan(c.cfg, nil, msg.Subject(), len(msg.Data()), headerCarrier(msg.Headers()))
+	if md, err := msg.Metadata(); err == nil {
+		span.SetTag(tagStream, md.Stream)
+		span.SetTag(tagConsumer, md.Consumer)
+		span.SetTag(tagStreamSequence, md.Sequence.Stream)
+		span.SetTag(tagNumDelivered, md.NumDelivered)
+	}
+	tm := &tracedMsg{Msg: msg, span: span, received: time.Now()}
+	if c.ackNone && finishNoAck {
+		tm.finishNoAck()
+	}
+	return tm
+}
+
+type tracedBatch struct {
+	jetstream.MessageBatch
+	msgs chan jetstream.Msg
+}
+
+// Messages returns the traced messages of the batch.
+func (b *tracedBatch) Messages() <-chan jetstream.Msg {
+	return b.msgs
+}
+
+// tracedMsg is a jetstream.Msg finishing its span on acknowledgement.
+type tracedMsg struct {
+	jetstream.Msg
+	span     *tracer.Span
+	received time.Time
+	once     sync.Once
+}
+
+// MessageSpan returns the span of a message pulled by a traced consumer, which can be used as
+// the parent of the spans of its processing.
+func MessageSpan(msg jetstream.Msg) (*tracer.Span, bool) {
+	tm, ok := msg.(*tracedMsg)
+	if !ok {
+		return nil, false
+	}
+	return tm.span, true
+}
+
+// finish finishes the span of the message with the given acknowledgement.
+func (m *tracedMsg) finish(ack string, err error) {
+	m.once.Do(func() {
+		m.span.SetTag(tagAck, ack)
+		m.span.SetTag(tagAckLatency, time.Since(m.received).Milliseconds())
+		m.span.Finish(tracer.WithError(err))
+	})
+}
+
+// finishNoAck finishes the span of a message which isn't acknowledged.
+func (m *tracedMsg) finishNoAck() {
+	m.once.Do(func() {
+		m.span.Finish()
+	})
+}
+
+// Ack calls jetstream.Msg.Ack and finishes the span of the message.
+func (m *tracedMsg) Ack() error {
+	err := m.Msg.Ack()
+	m.finish("ack", err)
+	return err
+}
+
+// DoubleAck calls jetstream.Msg.DoubleAck and finishes the span of the message.
+func (m *tracedMsg) DoubleAck(ctx context.Context) error {
+	err := m.Msg.DoubleAck(ctx)
+	m.finish("ack", err)
+	return err
+}
+
+// Nak calls jetstream.Msg.Nak and finishes the span of the message.
+func (m *tracedMsg) Nak() error {
+	err := m.Msg.Nak()
+	m.finish("nak", err)
+	return err
+}
+
+// NakWithDelay calls jetstream.Msg.NakWithDelay and finishes the span of the message.
+func (m *tracedMsg) NakWithDelay(delay time.Duration) error {
+	err := m.Msg.NakWithDelay(delay)
+	m.finish("nak", err)
+	return err
+}
+
+// Term calls jetstream.Msg.Term and finishes the span of the message.
+func (m *tracedMsg) Term() error {
+	err := m.Msg.Term()
+	m.finish("term", err)
+	return err
+}
+
+// TermWithReason calls jetstream.Msg.TermWithReason and finishes the span of the message.
+func (m *tracedMsg) TermWithReason(reason string) error {
+	err := m.Msg.TermWithReason(reason)
+	m.finish("term", err)
+	return err
+}
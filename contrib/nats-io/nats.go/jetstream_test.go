@@ -0,0 +1,175 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMsg struct {
+	jetstream.Msg
+	header nats.Header
+	seq    uint64
+	acks   []string
+}
+
+func (m *fakeMsg) Subject() string      { return "orders.created" }
+func (m *fakeMsg) Data() []byte         { return []byte("payload") }
+func (m *fakeMsg) Headers() nats.Header { return m.header }
+func (m *fakeMsg) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{
+		Sequence:     jetstream.SequencePair{Stream: m.seq},
+		NumDelivered: 1,
+		Stream:       "ORDERS",
+		Consumer:     "worker",
+	}, nil
+}
+func (m *fakeMsg) Ack() error  { m.acks = append(m.acks, "ack"); return nil }
+func (m *fakeMsg) Nak() error  { m.acks = append(m.acks, "nak"); return nil }
+func (m *fakeMsg) Term() error { m.acks = append(m.acks, "term"); return nil }
+
+type fakeBatch struct {
+	msgs chan jetstream.Msg
+}
+
+func (b *fakeBatch) Messages() <-chan jetstream.Msg { return b.msgs }
+func (b *fakeBatch) Error() error                   { return nil }
+
+type fakeConsumer struct {
+	jetstream.Consumer
+	ackPolicy jetstream.AckPolicy
+	msgs      []jetstream.Msg
+}
+
+func (c *fakeConsumer) CachedInfo() *jetstream.ConsumerInfo {
+	return &jetstream.ConsumerInfo{Config: jetstream.ConsumerConfig{AckPolicy: c.ackPolicy}}
+}
+
+func (c *fakeConsumer) Next(...jetstream.FetchOpt) (jetstream.Msg, error) {
+	return c.msgs[0], nil
+}
+
+func (c *fakeConsumer) Fetch(int, ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+	b := &fakeBatch{msgs: make(chan jetstream.Msg, len(c.msgs))}
+	for _, m := range c.msgs {
+		b.msgs <- m
+	}
+	close(b.msgs)
+	return b, nil
+}
+
+// publishedMsg returns a message carrying the context of a new producer span, finished.
+func publishedMsg(t *testing.T, seq uint64) (*fakeMsg, *tracer.Span) {
+	t.Helper()
+	msg := &nats.Msg{Subject: "orders.created"}
+	span := startPublishSpan(context.Background(), defaultConfig(), nil, msg)
+	span.Finish()
+	return &fakeMsg{header: msg.Header, seq: seq}, span
+}
+
+func TestConsumerNext(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	msg, producer := publishedMsg(t, 7)
+	c := WrapConsumer(&fakeConsumer{ackPolicy: jetstream.AckExplicitPolicy, msgs: []jetstream.Msg{msg}})
+	got, err := c.Next()
+	require.NoError(t, err)
+
+	span, ok := MessageSpan(got)
+	require.True(t, ok)
+	// the span finishes on acknowledgement
+	require.Len(t, mt.FinishedSpans(), 1)
+	assert.NoError(t, got.Ack())
+	assert.NoError(t, got.Ack())
+	assert.Equal(t, []string{"ack", "ack"}, msg.acks)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[1]
+	assert.Equal(t, span.Context().SpanID(), s.SpanID())
+	assert.Equal(t, producer.Context().SpanID(), s.ParentID())
+	assert.Equal(t, "nats.consume", s.OperationName())
+	assert.Equal(t, "Consume orders.created", s.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindConsumer, s.Tag(ext.SpanKind))
+	assert.Equal(t, "nats", s.Tag(ext.MessagingSystem))
+	assert.Equal(t, "orders.created", s.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, "nats-io/nats.go", s.Tag(ext.Component))
+	assert.Equal(t, "ORDERS", s.Tag(tagStream))
+	assert.Equal(t, "worker", s.Tag(tagConsumer))
+	assert.Equal(t, float64(7), s.Tag(tagStreamSequence))
+	assert.Equal(t, float64(1), s.Tag(tagNumDelivered))
+	assert.Equal(t, "ack", s.Tag(tagAck))
+	assert.NotNil(t, s.Tag(tagAckLatency))
+}
+
+func TestConsumerFetch(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	msg1, _ := publishedMsg(t, 1)
+	msg2, _ := publishedMsg(t, 2)
+	c := WrapConsumer(&fakeConsumer{ackPolicy: jetstream.AckExplicitPolicy, msgs: []jetstream.Msg{msg1, msg2}})
+	batch, err := c.Fetch(2)
+	require.NoError(t, err)
+
+	var msgs []jetstream.Msg
+	for m := range batch.Messages() {
+		msgs = append(msgs, m)
+	}
+	require.NoError(t, batch.Error())
+	require.Len(t, msgs, 2)
+	assert.NoError(t, msgs[0].Nak())
+	assert.NoError(t, msgs[1].Term())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 4)
+	assert.Equal(t, "nak", spans[2].Tag(tagAck))
+	assert.Equal(t, float64(1), spans[2].Tag(tagStreamSequence))
+	assert.Equal(t, "term", spans[3].Tag(tagAck))
+	assert.Equal(t, float64(2), spans[3].Tag(tagStreamSequence))
+}
+
+func TestConsumerAckNone(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	msg, _ := publishedMsg(t, 1)
+	c := WrapConsumer(&fakeConsumer{ackPolicy: jetstream.AckNonePolicy, msgs: []jetstream.Msg{msg}})
+	_, err := c.Next()
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "nats.consume", spans[1].OperationName())
+	assert.Nil(t, spans[1].Tag(tagAck))
+}
+
+func TestWithService(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	msg, _ := publishedMsg(t, 1)
+	c := WrapConsumer(&fakeConsumer{ackPolicy: jetstream.AckExplicitPolicy, msgs: []jetstream.Msg{msg}}, WithService("orders-worker"))
+	got, err := c.Next()
+	require.NoError(t, err)
+	require.NoError(t, got.Ack())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "nats", spans[0].Tag(ext.ServiceName))
+	assert.Equal(t, "orders-worker", spans[1].Tag(ext.ServiceName))
+}
@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package nats provides functions to trace the nats-io/nats.go package (https://github.com/nats-io/nats.go).
+//
+// The trace context of published messages is propagated in their headers, so that the spans
+// of the messages received by traced subscriptions and JetStream consumers continue the trace
+// of the publisher.
+package nats // import "github.com/DataDog/dd-trace-go/contrib/nats-io/nats.go/v2"
+
+import (
+	"context"
+	"net"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/nats-io/nats.go"
+)
+
+const componentName = instrumentation.PackageNatsIoNatsGo
+
+// tagQueueGroup is the queue group of the subscription which received a message.
+const tagQueueGroup = "messaging.nats.queue_group"
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageNatsIoNatsGo)
+}
+
+// Conn wraps a *nats.Conn so that the messages it publishes and the messages received by its
+// asynchronous subscriptions are traced.
+type Conn struct {
+	*nats.Conn
+	cfg *config
+}
+
+// WrapConn wraps a *nats.Conn so that published and received messages are traced.
+func WrapConn(nc *nats.Conn, opts ...Option) *Conn {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/nats-io/nats.go: Wrapping Conn: %#v", cfg)
+	return &Conn{Conn: nc, cfg: cfg}
+}
+
+// Publish calls nats.Conn.Publish and traces the message.
+func (c *Conn) Publish(subj string, data []byte) error {
+	return c.PublishMsgWithContext(context.Background(), &nats.Msg{Subject: subj, Data: data})
+}
+
+// PublishMsg calls nats.Conn.PublishMsg and traces the message.
+func (c *Conn) PublishMsg(msg *nats.Msg) error {
+	return c.PublishMsgWithContext(context.Background(), msg)
+}
+
+// PublishMsgWithContext calls nats.Conn.PublishMsg and traces the message with a span child
+// of the span in ctx, if any.
+func (c *Conn) PublishMsgWithContext(ctx context.Context, msg *nats.Msg) error {
+	span := startPublishSpan(ctx, c.cfg, c.Conn, msg)
+	err := c.Conn.PublishMsg(msg)
+	span.Finish(tracer.WithError(err))
+	return err
+}
+
+// Subscribe calls nats.Conn.Subscribe, tracing the handling of each received message.
+func (c *Conn) Subscribe(subj string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	return c.Conn.Subscribe(subj, c.wrapHandler(cb))
+}
+
+// QueueSubscribe calls nats.Conn.QueueSubscribe, tracing the handling of each received message.
+func (c *Conn) QueueSubscribe(subj, queue string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	return c.Conn.QueueSubscribe(subj, queue, c.wrapHandler(cb))
+}
+
+// wrapHandler returns a handler running cb within a consumer span. The context of the span
+// is injected in the headers of the message, so that cb can continue the trace with
+// ExtractSpanContext.
+func (c *Conn) wrapHandler(cb nats.MsgHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		span := startConsumeSpan(c.cfg, c.Conn, msg.Subject, len(msg.Data), headerCarrier(msg.Header))
+		if msg.Sub != nil && msg.Sub.Queue != "" {
+			span.SetTag(tagQueueGroup, msg.Sub.Queue)
+		}
+		injectSpanContext(span, msg)
+		defer span.Finish()
+		cb(msg)
+	}
+}
+
+func startPublishSpan(ctx context.Context, cfg *config, nc *nats.Conn, msg *nats.Msg) *tracer.Span {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(cfg.producerServiceName),
+		tracer.ResourceName("Publish " + msg.Subject),
+		tracer.SpanType(ext.SpanTypeMessageProducer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindProducer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemNATS),
+		tracer.Tag(ext.MessagingDestinationName, msg.Subject),
+		tracer.Tag("message_size", len(msg.Data)),
+	}
+	opts = append(opts, peerOptions(nc)...)
+	span, _ := tracer.StartSpanFromContext(ctx, cfg.producerSpanName, opts...)
+	injectSpanContext(span, msg)
+	return span
+}
+
+// startConsumeSpan starts the span of a received message, continuing the trace propagated
+// in its headers.
+func startConsumeSpan(cfg *config, nc *nats.Conn, subject string, size int, carrier headerCarrier) *tracer.Span {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(cfg.consumerServiceName),
+		tracer.ResourceName("Consume " + subject),
+		tracer.SpanType(ext.SpanTypeMessageConsumer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemNATS),
+		tracer.Tag(ext.MessagingDestinationName, subject),
+		tracer.Tag("message_size", size),
+		tracer.Measured(),
+	}
+	opts = append(opts, peerOptions(nc)...)
+	if spanCtx, err := tracer.Extract(carrier); err == nil {
+		opts = append(opts, tracer.ChildOf(spanCtx))
+		if links := spanCtx.SpanLinks(); links != nil {
+			opts = append(opts, tracer.WithSpanLinks(links))
+		}
+	}
+	return tracer.StartSpan(cfg.consumerSpanName, opts...)
+}
+
+// peerOptions returns the options tagging a span with the address of the server nc is connected to.
+func peerOptions(nc *nats.Conn) []tracer.StartSpanOption {
+	if nc == nil {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(nc.ConnectedAddr())
+	if err != nil {
+		return nil
+	}
+	return []tracer.StartSpanOption{
+		tracer.Tag(ext.NetworkDestinationName, host),
+		tracer.Tag(ext.NetworkDestinationPort, port),
+	}
+}
@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const natsURL = "nats://127.0.0.1:4222"
+
+func connect(t *testing.T, opts ...Option) *Conn {
+	t.Helper()
+	if _, ok := os.LookupEnv("INTEGRATION"); !ok {
+		t.Skip("to enable integration test, set the INTEGRATION environment variable")
+	}
+	nc, err := nats.Connect(natsURL)
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	return WrapConn(nc, opts...)
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	nc := connect(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	received := make(chan *tracer.SpanContext, 1)
+	sub, err := nc.QueueSubscribe("test.subject", "workers", func(msg *nats.Msg) {
+		spanCtx, err := ExtractSpanContext(msg)
+		assert.NoError(t, err)
+		received <- spanCtx
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	parent, ctx := tracer.StartSpanFromContext(context.Background(), "parent")
+	err = nc.PublishMsgWithContext(ctx, &nats.Msg{Subject: "test.subject", Data: []byte("hello")})
+	require.NoError(t, err)
+	parent.Finish()
+
+	var consumerCtx *tracer.SpanContext
+	select {
+	case consumerCtx = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+	}
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	spans := mt.FinishedSpans()
+	var producer, consumer *mocktracer.Span
+	for _, s := range spans {
+		switch s.OperationName() {
+		case "nats.publish":
+			producer = s
+		case "nats.consume":
+			consumer = s
+		}
+	}
+	require.NotNil(t, producer)
+	require.NotNil(t, consumer)
+
+	assert.Equal(t, parent.Context().SpanID(), producer.ParentID())
+	assert.Equal(t, "Publish test.subject", producer.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindProducer, producer.Tag(ext.SpanKind))
+	assert.Equal(t, "nats", producer.Tag(ext.MessagingSystem))
+	assert.Equal(t, "test.subject", producer.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, "127.0.0.1", producer.Tag(ext.NetworkDestinationName))
+	assert.Equal(t, float64(5), producer.Tag("message_size"))
+
+	assert.Equal(t, producer.SpanID(), consumer.ParentID())
+	assert.Equal(t, "Consume test.subject", consumer.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindConsumer, consumer.Tag(ext.SpanKind))
+	assert.Equal(t, "workers", consumer.Tag(tagQueueGroup))
+	// the handler continues the trace from the consumer span
+	assert.Equal(t, consumer.SpanID(), consumerCtx.SpanID())
+}
+
+func TestJetStream(t *testing.T) {
+	nc := connect(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	js, err := NewJetStream(nc.Conn)
+	require.NoError(t, err)
+	if _, err := js.AccountInfo(ctx); errors.Is(err, jetstream.ErrJetStreamNotEnabled) {
+		t.Skip("JetStream is not enabled on the server")
+	}
+	_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{Name: "TEST", Subjects: []string{"test.js"}})
+	require.NoError(t, err)
+	defer js.DeleteStream(context.Background(), "TEST")
+
+	ack, err := js.Publish(ctx, "test.js", []byte("hello"))
+	require.NoError(t, err)
+
+	c, err := js.CreateOrUpdateConsumer(ctx, "TEST", jetstream.ConsumerConfig{Durable: "worker", AckPolicy: jetstream.AckExplicitPolicy})
+	require.NoError(t, err)
+	msg, err := c.Next(jetstream.FetchMaxWait(5 * time.Second))
+	require.NoError(t, err)
+	require.NoError(t, msg.Ack())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	producer, consumer := spans[0], spans[1]
+	assert.Equal(t, "nats.publish", producer.OperationName())
+	assert.Equal(t, "TEST", producer.Tag(tagStream))
+	assert.Equal(t, float64(ack.Sequence), producer.Tag(tagStreamSequence))
+	assert.Equal(t, "nats.consume", consumer.OperationName())
+	assert.Equal(t, producer.SpanID(), consumer.ParentID())
+	assert.Equal(t, "worker", consumer.Tag(tagConsumer))
+	assert.Equal(t, "ack", consumer.Tag(tagAck))
+}
@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nats
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	consumerServiceName string
+	producerServiceName string
+	consumerSpanName    string
+	producerSpanName    string
+}
+
+// Option represents an option that can be used to wrap a connection, a JetStream context or a consumer.
+type Option func(*config)
+
+func defaultConfig() *config {
+	return &config{
+		consumerServiceName: instr.ServiceName(instrumentation.ComponentConsumer, nil),
+		producerServiceName: instr.ServiceName(instrumentation.ComponentProducer, nil),
+		consumerSpanName:    instr.OperationName(instrumentation.ComponentConsumer, nil),
+		producerSpanName:    instr.OperationName(instrumentation.ComponentProducer, nil),
+	}
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the published and consumed messages.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.consumerServiceName = name
+		cfg.producerServiceName = name
+	}
+}
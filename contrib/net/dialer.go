@@ -0,0 +1,150 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package net
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/netip"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+)
+
+// Dialer wraps a *net.Dialer so that the connections made with its Dial, DialContext and
+// DialTLSContext methods are traced. The resolution of the host of the address, its
+// connection and its TLS handshake are traced separately.
+//
+// The host of the address is resolved with the resolver of the dialer, traced, and the
+// resolved addresses are attempted in order until a connection succeeds. Unlike net.Dialer,
+// IPv4 and IPv6 addresses aren't raced (RFC 6555) and the timeout of the dialer applies to
+// each attempt.
+type Dialer struct {
+	*net.Dialer
+	cfg      *config
+	resolver *Resolver
+}
+
+// WrapDialer returns the given dialer traced, or a zero net.Dialer if d is nil.
+func WrapDialer(d *net.Dialer, opts ...Option) *Dialer {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/net: Wrapping Dialer: %#v", cfg)
+	if d == nil {
+		d = new(net.Dialer)
+	}
+	return &Dialer{Dialer: d, cfg: cfg, resolver: newResolver(d.Resolver, cfg)}
+}
+
+// Dial calls DialContext with a background context.
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to the address on the named network, as net.Dialer.DialContext does,
+// and traces the resolution of the host of the address and the connection.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || !resolvable(network, host) {
+		// unix sockets and IP literals aren't resolved
+		s := startStep(ctx, d.cfg, opConnect, address)
+		conn, err := d.Dialer.DialContext(ctx, network, address)
+		s.finish(err)
+		return conn, err
+	}
+	ips, err := d.resolver.LookupIP(ctx, ipNetwork(network), host)
+	if err != nil {
+		return nil, err
+	}
+	s := startStep(ctx, d.cfg, opConnect, address)
+	s.setTag(ext.NetworkDestinationName, host)
+	s.setTag(ext.NetworkDestinationPort, port)
+	var (
+		conn     net.Conn
+		firstErr error
+	)
+	for i, ip := range ips {
+		addr := net.JoinHostPort(ip.String(), port)
+		conn, err = d.Dialer.DialContext(ctx, network, addr)
+		if err == nil {
+			s.setTag(ext.NetworkDestinationIP, ip.String())
+			s.setTag(tagAttempts, i+1)
+			s.finish(nil)
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if firstErr == nil {
+		firstErr = &net.DNSError{Err: "no suitable address found", Name: host, IsNotFound: true}
+	}
+	s.finish(firstErr)
+	return nil, firstErr
+}
+
+// DialTLSContext connects to the address on the named network with DialContext and performs
+// the TLS handshake of the connection with the given configuration, which may be nil. The
+// server name of the configuration defaults to the host of the address. The handshake is
+// traced.
+func (d *Dialer) DialTLSContext(ctx context.Context, network, address string, config *tls.Config) (net.Conn, error) {
+	conn, err := d.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = new(tls.Config)
+	}
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		config = config.Clone()
+		config.ServerName = host
+	}
+	s := startStep(ctx, d.cfg, opHandshake, config.ServerName)
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		s.finish(err)
+		return nil, err
+	}
+	state := tlsConn.ConnectionState()
+	s.setTag(tagTLSVersion, tls.VersionName(state.Version))
+	s.setTag(tagTLSCipher, tls.CipherSuiteName(state.CipherSuite))
+	s.setTag(tagTLSResumed, state.DidResume)
+	s.finish(nil)
+	return tlsConn, nil
+}
+
+// resolvable reports whether the host must be resolved before connecting to it on the
+// named network.
+func resolvable(network, host string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return false
+	}
+	if host == "" {
+		return false
+	}
+	_, err := netip.ParseAddr(host)
+	return err != nil
+}
+
+// ipNetwork returns the network of the lookup of a host to connect to on the named network.
+func ipNetwork(network string) string {
+	switch network[len(network)-1] {
+	case '4':
+		return "ip4"
+	case '6':
+		return "ip6"
+	}
+	return "ip"
+}
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package net_test
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	nettrace "github.com/DataDog/dd-trace-go/v2/contrib/net"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	// Trace the DNS resolutions, connections and TLS handshakes of an HTTP client.
+	d := nettrace.WrapDialer(&net.Dialer{Timeout: 5 * time.Second})
+	transport := &http.Transport{
+		DialContext: d.DialContext,
+		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return d.DialTLSContext(ctx, network, addr, &tls.Config{})
+		},
+	}
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequest(http.MethodGet, "https://www.datadoghq.com", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func ExampleWrapResolver() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "lookup")
+	defer span.Finish()
+
+	// Record the lookups as span events of the span in ctx instead of as child spans.
+	r := nettrace.WrapResolver(nil, nettrace.WithSpanEvents(true))
+	if _, err := r.LookupHost(ctx, "www.datadoghq.com"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -6,11 +6,16 @@
 package http
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -147,6 +152,59 @@ func TestWithHeaderTags(t *testing.T) {
 			assert.Equal(strings.Join(r.Header.Values(header), ","), s.Tags()[tag])
 		})
 	})
+
+	t.Run("wildcard-redaction", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		handler := WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-Request-Id", "0123456789abcdef")
+			w.Write([]byte("OK\n"))
+		}), "my-service", "my-resource",
+			WithHeaderTags([]string{"X-Request-*", "X-Tenant-*:tenant"}),
+			WithHeaderTagsRedaction(func(header, value string) string {
+				if header == "X-Request-Id" && len(value) > 8 {
+					return value[:8]
+				}
+				return value
+			}),
+		)
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Request-Id", "fedcba9876543210")
+		r.Header.Set("X-Request-Source", "mobile")
+		r.Header.Set("X-Tenant-Name", "acme")
+		r.Header.Set("X-Other", "other")
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		tags := spans[0].Tags()
+		assert.Equal(t, "fedcba98", tags["http.request.headers.x-request-id"])
+		assert.Equal(t, "mobile", tags["http.request.headers.x-request-source"])
+		assert.Equal(t, "acme", tags["tenant.x-tenant-name"])
+		assert.Equal(t, "01234567", tags["http.response.headers.x-request-id"])
+		assert.NotContains(t, tags, "http.request.headers.x-other")
+	})
+
+	t.Run("response", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		handler := WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-Request-Id", "response")
+			w.Write([]byte("OK\n"))
+		}), "my-service", "my-resource", WithHeaderTags([]string{"X-Request-Id:request_id"}))
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Request-Id", "request")
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		tags := spans[0].Tags()
+		// the response header doesn't overwrite the tag of the request header
+		assert.Equal(t, "request", tags["request_id"])
+		assert.Equal(t, "response", tags["http.response.headers.x-request-id"])
+	})
 }
 
 func TestHttpTracer200(t *testing.T) {
@@ -243,6 +301,79 @@ func TestWrapHandler200(t *testing.T) {
 	assert.Equal("net/http", s.Integration())
 }
 
+func TestWrapHandlerWebSocket(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	done := make(chan struct{})
+	handler := WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		defer close(done)
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		rw.Write([]byte{0x81, 2, 'h', 'i'}) // text frame
+		rw.Flush()
+		// read the masked text frame and the masked close frame of the client
+		_, err = io.ReadFull(rw, make([]byte, 11+6))
+		assert.NoError(t, err)
+	}), "my-service", "my-resource", WithWebSocketMessageEvents(true))
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	fmt.Fprint(conn, "GET /ws HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	frame := make([]byte, 4)
+	_, err = io.ReadFull(br, frame)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hi"), frame[2:])
+	// write the text frame in two parts, splitting its header, and a close frame, with a
+	// zero masking key.
+	conn.Write([]byte{0x81, 0x80 | 5, 0, 0})
+	conn.Write([]byte{0, 0, 'h', 'e', 'l', 'l', 'o'})
+	conn.Write([]byte{0x88, 0x80, 0, 0, 0, 0})
+	<-done
+
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 2 }, time.Second, 10*time.Millisecond)
+	var req, ws *mocktracer.Span
+	for _, s := range mt.FinishedSpans() {
+		switch s.OperationName() {
+		case "http.request":
+			req = s
+		case "websocket.connection":
+			ws = s
+		}
+	}
+	require.NotNil(t, req)
+	require.NotNil(t, ws)
+	assert.Equal(t, "my-service", ws.Tag(ext.ServiceName))
+	assert.Equal(t, "my-resource", ws.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindServer, ws.Tag(ext.SpanKind))
+	assert.Equal(t, "net/http", ws.Tag(ext.Component))
+	assert.Equal(t, float64(1), ws.Tag("websocket.message.received"))
+	assert.Equal(t, float64(1), ws.Tag("websocket.message.sent"))
+	assert.Equal(t, float64(5), ws.Tag("websocket.message.received_bytes"))
+	assert.Equal(t, float64(2), ws.Tag("websocket.message.sent_bytes"))
+	assert.Equal(t, "client", ws.Tag("websocket.closed_by"))
+	assert.NotEqual(t, req.TraceID(), ws.TraceID())
+	links := ws.Links()
+	require.Len(t, links, 1)
+	assert.Equal(t, req.SpanID(), links[0].SpanID)
+
+	events := ws.Events()
+	require.Len(t, events, 2)
+	events[0].AssertAttributes(t, map[string]any{"message.direction": "outgoing", "message.size": 2, "message.type": "text"})
+	events[1].AssertAttributes(t, map[string]any{"message.direction": "incoming", "message.size": 5, "message.type": "text"})
+}
+
 func TestNoStack(t *testing.T) {
 	mt := mocktracer.Start()
 	defer mt.Stop()
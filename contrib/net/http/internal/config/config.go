@@ -8,6 +8,7 @@ package config
 import (
 	"math"
 	"net/http"
+	"strings"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -40,24 +41,38 @@ func (o HandlerOptionFn) apply(cfg *Config) {
 }
 
 type CommonConfig struct {
-	AnalyticsRate float64
-	IgnoreRequest func(*http.Request) bool
-	ServiceName   string
-	ResourceNamer func(*http.Request) string
-	SpanOpts      []tracer.StartSpanOption
-	IsStatusError func(int) bool
+	AnalyticsRate  float64
+	IgnoreRequest  func(*http.Request) bool
+	ServiceName    string
+	ResourceNamer  func(*http.Request) string
+	SpanOpts       []tracer.StartSpanOption
+	IsStatusError  func(int) bool
+	HeaderTags     instrumentation.HeaderTags
+	HeaderRedactor func(header, value string) string
+}
+
+// redactHeaderTags applies the header redactor, if any, to the header tags.
+func (c *CommonConfig) redactHeaderTags() {
+	if c.HeaderTags != nil && c.HeaderRedactor != nil {
+		c.HeaderTags = instrumentation.RedactHeaderTags(c.HeaderTags, c.HeaderRedactor)
+	}
 }
 
 type Config struct {
 	CommonConfig
 	FinishOpts []tracer.FinishOption
-	HeaderTags instrumentation.HeaderTags
+	// WebSocket enables tracing of the WebSocket connections upgraded from traced requests.
+	WebSocket bool
+	// WebSocketMessageEvents enables recording the messages of traced WebSocket connections
+	// as span events.
+	WebSocketMessageEvents bool
 }
 
 func (c *Config) ApplyOpts(opts ...Option) {
 	for _, fn := range opts {
 		fn.apply(c)
 	}
+	c.redactHeaderTags()
 }
 
 func Default(instr *instrumentation.Instrumentation) *Config {
@@ -95,12 +110,51 @@ type RoundTripperConfig struct {
 	ErrCheck      func(err error) bool
 	QueryString   bool // reports whether the query string is included in the URL tag for http client spans
 	IsStatusError func(statusCode int) bool
+	// PropagationAllowlist holds the hosts the trace headers are injected to, all hosts when empty.
+	// Hosts starting with a dot match the subdomains of the following domain.
+	PropagationAllowlist []string
+	// ClientTimings reports whether the phases of the requests are recorded on their spans.
+	ClientTimings bool
+}
+
+// Propagates reports whether the trace and baggage headers are injected into the requests
+// sent to host.
+func (c *RoundTripperConfig) Propagates(host string) bool {
+	if !c.Propagation {
+		return false
+	}
+	if len(c.PropagationAllowlist) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, h := range c.PropagationAllowlist {
+		if h == host || (h[0] == '.' && strings.HasSuffix(host, h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeHosts returns the given hosts in lower case, without surrounding spaces and empty
+// hosts, turning the wildcard domains, such as "*.example.com", into ".example.com".
+func NormalizeHosts(hosts []string) []string {
+	var normalized []string
+	for _, h := range hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		h = strings.TrimPrefix(h, "*")
+		if h == "" || h == "." {
+			continue
+		}
+		normalized = append(normalized, h)
+	}
+	return normalized
 }
 
 func (c *RoundTripperConfig) ApplyOpts(opts ...RoundTripperOption) {
 	for _, fn := range opts {
 		fn.applyRoundTripper(c)
 	}
+	c.redactHeaderTags()
 }
 
 // RoundTripperOption describes options for http.RoundTripper.
@@ -12,4 +12,10 @@ const (
 	EnvClientErrorStatuses = "DD_TRACE_HTTP_CLIENT_ERROR_STATUSES"
 	// EnvQueryStringRegexp is the name of the env var used to specify the regexp to use for query string obfuscation.
 	EnvQueryStringRegexp = "DD_TRACE_OBFUSCATION_QUERY_STRING_REGEXP"
+	// EnvPropagationAllowlist is the name of the env var that specifies the comma-separated list of hosts
+	// the trace and baggage headers are injected to by http clients.
+	EnvPropagationAllowlist = "DD_TRACE_PROPAGATION_HTTP_BAGGAGE_ALLOWLIST"
+	// EnvClientTimingsEnabled is the name of the env var used to specify whether the phases of the requests
+	// of http clients are recorded on their spans.
+	EnvClientTimingsEnabled = "DD_TRACE_HTTP_CLIENT_TIMINGS_ENABLED"
 )
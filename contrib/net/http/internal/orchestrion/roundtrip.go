@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/DataDog/dd-trace-go/contrib/net/http/v2/internal/config"
 	"github.com/DataDog/dd-trace-go/contrib/net/http/v2/internal/wrap"
@@ -41,8 +42,10 @@ var roundTripperConfig = config.RoundTripperConfig{
 		}
 		return func(statusCode int) bool { return statusCode >= 400 && statusCode < 500 }
 	}(),
-	Propagation: true,
-	QueryString: options.GetBoolEnv(config.EnvClientQueryStringEnabled, true),
+	Propagation:          true,
+	PropagationAllowlist: config.NormalizeHosts(strings.Split(os.Getenv(config.EnvPropagationAllowlist), ",")),
+	QueryString:          options.GetBoolEnv(config.EnvClientQueryStringEnabled, true),
+	ClientTimings:        options.GetBoolEnv(config.EnvClientTimingsEnabled, false),
 	SpanNamer: func(*http.Request) string {
 		return config.Instrumentation.OperationName(instrumentation.ComponentClient, nil)
 	},
@@ -41,15 +41,16 @@ func Handler(h http.Handler, service, resource string, opts ...internal.Option)
 		copy(so, cfg.SpanOpts)
 		so = append(so, httptrace.HeaderTagsFromRequest(req, cfg.HeaderTags))
 		pttrn := getPattern(nil, req)
-		TraceAndServe(h, w, req, &httptrace.ServeConfig{
-			Framework:     "net/http",
-			Service:       service,
-			Resource:      resc,
-			FinishOpts:    cfg.FinishOpts,
-			SpanOpts:      so,
-			IsStatusError: cfg.IsStatusError,
-			Route:         pattern.Route(pttrn),
-			RouteParams:   pattern.PathParameters(pttrn, req),
+		TraceAndServe(webSocketHandler(h, req, cfg, service, resc), w, req, &httptrace.ServeConfig{
+			Framework:          "net/http",
+			Service:            service,
+			Resource:           resc,
+			FinishOpts:         cfg.FinishOpts,
+			SpanOpts:           so,
+			IsStatusError:      cfg.IsStatusError,
+			Route:              pattern.Route(pttrn),
+			RouteParams:        pattern.PathParameters(pttrn, req),
+			ResponseHeaderTags: cfg.HeaderTags,
 		})
 	})
 }
@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package wrap
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+const (
+	// tagRedirectHop is the number of redirects followed before a request.
+	tagRedirectHop = "http.redirect.hop"
+	// tagRedirectLocation is the location a response redirects to.
+	tagRedirectLocation = "http.redirect.location"
+)
+
+// spanLink returns a link to the span with the given context, for the given reason.
+func spanLink(spanCtx *tracer.SpanContext, reason string) tracer.SpanLink {
+	link := tracer.SpanLink{
+		TraceID:     spanCtx.TraceIDLower(),
+		TraceIDHigh: spanCtx.TraceIDUpper(),
+		SpanID:      spanCtx.SpanID(),
+		Attributes:  map[string]string{"reason": reason},
+	}
+	if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+		link.Flags = 1
+	}
+	return link
+}
+
+// redirectHop returns the number of redirects followed by the client before req, and the
+// context of the span of the request which was redirected, if it was traced.
+func redirectHop(req *http.Request) (hop int, prev *tracer.SpanContext) {
+	for resp := req.Response; resp != nil && resp.Request != nil; resp = resp.Request.Response {
+		if hop == 0 {
+			if span, ok := tracer.SpanFromContext(resp.Request.Context()); ok {
+				prev = span.Context()
+			}
+		}
+		hop++
+	}
+	return hop, prev
+}
+
+// redirectLocation returns the location of a redirect as reported in span tags, without
+// user information, query string and fragment, which may hold sensitive data.
+func redirectLocation(loc string) string {
+	u, err := url.Parse(loc)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
@@ -55,13 +55,14 @@ func (mux *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	so := make([]tracer.StartSpanOption, len(mux.cfg.SpanOpts), len(mux.cfg.SpanOpts)+1)
 	copy(so, mux.cfg.SpanOpts)
 	so = append(so, httptrace.HeaderTagsFromRequest(r, mux.cfg.HeaderTags))
-	TraceAndServe(mux.ServeMux, w, r, &httptrace.ServeConfig{
-		Framework:     "net/http",
-		Service:       mux.cfg.ServiceName,
-		Resource:      resource,
-		SpanOpts:      so,
-		Route:         route,
-		IsStatusError: mux.cfg.IsStatusError,
-		RouteParams:   pattern.PathParameters(pttrn, r),
+	TraceAndServe(webSocketHandler(mux.ServeMux, r, mux.cfg, mux.cfg.ServiceName, resource), w, r, &httptrace.ServeConfig{
+		Framework:          "net/http",
+		Service:            mux.cfg.ServiceName,
+		Resource:           resource,
+		SpanOpts:           so,
+		Route:              route,
+		IsStatusError:      mux.cfg.IsStatusError,
+		RouteParams:        pattern.PathParameters(pttrn, r),
+		ResponseHeaderTags: mux.cfg.HeaderTags,
 	})
 }
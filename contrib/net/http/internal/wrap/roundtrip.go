@@ -7,10 +7,13 @@ package wrap
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/DataDog/dd-trace-go/contrib/net/http/v2/internal/config"
 	"github.com/DataDog/dd-trace-go/v2/appsec/events"
@@ -59,10 +62,21 @@ func ObserveRoundTrip(cfg *config.RoundTripperConfig, req *http.Request) (*http.
 	if port, err := strconv.Atoi(url.Port()); err == nil {
 		opts = append(opts, tracer.Tag(ext.NetworkDestinationPort, port))
 	}
+	if cfg.HeaderTags != nil {
+		opts = append(opts, httptrace.HeaderTagsFromRequest(req, cfg.HeaderTags))
+	}
 	if len(cfg.SpanOpts) > 0 {
 		opts = append(opts, cfg.SpanOpts...)
 	}
 
+	// Link the span to the span of the request redirected to this one.
+	if hop, prev := redirectHop(req); hop > 0 {
+		opts = append(opts, tracer.Tag(tagRedirectHop, hop))
+		if prev != nil {
+			opts = append(opts, tracer.WithSpanLinks([]tracer.SpanLink{spanLink(prev, "redirect")}))
+		}
+	}
+
 	// Start a new span
 	span, ctx := tracer.StartSpanFromContext(req.Context(), spanName, opts...)
 
@@ -71,12 +85,22 @@ func ObserveRoundTrip(cfg *config.RoundTripperConfig, req *http.Request) (*http.
 		cfg.Before(req, span)
 	}
 
+	if cfg.ClientTimings {
+		ctx = httptrace.WithClientTrace(ctx, span)
+	}
+
 	// Clone the request so we can modify it without causing visible side-effects to the caller...
 	req = req.Clone(ctx)
+	var body *countingBody
+	if req.ContentLength <= 0 && req.Body != nil && req.Body != http.NoBody {
+		// the size of the body is unknown: count the bytes sent by the transport
+		body = &countingBody{ReadCloser: req.Body}
+		req.Body = body
+	}
 	for k, v := range baggage.All(ctx) {
 		span.SetBaggageItem(k, v)
 	}
-	if cfg.Propagation {
+	if cfg.Propagates(req.URL.Hostname()) {
 		// inject the span context into the http request copy
 		err := tracer.Inject(span.Context(), tracer.HTTPHeadersCarrier(req.Header))
 		if err != nil {
@@ -94,6 +118,11 @@ func ObserveRoundTrip(cfg *config.RoundTripperConfig, req *http.Request) (*http.
 	}
 
 	after := func(resp *http.Response, err error) (*http.Response, error) {
+		if body != nil {
+			span.SetTag(ext.HTTPRequestContentLength, body.n.Load())
+		} else {
+			span.SetTag(ext.HTTPRequestContentLength, req.ContentLength)
+		}
 		// Register http errors and observe the status code...
 		if err != nil {
 			span.SetTag("http.errors", err.Error())
@@ -102,6 +131,18 @@ func ObserveRoundTrip(cfg *config.RoundTripperConfig, req *http.Request) (*http.
 			}
 		} else {
 			span.SetTag(ext.HTTPCode, strconv.Itoa(resp.StatusCode))
+			if resp.ContentLength >= 0 {
+				span.SetTag(ext.HTTPResponseContentLength, resp.ContentLength)
+			}
+			if resp.ContentLength < 0 || slices.Contains(resp.TransferEncoding, "chunked") {
+				span.SetTag(ext.HTTPResponseStreamed, true)
+			}
+			for k, v := range httptrace.HeaderTagsFromResponse(resp.Header, cfg.HeaderTags) {
+				span.SetTag(k, v)
+			}
+			if loc := resp.Header.Get("Location"); loc != "" && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+				span.SetTag(tagRedirectLocation, redirectLocation(loc))
+			}
 			if cfg.IsStatusError(resp.StatusCode) {
 				span.SetTag("http.errors", resp.Status)
 				span.SetTag(ext.Error, fmt.Errorf("%d: %s", resp.StatusCode, http.StatusText(resp.StatusCode)))
@@ -128,3 +169,15 @@ func ObserveRoundTrip(cfg *config.RoundTripperConfig, req *http.Request) (*http.
 func identityAfterRoundTrip(resp *http.Response, err error) (*http.Response, error) {
 	return resp, err
 }
+
+// countingBody wraps the body of a request to count the bytes read from it.
+type countingBody struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	return n, err
+}
@@ -14,6 +14,10 @@ import (
 // TraceAndServe serves the handler h using the given ResponseWriter and Request, applying tracing
 // according to the specified config.
 func TraceAndServe(h http.Handler, w http.ResponseWriter, r *http.Request, cfg *httptrace.ServeConfig) {
+	if httptrace.IgnoreRequest(r) {
+		h.ServeHTTP(w, r)
+		return
+	}
 	tw, tr, afterHandle, handled := httptrace.BeforeHandle(cfg, w, r)
 	defer afterHandle()
 
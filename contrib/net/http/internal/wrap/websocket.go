@@ -0,0 +1,320 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package wrap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	internal "github.com/DataDog/dd-trace-go/contrib/net/http/v2/internal/config"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// maxWebSocketMessageEvents is the maximum number of message events added to the span of a
+// WebSocket connection, so that long-lived connections don't grow unbounded spans. Messages
+// are still counted past this limit.
+const maxWebSocketMessageEvents = 1000
+
+// WebSocket opcodes, as defined in RFC 6455, section 5.2.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+)
+
+// handshakeEnd ends the response of the opening handshake, when the handler writes it to
+// the hijacked connection.
+const handshakeEnd = "\r\n\r\n"
+
+// isWebSocketUpgrade reports whether r is a WebSocket opening handshake.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return headerContainsToken(r.Header, "Connection", "upgrade") &&
+		headerContainsToken(r.Header, "Upgrade", "websocket")
+}
+
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// webSocketHandler returns h, tracing the WebSocket connection hijacked from the response
+// writer when r is a WebSocket opening handshake and WebSocket tracing is enabled.
+func webSocketHandler(h http.Handler, r *http.Request, cfg *internal.Config, service, resource string) http.Handler {
+	if !cfg.WebSocket || !isWebSocketUpgrade(r) {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(http.Hijacker); ok {
+			w = &webSocketResponseWriter{
+				ResponseWriter: w,
+				req:            r,
+				cfg:            cfg,
+				service:        service,
+				resource:       resource,
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// webSocketResponseWriter traces the connection hijacked to serve a WebSocket.
+type webSocketResponseWriter struct {
+	http.ResponseWriter
+	req      *http.Request
+	cfg      *internal.Config
+	service  string
+	resource string
+	// switched is whether the response of the opening handshake was written with
+	// WriteHeader, in which case it is sent before the connection is hijacked.
+	switched bool
+}
+
+// Unwrap returns the underlying ResponseWriter, for use by http.ResponseController.
+func (w *webSocketResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *webSocketResponseWriter) WriteHeader(status int) {
+	w.switched = status == http.StatusSwitchingProtocols
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack starts the span of the WebSocket connection, finished when the returned
+// connection is closed.
+func (w *webSocketResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.ResponseWriter.(http.Hijacker).Hijack()
+	if err != nil {
+		return conn, rw, err
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(w.service),
+		tracer.ResourceName(w.resource),
+		tracer.SpanType(ext.SpanTypeWeb),
+		tracer.Tag(ext.SpanKind, ext.SpanKindServer),
+		tracer.Tag(ext.Component, internal.ComponentName),
+		tracer.Tag(ext.HTTPURL, w.req.URL.Path),
+	}
+	// The connection outlives the request, so its span starts a new trace linked to the
+	// span of the opening handshake rather than being its child, which would keep the
+	// trace of the request from being flushed until the connection is closed.
+	if reqSpan, ok := tracer.SpanFromContext(w.req.Context()); ok {
+		spanCtx := reqSpan.Context()
+		link := tracer.SpanLink{
+			TraceID:     spanCtx.TraceIDLower(),
+			TraceIDHigh: spanCtx.TraceIDUpper(),
+			SpanID:      spanCtx.SpanID(),
+			Attributes:  map[string]string{"dd.kind": "executed_by"},
+		}
+		if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+			link.Flags = 1
+		}
+		opts = append(opts, tracer.WithSpanLinks([]tracer.SpanLink{link}))
+	}
+	span := tracer.StartSpan("websocket.connection", opts...)
+	tc := &webSocketConn{
+		Conn:          conn,
+		r:             rw.Reader,
+		span:          span,
+		messageEvents: w.cfg.WebSocketMessageEvents,
+		handshakeDone: w.switched,
+	}
+	return tc, bufio.NewReadWriter(bufio.NewReader(tc), bufio.NewWriter(tc)), nil
+}
+
+// webSocketConn is a connection serving a WebSocket, recording the messages it carries
+// on the span of the connection.
+type webSocketConn struct {
+	net.Conn
+	r             io.Reader // reader of the connection, holding the data buffered before hijacking
+	span          *tracer.Span
+	messageEvents bool
+
+	// handshakeDone is whether the response of the opening handshake was written, after
+	// which the frames written are parsed. handshakeEnd is the length of the prefix of
+	// handshakeEnd written last.
+	handshakeDone bool
+	handshakeEnd  int
+
+	in, out                  frameParser
+	received, sent           atomic.Int64
+	bytesReceived, bytesSent atomic.Int64
+	closedBy                 atomic.Value
+	finishOnce               sync.Once
+}
+
+func (c *webSocketConn) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.in.parse(b[:n], func(opcode byte, size uint64) {
+		c.record("incoming", "client", opcode, size, &c.received, &c.bytesReceived)
+	})
+	return n, err
+}
+
+func (c *webSocketConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	frames := b[:n]
+	if !c.handshakeDone {
+		frames = c.skipHandshake(frames)
+	}
+	c.out.parse(frames, func(opcode byte, size uint64) {
+		c.record("outgoing", "server", opcode, size, &c.sent, &c.bytesSent)
+	})
+	return n, err
+}
+
+// skipHandshake returns the part of b following the end of the response of the opening
+// handshake, if b contains it.
+func (c *webSocketConn) skipHandshake(b []byte) []byte {
+	for i, ch := range b {
+		switch {
+		case ch == handshakeEnd[c.handshakeEnd]:
+			c.handshakeEnd++
+		case ch == handshakeEnd[0]:
+			c.handshakeEnd = 1
+		default:
+			c.handshakeEnd = 0
+		}
+		if c.handshakeEnd == len(handshakeEnd) {
+			c.handshakeDone = true
+			return b[i+1:]
+		}
+	}
+	return nil
+}
+
+// Close closes the connection and finishes its span.
+func (c *webSocketConn) Close() error {
+	err := c.Conn.Close()
+	c.finishOnce.Do(func() {
+		c.span.SetTag("websocket.message.received", c.received.Load())
+		c.span.SetTag("websocket.message.sent", c.sent.Load())
+		c.span.SetTag("websocket.message.received_bytes", c.bytesReceived.Load())
+		c.span.SetTag("websocket.message.sent_bytes", c.bytesSent.Load())
+		if by, ok := c.closedBy.Load().(string); ok {
+			c.span.SetTag("websocket.closed_by", by)
+		}
+		c.span.Finish()
+	})
+	return err
+}
+
+func (c *webSocketConn) record(direction, peer string, opcode byte, size uint64, count, bytes *atomic.Int64) {
+	if opcode == opClose {
+		c.closedBy.CompareAndSwap(nil, peer)
+		return
+	}
+	count.Add(1)
+	bytes.Add(int64(size))
+	if !c.messageEvents || c.received.Load()+c.sent.Load() > maxWebSocketMessageEvents {
+		return
+	}
+	typ := "binary"
+	if opcode == opText {
+		typ = "text"
+	}
+	c.span.AddEvent("websocket.message", tracer.WithSpanEventAttributes(map[string]any{
+		"message.direction": direction,
+		"message.type":      typ,
+		"message.size":      size,
+	}))
+}
+
+// frameParser follows the WebSocket frames written in one direction of a connection,
+// reporting each data message once its last frame is seen, and each close frame.
+type frameParser struct {
+	header    [14]byte
+	n         int    // number of bytes of the header of the current frame read so far
+	remaining uint64 // number of bytes of the payload of the current frame not read yet
+	opcode    byte   // opcode of the current data message
+	size      uint64 // payload size of the current data message so far
+}
+
+// parse follows the frames in b, calling report for each complete message.
+func (p *frameParser) parse(b []byte, report func(opcode byte, size uint64)) {
+	for len(b) > 0 {
+		if p.remaining > 0 {
+			skip := uint64(len(b))
+			if skip > p.remaining {
+				skip = p.remaining
+			}
+			p.remaining -= skip
+			b = b[skip:]
+			continue
+		}
+		c := copy(p.header[p.n:p.headerLen()], b)
+		p.n += c
+		b = b[c:]
+		if p.n < p.headerLen() {
+			continue
+		}
+		p.frame(report)
+	}
+}
+
+// headerLen returns the length of the header of the current frame, as far as it is known.
+func (p *frameParser) headerLen() int {
+	if p.n < 2 {
+		return 2
+	}
+	n := 2
+	switch p.header[1] & 0x7f {
+	case 126:
+		n += 2
+	case 127:
+		n += 8
+	}
+	if p.header[1]&0x80 != 0 {
+		n += 4 // masking key
+	}
+	return n
+}
+
+// frame handles the complete header of the current frame.
+func (p *frameParser) frame(report func(opcode byte, size uint64)) {
+	fin := p.header[0]&0x80 != 0
+	opcode := p.header[0] & 0x0f
+	var length uint64
+	switch l := p.header[1] & 0x7f; l {
+	case 126:
+		length = uint64(binary.BigEndian.Uint16(p.header[2:4]))
+	case 127:
+		length = binary.BigEndian.Uint64(p.header[2:10])
+	default:
+		length = uint64(l)
+	}
+	p.n = 0
+	p.remaining = length
+	switch opcode {
+	case opText, opBinary:
+		p.opcode, p.size = opcode, length
+	case opContinuation:
+		p.size += length
+	case opClose:
+		report(opcode, length)
+		return
+	default:
+		// other control frames (ping and pong) aren't reported, and may be interleaved
+		// with the frames of a fragmented message.
+		return
+	}
+	if fin {
+		report(p.opcode, p.size)
+	}
+}
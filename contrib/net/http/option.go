@@ -9,6 +9,7 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"strings"
 
 	internal "github.com/DataDog/dd-trace-go/contrib/net/http/v2/internal/config"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
@@ -42,16 +43,38 @@ func WithService(name string) OptionFn {
 	}
 }
 
-// WithHeaderTags enables the integration to attach HTTP request headers as span tags.
+// WithHeaderTags enables the integration to attach HTTP request and response headers as span tags.
+// Headers are given as "header" or "header:tag", and may contain '*' wildcards matching any
+// sequence of characters, e.g. "X-Request-*". The tag of a header matching a wildcard is
+// named after the header, prefixed with the given tag if any. The response headers are tagged
+// as "http.response.headers.<header>", whatever the given tag.
 // Warning:
 // Using this feature can risk exposing sensitive data such as authorization tokens to Datadog.
 // Special headers can not be sub-selected. E.g., an entire Cookie header would be transmitted, without the ability to choose specific Cookies.
+// Use WithHeaderTagsRedaction to only capture part of the values of sensitive headers.
 func WithHeaderTags(headers []string) HandlerOptionFn {
 	return func(cfg *internal.Config) {
 		cfg.HeaderTags = instrumentation.NewHeaderTags(headers)
 	}
 }
 
+// WithHeaderTagsRedaction sets a function returning the value to set as a tag for the
+// headers captured as span tags, e.g. to only keep the first 8 characters of request IDs:
+//
+//	WithHeaderTagsRedaction(func(header, value string) string {
+//		if header == "X-Request-Id" && len(value) > 8 {
+//			return value[:8]
+//		}
+//		return value
+//	})
+//
+// The header is passed in canonical format.
+func WithHeaderTagsRedaction(redact func(header, value string) string) OptionFn {
+	return func(cfg *internal.CommonConfig) {
+		cfg.HeaderRedactor = redact
+	}
+}
+
 // WithStatusCheck sets a span to be an error if the passed function
 // returns true for a given status code.
 func WithStatusCheck(fn func(statusCode int) bool) OptionFn {
@@ -107,6 +130,31 @@ func NoDebugStack() HandlerOptionFn {
 	}
 }
 
+// WithWebSockets enables or disables tracing of the WebSocket connections established through
+// the handler. A "websocket.connection" span is started when the connection of a WebSocket
+// opening handshake is hijacked from the ResponseWriter, and finished when it is closed. It
+// is tagged with the number of messages and bytes sent and received, and the peer which
+// initiated the closing handshake. Since connections can live much longer than the request
+// they are upgraded from, their span starts a new trace, linked to the span of the request.
+func WithWebSockets(enabled bool) HandlerOptionFn {
+	return func(cfg *internal.Config) {
+		cfg.WebSocket = enabled
+	}
+}
+
+// WithWebSocketMessageEvents enables or disables recording of the messages of traced WebSocket
+// connections as "websocket.message" events on the span of the connection, with their
+// direction, type and size. Enabling it also enables WebSocket tracing. Only the first 1000
+// messages of a connection are recorded as events.
+func WithWebSocketMessageEvents(enabled bool) HandlerOptionFn {
+	return func(cfg *internal.Config) {
+		cfg.WebSocketMessageEvents = enabled
+		if enabled {
+			cfg.WebSocket = true
+		}
+	}
+}
+
 // RoundTripperOption describes options for http.RoundTripper.
 type RoundTripperOption = internal.RoundTripperOption
 
@@ -134,7 +182,9 @@ func newRoundTripperConfig() *internal.RoundTripperConfig {
 		SpanNamer:     defaultSpanNamer,
 		QueryString:   options.GetBoolEnv(internal.EnvClientQueryStringEnabled, true),
 		IsStatusError: isClientError,
+		ClientTimings: options.GetBoolEnv(internal.EnvClientTimingsEnabled, false),
 	}
+	rtConfig.PropagationAllowlist = internal.NormalizeHosts(strings.Split(os.Getenv(internal.EnvPropagationAllowlist), ","))
 	v := os.Getenv(internal.EnvClientErrorStatuses)
 	if fn := httptrace.GetErrorCodesFromInput(v); fn != nil {
 		rtConfig.IsStatusError = fn
@@ -182,6 +232,36 @@ func WithPropagation(propagation bool) RoundTripperOptionFn {
 	}
 }
 
+// WithPropagationAllowlist restricts the injection of the trace and baggage headers to the
+// requests sent to the given hosts, so that internal identifiers aren't leaked to third-party
+// services. A host such as "*.example.com" or ".example.com" matches all the subdomains of
+// example.com. It overrides the comma-separated list of hosts of the
+// DD_TRACE_PROPAGATION_HTTP_BAGGAGE_ALLOWLIST environment variable. The headers are injected
+// to all hosts when no host is given.
+func WithPropagationAllowlist(hosts ...string) RoundTripperOptionFn {
+	return func(cfg *internal.RoundTripperConfig) {
+		cfg.PropagationAllowlist = internal.NormalizeHosts(hosts)
+	}
+}
+
+// WithClientTimings enables or disables the recording of the phases of the requests on their
+// spans: whether their connection was reused, and the durations of their DNS resolution,
+// connection, TLS handshake and time to first byte, in milliseconds. It overrides the
+// DD_TRACE_HTTP_CLIENT_TIMINGS_ENABLED environment variable, false by default.
+func WithClientTimings(enabled bool) RoundTripperOptionFn {
+	return func(cfg *internal.RoundTripperConfig) {
+		cfg.ClientTimings = enabled
+	}
+}
+
+// WithClientHeaderTags enables the integration to attach the headers of the requests sent and of
+// their responses as span tags, like WithHeaderTags does for the requests received.
+func WithClientHeaderTags(headers []string) RoundTripperOptionFn {
+	return func(cfg *internal.RoundTripperConfig) {
+		cfg.HeaderTags = instrumentation.NewHeaderTags(headers)
+	}
+}
+
 // WithErrorCheck specifies a function fn which determines whether the passed
 // error should be marked as an error. The fn is called whenever an http operation
 // finishes with an error
@@ -33,6 +33,10 @@ func (rt *roundTripper) Unwrap() http.RoundTripper {
 
 // WrapRoundTripper returns a new RoundTripper which traces all requests sent
 // over the transport.
+//
+// Each hop of a chain of redirects followed by the client gets its own span, tagged with the
+// number of redirects followed before it and linked to the span of the redirected request,
+// which is tagged with the redirect location.
 func WrapRoundTripper(rt http.RoundTripper, opts ...RoundTripperOption) http.RoundTripper {
 	if rt == nil {
 		rt = http.DefaultTransport
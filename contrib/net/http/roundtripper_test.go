@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -29,6 +30,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/httptrace"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
 )
 
@@ -102,6 +104,50 @@ func TestRoundTripper(t *testing.T) {
 	assert.Equal(t, float64(wantPort), s1.Tag(ext.NetworkDestinationPort))
 }
 
+func TestRoundTripperRedirects(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/first":
+			http.Redirect(w, r, "/second?token=secret", http.StatusFound)
+		case "/second":
+			http.Redirect(w, r, "/last", http.StatusMovedPermanently)
+		default:
+			w.Write([]byte("Hello World"))
+		}
+	}))
+	defer s.Close()
+
+	client := WrapClient(&http.Client{})
+	resp, err := client.Get(s.URL + "/first")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	first, second, last := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "302", first.Tag(ext.HTTPCode))
+	assert.Equal(t, "/second", first.Tag("http.redirect.location"))
+	assert.Nil(t, first.Tag("http.redirect.hop"))
+	assert.Empty(t, first.Links())
+
+	assert.Equal(t, "301", second.Tag(ext.HTTPCode))
+	assert.Equal(t, "/last", second.Tag("http.redirect.location"))
+	assert.Equal(t, float64(1), second.Tag("http.redirect.hop"))
+	require.Len(t, second.Links(), 1)
+	assert.Equal(t, first.SpanID(), second.Links()[0].SpanID)
+	assert.Equal(t, "redirect", second.Links()[0].Attributes["reason"])
+
+	assert.Equal(t, "200", last.Tag(ext.HTTPCode))
+	assert.Nil(t, last.Tag("http.redirect.location"))
+	assert.Equal(t, float64(2), last.Tag("http.redirect.hop"))
+	require.Len(t, last.Links(), 1)
+	assert.Equal(t, second.SpanID(), last.Links()[0].SpanID)
+}
+
 func makeRequests(rt http.RoundTripper, url string, t *testing.T) {
 	client := &http.Client{
 		Transport: rt,
@@ -450,6 +496,71 @@ func TestRoundTripperStatusCheck(t *testing.T) {
 	assert.Nil(t, spans[0].Tag(ext.Error))
 }
 
+func TestRoundTripperBodySize(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if r.URL.Path == "/stream" {
+			w.Write([]byte("part 1"))
+			w.(http.Flusher).Flush()
+			w.Write([]byte("part 2"))
+			return
+		}
+		w.Write([]byte("hello world"))
+	}))
+	defer s.Close()
+
+	client := &http.Client{Transport: WrapRoundTripper(http.DefaultTransport)}
+	resp, err := client.Post(s.URL+"/sized", "text/plain", strings.NewReader("hello"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	// wrap the body so that its size is unknown
+	resp, err = client.Post(s.URL+"/stream", "text/plain", io.MultiReader(strings.NewReader("chunked body")))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, 5.0, spans[0].Tag(ext.HTTPRequestContentLength))
+	assert.Equal(t, 11.0, spans[0].Tag(ext.HTTPResponseContentLength))
+	assert.Nil(t, spans[0].Tag(ext.HTTPResponseStreamed))
+	assert.Equal(t, 12.0, spans[1].Tag(ext.HTTPRequestContentLength))
+	assert.Nil(t, spans[1].Tag(ext.HTTPResponseContentLength))
+	assert.Equal(t, "true", spans[1].Tag(ext.HTTPResponseStreamed))
+}
+
+func TestRoundTripperHeaderTags(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "0123456789abcdef")
+		w.Write([]byte("OK"))
+	}))
+	defer s.Close()
+
+	rt := WrapRoundTripper(http.DefaultTransport,
+		WithClientHeaderTags([]string{"X-Request-*"}),
+		WithHeaderTagsRedaction(func(_, value string) string {
+			return value[:min(len(value), 8)]
+		}),
+	)
+	req, err := http.NewRequest("GET", s.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Request-Id", "fedcba9876543210")
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	tags := spans[0].Tags()
+	assert.Equal(t, "fedcba98", tags["http.request.headers.x-request-id"])
+	assert.Equal(t, "01234567", tags["http.response.headers.x-request-id"])
+}
+
 func TestRoundTripperURLWithoutPort(t *testing.T) {
 	mt := mocktracer.Start()
 	defer mt.Stop()
@@ -731,6 +842,65 @@ func TestRoundTripperPropagation(t *testing.T) {
 	defer resp.Body.Close()
 }
 
+func TestRoundTripperPropagationAllowlist(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		env    string
+		opts   []RoundTripperOption
+		inject bool
+	}{
+		{name: "default", inject: true},
+		{name: "allowed", opts: []RoundTripperOption{WithPropagationAllowlist("example.com", "127.0.0.1")}, inject: true},
+		{name: "denied", opts: []RoundTripperOption{WithPropagationAllowlist("example.com", "*.127.0.0.1")}, inject: false},
+		{name: "env-allowed", env: "example.com, 127.0.0.1", inject: true},
+		{name: "env-denied", env: ".internal.example.com", inject: false},
+		{name: "option-overrides-env", env: "example.com", opts: []RoundTripperOption{WithPropagationAllowlist("127.0.0.1")}, inject: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(internal.EnvPropagationAllowlist, tt.env)
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			var header http.Header
+			s := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				header = r.Header
+			}))
+			defer s.Close()
+
+			client := &http.Client{Transport: WrapRoundTripper(http.DefaultTransport, tt.opts...)}
+			ctx := baggage.Set(context.Background(), "user.id", "42")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			_, err = tracer.Extract(tracer.HTTPHeadersCarrier(header))
+			if tt.inject {
+				assert.NoError(t, err)
+				assert.Equal(t, "42", header.Get(tracer.DefaultBaggageHeaderPrefix+"user.id"))
+			} else {
+				assert.ErrorIs(t, err, tracer.ErrSpanContextNotFound)
+				assert.Empty(t, header.Get(tracer.DefaultBaggageHeaderPrefix+"user.id"))
+			}
+		})
+	}
+}
+
+func TestPropagates(t *testing.T) {
+	cfg := newRoundTripperConfig()
+	cfg.ApplyOpts(WithPropagationAllowlist("API.example.com", "*.internal.example.com", " ", "*"))
+	assert.Equal(t, []string{"api.example.com", ".internal.example.com"}, cfg.PropagationAllowlist)
+	assert.True(t, cfg.Propagates("api.example.com"))
+	assert.True(t, cfg.Propagates("Users.Internal.example.com"))
+	assert.False(t, cfg.Propagates("internal.example.com"))
+	assert.False(t, cfg.Propagates("example.com"))
+	assert.False(t, cfg.Propagates("evil-api.example.com"))
+
+	cfg.ApplyOpts(WithPropagation(false))
+	assert.False(t, cfg.Propagates("api.example.com"))
+}
+
 type emptyRoundTripper struct{}
 
 func (rt *emptyRoundTripper) RoundTrip(_ *http.Request) (*http.Response, error) {
@@ -739,6 +909,47 @@ func (rt *emptyRoundTripper) RoundTrip(_ *http.Request) (*http.Response, error)
 	return recorder.Result(), nil
 }
 
+func TestRoundTripperClientTimings(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		env     string
+		opts    []RoundTripperOption
+		enabled bool
+	}{
+		{name: "default", enabled: false},
+		{name: "option", opts: []RoundTripperOption{WithClientTimings(true)}, enabled: true},
+		{name: "env", env: "true", enabled: true},
+		{name: "option-overrides-env", env: "true", opts: []RoundTripperOption{WithClientTimings(false)}, enabled: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(internal.EnvClientTimingsEnabled, tt.env)
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte("ok"))
+			}))
+			defer s.Close()
+
+			client := &http.Client{Transport: WrapRoundTripper(&http.Transport{}, tt.opts...)}
+			resp, err := client.Get(s.URL)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			if tt.enabled {
+				assert.Equal(t, float64(0), spans[0].Tag(httptrace.MetricConnReused))
+				assert.IsType(t, float64(0), spans[0].Tag(httptrace.MetricConnect))
+				assert.IsType(t, float64(0), spans[0].Tag(httptrace.MetricTTFB))
+			} else {
+				assert.Nil(t, spans[0].Tag(httptrace.MetricConnReused))
+				assert.Nil(t, spans[0].Tag(httptrace.MetricTTFB))
+			}
+		})
+	}
+}
+
 func TestAppsec(t *testing.T) {
 	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/rasp.json")
 
@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -436,6 +438,72 @@ func TestTraceAndServe(t *testing.T) {
 	})
 }
 
+func TestTraceAndServeBodySize(t *testing.T) {
+	t.Run("sized", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/upload", strings.NewReader("hello"))
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			w.Write([]byte("hello world"))
+		}
+		TraceAndServe(http.HandlerFunc(handler), w, r, &ServeConfig{})
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, 5.0, spans[0].Tag(ext.HTTPRequestContentLength))
+		assert.Equal(t, 11.0, spans[0].Tag(ext.HTTPResponseContentLength))
+		assert.Nil(t, spans[0].Tag(ext.HTTPResponseStreamed))
+	})
+
+	t.Run("streamed", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/upload", strings.NewReader("chunked body"))
+		r.ContentLength = -1
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			w.Write([]byte("part 1"))
+			w.(http.Flusher).Flush()
+			w.Write([]byte("part 2"))
+		}
+		TraceAndServe(http.HandlerFunc(handler), w, r, &ServeConfig{})
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, 12.0, spans[0].Tag(ext.HTTPRequestContentLength))
+		assert.Equal(t, 12.0, spans[0].Tag(ext.HTTPResponseContentLength))
+		assert.Equal(t, "true", spans[0].Tag(ext.HTTPResponseStreamed))
+		assert.True(t, w.Flushed)
+	})
+
+	t.Run("chunked", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+
+		body := strings.Repeat("a", 4096)
+		for _, sized := range []bool{false, true} {
+			handler := func(w http.ResponseWriter, _ *http.Request) {
+				if sized {
+					w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				}
+				w.Write([]byte(body))
+			}
+			TraceAndServe(http.HandlerFunc(handler), httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), &ServeConfig{})
+		}
+
+		spans := mt.FinishedSpans()
+		require.Len(t, spans, 2)
+		// without a Content-Length, the server sends the large response in chunks
+		assert.Equal(t, "true", spans[0].Tag(ext.HTTPResponseStreamed))
+		assert.Nil(t, spans[1].Tag(ext.HTTPResponseStreamed))
+	})
+}
+
 func TestTraceAndServeHost(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package net provides functions to trace the DNS resolutions, connections and TLS handshakes
+// made with the standard library's net package (https://golang.org/pkg/net), so that the
+// latency of a client can be decomposed below the protocol layer.
+//
+// The lookups of a Resolver returned by WrapResolver, and the connections of a Dialer returned
+// by WrapDialer, are traced with spans child of the span found in their context or, with the
+// WithSpanEvents option, with span events added to that span.
+package net // import "github.com/DataDog/dd-trace-go/v2/contrib/net"
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+const componentName = instrumentation.PackageNet
+
+// The names of the spans, or span events, of the traced steps.
+const (
+	opLookup    = "dns.lookup"
+	opConnect   = "net.connect"
+	opHandshake = "tls.handshake"
+)
+
+const (
+	// tagAddresses is the number of addresses returned by a lookup.
+	tagAddresses = "dns.addresses"
+	// tagAttempts is the number of addresses a connection was attempted to.
+	tagAttempts   = "net.attempts"
+	tagTLSVersion = "tls.version"
+	tagTLSCipher  = "tls.cipher_suite"
+	tagTLSResumed = "tls.resumed"
+	// attrDuration is the duration of a step, in nanoseconds, recorded on its span event.
+	attrDuration = "duration_ns"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageNet)
+}
+
+// step traces a DNS lookup, a connection or a TLS handshake, either with a span or with a
+// span event added to the span in the context when the span events are enabled.
+type step struct {
+	span   *tracer.Span
+	parent *tracer.Span
+	name   string
+	start  time.Time
+	attrs  map[string]any
+}
+
+func startStep(ctx context.Context, cfg *config, name, resource string) *step {
+	if cfg.spanEvents {
+		parent, _ := tracer.SpanFromContext(ctx)
+		return &step{
+			parent: parent,
+			name:   name,
+			start:  time.Now(),
+			attrs:  map[string]any{ext.ResourceName: resource},
+		}
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ResourceName(resource),
+		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		tracer.Tag(ext.Component, componentName),
+	}
+	if name == opLookup {
+		opts = append(opts, tracer.SpanType(ext.SpanTypeDNS))
+	}
+	if cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(cfg.serviceName))
+	}
+	span, _ := tracer.StartSpanFromContext(ctx, name, opts...)
+	return &step{span: span, name: name}
+}
+
+func (s *step) setTag(key string, value any) {
+	if s.span != nil {
+		s.span.SetTag(key, value)
+	} else {
+		s.attrs[key] = value
+	}
+}
+
+func (s *step) finish(err error) {
+	if s.span != nil {
+		s.span.Finish(tracer.WithError(err))
+		return
+	}
+	if s.parent == nil {
+		return
+	}
+	s.attrs[attrDuration] = time.Since(s.start).Nanoseconds()
+	if err != nil {
+		s.attrs[ext.ErrorMsg] = err.Error()
+	}
+	s.parent.AddEvent(s.name, tracer.WithSpanEventTimestamp(s.start), tracer.WithSpanEventAttributes(s.attrs))
+}
@@ -0,0 +1,189 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package net
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listen(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// failingResolver is a resolver which can only resolve the hosts of the hosts file.
+var failingResolver = &net.Resolver{
+	PreferGo: true,
+	Dial: func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("no DNS server")
+	},
+}
+
+func TestResolver(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	addrs, err := WrapResolver(nil, WithService("dns")).LookupHost(ctx, "localhost")
+	require.NoError(t, err)
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[0]
+	assert.Equal(t, opLookup, s.OperationName())
+	assert.Equal(t, root.Context().SpanID(), s.ParentID())
+	assert.Equal(t, "dns", s.Tag(ext.ServiceName))
+	assert.Equal(t, "localhost", s.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanTypeDNS, s.Tag(ext.SpanType))
+	assert.Equal(t, "localhost", s.Tag(ext.NetworkDestinationName))
+	assert.Equal(t, float64(len(addrs)), s.Tag(tagAddresses))
+	assert.Equal(t, string(instrumentation.PackageNet), s.Tag(ext.Component))
+}
+
+func TestResolverError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	_, err := WrapResolver(failingResolver).LookupIPAddr(context.Background(), "example.invalid")
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, err.Error(), spans[0].Tag(ext.ErrorMsg))
+	assert.Equal(t, float64(0), spans[0].Tag(tagAddresses))
+}
+
+func TestDialer(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	_, port, err := net.SplitHostPort(listen(t))
+	require.NoError(t, err)
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	conn, err := WrapDialer(&net.Dialer{Resolver: failingResolver}).DialContext(ctx, "tcp4", net.JoinHostPort("localhost", port))
+	require.NoError(t, err)
+	conn.Close()
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	lookup, connect := spans[0], spans[1]
+	assert.Equal(t, opLookup, lookup.OperationName())
+	assert.Equal(t, root.Context().SpanID(), lookup.ParentID())
+	assert.Equal(t, opConnect, connect.OperationName())
+	assert.Equal(t, root.Context().SpanID(), connect.ParentID())
+	assert.Equal(t, "localhost:"+port, connect.Tag(ext.ResourceName))
+	assert.Equal(t, "localhost", connect.Tag(ext.NetworkDestinationName))
+	assert.Equal(t, port, connect.Tag(ext.NetworkDestinationPort))
+	assert.Equal(t, "127.0.0.1", connect.Tag(ext.NetworkDestinationIP))
+	assert.Equal(t, float64(1), connect.Tag(tagAttempts))
+	assert.Equal(t, ext.SpanKindClient, connect.Tag(ext.SpanKind))
+}
+
+func TestDialerIP(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	addr := listen(t)
+	conn, err := WrapDialer(nil).Dial("tcp", addr)
+	require.NoError(t, err)
+	conn.Close()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, opConnect, spans[0].OperationName())
+	assert.Equal(t, addr, spans[0].Tag(ext.ResourceName))
+}
+
+func TestDialerError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	_, err := WrapDialer(&net.Dialer{Resolver: failingResolver}).Dial("tcp", "example.invalid:80")
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, opLookup, spans[0].OperationName())
+	assert.Equal(t, err.Error(), spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestDialTLS(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+	config := srv.Client().Transport.(*http.Transport).TLSClientConfig
+	conn, err := WrapDialer(nil).DialTLSContext(context.Background(), "tcp", srv.Listener.Addr().String(), config)
+	require.NoError(t, err)
+	conn.Close()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[1]
+	assert.Equal(t, opHandshake, s.OperationName())
+	assert.Equal(t, "127.0.0.1", s.Tag(ext.ResourceName))
+	assert.Equal(t, "TLS 1.3", s.Tag(tagTLSVersion))
+	assert.NotEmpty(t, s.Tag(tagTLSCipher))
+	assert.Equal(t, false, s.Tag(tagTLSResumed))
+}
+
+func TestSpanEvents(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	_, port, err := net.SplitHostPort(listen(t))
+	require.NoError(t, err)
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	d := WrapDialer(&net.Dialer{Resolver: failingResolver}, WithSpanEvents(true))
+	conn, err := d.DialContext(ctx, "tcp4", net.JoinHostPort("localhost", port))
+	require.NoError(t, err)
+	conn.Close()
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, opLookup, events[0].Name)
+	assert.Equal(t, "localhost", events[0].Attributes[ext.NetworkDestinationName])
+	assert.Contains(t, events[0].Attributes, attrDuration)
+	assert.Equal(t, opConnect, events[1].Name)
+	assert.Equal(t, "127.0.0.1", events[1].Attributes[ext.NetworkDestinationIP])
+	assert.Contains(t, events[1].Attributes, attrDuration)
+
+	// without a span in the context, the events are dropped
+	conn, err = d.DialContext(context.Background(), "tcp4", net.JoinHostPort("localhost", port))
+	require.NoError(t, err)
+	conn.Close()
+	assert.Len(t, mt.FinishedSpans(), 1)
+}
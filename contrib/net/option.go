@@ -0,0 +1,39 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package net
+
+type config struct {
+	serviceName string
+	spanEvents  bool
+}
+
+// Option describes options for the net integration.
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := new(config)
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the service name of the spans of the lookups, connections and handshakes.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithSpanEvents records the lookups, connections and handshakes as span events added to the
+// span found in their context, instead of as child spans, disabled by default. The events
+// hold the duration of the step in nanoseconds and are dropped when there is no span in the
+// context.
+func WithSpanEvents(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.spanEvents = enabled
+	}
+}
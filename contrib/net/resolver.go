@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package net
+
+import (
+	"context"
+	"net"
+	"net/netip"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+)
+
+// Resolver wraps a *net.Resolver so that its LookupHost, LookupIPAddr, LookupIP and
+// LookupNetIP methods are traced.
+type Resolver struct {
+	*net.Resolver
+	cfg *config
+}
+
+// WrapResolver returns the given resolver traced, or net.DefaultResolver if r is nil.
+func WrapResolver(r *net.Resolver, opts ...Option) *Resolver {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/net: Wrapping Resolver: %#v", cfg)
+	return newResolver(r, cfg)
+}
+
+func newResolver(r *net.Resolver, cfg *config) *Resolver {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	return &Resolver{Resolver: r, cfg: cfg}
+}
+
+func (r *Resolver) startLookup(ctx context.Context, host string) *step {
+	s := startStep(ctx, r.cfg, opLookup, host)
+	s.setTag(ext.NetworkDestinationName, host)
+	return s
+}
+
+// LookupHost calls net.Resolver.LookupHost and traces the lookup.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	s := r.startLookup(ctx, host)
+	addrs, err := r.Resolver.LookupHost(ctx, host)
+	s.setTag(tagAddresses, len(addrs))
+	s.finish(err)
+	return addrs, err
+}
+
+// LookupIPAddr calls net.Resolver.LookupIPAddr and traces the lookup.
+func (r *Resolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	s := r.startLookup(ctx, host)
+	addrs, err := r.Resolver.LookupIPAddr(ctx, host)
+	s.setTag(tagAddresses, len(addrs))
+	s.finish(err)
+	return addrs, err
+}
+
+// LookupIP calls net.Resolver.LookupIP and traces the lookup.
+func (r *Resolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	s := r.startLookup(ctx, host)
+	addrs, err := r.Resolver.LookupIP(ctx, network, host)
+	s.setTag(tagAddresses, len(addrs))
+	s.finish(err)
+	return addrs, err
+}
+
+// LookupNetIP calls net.Resolver.LookupNetIP and traces the lookup.
+func (r *Resolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	s := r.startLookup(ctx, host)
+	addrs, err := r.Resolver.LookupNetIP(ctx, network, host)
+	s.setTag(tagAddresses, len(addrs))
+	s.finish(err)
+	return addrs, err
+}
@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package smtp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/smtp"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// Client wraps an *smtp.Client so that the messages it sends are traced. A message is traced
+// from the call to Mail, or MailContext, to the closing of the writer returned by Data. The
+// span is finished early with the error of the first failed command.
+type Client struct {
+	*smtp.Client
+	cfg  *config
+	addr string
+
+	span *tracer.Span
+	to   []string
+	size int
+}
+
+// Dial calls smtp.Dial and wraps the returned client.
+func Dial(addr string, opts ...Option) (*Client, error) {
+	c, err := smtp.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	return WrapClient(c, addr, opts...), nil
+}
+
+// NewClient calls smtp.NewClient and wraps the returned client.
+func NewClient(conn net.Conn, host string, opts ...Option) (*Client, error) {
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return nil, err
+	}
+	return WrapClient(c, conn.RemoteAddr().String(), opts...), nil
+}
+
+// WrapClient wraps an *smtp.Client connected to the server at addr so that the messages it
+// sends are traced.
+func WrapClient(c *smtp.Client, addr string, opts ...Option) *Client {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/net/smtp: Wrapping Client: %#v", cfg)
+	return &Client{Client: c, cfg: cfg, addr: addr}
+}
+
+// Mail calls smtp.Client.Mail and starts the span of a message.
+func (c *Client) Mail(from string) error {
+	return c.MailContext(context.Background(), from)
+}
+
+// MailContext calls smtp.Client.Mail and starts the span of a message, child of the span in
+// ctx, if any.
+func (c *Client) MailContext(ctx context.Context, from string) error {
+	c.finish(nil)
+	c.span = startSpan(ctx, c.cfg, c.addr)
+	err := c.Client.Mail(from)
+	if err != nil {
+		c.finish(err)
+	}
+	return err
+}
+
+// Rcpt calls smtp.Client.Rcpt and records the recipient in the span of the message.
+func (c *Client) Rcpt(to string) error {
+	err := c.Client.Rcpt(to)
+	if err != nil {
+		c.finish(err)
+		return err
+	}
+	c.to = append(c.to, to)
+	return nil
+}
+
+// Data calls smtp.Client.Data. The span of the message is finished when the returned writer
+// is closed.
+func (c *Client) Data() (io.WriteCloser, error) {
+	w, err := c.Client.Data()
+	if err != nil {
+		c.finish(err)
+		return nil, err
+	}
+	return &dataWriter{WriteCloser: w, c: c}, nil
+}
+
+// Reset calls smtp.Client.Reset, finishing the span of the message being sent, if any.
+func (c *Client) Reset() error {
+	c.finish(nil)
+	return c.Client.Reset()
+}
+
+// Quit calls smtp.Client.Quit, finishing the span of the message being sent, if any.
+func (c *Client) Quit() error {
+	c.finish(nil)
+	return c.Client.Quit()
+}
+
+// Close calls smtp.Client.Close, finishing the span of the message being sent, if any.
+func (c *Client) Close() error {
+	c.finish(nil)
+	return c.Client.Close()
+}
+
+func (c *Client) finish(err error) {
+	if c.span == nil {
+		return
+	}
+	finishSpan(c.span, c.to, c.size, err)
+	c.span, c.to, c.size = nil, nil, 0
+}
+
+// dataWriter counts the bytes of a message and finishes its span when closed.
+type dataWriter struct {
+	io.WriteCloser
+	c *Client
+}
+
+func (w *dataWriter) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.c.size += n
+	return n, err
+}
+
+func (w *dataWriter) Close() error {
+	err := w.WriteCloser.Close()
+	w.c.finish(err)
+	return err
+}
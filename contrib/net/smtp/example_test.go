@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package smtp_test
+
+import (
+	"context"
+	"log"
+	"net/smtp"
+
+	smtptrace "github.com/DataDog/dd-trace-go/contrib/net/smtp/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "signup")
+	defer span.Finish()
+
+	auth := smtp.PlainAuth("", "user@example.com", "password", "mail.example.com")
+	err := smtptrace.SendMailContext(ctx, "mail.example.com:587", auth, "noreply@example.com",
+		[]string{"alice@example.com"}, []byte("Subject: Welcome\r\n\r\nWelcome!\r\n"))
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleDial() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	c, err := smtptrace.Dial("mail.example.com:25")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Quit()
+	// The message is traced from the call to Mail to the closing of the writer returned by Data.
+	if err := c.Mail("noreply@example.com"); err != nil {
+		log.Fatal(err)
+	}
+	if err := c.Rcpt("alice@example.com"); err != nil {
+		log.Fatal(err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := w.Write([]byte("Subject: Welcome\r\n\r\nWelcome!\r\n")); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package smtp
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	serviceName string
+	spanName    string
+}
+
+// Option represents an option that can be used to trace the delivery of mail.
+type Option func(*config)
+
+func defaultConfig() *config {
+	return &config{
+		serviceName: instr.ServiceName(instrumentation.ComponentClient, nil),
+		spanName:    instr.OperationName(instrumentation.ComponentClient, nil),
+	}
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the spans of the delivered messages.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
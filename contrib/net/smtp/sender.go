@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package smtp
+
+import (
+	"context"
+	"io"
+)
+
+// Sender is the interface of the senders of mail libraries, such as gopkg.in/mail.v2 and
+// gopkg.in/gomail.v2, whose SendCloser values implement it.
+type Sender interface {
+	Send(from string, to []string, msg io.WriterTo) error
+}
+
+// WrapSender wraps a Sender connected to the server at addr so that the messages it sends
+// are traced. The spans of the messages are children of the span in ctx, if any. The
+// returned Sender implements io.Closer, closing s if it implements it too.
+func WrapSender(ctx context.Context, s Sender, addr string, opts ...Option) Sender {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/net/smtp: Wrapping Sender: %#v", cfg)
+	return &sender{Sender: s, ctx: ctx, cfg: cfg, addr: addr}
+}
+
+type sender struct {
+	Sender
+	ctx  context.Context
+	cfg  *config
+	addr string
+}
+
+func (s *sender) Send(from string, to []string, msg io.WriterTo) error {
+	span := startSpan(s.ctx, s.cfg, s.addr)
+	m := &countingWriterTo{WriterTo: msg}
+	err := s.Sender.Send(from, to, m)
+	finishSpan(span, to, int(m.n), err)
+	return err
+}
+
+// Close closes the wrapped Sender, if it implements io.Closer.
+func (s *sender) Close() error {
+	if c, ok := s.Sender.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// countingWriterTo counts the bytes of a message as they are written by the sender.
+type countingWriterTo struct {
+	io.WriterTo
+	n int64
+}
+
+func (m *countingWriterTo) WriteTo(w io.Writer) (int64, error) {
+	n, err := m.WriterTo.WriteTo(w)
+	m.n += n
+	return n, err
+}
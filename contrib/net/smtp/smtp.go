@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package smtp provides functions to trace the delivery of mail with the net/smtp package
+// (https://golang.org/pkg/net/smtp) and with the libraries built on it.
+//
+// The spans of the delivered messages are tagged with the SMTP server, the size of the
+// messages and their number of recipients. The addresses of the recipients are never
+// tagged: a hash of them is, so that the messages sent to the same recipients can be grouped.
+package smtp // import "github.com/DataDog/dd-trace-go/contrib/net/smtp/v2"
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"slices"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+const componentName = instrumentation.PackageNetSMTP
+
+const (
+	tagRecipientCount = "smtp.recipient_count"
+	// tagRecipientsHash is a hash of the addresses of the recipients of a message.
+	tagRecipientsHash = "smtp.recipients_hash"
+	// tagStatusCode is the status code of the reply of the server to a failed command.
+	tagStatusCode = "smtp.status_code"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageNetSMTP)
+}
+
+// SendMail calls smtp.SendMail and traces the delivery of the message.
+func SendMail(addr string, a smtp.Auth, from string, to []string, msg []byte, opts ...Option) error {
+	return SendMailContext(context.Background(), addr, a, from, to, msg, opts...)
+}
+
+// SendMailContext calls smtp.SendMail and traces the delivery of the message with a span
+// child of the span in ctx, if any.
+func SendMailContext(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte, opts ...Option) error {
+	span := startSpan(ctx, newConfig(opts...), addr)
+	err := smtp.SendMail(addr, a, from, to, msg)
+	finishSpan(span, to, len(msg), err)
+	return err
+}
+
+func startSpan(ctx context.Context, cfg *config, addr string) *tracer.Span {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(cfg.serviceName),
+		tracer.ResourceName(host),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemSMTP),
+		tracer.Tag(ext.NetworkDestinationName, host),
+	}
+	if port != "" {
+		opts = append(opts, tracer.Tag(ext.NetworkDestinationPort, port))
+	}
+	span, _ := tracer.StartSpanFromContext(ctx, cfg.spanName, opts...)
+	return span
+}
+
+// finishSpan finishes the span of a message with the given recipients and size.
+func finishSpan(span *tracer.Span, to []string, size int, err error) {
+	span.SetTag(tagRecipientCount, len(to))
+	if len(to) > 0 {
+		span.SetTag(tagRecipientsHash, recipientsHash(to))
+	}
+	if size >= 0 {
+		span.SetTag("message_size", size)
+	}
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) {
+		span.SetTag(tagStatusCode, tpErr.Code)
+	}
+	span.Finish(tracer.WithError(err))
+}
+
+// recipientsHash returns a hash of the given addresses, independent of their order and case.
+func recipientsHash(to []string) string {
+	addrs := make([]string, len(to))
+	for i, a := range to {
+		addrs[i] = strings.ToLower(strings.TrimSpace(a))
+	}
+	slices.Sort(addrs)
+	sum := sha256.Sum256([]byte(strings.Join(addrs, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
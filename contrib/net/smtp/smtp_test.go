@@ -0,0 +1,184 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package smtp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const msg = "Subject: hello\r\n\r\nHello, world!\r\n"
+
+// startServer starts a minimal SMTP server, rejecting the recipients of the domain
+// rejected.example.com, and returns its address.
+func startServer(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { fmt.Fprintf(conn, "%s\r\n", s) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "RCPT") && strings.Contains(cmd, "REJECTED.EXAMPLE.COM"):
+			reply("550 mailbox unavailable")
+		case strings.HasPrefix(cmd, "DATA"):
+			reply("354 go ahead")
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+			}
+			reply("250 queued")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSendMail(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	addr := startServer(t)
+	root := tracer.StartSpan("root")
+	ctx := tracer.ContextWithSpan(context.Background(), root)
+	to := []string{"bob@example.com", "Alice@example.com"}
+	err := SendMailContext(ctx, addr, nil, "carol@example.com", to, []byte(msg))
+	require.NoError(t, err)
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[0]
+	host, port, _ := net.SplitHostPort(addr)
+	assert.Equal(t, "smtp.send", s.OperationName())
+	assert.Equal(t, "smtp", s.Tag(ext.ServiceName))
+	assert.Equal(t, host, s.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindClient, s.Tag(ext.SpanKind))
+	assert.Equal(t, ext.MessagingSystemSMTP, s.Tag(ext.MessagingSystem))
+	assert.Equal(t, "net/smtp", s.Tag(ext.Component))
+	assert.Equal(t, host, s.Tag(ext.NetworkDestinationName))
+	assert.Equal(t, port, s.Tag(ext.NetworkDestinationPort))
+	assert.Equal(t, float64(2), s.Tag(tagRecipientCount))
+	assert.Equal(t, recipientsHash([]string{"alice@example.com", "bob@example.com"}), s.Tag(tagRecipientsHash))
+	assert.Equal(t, float64(len(msg)), s.Tag("message_size"))
+	assert.Equal(t, root.Context().SpanID(), s.ParentID())
+	for _, v := range s.Tags() {
+		if str, ok := v.(string); ok {
+			assert.NotContains(t, str, "@example.com")
+		}
+	}
+}
+
+func TestSendMailError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	addr := startServer(t)
+	err := SendMail(addr, nil, "carol@example.com", []string{"bob@rejected.example.com"}, []byte(msg))
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, float64(550), spans[0].Tag(tagStatusCode))
+	assert.NotNil(t, spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestClient(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c, err := Dial(startServer(t), WithService("mailer"))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, c.Mail("carol@example.com"))
+		require.NoError(t, c.Rcpt("bob@example.com"))
+		w, err := c.Data()
+		require.NoError(t, err)
+		_, err = io.WriteString(w, msg)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+	require.NoError(t, c.Mail("carol@example.com"))
+	require.NoError(t, c.Reset())
+	require.NoError(t, c.Quit())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	for _, s := range spans[:2] {
+		assert.Equal(t, "mailer", s.Tag(ext.ServiceName))
+		assert.Equal(t, float64(1), s.Tag(tagRecipientCount))
+		assert.Equal(t, float64(len(msg)), s.Tag("message_size"))
+		assert.Nil(t, s.Tag(ext.ErrorMsg))
+	}
+}
+
+type fakeSender struct {
+	buf bytes.Buffer
+}
+
+func (s *fakeSender) Send(_ string, _ []string, msg io.WriterTo) error {
+	_, err := msg.WriteTo(&s.buf)
+	return err
+}
+
+func TestWrapSender(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	s := WrapSender(context.Background(), &fakeSender{}, "smtp.example.com:587")
+	err := s.Send("carol@example.com", []string{"bob@example.com"}, bytes.NewBufferString(msg))
+	require.NoError(t, err)
+	assert.NoError(t, s.(io.Closer).Close())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "smtp.example.com", spans[0].Tag(ext.NetworkDestinationName))
+	assert.Equal(t, "587", spans[0].Tag(ext.NetworkDestinationPort))
+	assert.Equal(t, float64(len(msg)), spans[0].Tag("message_size"))
+}
@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package opensearch_test
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	opensearchtrace "github.com/DataDog/dd-trace-go/contrib/opensearch-project/opensearch-go.v4/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/opensearch-project/opensearch-go/v4"
+	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	client, err := opensearchapi.NewClient(opensearchapi.Config{
+		Client: opensearch.Config{
+			Addresses: []string{"http://127.0.0.1:9200"},
+			// Trace the requests of the client.
+			Transport: opensearchtrace.NewRoundTripper(opensearchtrace.WithService("my-search")),
+		},
+	})
+	if err != nil {
+		log.Fatalf("Error creating the client: %s", err)
+	}
+
+	// Use a context to pass information down the call chain
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "parent.request",
+		tracer.ServiceName("web"),
+		tracer.ResourceName("/tweet/1"),
+	)
+	defer root.Finish()
+
+	_, err = client.Index(ctx, opensearchapi.IndexReq{
+		Index:      "twitter",
+		DocumentID: "1",
+		Body:       strings.NewReader(`{"user": "test", "message": "hello"}`),
+	})
+	if err != nil {
+		log.Fatalf("Error indexing the document: %s", err)
+	}
+}
@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package opensearch provides functions to trace the github.com/opensearch-project/opensearch-go
+// package (https://github.com/opensearch-project/opensearch-go).
+//
+// The requests of a client are traced by the http.RoundTripper returned by NewRoundTripper,
+// to be set as the Transport of the configuration of the client.
+package opensearch // import "github.com/DataDog/dd-trace-go/contrib/opensearch-project/opensearch-go.v4/v2"
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+const componentName = instrumentation.PackageOpenSearchGoV4
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageOpenSearchGoV4)
+}
+
+// NewRoundTripper returns a new http.RoundTripper which traces the OpenSearch requests made
+// through it.
+func NewRoundTripper(opts ...ClientOption) http.RoundTripper {
+	cfg := new(clientConfig)
+	defaults(cfg)
+	for _, fn := range opts {
+		fn.apply(cfg)
+	}
+	instr.Logger().Debug("contrib/opensearch-project/opensearch-go.v4: Configuring RoundTripper: %#v", cfg)
+	return &roundTripper{config: *cfg}
+}
+
+// roundTripper is an implementation of http.RoundTripper that captures OpenSearch spans.
+type roundTripper struct {
+	config clientConfig
+}
+
+var _ http.RoundTripper = &roundTripper{}
+
+// RoundTrip satisfies the RoundTripper interface, wraps the sub Transport and
+// captures a span of the OpenSearch request.
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.Path
+	method := req.Method
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(t.config.serviceName),
+		tracer.SpanType(ext.SpanTypeOpenSearch),
+		tracer.ResourceName(t.config.resourceNamer(url, method)),
+		tracer.Tag("opensearch.method", method),
+		tracer.Tag("opensearch.url", url),
+		tracer.Tag("opensearch.params", req.URL.Query().Encode()),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		tracer.Tag(ext.DBSystem, ext.DBSystemOpenSearch),
+		tracer.Tag(ext.NetworkDestinationName, req.URL.Hostname()),
+	}
+	if !math.IsNaN(t.config.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, t.config.analyticsRate))
+	}
+	span, _ := tracer.StartSpanFromContext(req.Context(), t.config.operationName, opts...)
+	defer span.Finish()
+
+	contentEncoding := req.Header.Get("Content-Encoding")
+	if t.config.bodyCutoff > 0 {
+		snip, rc, err := peek(req.Body, contentEncoding, int(req.ContentLength), t.config.bodyCutoff)
+		if err == nil {
+			span.SetTag("opensearch.body", snip)
+		}
+		req.Body = rc
+	}
+	res, err := t.config.transport.RoundTrip(req)
+	if err != nil {
+		span.SetTag(ext.Error, err)
+	} else if res.StatusCode < 200 || res.StatusCode > 299 {
+		// the error message is the start of the body, read regardless of the body cutoff
+		snip, rc, err := peek(res.Body, res.Header.Get("Content-Encoding"), int(res.ContentLength), errorBodyCutoff)
+		if err != nil || snip == "" {
+			snip = http.StatusText(res.StatusCode)
+		}
+		span.SetTag(ext.Error, errors.New(snip))
+		res.Body = rc
+	} else if t.config.bulkItems && isBulk(url) {
+		tagBulkResponse(span, res)
+	}
+	if res != nil {
+		span.SetTag(ext.HTTPCode, strconv.Itoa(res.StatusCode))
+	}
+	return res, err
+}
+
+// errorBodyCutoff is the maximum number of bytes of the body of an error response used as the
+// error message of its span.
+const errorBodyCutoff = 5 * 1024
+
+var (
+	idRegexp         = regexp.MustCompile(`/([0-9]+)([/\?]|$)`)
+	idPlaceholder    = []byte("/?$2")
+	indexRegexp      = regexp.MustCompile("[0-9]{2,}")
+	indexPlaceholder = []byte("?")
+)
+
+// documentEndpoints are the endpoints followed by the ID of a document in a path.
+var documentEndpoints = map[string]bool{
+	"_doc":    true,
+	"_create": true,
+	"_update": true,
+	"_source": true,
+}
+
+// quantize quantizes an OpenSearch request to extract a meaningful resource from it, based on
+// its method and URL. The IDs of the documents, numeric IDs and (potential) timestamped
+// indices are generalized.
+func quantize(url, method string) string {
+	segments := strings.Split(url, "/")
+	for i := 1; i < len(segments); i++ {
+		if documentEndpoints[segments[i-1]] && segments[i] != "" {
+			segments[i] = "?"
+		}
+	}
+	quantizedURL := idRegexp.ReplaceAll([]byte(strings.Join(segments, "/")), idPlaceholder)
+	quantizedURL = indexRegexp.ReplaceAll(quantizedURL, indexPlaceholder)
+	return method + " " + string(quantizedURL)
+}
+
+// peek attempts to return the first n bytes, as a string, from the provided io.ReadCloser.
+// It returns a new io.ReadCloser which points to the same underlying stream and can be read
+// from to access the entire data including the snippet. max is used to specify the length
+// of the stream contained in the reader. If unknown, it should be -1. If 0 < max < n it
+// will override n.
+func peek(rc io.ReadCloser, encoding string, max, n int) (string, io.ReadCloser, error) {
+	if rc == nil || rc == http.NoBody {
+		return "", rc, errors.New("empty stream")
+	}
+	if max > 0 && max < n {
+		n = max
+	}
+	r := bufio.NewReaderSize(rc, n)
+	rc2 := struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: r,
+		Closer: rc,
+	}
+	snip, err := r.Peek(n)
+	if err == io.EOF {
+		err = nil
+	}
+	if err != nil {
+		return string(snip), rc2, err
+	}
+	if encoding == "gzip" {
+		// unpack the snippet
+		gzr, err2 := gzip.NewReader(bytes.NewReader(snip))
+		if err2 != nil {
+			// snip wasn't gzip; return it as is
+			return string(snip), rc2, nil
+		}
+		defer gzr.Close()
+		snip, err = io.ReadAll(gzr)
+	}
+	return string(snip), rc2, err
+}
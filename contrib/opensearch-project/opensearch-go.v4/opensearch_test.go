@@ -0,0 +1,202 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package opensearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/opensearch-project/opensearch-go/v4"
+	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func respond(status int, body string) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil {
+			io.Copy(io.Discard, req.Body)
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}
+}
+
+func newClient(t *testing.T, opts ...ClientOption) *opensearchapi.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_bulk"):
+			w.Write([]byte(`{"took":1,"errors":false,"items":[{"index":{"status":201}}]}`))
+		case r.URL.Path == "/missing/_doc/1":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"_index":"missing","_id":"1","found":false}`))
+		default:
+			w.Write([]byte(`{"_index":"logs","_id":"1","result":"created"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := opensearchapi.NewClient(opensearchapi.Config{
+		Client: opensearch.Config{
+			Addresses: []string{srv.URL},
+			Transport: NewRoundTripper(opts...),
+		},
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	client := newClient(t)
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	_, err := client.Index(ctx, opensearchapi.IndexReq{
+		Index:      "logs",
+		DocumentID: "a1b2c3",
+		Body:       strings.NewReader(`{"message":"hello"}`),
+	})
+	require.NoError(t, err)
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[0]
+	assert.Equal(t, root.Context().SpanID(), s.ParentID())
+	assert.Equal(t, "opensearch.query", s.OperationName())
+	assert.Equal(t, "opensearch.client", s.Tag(ext.ServiceName))
+	assert.Equal(t, "PUT /logs/_doc/?", s.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanTypeOpenSearch, s.Tag(ext.SpanType))
+	assert.Equal(t, "PUT", s.Tag("opensearch.method"))
+	assert.Equal(t, "/logs/_doc/a1b2c3", s.Tag("opensearch.url"))
+	assert.Equal(t, `{"message":"hello"}`, s.Tag("opensearch.body"))
+	assert.Equal(t, "200", s.Tag(ext.HTTPCode))
+	assert.Equal(t, ext.SpanKindClient, s.Tag(ext.SpanKind))
+	assert.Equal(t, ext.DBSystemOpenSearch, s.Tag(ext.DBSystem))
+	assert.Equal(t, string(instrumentation.PackageOpenSearchGoV4), s.Tag(ext.Component))
+	assert.Equal(t, "127.0.0.1", s.Tag(ext.NetworkDestinationName))
+}
+
+func TestClientError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	client := newClient(t, WithService("my-search"))
+	// the error returned for the missing document depends on the version of the client
+	client.Document.Get(context.Background(), opensearchapi.DocumentGetReq{Index: "missing", DocumentID: "1"})
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Equal(t, "my-search", s.Tag(ext.ServiceName))
+	assert.Equal(t, "GET /missing/_doc/?", s.Tag(ext.ResourceName))
+	assert.Equal(t, "404", s.Tag(ext.HTTPCode))
+	assert.Equal(t, `{"_index":"missing","_id":"1","found":false}`, s.Tag(ext.ErrorMsg))
+}
+
+func TestClientBulk(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	client := newClient(t)
+	_, err := client.Bulk(context.Background(), opensearchapi.BulkReq{
+		Body: strings.NewReader("{\"index\":{\"_index\":\"logs\"}}\n{\"message\":\"hello\"}\n"),
+	})
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "POST /_bulk", spans[0].Tag(ext.ResourceName))
+	assert.Equal(t, float64(1), spans[0].Tag(tagBulkItems))
+	assert.Equal(t, float64(0), spans[0].Tag(tagBulkFailedItems))
+}
+
+func TestBodyCutoff(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []ClientOption
+		want any
+	}{
+		{name: "default", want: `{"message":"hello"}`},
+		{name: "truncated", opts: []ClientOption{WithBodyCutoff(4)}, want: `{"me`},
+		{name: "disabled", opts: []ClientOption{WithBodyCutoff(0)}, want: nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			rt := NewRoundTripper(append(tt.opts, WithTransport(respond(http.StatusOK, "{}")))...)
+			req := httptest.NewRequest("POST", "http://127.0.0.1:9200/logs/_search", strings.NewReader(`{"message":"hello"}`))
+			res, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			res.Body.Close()
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tt.want, spans[0].Tag("opensearch.body"))
+		})
+	}
+}
+
+func TestBulkItems(t *testing.T) {
+	const failedBody = `{"took":3,"errors":true,"items":[` +
+		`{"index":{"_index":"logs","status":201}},` +
+		`{"index":{"_index":"logs","status":429,"error":{"type":"rejected_execution_exception","reason":"rejected"}}}]}`
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	rt := NewRoundTripper(WithTransport(respond(http.StatusOK, failedBody)))
+	req := httptest.NewRequest("POST", "http://127.0.0.1:9200/logs/_bulk", strings.NewReader("{}"))
+	res, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, failedBody, string(body))
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, float64(2), spans[0].Tag(tagBulkItems))
+	assert.Equal(t, float64(1), spans[0].Tag(tagBulkFailedItems))
+	assert.Equal(t, "1 of 2 bulk items failed, first error: rejected_execution_exception: rejected", spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestQuantize(t *testing.T) {
+	for _, tc := range []struct {
+		url, method string
+		expected    string
+	}{
+		{url: "/logs/_search", method: "POST", expected: "POST /logs/_search"},
+		{url: "/logs_2016_05/_search", method: "GET", expected: "GET /logs_?_?/_search"},
+		{url: "/logs/_doc/123", method: "GET", expected: "GET /logs/_doc/?"},
+		{url: "/logs/_doc/a1b2c3", method: "PUT", expected: "PUT /logs/_doc/?"},
+		{url: "/logs/_update/user-42", method: "POST", expected: "POST /logs/_update/?"},
+		{url: "/logs/_doc", method: "POST", expected: "POST /logs/_doc"},
+		{url: "/_plugins/_security/api/roles", method: "GET", expected: "GET /_plugins/_security/api/roles"},
+	} {
+		assert.Equal(t, tc.expected, quantize(tc.url, tc.method))
+	}
+}
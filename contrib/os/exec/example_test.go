@@ -0,0 +1,41 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package exec_test
+
+import (
+	"context"
+	"log"
+	"os"
+
+	exectrace "github.com/DataDog/dd-trace-go/v2/contrib/os/exec"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "backup")
+	defer span.Finish()
+
+	// The execution of the command is traced with a span child of the span in ctx.
+	if err := exectrace.CommandContext(ctx, "tar", "-czf", "backup.tar.gz", "data").Run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func Example_child() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	// In the child process, the trace of the parent is continued from the environment.
+	var opts []tracer.StartSpanOption
+	if sctx, err := tracer.Extract(tracer.EnvCarrier(os.Environ())); err == nil {
+		opts = append(opts, tracer.ChildOf(sctx))
+	}
+	span := tracer.StartSpan("backup.run", opts...)
+	defer span.Finish()
+}
@@ -0,0 +1,200 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package exec provides functions to trace the execution of commands with the standard
+// library's os/exec package (https://golang.org/pkg/os/exec).
+//
+// A span is created per command execution, tagged with the sanitized arguments of the command,
+// its exit code and the signal which terminated it, if any. The trace context is injected into
+// the environment variables of the command so that a traced child process can continue the
+// trace, by extracting it with tracer.EnvCarrier.
+package exec // import "github.com/DataDog/dd-trace-go/v2/contrib/os/exec"
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+const componentName = instrumentation.PackageOSExec
+
+const (
+	// tagExec holds the sanitized arguments of the command, as a JSON array.
+	tagExec = "cmd.exec"
+	// tagTruncated is set when arguments were dropped from tagExec.
+	tagTruncated = "cmd.truncated"
+	tagExitCode  = "cmd.exit_code"
+	// tagSignal is the signal which terminated the command.
+	tagSignal = "cmd.signal"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageOSExec)
+}
+
+// Cmd is an exec.Cmd tracing its execution with a span child of the span found in its
+// context. Its Run, Start, Wait, Output and CombinedOutput methods must be used for the
+// execution to be traced.
+type Cmd struct {
+	*exec.Cmd
+	ctx  context.Context
+	cfg  *config
+	span *tracer.Span
+}
+
+// CommandContext returns a traced Cmd, as exec.CommandContext does.
+func CommandContext(ctx context.Context, name string, arg ...string) *Cmd {
+	return WrapCmd(ctx, exec.CommandContext(ctx, name, arg...))
+}
+
+// WrapCmd returns the given command traced with a span child of the span found in ctx.
+func WrapCmd(ctx context.Context, cmd *exec.Cmd, opts ...Option) *Cmd {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/os/exec: Wrapping Cmd: %#v", cfg)
+	return &Cmd{Cmd: cmd, ctx: ctx, cfg: cfg}
+}
+
+// Run starts the command and waits for it to complete, as exec.Cmd.Run does.
+func (c *Cmd) Run() error {
+	c.startSpan()
+	err := c.Cmd.Run()
+	c.finishSpan(err)
+	return err
+}
+
+// Start starts the command without waiting for it to complete, as exec.Cmd.Start does. The
+// span of the command is finished by Wait.
+func (c *Cmd) Start() error {
+	c.startSpan()
+	err := c.Cmd.Start()
+	if err != nil {
+		c.finishSpan(err)
+	}
+	return err
+}
+
+// Wait waits for the command started by Start to exit, as exec.Cmd.Wait does.
+func (c *Cmd) Wait() error {
+	err := c.Cmd.Wait()
+	c.finishSpan(err)
+	return err
+}
+
+// Output runs the command and returns its standard output, as exec.Cmd.Output does.
+func (c *Cmd) Output() ([]byte, error) {
+	c.startSpan()
+	out, err := c.Cmd.Output()
+	c.finishSpan(err)
+	return out, err
+}
+
+// CombinedOutput runs the command and returns its combined standard output and standard
+// error, as exec.Cmd.CombinedOutput does.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	c.startSpan()
+	out, err := c.Cmd.CombinedOutput()
+	c.finishSpan(err)
+	return out, err
+}
+
+func (c *Cmd) startSpan() {
+	args, truncated := sanitizeArgs(c.Args)
+	opts := []tracer.StartSpanOption{
+		tracer.ResourceName(filepath.Base(c.Path)),
+		tracer.Tag(ext.Component, componentName),
+	}
+	if c.cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(c.cfg.serviceName))
+	}
+	if b, err := json.Marshal(args); err == nil {
+		opts = append(opts, tracer.Tag(tagExec, string(b)))
+	}
+	if truncated {
+		opts = append(opts, tracer.Tag(tagTruncated, "true"))
+	}
+	c.span, _ = tracer.StartSpanFromContext(c.ctx, c.cfg.spanName, opts...)
+	if c.cfg.propagation {
+		env := tracer.EnvCarrier(c.Environ())
+		if err := tracer.Inject(c.span.Context(), &env); err != nil {
+			instr.Logger().Debug("contrib/os/exec: failed to inject the trace context: %v", err)
+		} else {
+			c.Env = env
+		}
+	}
+}
+
+// waitStatus is implemented by the syscall.WaitStatus of the platforms supporting signals.
+type waitStatus interface {
+	Signaled() bool
+	Signal() syscall.Signal
+}
+
+func (c *Cmd) finishSpan(err error) {
+	if c.span == nil {
+		return
+	}
+	if ps := c.ProcessState; ps != nil {
+		c.span.SetTag(tagExitCode, strconv.Itoa(ps.ExitCode()))
+		if ws, ok := ps.Sys().(waitStatus); ok && ws.Signaled() {
+			c.span.SetTag(tagSignal, ws.Signal().String())
+		}
+	}
+	c.span.Finish(tracer.WithError(err))
+	c.span = nil
+}
+
+// maxArgsSize is the maximum size of the arguments of a command kept in its span.
+const maxArgsSize = 4 * 1024
+
+// sensitiveArg matches the arguments whose value is redacted, either as the following argument
+// or after an equal sign.
+var sensitiveArg = regexp.MustCompile(`(?i)^-{0,2}(?:p(?:ass(?:w(?:or)?d)?)?|pwd|api[-_]?key|secret(?:[-_]?key)?|a(?:ccess|uth)[-_]?token|(?:stripe)?token|mysql[-_]pwd|credentials?)$`)
+
+// sensitiveBinaries are the binaries whose arguments are all redacted.
+var sensitiveBinaries = map[string]bool{
+	"md5": true,
+}
+
+// sanitizeArgs returns the given arguments of a command with the values of the sensitive
+// arguments replaced by "?", truncated to maxArgsSize.
+func sanitizeArgs(args []string) (sanitized []string, truncated bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	sanitized = make([]string, 0, len(args))
+	redactAll := sensitiveBinaries[filepath.Base(args[0])]
+	redactNext := false
+	size := 0
+	for i, arg := range args {
+		switch {
+		case i == 0:
+		case redactAll || redactNext:
+			arg = "?"
+			redactNext = false
+		case sensitiveArg.MatchString(arg):
+			redactNext = true
+		default:
+			if k, _, ok := strings.Cut(arg, "="); ok && sensitiveArg.MatchString(k) {
+				arg = k + "=?"
+			}
+		}
+		if size += len(arg); size > maxArgsSize && i > 0 {
+			return sanitized, true
+		}
+		sanitized = append(sanitized, arg)
+	}
+	return sanitized, false
+}
@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain runs the test binary as the command executed by the tests when helperEnv is set.
+func TestMain(m *testing.M) {
+	switch os.Getenv(helperEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "env":
+		fmt.Print(os.Getenv("X_DATADOG_TRACE_ID"))
+		os.Exit(0)
+	case "sleep":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	default:
+		code, _ := strconv.Atoi(os.Getenv(helperEnv))
+		os.Exit(code)
+	}
+}
+
+const helperEnv = "DD_TEST_EXEC_HELPER"
+
+func helperCmd(ctx context.Context, mode string, opts ...Option) *Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^$", "--password", "hunter2")
+	cmd.Env = append(os.Environ(), helperEnv+"="+mode)
+	return WrapCmd(ctx, cmd, opts...)
+}
+
+func TestOutput(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	out, err := helperCmd(ctx, "env").Output()
+	require.NoError(t, err)
+	root.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[0]
+	assert.Equal(t, "command_execution", s.OperationName())
+	assert.Equal(t, root.Context().SpanID(), s.ParentID())
+	assert.Equal(t, string(instrumentation.PackageOSExec), s.Tag(ext.Component))
+	assert.Equal(t, `["`+strings.ReplaceAll(os.Args[0], `\`, `\\`)+`","-test.run=^$","--password","?"]`, s.Tag(tagExec))
+	assert.Equal(t, "0", s.Tag(tagExitCode))
+	assert.Nil(t, s.Tag(ext.ErrorMsg))
+	// the trace context is injected into the environment of the command
+	assert.Equal(t, strconv.FormatUint(s.Context().TraceIDLower(), 10), string(out))
+}
+
+func TestExitCode(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	err := helperCmd(context.Background(), "3", WithPropagation(false)).Run()
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "3", spans[0].Tag(tagExitCode))
+	assert.Equal(t, err.Error(), spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestSignal(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	cmd := helperCmd(context.Background(), "sleep")
+	require.NoError(t, cmd.Start())
+	require.NoError(t, cmd.Process.Kill())
+	assert.Error(t, cmd.Wait())
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, "-1", spans[0].Tag(tagExitCode))
+		assert.Equal(t, "killed", spans[0].Tag(tagSignal))
+	}
+}
+
+func TestStartError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	err := CommandContext(context.Background(), "/nonexistent/binary").Run()
+	require.Error(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "binary", spans[0].Tag(ext.ResourceName))
+	assert.Nil(t, spans[0].Tag(tagExitCode))
+	assert.NotNil(t, spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestSanitizeArgs(t *testing.T) {
+	for _, tc := range []struct {
+		args      []string
+		expected  []string
+		truncated bool
+	}{
+		{args: nil, expected: nil},
+		{args: []string{"ls", "-l", "/tmp"}, expected: []string{"ls", "-l", "/tmp"}},
+		{args: []string{"mysql", "-u", "root", "--password", "secret", "db"}, expected: []string{"mysql", "-u", "root", "--password", "?", "db"}},
+		{args: []string{"curl", "--token=abc", "--api-key", "xyz", "https://example.com"}, expected: []string{"curl", "--token=?", "--api-key", "?", "https://example.com"}},
+		{args: []string{"/sbin/md5", "-s", "secret"}, expected: []string{"/sbin/md5", "?", "?"}},
+		{args: []string{"echo", strings.Repeat("a", maxArgsSize), "b"}, expected: []string{"echo"}, truncated: true},
+	} {
+		sanitized, truncated := sanitizeArgs(tc.args)
+		assert.Equal(t, tc.expected, sanitized)
+		assert.Equal(t, tc.truncated, truncated)
+	}
+}
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package exec
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	serviceName string
+	spanName    string
+	propagation bool
+}
+
+// Option describes options for the os/exec integration.
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		serviceName: instr.ServiceName(instrumentation.ComponentDefault, nil),
+		spanName:    instr.OperationName(instrumentation.ComponentDefault, nil),
+		propagation: true,
+	}
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the service name of the spans of the commands.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithPropagation enables or disables the injection of the trace context into the environment
+// variables of the commands, enabled by default. See tracer.EnvCarrier to continue the trace in
+// the child process.
+func WithPropagation(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.propagation = enabled
+	}
+}
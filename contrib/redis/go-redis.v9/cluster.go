@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package redis
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// tagClusterSlot is the hash slot of the key of a command sent to a cluster.
+	tagClusterSlot = "redis.cluster.slot"
+	// tagClusterNode is the address of the cluster node which served a command.
+	tagClusterNode = "redis.cluster.node"
+	// tagClusterRedirect is the last redirect, MOVED or ASK, replied by a cluster node to a command.
+	tagClusterRedirect = "redis.cluster.redirect"
+)
+
+// clusterSlots is the number of hash slots of a Redis cluster.
+const clusterSlots = 16384
+
+// nodeNotifier is implemented by clients of Redis clusters, such as *redis.ClusterClient.
+type nodeNotifier interface {
+	OnNewNode(fn func(rdb *redis.Client))
+}
+
+// nodeHook is added to the clients of the nodes of a cluster, to tag the span of the
+// commands of the cluster client with the node which served them, and the redirects
+// replied by the nodes.
+type nodeHook struct {
+	addr string
+}
+
+func (h *nodeHook) DialHook(hook redis.DialHook) redis.DialHook {
+	return hook
+}
+
+func (h *nodeHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := hook(ctx, cmd)
+		span, ok := ctx.Value(clusterSpanKey{}).(*tracer.Span)
+		if !ok {
+			return err
+		}
+		span.SetTag(tagClusterNode, h.addr)
+		if host, port, splitErr := net.SplitHostPort(h.addr); splitErr == nil {
+			span.SetTag(ext.TargetHost, host)
+			span.SetTag(ext.TargetPort, port)
+		}
+		if err != nil {
+			if redirect, _, ok := strings.Cut(err.Error(), " "); ok && (redirect == "MOVED" || redirect == "ASK") {
+				span.SetTag(tagClusterRedirect, redirect)
+			}
+		}
+		return err
+	}
+}
+
+func (h *nodeHook) ProcessPipelineHook(hook redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return hook
+}
+
+// clusterSpanKey is the key of the span of a command sent through a cluster client in the
+// context passed to the clients of the nodes.
+type clusterSpanKey struct{}
+
+// firstKey returns the key of cmd, if any.
+func firstKey(cmd redis.Cmder) (string, bool) {
+	args := cmd.Args()
+	pos := 1 // most commands have their key as first argument
+	switch cmd.Name() {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro", "fcall", "fcall_ro":
+		// the script is followed by the number of keys and the keys
+		if len(args) < 3 || args[2] == 0 {
+			return "", false
+		}
+		pos = 3
+	}
+	if len(args) <= pos {
+		return "", false
+	}
+	key, ok := args[pos].(string)
+	return key, ok
+}
+
+// hashSlot returns the hash slot of key in a Redis cluster, computed from its hash tag if any.
+func hashSlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % clusterSlots)
+}
+
+// crc16 returns the CRC16 (XMODEM) checksum of s, as used by Redis cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -17,6 +17,8 @@ type clientConfig struct {
 	analyticsRate float64
 	skipRaw       bool
 	errCheck      func(err error) bool
+	// pipelineCommands is the maximum number of commands of a pipeline recorded as events.
+	pipelineCommands int
 }
 
 // ClientOption describes options for the Redis integration.
@@ -77,6 +79,17 @@ func WithAnalyticsRate(rate float64) ClientOptionFn {
 	}
 }
 
+// WithPipelineCommands records the first max commands of pipelines and transactions as
+// "redis.command" events on their span, with the name of the command, the prefix of its key
+// up to the first ':', the size of its reply when made of strings, and its error if any. The
+// number of commands left out is set in the "redis.pipeline.dropped_commands" tag. Commands
+// aren't recorded if max is 0, which is the default.
+func WithPipelineCommands(max int) ClientOptionFn {
+	return func(cfg *clientConfig) {
+		cfg.pipelineCommands = max
+	}
+}
+
 // WithErrorCheck specifies a function fn which determines whether the passed
 // error should be marked as an error.
 func WithErrorCheck(fn func(err error) bool) ClientOptionFn {
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package redis
+
+import (
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// isTransaction reports whether the commands of a pipeline are run in a MULTI/EXEC
+// transaction, as done by TxPipeline.
+func isTransaction(cmds []redis.Cmder) bool {
+	return len(cmds) >= 2 && cmds[0].Name() == "multi" && cmds[len(cmds)-1].Name() == "exec"
+}
+
+// recordPipelineCommands adds a "redis.command" event to the span of a pipeline for each of
+// its first max commands, with their name, key prefix, reply size and error, and tags the
+// span with the number of commands left out.
+func recordPipelineCommands(span *tracer.Span, cmds []redis.Cmder, max int) {
+	for i, cmd := range cmds {
+		if i >= max {
+			span.SetTag("redis.pipeline.dropped_commands", len(cmds)-max)
+			return
+		}
+		attrs := map[string]any{
+			"redis.command": cmd.Name(),
+			"redis.index":   i,
+		}
+		if prefix, ok := keyPrefix(cmd); ok {
+			attrs["redis.key_prefix"] = prefix
+		}
+		if size, ok := replySize(cmd); ok {
+			attrs["redis.reply_size"] = size
+		}
+		if err := cmd.Err(); err != nil && err != redis.Nil {
+			attrs["error.message"] = err.Error()
+		}
+		span.AddEvent("redis.command", tracer.WithSpanEventAttributes(attrs))
+	}
+}
+
+// keyPrefix returns the prefix of the key of cmd, up to the first ':', so that it doesn't
+// carry the identifiers usually found at the end of keys. It returns false if cmd has no key
+// or if its key has no prefix.
+func keyPrefix(cmd redis.Cmder) (string, bool) {
+	key, ok := firstKey(cmd)
+	if !ok {
+		return "", false
+	}
+	prefix, _, found := strings.Cut(key, ":")
+	if !found {
+		return "", false
+	}
+	return prefix, true
+}
+
+// replySize returns the size in bytes of the reply of cmd, for replies made of strings.
+func replySize(cmd redis.Cmder) (int, bool) {
+	switch c := cmd.(type) {
+	case *redis.StringCmd:
+		return len(c.Val()), true
+	case *redis.StringSliceCmd:
+		size := 0
+		for _, v := range c.Val() {
+			size += len(v)
+		}
+		return size, true
+	case *redis.MapStringStringCmd:
+		size := 0
+		for k, v := range c.Val() {
+			size += len(k) + len(v)
+		}
+		return size, true
+	case *redis.SliceCmd:
+		size := 0
+		for _, v := range c.Val() {
+			if s, ok := v.(string); ok {
+				size += len(s)
+			}
+		}
+		return size, true
+	}
+	return 0, false
+}
@@ -35,6 +35,9 @@ type datadogHook struct {
 type params struct {
 	config         *clientConfig
 	additionalTags []tracer.StartSpanOption
+	// cluster reports whether the client is a cluster client, whose commands are tagged
+	// with the hash slot of their key and the node which served them.
+	cluster bool
 }
 
 // NewClient returns a new Client that is traced with the default tracer under
@@ -47,6 +50,10 @@ func NewClient(opt *redis.Options, opts ...ClientOption) redis.UniversalClient {
 
 // WrapClient adds a hook to the given client that traces with the default tracer under
 // the service name "redis".
+//
+// The spans of the commands of cluster clients are tagged with the hash slot of their key,
+// the address of the node which served them and the last MOVED or ASK redirect replied by
+// the nodes, if any. Nodes are only tagged if the client is wrapped before it connects to them.
 func WrapClient(client redis.UniversalClient, opts ...ClientOption) {
 	cfg := new(clientConfig)
 	defaults(cfg)
@@ -58,6 +65,12 @@ func WrapClient(client redis.UniversalClient, opts ...ClientOption) {
 		additionalTags: additionalTagOptions(client),
 		config:         cfg,
 	}
+	if n, ok := client.(nodeNotifier); ok {
+		hookParams.cluster = true
+		n.OnNewNode(func(rdb *redis.Client) {
+			rdb.AddHook(&nodeHook{addr: rdb.Options().Addr})
+		})
+	}
 
 	client.AddHook(&datadogHook{params: hookParams})
 }
@@ -148,7 +161,15 @@ func (ddh *datadogHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
 		if !math.IsNaN(p.config.analyticsRate) {
 			startOpts = append(startOpts, tracer.Tag(ext.EventSampleRate, p.config.analyticsRate))
 		}
+		if p.cluster {
+			if key, ok := firstKey(cmd); ok {
+				startOpts = append(startOpts, tracer.Tag(tagClusterSlot, hashSlot(key)))
+			}
+		}
 		span, ctx := tracer.StartSpanFromContext(ctx, p.config.spanName, startOpts...)
+		if p.cluster {
+			ctx = context.WithValue(ctx, clusterSpanKey{}, span)
+		}
 
 		err := hook(ctx, cmd)
 
@@ -178,10 +199,16 @@ func (ddh *datadogHook) ProcessPipelineHook(hook redis.ProcessPipelineHook) redi
 		if !math.IsNaN(p.config.analyticsRate) {
 			startOpts = append(startOpts, tracer.Tag(ext.EventSampleRate, p.config.analyticsRate))
 		}
+		if isTransaction(cmds) {
+			startOpts = append(startOpts, tracer.Tag("redis.transaction", true))
+		}
 		span, ctx := tracer.StartSpanFromContext(ctx, p.config.spanName, startOpts...)
 
 		err := hook(ctx, cmds)
 
+		if p.config.pipelineCommands > 0 {
+			recordPipelineCommands(span, cmds, p.config.pipelineCommands)
+		}
 		var finishOpts []tracer.FinishOption
 		if err != nil && err != redis.Nil && ddh.config.errCheck(err) {
 			finishOpts = append(finishOpts, tracer.WithError(err))
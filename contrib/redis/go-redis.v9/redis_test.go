@@ -360,6 +360,62 @@ func TestPipeline(t *testing.T) {
 	assert.Equal("redis", span.Tag(ext.DBSystem))
 }
 
+func TestPipelineCommands(t *testing.T) {
+	ctx := context.Background()
+	opts := &redis.Options{Addr: "127.0.0.1:6379"}
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	client := NewClient(opts, WithPipelineCommands(2))
+	client.Set(ctx, "user:1", "alice", 0)
+	mt.Reset()
+
+	pipeline := client.Pipeline()
+	pipeline.Get(ctx, "user:1")
+	pipeline.Incr(ctx, "counter")
+	pipeline.Expire(ctx, "user:1", time.Hour)
+	_, err := pipeline.Exec(ctx)
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Nil(t, span.Tag("redis.transaction"))
+	assert.Equal(t, float64(1), span.Tag("redis.pipeline.dropped_commands"))
+	events := span.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "redis.command", events[0].Name)
+	events[0].AssertAttributes(t, map[string]any{
+		"redis.command":    "get",
+		"redis.index":      0,
+		"redis.key_prefix": "user",
+		"redis.reply_size": 5,
+	})
+	events[1].AssertAttributes(t, map[string]any{
+		"redis.command": "incr",
+		"redis.index":   1,
+	})
+
+	mt.Reset()
+	tx := client.TxPipeline()
+	tx.Get(ctx, "user:1")
+	_, err = tx.Exec(ctx)
+	require.NoError(t, err)
+
+	spans = mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "true", spans[0].Tag("redis.transaction"))
+	assert.Len(t, spans[0].Events(), 2)
+}
+
+func TestHashSlot(t *testing.T) {
+	assert.Equal(t, uint16(0x31c3), crc16("123456789"))
+	assert.Equal(t, 12182, hashSlot("foo"))
+	assert.Equal(t, hashSlot("user1000"), hashSlot("{user1000}.following"))
+	assert.Equal(t, hashSlot("{user1000}.followers"), hashSlot("{user1000}.following"))
+	assert.NotEqual(t, hashSlot("bar"), hashSlot("foo{}{bar}"))
+}
+
 func TestChildSpan(t *testing.T) {
 	ctx := context.Background()
 	opts := &redis.Options{Addr: "127.0.0.1:6379"}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package river_test
+
+import (
+	"context"
+	"log"
+
+	rivertrace "github.com/DataDog/dd-trace-go/contrib/riverqueue/river/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/riverdriver/riverpgxv5"
+	"github.com/riverqueue/river/rivertype"
+)
+
+type EmailArgs struct {
+	To string `json:"to"`
+}
+
+func (EmailArgs) Kind() string { return "email" }
+
+type EmailWorker struct {
+	river.WorkerDefaults[EmailArgs]
+}
+
+func (w *EmailWorker) Work(ctx context.Context, job *river.Job[EmailArgs]) error {
+	// The span of the attempt is in ctx.
+	return nil
+}
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, "postgres://localhost:5432/river")
+	if err != nil {
+		log.Fatal(err)
+	}
+	workers := river.NewWorkers()
+	river.AddWorker(workers, &EmailWorker{})
+
+	// Add the middleware to the configuration of the client, so that the jobs it inserts
+	// and works are traced.
+	client, err := river.NewClient(riverpgxv5.New(pool), &river.Config{
+		Queues:     map[string]river.QueueConfig{river.QueueDefault: {MaxWorkers: 10}},
+		Workers:    workers,
+		Middleware: []rivertype.Middleware{rivertrace.NewMiddleware()},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := client.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if _, err := client.Insert(ctx, EmailArgs{To: "alice@example.com"}, nil); err != nil {
+		log.Fatal(err)
+	}
+}
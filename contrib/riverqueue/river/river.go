@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package river provides a middleware tracing the jobs of the riverqueue/river package
+// (https://github.com/riverqueue/river).
+//
+// The trace context of inserted jobs is propagated in their metadata, so that the spans of
+// the attempts to work them continue the trace of the insertion.
+package river // import "github.com/DataDog/dd-trace-go/contrib/riverqueue/river/v2"
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+const componentName = instrumentation.PackageRiverqueueRiver
+
+// metadataKey is the key of the trace context in the metadata of jobs.
+const metadataKey = "_datadog"
+
+const (
+	tagJobID          = "river.job.id"
+	tagJobKind        = "river.job.kind"
+	tagJobAttempt     = "river.job.attempt"
+	tagJobMaxAttempts = "river.job.max_attempts"
+	tagJobPriority    = "river.job.priority"
+	// tagUniqueSkipped is set when an inserted job is skipped as a duplicate of a unique job.
+	tagUniqueSkipped = "river.job.unique_skipped"
+	// tagQueueLatency is the time elapsed between the time a job was scheduled at and the
+	// start of an attempt, in milliseconds.
+	tagQueueLatency = "river.job.queue_latency_ms"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageRiverqueueRiver)
+}
+
+// Middleware traces the insertion and the attempts of River jobs. It must be added to the
+// Middleware of the river.Config of both the clients inserting jobs and the clients working
+// them.
+type Middleware struct {
+	river.MiddlewareDefaults
+	cfg *config
+}
+
+var (
+	_ rivertype.JobInsertMiddleware = (*Middleware)(nil)
+	_ rivertype.WorkerMiddleware    = (*Middleware)(nil)
+)
+
+// NewMiddleware returns a Middleware tracing River jobs.
+func NewMiddleware(opts ...Option) *Middleware {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	instr.Logger().Debug("contrib/riverqueue/river: Configuring Middleware: %#v", cfg)
+	return &Middleware{cfg: cfg}
+}
+
+// InsertMany implements rivertype.JobInsertMiddleware. It traces the insertion of jobs with
+// a producer span, whose context is injected in the metadata of the jobs.
+func (m *Middleware) InsertMany(ctx context.Context, manyParams []*rivertype.JobInsertParams, doInner func(context.Context) ([]*rivertype.JobInsertResult, error)) ([]*rivertype.JobInsertResult, error) {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(m.cfg.producerServiceName),
+		tracer.SpanType(ext.SpanTypeMessageProducer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindProducer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemRiver),
+	}
+	if kind, ok := commonValue(manyParams, func(p *rivertype.JobInsertParams) string { return p.Kind }); ok {
+		opts = append(opts, tracer.ResourceName(kind), tracer.Tag(tagJobKind, kind))
+	} else {
+		opts = append(opts, tracer.ResourceName("InsertMany"))
+	}
+	if queue, ok := commonValue(manyParams, func(p *rivertype.JobInsertParams) string { return p.Queue }); ok {
+		opts = append(opts, tracer.Tag(ext.MessagingDestinationName, queue))
+	}
+	if len(manyParams) > 1 {
+		opts = append(opts, tracer.Tag(ext.MessagingBatchMessageCount, len(manyParams)))
+	}
+	span, ctx := tracer.StartSpanFromContext(ctx, m.cfg.producerSpanName, opts...)
+	for _, p := range manyParams {
+		p.Metadata = injectMetadata(span, p.Metadata)
+	}
+	results, err := doInner(ctx)
+	if len(results) == 1 && results[0].Job != nil {
+		span.SetTag(tagJobID, results[0].Job.ID)
+		if results[0].UniqueSkippedAsDuplicate {
+			span.SetTag(tagUniqueSkipped, true)
+		}
+	}
+	span.Finish(tracer.WithError(err))
+	return results, err
+}
+
+// Work implements rivertype.WorkerMiddleware. It traces the attempts to work jobs with a
+// consumer span, continuing the trace propagated in the metadata of the jobs.
+func (m *Middleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(context.Context) error) error {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(m.cfg.consumerServiceName),
+		tracer.ResourceName(job.Kind),
+		tracer.SpanType(ext.SpanTypeMessageConsumer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemRiver),
+		tracer.Tag(ext.MessagingDestinationName, job.Queue),
+		tracer.Tag(tagJobID, job.ID),
+		tracer.Tag(tagJobKind, job.Kind),
+		tracer.Tag(tagJobAttempt, job.Attempt),
+		tracer.Tag(tagJobMaxAttempts, job.MaxAttempts),
+		tracer.Tag(tagJobPriority, job.Priority),
+		tracer.Measured(),
+	}
+	if !job.ScheduledAt.IsZero() {
+		start := time.Now()
+		if job.AttemptedAt != nil {
+			start = *job.AttemptedAt
+		}
+		opts = append(opts, tracer.Tag(tagQueueLatency, start.Sub(job.ScheduledAt).Milliseconds()))
+	}
+	if spanCtx := extractMetadata(job.Metadata); spanCtx != nil {
+		if m.cfg.linkedTraces {
+			opts = append(opts, tracer.WithSpanLinks([]tracer.SpanLink{spanLink(spanCtx)}))
+		} else {
+			opts = append(opts, tracer.ChildOf(spanCtx))
+		}
+	}
+	span, ctx := tracer.StartSpanFromContext(ctx, m.cfg.consumerSpanName, opts...)
+	err := doInner(ctx)
+	span.Finish(tracer.WithError(err))
+	return err
+}
+
+// commonValue returns the value shared by all params, if any.
+func commonValue(manyParams []*rivertype.JobInsertParams, value func(*rivertype.JobInsertParams) string) (string, bool) {
+	if len(manyParams) == 0 {
+		return "", false
+	}
+	v := value(manyParams[0])
+	for _, p := range manyParams[1:] {
+		if value(p) != v {
+			return "", false
+		}
+	}
+	return v, true
+}
+
+// injectMetadata returns the metadata of a job, a JSON object, with the context of span
+// added to it. The metadata is returned unchanged if it isn't a JSON object.
+func injectMetadata(span *tracer.Span, metadata []byte) []byte {
+	fields := make(map[string]json.RawMessage)
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &fields); err != nil {
+			instr.Logger().Debug("contrib/riverqueue/river: failed to decode job metadata: %v", err)
+			return metadata
+		}
+	}
+	carrier := tracer.TextMapCarrier{}
+	if err := tracer.Inject(span.Context(), carrier); err != nil {
+		return metadata
+	}
+	b, err := json.Marshal(carrier)
+	if err != nil {
+		return metadata
+	}
+	fields[metadataKey] = b
+	b, err = json.Marshal(fields)
+	if err != nil {
+		return metadata
+	}
+	return b
+}
+
+// extractMetadata returns the trace context propagated in the metadata of a job, if any.
+func extractMetadata(metadata []byte) *tracer.SpanContext {
+	var fields struct {
+		Datadog tracer.TextMapCarrier `json:"_datadog"`
+	}
+	if err := json.Unmarshal(metadata, &fields); err != nil || len(fields.Datadog) == 0 {
+		return nil
+	}
+	spanCtx, err := tracer.Extract(fields.Datadog)
+	if err != nil {
+		return nil
+	}
+	return spanCtx
+}
+
+// spanLink returns a link to the span which inserted a job.
+func spanLink(spanCtx *tracer.SpanContext) tracer.SpanLink {
+	link := tracer.SpanLink{
+		TraceID:     spanCtx.TraceIDLower(),
+		TraceIDHigh: spanCtx.TraceIDUpper(),
+		SpanID:      spanCtx.SpanID(),
+		Attributes:  map[string]string{"reason": "river.insert"},
+	}
+	if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+		link.Flags = 1
+	}
+	return link
+}
@@ -0,0 +1,150 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package river
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertAndWork inserts a job with m and works it, as River would do with the row of the job.
+func insertAndWork(t *testing.T, m *Middleware, params *rivertype.JobInsertParams, work func(context.Context) error) {
+	ctx := context.Background()
+	var job *rivertype.JobRow
+	_, err := m.InsertMany(ctx, []*rivertype.JobInsertParams{params}, func(context.Context) ([]*rivertype.JobInsertResult, error) {
+		job = &rivertype.JobRow{
+			ID:          42,
+			Attempt:     1,
+			Kind:        params.Kind,
+			MaxAttempts: 25,
+			Metadata:    params.Metadata,
+			Priority:    1,
+			Queue:       params.Queue,
+			ScheduledAt: time.Now().Add(-time.Second),
+		}
+		return []*rivertype.JobInsertResult{{Job: job}}, nil
+	})
+	require.NoError(t, err)
+	attemptedAt := time.Now()
+	job.AttemptedAt = &attemptedAt
+	_ = m.Work(ctx, job, work)
+}
+
+func TestMiddleware(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	params := &rivertype.JobInsertParams{Kind: "email", Queue: "default", Metadata: []byte(`{"tenant":"acme"}`)}
+	insertAndWork(t, NewMiddleware(), params, func(context.Context) error { return nil })
+	assert.JSONEq(t, `"acme"`, string(mustField(t, params.Metadata, "tenant")))
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	insert, work := spans[0], spans[1]
+
+	assert.Equal(t, "river.insert", insert.OperationName())
+	assert.Equal(t, "river", insert.Tag(ext.ServiceName))
+	assert.Equal(t, "email", insert.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindProducer, insert.Tag(ext.SpanKind))
+	assert.Equal(t, ext.MessagingSystemRiver, insert.Tag(ext.MessagingSystem))
+	assert.Equal(t, "default", insert.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, float64(42), insert.Tag(tagJobID))
+	assert.Equal(t, "riverqueue/river", insert.Tag(ext.Component))
+
+	assert.Equal(t, "river.work", work.OperationName())
+	assert.Equal(t, "email", work.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanKindConsumer, work.Tag(ext.SpanKind))
+	assert.Equal(t, "default", work.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, float64(42), work.Tag(tagJobID))
+	assert.Equal(t, float64(1), work.Tag(tagJobAttempt))
+	assert.Equal(t, float64(25), work.Tag(tagJobMaxAttempts))
+	assert.GreaterOrEqual(t, work.Tag(tagQueueLatency), float64(1000))
+	assert.Equal(t, insert.SpanID(), work.ParentID())
+	assert.Equal(t, insert.TraceID(), work.TraceID())
+	assert.Nil(t, work.Tag(ext.ErrorMsg))
+}
+
+func TestMiddlewareError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	params := &rivertype.JobInsertParams{Kind: "email", Queue: "default"}
+	insertAndWork(t, NewMiddleware(), params, func(context.Context) error { return errors.New("smtp unavailable") })
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "smtp unavailable", spans[1].Tag(ext.ErrorMsg))
+}
+
+func TestWithLinkedTraces(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	params := &rivertype.JobInsertParams{Kind: "email", Queue: "default"}
+	insertAndWork(t, NewMiddleware(WithLinkedTraces()), params, func(context.Context) error { return nil })
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	insert, work := spans[0], spans[1]
+	assert.NotEqual(t, insert.TraceID(), work.TraceID())
+	assert.Zero(t, work.ParentID())
+	require.Len(t, work.Links(), 1)
+	assert.Equal(t, insert.SpanID(), work.Links()[0].SpanID)
+}
+
+func TestInsertManyBatch(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	manyParams := []*rivertype.JobInsertParams{
+		{Kind: "email", Queue: "default"},
+		{Kind: "sms", Queue: "default"},
+	}
+	_, err := NewMiddleware().InsertMany(context.Background(), manyParams, func(context.Context) ([]*rivertype.JobInsertResult, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "InsertMany", spans[0].Tag(ext.ResourceName))
+	assert.Nil(t, spans[0].Tag(tagJobKind))
+	assert.Equal(t, "default", spans[0].Tag(ext.MessagingDestinationName))
+	assert.Equal(t, float64(2), spans[0].Tag(ext.MessagingBatchMessageCount))
+	for _, p := range manyParams {
+		assert.NotNil(t, extractMetadata(p.Metadata))
+	}
+}
+
+func TestWithService(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	params := &rivertype.JobInsertParams{Kind: "email", Queue: "default"}
+	insertAndWork(t, NewMiddleware(WithService("mailer")), params, func(context.Context) error { return nil })
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	for _, s := range spans {
+		assert.Equal(t, "mailer", s.Tag(ext.ServiceName))
+	}
+}
+
+func mustField(t *testing.T, metadata []byte, key string) []byte {
+	fields := make(map[string]json.RawMessage)
+	require.NoError(t, json.Unmarshal(metadata, &fields))
+	return fields[key]
+}
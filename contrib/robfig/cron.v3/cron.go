@@ -0,0 +1,209 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package cron provides functions to trace the robfig/cron/v3 package (https://github.com/robfig/cron).
+//
+// Each run of a scheduled job is traced with the root span of a new trace, tagged with the
+// name of the job and its schedule.
+package cron // import "github.com/DataDog/dd-trace-go/contrib/robfig/cron.v3/v2"
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/robfig/cron/v3"
+)
+
+const componentName = instrumentation.PackageRobfigCronV3
+
+const (
+	tagJobName  = "cron.job.name"
+	tagSchedule = "cron.schedule"
+	// tagOverlapping is set on the span of a run started while previous runs of the same job
+	// are still running.
+	tagOverlapping = "cron.overlapping"
+	// tagRunningInstances is the number of runs of a job running at the start of a run,
+	// including it.
+	tagRunningInstances = "cron.running_instances"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageRobfigCronV3)
+}
+
+// ContextJob is implemented by jobs receiving the context of the span of their runs. Such
+// jobs are run with RunContext instead of Run.
+type ContextJob interface {
+	cron.Job
+	RunContext(ctx context.Context)
+}
+
+// Cron wraps a *cron.Cron so that the runs of the jobs it schedules are traced.
+type Cron struct {
+	*cron.Cron
+	cfg *config
+}
+
+// WrapCron wraps a *cron.Cron so that the runs of the jobs added with the methods of the
+// returned Cron are traced. The jobs added to c directly aren't traced.
+func WrapCron(c *cron.Cron, opts ...Option) *Cron {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	instr.Logger().Debug("contrib/robfig/cron.v3: Wrapping Cron: %#v", cfg)
+	return &Cron{Cron: c, cfg: cfg}
+}
+
+// AddFunc calls cron.Cron.AddFunc, tracing the runs of cmd. The job is named after the function.
+func (c *Cron) AddFunc(spec string, cmd func()) (cron.EntryID, error) {
+	return c.Cron.AddJob(spec, c.wrap(funcName(cmd), spec, cron.FuncJob(cmd)))
+}
+
+// AddNamedFunc calls cron.Cron.AddFunc with the given job name, tracing the runs of cmd. The
+// context passed to cmd holds the span of the run.
+func (c *Cron) AddNamedFunc(name, spec string, cmd func(ctx context.Context)) (cron.EntryID, error) {
+	return c.Cron.AddJob(spec, c.wrap(name, spec, contextFuncJob(cmd)))
+}
+
+// AddJob calls cron.Cron.AddJob, tracing the runs of cmd. The job is named after its type.
+func (c *Cron) AddJob(spec string, cmd cron.Job) (cron.EntryID, error) {
+	return c.Cron.AddJob(spec, c.wrap(typeName(cmd), spec, cmd))
+}
+
+// AddNamedJob calls cron.Cron.AddJob with the given job name, tracing the runs of cmd.
+func (c *Cron) AddNamedJob(name, spec string, cmd cron.Job) (cron.EntryID, error) {
+	return c.Cron.AddJob(spec, c.wrap(name, spec, cmd))
+}
+
+// Schedule calls cron.Cron.Schedule, tracing the runs of cmd. The job is named after its type,
+// and its spans have no schedule expression.
+func (c *Cron) Schedule(schedule cron.Schedule, cmd cron.Job) cron.EntryID {
+	return c.Cron.Schedule(schedule, c.wrap(typeName(cmd), "", cmd))
+}
+
+func (c *Cron) wrap(name, spec string, job cron.Job) cron.Job {
+	return &tracedJob{job: job, cfg: c.cfg, name: name, spec: spec}
+}
+
+// tracedJob traces the runs of a job.
+type tracedJob struct {
+	job     cron.Job
+	cfg     *config
+	name    string
+	spec    string
+	running atomic.Int32
+
+	mu      sync.Mutex
+	prevRun *tracer.SpanContext // the context of the span of the previous run, with WithRunLinks
+}
+
+// Run implements cron.Job.
+func (j *tracedJob) Run() {
+	running := j.running.Add(1)
+	defer j.running.Add(-1)
+
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(j.cfg.serviceName),
+		tracer.ResourceName(j.name),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindInternal),
+		tracer.Tag(tagJobName, j.name),
+		tracer.Measured(),
+	}
+	if j.spec != "" {
+		opts = append(opts, tracer.Tag(tagSchedule, j.spec))
+	}
+	if running > 1 {
+		opts = append(opts,
+			tracer.Tag(tagOverlapping, true),
+			tracer.Tag(tagRunningInstances, running),
+		)
+	}
+	opts = append(opts, j.cfg.spanOpts...)
+	if j.cfg.linkRuns {
+		if prev := j.previousRun(); prev != nil {
+			opts = append(opts, tracer.WithSpanLinks([]tracer.SpanLink{previousRunLink(prev)}))
+		}
+	}
+	span := tracer.StartSpan(j.cfg.spanName, opts...)
+	if j.cfg.linkRuns {
+		j.setPreviousRun(span.Context())
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			span.Finish(tracer.WithError(fmt.Errorf("panic: %v", r)))
+			panic(r)
+		}
+		span.Finish()
+	}()
+	if cj, ok := j.job.(ContextJob); ok {
+		cj.RunContext(tracer.ContextWithSpan(context.Background(), span))
+		return
+	}
+	j.job.Run()
+}
+
+func (j *tracedJob) previousRun() *tracer.SpanContext {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.prevRun
+}
+
+func (j *tracedJob) setPreviousRun(spanCtx *tracer.SpanContext) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.prevRun = spanCtx
+}
+
+// previousRunLink returns a link to the span of the previous run of a job.
+func previousRunLink(spanCtx *tracer.SpanContext) tracer.SpanLink {
+	link := tracer.SpanLink{
+		TraceID:     spanCtx.TraceIDLower(),
+		TraceIDHigh: spanCtx.TraceIDUpper(),
+		SpanID:      spanCtx.SpanID(),
+		Attributes:  map[string]string{"reason": "previous_run"},
+	}
+	if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+		link.Flags = 1
+	}
+	return link
+}
+
+// contextFuncJob is a ContextJob running a function.
+type contextFuncJob func(ctx context.Context)
+
+func (f contextFuncJob) Run() { f(context.Background()) }
+
+func (f contextFuncJob) RunContext(ctx context.Context) { f(ctx) }
+
+// funcName returns the name of fn without its package path, such as "main.cleanup".
+func funcName(fn func()) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "func"
+	}
+	name := f.Name()
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// typeName returns the name of the type of job, such as "main.CleanupJob".
+func typeName(job cron.Job) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", job), "*")
+}
@@ -0,0 +1,188 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package cron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cleanupJob struct{}
+
+func (cleanupJob) Run() {}
+
+func cleanup() {}
+
+// run runs the job of the given entry once, as the scheduler does.
+func run(c *Cron, id cron.EntryID) {
+	c.Entry(id).Job.Run()
+}
+
+func TestAddFunc(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c := WrapCron(cron.New())
+	id, err := c.AddFunc("@hourly", cleanup)
+	require.NoError(t, err)
+	run(c, id)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	assert.Equal(t, "cron.run", s.OperationName())
+	assert.Equal(t, "cron", s.Tag(ext.ServiceName))
+	assert.Equal(t, "cron.cleanup", s.Tag(ext.ResourceName))
+	assert.Equal(t, "cron.cleanup", s.Tag(tagJobName))
+	assert.Equal(t, "@hourly", s.Tag(tagSchedule))
+	assert.Equal(t, "robfig/cron.v3", s.Tag(ext.Component))
+	assert.Equal(t, ext.SpanKindInternal, s.Tag(ext.SpanKind))
+	assert.Nil(t, s.Tag(tagOverlapping))
+	assert.Zero(t, s.ParentID())
+}
+
+func TestAddJob(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c := WrapCron(cron.New())
+	id, err := c.AddJob("0 3 * * *", &cleanupJob{})
+	require.NoError(t, err)
+	run(c, id)
+	id, err = c.AddNamedJob("nightly-cleanup", "0 4 * * *", cleanupJob{})
+	require.NoError(t, err)
+	run(c, id)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "cron.cleanupJob", spans[0].Tag(tagJobName))
+	assert.Equal(t, "0 3 * * *", spans[0].Tag(tagSchedule))
+	assert.Equal(t, "nightly-cleanup", spans[1].Tag(tagJobName))
+}
+
+func TestAddNamedFunc(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var spanID uint64
+	c := WrapCron(cron.New())
+	id, err := c.AddNamedFunc("report", "@daily", func(ctx context.Context) {
+		span, ok := tracer.SpanFromContext(ctx)
+		require.True(t, ok)
+		spanID = span.Context().SpanID()
+	})
+	require.NoError(t, err)
+	run(c, id)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, spans[0].SpanID(), spanID)
+}
+
+func TestOverlappingRuns(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	c := WrapCron(cron.New())
+	id, err := c.AddNamedFunc("slow", "@every 1m", func(context.Context) {
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		run(c, id)
+	}()
+	<-started
+	run(c, id)
+	close(release)
+	wg.Wait()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	overlapping := spans[0]
+	assert.Equal(t, true, overlapping.Tag(tagOverlapping))
+	assert.Equal(t, float64(2), overlapping.Tag(tagRunningInstances))
+	assert.Nil(t, spans[1].Tag(tagOverlapping))
+}
+
+func TestWithRunLinks(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c := WrapCron(cron.New(), WithRunLinks())
+	id, err := c.AddFunc("@hourly", cleanup)
+	require.NoError(t, err)
+	run(c, id)
+	run(c, id)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Empty(t, spans[0].Links())
+	require.Len(t, spans[1].Links(), 1)
+	assert.Equal(t, spans[0].SpanID(), spans[1].Links()[0].SpanID)
+	assert.Equal(t, "previous_run", spans[1].Links()[0].Attributes["reason"])
+	assert.NotEqual(t, spans[0].TraceID(), spans[1].TraceID())
+}
+
+func TestPanic(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c := WrapCron(cron.New())
+	id, err := c.AddNamedFunc("panicking", "@hourly", func(context.Context) {
+		panic("boom")
+	})
+	require.NoError(t, err)
+	assert.Panics(t, func() { run(c, id) })
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "panic: boom", spans[0].Tag(ext.ErrorMsg))
+}
+
+func TestScheduledRun(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c := WrapCron(cron.New(cron.WithSeconds()), WithService("scheduler"))
+	done := make(chan struct{})
+	var once sync.Once
+	_, err := c.AddNamedFunc("tick", "* * * * * *", func(context.Context) {
+		once.Do(func() { close(done) })
+	})
+	require.NoError(t, err)
+	c.Start()
+	defer c.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job not run")
+	}
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) > 0 }, time.Second, 10*time.Millisecond)
+	s := mt.FinishedSpans()[0]
+	assert.Equal(t, "scheduler", s.Tag(ext.ServiceName))
+	assert.Equal(t, "* * * * * *", s.Tag(tagSchedule))
+}
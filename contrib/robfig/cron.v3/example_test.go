@@ -0,0 +1,32 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package cron_test
+
+import (
+	"context"
+	"log"
+
+	crontrace "github.com/DataDog/dd-trace-go/contrib/robfig/cron.v3/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/robfig/cron/v3"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	// Wrap the scheduler and add the jobs through the wrapper, so that their runs are traced.
+	c := crontrace.WrapCron(cron.New(), crontrace.WithRunLinks())
+	_, err := c.AddNamedFunc("cleanup", "@hourly", func(ctx context.Context) {
+		// The span of the run is in ctx.
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	c.Start()
+	defer c.Stop()
+}
@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package cron
+
+import (
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	serviceName string
+	spanName    string
+	linkRuns    bool
+	spanOpts    []tracer.StartSpanOption
+}
+
+// Option represents an option that can be used to wrap a *cron.Cron.
+type Option func(*config)
+
+func defaultConfig() *config {
+	cfg := &config{
+		serviceName: instr.ServiceName(instrumentation.ComponentDefault, nil),
+		spanName:    instr.OperationName(instrumentation.ComponentDefault, nil),
+	}
+	if rate := instr.AnalyticsRate(false); !math.IsNaN(rate) {
+		cfg.spanOpts = append(cfg.spanOpts, tracer.AnalyticsRate(rate))
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the spans of the runs of the jobs.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithRunLinks links the span of each run of a job to the span of its previous run, so that
+// the consecutive runs of the job can be navigated, although each run is the root of its
+// own trace.
+func WithRunLinks() Option {
+	return func(cfg *config) {
+		cfg.linkRuns = true
+	}
+}
+
+// WithSpanOptions defines a set of additional tracer.StartSpanOption to be added
+// to the spans of the runs of the jobs.
+func WithSpanOptions(opts ...tracer.StartSpanOption) Option {
+	return func(cfg *config) {
+		cfg.spanOpts = append(cfg.spanOpts, opts...)
+	}
+}
@@ -0,0 +1,31 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package zerolog_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	ddzerolog "github.com/DataDog/dd-trace-go/contrib/rs/zerolog/v2"
+	"github.com/rs/zerolog"
+)
+
+func ExampleHook() {
+	// Ensure your tracer is started and stopped
+	tracer.Start()
+	defer tracer.Stop()
+
+	// Add the hook to the logger, do this once at the beginning of your program
+	logger := zerolog.New(os.Stdout).Hook(ddzerolog.NewHook())
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "mySpan")
+	defer span.Finish()
+
+	// Pass the context holding the span with the events
+	logger.Info().Ctx(ctx).Msg("Completed some work!")
+}
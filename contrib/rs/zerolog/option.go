@@ -0,0 +1,43 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package zerolog
+
+import "github.com/DataDog/dd-trace-go/v2/instrumentation/options"
+
+type config struct {
+	log128bits bool
+}
+
+// Option describes options for the zerolog integration.
+type Option interface {
+	apply(*config)
+}
+
+// OptionFn represents options applicable to the zerolog integration.
+type OptionFn func(*config)
+
+func (fn OptionFn) apply(cfg *config) {
+	fn(cfg)
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		log128bits: options.GetBoolEnv("DD_TRACE_128_BIT_TRACEID_LOGGING_ENABLED", true),
+	}
+	for _, fn := range opts {
+		fn.apply(cfg)
+	}
+	return cfg
+}
+
+// WithTraceID128 sets whether the 128-bit trace IDs are logged as such, in
+// hex, or as their lower 64 bits in decimal. It defaults to the value of the
+// DD_TRACE_128_BIT_TRACEID_LOGGING_ENABLED environment variable, true if unset.
+func WithTraceID128(enabled bool) OptionFn {
+	return func(cfg *config) {
+		cfg.log128bits = enabled
+	}
+}
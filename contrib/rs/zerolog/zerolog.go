@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package zerolog provides a log/span correlation hook for the rs/zerolog package (https://github.com/rs/zerolog).
+package zerolog // import "github.com/DataDog/dd-trace-go/contrib/rs/zerolog/v2"
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	_ = instrumentation.Load(instrumentation.PackageRsZerolog)
+}
+
+var _ zerolog.Hook = (*Hook)(nil)
+
+// Hook is a zerolog.Hook adding dd.trace_id and dd.span_id to the events
+// whose context holds a span, along with dd.service, dd.env and dd.version
+// if set. The context of the events is set with Event.Ctx, or with
+// Context.Ctx for the loggers.
+//
+//	logger := zerolog.New(os.Stdout).Hook(ddzerolog.NewHook())
+//	logger.Info().Ctx(ctx).Msg("Completed some work!")
+type Hook struct {
+	cfg *config
+}
+
+// NewHook returns a new Hook.
+func NewHook(opts ...Option) *Hook {
+	return &Hook{cfg: newConfig(opts)}
+}
+
+// Run implements zerolog.Hook.
+func (h *Hook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	span, ok := tracer.SpanFromContext(e.GetCtx())
+	if !ok {
+		return
+	}
+	cfg := h.cfg
+	if cfg == nil {
+		cfg = newConfig(nil)
+	}
+	for _, lf := range tracer.LogFields(span, cfg.log128bits) {
+		e.Str(lf.Key, lf.Value)
+	}
+}
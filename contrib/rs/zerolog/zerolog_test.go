@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook(t *testing.T) {
+	tracer.Start(tracer.WithService("svc"), tracer.WithServiceVersion("1.2.3"), tracer.WithLogStartup(false))
+	defer tracer.Stop()
+	sp, ctx := tracer.StartSpanFromContext(context.Background(), "testSpan", tracer.WithSpanID(1234))
+	defer sp.Finish()
+
+	log := func(h *Hook, ctx context.Context) map[string]any {
+		t.Helper()
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf).Hook(h)
+		logger.Info().Ctx(ctx).Msg("msg")
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		return entry
+	}
+
+	entry := log(NewHook(), ctx)
+	// By default, trace IDs are logged in 128bit format
+	assert.Equal(t, sp.Context().TraceID(), entry["dd.trace_id"])
+	assert.Equal(t, "1234", entry["dd.span_id"])
+	assert.Equal(t, "svc", entry["dd.service"])
+	assert.Equal(t, "1.2.3", entry["dd.version"])
+
+	entry = log(NewHook(WithTraceID128(false)), ctx)
+	assert.Equal(t, strconv.FormatUint(sp.Context().TraceIDLower(), 10), entry["dd.trace_id"])
+
+	entry = log(&Hook{}, context.Background())
+	assert.NotContains(t, entry, "dd.trace_id")
+	assert.Equal(t, "msg", entry["message"])
+}
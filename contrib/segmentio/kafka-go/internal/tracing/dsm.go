@@ -36,6 +36,11 @@ func (tr *Tracer) SetConsumeDSMCheckpoint(msg Message) {
 		// since there is no ack mechanism, we consider that messages read are committed right away.
 		tracer.TrackKafkaCommitOffset(tr.kafkaCfg.ConsumerGroupID, msg.GetTopic(), int32(msg.GetPartition()), msg.GetOffset())
 	}
+	if hw, ok := msg.(HighWatermarkMessage); ok && hw.GetHighWaterMark() > 0 {
+		// the high watermark is the offset of the next message produced to the partition,
+		// used along with the committed offsets to compute the lag of consumer groups.
+		tracer.TrackKafkaHighWatermarkOffset("", msg.GetTopic(), int32(msg.GetPartition()), hw.GetHighWaterMark())
+	}
 }
 
 func (tr *Tracer) SetProduceDSMCheckpoint(msg Message, writer Writer) {
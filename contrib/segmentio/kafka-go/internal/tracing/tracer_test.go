@@ -6,12 +6,21 @@
 package tracing
 
 import (
+	"context"
 	"math"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/kafkabatch"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTracerAnalyticsSettings(t *testing.T) {
@@ -53,3 +62,97 @@ func TestTracerAnalyticsSettings(t *testing.T) {
 		assert.True(t, tr.dataStreamsEnabled)
 	})
 }
+
+type testMessage struct {
+	topic         string
+	partition     int
+	offset        int64
+	headers       []Header
+	time          time.Time
+	highWaterMark int64
+}
+
+func (m *testMessage) GetValue() []byte        { return nil }
+func (m *testMessage) GetKey() []byte          { return nil }
+func (m *testMessage) GetHeaders() []Header    { return m.headers }
+func (m *testMessage) SetHeaders(hs []Header)  { m.headers = hs }
+func (m *testMessage) GetTopic() string        { return m.topic }
+func (m *testMessage) GetPartition() int       { return m.partition }
+func (m *testMessage) GetOffset() int64        { return m.offset }
+func (m *testMessage) GetTime() time.Time      { return m.time }
+func (m *testMessage) GetHighWaterMark() int64 { return m.highWaterMark }
+
+func TestConsumeDSMCheckpointHighWatermark(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := NewTracer(KafkaConfig{ConsumerGroupID: "group"}, WithDataStreams())
+	tr.SetConsumeDSMCheckpoint(&testMessage{topic: "topic", partition: 1, offset: 10, highWaterMark: 42})
+	// the high watermark is unknown for the messages which don't carry it
+	tr.SetConsumeDSMCheckpoint(&testMessage{topic: "other", offset: 3})
+
+	backlogs := make(map[string]int64)
+	for _, b := range mt.SentDSMBacklogs() {
+		backlogs[strings.Join(b.Tags, ",")] = b.Value
+	}
+	assert.Equal(t, int64(42), backlogs["partition:1,topic:topic,type:kafka_high_watermark"])
+	assert.Equal(t, int64(10), backlogs["consumer_group:group,partition:1,topic:topic,type:kafka_commit"])
+	assert.Equal(t, int64(3), backlogs["consumer_group:group,partition:0,topic:other,type:kafka_commit"])
+	assert.NotContains(t, backlogs, "partition:0,topic:other,type:kafka_high_watermark")
+}
+
+func TestStartConsumeBatchSpan(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var (
+		msgs    []Message
+		parents []*tracer.Span
+	)
+	for i := 0; i < 3; i++ {
+		parent := tracer.StartSpan("produce")
+		parents = append(parents, parent)
+		msg := &testMessage{topic: "topic", partition: i, offset: int64(10 + i), time: time.Now().Add(-time.Duration(3-i) * time.Second)}
+		require.NoError(t, tracer.Inject(parent.Context(), NewMessageCarrier(msg)))
+		msgs = append(msgs, msg)
+		parent.Finish()
+	}
+	// messages without trace context are counted but not linked
+	msgs = append(msgs, &testMessage{topic: "topic", partition: 0, offset: 20})
+
+	tr := NewTracer(KafkaConfig{BootstrapServers: "localhost:9092"})
+	span, ctx := tr.StartConsumeBatchSpan(context.Background(), msgs)
+	got, ok := tracer.SpanFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, span, got)
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 4)
+	s := spans[3]
+	assert.Equal(t, "Consume Batch Topic topic", s.Tag(ext.ResourceName))
+	assert.Equal(t, "topic", s.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, float64(4), s.Tag(ext.MessagingBatchMessageCount))
+	assert.Equal(t, ext.SpanKindConsumer, s.Tag(ext.SpanKind))
+	assert.GreaterOrEqual(t, s.Tag(kafkabatch.TagLag), float64(3000))
+	assert.Zero(t, s.ParentID())
+	links := s.Links()
+	require.Len(t, links, 3)
+	for i, link := range links {
+		assert.Equal(t, parents[i].Context().SpanID(), link.SpanID)
+		assert.Equal(t, parents[i].Context().TraceIDLower(), link.TraceID)
+		assert.Equal(t, map[string]string{ext.MessagingKafkaPartition: strconv.Itoa(i), "offset": strconv.Itoa(10 + i)}, link.Attributes)
+	}
+
+	t.Run("topics", func(t *testing.T) {
+		span, _ := tr.StartConsumeBatchSpan(context.Background(), []Message{
+			&testMessage{topic: "a"},
+			&testMessage{topic: "b"},
+		})
+		span.Finish()
+		s := mt.FinishedSpans()[4]
+		assert.Equal(t, "Consume Batch", s.Tag(ext.ResourceName))
+		assert.Nil(t, s.Tag(ext.MessagingDestinationName))
+		assert.Nil(t, s.Tag(kafkabatch.TagLag))
+	})
+}
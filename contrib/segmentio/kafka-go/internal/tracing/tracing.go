@@ -18,6 +18,7 @@ import (
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/kafkabatch"
 )
 
 const componentName = "segmentio/kafka.go.v0"
@@ -54,6 +55,38 @@ func (tr *Tracer) StartConsumeSpan(ctx context.Context, msg Message) *tracer.Spa
 	return span
 }
 
+// StartConsumeBatchSpan starts a span for the processing of a batch of messages, linking to
+// the trace of each message, see kafkabatch.SpanOptions.
+func (tr *Tracer) StartConsumeBatchSpan(ctx context.Context, msgs []Message) (*tracer.Span, context.Context) {
+	opts := kafkabatch.SpanOptions(len(msgs), func(i int) kafkabatch.Message {
+		m := kafkabatch.Message{
+			Topic:     msgs[i].GetTopic(),
+			Partition: msgs[i].GetPartition(),
+			Offset:    msgs[i].GetOffset(),
+			Carrier:   NewMessageCarrier(msgs[i]),
+		}
+		if tm, ok := msgs[i].(TimestampedMessage); ok {
+			m.Time = tm.GetTime()
+		}
+		return m
+	})
+	opts = append(opts,
+		tracer.ServiceName(tr.consumerServiceName),
+		tracer.SpanType(ext.SpanTypeMessageConsumer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemKafka),
+		tracer.Measured(),
+	)
+	if tr.kafkaCfg.BootstrapServers != "" {
+		opts = append(opts, tracer.Tag(ext.KafkaBootstrapServers, tr.kafkaCfg.BootstrapServers))
+	}
+	if !math.IsNaN(tr.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, tr.analyticsRate))
+	}
+	return tracer.StartSpanFromContext(ctx, tr.consumerSpanName, opts...)
+}
+
 func (tr *Tracer) StartProduceSpan(ctx context.Context, writer Writer, msg Message, spanOpts ...tracer.StartSpanOption) *tracer.Span {
 	topic := writer.GetTopic()
 	if topic == "" {
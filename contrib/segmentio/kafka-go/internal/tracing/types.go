@@ -5,6 +5,8 @@
 
 package tracing
 
+import "time"
+
 type Header interface {
 	GetKey() string
 	GetValue() []byte
@@ -37,6 +39,16 @@ type Message interface {
 	GetOffset() int64
 }
 
+// TimestampedMessage is implemented by messages which carry their timestamp.
+type TimestampedMessage interface {
+	GetTime() time.Time
+}
+
+// HighWatermarkMessage is implemented by messages which carry the high watermark of their partition.
+type HighWatermarkMessage interface {
+	GetHighWaterMark() int64
+}
+
 // KafkaConfig holds information from the kafka config for span tags.
 type KafkaConfig struct {
 	BootstrapServers string
@@ -88,6 +88,17 @@ func (r *Reader) FetchMessage(ctx context.Context) (kafka.Message, error) {
 	return msg, nil
 }
 
+// StartBatchSpan starts a span for the processing of a batch of messages read from r, linking
+// to the trace of each message, and returns it along with a context holding it. The caller
+// must finish the span once the batch is processed.
+func (r *Reader) StartBatchSpan(ctx context.Context, msgs []kafka.Message) (*tracer.Span, context.Context) {
+	tMsgs := make([]tracing.Message, len(msgs))
+	for i := range msgs {
+		tMsgs[i] = wrapMessage(&msgs[i])
+	}
+	return r.tracer.StartConsumeBatchSpan(ctx, tMsgs)
+}
+
 // Writer wraps a kafka.Writer with tracing config data
 type KafkaWriter struct {
 	*kafka.Writer
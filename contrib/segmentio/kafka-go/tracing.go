@@ -6,6 +6,8 @@
 package kafka
 
 import (
+	"time"
+
 	"github.com/DataDog/dd-trace-go/contrib/segmentio/kafka-go/v2/internal/tracing"
 	"github.com/segmentio/kafka-go"
 )
@@ -60,6 +62,14 @@ func (w *wMessage) GetOffset() int64 {
 	return w.Offset
 }
 
+func (w *wMessage) GetTime() time.Time {
+	return w.Time
+}
+
+func (w *wMessage) GetHighWaterMark() int64 {
+	return w.HighWaterMark
+}
+
 type wHeader struct {
 	kafka.Header
 }
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package controllerruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var deploymentGVK = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+func newRequest(namespace, name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+}
+
+func TestReconciler(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		res      reconcile.Result
+		err      error
+		requeue  bool
+		terminal bool
+	}{
+		{name: "done"},
+		{name: "requeue", res: reconcile.Result{Requeue: true}, requeue: true},
+		{name: "requeue-after", res: reconcile.Result{RequeueAfter: time.Minute}, requeue: true},
+		{name: "error", err: errors.New("conflict"), requeue: true},
+		{name: "terminal-error", err: reconcile.TerminalError(errors.New("invalid spec")), terminal: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			var inner *tracer.Span
+			r := WrapReconciler(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				inner, _ = tracer.SpanFromContext(ctx)
+				return tt.res, tt.err
+			}), WithGroupVersionKind(deploymentGVK), WithControllerName("deployment"))
+			res, err := r.Reconcile(context.Background(), newRequest("default", "web"))
+			assert.Equal(t, tt.res, res)
+			assert.Equal(t, tt.err, err)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			s := spans[0]
+			assert.Equal(t, s.SpanID(), inner.Context().SpanID())
+			assert.Equal(t, "controller_runtime.reconcile", s.OperationName())
+			assert.Equal(t, "Deployment", s.Tag(ext.ResourceName))
+			assert.Equal(t, "controller-runtime", s.Tag(ext.ServiceName))
+			assert.Equal(t, "deployment", s.Tag(tagController))
+			assert.Equal(t, "apps", s.Tag(tagAPIGroup))
+			assert.Equal(t, "v1", s.Tag(tagAPIVersion))
+			assert.Equal(t, "Deployment", s.Tag(tagKind))
+			assert.Equal(t, "default", s.Tag(tagNamespace))
+			assert.Equal(t, "web", s.Tag(tagName))
+			assert.Equal(t, tt.requeue, s.Tag(tagRequeue))
+			assert.Equal(t, string(componentName), s.Tag(ext.Component))
+			if tt.res.RequeueAfter > 0 {
+				assert.Equal(t, "1m0s", s.Tag(tagRequeueAfter))
+			}
+			if tt.terminal {
+				assert.Equal(t, true, s.Tag(tagTerminal))
+			} else {
+				assert.Nil(t, s.Tag(tagTerminal))
+			}
+			if tt.err != nil {
+				assert.Equal(t, tt.err.Error(), s.Tag(ext.ErrorMsg))
+			}
+		})
+	}
+}
+
+func TestEventLinks(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	events := NewEventTracker()
+	h := WrapEventHandler(&handler.EnqueueRequestForObject{}, WithGroupVersionKind(deploymentGVK), WithEventTracker(events))
+	r := WrapReconciler(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, nil
+	}), WithEventTracker(events))
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	obj := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}}
+	h.Create(context.Background(), event.CreateEvent{Object: obj}, q)
+	h.Update(context.Background(), event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}, q)
+	require.Equal(t, 1, q.Len())
+
+	req, _ := q.Get()
+	assert.Equal(t, newRequest("default", "web"), req)
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	q.Done(req)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 3)
+	create, update, reconciliation := spans[0], spans[1], spans[2]
+	assert.Equal(t, eventSpanName, create.OperationName())
+	assert.Equal(t, "create Deployment", create.Tag(ext.ResourceName))
+	assert.Equal(t, "create", create.Tag(tagEventType))
+	assert.Equal(t, "default", create.Tag(tagNamespace))
+	assert.Equal(t, "web", create.Tag(tagName))
+	assert.Equal(t, ext.SpanKindConsumer, create.Tag(ext.SpanKind))
+	assert.Equal(t, "update Deployment", update.Tag(ext.ResourceName))
+
+	links := reconciliation.Links()
+	require.Len(t, links, 2)
+	assert.Equal(t, create.SpanID(), links[0].SpanID)
+	assert.Equal(t, update.SpanID(), links[1].SpanID)
+	assert.Equal(t, "watch_event", links[0].Attributes["reason"])
+
+	// the links are cleared by the reconciliation
+	_, err = r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	spans = mt.FinishedSpans()
+	require.Len(t, spans, 4)
+	assert.Empty(t, spans[3].Links())
+}
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package controllerruntime_test
+
+import (
+	"context"
+
+	controllerruntimetrace "github.com/DataDog/dd-trace-go/contrib/sigs.k8s.io/controller-runtime/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{})
+	if err != nil {
+		panic(err)
+	}
+
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	obj := &v1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	// Link the spans of the reconciliations to the spans of the watch events which
+	// enqueued their requests.
+	events := controllerruntimetrace.NewEventTracker()
+	var r reconcile.Reconciler = reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		// Reconcile the deployment of req.
+		return reconcile.Result{}, nil
+	})
+	err = ctrl.NewControllerManagedBy(mgr).
+		Named("deployment").
+		WatchesMetadata(obj, controllerruntimetrace.WrapEventHandler(&handler.EnqueueRequestForObject{},
+			controllerruntimetrace.WithGroupVersionKind(gvk),
+			controllerruntimetrace.WithEventTracker(events),
+		)).
+		Complete(controllerruntimetrace.WrapReconciler(r,
+			controllerruntimetrace.WithGroupVersionKind(gvk),
+			controllerruntimetrace.WithControllerName("deployment"),
+			controllerruntimetrace.WithEventTracker(events),
+		))
+	if err != nil {
+		panic(err)
+	}
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		panic(err)
+	}
+}
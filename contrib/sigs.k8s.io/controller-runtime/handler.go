@@ -0,0 +1,162 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package controllerruntime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// eventSpanName is the name of the spans of the watch events.
+const eventSpanName = "controller_runtime.event"
+
+// maxEventLinks is the maximum number of watch events linked to a reconciliation. The first
+// events are kept.
+const maxEventLinks = 16
+
+// EventTracker links the spans of the reconciliations of a controller to the spans of the
+// watch events which enqueued their requests. It is shared by the event handlers and the
+// reconciler of a controller with the WithEventTracker option.
+type EventTracker struct {
+	mu      sync.Mutex
+	pending map[reconcile.Request][]tracer.SpanLink
+}
+
+// NewEventTracker returns a new EventTracker.
+func NewEventTracker() *EventTracker {
+	return &EventTracker{pending: make(map[reconcile.Request][]tracer.SpanLink)}
+}
+
+// add records that the event of span enqueued req.
+func (t *EventTracker) add(req reconcile.Request, span *tracer.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	links := t.pending[req]
+	if len(links) >= maxEventLinks {
+		return
+	}
+	spanCtx := span.Context()
+	link := tracer.SpanLink{
+		TraceID:     spanCtx.TraceIDLower(),
+		TraceIDHigh: spanCtx.TraceIDUpper(),
+		SpanID:      spanCtx.SpanID(),
+		Attributes:  map[string]string{"reason": "watch_event"},
+	}
+	if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+		link.Flags = 1
+	}
+	t.pending[req] = append(links, link)
+}
+
+// take returns the links to the events which enqueued req since its last reconciliation.
+func (t *EventTracker) take(req reconcile.Request) []tracer.SpanLink {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	links := t.pending[req]
+	delete(t.pending, req)
+	return links
+}
+
+// WrapEventHandler returns an event handler tracing the watch events handled by h with spans
+// tagged with the type of the event and the namespace and name of the object of the event. The
+// requests enqueued by h are linked to the span of their event when the handler and the
+// reconciler of the controller share an EventTracker.
+func WrapEventHandler(h handler.EventHandler, opts ...Option) handler.EventHandler {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/sigs.k8s.io/controller-runtime: Wrapping EventHandler: %#v", cfg)
+	return &eventHandler{EventHandler: h, cfg: cfg}
+}
+
+type eventHandler struct {
+	handler.EventHandler
+	cfg *config
+}
+
+type queue = workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+// Create traces the create event and calls handler.EventHandler.Create.
+func (h *eventHandler) Create(ctx context.Context, evt event.CreateEvent, q queue) {
+	span, q := h.start(ctx, "create", evt.Object, q)
+	defer span.Finish()
+	h.EventHandler.Create(ctx, evt, q)
+}
+
+// Update traces the update event and calls handler.EventHandler.Update.
+func (h *eventHandler) Update(ctx context.Context, evt event.UpdateEvent, q queue) {
+	span, q := h.start(ctx, "update", evt.ObjectNew, q)
+	defer span.Finish()
+	h.EventHandler.Update(ctx, evt, q)
+}
+
+// Delete traces the delete event and calls handler.EventHandler.Delete.
+func (h *eventHandler) Delete(ctx context.Context, evt event.DeleteEvent, q queue) {
+	span, q := h.start(ctx, "delete", evt.Object, q)
+	defer span.Finish()
+	h.EventHandler.Delete(ctx, evt, q)
+}
+
+// Generic traces the generic event and calls handler.EventHandler.Generic.
+func (h *eventHandler) Generic(ctx context.Context, evt event.GenericEvent, q queue) {
+	span, q := h.start(ctx, "generic", evt.Object, q)
+	defer span.Finish()
+	h.EventHandler.Generic(ctx, evt, q)
+}
+
+func (h *eventHandler) start(ctx context.Context, typ string, obj client.Object, q queue) (*tracer.Span, queue) {
+	opts := []tracer.StartSpanOption{
+		tracer.ResourceName(typ + " " + h.cfg.resourceName()),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(tagEventType, typ),
+	}
+	opts = append(opts, h.cfg.tags()...)
+	if h.cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(h.cfg.serviceName))
+	}
+	if obj != nil {
+		opts = append(opts, tracer.Tag(tagName, obj.GetName()))
+		if ns := obj.GetNamespace(); ns != "" {
+			opts = append(opts, tracer.Tag(tagNamespace, ns))
+		}
+	}
+	span, _ := tracer.StartSpanFromContext(ctx, eventSpanName, opts...)
+	if h.cfg.events == nil {
+		return span, q
+	}
+	return span, &trackedQueue{TypedRateLimitingInterface: q, events: h.cfg.events, span: span}
+}
+
+// trackedQueue records the requests enqueued during the handling of the event of span.
+type trackedQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	events *EventTracker
+	span   *tracer.Span
+}
+
+func (q *trackedQueue) Add(req reconcile.Request) {
+	q.events.add(req, q.span)
+	q.TypedRateLimitingInterface.Add(req)
+}
+
+func (q *trackedQueue) AddAfter(req reconcile.Request, d time.Duration) {
+	q.events.add(req, q.span)
+	q.TypedRateLimitingInterface.AddAfter(req, d)
+}
+
+func (q *trackedQueue) AddRateLimited(req reconcile.Request) {
+	q.events.add(req, q.span)
+	q.TypedRateLimitingInterface.AddRateLimited(req)
+}
@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package controllerruntime
+
+import (
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type config struct {
+	serviceName    string
+	spanName       string
+	analyticsRate  float64
+	controllerName string
+	gvk            schema.GroupVersionKind
+	events         *EventTracker
+}
+
+// Option describes options for the controller-runtime integration.
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		serviceName:   instr.ServiceName(instrumentation.ComponentDefault, nil),
+		spanName:      instr.OperationName(instrumentation.ComponentDefault, nil),
+		analyticsRate: instr.AnalyticsRate(false),
+	}
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// resourceName returns the resource name of the spans, the kind of the reconciled objects or
+// the name of the controller.
+func (cfg *config) resourceName() string {
+	switch {
+	case cfg.gvk.Kind != "":
+		return cfg.gvk.Kind
+	case cfg.controllerName != "":
+		return cfg.controllerName
+	default:
+		return "reconcile"
+	}
+}
+
+func (cfg *config) tags() []tracer.StartSpanOption {
+	var opts []tracer.StartSpanOption
+	if cfg.controllerName != "" {
+		opts = append(opts, tracer.Tag(tagController, cfg.controllerName))
+	}
+	if cfg.gvk.Group != "" {
+		opts = append(opts, tracer.Tag(tagAPIGroup, cfg.gvk.Group))
+	}
+	if cfg.gvk.Version != "" {
+		opts = append(opts, tracer.Tag(tagAPIVersion, cfg.gvk.Version))
+	}
+	if cfg.gvk.Kind != "" {
+		opts = append(opts, tracer.Tag(tagKind, cfg.gvk.Kind))
+	}
+	return opts
+}
+
+// WithService sets the service name of the spans.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithControllerName sets the name of the controller, tagged on the spans.
+func WithControllerName(name string) Option {
+	return func(cfg *config) {
+		cfg.controllerName = name
+	}
+}
+
+// WithGroupVersionKind sets the group, version and kind of the objects reconciled by the
+// reconciler, or of the objects of the events handled by the event handler. The kind is the
+// resource name of the spans.
+func WithGroupVersionKind(gvk schema.GroupVersionKind) Option {
+	return func(cfg *config) {
+		cfg.gvk = gvk
+	}
+}
+
+// WithEventTracker sets the EventTracker linking the spans of the watch events handled by the
+// event handlers of a controller to the spans of the reconciliations of the requests they
+// enqueued. It must be given to the event handlers and to the reconciler of the controller.
+func WithEventTracker(t *EventTracker) Option {
+	return func(cfg *config) {
+		cfg.events = t
+	}
+}
+
+// WithAnalytics enables Trace Analytics for all started spans.
+func WithAnalytics(on bool) Option {
+	return func(cfg *config) {
+		if on {
+			cfg.analyticsRate = 1.0
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithAnalyticsRate sets the sampling rate for Trace Analytics events
+// correlated to started spans.
+func WithAnalyticsRate(rate float64) Option {
+	return func(cfg *config) {
+		if rate >= 0.0 && rate <= 1.0 {
+			cfg.analyticsRate = rate
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package controllerruntime provides functions to trace the sigs.k8s.io/controller-runtime
+// package (https://github.com/kubernetes-sigs/controller-runtime).
+//
+// The reconciliations of a reconciler wrapped with WrapReconciler are traced. The event handlers
+// wrapped with WrapEventHandler trace the watch events which enqueue requests, and link the
+// spans of the events to the spans of the reconciliations of the requests when the handlers and
+// the reconciler share an EventTracker.
+package controllerruntime // import "github.com/DataDog/dd-trace-go/contrib/sigs.k8s.io/controller-runtime/v2"
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const componentName = instrumentation.PackageSigsK8sControllerRuntime
+
+const (
+	tagController   = "controller_runtime.controller"
+	tagReconcileID  = "controller_runtime.reconcile_id"
+	tagRequeue      = "controller_runtime.requeue"
+	tagRequeueAfter = "controller_runtime.requeue_after"
+	// tagTerminal is set when the reconciliation failed with a terminal error, which isn't retried.
+	tagTerminal   = "controller_runtime.terminal_error"
+	tagEventType  = "controller_runtime.event_type"
+	tagAPIGroup   = "kubernetes.api_group"
+	tagAPIVersion = "kubernetes.api_version"
+	tagKind       = "kubernetes.kind"
+	tagNamespace  = "kubernetes.namespace"
+	tagName       = "kubernetes.name"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageSigsK8sControllerRuntime)
+}
+
+// WrapReconciler returns a reconciler tracing the reconciliations of r with spans tagged with the
+// kind of the reconciled objects (see WithGroupVersionKind), the namespace and name of the
+// request, the reconcile ID, whether the request is requeued and the error of the
+// reconciliation.
+func WrapReconciler(r reconcile.Reconciler, opts ...Option) reconcile.Reconciler {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/sigs.k8s.io/controller-runtime: Wrapping Reconciler: %#v", cfg)
+	return &reconciler{Reconciler: r, cfg: cfg}
+}
+
+type reconciler struct {
+	reconcile.Reconciler
+	cfg *config
+}
+
+// Reconcile calls reconcile.Reconciler.Reconcile and traces the reconciliation.
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	opts := []tracer.StartSpanOption{
+		tracer.ResourceName(r.cfg.resourceName()),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindInternal),
+		tracer.Tag(tagName, req.Name),
+	}
+	opts = append(opts, r.cfg.tags()...)
+	if r.cfg.serviceName != "" {
+		opts = append(opts, tracer.ServiceName(r.cfg.serviceName))
+	}
+	if req.Namespace != "" {
+		opts = append(opts, tracer.Tag(tagNamespace, req.Namespace))
+	}
+	if id := controller.ReconcileIDFromContext(ctx); id != "" {
+		opts = append(opts, tracer.Tag(tagReconcileID, string(id)))
+	}
+	if !math.IsNaN(r.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, r.cfg.analyticsRate))
+	}
+	if r.cfg.events != nil {
+		if links := r.cfg.events.take(req); len(links) > 0 {
+			opts = append(opts, tracer.WithSpanLinks(links))
+		}
+	}
+	span, ctx := tracer.StartSpanFromContext(ctx, r.cfg.spanName, opts...)
+	res, err := r.Reconciler.Reconcile(ctx, req)
+	terminal := errors.Is(err, reconcile.TerminalError(nil))
+	// the request is requeued with rate limiting on error, unless the error is terminal
+	span.SetTag(tagRequeue, res.Requeue || res.RequeueAfter > 0 || (err != nil && !terminal))
+	if res.RequeueAfter > 0 {
+		span.SetTag(tagRequeueAfter, res.RequeueAfter.String())
+	}
+	if terminal {
+		span.SetTag(tagTerminal, true)
+	}
+	span.Finish(tracer.WithError(err))
+	return res, err
+}
@@ -35,3 +35,16 @@ func ExampleHook() {
 	// You should see:
 	// {"dd.span_id":0,"dd.trace_id":0,"level":"info","msg":"Completed some work!","time":"2000-01-01T01:01:01Z"}
 }
+
+func ExampleWithGoroutineFallback() {
+	tracer.Start()
+	defer tracer.Stop()
+	// Correlate the entries logged without a context with the span most
+	// recently activated on the goroutine, when built with Orchestrion.
+	logrus.AddHook(ddlogrus.NewHook(ddlogrus.WithGoroutineFallback(true)))
+
+	span, _ := tracer.StartSpanFromContext(context.Background(), "mySpan")
+	defer span.Finish()
+
+	logrus.Info("Completed some work!")
+}
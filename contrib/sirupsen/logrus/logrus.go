@@ -7,6 +7,7 @@
 package logrus
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
@@ -24,10 +25,22 @@ func init() {
 }
 
 // DDContextLogHook ensures that any span in the log context is correlated to log output.
-type DDContextLogHook struct{}
+type DDContextLogHook struct {
+	cfg *config
+}
+
+// NewHook returns a DDContextLogHook configured with the given options.
+func NewHook(opts ...Option) *DDContextLogHook {
+	c := newConfig()
+	for _, fn := range opts {
+		fn.apply(c)
+	}
+	return &DDContextLogHook{cfg: c}
+}
 
 type config struct {
-	log128bits bool
+	log128bits  bool
+	glsFallback bool
 }
 
 var cfg = newConfig()
@@ -45,11 +58,21 @@ func (d *DDContextLogHook) Levels() []logrus.Level {
 
 // Fire implements logrus.Hook interface, attaches trace and span details found in entry context
 func (d *DDContextLogHook) Fire(e *logrus.Entry) error {
-	span, found := tracer.SpanFromContext(e.Context)
+	c := d.cfg
+	if c == nil {
+		c = cfg
+	}
+	ctx := e.Context
+	if ctx == nil && c.glsFallback {
+		// Without a context, the tracer falls back to the span most recently
+		// activated on the goroutine, when it tracks them.
+		ctx = context.Background()
+	}
+	span, found := tracer.SpanFromContext(ctx)
 	if !found {
 		return nil
 	}
-	if cfg.log128bits && span.Context().TraceID() != tracer.TraceIDZero {
+	if c.log128bits && span.Context().TraceID() != tracer.TraceIDZero {
 		e.Data[ext.LogKeyTraceID] = span.Context().TraceID()
 	} else {
 		e.Data[ext.LogKeyTraceID] = strconv.FormatUint(span.Context().TraceIDLower(), 10)
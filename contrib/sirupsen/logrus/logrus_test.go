@@ -51,3 +51,22 @@ func TestFire128BitDisabled(t *testing.T) {
 	assert.Equal(t, strconv.FormatUint(sp.Context().TraceIDLower(), 10), e.Data["dd.trace_id"])
 	assert.Equal(t, strconv.FormatUint(sp.Context().SpanID(), 10), e.Data["dd.span_id"])
 }
+
+func TestFireGoroutineFallback(t *testing.T) {
+	tracer.Start()
+	defer tracer.Stop()
+	sp, sctx := tracer.StartSpanFromContext(context.Background(), "testSpan", tracer.WithSpanID(1234))
+	defer sp.Finish()
+
+	hook := NewHook(WithGoroutineFallback(true))
+	e := logrus.NewEntry(logrus.New())
+	e.Context = sctx
+	assert.NoError(t, hook.Fire(e))
+	assert.Equal(t, sp.Context().TraceID(), e.Data["dd.trace_id"])
+	assert.Equal(t, strconv.FormatUint(sp.Context().SpanID(), 10), e.Data["dd.span_id"])
+
+	// Without Orchestrion, the active spans of the goroutines aren't tracked.
+	e = logrus.NewEntry(logrus.New())
+	assert.NoError(t, hook.Fire(e))
+	assert.NotContains(t, e.Data, "dd.trace_id")
+}
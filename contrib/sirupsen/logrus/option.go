@@ -0,0 +1,30 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package logrus
+
+// Option describes options for the logrus integration.
+type Option interface {
+	apply(*config)
+}
+
+// OptionFn represents options applicable to NewHook.
+type OptionFn func(*config)
+
+func (fn OptionFn) apply(cfg *config) {
+	fn(cfg)
+}
+
+// WithGoroutineFallback sets whether the entries without a context are
+// correlated with the span most recently activated on the goroutine logging
+// them, for the codebases which can't pass a context to every log call. The
+// active spans of the goroutines are only tracked in the applications built
+// with Orchestrion (https://github.com/DataDog/orchestrion): otherwise, the
+// entries without a context are left as is. It is disabled by default.
+func WithGoroutineFallback(enabled bool) OptionFn {
+	return func(cfg *config) {
+		cfg.glsFallback = enabled
+	}
+}
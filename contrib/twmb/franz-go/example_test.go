@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kgo_test
+
+import (
+	"context"
+	"log"
+
+	kgotrace "github.com/DataDog/dd-trace-go/contrib/twmb/franz-go/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	cl, err := kgotrace.NewClient([]kgo.Opt{
+		kgo.SeedBrokers("localhost:9092"),
+		kgo.ConsumerGroup("my-group"),
+		kgo.ConsumeTopics("orders"),
+	}, kgotrace.WithService("orders-consumer"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cl.Close()
+
+	ctx := context.Background()
+	for {
+		fetches := cl.PollFetches(ctx)
+		if fetches.IsClientClosed() {
+			return
+		}
+		fetches.EachRecord(func(r *kgo.Record) {
+			// the context of the record holds its span, until the next poll
+			span, _ := tracer.StartSpanFromContext(r.Context, "process.order")
+			defer span.Finish()
+		})
+	}
+}
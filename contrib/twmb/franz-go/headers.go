@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kgo
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// A RecordCarrier injects and extracts traces from the headers of a *kgo.Record.
+type RecordCarrier struct {
+	r *kgo.Record
+}
+
+var _ interface {
+	tracer.TextMapReader
+	tracer.TextMapWriter
+} = (*RecordCarrier)(nil)
+
+// NewRecordCarrier creates a new RecordCarrier.
+func NewRecordCarrier(r *kgo.Record) RecordCarrier {
+	return RecordCarrier{r}
+}
+
+// ForeachKey iterates over every header.
+func (c RecordCarrier) ForeachKey(handler func(key, val string) error) error {
+	for _, h := range c.r.Headers {
+		if err := handler(h.Key, string(h.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set sets a header.
+func (c RecordCarrier) Set(key, val string) {
+	// ensure uniqueness of keys
+	for i := 0; i < len(c.r.Headers); i++ {
+		if c.r.Headers[i].Key == key {
+			c.r.Headers = append(c.r.Headers[:i], c.r.Headers[i+1:]...)
+			i--
+		}
+	}
+	c.r.Headers = append(c.r.Headers, kgo.RecordHeader{
+		Key:   key,
+		Value: []byte(val),
+	})
+}
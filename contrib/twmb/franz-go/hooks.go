@@ -0,0 +1,72 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kgo
+
+import (
+	"context"
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// hooks trace the records produced by a client, from the time they are buffered by the
+// client until they are acknowledged by the brokers or fail.
+type hooks struct {
+	cfg              *config
+	bootstrapServers string
+}
+
+var (
+	_ kgo.HookProduceRecordBuffered   = (*hooks)(nil)
+	_ kgo.HookProduceRecordUnbuffered = (*hooks)(nil)
+)
+
+// produceSpanKey is the key of the span of a produced record in the context of the record.
+type produceSpanKey struct{}
+
+// OnProduceRecordBuffered starts the span of r and injects its context in the headers of r.
+func (h *hooks) OnProduceRecordBuffered(r *kgo.Record) {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(h.cfg.producerServiceName),
+		tracer.ResourceName("Produce Topic " + r.Topic),
+		tracer.SpanType(ext.SpanTypeMessageProducer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindProducer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemKafka),
+		tracer.Tag(ext.MessagingDestinationName, r.Topic),
+	}
+	if h.bootstrapServers != "" {
+		opts = append(opts, tracer.Tag(ext.KafkaBootstrapServers, h.bootstrapServers))
+	}
+	if !math.IsNaN(h.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, h.cfg.analyticsRate))
+	}
+	span, ctx := tracer.StartSpanFromContext(recordContext(r), h.cfg.producerSpanName, opts...)
+	if err := tracer.Inject(span.Context(), NewRecordCarrier(r)); err != nil {
+		instr.Logger().Debug("contrib/twmb/franz-go: Failed to inject span context into record headers: %v", err)
+	}
+	r.Context = context.WithValue(ctx, produceSpanKey{}, span)
+}
+
+// OnProduceRecordUnbuffered finishes the span of r, tagged with the partition and offset r
+// was written to.
+func (h *hooks) OnProduceRecordUnbuffered(r *kgo.Record, err error) {
+	if r.Context == nil {
+		return
+	}
+	span, ok := r.Context.Value(produceSpanKey{}).(*tracer.Span)
+	if !ok {
+		return
+	}
+	if err == nil {
+		span.SetTag(ext.MessagingKafkaPartition, r.Partition)
+		span.SetTag(tagOffset, r.Offset)
+	}
+	span.Finish(tracer.WithError(err))
+}
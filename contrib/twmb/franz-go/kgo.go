@@ -0,0 +1,263 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package kgo provides functions to trace the twmb/franz-go/pkg/kgo package.
+//
+// The records produced by a Client are traced with hooks, and the trace context is
+// propagated in the headers of the records. The records returned by the polls of a Client
+// are traced from the poll returning them until the next poll, so that the spans cover their
+// processing, and the context of the records is set to the context of their span.
+package kgo // import "github.com/DataDog/dd-trace-go/contrib/twmb/franz-go/v2"
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const componentName = instrumentation.PackageTwmbFranzGo
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageTwmbFranzGo)
+}
+
+const (
+	// tagOffset is the offset of a record in its partition.
+	tagOffset = "offset"
+	// tagConsumerLag is the number of records of the partition of a consumed record which
+	// follow it, as known when it was fetched. For batches, it's the largest lag of their
+	// records.
+	tagConsumerLag = "kafka.consumer_lag"
+	// tagBatchLag holds the time elapsed since the timestamp of the oldest record of a batch,
+	// in milliseconds.
+	tagBatchLag = "kafka.batch.lag_ms"
+)
+
+// maxBatchSpanLinks is the maximum number of records linked from the span of a batch.
+const maxBatchSpanLinks = 128
+
+// Client wraps a *kgo.Client, tracing the records it produces and polls.
+type Client struct {
+	*kgo.Client
+	cfg              *config
+	bootstrapServers string
+
+	mu     sync.Mutex
+	active []*tracer.Span // spans of the records of the last poll
+}
+
+// NewClient creates a *kgo.Client with the given options, adding the hooks tracing the
+// produced records, and returns it wrapped in a Client.
+func NewClient(kopts []kgo.Opt, opts ...Option) (*Client, error) {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/twmb/franz-go: Creating Client: %#v", cfg)
+	h := &hooks{cfg: cfg}
+	cl, err := kgo.NewClient(append(kopts[:len(kopts):len(kopts)], kgo.WithHooks(h))...)
+	if err != nil {
+		return nil, err
+	}
+	if seeds, ok := cl.OptValue(kgo.SeedBrokers).([]string); ok {
+		h.bootstrapServers = strings.Join(seeds, ",")
+	}
+	return &Client{Client: cl, cfg: cfg, bootstrapServers: h.bootstrapServers}, nil
+}
+
+// PollFetches finishes the spans of the records of the previous poll, and polls the
+// fetched records, tracing them.
+func (c *Client) PollFetches(ctx context.Context) kgo.Fetches {
+	c.finishActive()
+	fetches := c.Client.PollFetches(ctx)
+	c.traceFetches(fetches)
+	return fetches
+}
+
+// PollRecords finishes the spans of the records of the previous poll, and polls up to
+// maxPollRecords fetched records, tracing them.
+func (c *Client) PollRecords(ctx context.Context, maxPollRecords int) kgo.Fetches {
+	c.finishActive()
+	fetches := c.Client.PollRecords(ctx, maxPollRecords)
+	c.traceFetches(fetches)
+	return fetches
+}
+
+// Close finishes the spans of the records of the last poll, and closes the client.
+func (c *Client) Close() {
+	c.finishActive()
+	c.Client.Close()
+}
+
+func (c *Client) finishActive() {
+	c.mu.Lock()
+	active := c.active
+	c.active = nil
+	c.mu.Unlock()
+	for _, span := range active {
+		span.Finish()
+	}
+}
+
+// traceFetches starts the spans of the polled records, and sets the context of the records
+// to the context of their span.
+func (c *Client) traceFetches(fetches kgo.Fetches) {
+	if fetches.NumRecords() == 0 {
+		return
+	}
+	if c.cfg.batchSpans {
+		span := c.startBatchSpan(fetches)
+		fetches.EachRecord(func(r *kgo.Record) {
+			r.Context = tracer.ContextWithSpan(recordContext(r), span)
+		})
+		c.setActive([]*tracer.Span{span})
+		return
+	}
+	spans := make([]*tracer.Span, 0, fetches.NumRecords())
+	fetches.EachPartition(func(p kgo.FetchTopicPartition) {
+		for _, r := range p.Records {
+			span, ctx := c.startConsumeSpan(r, consumerLag(p.HighWatermark, r.Offset))
+			r.Context = ctx
+			spans = append(spans, span)
+		}
+	})
+	c.setActive(spans)
+}
+
+func (c *Client) setActive(spans []*tracer.Span) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active = append(c.active, spans...)
+}
+
+// consumerLag returns the number of records following the record at offset in a partition
+// with the given high watermark, or -1 if the high watermark isn't known.
+func consumerLag(highWatermark, offset int64) int64 {
+	if highWatermark <= 0 {
+		return -1
+	}
+	return max(highWatermark-offset-1, 0)
+}
+
+// recordContext returns the context of r, or the background context if it has none.
+func recordContext(r *kgo.Record) context.Context {
+	if r.Context == nil {
+		return context.Background()
+	}
+	return r.Context
+}
+
+func (c *Client) consumerSpanOptions() []tracer.StartSpanOption {
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(c.cfg.consumerServiceName),
+		tracer.SpanType(ext.SpanTypeMessageConsumer),
+		tracer.Tag(ext.Component, componentName),
+		tracer.Tag(ext.SpanKind, ext.SpanKindConsumer),
+		tracer.Tag(ext.MessagingSystem, ext.MessagingSystemKafka),
+		tracer.Measured(),
+	}
+	if c.bootstrapServers != "" {
+		opts = append(opts, tracer.Tag(ext.KafkaBootstrapServers, c.bootstrapServers))
+	}
+	if !math.IsNaN(c.cfg.analyticsRate) {
+		opts = append(opts, tracer.Tag(ext.EventSampleRate, c.cfg.analyticsRate))
+	}
+	return opts
+}
+
+// startConsumeSpan starts the span of a polled record, child of the span which produced it
+// if its headers carry its context.
+func (c *Client) startConsumeSpan(r *kgo.Record, lag int64) (*tracer.Span, context.Context) {
+	opts := append(c.consumerSpanOptions(),
+		tracer.ResourceName("Consume Topic "+r.Topic),
+		tracer.Tag(ext.MessagingDestinationName, r.Topic),
+		tracer.Tag(ext.MessagingKafkaPartition, r.Partition),
+		tracer.Tag(tagOffset, r.Offset),
+	)
+	if lag >= 0 {
+		opts = append(opts, tracer.Tag(tagConsumerLag, lag))
+	}
+	if spanCtx, err := tracer.Extract(NewRecordCarrier(r)); err == nil {
+		opts = append(opts, tracer.ChildOf(spanCtx))
+	}
+	return tracer.StartSpanFromContext(recordContext(r), c.cfg.consumerSpanName, opts...)
+}
+
+// startBatchSpan starts the span of a poll returning several records. Instead of continuing
+// the trace of one of the records, the span links to the trace of each record, up to
+// maxBatchSpanLinks of them.
+func (c *Client) startBatchSpan(fetches kgo.Fetches) *tracer.Span {
+	var (
+		links  []tracer.SpanLink
+		topic  string
+		single = true
+		oldest time.Time
+		lag    int64 = -1
+	)
+	fetches.EachPartition(func(p kgo.FetchTopicPartition) {
+		if len(p.Records) == 0 {
+			return
+		}
+		if topic == "" {
+			topic = p.Topic
+		} else if topic != p.Topic {
+			single = false
+		}
+		lag = max(lag, consumerLag(p.HighWatermark, p.Records[0].Offset))
+		for _, r := range p.Records {
+			if !r.Timestamp.IsZero() && (oldest.IsZero() || r.Timestamp.Before(oldest)) {
+				oldest = r.Timestamp
+			}
+			if len(links) == maxBatchSpanLinks {
+				continue
+			}
+			if spanCtx, err := tracer.Extract(NewRecordCarrier(r)); err == nil {
+				links = append(links, batchSpanLink(spanCtx, r.Partition, r.Offset))
+			}
+		}
+	})
+	resource := "Consume Batch"
+	opts := append(c.consumerSpanOptions(), tracer.Tag(ext.MessagingBatchMessageCount, fetches.NumRecords()))
+	if single {
+		resource += " Topic " + topic
+		opts = append(opts, tracer.Tag(ext.MessagingDestinationName, topic))
+	}
+	opts = append(opts, tracer.ResourceName(resource))
+	if len(links) > 0 {
+		opts = append(opts, tracer.WithSpanLinks(links))
+	}
+	if !oldest.IsZero() {
+		opts = append(opts, tracer.Tag(tagBatchLag, time.Since(oldest).Milliseconds()))
+	}
+	if lag >= 0 {
+		opts = append(opts, tracer.Tag(tagConsumerLag, lag))
+	}
+	return tracer.StartSpan(c.cfg.consumerSpanName, opts...)
+}
+
+// batchSpanLink returns the link to the span of a record of a batch.
+func batchSpanLink(spanCtx *tracer.SpanContext, partition int32, offset int64) tracer.SpanLink {
+	link := tracer.SpanLink{
+		TraceID:     spanCtx.TraceIDLower(),
+		TraceIDHigh: spanCtx.TraceIDUpper(),
+		SpanID:      spanCtx.SpanID(),
+		Attributes: map[string]string{
+			ext.MessagingKafkaPartition: strconv.Itoa(int(partition)),
+			tagOffset:                   strconv.FormatInt(offset, 10),
+		},
+	}
+	if p, ok := spanCtx.SamplingPriority(); ok && p > 0 {
+		link.Flags = 1
+	}
+	return link
+}
@@ -0,0 +1,206 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kgo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const testTopic = "gotest"
+
+func newCluster(t *testing.T) []string {
+	c, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, testTopic))
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return c.ListenAddrs()
+}
+
+func newClient(t *testing.T, seeds []string, kopts []kgo.Opt, opts ...Option) *Client {
+	c, err := NewClient(append([]kgo.Opt{kgo.SeedBrokers(seeds...)}, kopts...), opts...)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func produce(t *testing.T, c *Client, ctx context.Context, values ...string) {
+	for _, v := range values {
+		require.NoError(t, c.ProduceSync(ctx, &kgo.Record{Topic: testTopic, Value: []byte(v)}).FirstErr())
+	}
+}
+
+func poll(t *testing.T, c *Client, n int) []*kgo.Record {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var records []*kgo.Record
+	for len(records) < n {
+		fetches := c.PollFetches(ctx)
+		require.NoError(t, ctx.Err())
+		records = append(records, fetches.Records()...)
+	}
+	return records
+}
+
+func spansNamed(mt mocktracer.Tracer, name string) []*mocktracer.Span {
+	var spans []*mocktracer.Span
+	for _, s := range mt.FinishedSpans() {
+		if s.OperationName() == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+func TestProduceConsume(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	seeds := newCluster(t)
+	producer := newClient(t, seeds, nil)
+	consumer := newClient(t, seeds, []kgo.Opt{kgo.ConsumeTopics(testTopic)})
+
+	root, ctx := tracer.StartSpanFromContext(context.Background(), "root")
+	produce(t, producer, ctx, "hello")
+	root.Finish()
+
+	records := poll(t, consumer, 1)
+	require.Len(t, records, 1)
+	span, ok := tracer.SpanFromContext(records[0].Context)
+	require.True(t, ok)
+	// the span of the record covers its processing, until the next poll
+	assert.Empty(t, spansNamed(mt, "kafka.consume"))
+	consumer.finishActive()
+
+	produced := spansNamed(mt, "kafka.produce")
+	require.Len(t, produced, 1)
+	p := produced[0]
+	assert.Equal(t, root.Context().SpanID(), p.ParentID())
+	assert.Equal(t, "Produce Topic "+testTopic, p.Tag(ext.ResourceName))
+	assert.Equal(t, "kafka", p.Tag(ext.ServiceName))
+	assert.Equal(t, ext.SpanTypeMessageProducer, p.Tag(ext.SpanType))
+	assert.Equal(t, ext.SpanKindProducer, p.Tag(ext.SpanKind))
+	assert.Equal(t, string(instrumentation.PackageTwmbFranzGo), p.Tag(ext.Component))
+	assert.Equal(t, ext.MessagingSystemKafka, p.Tag(ext.MessagingSystem))
+	assert.Equal(t, testTopic, p.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, strings.Join(seeds, ","), p.Tag(ext.KafkaBootstrapServers))
+	assert.Equal(t, float64(0), p.Tag(ext.MessagingKafkaPartition))
+	assert.Equal(t, float64(0), p.Tag("offset"))
+
+	consumed := spansNamed(mt, "kafka.consume")
+	require.Len(t, consumed, 1)
+	cs := consumed[0]
+	assert.Equal(t, span.Context().SpanID(), cs.SpanID())
+	assert.Equal(t, p.SpanID(), cs.ParentID())
+	assert.Equal(t, p.TraceID(), cs.TraceID())
+	assert.Equal(t, "Consume Topic "+testTopic, cs.Tag(ext.ResourceName))
+	assert.Equal(t, ext.SpanTypeMessageConsumer, cs.Tag(ext.SpanType))
+	assert.Equal(t, ext.SpanKindConsumer, cs.Tag(ext.SpanKind))
+	assert.Equal(t, float64(0), cs.Tag("offset"))
+	assert.Equal(t, float64(0), cs.Tag("kafka.consumer_lag"))
+}
+
+func TestConsumerLag(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	seeds := newCluster(t)
+	producer := newClient(t, seeds, nil)
+	produce(t, producer, context.Background(), "a", "b", "c")
+	consumer := newClient(t, seeds, []kgo.Opt{kgo.ConsumeTopics(testTopic)})
+
+	records := poll(t, consumer, 3)
+	require.Len(t, records, 3)
+	consumer.Close()
+
+	consumed := spansNamed(mt, "kafka.consume")
+	require.Len(t, consumed, 3)
+	for _, s := range consumed {
+		offset := s.Tag("offset").(float64)
+		assert.Equal(t, 2-offset, s.Tag("kafka.consumer_lag"))
+	}
+}
+
+func TestBatchSpans(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	seeds := newCluster(t)
+	producer := newClient(t, seeds, nil)
+	produce(t, producer, context.Background(), "a", "b")
+	consumer := newClient(t, seeds, []kgo.Opt{kgo.ConsumeTopics(testTopic)}, WithBatchSpans())
+
+	records := poll(t, consumer, 2)
+	consumer.Close()
+
+	produced := spansNamed(mt, "kafka.produce")
+	require.Len(t, produced, 2)
+	consumed := spansNamed(mt, "kafka.consume")
+	require.NotEmpty(t, consumed)
+	batch := consumed[0]
+	assert.Equal(t, "Consume Batch Topic "+testTopic, batch.Tag(ext.ResourceName))
+	assert.Equal(t, testTopic, batch.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, uint64(0), batch.ParentID())
+	span, ok := tracer.SpanFromContext(records[0].Context)
+	require.True(t, ok)
+	assert.Equal(t, batch.SpanID(), span.Context().SpanID())
+
+	var linked int
+	for _, s := range consumed {
+		linked += len(s.Links())
+	}
+	assert.Equal(t, 2, linked)
+	assert.Equal(t, produced[0].SpanID(), batch.Links()[0].SpanID)
+	assert.Equal(t, "0", batch.Links()[0].Attributes["offset"])
+}
+
+func TestProduceError(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	seeds := newCluster(t)
+	producer := newClient(t, seeds, []kgo.Opt{kgo.RecordDeliveryTimeout(time.Second)}, WithService("my-producer"))
+	// the topic doesn't exist and isn't created, so the record can't be delivered
+	err := producer.ProduceSync(context.Background(), &kgo.Record{Topic: "unknown", Value: []byte("x")}).FirstErr()
+	require.Error(t, err)
+
+	produced := spansNamed(mt, "kafka.produce")
+	require.Len(t, produced, 1)
+	assert.Equal(t, "my-producer", produced[0].Tag(ext.ServiceName))
+	assert.NotNil(t, produced[0].Tag(ext.ErrorMsg))
+}
+
+func TestRecordCarrier(t *testing.T) {
+	r := &kgo.Record{Headers: []kgo.RecordHeader{{Key: "x-datadog-trace-id", Value: []byte("1")}}}
+	c := NewRecordCarrier(r)
+	c.Set("x-datadog-trace-id", "2")
+	c.Set("x-datadog-parent-id", "3")
+	require.Len(t, r.Headers, 2)
+	got := map[string]string{}
+	require.NoError(t, c.ForeachKey(func(k, v string) error {
+		got[k] = v
+		return nil
+	}))
+	assert.Equal(t, map[string]string{"x-datadog-trace-id": "2", "x-datadog-parent-id": "3"}, got)
+}
+
+func TestConsumerLagValue(t *testing.T) {
+	assert.Equal(t, int64(-1), consumerLag(0, 5))
+	assert.Equal(t, int64(4), consumerLag(10, 5))
+	assert.Equal(t, int64(0), consumerLag(10, 9))
+	assert.Equal(t, int64(0), consumerLag(10, 12))
+}
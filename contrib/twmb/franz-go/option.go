@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kgo
+
+import (
+	"math"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	consumerServiceName string
+	producerServiceName string
+	consumerSpanName    string
+	producerSpanName    string
+	analyticsRate       float64
+	batchSpans          bool
+}
+
+// Option represents an option that can be used to trace a franz-go client.
+type Option func(*config)
+
+func defaultConfig() *config {
+	return &config{
+		consumerServiceName: instr.ServiceName(instrumentation.ComponentConsumer, nil),
+		producerServiceName: instr.ServiceName(instrumentation.ComponentProducer, nil),
+		consumerSpanName:    instr.OperationName(instrumentation.ComponentConsumer, nil),
+		producerSpanName:    instr.OperationName(instrumentation.ComponentProducer, nil),
+		analyticsRate:       instr.AnalyticsRate(false),
+	}
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := defaultConfig()
+	for _, fn := range opts {
+		fn(cfg)
+	}
+	return cfg
+}
+
+// WithService sets the given service name for the spans of the client.
+func WithService(name string) Option {
+	return func(cfg *config) {
+		cfg.consumerServiceName = name
+		cfg.producerServiceName = name
+	}
+}
+
+// WithAnalytics enables Trace Analytics for all started spans.
+func WithAnalytics(on bool) Option {
+	return func(cfg *config) {
+		if on {
+			cfg.analyticsRate = 1.0
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithAnalyticsRate sets the sampling rate for Trace Analytics events
+// correlated to started spans.
+func WithAnalyticsRate(rate float64) Option {
+	return func(cfg *config) {
+		if rate >= 0.0 && rate <= 1.0 {
+			cfg.analyticsRate = rate
+		} else {
+			cfg.analyticsRate = math.NaN()
+		}
+	}
+}
+
+// WithBatchSpans traces each poll returning records with a single span, linked to the spans
+// which produced its records, instead of a span for each record. The context of the
+// records is set to the context of the span of the poll.
+func WithBatchSpans() Option {
+	return func(cfg *config) {
+		cfg.batchSpans = true
+	}
+}
@@ -0,0 +1,36 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package zap_test
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	ddzap "github.com/DataDog/dd-trace-go/contrib/uber-go/zap/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func ExampleWrapCore() {
+	// Ensure your tracer is started and stopped
+	tracer.Start()
+	defer tracer.Stop()
+
+	// Wrap the core of the logger, do this once at the beginning of your program
+	logger, _ := zap.NewProduction(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return ddzap.WrapCore(c)
+	}))
+	defer logger.Sync()
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "mySpan")
+	defer span.Finish()
+
+	// Pass the context holding the span with the log entries
+	logger.Info("Completed some work!", ddzap.Context(ctx))
+	// Or add the correlation fields to all the entries of a child logger
+	ddzap.WithContext(ctx, logger).Info("Completed some more work!")
+}
@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package zap provides functions to correlate logs and traces using the go.uber.org/zap package (https://github.com/uber-go/zap).
+package zap // import "github.com/DataDog/dd-trace-go/contrib/uber-go/zap/v2"
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func init() {
+	_ = instrumentation.Load(instrumentation.PackageUberGoZap)
+}
+
+// contextKey is the key of the fields created by Context.
+const contextKey = "dd.context"
+
+// Fields returns the fields correlating a log entry with the span found in
+// ctx: dd.trace_id and dd.span_id, along with dd.service, dd.env and
+// dd.version if set. It returns nil if ctx holds no span.
+//
+//	logger.Info("Completed some work!", ddzap.Fields(ctx)...)
+func Fields(ctx context.Context, opts ...Option) []zap.Field {
+	return newConfig(opts).fields(ctx)
+}
+
+// WithContext returns a child of l adding the fields returned by Fields to
+// all its log entries.
+func WithContext(ctx context.Context, l *zap.Logger, opts ...Option) *zap.Logger {
+	return l.With(Fields(ctx, opts...)...)
+}
+
+// Context returns a field holding ctx, replaced with the fields returned by
+// Fields when written by a core wrapped with WrapCore. It is skipped by the
+// other cores.
+//
+//	logger := zap.New(ddzap.WrapCore(core))
+//	logger.Info("Completed some work!", ddzap.Context(ctx))
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// WrapCore returns a core writing its entries to c, with the fields created by
+// Context replaced with the fields correlating the entries with the span in
+// their context.
+func WrapCore(c zapcore.Core, opts ...Option) zapcore.Core {
+	return &core{Core: c, cfg: newConfig(opts)}
+}
+
+type core struct {
+	zapcore.Core
+	cfg *config
+}
+
+// With implements zapcore.Core.
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(c.expand(fields)), cfg: c.cfg}
+}
+
+// Check implements zapcore.Core.
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.expand(fields))
+}
+
+// expand returns fields with the fields created by Context replaced with the
+// fields correlating the entry with the span in their context.
+func (c *core) expand(fields []zapcore.Field) []zapcore.Field {
+	i := -1
+	for j, f := range fields {
+		if f.Key == contextKey && f.Type == zapcore.SkipType {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return fields
+	}
+	expanded := make([]zapcore.Field, 0, len(fields)+5)
+	expanded = append(expanded, fields[:i]...)
+	for _, f := range fields[i:] {
+		if f.Key != contextKey || f.Type != zapcore.SkipType {
+			expanded = append(expanded, f)
+			continue
+		}
+		if ctx, ok := f.Interface.(context.Context); ok {
+			expanded = append(expanded, c.cfg.fields(ctx)...)
+		}
+	}
+	return expanded
+}
+
+// fields returns the fields correlating a log entry with the span in ctx.
+func (cfg *config) fields(ctx context.Context) []zap.Field {
+	span, ok := tracer.SpanFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	logFields := tracer.LogFields(span, cfg.log128bits)
+	fields := make([]zap.Field, 0, len(logFields))
+	for _, lf := range logFields {
+		fields = append(fields, zap.String(lf.Key, lf.Value))
+	}
+	return fields
+}
@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package zap
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFields(t *testing.T) {
+	tracer.Start(tracer.WithService("svc"), tracer.WithEnv("prod"), tracer.WithLogStartup(false))
+	defer tracer.Stop()
+	sp, ctx := tracer.StartSpanFromContext(context.Background(), "testSpan", tracer.WithSpanID(1234))
+	defer sp.Finish()
+
+	assert.Nil(t, Fields(context.Background()))
+	assert.Equal(t, []zap.Field{
+		zap.String("dd.trace_id", sp.Context().TraceID()),
+		zap.String("dd.span_id", "1234"),
+		zap.String("dd.service", "svc"),
+		zap.String("dd.env", "prod"),
+	}, Fields(ctx))
+	assert.Equal(t, zap.String("dd.trace_id", strconv.FormatUint(sp.Context().TraceIDLower(), 10)),
+		Fields(ctx, WithTraceID128(false))[0])
+}
+
+func TestWrapCore(t *testing.T) {
+	tracer.Start(tracer.WithLogStartup(false))
+	defer tracer.Stop()
+	sp, ctx := tracer.StartSpanFromContext(context.Background(), "testSpan", tracer.WithSpanID(1234))
+	defer sp.Finish()
+
+	obs, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(WrapCore(obs))
+	logger.Info("with context", Context(ctx), zap.Int("rows", 3))
+	logger.Info("without context")
+	logger.With(Context(ctx)).Info("with child")
+	logger.Debug("disabled", Context(ctx))
+
+	entries := logs.All()
+	require.Len(t, entries, 3)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, sp.Context().TraceID(), fields["dd.trace_id"])
+	assert.Equal(t, "1234", fields["dd.span_id"])
+	assert.Equal(t, int64(3), fields["rows"])
+	assert.NotContains(t, fields, contextKey)
+	assert.NotContains(t, entries[1].ContextMap(), "dd.trace_id")
+	assert.Equal(t, "1234", entries[2].ContextMap()["dd.span_id"])
+
+	// the field is skipped by the cores that aren't wrapped.
+	obs, logs = observer.New(zapcore.InfoLevel)
+	zap.New(obs).Info("not wrapped", Context(ctx))
+	assert.Empty(t, logs.All()[0].ContextMap())
+}
@@ -4,18 +4,48 @@
 // Copyright 2024 Datadog, Inc.
 
 // Package bun provides helper functions for tracing the github.com/uptrace/bun package (https://github.com/uptrace/bun).
+//
+// The queries are obfuscated before being used as the resource of their spans, and their
+// spans are tagged with the operation of the query and the table and model it applies to.
 package bun
 
 import (
 	"context"
+	"sync"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/DataDog/datadog-agent/pkg/obfuscate"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect"
 )
 
+const (
+	// tagOperation is the operation of a query, such as SELECT or INSERT.
+	tagOperation = "bun.operation"
+	// tagTable is the name of the table a query applies to.
+	tagTable = "bun.table"
+	// tagModel is the name of the Go type of the model of a query.
+	tagModel = "bun.model"
+	// tagTables is the comma-separated list of the tables found in an obfuscated query.
+	tagTables = "sql.tables"
+)
+
+// nonParsableResource is the resource of the spans of the queries which can't be
+// obfuscated, as set by the Datadog Agent.
+const nonParsableResource = "Non-parsable SQL query"
+
+var obfuscator = sync.OnceValue(func() *obfuscate.Obfuscator {
+	return obfuscate.NewObfuscator(obfuscate.Config{
+		SQL: obfuscate.SQLConfig{
+			TableNames:    true,
+			ReplaceDigits: true,
+		},
+	})
+})
+
 var instr *instrumentation.Instrumentation
 
 func init() {
@@ -52,16 +82,29 @@ func (qh *queryHook) BeforeQuery(ctx context.Context, qe *bun.QueryEvent) contex
 	default:
 		dbSystem = ext.DBSystemOtherSQL
 	}
-	var (
-		query = qe.Query
-		opts  = []tracer.StartSpanOption{
-			tracer.SpanType(ext.SpanTypeSQL),
-			tracer.ResourceName(string(query)),
-			tracer.ServiceName(qh.cfg.serviceName),
-			tracer.Tag(ext.Component, instrumentation.PackageUptraceBun),
-			tracer.Tag(ext.DBSystem, dbSystem),
+	opts := []tracer.StartSpanOption{
+		tracer.SpanType(ext.SpanTypeSQL),
+		tracer.ServiceName(qh.cfg.serviceName),
+		tracer.Tag(ext.Component, instrumentation.PackageUptraceBun),
+		tracer.Tag(ext.DBSystem, dbSystem),
+		tracer.Tag(tagOperation, qe.Operation()),
+	}
+	if oq, err := obfuscator().ObfuscateSQLString(qe.Query); err == nil {
+		opts = append(opts, tracer.ResourceName(oq.Query))
+		if oq.Metadata.TablesCSV != "" {
+			opts = append(opts, tracer.Tag(tagTables, oq.Metadata.TablesCSV))
+		}
+	} else {
+		opts = append(opts, tracer.ResourceName(nonParsableResource))
+	}
+	if qe.IQuery != nil {
+		if table := qe.IQuery.GetTableName(); table != "" {
+			opts = append(opts, tracer.Tag(tagTable, table))
 		}
-	)
+	}
+	if model, ok := qe.Model.(bun.TableModel); ok && model.Table() != nil {
+		opts = append(opts, tracer.Tag(tagModel, model.Table().Type.Name()))
+	}
 	_, ctx = tracer.StartSpanFromContext(ctx, "bun.query", opts...)
 	return ctx
 }
@@ -217,3 +217,37 @@ func TestServiceName(t *testing.T) {
 		assert.Equal(ext.DBSystemOtherSQL, spans[0].Tag(ext.DBSystem))
 	})
 }
+
+type testUser struct {
+	bun.BaseModel `bun:"table:users"`
+
+	ID   int64 `bun:",pk,autoincrement"`
+	Name string
+}
+
+func TestQueryTags(t *testing.T) {
+	assert := assert.New(t)
+	db := setupDB(t, "sqlite", "file::memory:?cache=shared")
+	ctx := context.Background()
+	_, err := db.NewCreateTable().Model((*testUser)(nil)).IfNotExists().Exec(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.NewDropTable().Model((*testUser)(nil)).IfExists().Exec(ctx) })
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	var user testUser
+	err = db.NewSelect().Model(&user).Where("id = ?", 42).Scan(ctx)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	s := spans[0]
+	resource := s.Tag(ext.ResourceName).(string)
+	assert.Contains(resource, "id = ?")
+	assert.NotContains(resource, "42")
+	assert.Equal("SELECT", s.Tag("bun.operation"))
+	assert.Equal("users", s.Tag("bun.table"))
+	assert.Equal("testUser", s.Tag("bun.model"))
+	assert.Contains(s.Tag("sql.tables"), "users")
+}
@@ -5,6 +5,7 @@ go 1.23.0
 godebug x509negativeserial=1
 
 require (
+	github.com/DataDog/datadog-agent/pkg/obfuscate v0.66.1
 	github.com/DataDog/dd-trace-go/v2 v2.2.0-dev
 	github.com/go-sql-driver/mysql v1.6.0
 	github.com/lib/pq v1.10.2
@@ -18,7 +19,6 @@ require (
 require (
 	github.com/DataDog/appsec-internal-go v1.12.0 // indirect
 	github.com/DataDog/datadog-agent/comp/core/tagger/origindetection v0.66.1 // indirect
-	github.com/DataDog/datadog-agent/pkg/obfuscate v0.66.1 // indirect
 	github.com/DataDog/datadog-agent/pkg/proto v0.66.1 // indirect
 	github.com/DataDog/datadog-agent/pkg/remoteconfig/state v0.66.1 // indirect
 	github.com/DataDog/datadog-agent/pkg/trace v0.66.1 // indirect
@@ -34,10 +34,14 @@ type DatadogMiddleware struct {
 }
 
 func (m *DatadogMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if httptrace.IgnoreRequest(r) {
+		next(w, r)
+		return
+	}
 	opts := options.Expand(m.cfg.spanOpts, 0, 4) // opts must be a copy of m.cfg.spanOpts, locally scoped, to avoid races.
 	opts = append(opts,
 		tracer.ServiceName(m.cfg.serviceName),
-		tracer.ResourceName(m.cfg.resourceNamer(r)),
+		tracer.ResourceName(httptrace.GuardResource(m.cfg.resourceNamer(r))),
 		httptrace.HeaderTagsFromRequest(r, m.cfg.headerTags))
 	if !math.IsNaN(m.cfg.analyticsRate) {
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, m.cfg.analyticsRate))
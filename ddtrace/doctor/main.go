@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Command doctor checks the tracer configuration of the environment it runs
+// in, and whether the tracer can reach the agent, so that a deployment can be
+// troubleshot before, or without, starting the application. See
+// tracer.Diagnose for the list of checks.
+//
+// Usage:
+//
+//	go run github.com/DataDog/dd-trace-go/v2/ddtrace/doctor [-json] [-timeout 10s]
+//
+// The command exits with status 1 when a check failed.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	timeout := flag.Duration("timeout", 10*time.Second, "maximum duration of the checks")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	r := tracer.Diagnose(ctx)
+	cancel()
+
+	var err error
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(r)
+	} else {
+		err = write(os.Stdout, r)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "writing the report: %v\n", err)
+		os.Exit(1)
+	}
+	if !r.OK() {
+		os.Exit(1)
+	}
+}
+
+// write writes r to w in a human-readable format.
+func write(w io.Writer, r tracer.DiagnosticReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Agent:\t%s\n\n", r.AgentURL)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tMESSAGE")
+	for _, c := range r.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Name, c.Status, c.Message)
+	}
+	return tw.Flush()
+}
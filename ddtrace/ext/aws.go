@@ -18,11 +18,20 @@ const (
 	AWSRetryCount = "aws.retry_count"
 
 	SQSQueueName = "queuename"
+	// SQSApproximateReceiveCount is the highest number of times the messages received
+	// from a queue were received.
+	SQSApproximateReceiveCount = "aws.sqs.approximate_receive_count"
 
 	SNSTargetName = "targetname"
 	SNSTopicName  = "topicname"
 
 	DynamoDBTableName = "tablename"
+	// DynamoDBConsumedCapacity is the total number of capacity units consumed by a request.
+	DynamoDBConsumedCapacity = "aws.dynamodb.consumed_capacity"
+	// DynamoDBConsumedReadCapacity is the number of read capacity units consumed by a request.
+	DynamoDBConsumedReadCapacity = "aws.dynamodb.consumed_read_capacity"
+	// DynamoDBConsumedWriteCapacity is the number of write capacity units consumed by a request.
+	DynamoDBConsumedWriteCapacity = "aws.dynamodb.consumed_write_capacity"
 
 	KinesisStreamName = "streamname"
 
@@ -31,4 +40,8 @@ const (
 	SFNStateMachineName = "statemachinename"
 
 	S3BucketName = "bucketname"
+	// S3ObjectSize is the size in bytes of the object read or written by a request.
+	S3ObjectSize = "aws.s3.object_size"
+	// S3StorageClass is the storage class of the object read or written by a request.
+	S3StorageClass = "aws.s3.storage_class"
 )
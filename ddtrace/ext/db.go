@@ -38,6 +38,9 @@ const (
 	DBSystemConsulKV      = "consul"
 	DBSystemLevelDB       = "leveldb"
 	DBSystemBuntDB        = "buntdb"
+	DBSystemSpanner       = "spanner"
+	DBSystemClickHouse    = "clickhouse"
+	DBSystemOpenSearch    = "opensearch"
 )
 
 // MicrosoftSQLServer tags.
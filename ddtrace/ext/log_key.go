@@ -10,4 +10,10 @@ const (
 	LogKeyTraceID = "dd.trace_id"
 	// LogKeySpanID is used by log integrations to correlate logs with a given span.
 	LogKeySpanID = "dd.span_id"
+	// LogKeyService is used by log integrations to tag logs with the service of the tracer.
+	LogKeyService = "dd.service"
+	// LogKeyEnv is used by log integrations to tag logs with the environment of the tracer.
+	LogKeyEnv = "dd.env"
+	// LogKeyVersion is used by log integrations to tag logs with the version of the tracer.
+	LogKeyVersion = "dd.version"
 )
@@ -10,12 +10,18 @@ const (
 	MessagingSystem = "messaging.system"
 	// MessagingDestinationName identifies message destination name
 	MessagingDestinationName = "messaging.destination.name"
+	// MessagingBatchMessageCount holds the number of messages in a batch.
+	MessagingBatchMessageCount = "messaging.batch.message_count"
 )
 
 // Available values for messaging.system.
 const (
 	MessagingSystemGCPPubsub = "googlepubsub"
 	MessagingSystemKafka     = "kafka"
+	MessagingSystemNATS      = "nats"
+	MessagingSystemRiver     = "river"
+	MessagingSystemAsynq     = "asynq"
+	MessagingSystemSMTP      = "smtp"
 )
 
 // Kafka tags.
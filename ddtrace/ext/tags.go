@@ -47,6 +47,10 @@ const (
 	// HTTPRoute is the route value of the HTTP request.
 	HTTPRoute = "http.route"
 
+	// HTTPRouteParams sets the HTTP route parameters partial tag, composed
+	// like HTTPRequestHeaders, i.e http.route.params.user_id.
+	HTTPRouteParams = "http.route.params"
+
 	// HTTPURL sets the HTTP URL for a span.
 	HTTPURL = "http.url"
 
@@ -61,6 +65,20 @@ const (
 	// See https://docs.datadoghq.com/tracing/trace_collection/tracing_naming_convention/#http-requests
 	HTTPRequestHeaders = "http.request.headers"
 
+	// HTTPResponseHeaders sets the HTTP response headers partial tag, composed
+	// like HTTPRequestHeaders.
+	HTTPResponseHeaders = "http.response.headers"
+
+	// HTTPRequestContentLength is the size in bytes of the HTTP request body.
+	HTTPRequestContentLength = "http.request.content_length"
+
+	// HTTPResponseContentLength is the size in bytes of the HTTP response body.
+	HTTPResponseContentLength = "http.response.content_length"
+
+	// HTTPResponseStreamed is set when the HTTP response was streamed, i.e. sent
+	// in chunks or without a known length.
+	HTTPResponseStreamed = "http.response.streamed"
+
 	// SpanName is a pseudo-key for setting a span's operation name by means of
 	// a tag. It is mostly here to facilitate vendor-agnostic frameworks like Opentracing
 	// and OpenCensus.
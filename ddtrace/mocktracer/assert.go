@@ -0,0 +1,240 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mocktracer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+)
+
+// Matcher describes the properties of a span, and of its relatives, to look
+// for among the finished spans. The zero value of a field matches any span.
+type Matcher struct {
+	Name     string
+	Service  string
+	Resource string
+	Type     string
+	// TagsSubset are tags the span must have, with the given values. The
+	// values are compared after conversion to the type of the span's ones,
+	// so that 1 matches a tag set to int64(1) or float64(1).
+	TagsSubset map[string]any
+	// Parent, if set, must match the parent of the span.
+	Parent *Matcher
+	// Children must each match a distinct child of the span, in any order.
+	// The span may have more children.
+	Children []Matcher
+}
+
+// String returns the description of m, with its children on separate
+// indented lines.
+func (m Matcher) String() string {
+	var b strings.Builder
+	m.write(&b, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (m Matcher) write(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s- %s\n", strings.Repeat("  ", depth), m.describe())
+	for _, c := range m.Children {
+		c.write(b, depth+1)
+	}
+}
+
+// describe returns the description of m on a single line, without its children.
+func (m Matcher) describe() string {
+	var props []string
+	for _, p := range [][2]string{{"name", m.Name}, {"service", m.Service}, {"resource", m.Resource}, {"type", m.Type}} {
+		if p[1] != "" {
+			props = append(props, fmt.Sprintf("%s=%q", p[0], p[1]))
+		}
+	}
+	keys := make([]string, 0, len(m.TagsSubset))
+	for k := range m.TagsSubset {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		props = append(props, fmt.Sprintf("%s=%v", k, m.TagsSubset[k]))
+	}
+	if len(props) == 0 {
+		props = append(props, "any span")
+	}
+	if m.Parent != nil {
+		props = append(props, "parent={"+m.Parent.describe()+"}")
+	}
+	return strings.Join(props, " ")
+}
+
+// spanTree indexes finished spans by ID and by parent, to match hierarchies.
+type spanTree struct {
+	spans    []*Span
+	byID     map[uint64]*Span
+	children map[uint64][]*Span
+}
+
+func newSpanTree(spans []*Span) *spanTree {
+	st := &spanTree{
+		spans:    spans,
+		byID:     make(map[uint64]*Span, len(spans)),
+		children: make(map[uint64][]*Span),
+	}
+	for _, s := range spans {
+		st.byID[s.SpanID()] = s
+	}
+	for _, s := range spans {
+		st.children[s.ParentID()] = append(st.children[s.ParentID()], s)
+	}
+	for _, c := range st.children {
+		sortSpans(c)
+	}
+	return st
+}
+
+func sortSpans(spans []*Span) {
+	slices.SortStableFunc(spans, func(a, b *Span) int {
+		return a.StartTime().Compare(b.StartTime())
+	})
+}
+
+// roots returns the spans whose parent isn't among the finished spans.
+func (st *spanTree) roots() []*Span {
+	var roots []*Span
+	for _, s := range st.spans {
+		if _, ok := st.byID[s.ParentID()]; !ok {
+			roots = append(roots, s)
+		}
+	}
+	sortSpans(roots)
+	return roots
+}
+
+// mismatch returns why s doesn't match m, or an empty string if it does.
+func (st *spanTree) mismatch(m Matcher, s *Span) string {
+	for _, p := range []struct{ name, want, tag string }{
+		{"name", m.Name, ext.SpanName},
+		{"service", m.Service, ext.ServiceName},
+		{"resource", m.Resource, ext.ResourceName},
+		{"type", m.Type, ext.SpanType},
+	} {
+		if got := s.Tag(p.tag); p.want != "" && got != p.want {
+			return fmt.Sprintf("%s: want %q, got %v", p.name, p.want, got)
+		}
+	}
+	keys := make([]string, 0, len(m.TagsSubset))
+	for k := range m.TagsSubset {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		want, got := m.TagsSubset[k], s.Tag(k)
+		if got == nil {
+			return fmt.Sprintf("tag %s: want %v, not set", k, want)
+		}
+		if !assert.ObjectsAreEqualValues(want, got) {
+			return fmt.Sprintf("tag %s: want %v, got %v", k, want, got)
+		}
+	}
+	if m.Parent != nil {
+		parent, ok := st.byID[s.ParentID()]
+		if !ok {
+			return "parent: not among the finished spans"
+		}
+		if why := st.mismatch(*m.Parent, parent); why != "" {
+			return "parent " + why
+		}
+	}
+	if len(m.Children) > 0 && !st.matchChildren(m.Children, st.children[s.SpanID()], make(map[*Span]bool)) {
+		return "children don't match"
+	}
+	return ""
+}
+
+// matchChildren reports whether each of ms matches a distinct span of
+// children which isn't in used.
+func (st *spanTree) matchChildren(ms []Matcher, children []*Span, used map[*Span]bool) bool {
+	if len(ms) == 0 {
+		return true
+	}
+	for _, c := range children {
+		if used[c] || st.mismatch(ms[0], c) != "" {
+			continue
+		}
+		used[c] = true
+		if st.matchChildren(ms[1:], children, used) {
+			return true
+		}
+		used[c] = false
+	}
+	return false
+}
+
+// find returns the spans matching m.
+func (st *spanTree) find(m Matcher) []*Span {
+	var found []*Span
+	for _, s := range st.spans {
+		if st.mismatch(m, s) == "" {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+// format returns the finished spans as trees. If m isn't nil, each span with
+// the name m looks for is annotated with the reason it doesn't match m.
+func (st *spanTree) format(m *Matcher) string {
+	if len(st.spans) == 0 {
+		return "(no finished spans)"
+	}
+	var b strings.Builder
+	var walk func(s *Span, depth int)
+	walk = func(s *Span, depth int) {
+		fmt.Fprintf(&b, "%s- name=%q service=%q resource=%q", strings.Repeat("  ", depth), s.OperationName(), s.Tag(ext.ServiceName), s.Tag(ext.ResourceName))
+		if m != nil && (m.Name == "" || m.Name == s.OperationName()) {
+			if why := st.mismatch(*m, s); why != "" {
+				fmt.Fprintf(&b, " <- %s", why)
+			}
+		}
+		b.WriteByte('\n')
+		for _, c := range st.children[s.SpanID()] {
+			walk(c, depth+1)
+		}
+	}
+	for _, r := range st.roots() {
+		walk(r, 0)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// FindSpans returns the spans of spans matching m, in the order of spans.
+func FindSpans(spans []*Span, m Matcher) []*Span {
+	return newSpanTree(spans).find(m)
+}
+
+// FormatSpans returns spans as indented trees of spans and children, to be
+// printed when debugging tests.
+func FormatSpans(spans []*Span) string {
+	return newSpanTree(spans).format(nil)
+}
+
+// assertSpan asserts that one of spans matches m, returning the first one. On
+// failure, it reports the finished spans as trees, annotated with the reason
+// each of the candidates doesn't match.
+func assertSpan(t testing.TB, spans []*Span, m Matcher) *Span {
+	t.Helper()
+	st := newSpanTree(spans)
+	if found := st.find(m); len(found) > 0 {
+		return found[0]
+	}
+	t.Errorf("no finished span matches:\n%s\nfinished spans:\n%s", m, st.format(&m))
+	return nil
+}
@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mocktracer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingT records the failures of the assertions instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertSpan(t *testing.T) {
+	mt := Start()
+	defer mt.Stop()
+
+	root := tracer.StartSpan("http.request", tracer.ServiceName("web"), tracer.ResourceName("GET /users"))
+	db := tracer.StartSpan("db.query", tracer.ServiceName("db"), tracer.ChildOf(root.Context()),
+		tracer.Tag(ext.DBSystem, "postgresql"), tracer.Tag("rows", 3))
+	db.Finish()
+	cache := tracer.StartSpan("cache.get", tracer.ServiceName("redis"), tracer.ChildOf(root.Context()))
+	cache.Finish()
+	root.Finish()
+
+	t.Run("match", func(t *testing.T) {
+		s := mt.AssertSpan(t, Matcher{
+			Name:       "db.query",
+			Service:    "db",
+			TagsSubset: map[string]any{ext.DBSystem: "postgresql", "rows": 3},
+			Parent:     &Matcher{Name: "http.request"},
+		})
+		require.NotNil(t, s)
+		assert.Equal(t, db.Context().SpanID(), s.SpanID())
+
+		s = mt.AssertSpan(t, Matcher{
+			Resource: "GET /users",
+			Children: []Matcher{{Name: "cache.get"}, {Name: "db.query", Service: "db"}},
+		})
+		require.NotNil(t, s)
+		assert.Equal(t, root.Context().SpanID(), s.SpanID())
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			m    Matcher
+			want string
+		}{
+			"service": {
+				m:    Matcher{Name: "db.query", Service: "mysql"},
+				want: `service: want "mysql", got db`,
+			},
+			"tag": {
+				m:    Matcher{Name: "db.query", TagsSubset: map[string]any{ext.DBSystem: "mysql"}},
+				want: "tag db.system: want mysql, got postgresql",
+			},
+			"missing-tag": {
+				m:    Matcher{Name: "db.query", TagsSubset: map[string]any{ext.DBInstance: "users"}},
+				want: "tag db.instance: want users, not set",
+			},
+			"parent": {
+				m:    Matcher{Name: "db.query", Parent: &Matcher{Name: "grpc.server"}},
+				want: `parent name: want "grpc.server", got http.request`,
+			},
+			"children": {
+				m:    Matcher{Name: "http.request", Children: []Matcher{{Name: "db.query"}, {Name: "db.query"}}},
+				want: "children don't match",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				rt := &recordingT{TB: t}
+				assert.Nil(t, mt.AssertSpan(rt, tc.m))
+				require.Len(t, rt.errors, 1)
+				assert.Contains(t, rt.errors[0], tc.want)
+				assert.Contains(t, rt.errors[0], "\n  - name=")
+			})
+		}
+	})
+
+	t.Run("format", func(t *testing.T) {
+		assert.Equal(t, `- name="http.request" service="web" resource="GET /users"
+  - name="db.query" service="db" resource="db.query"
+  - name="cache.get" service="redis" resource="cache.get"`, FormatSpans(mt.FinishedSpans()))
+		assert.Equal(t, `- name="db.query" parent={name="http.request"}
+  - service="redis"`, Matcher{Name: "db.query", Parent: &Matcher{Name: "http.request"}, Children: []Matcher{{Service: "redis"}}}.String())
+	})
+
+	t.Run("find", func(t *testing.T) {
+		assert.Len(t, FindSpans(mt.FinishedSpans(), Matcher{Parent: &Matcher{Service: "web"}}), 2)
+		assert.Empty(t, FindSpans(mt.FinishedSpans(), Matcher{Type: "web"}))
+	})
+}
@@ -8,6 +8,7 @@ package mocktracer
 import (
 	"sync"
 	"sync/atomic"
+	"testing"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -31,7 +32,7 @@ var (
 )
 
 // Creates a new CIVisibilityMockTracer that uses the mock tracer for all spans except the CIVisibility spans.
-func newCIVisibilityMockTracer() *civisibilitymocktracer {
+func newCIVisibilityMockTracer(opts ...Option) *civisibilitymocktracer {
 	currentTracer := getGlobalTracer()
 	// let's check if the current tracer is already a civisibilitymocktracer
 	// if so, we need to get the real tracer from it
@@ -39,7 +40,7 @@ func newCIVisibilityMockTracer() *civisibilitymocktracer {
 		currentTracer = currentCIVisibilityMockTracer.real
 	}
 	return &civisibilitymocktracer{
-		mock: newMockTracer(),
+		mock: newMockTracer(opts...),
 		real: currentTracer,
 	}
 }
@@ -135,6 +136,17 @@ func (t *civisibilitymocktracer) FinishedSpans() []*Span {
 	return t.mock.FinishedSpans()
 }
 
+// AssertSpan asserts that one of the finished spans matches m, and returns the first one.
+func (t *civisibilitymocktracer) AssertSpan(tb testing.TB, m Matcher) *Span {
+	tb.Helper()
+	return t.mock.AssertSpan(tb, m)
+}
+
+// InjectedHeaders returns the headers injected by the mock tracer.
+func (t *civisibilitymocktracer) InjectedHeaders() []map[string]string {
+	return t.mock.InjectedHeaders()
+}
+
 // Reset clears all spans (both open and finished) from the mock tracer.
 // This is especially useful when running tests in a loop, where a clean state
 // is desired between test iterations.
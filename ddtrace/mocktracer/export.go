@@ -0,0 +1,259 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mocktracer
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+)
+
+// volatileTags are the tags whose values change from a run to another, and
+// which are left out of the exports.
+var volatileTags = map[string]bool{
+	ext.RuntimeID:    true,
+	"process_id":     true,
+	"_dd.p.tid":      true,
+	ext.ErrorStack:   true,
+	"_dd.span_links": true,
+	ext.SpanName:     true,
+	ext.ServiceName:  true,
+	ext.ResourceName: true,
+	ext.SpanType:     true,
+}
+
+// exportedSpan is the representation of a span in the JSON export.
+type exportedSpan struct {
+	Name     string             `json:"name"`
+	Service  string             `json:"service"`
+	Resource string             `json:"resource"`
+	Type     string             `json:"type,omitempty"`
+	Error    bool               `json:"error,omitempty"`
+	Meta     map[string]string  `json:"meta,omitempty"`
+	Metrics  map[string]float64 `json:"metrics,omitempty"`
+	Children []*exportedSpan    `json:"children,omitempty"`
+}
+
+// exportTrees returns spans as trees of exported spans, in a deterministic
+// order: the siblings are sorted by name, resource and service, then by
+// start time.
+func exportTrees(spans []*Span) []*exportedSpan {
+	st := newSpanTree(spans)
+	var export func(s *Span) *exportedSpan
+	export = func(s *Span) *exportedSpan {
+		es := &exportedSpan{
+			Name:     s.OperationName(),
+			Service:  fmt.Sprint(s.Tag(ext.ServiceName)),
+			Resource: fmt.Sprint(s.Tag(ext.ResourceName)),
+			Type:     fmt.Sprint(s.Tag(ext.SpanType)),
+			Error:    s.Tag(ext.ErrorMsg) != nil || s.Tag(ext.ErrorType) != nil,
+		}
+		for k, v := range s.Tags() {
+			if volatileTags[k] {
+				continue
+			}
+			switch v := v.(type) {
+			case float64:
+				if es.Metrics == nil {
+					es.Metrics = make(map[string]float64)
+				}
+				es.Metrics[k] = v
+			default:
+				if es.Meta == nil {
+					es.Meta = make(map[string]string)
+				}
+				es.Meta[k] = fmt.Sprint(v)
+			}
+		}
+		for _, c := range st.children[s.SpanID()] {
+			es.Children = append(es.Children, export(c))
+		}
+		sortExported(es.Children)
+		return es
+	}
+	roots := st.roots()
+	trees := make([]*exportedSpan, 0, len(roots))
+	for _, r := range roots {
+		trees = append(trees, export(r))
+	}
+	sortExported(trees)
+	return trees
+}
+
+// sortExported sorts exported, initially sorted by start time, by name,
+// resource and service.
+func sortExported(exported []*exportedSpan) {
+	order := make(map[*exportedSpan]int, len(exported))
+	for i, es := range exported {
+		order[es] = i
+	}
+	slices.SortStableFunc(exported, func(a, b *exportedSpan) int {
+		return cmp.Or(
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Resource, b.Resource),
+			cmp.Compare(a.Service, b.Service),
+			cmp.Compare(order[a], order[b]),
+		)
+	})
+}
+
+// ExportJSON writes spans to w as indented JSON, in a format meant to be
+// compared with golden files: each trace is a tree of spans holding their
+// children, and the values changing from a run to another, such as the IDs,
+// the timestamps, the runtime ID or the error stacks, are left out. Spans are
+// typically the finished spans of a mock tracer:
+//
+//	var buf bytes.Buffer
+//	if err := mocktracer.ExportJSON(&buf, mt.FinishedSpans()); err != nil {
+//		t.Fatal(err)
+//	}
+//	golden, _ := os.ReadFile("testdata/trace.golden.json")
+//	assert.JSONEq(t, string(golden), buf.String())
+func ExportJSON(w io.Writer, spans []*Span) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exportTrees(spans))
+}
+
+// OTLP JSON encoding of the spans, as defined by the OpenTelemetry protocol.
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+type (
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpScopeSpans struct {
+		Scope struct {
+			Name string `json:"name"`
+		} `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		Name              string          `json:"name"`
+		Kind              int             `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Status            otlpStatus      `json:"status"`
+	}
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+// otlpSpanKinds maps the span.kind tag values to the OTLP span kinds.
+var otlpSpanKinds = map[string]int{
+	ext.SpanKindInternal: 1,
+	ext.SpanKindServer:   2,
+	ext.SpanKindClient:   3,
+	ext.SpanKindProducer: 4,
+	ext.SpanKindConsumer: 5,
+}
+
+// ExportOTLP writes spans to w in the JSON encoding of the OTLP traces, with
+// one resource per service, to be compared with golden files or loaded in
+// OpenTelemetry tools. Like with ExportJSON, the values changing from a run
+// to another are left out: the trace and span IDs are numbered in the order
+// of the spans, and the timestamps are zero. The span name is the span
+// resource, the Datadog span name being the operation.name attribute.
+func ExportOTLP(w io.Writer, spans []*Span) error {
+	var (
+		out       otlpTraces
+		resources = make(map[string]int)
+		traceID   int
+		spanID    int
+	)
+	var export func(es *exportedSpan, parentID string)
+	export = func(es *exportedSpan, parentID string) {
+		spanID++
+		span := otlpSpan{
+			TraceID:           fmt.Sprintf("%032x", traceID),
+			SpanID:            fmt.Sprintf("%016x", spanID),
+			ParentSpanID:      parentID,
+			Name:              es.Resource,
+			Kind:              cmp.Or(otlpSpanKinds[es.Meta[ext.SpanKind]], 1),
+			StartTimeUnixNano: "0",
+			EndTimeUnixNano:   "0",
+		}
+		span.Attributes = append(span.Attributes, stringAttribute("operation.name", es.Name))
+		if es.Type != "" {
+			span.Attributes = append(span.Attributes, stringAttribute("span.type", es.Type))
+		}
+		keys := make([]string, 0, len(es.Meta))
+		for k := range es.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			span.Attributes = append(span.Attributes, stringAttribute(k, es.Meta[k]))
+		}
+		keys = keys[:0]
+		for k := range es.Metrics {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := es.Metrics[k]
+			span.Attributes = append(span.Attributes, otlpAttribute{Key: k, Value: otlpValue{DoubleValue: &v}})
+		}
+		if es.Error {
+			span.Status = otlpStatus{Code: 2, Message: es.Meta[ext.ErrorMsg]}
+		}
+
+		i, ok := resources[es.Service]
+		if !ok {
+			i = len(out.ResourceSpans)
+			resources[es.Service] = i
+			var rs otlpResourceSpans
+			rs.Resource.Attributes = []otlpAttribute{stringAttribute("service.name", es.Service)}
+			rs.ScopeSpans = []otlpScopeSpans{{}}
+			rs.ScopeSpans[0].Scope.Name = "dd-trace-go"
+			out.ResourceSpans = append(out.ResourceSpans, rs)
+		}
+		out.ResourceSpans[i].ScopeSpans[0].Spans = append(out.ResourceSpans[i].ScopeSpans[0].Spans, span)
+
+		id := span.SpanID
+		for _, c := range es.Children {
+			export(c, id)
+		}
+	}
+	for _, root := range exportTrees(spans) {
+		traceID++
+		export(root, "")
+	}
+	if out.ResourceSpans == nil {
+		out.ResourceSpans = []otlpResourceSpans{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mocktracer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordTrace records a trace, starting the children in the given order.
+func recordTrace(t *testing.T, children ...string) []*Span {
+	mt := Start()
+	defer mt.Stop()
+	root := tracer.StartSpan("http.request", tracer.ServiceName("web"), tracer.ResourceName("GET /users"),
+		tracer.Tag(ext.SpanKind, ext.SpanKindServer), tracer.Tag(ext.RuntimeID, "volatile"))
+	for _, name := range children {
+		child := tracer.StartSpan(name, tracer.ServiceName("db"), tracer.ChildOf(root.Context()), tracer.Tag("rows", 3))
+		child.Finish(tracer.WithError(errors.New("timeout")))
+	}
+	root.Finish()
+	return mt.FinishedSpans()
+}
+
+func TestExportJSON(t *testing.T) {
+	var first, second bytes.Buffer
+	require.NoError(t, ExportJSON(&first, recordTrace(t, "db.query", "db.connect")))
+	require.NoError(t, ExportJSON(&second, recordTrace(t, "db.connect", "db.query")))
+	// The exports don't depend on IDs, timestamps or the order of the spans.
+	assert.Equal(t, first.String(), second.String())
+
+	var trees []exportedSpan
+	require.NoError(t, json.Unmarshal(first.Bytes(), &trees))
+	require.Len(t, trees, 1)
+	root := trees[0]
+	assert.Equal(t, "http.request", root.Name)
+	assert.Equal(t, "web", root.Service)
+	assert.Equal(t, "GET /users", root.Resource)
+	assert.False(t, root.Error)
+	assert.Equal(t, ext.SpanKindServer, root.Meta[ext.SpanKind])
+	assert.NotContains(t, root.Meta, ext.RuntimeID)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, "db.connect", root.Children[0].Name)
+	assert.Equal(t, "db.query", root.Children[1].Name)
+	assert.True(t, root.Children[1].Error)
+	assert.Equal(t, "timeout", root.Children[1].Meta[ext.ErrorMsg])
+	assert.NotContains(t, root.Children[1].Meta, ext.ErrorStack)
+	assert.Equal(t, float64(3), root.Children[1].Metrics["rows"])
+
+	var empty bytes.Buffer
+	require.NoError(t, ExportJSON(&empty, nil))
+	assert.JSONEq(t, "[]", empty.String())
+}
+
+func TestExportOTLP(t *testing.T) {
+	var first, second bytes.Buffer
+	require.NoError(t, ExportOTLP(&first, recordTrace(t, "db.query", "db.connect")))
+	require.NoError(t, ExportOTLP(&second, recordTrace(t, "db.connect", "db.query")))
+	assert.Equal(t, first.String(), second.String())
+
+	var got otlpTraces
+	require.NoError(t, json.Unmarshal(first.Bytes(), &got))
+	require.Len(t, got.ResourceSpans, 2)
+
+	web := got.ResourceSpans[0]
+	assert.Equal(t, "web", *web.Resource.Attributes[0].Value.StringValue)
+	require.Len(t, web.ScopeSpans[0].Spans, 1)
+	root := web.ScopeSpans[0].Spans[0]
+	assert.Equal(t, "00000000000000000000000000000001", root.TraceID)
+	assert.Equal(t, "0000000000000001", root.SpanID)
+	assert.Empty(t, root.ParentSpanID)
+	assert.Equal(t, "GET /users", root.Name)
+	assert.Equal(t, 2, root.Kind)
+	assert.Equal(t, "operation.name", root.Attributes[0].Key)
+	assert.Equal(t, "http.request", *root.Attributes[0].Value.StringValue)
+
+	db := got.ResourceSpans[1]
+	assert.Equal(t, "db", *db.Resource.Attributes[0].Value.StringValue)
+	require.Len(t, db.ScopeSpans[0].Spans, 2)
+	for _, s := range db.ScopeSpans[0].Spans {
+		assert.Equal(t, root.TraceID, s.TraceID)
+		assert.Equal(t, root.SpanID, s.ParentSpanID)
+		assert.Equal(t, 1, s.Kind)
+		assert.Equal(t, otlpStatus{Code: 2, Message: "timeout"}, s.Status)
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"testing"
+	_ "unsafe" // Needed for go:linkname directive.
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/internal"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -46,6 +48,15 @@ type Tracer interface {
 
 	SentDSMBacklogs() []DSMBacklog
 
+	// InjectedHeaders returns the headers injected by Inject in the carriers
+	// implementing tracer.TextMapWriter, in the order of the calls.
+	InjectedHeaders() []map[string]string
+
+	// AssertSpan asserts that one of the finished spans matches m, and
+	// returns the first one, or nil. On failure, the finished spans are
+	// reported as trees, with the reason each one doesn't match.
+	AssertSpan(t testing.TB, m Matcher) *Span
+
 	// Reset resets the spans and services recorded in the tracer. This is
 	// especially useful when running tests in a loop, where a clean start
 	// is desired for FinishedSpans calls.
@@ -56,22 +67,43 @@ type Tracer interface {
 	Stop()
 }
 
+// Option configures the mock tracer.
+type Option func(*config)
+
+type config struct {
+	sampling bool
+}
+
+// WithSampling makes the mock tracer take the sampling decision of the root
+// spans the way the tracer does, according to DD_TRACE_SAMPLE_RATE,
+// DD_TRACE_RATE_LIMIT and DD_TRACE_SAMPLING_RULES, instead of leaving it to
+// the propagated context, if any. The spans then hold the sampling priority
+// and the decision maker the tracer would set.
+func WithSampling() Option {
+	return func(c *config) {
+		c.sampling = true
+	}
+}
+
 // Start sets the internal tracer to a mock and returns an interface
 // which allows querying it. Call Start at the beginning of your tests
 // to activate the mock tracer. When your test runs, use the returned
 // interface to query the tracer's state.
-func Start() Tracer {
+//
+// The mock tracer propagates the span contexts the way the tracer does,
+// honoring the propagation styles of the environment.
+func Start(opts ...Option) Tracer {
 	if utils.BoolEnv(constants.CIVisibilityEnabledEnvironmentVariable, false) && !civisibility.IsTestMode() {
 		// If CI Visibility is enabled (and we are not in a CI Visibility testing mode), we need to use the CIVisibilityMockTracer
 		// to bypass the CI Visibility spans from the mocktracer.
 		// This supports the scenario where the mocktracer is used in a test (we need to keep reporting test spans)
-		t := newCIVisibilityMockTracer()
+		t := newCIVisibilityMockTracer(opts...)
 		// Set the global tracer to the mock tracer without stopping the old one (inside the mock tracer)
 		internal.StoreGlobalTracer[Tracer, tracer.Tracer](t)
 		return t
 	}
 
-	var t tracer.Tracer = newMockTracer()
+	var t tracer.Tracer = newMockTracer(opts...)
 	internal.SetGlobalTracer(t)
 	return t.(Tracer)
 }
@@ -86,6 +118,11 @@ type mocktracer struct {
 	openSpans     map[uint64]*Span
 	dsmTransport  *mockDSMTransport
 	dsmProcessor  *datastreams.Processor
+	injected      []map[string]string
+
+	propagator tracer.Propagator
+	// sample makes the sampling decision of the root spans, if enabled.
+	sample func(*tracer.Span)
 }
 
 func (t *mocktracer) SentDSMBacklogs() []DSMBacklog {
@@ -93,9 +130,17 @@ func (t *mocktracer) SentDSMBacklogs() []DSMBacklog {
 	return t.dsmTransport.backlogs
 }
 
-func newMockTracer() *mocktracer {
+func newMockTracer(opts ...Option) *mocktracer {
+	var cfg config
+	for _, fn := range opts {
+		fn(&cfg)
+	}
 	var t mocktracer
 	t.openSpans = make(map[uint64]*Span)
+	t.propagator = propagatorFromEnv()
+	if cfg.sampling {
+		t.sample = newSpanSampler()
+	}
 	t.dsmTransport = &mockDSMTransport{}
 	client := &http.Client{
 		Transport: t.dsmTransport,
@@ -123,6 +168,14 @@ func (t *mocktracer) StartSpan(operationName string, opts ...tracer.StartSpanOpt
 		fn(&cfg)
 	}
 	span := newSpan(operationName, &cfg)
+	if span == nil {
+		// the integration starting the span was disabled
+		return nil
+	}
+	if t.sample != nil {
+		// Only the spans whose trace has no sampling decision yet are sampled.
+		t.sample(span)
+	}
 
 	t.Lock()
 	t.openSpans[span.Context().SpanID()] = MockSpan(span)
@@ -159,6 +212,11 @@ func (t *mocktracer) FinishedSpans() []*Span {
 	return t.finishedSpans
 }
 
+func (t *mocktracer) AssertSpan(tb testing.TB, m Matcher) *Span {
+	tb.Helper()
+	return assertSpan(tb, t.FinishedSpans(), m)
+}
+
 func (t *mocktracer) Reset() {
 	t.Lock()
 	defer t.Unlock()
@@ -166,6 +224,7 @@ func (t *mocktracer) Reset() {
 		delete(t.openSpans, k)
 	}
 	t.finishedSpans = nil
+	t.injected = nil
 }
 
 func (t *mocktracer) addFinishedSpan(s *tracer.Span) {
@@ -191,16 +250,48 @@ const (
 	baggagePrefix  = tracer.DefaultBaggageHeaderPrefix
 )
 
+//go:linkname propagatorFromEnv github.com/DataDog/dd-trace-go/v2/ddtrace/tracer.propagatorFromEnv
+func propagatorFromEnv() tracer.Propagator
+
+//go:linkname newSpanSampler github.com/DataDog/dd-trace-go/v2/ddtrace/tracer.newSpanSampler
+func newSpanSampler() func(*tracer.Span)
+
+// getPropagator returns the propagator of t, or a new one if t wasn't created
+// by newMockTracer.
+func (t *mocktracer) getPropagator() tracer.Propagator {
+	if t.propagator == nil {
+		return propagatorFromEnv()
+	}
+	return t.propagator
+}
+
 func (t *mocktracer) Extract(carrier interface{}) (*tracer.SpanContext, error) {
-	return tracer.NewPropagator(&tracer.PropagatorConfig{
-		MaxTagsHeaderLen: 512,
-	}).Extract(carrier)
+	return t.getPropagator().Extract(carrier)
 }
 
 func (t *mocktracer) Inject(context *tracer.SpanContext, carrier interface{}) error {
-	return tracer.NewPropagator(&tracer.PropagatorConfig{
-		MaxTagsHeaderLen: 512,
-	}).Inject(context, carrier)
+	w, ok := carrier.(tracer.TextMapWriter)
+	if !ok {
+		return t.getPropagator().Inject(context, carrier)
+	}
+	// Inject in a map first to record the headers.
+	headers := make(tracer.TextMapCarrier)
+	if err := t.getPropagator().Inject(context, headers); err != nil {
+		return err
+	}
+	for k, v := range headers {
+		w.Set(k, v)
+	}
+	t.Lock()
+	t.injected = append(t.injected, map[string]string(headers))
+	t.Unlock()
+	return nil
+}
+
+func (t *mocktracer) InjectedHeaders() []map[string]string {
+	t.RLock()
+	defer t.RUnlock()
+	return t.injected
 }
 
 func (t *mocktracer) TracerConf() tracer.TracerConf {
@@ -7,6 +7,7 @@ package mocktracer
 
 import (
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStart(t *testing.T) {
@@ -214,6 +216,63 @@ func TestTracerInject(t *testing.T) {
 	})
 }
 
+func TestTracerInjectedHeaders(t *testing.T) {
+	t.Setenv("DD_TRACE_PROPAGATION_STYLE", "tracecontext")
+	mt := newMockTracer()
+	defer mt.Stop()
+
+	sp := mt.StartSpan("op", tracer.WithSpanID(2))
+	carrier := make(map[string]string)
+	assert.NoError(t, mt.Inject(sp.Context(), tracer.TextMapCarrier(carrier)))
+	assert.Error(t, mt.Inject(sp.Context(), 2))
+
+	headers := mt.InjectedHeaders()
+	require.Len(t, headers, 1)
+	assert.Equal(t, carrier, headers[0])
+	assert.Contains(t, headers[0], "traceparent")
+	assert.NotContains(t, headers[0], traceHeader)
+
+	mt.Reset()
+	assert.Empty(t, mt.InjectedHeaders())
+}
+
+func TestTracerSampling(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		mt := newMockTracer()
+		defer mt.Stop()
+		_, ok := mt.StartSpan("op").Context().SamplingPriority()
+		assert.False(t, ok)
+	})
+
+	t.Run("rules", func(t *testing.T) {
+		t.Setenv("DD_TRACE_SAMPLING_RULES", `[{"service": "kept", "sample_rate": 1}, {"service": "dropped", "sample_rate": 0}]`)
+		mt := newMockTracer(WithSampling())
+		defer mt.Stop()
+
+		for _, tc := range []struct {
+			service  string
+			priority int
+		}{
+			{service: "kept", priority: ext.PriorityUserKeep},
+			{service: "dropped", priority: ext.PriorityUserReject},
+			{service: "other", priority: ext.PriorityAutoKeep},
+		} {
+			root := mt.StartSpan("op", tracer.ServiceName(tc.service))
+			p, ok := root.Context().SamplingPriority()
+			assert.True(t, ok)
+			assert.Equal(t, tc.priority, p, tc.service)
+
+			// Children and propagated contexts keep the decision of the root span.
+			child := mt.StartSpan("child", tracer.ChildOf(root.Context()), tracer.ServiceName("kept"))
+			p, _ = child.Context().SamplingPriority()
+			assert.Equal(t, tc.priority, p, tc.service)
+			carrier := make(map[string]string)
+			require.NoError(t, mt.Inject(child.Context(), tracer.TextMapCarrier(carrier)))
+			assert.Equal(t, strconv.Itoa(tc.priority), carrier[priorityHeader])
+		}
+	})
+}
+
 func TestTracerExtract(t *testing.T) {
 	// carry creates a tracer.TextMapCarrier containing the given sequence
 	// of key/value pairs.
@@ -0,0 +1,233 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+const (
+	// agentEndpointMinBackoff and agentEndpointMaxBackoff bound the delay
+	// between two health checks of an unavailable agent endpoint, doubled after
+	// each failed check.
+	agentEndpointMinBackoff = time.Second
+	agentEndpointMaxBackoff = time.Minute
+
+	// agentEndpointCheckTimeout is the timeout of the health checks.
+	agentEndpointCheckTimeout = 5 * time.Second
+)
+
+// errNoAgentEndpoint is returned when all the agent endpoints are unavailable.
+var errNoAgentEndpoint = errors.New("all the agent endpoints are unavailable")
+
+var _ http.RoundTripper = (*agentEndpoints)(nil)
+
+// agentEndpoints is a http.RoundTripper sending the requests addressed to the
+// agent to the first available one among several endpoints, in order of
+// preference. When a request fails to reach an endpoint, the endpoint is marked
+// unavailable and the request is sent to the next one, if its body can be read
+// again. The unavailable endpoints are health checked with exponential backoff,
+// and receive the requests again as soon as they answer, so that a restart of
+// the preferred agent doesn't drop the payloads sent meanwhile.
+type agentEndpoints struct {
+	// tcp is the transport of the http and https endpoints, and of the
+	// requests which aren't addressed to the agent.
+	tcp *http.Transport
+
+	// endpoints lists the endpoints, in order of preference.
+	endpoints []*agentEndpoint
+
+	// minBackoff is the delay before the first health check of an
+	// unavailable endpoint; replaced in tests.
+	minBackoff time.Duration
+
+	mu      sync.Mutex // guards the state of the endpoints and stopped
+	stopped bool
+}
+
+// agentEndpoint is an agent endpoint of agentEndpoints.
+type agentEndpoint struct {
+	// url is the URL of the endpoint, as configured.
+	url *url.URL
+
+	// target holds the scheme and host the requests are sent to.
+	target *url.URL
+
+	// transport is the transport reaching the endpoint.
+	transport http.RoundTripper
+
+	// available reports whether the endpoint receives the requests.
+	available bool
+
+	// failures counts the consecutive failures to reach the endpoint.
+	failures int
+
+	// check schedules the next health check, while the endpoint is unavailable.
+	check *time.Timer
+}
+
+// newAgentEndpoints returns a router to the agents listening at urls, in
+// order of preference, with the transports of defaultHTTPClient and udsClient.
+func newAgentEndpoints(urls []*url.URL, timeout time.Duration) *agentEndpoints {
+	a := &agentEndpoints{
+		tcp:        defaultHTTPClient(timeout).Transport.(*http.Transport),
+		minBackoff: agentEndpointMinBackoff,
+	}
+	for _, u := range urls {
+		e := &agentEndpoint{url: u, target: u, transport: a.tcp, available: true}
+		if u.Scheme == "unix" {
+			e.target = udsURL(u.Path)
+			e.transport = udsClient(u.Path, timeout).Transport
+		}
+		a.endpoints = append(a.endpoints, e)
+	}
+	return a
+}
+
+// url returns the URL the requests to the agent must be addressed to, the one
+// of the preferred endpoint.
+func (a *agentEndpoints) url() *url.URL {
+	return a.endpoints[0].target
+}
+
+// client returns an HTTP client sending its requests with a.
+func (a *agentEndpoints) client(timeout time.Duration) *http.Client {
+	if timeout == 0 {
+		timeout = defaultHTTPTimeout
+	}
+	return &http.Client{Transport: a, Timeout: timeout}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (a *agentEndpoints) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != a.url().Host {
+		return a.tcp.RoundTrip(req)
+	}
+	var lastErr error
+	for _, e := range a.endpoints {
+		if !a.isAvailable(e) {
+			continue
+		}
+		r := req.Clone(req.Context())
+		if lastErr != nil && req.Body != nil && req.Body != http.NoBody {
+			// The body was consumed by the previous attempt.
+			if req.GetBody == nil {
+				return nil, lastErr
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, lastErr
+			}
+			r.Body = body
+		}
+		r.URL.Scheme, r.URL.Host, r.Host = e.target.Scheme, e.target.Host, e.target.Host
+		resp, err := e.transport.RoundTrip(r)
+		if err == nil {
+			return resp, nil
+		}
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		a.markUnavailable(e, err)
+		lastErr = err
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, errNoAgentEndpoint
+}
+
+// isAvailable reports whether e receives the requests.
+func (a *agentEndpoints) isAvailable(e *agentEndpoint) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return e.available
+}
+
+// markUnavailable stops sending the requests to e, which failed with err,
+// until it passes a health check.
+func (a *agentEndpoints) markUnavailable(e *agentEndpoint, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !e.available {
+		return
+	}
+	log.Warn("Agent endpoint %s is unavailable, failing over to the next available one: %v", e.url, err)
+	e.available = false
+	e.failures = 1
+	a.scheduleCheck(e)
+}
+
+// scheduleCheck schedules the next health check of e, after a delay doubled
+// with each of its consecutive failures. a.mu must be held.
+func (a *agentEndpoints) scheduleCheck(e *agentEndpoint) {
+	if a.stopped {
+		return
+	}
+	backoff := agentEndpointMaxBackoff
+	if e.failures <= 16 {
+		backoff = min(a.minBackoff<<(e.failures-1), agentEndpointMaxBackoff)
+	}
+	e.check = time.AfterFunc(backoff, func() { a.checkHealth(e) })
+}
+
+// checkHealth makes e available again if it answers, or schedules its next
+// health check.
+func (a *agentEndpoints) checkHealth(e *agentEndpoint) {
+	healthy := e.healthy()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !healthy {
+		e.failures++
+		a.scheduleCheck(e)
+		return
+	}
+	log.Info("Agent endpoint %s is available again.", e.url)
+	e.available = true
+	e.failures = 0
+	e.check = nil
+}
+
+// healthy reports whether the agent at e answers to a request to its /info
+// endpoint. The agents without it answer with 404 Not Found.
+func (e *agentEndpoint) healthy() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), agentEndpointCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.target.String()+"/info", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := e.transport.RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode < http.StatusInternalServerError
+}
+
+// stop stops the health checks.
+func (a *agentEndpoints) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stopped = true
+	for _, e := range a.endpoints {
+		if e.check != nil {
+			e.check.Stop()
+		}
+	}
+}
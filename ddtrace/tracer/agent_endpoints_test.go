@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyAgent is an agent server closing the connections while it is down.
+type flakyAgent struct {
+	*httptest.Server
+	down   atomic.Bool
+	bodies atomic.Pointer[[]string]
+}
+
+func newFlakyAgent(t *testing.T) *flakyAgent {
+	a := &flakyAgent{}
+	a.bodies.Store(&[]string{})
+	a.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.down.Load() {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		if r.URL.Path == "/info" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies := append(*a.bodies.Load(), string(body))
+		a.bodies.Store(&bodies)
+	}))
+	t.Cleanup(a.Close)
+	return a
+}
+
+func (a *flakyAgent) url(t *testing.T) *url.URL {
+	u, err := url.Parse(a.URL)
+	require.NoError(t, err)
+	return u
+}
+
+func (a *flakyAgent) received() []string {
+	return *a.bodies.Load()
+}
+
+func TestAgentEndpointsFailover(t *testing.T) {
+	primary, secondary := newFlakyAgent(t), newFlakyAgent(t)
+	endpoints := newAgentEndpoints([]*url.URL{primary.url(t), secondary.url(t)}, 0)
+	endpoints.minBackoff = 10 * time.Millisecond
+	defer endpoints.stop()
+	client := endpoints.client(0)
+	assert.Equal(t, primary.URL, endpoints.url().String())
+
+	send := func(payload string) {
+		t.Helper()
+		resp, err := client.Post(endpoints.url().String()+"/v0.4/traces", "application/msgpack", bytes.NewBufferString(payload))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	send("1")
+	primary.down.Store(true)
+	send("2")
+	send("3")
+	assert.Equal(t, []string{"1"}, primary.received())
+	assert.Equal(t, []string{"2", "3"}, secondary.received())
+	assert.False(t, endpoints.isAvailable(endpoints.endpoints[0]))
+
+	primary.down.Store(false)
+	assert.Eventually(t, func() bool {
+		return endpoints.isAvailable(endpoints.endpoints[0])
+	}, 5*time.Second, 10*time.Millisecond)
+	send("4")
+	assert.Equal(t, []string{"1", "4"}, primary.received())
+}
+
+func TestAgentEndpointsUnavailable(t *testing.T) {
+	primary, secondary := newFlakyAgent(t), newFlakyAgent(t)
+	endpoints := newAgentEndpoints([]*url.URL{primary.url(t), secondary.url(t)}, 0)
+	defer endpoints.stop()
+	client := endpoints.client(0)
+	primary.down.Store(true)
+	secondary.down.Store(true)
+
+	_, err := client.Post(endpoints.url().String()+"/v0.4/traces", "application/msgpack", bytes.NewBufferString("1"))
+	assert.Error(t, err)
+	_, err = client.Post(endpoints.url().String()+"/v0.4/traces", "application/msgpack", bytes.NewBufferString("2"))
+	assert.ErrorIs(t, err, errNoAgentEndpoint)
+}
+
+func TestAgentURLsConfig(t *testing.T) {
+	primary, secondary := newFlakyAgent(t), newFlakyAgent(t)
+	t.Setenv("DD_TRACE_AGENT_URLS", strings.Join([]string{primary.URL, secondary.URL}, ","))
+	c, err := newConfig(withNoopStats())
+	require.NoError(t, err)
+	require.NotNil(t, c.agentEndpoints)
+	defer c.agentEndpoints.stop()
+	assert.Equal(t, primary.URL, c.agentURL.String())
+	assert.Same(t, c.agentEndpoints, c.httpClient.Transport)
+	assert.Len(t, c.agentEndpoints.endpoints, 2)
+
+	t.Run("custom-client", func(t *testing.T) {
+		c, err := newConfig(WithHTTPClient(&http.Client{}), withNoopStats())
+		require.NoError(t, err)
+		assert.Nil(t, c.agentEndpoints)
+		assert.Equal(t, primary.URL, c.agentURL.String())
+	})
+}
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// agentFeaturesRefreshInterval is how often the capabilities of the trace-agent
+// are discovered again, as it can be upgraded, downgraded or reconfigured while
+// the tracer runs.
+const agentFeaturesRefreshInterval = 5 * time.Minute
+
+// agentFeatures returns the capabilities of the trace-agent the tracer's
+// behaviour is gated on: the ones of its last discovery, or the ones
+// discovered when the tracer started.
+func (c *config) agentFeatures() *agentFeatures {
+	if a := c.refreshedAgent.Load(); a != nil {
+		return a
+	}
+	return &c.agent
+}
+
+// refreshAgentFeatures discovers the capabilities of the trace-agent every
+// interval, until the tracer stops.
+func (t *tracer) refreshAgentFeatures(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.config.updateAgentFeatures()
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// updateAgentFeatures discovers the capabilities of the trace-agent. When the
+// agent can't be reached or its answer can't be decoded, the capabilities
+// last discovered are kept.
+func (c *config) updateAgentFeatures() {
+	features, err := fetchAgentFeatures(c.agentURL, c.httpClient)
+	if err != nil {
+		log.Debug("Refreshing agent features, keeping the last known ones: %v", err)
+		return
+	}
+	if old := c.agentFeatures(); old.Stats != features.Stats || old.DropP0s != features.DropP0s {
+		log.Info("Agent features changed: stats %t -> %t, client drop P0s %t -> %t", old.Stats, features.Stats, old.DropP0s, features.DropP0s)
+	}
+	c.refreshedAgent.Store(&features)
+}
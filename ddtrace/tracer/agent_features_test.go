@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withAgentFeaturesRefresh sets how often the trace-agent's capabilities are
+// discovered again.
+func withAgentFeaturesRefresh(d time.Duration) StartOption {
+	return func(c *config) {
+		c.agentFeaturesRefresh = d
+	}
+}
+
+// newInfoServer returns an agent answering its /info requests with the
+// current value of info, or with an internal error when it is empty.
+func newInfoServer(t *testing.T, info *atomic.Value) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		body, _ := info.Load().(string)
+		if body == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestUpdateAgentFeatures(t *testing.T) {
+	t.Setenv("DD_TRACE_FEATURES", "discovery")
+	var info atomic.Value
+	info.Store(`{"endpoints":["/v0.4/traces"]}`)
+	srv := newInfoServer(t, &info)
+	cfg, err := newConfig(WithAgentAddr(strings.TrimPrefix(srv.URL, "http://")), WithAgentTimeout(2))
+	require.NoError(t, err)
+	assert.False(t, cfg.canComputeStats())
+	assert.False(t, cfg.agentFeatures().spanEventsAvailable)
+
+	t.Run("upgrade", func(t *testing.T) {
+		info.Store(`{"endpoints":["/v0.4/traces","/v0.6/stats","/v0.5/traces"],"client_drop_p0s":true,"span_events":true}`)
+		cfg.updateAgentFeatures()
+		assert.True(t, cfg.canComputeStats())
+		assert.True(t, cfg.canDropP0s())
+		assert.True(t, cfg.agentFeatures().spanEventsAvailable)
+		assert.True(t, cfg.agentFeatures().traceV05)
+		// the features discovered at startup are left untouched.
+		assert.False(t, cfg.agent.Stats)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		info.Store("")
+		cfg.updateAgentFeatures()
+		assert.True(t, cfg.canDropP0s())
+		assert.True(t, cfg.agentFeatures().spanEventsAvailable)
+	})
+
+	t.Run("downgrade", func(t *testing.T) {
+		info.Store(`{"endpoints":["/v0.4/traces"]}`)
+		cfg.updateAgentFeatures()
+		assert.False(t, cfg.canComputeStats())
+		assert.False(t, cfg.canDropP0s())
+		assert.False(t, cfg.agentFeatures().spanEventsAvailable)
+	})
+}
+
+func TestRefreshAgentFeatures(t *testing.T) {
+	var info atomic.Value
+	info.Store(`{"endpoints":["/v0.4/traces"]}`)
+	srv := newInfoServer(t, &info)
+	tracer, err := newTracer(
+		WithAgentAddr(strings.TrimPrefix(srv.URL, "http://")),
+		WithStatsComputation(true),
+		withNoopStats(),
+		withAgentFeaturesRefresh(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer tracer.Stop()
+	assert.False(t, tracer.TracerConf().CanComputeStats)
+
+	info.Store(`{"endpoints":["/v0.4/traces","/v0.6/stats"],"span_events":true}`)
+	assert.Eventually(t, func() bool {
+		return tracer.TracerConf().CanComputeStats
+	}, 5*time.Second, 10*time.Millisecond)
+	span := tracer.StartSpan("op")
+	assert.True(t, span.supportsEvents)
+	span.Finish()
+}
@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	// keyResumeAttempt holds the number of times the trace of a span was resumed
+	// from a checkpoint.
+	keyResumeAttempt = "resume.attempt"
+
+	// checkpointMaxTagsLen is the maximum length of the propagating tags persisted
+	// in a checkpoint. It is larger than the default header limit since checkpoints
+	// are not sent over the wire.
+	checkpointMaxTagsLen = 4096
+)
+
+// checkpointPropagator encodes span contexts persisted in checkpoints. It carries the
+// trace and span IDs, the sampling priority, the origin, the propagating tags and the
+// baggage of the span context.
+var checkpointPropagator = &propagator{&PropagatorConfig{
+	BaggagePrefix:    DefaultBaggageHeaderPrefix,
+	TraceHeader:      DefaultTraceIDHeader,
+	ParentHeader:     DefaultParentIDHeader,
+	PriorityHeader:   DefaultPriorityHeader,
+	MaxTagsHeaderLen: checkpointMaxTagsLen,
+}}
+
+// spanCheckpoint is the persisted state of a span.
+type spanCheckpoint struct {
+	// Context holds the propagated span context of the span.
+	Context TextMapCarrier `json:"context"`
+	// ParentID is the ID of the parent of the span, or 0 if it was a root span.
+	ParentID uint64 `json:"parent_id,string"`
+	// Attempt is the number of times the trace was resumed before the checkpoint was written.
+	Attempt int `json:"attempt"`
+}
+
+// WriteSpanCheckpoint persists the context of s to the file at path, including its
+// sampling priority, origin, propagating tags and baggage, so that its trace can be
+// continued with ResumeSpan by a later process, for example after a crash or restart
+// of a long-running job. The file is replaced atomically.
+func WriteSpanCheckpoint(path string, s *Span) error {
+	if s == nil {
+		return ErrInvalidSpanContext
+	}
+	cp := spanCheckpoint{Context: TextMapCarrier{}}
+	if err := checkpointPropagator.Inject(s.Context(), cp.Context); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	cp.ParentID = s.parentID
+	cp.Attempt = int(s.metrics[keyResumeAttempt])
+	s.mu.RUnlock()
+
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// ResumeSpan starts a span continuing the trace of the span persisted at path with
+// WriteSpanCheckpoint. The new span takes the place of the persisted one: it has the
+// same parent, or is the root of the same trace, keeps its sampling decision and
+// propagating tags, and links to it as the previous attempt. The number of attempts
+// is reported in the "resume.attempt" tag.
+//
+// If no checkpoint exists at path, a span is started as usual and the returned error
+// is nil; any other error reading the checkpoint is returned along with that span.
+func ResumeSpan(path, operationName string, opts ...StartSpanOption) (*Span, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return StartSpan(operationName, opts...), nil
+	}
+	if err != nil {
+		return StartSpan(operationName, opts...), err
+	}
+	var cp spanCheckpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return StartSpan(operationName, opts...), fmt.Errorf("invalid span checkpoint %q: %w", path, err)
+	}
+	ctx, err := checkpointPropagator.Extract(cp.Context)
+	if err != nil {
+		return StartSpan(operationName, opts...), fmt.Errorf("invalid span checkpoint %q: %w", path, err)
+	}
+	attempt := cp.Attempt + 1
+	link := SpanLink{
+		TraceID:     ctx.traceID.Lower(),
+		TraceIDHigh: ctx.traceID.Upper(),
+		SpanID:      ctx.spanID,
+		Attributes: map[string]string{
+			"reason":  "resumed",
+			"attempt": strconv.Itoa(cp.Attempt),
+		},
+	}
+	// the resumed span replaces the persisted one as a child of its parent
+	ctx.spanID = cp.ParentID
+	opts = append([]StartSpanOption{ChildOf(ctx), WithSpanLinks([]SpanLink{link})}, opts...)
+	opts = append(opts, Tag(keyResumeAttempt, attempt))
+	return StartSpan(operationName, opts...), nil
+}
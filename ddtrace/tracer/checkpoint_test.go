@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanCheckpoint(t *testing.T) {
+	_, _, _, stop, err := startTestTracer(t)
+	require.NoError(t, err)
+	defer stop()
+
+	t.Run("no-checkpoint", func(t *testing.T) {
+		span, err := ResumeSpan(filepath.Join(t.TempDir(), "missing.json"), "job")
+		require.NoError(t, err)
+		defer span.Finish()
+		assert.Equal(t, uint64(0), span.parentID)
+		assert.Empty(t, span.spanLinks)
+		assert.NotContains(t, span.metrics, keyResumeAttempt)
+	})
+
+	t.Run("root", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "job.json")
+		first := StartSpan("job")
+		defer first.Finish()
+		first.SetTag(ext.ManualKeep, true)
+		first.SetBaggageItem("tenant", "acme")
+		require.NoError(t, WriteSpanCheckpoint(path, first))
+
+		resumed, err := ResumeSpan(path, "job")
+		require.NoError(t, err)
+		defer resumed.Finish()
+
+		assert.Equal(t, first.Context().TraceID(), resumed.Context().TraceID())
+		assert.NotEqual(t, first.spanID, resumed.spanID)
+		assert.Equal(t, uint64(0), resumed.parentID)
+		p, ok := resumed.Context().SamplingPriority()
+		assert.True(t, ok)
+		assert.Equal(t, ext.PriorityUserKeep, p)
+		assert.Equal(t, "acme", resumed.BaggageItem("tenant"))
+		assert.Equal(t, float64(1), resumed.metrics[keyResumeAttempt])
+		require.Len(t, resumed.spanLinks, 1)
+		link := resumed.spanLinks[0]
+		assert.Equal(t, first.spanID, link.SpanID)
+		assert.Equal(t, first.traceID, link.TraceID)
+		assert.Equal(t, "resumed", link.Attributes["reason"])
+		assert.Equal(t, "0", link.Attributes["attempt"])
+
+		// resuming again chains the attempts
+		require.NoError(t, WriteSpanCheckpoint(path, resumed))
+		again, err := ResumeSpan(path, "job")
+		require.NoError(t, err)
+		defer again.Finish()
+		assert.Equal(t, first.Context().TraceID(), again.Context().TraceID())
+		assert.Equal(t, float64(2), again.metrics[keyResumeAttempt])
+		require.Len(t, again.spanLinks, 1)
+		assert.Equal(t, resumed.spanID, again.spanLinks[0].SpanID)
+	})
+
+	t.Run("child", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "job.json")
+		parent := StartSpan("parent")
+		defer parent.Finish()
+		child := parent.StartChild("job")
+		require.NoError(t, WriteSpanCheckpoint(path, child))
+
+		resumed, err := ResumeSpan(path, "job")
+		require.NoError(t, err)
+		defer resumed.Finish()
+		assert.Equal(t, parent.spanID, resumed.parentID)
+		assert.Equal(t, parent.traceID, resumed.traceID)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "job.json")
+		require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+		span, err := ResumeSpan(path, "job")
+		assert.Error(t, err)
+		require.NotNil(t, span)
+		span.Finish()
+	})
+}
@@ -69,6 +69,9 @@ func newCiVisibilityTransport(config *config) *ciVisibilityTransport {
 	if eid := internal.EntityID(); eid != "" {
 		defaultHeaders["Datadog-Entity-ID"] = eid
 	}
+	if extEnv := internal.ExternalEnvironment(); extEnv != "" {
+		defaultHeaders["Datadog-External-Env"] = extEnv
+	}
 
 	// Determine if agentless mode is enabled through an environment variable.
 	agentlessEnabled := internal.BoolEnv(constants.CIVisibilityAgentlessEnabledEnvironmentVariable, false)
@@ -76,12 +79,15 @@ func newCiVisibilityTransport(config *config) *ciVisibilityTransport {
 	testCycleURL := ""
 	if agentlessEnabled {
 		// Agentless mode is enabled.
-		APIKeyValue := os.Getenv(constants.APIKeyEnvironmentVariable)
-		if APIKeyValue == "" {
-			log.Error("An API key is required for agentless mode. Use the DD_API_KEY env variable to set it")
-		}
+		// The API key is set when sending the payloads if it is resolved with WithAPIKeyResolver.
+		if config.apiKeyResolver == nil {
+			APIKeyValue := os.Getenv(constants.APIKeyEnvironmentVariable)
+			if APIKeyValue == "" {
+				log.Error("An API key is required for agentless mode. Use the DD_API_KEY env variable or WithAPIKeyResolver to set it")
+			}
 
-		defaultHeaders["dd-api-key"] = APIKeyValue
+			defaultHeaders["dd-api-key"] = APIKeyValue
+		}
 
 		// Check for a custom agentless URL.
 		agentlessURL := ""
@@ -165,7 +171,13 @@ func (t *ciVisibilityTransport) send(p *payload) (body io.ReadCloser, err error)
 
 	log.Debug("ciVisibilityTransport: sending transport request: %v bytes", buffer.Len())
 	startTime := time.Now()
-	response, err := t.config.httpClient.Do(req)
+	var response *http.Response
+	if t.agentless && t.config.apiKeyResolver != nil {
+		// The payload is sent again with the key resolved again if it is rejected.
+		response, err = t.config.apiKeyResolver.Do(t.config.httpClient, req)
+	} else {
+		response, err = t.config.httpClient.Do(req)
+	}
 	telemetry.EndpointPayloadRequestsMs(telemetry.TestCycleEndpointType, float64(time.Since(startTime).Milliseconds()))
 	if err != nil {
 		return nil, err
@@ -8,6 +8,7 @@ package tracer
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -110,3 +111,34 @@ func runTransportTest(t *testing.T, agentless, shouldSetAPIKey bool) {
 	assert.Equal(hits, len(testCases))
 	assert.Equal(remainingEvents, 0)
 }
+
+func TestCiVisibilityTransportAPIKeyResolver(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("dd-api-key"))
+		if r.Header.Get("dd-api-key") != "rotated-key" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv(constants.CIVisibilityAgentlessEnabledEnvironmentVariable, "1")
+	t.Setenv(constants.CIVisibilityAgentlessURLEnvironmentVariable, srv.URL)
+
+	resolved := []string{"revoked-key", "rotated-key"}
+	c := config{
+		ciVisibilityEnabled: true,
+		httpClient:          defaultHTTPClient(0),
+	}
+	WithAPIKeyResolver(func(context.Context) (string, error) {
+		key := resolved[0]
+		resolved = resolved[1:]
+		return key, nil
+	})(&c)
+	transport := newCiVisibilityTransport(&c)
+
+	p := newCiVisibilityPayload()
+	assert.NoError(t, p.push(getCiVisibilityEvent(getTestTrace(1, 1)[0][0])))
+	_, err := transport.send(p.payload)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"revoked-key", "rotated-key"}, keys)
+}
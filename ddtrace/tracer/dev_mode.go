@@ -0,0 +1,277 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// devModeWriter specifies the output target of the devTraceWriter; replaced in tests.
+var devModeWriter io.Writer = os.Stdout
+
+// devModeConfig holds the configuration of the dev mode. See WithDevMode.
+type devModeConfig struct {
+	// json reports whether the spans are printed as JSON lines rather than trees.
+	json bool
+
+	// agent reports whether the traces are sent to the agent too.
+	agent bool
+}
+
+// DevModeOption configures the dev mode of the tracer. See WithDevMode.
+type DevModeOption func(*devModeConfig)
+
+// DevModeJSON prints the spans as JSON lines, one per span, holding the
+// fields returned by Span.AsMap, rather than as trees.
+func DevModeJSON() DevModeOption {
+	return func(c *devModeConfig) {
+		c.json = true
+	}
+}
+
+// DevModeWithAgent sends the traces to the agent too.
+func DevModeWithAgent() DevModeOption {
+	return func(c *devModeConfig) {
+		c.agent = true
+	}
+}
+
+// WithDevMode prints the finished traces to the standard output, to get
+// immediate feedback when developing locally without an agent. By default, the
+// traces are printed as trees of spans, colored when the standard output is a
+// terminal and NO_COLOR is unset, and aren't sent to the agent. Use
+// DevModeJSON to print them as JSON lines instead, and DevModeWithAgent to
+// send them to the agent too.
+//
+// Dev mode is not meant to be used in production: the traces are printed as
+// soon as they finish, by the goroutine flushing them.
+func WithDevMode(opts ...DevModeOption) StartOption {
+	return func(c *config) {
+		c.devMode = &devModeConfig{}
+		for _, fn := range opts {
+			fn(c.devMode)
+		}
+	}
+}
+
+// devModeHiddenTags lists the tags set on all the spans, which aren't printed
+// in the trees, along with the internal ones, prefixed with an underscore, and
+// the error ones, the error message being printed with the span.
+var devModeHiddenTags = map[string]bool{
+	ext.RuntimeID: true,
+	ext.Pid:       true,
+	"language":    true,
+
+	globalinternal.RuntimeEnvGOMAXPROCS:        true,
+	globalinternal.RuntimeEnvGOGC:              true,
+	globalinternal.RuntimeEnvGOMEMLIMIT:        true,
+	globalinternal.RuntimeEnvCgroupCPUQuota:    true,
+	globalinternal.RuntimeEnvCgroupCPUPeriod:   true,
+	globalinternal.RuntimeEnvCgroupMemoryLimit: true,
+}
+
+// ANSI escape codes used to color the trees.
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+)
+
+var _ traceWriter = (*devTraceWriter)(nil)
+
+// devTraceWriter prints the traces to devModeWriter, and passes them to the
+// next writer, if any.
+type devTraceWriter struct {
+	cfg   *devModeConfig
+	w     io.Writer
+	color bool
+	next  traceWriter
+	buf   bytes.Buffer
+}
+
+// newDevTraceWriter returns a writer printing the traces as configured by
+// cfg, and passing them to next when it is not nil.
+func newDevTraceWriter(cfg *devModeConfig, next traceWriter) *devTraceWriter {
+	return &devTraceWriter{
+		cfg:   cfg,
+		w:     devModeWriter,
+		color: !cfg.json && isTerminal(devModeWriter) && os.Getenv("NO_COLOR") == "",
+		next:  next,
+	}
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func (h *devTraceWriter) add(trace []*Span) {
+	h.buf.Reset()
+	if h.cfg.json {
+		h.writeJSON(trace)
+	} else {
+		h.writeTree(trace)
+	}
+	if _, err := h.w.Write(h.buf.Bytes()); err != nil {
+		log.Error("Dev mode: error printing trace: %v", err)
+	}
+	if h.next != nil {
+		h.next.add(trace)
+	}
+}
+
+func (h *devTraceWriter) flush() {
+	if h.next != nil {
+		h.next.flush()
+	}
+}
+
+func (h *devTraceWriter) stop() {
+	if h.next != nil {
+		h.next.stop()
+	}
+}
+
+// flushed implements flushNotifier.
+func (h *devTraceWriter) flushed() <-chan struct{} {
+	if w, ok := h.next.(flushNotifier); ok {
+		return w.flushed()
+	}
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+// writeJSON writes the spans of trace as JSON lines.
+func (h *devTraceWriter) writeJSON(trace []*Span) {
+	enc := json.NewEncoder(&h.buf)
+	for _, s := range trace {
+		if err := enc.Encode(s.AsMap()); err != nil {
+			log.Error("Dev mode: error encoding span: %v", err)
+		}
+	}
+}
+
+// writeTree writes the spans of trace as trees, the children of each span
+// being sorted by start time.
+func (h *devTraceWriter) writeTree(trace []*Span) {
+	if len(trace) == 0 {
+		return
+	}
+	ids := make(map[uint64]bool, len(trace))
+	for _, s := range trace {
+		ids[s.spanID] = true
+	}
+	var roots []*Span
+	children := make(map[uint64][]*Span, len(trace))
+	for _, s := range trace {
+		if s.parentID == 0 || !ids[s.parentID] {
+			roots = append(roots, s)
+		} else {
+			children[s.parentID] = append(children[s.parentID], s)
+		}
+	}
+	byStart := func(a, b *Span) int { return cmp.Compare(a.start, b.start) }
+	slices.SortFunc(roots, byStart)
+	for _, c := range children {
+		slices.SortFunc(c, byStart)
+	}
+
+	traceID := strconv.FormatUint(trace[0].traceID, 10)
+	if ctx := trace[0].context; ctx != nil {
+		traceID = ctx.traceID.HexEncoded()
+	}
+	h.style(ansiBold, fmt.Sprintf("trace %s", traceID))
+	fmt.Fprintf(&h.buf, " (%d spans)\n", len(trace))
+	var writeChildren func(s *Span, prefix string)
+	writeChildren = func(s *Span, prefix string) {
+		c := children[s.spanID]
+		for i, child := range c {
+			branch, indent := "├─ ", "│  "
+			if i == len(c)-1 {
+				branch, indent = "└─ ", "   "
+			}
+			h.buf.WriteString(prefix + branch)
+			h.writeSpan(child, prefix+indent, len(children[child.spanID]) > 0)
+			writeChildren(child, prefix+indent)
+		}
+	}
+	for _, root := range roots {
+		h.writeSpan(root, "", len(children[root.spanID]) > 0)
+		writeChildren(root, "")
+	}
+}
+
+// writeSpan writes s on a line, and its tags on the following one, prefixed
+// with indent and, when s has children, with the start of their branches.
+func (h *devTraceWriter) writeSpan(s *Span, indent string, hasChildren bool) {
+	h.style(ansiCyan, s.service)
+	h.buf.WriteString(" ")
+	h.style(ansiBold, s.name)
+	if s.resource != "" && s.resource != s.name {
+		h.buf.WriteString(" " + s.resource)
+	}
+	h.buf.WriteString(" ")
+	h.style(ansiDim, time.Duration(s.duration).String())
+	if s.error != 0 {
+		h.buf.WriteString(" ")
+		h.style(ansiRed, cmp.Or(s.meta[ext.ErrorMsg], "error"))
+	}
+	h.buf.WriteString("\n")
+
+	tags := make([]string, 0, len(s.meta)+len(s.metrics))
+	for k, v := range s.meta {
+		if !strings.HasPrefix(k, "_") && !devModeHiddenTags[k] && !strings.HasPrefix(k, "error.") {
+			tags = append(tags, k+"="+v)
+		}
+	}
+	for k, v := range s.metrics {
+		if !strings.HasPrefix(k, "_") && !devModeHiddenTags[k] {
+			tags = append(tags, k+"="+strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	}
+	if len(tags) == 0 {
+		return
+	}
+	slices.Sort(tags)
+	if hasChildren {
+		indent += "│  "
+	} else {
+		indent += "   "
+	}
+	h.buf.WriteString(indent)
+	h.style(ansiDim, strings.Join(tags, " "))
+	h.buf.WriteString("\n")
+}
+
+// style writes s, styled with the ANSI escape code when colors are enabled.
+func (h *devTraceWriter) style(code, s string) {
+	if !h.color {
+		h.buf.WriteString(s)
+		return
+	}
+	h.buf.WriteString(code + s + ansiReset)
+}
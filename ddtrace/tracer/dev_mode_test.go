@@ -0,0 +1,92 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startDevModeTracer starts a tracer in dev mode, printing the traces to the
+// returned buffer.
+func startDevModeTracer(t *testing.T, opts ...DevModeOption) (*tracer, *dummyTransport, *bytes.Buffer) {
+	var buf bytes.Buffer
+	old := devModeWriter
+	devModeWriter = &buf
+	t.Cleanup(func() { devModeWriter = old })
+	transport := newDummyTransport()
+	tracer, err := newTracer(WithDevMode(opts...), withTransport(transport), withNoopStats(), WithService("web"), WithLogStartup(false))
+	require.NoError(t, err)
+	setGlobalTracer(tracer)
+	t.Cleanup(func() {
+		setGlobalTracer(&NoopTracer{})
+		tracer.Stop()
+	})
+	return tracer, transport, &buf
+}
+
+// recordDevModeTrace records a trace of three spans with fixed durations.
+func recordDevModeTrace(tracer *tracer) {
+	start := time.Now()
+	root := tracer.StartSpan("http.request", ResourceName("GET /users"), Tag("http.method", "GET"), StartTime(start))
+	child := tracer.StartSpan("db.query", ChildOf(root.Context()), ServiceName("db"), Tag("rows", 3), StartTime(start.Add(time.Millisecond)))
+	grandchild := tracer.StartSpan("db.connect", ChildOf(child.Context()), ServiceName("db"), StartTime(start.Add(time.Millisecond)))
+	grandchild.Finish(FinishTime(start.Add(2*time.Millisecond)), WithError(errors.New("timeout")))
+	child.Finish(FinishTime(start.Add(3 * time.Millisecond)))
+	root.Finish(FinishTime(start.Add(10 * time.Millisecond)))
+}
+
+func TestDevModeTree(t *testing.T) {
+	tracer, _, buf := startDevModeTracer(t)
+	assert.True(t, tracer.config.agentDisabled())
+	recordDevModeTrace(tracer)
+	tracer.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 6, buf.String())
+	assert.Regexp(t, `^trace [0-9a-f]{32} \(3 spans\)$`, lines[0])
+	assert.Equal(t, "web http.request GET /users 10ms", lines[1])
+	assert.Equal(t, "│  http.method=GET", lines[2])
+	assert.Equal(t, "└─ db db.query 2ms", lines[3])
+	assert.Equal(t, "   │  rows=3", lines[4])
+	assert.Equal(t, "   └─ db db.connect 1ms timeout", lines[5])
+}
+
+func TestDevModeJSON(t *testing.T) {
+	tracer, transport, buf := startDevModeTracer(t, DevModeJSON())
+	recordDevModeTrace(tracer)
+	tracer.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3, buf.String())
+	names := make([]string, 0, len(lines))
+	for _, l := range lines {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(l), &m))
+		names = append(names, m[ext.SpanName].(string))
+	}
+	assert.ElementsMatch(t, []string{"http.request", "db.query", "db.connect"}, names)
+	assert.Zero(t, transport.Len())
+}
+
+func TestDevModeWithAgent(t *testing.T) {
+	tracer, transport, buf := startDevModeTracer(t, DevModeWithAgent())
+	assert.False(t, tracer.config.agentDisabled())
+	recordDevModeTrace(tracer)
+	tracer.Flush()
+
+	assert.Contains(t, buf.String(), "web http.request GET /users 10ms")
+	assert.Equal(t, 1, transport.Len())
+}
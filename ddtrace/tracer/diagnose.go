@@ -0,0 +1,278 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal"
+)
+
+// DiagnosticStatus is the outcome of a diagnostic check.
+type DiagnosticStatus string
+
+const (
+	// DiagnosticOK is the status of the checks which passed.
+	DiagnosticOK DiagnosticStatus = "ok"
+	// DiagnosticWarning is the status of the checks which found a problem
+	// which doesn't prevent the tracer from working, but may be unexpected.
+	DiagnosticWarning DiagnosticStatus = "warning"
+	// DiagnosticError is the status of the checks which found a problem
+	// preventing the tracer from working as configured.
+	DiagnosticError DiagnosticStatus = "error"
+)
+
+// DiagnosticCheck is the result of a diagnostic check.
+type DiagnosticCheck struct {
+	// Name identifies the check, e.g. "agent http://localhost:8126" or
+	// "env DD_TRACE_SAMPLE_RATE".
+	Name    string           `json:"name"`
+	Status  DiagnosticStatus `json:"status"`
+	Message string           `json:"message"`
+}
+
+// DiagnosticReport is the result of Diagnose.
+type DiagnosticReport struct {
+	// AgentURL is the URL of the agent the tracer would send its data to.
+	AgentURL string            `json:"agent_url"`
+	Checks   []DiagnosticCheck `json:"checks"`
+}
+
+// OK reports whether none of the checks of r failed with DiagnosticError.
+func (r *DiagnosticReport) OK() bool {
+	for _, c := range r.Checks {
+		if c.Status == DiagnosticError {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *DiagnosticReport) add(name string, status DiagnosticStatus, format string, args ...any) {
+	r.Checks = append(r.Checks, DiagnosticCheck{Name: name, Status: status, Message: fmt.Sprintf(format, args...)})
+}
+
+// diagnoseMaxClockSkew is the clock skew with the agent above which Diagnose
+// reports a warning. The agent's clock is known with a one second precision.
+const diagnoseMaxClockSkew = 2 * time.Second
+
+// diagnoseTimeout is the timeout of each of the requests sent by Diagnose.
+const diagnoseTimeout = 5 * time.Second
+
+// diagnoseBoolEnvs are the boolean environment variables validated by Diagnose.
+var diagnoseBoolEnvs = []string{
+	"DD_TRACE_ENABLED",
+	"DD_TRACE_DEBUG",
+	"DD_TRACE_STARTUP_LOGS",
+	"DD_TRACE_ANALYTICS_ENABLED",
+	"DD_TRACE_PARTIAL_FLUSH_ENABLED",
+	"DD_TRACE_128_BIT_TRACEID_GENERATION_ENABLED",
+	"DD_TRACE_STATS_COMPUTATION_ENABLED",
+	"DD_TRACE_CLIENT_IP_ENABLED",
+	"DD_RUNTIME_METRICS_ENABLED",
+	"DD_DATA_STREAMS_ENABLED",
+	"DD_APPSEC_ENABLED",
+	"DD_PROFILING_ENABLED",
+	"DD_EXCEPTION_REPLAY_ENABLED",
+	"DD_DYNAMIC_INSTRUMENTATION_ENABLED",
+	"DD_INSTRUMENTATION_TELEMETRY_ENABLED",
+}
+
+// Diagnose checks the configuration of the tracer from the environment, and
+// whether it can send its data to the agent, without starting it. It reports:
+//
+//   - whether the agent is reachable on each of the endpoints the tracer may
+//     use, i.e. the one resolved from DD_TRACE_AGENT_URL, DD_AGENT_HOST and
+//     DD_TRACE_AGENT_PORT, the default Unix Domain Socket and localhost:8126;
+//   - the environment variables whose values are invalid, and thus ignored;
+//   - the clock skew with the agent, which would make the traces appear
+//     shifted in time;
+//   - whether the sampling rules of DD_TRACE_SAMPLING_RULES and
+//     DD_SPAN_SAMPLING_RULES can be parsed.
+//
+// It is meant to troubleshoot a deployment, for instance from the
+// ddtrace/doctor command, and each of its requests is bounded by ctx.
+func Diagnose(ctx context.Context) DiagnosticReport {
+	r := DiagnosticReport{Checks: []DiagnosticCheck{}}
+	diagnoseEnv(&r)
+	diagnoseSamplingRules(&r)
+
+	resolved := internal.AgentURLFromEnv()
+	r.AgentURL = resolved.String()
+	candidates := []*url.URL{resolved}
+	if _, err := os.Stat(internal.DefaultTraceAgentUDSPath); err == nil {
+		candidates = append(candidates, &url.URL{Scheme: "unix", Path: internal.DefaultTraceAgentUDSPath})
+	}
+	candidates = append(candidates, &url.URL{Scheme: "http", Host: internal.DefaultAgentHostname + ":" + internal.DefaultTraceAgentPort})
+	seen := make(map[string]bool, len(candidates))
+	for i, u := range candidates {
+		if seen[u.String()] {
+			continue
+		}
+		seen[u.String()] = true
+		date, err := diagnoseAgent(ctx, &r, u)
+		if i != 0 {
+			continue
+		}
+		// The clock skew is only relevant for the agent the tracer would use.
+		switch {
+		case err != nil:
+			r.add("clock skew", DiagnosticWarning, "could not be checked as the agent is unreachable")
+		case date.IsZero():
+			r.add("clock skew", DiagnosticWarning, "could not be checked as the agent didn't return its date")
+		default:
+			diagnoseClockSkew(&r, date)
+		}
+	}
+	return r
+}
+
+// agentDate is the date of the agent, and the local time it was observed at.
+type agentDate struct {
+	remote, local time.Time
+}
+
+func (d agentDate) IsZero() bool { return d.remote.IsZero() }
+
+// diagnoseAgent checks whether the agent is reachable at u, and returns the
+// date it responded with.
+func diagnoseAgent(ctx context.Context, r *DiagnosticReport, u *url.URL) (agentDate, error) {
+	name := "agent " + u.String()
+	client, base := defaultHTTPClient(diagnoseTimeout), u
+	if u.Scheme == "unix" {
+		client, base = udsClient(u.Path, diagnoseTimeout), internal.UnixDataSocketURL(u.Path)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String()+"/info", nil)
+	if err != nil {
+		r.add(name, DiagnosticError, "invalid agent URL: %v", err)
+		return agentDate{}, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		r.add(name, DiagnosticError, "the agent is unreachable: %v", err)
+		return agentDate{}, err
+	}
+	defer resp.Body.Close()
+	// The agent responded somewhere between the request and the response.
+	local := start.Add(time.Since(start) / 2)
+	var date agentDate
+	if t, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		date = agentDate{remote: t, local: local}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// The agent is older than 7.28.0, its features can't be discovered.
+		r.add(name, DiagnosticWarning, "the agent is reachable but too old to report its features, upgrade it to 7.28.0 or later")
+		return date, nil
+	default:
+		r.add(name, DiagnosticError, "the agent responded with status %s", resp.Status)
+		return date, nil
+	}
+	var info struct {
+		Version   string   `json:"version"`
+		Endpoints []string `json:"endpoints"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		r.add(name, DiagnosticWarning, "the agent is reachable but its features could not be decoded: %v", err)
+		return date, nil
+	}
+	for _, e := range info.Endpoints {
+		if e == "/v0.4/traces" {
+			r.add(name, DiagnosticOK, "the agent %s is reachable", info.Version)
+			return date, nil
+		}
+	}
+	r.add(name, DiagnosticError, "the agent %s is reachable but doesn't accept traces", info.Version)
+	return date, nil
+}
+
+// diagnoseClockSkew checks the skew between the local clock and the agent's.
+func diagnoseClockSkew(r *DiagnosticReport, d agentDate) {
+	// The agent's date is truncated to the second, so it is half a second
+	// earlier than its actual time on average.
+	skew := d.remote.Add(500 * time.Millisecond).Sub(d.local)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > diagnoseMaxClockSkew {
+		r.add("clock skew", DiagnosticWarning, "the local clock is %s off the agent's, traces will appear shifted in time", skew.Round(time.Second))
+		return
+	}
+	r.add("clock skew", DiagnosticOK, "the local clock is in sync with the agent's")
+}
+
+// diagnoseEnv validates the environment variables whose invalid values are
+// ignored by the tracer.
+func diagnoseEnv(r *DiagnosticReport) {
+	n := len(r.Checks)
+	for _, key := range diagnoseBoolEnvs {
+		if v, ok := os.LookupEnv(key); ok {
+			if _, err := strconv.ParseBool(v); err != nil {
+				r.add("env "+key, DiagnosticError, "%q is not a boolean, the default value is used", v)
+			}
+		}
+	}
+	if v, ok := os.LookupEnv("DD_TRACE_ENABLED"); ok {
+		if enabled, err := strconv.ParseBool(v); err == nil && !enabled {
+			r.add("env DD_TRACE_ENABLED", DiagnosticWarning, "tracing is disabled")
+		}
+	}
+	if v := os.Getenv("DD_TRACE_SAMPLE_RATE"); v != "" {
+		if rate, err := strconv.ParseFloat(v, 64); err != nil || rate < 0 || rate > 1 {
+			r.add("env DD_TRACE_SAMPLE_RATE", DiagnosticError, "%q is not a number between 0.0 and 1.0, it is ignored", v)
+		}
+	}
+	if v := os.Getenv("DD_TRACE_RATE_LIMIT"); v != "" {
+		if limit, err := strconv.ParseFloat(v, 64); err != nil || limit < 0 {
+			r.add("env DD_TRACE_RATE_LIMIT", DiagnosticError, "%q is not a positive number, the default limit is used", v)
+		}
+	}
+	for _, key := range []string{"DD_TRACE_AGENT_PORT", "DD_DOGSTATSD_PORT"} {
+		if v := os.Getenv(key); v != "" {
+			if port, err := strconv.Atoi(v); err != nil || port <= 0 || port > 65535 {
+				r.add("env "+key, DiagnosticError, "%q is not a valid port", v)
+			}
+		}
+	}
+	if v := os.Getenv("DD_TRACE_AGENT_URL"); v != "" {
+		u, err := url.Parse(v)
+		switch {
+		case err != nil:
+			r.add("env DD_TRACE_AGENT_URL", DiagnosticError, "%q is not a valid URL, it is ignored: %v", v, err)
+		case u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "unix":
+			r.add("env DD_TRACE_AGENT_URL", DiagnosticError, "unsupported scheme %q, it must be one of http, https or unix, the URL is ignored", u.Scheme)
+		case os.Getenv("DD_AGENT_HOST") != "" || os.Getenv("DD_TRACE_AGENT_PORT") != "":
+			r.add("env DD_TRACE_AGENT_URL", DiagnosticWarning, "DD_AGENT_HOST and DD_TRACE_AGENT_PORT are ignored as DD_TRACE_AGENT_URL is set")
+		}
+	}
+	if os.Getenv("DD_SERVICE") == "" {
+		r.add("env DD_SERVICE", DiagnosticWarning, "the service isn't set, the name of the program is used")
+	}
+	if len(r.Checks) == n {
+		r.add("env", DiagnosticOK, "the environment variables are valid")
+	}
+}
+
+// diagnoseSamplingRules checks whether the sampling rules of the environment
+// can be parsed.
+func diagnoseSamplingRules(r *DiagnosticReport) {
+	trace, span, err := samplingRulesFromEnv()
+	if err != nil {
+		r.add("sampling rules", DiagnosticError, "%v", err)
+		return
+	}
+	r.add("sampling rules", DiagnosticOK, "%d trace sampling rules and %d span sampling rules", len(trace), len(span))
+}
@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findCheck(t *testing.T, r DiagnosticReport, name string) DiagnosticCheck {
+	t.Helper()
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	require.Failf(t, "check not found", "no check named %q in %v", name, r.Checks)
+	return DiagnosticCheck{}
+}
+
+func TestDiagnose(t *testing.T) {
+	// Don't probe the default Unix Domain Socket of the host.
+	defer func(path string) { internal.DefaultTraceAgentUDSPath = path }(internal.DefaultTraceAgentUDSPath)
+	internal.DefaultTraceAgentUDSPath = "/tmp/dd-trace-go-diagnose-test.sock"
+
+	var date time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !date.IsZero() {
+			w.Header()["Date"] = []string{date.UTC().Format(http.TimeFormat)}
+		}
+		w.Write([]byte(`{"version":"7.60.0","endpoints":["/v0.4/traces","/v0.6/stats"]}`))
+	}))
+	defer srv.Close()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Setenv("DD_TRACE_AGENT_URL", srv.URL)
+		t.Setenv("DD_SERVICE", "svc")
+		t.Setenv("DD_TRACE_SAMPLING_RULES", `[{"service": "svc", "sample_rate": 0.5}]`)
+		date = time.Time{}
+
+		r := Diagnose(context.Background())
+		assert.Equal(t, srv.URL, r.AgentURL)
+		assert.Equal(t, DiagnosticOK, findCheck(t, r, "env").Status)
+		assert.Equal(t, DiagnosticOK, findCheck(t, r, "sampling rules").Status)
+		agent := findCheck(t, r, "agent "+srv.URL)
+		assert.Equal(t, DiagnosticOK, agent.Status)
+		assert.Contains(t, agent.Message, "7.60.0")
+		assert.Equal(t, DiagnosticOK, findCheck(t, r, "clock skew").Status)
+	})
+
+	t.Run("clock-skew", func(t *testing.T) {
+		t.Setenv("DD_TRACE_AGENT_URL", srv.URL)
+		date = time.Now().Add(-time.Minute)
+
+		r := Diagnose(context.Background())
+		skew := findCheck(t, r, "clock skew")
+		assert.Equal(t, DiagnosticWarning, skew.Status)
+		assert.Contains(t, skew.Message, "off the agent's")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("DD_TRACE_AGENT_URL", "http://127.0.0.1:1")
+		t.Setenv("DD_TRACE_SAMPLE_RATE", "2")
+		t.Setenv("DD_TRACE_DEBUG", "yes")
+		t.Setenv("DD_TRACE_AGENT_PORT", "port")
+		t.Setenv("DD_TRACE_SAMPLING_RULES", `[{"service": "svc", "sample_rate": "high"}]`)
+
+		r := Diagnose(context.Background())
+		assert.False(t, r.OK())
+		assert.Equal(t, DiagnosticError, findCheck(t, r, "env DD_TRACE_SAMPLE_RATE").Status)
+		assert.Equal(t, DiagnosticError, findCheck(t, r, "env DD_TRACE_DEBUG").Status)
+		assert.Equal(t, DiagnosticError, findCheck(t, r, "env DD_TRACE_AGENT_PORT").Status)
+		assert.Equal(t, DiagnosticWarning, findCheck(t, r, "env DD_TRACE_AGENT_URL").Status)
+		assert.Equal(t, DiagnosticWarning, findCheck(t, r, "env DD_SERVICE").Status)
+		assert.Equal(t, DiagnosticError, findCheck(t, r, "sampling rules").Status)
+		assert.Equal(t, DiagnosticError, findCheck(t, r, "agent http://127.0.0.1:1").Status)
+		assert.Equal(t, DiagnosticWarning, findCheck(t, r, "clock skew").Status)
+	})
+}
@@ -55,8 +55,12 @@
 // and "?" character matches exactly one of any character.
 // The "sample_rate" field is optional, and if not specified, defaults to "1.0", sampling 100% of the spans.
 // The "max_per_second" field is optional, and if not specified, defaults to 0, keeping all the previously sampled spans.
+// Instead of glob patterns, the "service_re", "name_re" and "resource_re" fields can hold regular
+// expressions matching these fields. Glob patterns ignore case and regular expressions don't,
+// unless the "case_sensitive" field is set to true or false respectively.
 //
 //	export DD_TRACE_SAMPLING_RULES='[{"name": "web.request", "sample_rate": 1.0}]'
+//	export DD_TRACE_SAMPLING_RULES='[{"resource_re": "^GET /api/v[0-9]+/users", "sample_rate": 0.1}]'
 //	export DD_SPAN_SAMPLING_RULES='[{"service":"test.?","name": "web.*", "sample_rate": 1.0, "max_per_second":100}]'
 //
 // To create spans, use the functions StartSpan and StartSpanFromContext. Both accept
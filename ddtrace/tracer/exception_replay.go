@@ -0,0 +1,312 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+const (
+	// envExceptionReplayEnabled is the env var enabling the capture of variables with
+	// CaptureVariables.
+	envExceptionReplayEnabled = "DD_EXCEPTION_REPLAY_ENABLED"
+	// envRedactedIdentifiers is the env var holding the comma-separated identifiers whose
+	// values are redacted, in addition to the default ones.
+	envRedactedIdentifiers = "DD_DYNAMIC_INSTRUMENTATION_REDACTED_IDENTIFIERS"
+
+	// keyErrorSnapshot holds the variables captured at the frames of an error, as JSON.
+	keyErrorSnapshot = "_dd.debug.error.snapshot"
+)
+
+// Bounds of the captured values, the same as the Dynamic Instrumentation ones.
+const (
+	snapshotMaxDepth          = 3
+	snapshotMaxCollectionSize = 100
+	snapshotMaxFieldCount     = 20
+	snapshotMaxStringLength   = 255
+	// snapshotMaxFrames is the maximum number of frames captured for an error.
+	snapshotMaxFrames = 10
+)
+
+// defaultRedactedIdentifiers are the normalized identifiers whose values are never captured.
+var defaultRedactedIdentifiers = []string{
+	"2fa", "accesstoken", "aiohttpsession", "apikey", "apisecret", "apisignature", "applicationkey",
+	"auth", "authorization", "authtoken", "ccnumber", "certificatepin", "cipher", "clientid",
+	"clientsecret", "connectionstring", "connectsid", "cookie", "credentials", "creditcard", "csrf",
+	"csrftoken", "cvv", "databaseurl", "dburl", "encryptionkey", "encryptionkeyid", "geolocation",
+	"gpgkey", "ipaddress", "jti", "jwt", "licensekey", "masterkey", "mysqlpwd", "nonce", "oauth",
+	"oauthtoken", "otp", "passhash", "passwd", "password", "passwordb", "pemfile", "pgpkey",
+	"phpsessid", "pin", "pincode", "pkcs8", "privatekey", "publickey", "pwd", "recaptchakey",
+	"refreshtoken", "routingnumber", "salt", "secret", "secretkey", "secrettoken", "securityanswer",
+	"securitycode", "securityquestion", "serviceaccountcredentials", "session", "sessionid",
+	"sessionkey", "setcookie", "signature", "signaturekey", "sshkey", "ssn", "symfony", "token",
+	"transactionid", "twiliotoken", "usersession", "voterid", "xapikey", "xauthtoken", "xcsrftoken",
+	"xforwardedfor", "xrealip", "xsrf", "xsrftoken",
+}
+
+// exceptionReplayConfig is the configuration of the capture of variables, set while a tracer
+// with Exception Replay enabled is running.
+type exceptionReplayConfig struct {
+	redacted map[string]struct{}
+}
+
+var exceptionReplay atomic.Pointer[exceptionReplayConfig]
+
+// newExceptionReplayConfig returns the configuration redacting the default identifiers and
+// the comma-separated extra ones.
+func newExceptionReplayConfig(extra string) *exceptionReplayConfig {
+	cfg := &exceptionReplayConfig{redacted: make(map[string]struct{})}
+	for _, id := range defaultRedactedIdentifiers {
+		cfg.redacted[id] = struct{}{}
+	}
+	for _, id := range strings.Split(extra, ",") {
+		if id = normalizeIdentifier(id); id != "" {
+			cfg.redacted[id] = struct{}{}
+		}
+	}
+	return cfg
+}
+
+// normalizeIdentifier lower-cases id and removes the characters ignored when matching the
+// redacted identifiers.
+func normalizeIdentifier(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', '$', '@', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToLower(id))
+}
+
+func (c *exceptionReplayConfig) isRedacted(id string) bool {
+	_, ok := c.redacted[normalizeIdentifier(id)]
+	return ok
+}
+
+// capturedFrame holds the values of the variables captured at a frame.
+type capturedFrame struct {
+	Function string                   `json:"function"`
+	File     string                   `json:"file"`
+	Line     int                      `json:"line"`
+	Locals   map[string]capturedValue `json:"locals"`
+}
+
+// capturedValue is the snapshot of a value, following the format of the Dynamic
+// Instrumentation snapshots.
+type capturedValue struct {
+	Type              string                   `json:"type"`
+	Value             string                   `json:"value,omitempty"`
+	IsNull            bool                     `json:"isNull,omitempty"`
+	Truncated         bool                     `json:"truncated,omitempty"`
+	Size              int                      `json:"size,omitempty"`
+	Fields            map[string]capturedValue `json:"fields,omitempty"`
+	Elements          []capturedValue          `json:"elements,omitempty"`
+	Entries           [][2]capturedValue       `json:"entries,omitempty"`
+	NotCapturedReason string                   `json:"notCapturedReason,omitempty"`
+}
+
+// capturedError is an error annotated with the variables captured by CaptureVariables.
+type capturedError struct {
+	error
+	frames []capturedFrame
+}
+
+func (e *capturedError) Unwrap() error { return e.error }
+
+// ErrorType returns the type of the wrapped error, so that it is the one reported on spans.
+func (e *capturedError) ErrorType() string {
+	if v, ok := e.error.(interface{ ErrorType() string }); ok {
+		return v.ErrorType()
+	}
+	return reflect.TypeOf(e.error).String()
+}
+
+// CaptureVariables annotates err with the values of the given variables, by name, for Exception
+// Replay. When err is set on a span, with the ext.Error tag or the WithError FinishOption, the
+// values are attached to the span, along with the function, file and line of the caller of
+// CaptureVariables. Annotating the same error at several frames while it is returned captures
+// the variables of each of them, up to 10 frames.
+//
+// The values are captured with bounded depth and size, and the values of the variables, fields
+// and map keys whose names look sensitive, such as "password" or "token", are redacted. More
+// identifiers can be redacted with DD_DYNAMIC_INSTRUMENTATION_REDACTED_IDENTIFIERS.
+//
+// CaptureVariables returns err unchanged if it is nil or if Exception Replay is disabled, which
+// is the default. It is enabled with DD_EXCEPTION_REPLAY_ENABLED or WithExceptionReplay. It
+// is typically called when returning an error:
+//
+//	func charge(order Order, amount int) (err error) {
+//		defer func() {
+//			err = tracer.CaptureVariables(err, map[string]any{"order": order, "amount": amount})
+//		}()
+//		...
+//	}
+func CaptureVariables(err error, vars map[string]any) error {
+	cfg := exceptionReplay.Load()
+	if err == nil || cfg == nil {
+		return err
+	}
+	frame := capturedFrame{Locals: make(map[string]capturedValue, len(vars))}
+	if pc, file, line, ok := runtime.Caller(1); ok {
+		frame.File, frame.Line = file, line
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			frame.Function = fn.Name()
+		}
+	}
+	for name, v := range vars {
+		if cfg.isRedacted(name) {
+			frame.Locals[name] = redactedValue(reflect.ValueOf(v))
+			continue
+		}
+		frame.Locals[name] = cfg.capture(reflect.ValueOf(v), 0)
+	}
+	var ce *capturedError
+	if errors.As(err, &ce) {
+		if len(ce.frames) >= snapshotMaxFrames {
+			return err
+		}
+		// Keep the frames of the annotated error untouched, as it may be shared.
+		frames := append(ce.frames[:len(ce.frames):len(ce.frames)], frame)
+		return &capturedError{error: err, frames: frames}
+	}
+	return &capturedError{error: err, frames: []capturedFrame{frame}}
+}
+
+func redactedValue(v reflect.Value) capturedValue {
+	return capturedValue{Type: typeName(v), NotCapturedReason: "redactedIdent"}
+}
+
+func typeName(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	return v.Type().String()
+}
+
+// capture returns the snapshot of v, depth being its depth in the captured variable.
+func (c *exceptionReplayConfig) capture(v reflect.Value, depth int) capturedValue {
+	cv := capturedValue{Type: typeName(v)}
+	if !v.IsValid() {
+		cv.IsNull = true
+		return cv
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			cv.IsNull = true
+			return cv
+		}
+		if depth >= snapshotMaxDepth {
+			cv.NotCapturedReason = "depth"
+			return cv
+		}
+		elem := c.capture(v.Elem(), depth+1)
+		if v.Kind() == reflect.Pointer {
+			elem.Type = cv.Type
+		}
+		return elem
+	case reflect.String:
+		s := v.String()
+		cv.Value = s
+		if len(s) > snapshotMaxStringLength {
+			cv.Value, cv.Truncated, cv.Size = s[:snapshotMaxStringLength], true, len(s)
+		}
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		cv.Value = fmt.Sprint(v)
+	case reflect.Struct:
+		if depth >= snapshotMaxDepth {
+			cv.NotCapturedReason = "depth"
+			return cv
+		}
+		cv.Fields = make(map[string]capturedValue)
+		for i := 0; i < v.NumField(); i++ {
+			if i >= snapshotMaxFieldCount {
+				cv.NotCapturedReason = "fieldCount"
+				break
+			}
+			name := v.Type().Field(i).Name
+			if c.isRedacted(name) {
+				cv.Fields[name] = redactedValue(v.Field(i))
+				continue
+			}
+			cv.Fields[name] = c.capture(v.Field(i), depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			cv.IsNull = true
+			return cv
+		}
+		if depth >= snapshotMaxDepth {
+			cv.NotCapturedReason = "depth"
+			return cv
+		}
+		cv.Size = v.Len()
+		cv.Elements = []capturedValue{}
+		for i := 0; i < v.Len(); i++ {
+			if i >= snapshotMaxCollectionSize {
+				cv.NotCapturedReason = "collectionSize"
+				break
+			}
+			cv.Elements = append(cv.Elements, c.capture(v.Index(i), depth+1))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			cv.IsNull = true
+			return cv
+		}
+		if depth >= snapshotMaxDepth {
+			cv.NotCapturedReason = "depth"
+			return cv
+		}
+		cv.Size = v.Len()
+		cv.Entries = [][2]capturedValue{}
+		iter := v.MapRange()
+		for iter.Next() {
+			if len(cv.Entries) >= snapshotMaxCollectionSize {
+				cv.NotCapturedReason = "collectionSize"
+				break
+			}
+			key := c.capture(iter.Key(), depth+1)
+			if iter.Key().Kind() == reflect.String && c.isRedacted(iter.Key().String()) {
+				cv.Entries = append(cv.Entries, [2]capturedValue{key, redactedValue(iter.Value())})
+				continue
+			}
+			cv.Entries = append(cv.Entries, [2]capturedValue{key, c.capture(iter.Value(), depth+1)})
+		}
+	default:
+		// Functions, channels and unsafe pointers
+		cv.NotCapturedReason = "unsupportedType"
+	}
+	return cv
+}
+
+// errorSnapshot returns the variables captured for err by CaptureVariables, as JSON, from the
+// innermost frame to the outermost one, or false if there are none.
+func errorSnapshot(err error) (string, bool) {
+	var ce *capturedError
+	if !errors.As(err, &ce) || len(ce.frames) == 0 {
+		return "", false
+	}
+	data, jsonErr := json.Marshal(struct {
+		Frames []capturedFrame `json:"frames"`
+	}{ce.frames})
+	if jsonErr != nil {
+		log.Debug("Could not marshal the variables captured for error: %s", jsonErr.Error())
+		return "", false
+	}
+	return string(data), true
+}
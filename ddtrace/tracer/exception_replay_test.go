@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testOrder struct {
+	ID       int
+	Items    []string
+	Password string
+	next     *testOrder
+}
+
+func TestCaptureVariables(t *testing.T) {
+	errTest := errors.New("test error")
+
+	t.Run("disabled", func(t *testing.T) {
+		exceptionReplay.Store(nil)
+		assert.Same(t, errTest, CaptureVariables(errTest, map[string]any{"id": 1}))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		exceptionReplay.Store(newExceptionReplayConfig(""))
+		defer exceptionReplay.Store(nil)
+		assert.NoError(t, CaptureVariables(nil, map[string]any{"id": 1}))
+	})
+
+	t.Run("frames", func(t *testing.T) {
+		exceptionReplay.Store(newExceptionReplayConfig("customer-name"))
+		defer exceptionReplay.Store(nil)
+
+		order := &testOrder{ID: 42, Items: []string{"a", strings.Repeat("b", 300)}, Password: "hunter2"}
+		order.next = order
+		err := CaptureVariables(errTest, map[string]any{
+			"order":         order,
+			"api_key":       "secret",
+			"customer_name": "John",
+			"handler":       func() {},
+		})
+		err = CaptureVariables(err, map[string]any{"attempts": 3, "labels": map[string]string{"token": "x", "env": "prod"}})
+		assert.ErrorIs(t, err, errTest)
+		assert.Equal(t, "test error", err.Error())
+
+		snapshot, ok := errorSnapshot(err)
+		require.True(t, ok)
+		var got struct {
+			Frames []capturedFrame `json:"frames"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(snapshot), &got))
+		require.Len(t, got.Frames, 2)
+
+		frame := got.Frames[0]
+		assert.Contains(t, frame.Function, "TestCaptureVariables")
+		assert.Contains(t, frame.File, "exception_replay_test.go")
+		assert.NotZero(t, frame.Line)
+		assert.Equal(t, "redactedIdent", frame.Locals["api_key"].NotCapturedReason)
+		assert.Equal(t, "redactedIdent", frame.Locals["customer_name"].NotCapturedReason)
+		assert.Equal(t, "unsupportedType", frame.Locals["handler"].NotCapturedReason)
+
+		captured := frame.Locals["order"]
+		assert.Equal(t, "*tracer.testOrder", captured.Type)
+		assert.Equal(t, "42", captured.Fields["ID"].Value)
+		assert.Equal(t, "redactedIdent", captured.Fields["Password"].NotCapturedReason)
+		items := captured.Fields["Items"]
+		assert.Equal(t, 2, items.Size)
+		assert.Equal(t, "a", items.Elements[0].Value)
+		assert.True(t, items.Elements[1].Truncated)
+		assert.Len(t, items.Elements[1].Value, snapshotMaxStringLength)
+		assert.Equal(t, 300, items.Elements[1].Size)
+		// The cycle is bounded by the maximum depth.
+		assert.Equal(t, "depth", captured.Fields["next"].NotCapturedReason)
+
+		frame = got.Frames[1]
+		assert.Equal(t, "3", frame.Locals["attempts"].Value)
+		labels := frame.Locals["labels"]
+		assert.Equal(t, 2, labels.Size)
+		for _, e := range labels.Entries {
+			if e[0].Value == "token" {
+				assert.Equal(t, "redactedIdent", e[1].NotCapturedReason)
+			} else {
+				assert.Equal(t, "prod", e[1].Value)
+			}
+		}
+	})
+
+	t.Run("span", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t, WithExceptionReplay(true))
+		require.NoError(t, err)
+		defer stop()
+
+		span := tracer.newRootSpan("pylons.request", "pylons", "/")
+		span.Finish(WithError(CaptureVariables(errTest, map[string]any{"id": 1})))
+		assert.Equal(t, "test error", span.meta[ext.ErrorMsg])
+		assert.Equal(t, "*errors.errorString", span.meta[ext.ErrorType])
+		assert.Contains(t, span.meta[keyErrorSnapshot], `"id":{"type":"int","value":"1"}`)
+
+		span = tracer.newRootSpan("pylons.request", "pylons", "/")
+		span.Finish(WithError(errTest))
+		assert.NotContains(t, span.meta, keyErrorSnapshot)
+	})
+}
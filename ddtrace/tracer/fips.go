@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// fipsCipherSuites lists the TLS 1.2 cipher suites approved by FIPS 140-3.
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// fipsCurves lists the elliptic curves approved by FIPS 140-3.
+var fipsCurves = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+
+// WithFIPSMode restricts the TLS configuration of the HTTP client sending the
+// data of the tracer to the cipher suites and curves approved by FIPS 140-3,
+// and checks that the application is built with a FIPS validated
+// cryptographic module: either BoringCrypto, with GOEXPERIMENT=boringcrypto,
+// or the Go Cryptographic Module, with GODEBUG=fips140=on on Go 1.24 and
+// later. An error is logged when it isn't. Whether FIPS mode is enabled and
+// the module is validated is reported with telemetry.
+//
+// Only the TLS configuration of the HTTP client created by the tracer is
+// restricted: a client set with WithHTTPClient is left untouched, and a warning
+// is logged. This option takes precedence over the DD_TRACE_FIPS_MODE
+// environment variable.
+func WithFIPSMode() StartOption {
+	return func(c *config) {
+		c.fipsMode = true
+	}
+}
+
+// fipsTLSConfig returns a copy of base, which may be nil, restricted to the
+// versions, cipher suites and curves approved by FIPS 140-3. TLS 1.3 is only
+// allowed when the cryptographic module is validated, which then restricts its
+// cipher suites, as they can't be configured.
+func fipsTLSConfig(base *tls.Config) *tls.Config {
+	cfg := &tls.Config{}
+	if base != nil {
+		cfg = base.Clone()
+	}
+	cfg.MinVersion = tls.VersionTLS12
+	cfg.MaxVersion = tls.VersionTLS12
+	if fipsCryptoEnabled() {
+		cfg.MaxVersion = tls.VersionTLS13
+	}
+	cfg.CipherSuites = fipsCipherSuites
+	cfg.CurvePreferences = fipsCurves
+	return cfg
+}
+
+// applyFIPSMode restricts the TLS configuration of the HTTP client of c to the
+// one returned by fipsTLSConfig, when the client was created by the tracer, as
+// reported by own.
+func applyFIPSMode(c *config, own bool) {
+	if !fipsCryptoEnabled() {
+		log.Error("FIPS mode: the cryptographic module isn't FIPS validated. Build the application with GOEXPERIMENT=boringcrypto, or run it with GODEBUG=fips140=on on Go 1.24 and later.")
+	}
+	if !own {
+		log.Warn("FIPS mode: the TLS configuration of the HTTP client provided to tracer.Start isn't restricted, it must be configured by the application.")
+		return
+	}
+	switch t := c.httpClient.Transport.(type) {
+	case *agentEndpoints:
+		t.tcp.TLSClientConfig = fipsTLSConfig(t.tcp.TLSClientConfig)
+	case *http.Transport:
+		t.TLSClientConfig = fipsTLSConfig(t.TLSClientConfig)
+	default:
+		log.Error("FIPS mode: can't restrict the TLS configuration of the HTTP client, its transport is a %T rather than a *http.Transport.", c.httpClient.Transport)
+	}
+}
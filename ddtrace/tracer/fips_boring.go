@@ -0,0 +1,16 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build boringcrypto
+
+package tracer
+
+import "crypto/boring"
+
+// fipsCryptoEnabled reports whether the cryptographic operations are done by
+// a FIPS validated module, here BoringCrypto.
+func fipsCryptoEnabled() bool {
+	return boring.Enabled()
+}
@@ -0,0 +1,16 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build go1.24 && !boringcrypto
+
+package tracer
+
+import "crypto/fips140"
+
+// fipsCryptoEnabled reports whether the cryptographic operations are done by
+// a FIPS validated module, here the Go Cryptographic Module in FIPS mode.
+func fipsCryptoEnabled() bool {
+	return fips140.Enabled()
+}
@@ -0,0 +1,14 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build !go1.24 && !boringcrypto
+
+package tracer
+
+// fipsCryptoEnabled reports whether the cryptographic operations are done by
+// a FIPS validated module, which requires BoringCrypto before Go 1.24.
+func fipsCryptoEnabled() bool {
+	return false
+}
@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFIPSTLSConfig(t *testing.T) {
+	roots := x509.NewCertPool()
+	base := &tls.Config{RootCAs: roots, ServerName: "intake", CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256}}
+	cfg := fipsTLSConfig(base)
+	assert.Same(t, roots, cfg.RootCAs)
+	assert.Equal(t, "intake", cfg.ServerName)
+	assert.Equal(t, fipsCipherSuites, cfg.CipherSuites)
+	assert.Equal(t, fipsCurves, cfg.CurvePreferences)
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+	if fipsCryptoEnabled() {
+		assert.Equal(t, uint16(tls.VersionTLS13), cfg.MaxVersion)
+	} else {
+		assert.Equal(t, uint16(tls.VersionTLS12), cfg.MaxVersion)
+	}
+	// base is left untouched.
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256}, base.CipherSuites)
+	assert.Zero(t, base.MinVersion)
+
+	for _, id := range cfg.CipherSuites {
+		for _, s := range tls.InsecureCipherSuites() {
+			assert.NotEqual(t, s.ID, id, s.Name)
+		}
+	}
+}
+
+func TestWithFIPSMode(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		c, err := newConfig()
+		require.NoError(t, err)
+		assert.False(t, c.fipsMode)
+		assert.Nil(t, c.httpClient.Transport.(*http.Transport).TLSClientConfig)
+	})
+
+	t.Run("option", func(t *testing.T) {
+		c, err := newConfig(WithFIPSMode())
+		require.NoError(t, err)
+		assert.True(t, c.fipsMode)
+		tlsConfig := c.httpClient.Transport.(*http.Transport).TLSClientConfig
+		require.NotNil(t, tlsConfig)
+		assert.Equal(t, fipsCipherSuites, tlsConfig.CipherSuites)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("DD_TRACE_FIPS_MODE", "true")
+		c, err := newConfig()
+		require.NoError(t, err)
+		assert.True(t, c.fipsMode)
+	})
+
+	t.Run("custom-client", func(t *testing.T) {
+		transport := &http.Transport{}
+		client := &http.Client{Transport: transport}
+		c, err := newConfig(WithHTTPClient(client), WithFIPSMode())
+		require.NoError(t, err)
+		// the client provided by the application is left untouched
+		assert.Same(t, client, c.httpClient)
+		assert.Same(t, transport, c.httpClient.Transport)
+		// net/http sets the TLS configuration of the transport once it is used
+		if transport.TLSClientConfig != nil {
+			assert.Nil(t, transport.TLSClientConfig.CipherSuites)
+			assert.Nil(t, transport.TLSClientConfig.CurvePreferences)
+		}
+	})
+}
@@ -23,28 +23,6 @@ func withStatsdClient(s globalinternal.StatsdClient) StartOption {
 	}
 }
 
-func TestReportRuntimeMetrics(t *testing.T) {
-	var tg statsdtest.TestStatsdClient
-	trc, err := newUnstartedTracer(withStatsdClient(&tg))
-	assert.NoError(t, err)
-	defer trc.statsd.Close()
-
-	trc.wg.Add(1)
-	go func() {
-		defer trc.wg.Done()
-		trc.reportRuntimeMetrics(time.Millisecond)
-	}()
-	assert := assert.New(t)
-	err = tg.Wait(assert, 35, 1*time.Second)
-	close(trc.stop)
-	assert.NoError(err)
-	calls := tg.CallNames()
-	assert.True(len(calls) > 30)
-	assert.Contains(calls, "runtime.go.num_cpu")
-	assert.Contains(calls, "runtime.go.mem_stats.alloc")
-	assert.Contains(calls, "runtime.go.gc_stats.pause_quantiles.75p")
-}
-
 func TestReportHealthMetricsAtInterval(t *testing.T) {
 	assert := assert.New(t)
 	var tg statsdtest.TestStatsdClient
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/internal/orchestrion"
@@ -29,6 +30,7 @@ import (
 	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/apikey"
 	appsecconfig "github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
 	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/constants"
 	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
@@ -48,59 +50,80 @@ var contribIntegrations = map[string]struct {
 	name     string // user readable name for startup logs
 	imported bool   // true if the user has imported the integration
 }{
-	"github.com/99designs/gqlgen":                   {"gqlgen", false},
-	"github.com/aws/aws-sdk-go":                     {"AWS SDK", false},
-	"github.com/aws/aws-sdk-go-v2":                  {"AWS SDK v2", false},
-	"github.com/bradfitz/gomemcache":                {"Memcache", false},
-	"cloud.google.com/go/pubsub.v1":                 {"Pub/Sub", false},
-	"github.com/confluentinc/confluent-kafka-go":    {"Kafka (confluent)", false},
-	"github.com/confluentinc/confluent-kafka-go/v2": {"Kafka (confluent) v2", false},
-	"database/sql":                                  {"SQL", false},
-	"github.com/dimfeld/httptreemux/v5":             {"HTTP Treemux", false},
-	"github.com/elastic/go-elasticsearch/v6":        {"Elasticsearch v6", false},
-	"github.com/emicklei/go-restful/v3":             {"go-restful v3", false},
-	"github.com/gin-gonic/gin":                      {"Gin", false},
-	"github.com/globalsign/mgo":                     {"MongoDB (mgo)", false},
-	"github.com/go-chi/chi":                         {"chi", false},
-	"github.com/go-chi/chi/v5":                      {"chi v5", false},
-	"github.com/go-pg/pg/v10":                       {"go-pg v10", false},
-	"github.com/go-redis/redis":                     {"Redis", false},
-	"github.com/go-redis/redis/v7":                  {"Redis v7", false},
-	"github.com/go-redis/redis/v8":                  {"Redis v8", false},
-	"go.mongodb.org/mongo-driver":                   {"MongoDB", false},
-	"github.com/gocql/gocql":                        {"Cassandra", false},
-	"github.com/gofiber/fiber/v2":                   {"Fiber", false},
-	"github.com/gomodule/redigo":                    {"Redigo", false},
-	"google.golang.org/api":                         {"Google API", false},
-	"google.golang.org/grpc":                        {"gRPC", false},
-	"github.com/gorilla/mux":                        {"Gorilla Mux", false},
-	"gorm.io/gorm.v1":                               {"Gorm v1", false},
-	"github.com/graph-gophers/graphql-go":           {"Graph Gophers GraphQL", false},
-	"github.com/graphql-go/graphql":                 {"GraphQL-Go GraphQL", false},
-	"github.com/hashicorp/consul/api":               {"Consul", false},
-	"github.com/hashicorp/vault/api":                {"Vault", false},
-	"github.com/jackc/pgx/v5":                       {"PGX", false},
-	"github.com/jmoiron/sqlx":                       {"SQLx", false},
-	"github.com/julienschmidt/httprouter":           {"HTTP Router", false},
-	"k8s.io/client-go/kubernetes":                   {"Kubernetes", false},
-	"github.com/labstack/echo/v4":                   {"echo v4", false},
-	"log/slog":                                      {"log/slog", false},
-	"github.com/miekg/dns":                          {"miekg/dns", false},
-	"net/http":                                      {"HTTP", false},
-	"gopkg.in/olivere/elastic.v5":                   {"Elasticsearch v5", false},
-	"github.com/redis/go-redis/v9":                  {"Redis v9", false},
-	"github.com/redis/rueidis":                      {"Rueidis", false},
-	"github.com/segmentio/kafka-go":                 {"Kafka v0", false},
-	"github.com/IBM/sarama":                         {"IBM sarama", false},
-	"github.com/Shopify/sarama":                     {"Shopify sarama", false},
-	"github.com/sirupsen/logrus":                    {"Logrus", false},
-	"github.com/syndtr/goleveldb":                   {"LevelDB", false},
-	"github.com/tidwall/buntdb":                     {"BuntDB", false},
-	"github.com/twitchtv/twirp":                     {"Twirp", false},
-	"github.com/uptrace/bun":                        {"Bun", false},
-	"github.com/urfave/negroni":                     {"Negroni", false},
-	"github.com/valyala/fasthttp":                   {"FastHTTP", false},
-	"github.com/valkey-io/valkey-go":                {"Valkey", false},
+	"github.com/99designs/gqlgen":                    {"gqlgen", false},
+	"github.com/allegro/bigcache/v3":                 {"BigCache v3", false},
+	"github.com/aws/aws-sdk-go":                      {"AWS SDK", false},
+	"github.com/aws/aws-sdk-go-v2":                   {"AWS SDK v2", false},
+	"github.com/bradfitz/gomemcache":                 {"Memcache", false},
+	"github.com/ClickHouse/clickhouse-go/v2":         {"ClickHouse", false},
+	"cloud.google.com/go/pubsub.v1":                  {"Pub/Sub", false},
+	"cloud.google.com/go/spanner":                    {"Spanner", false},
+	"connectrpc.com/connect":                         {"Connect", false},
+	"github.com/confluentinc/confluent-kafka-go":     {"Kafka (confluent)", false},
+	"github.com/confluentinc/confluent-kafka-go/v2":  {"Kafka (confluent) v2", false},
+	"database/sql":                                   {"SQL", false},
+	"github.com/dgraph-io/ristretto":                 {"Ristretto", false},
+	"github.com/dimfeld/httptreemux/v5":              {"HTTP Treemux", false},
+	"github.com/elastic/go-elasticsearch/v6":         {"Elasticsearch v6", false},
+	"github.com/emicklei/go-restful/v3":              {"go-restful v3", false},
+	"entgo.io/ent":                                   {"ent", false},
+	"github.com/gin-gonic/gin":                       {"Gin", false},
+	"github.com/globalsign/mgo":                      {"MongoDB (mgo)", false},
+	"github.com/go-chi/chi":                          {"chi", false},
+	"github.com/go-chi/chi/v5":                       {"chi v5", false},
+	"github.com/go-pg/pg/v10":                        {"go-pg v10", false},
+	"github.com/go-redis/redis":                      {"Redis", false},
+	"github.com/go-redis/redis/v7":                   {"Redis v7", false},
+	"github.com/go-redis/redis/v8":                   {"Redis v8", false},
+	"go.mongodb.org/mongo-driver":                    {"MongoDB", false},
+	"go.temporal.io/sdk":                             {"Temporal", false},
+	"github.com/gocql/gocql":                         {"Cassandra", false},
+	"github.com/gofiber/fiber/v2":                    {"Fiber", false},
+	"github.com/golang/groupcache":                   {"groupcache", false},
+	"github.com/gomodule/redigo":                     {"Redigo", false},
+	"google.golang.org/api":                          {"Google API", false},
+	"google.golang.org/grpc":                         {"gRPC", false},
+	"github.com/gorilla/mux":                         {"Gorilla Mux", false},
+	"gorm.io/gorm.v1":                                {"Gorm v1", false},
+	"github.com/graph-gophers/graphql-go":            {"Graph Gophers GraphQL", false},
+	"github.com/graphql-go/graphql":                  {"GraphQL-Go GraphQL", false},
+	"github.com/hashicorp/consul/api":                {"Consul", false},
+	"github.com/hashicorp/vault/api":                 {"Vault", false},
+	"github.com/hibiken/asynq":                       {"Asynq", false},
+	"github.com/jackc/pgx/v5":                        {"PGX", false},
+	"github.com/jmoiron/sqlx":                        {"SQLx", false},
+	"github.com/julienschmidt/httprouter":            {"HTTP Router", false},
+	"k8s.io/client-go/kubernetes":                    {"Kubernetes", false},
+	"github.com/labstack/echo/v4":                    {"echo v4", false},
+	"log/slog":                                       {"log/slog", false},
+	"github.com/miekg/dns":                           {"miekg/dns", false},
+	"github.com/nats-io/nats.go":                     {"NATS", false},
+	"github.com/opensearch-project/opensearch-go/v4": {"OpenSearch v4", false},
+	"net/http":                       {"HTTP", false},
+	"net/smtp":                       {"SMTP", false},
+	"os/exec":                        {"os/exec", false},
+	"net":                            {"net", false},
+	"sigs.k8s.io/controller-runtime": {"controller-runtime", false},
+	"crypto":                         {"crypto", false},
+	"gopkg.in/olivere/elastic.v5":    {"Elasticsearch v5", false},
+	"github.com/redis/go-redis/v9":   {"Redis v9", false},
+	"github.com/redis/rueidis":       {"Rueidis", false},
+	"github.com/riverqueue/river":    {"River", false},
+	"github.com/rs/zerolog":          {"Zerolog", false},
+	"github.com/robfig/cron/v3":      {"cron v3", false},
+	"github.com/segmentio/kafka-go":  {"Kafka v0", false},
+	"github.com/IBM/sarama":          {"IBM sarama", false},
+	"github.com/Shopify/sarama":      {"Shopify sarama", false},
+	"github.com/sirupsen/logrus":     {"Logrus", false},
+	"github.com/syndtr/goleveldb":    {"LevelDB", false},
+	"github.com/tidwall/buntdb":      {"BuntDB", false},
+	"github.com/twmb/franz-go":       {"Kafka (franz-go)", false},
+	"github.com/twitchtv/twirp":      {"Twirp", false},
+	"github.com/uptrace/bun":         {"Bun", false},
+	"github.com/urfave/negroni":      {"Negroni", false},
+	"github.com/valyala/fasthttp":    {"FastHTTP", false},
+	"github.com/valkey-io/valkey-go": {"Valkey", false},
+	"go.uber.org/zap":                {"Zap", false},
 }
 
 var (
@@ -137,6 +160,10 @@ type config struct {
 	// featureFlags specifies any enabled feature flags.
 	featureFlags map[string]struct{}
 
+	// devMode holds the configuration of the dev mode, or is nil when it is
+	// disabled. See WithDevMode.
+	devMode *devModeConfig
+
 	// logToStdout reports whether we should log all traces to the standard
 	// output instead of using the agent. This is used in Lambda environments.
 	logToStdout bool
@@ -174,6 +201,10 @@ type config struct {
 	// originalAgentURL is the agent URL that receives traces from the tracer and does not get changed.
 	originalAgentURL *url.URL
 
+	// agentEndpoints routes the requests to the first available agent, when
+	// several are set with DD_TRACE_AGENT_URLS.
+	agentEndpoints *agentEndpoints
+
 	// serviceMappings holds a set of service mappings to dynamically rename services
 	serviceMappings map[string]string
 
@@ -207,6 +238,17 @@ type config struct {
 	// runtimeMetricsV2 specifies whether collection of runtime metrics v2 is enabled.
 	runtimeMetricsV2 bool
 
+	// runtimeMetricsV2Filter selects the runtime metrics v2 which are emitted.
+	runtimeMetricsV2Filter runtimeMetricsFilter
+
+	// spanDurationMetrics specifies whether span durations are aggregated per operation
+	// name and resource, and reported along with the tracer health metrics.
+	spanDurationMetrics bool
+
+	// runtimeEnvTags specifies whether the first span of each trace chunk is
+	// tagged with the runtime settings and cgroup limits of the process.
+	runtimeEnvTags bool
+
 	// dogstatsdAddr specifies the address to connect for sending metrics to the
 	// Datadog Agent. If not set, it defaults to "localhost:8125" or to the
 	// combination of the environment variables DD_AGENT_HOST and DD_DOGSTATSD_PORT.
@@ -224,10 +266,37 @@ type config struct {
 	// to the entire trace if any spans satisfy the criteria
 	traceRules []SamplingRule
 
+	// parentBasedSampling specifies whether sampling decisions received from upstream
+	// services are final. When set, local samplers only apply to traces started locally.
+	parentBasedSampling bool
+
+	// traceProtocol specifies the version of the format the traces are sent to the
+	// agent with, when it is supported by the agent. See traceProtocolV05.
+	traceProtocol string
+
+	// traceV05Rejected reports whether the agent rejected a payload encoded with
+	// the v0.5 format, in which case the traces are sent with the v0.4 format.
+	traceV05Rejected atomic.Bool
+
+	// refreshedAgent holds the trace-agent's capabilities of the last discovery
+	// made after the tracer started, if any. See agentFeatures.
+	refreshedAgent atomic.Pointer[agentFeatures]
+
+	// agentFeaturesRefresh specifies how often the trace-agent's capabilities are
+	// discovered again. It defaults to agentFeaturesRefreshInterval; replaced in tests.
+	agentFeaturesRefresh time.Duration
+
 	// tickChan specifies a channel which will receive the time every time the tracer must flush.
 	// It defaults to time.Ticker; replaced in tests.
 	tickChan <-chan time.Time
 
+	// flushInterval specifies the interval at which traces are flushed to the transport.
+	flushInterval time.Duration
+
+	// flushJitter specifies whether the first flush is delayed by a random duration
+	// of up to flushInterval.
+	flushJitter bool
+
 	// noDebugStack disables the collection of debug stack traces globally. No traces reporting
 	// errors will record a stack trace when this option is set.
 	noDebugStack bool
@@ -260,6 +329,21 @@ type config struct {
 	// misconfiguration
 	spanTimeout time.Duration
 
+	// spanStackDepth is the maximum number of frames of the creation stack captured
+	// for each span, or 0 if creation stacks are disabled.
+	spanStackDepth int
+
+	// spanStackThreshold is the minimum duration of a span for its creation stack
+	// to be added as a tag.
+	spanStackThreshold time.Duration
+
+	// maxSpanLinks and maxSpanEvents are the maximum numbers of span links and
+	// span events sent with each span.
+	maxSpanLinks, maxSpanEvents int
+
+	// fipsMode restricts the TLS configuration of httpClient to FIPS approved algorithms. See WithFIPSMode.
+	fipsMode bool
+
 	// partialFlushMinSpans is the number of finished spans in a single trace to trigger a
 	// partial flush, or 0 if partial flushing is disabled.
 	// Value from DD_TRACE_PARTIAL_FLUSH_MIN_SPANS, default 1000.
@@ -292,6 +376,15 @@ type config struct {
 	// Value from DD_DYNAMIC_INSTRUMENTATION_ENABLED, default false.
 	dynamicInstrumentationEnabled bool
 
+	// exceptionReplay enables the capture of variables with CaptureVariables.
+	// Value from DD_EXCEPTION_REPLAY_ENABLED, default false.
+	exceptionReplay bool
+
+	// deterministic makes the IDs, the sampling decisions and the flushes
+	// deterministic, the IDs being generated from deterministicSeed.
+	deterministic     bool
+	deterministicSeed uint64
+
 	// globalSampleRate holds sample rate read from environment variables.
 	globalSampleRate float64
 
@@ -301,6 +394,9 @@ type config struct {
 	// ciVisibilityAgentless controls if the tracer is loaded with CI Visibility agentless mode. default false
 	ciVisibilityAgentless bool
 
+	// apiKeyResolver resolves the API key of the agentless features, when set with WithAPIKeyResolver.
+	apiKeyResolver *apikey.Resolver
+
 	// logDirectory is directory for tracer logs specified by user-setting DD_TRACE_LOG_DIRECTORY. default empty/unused
 	logDirectory string
 
@@ -346,33 +442,20 @@ const partialFlushMinSpansDefault = 1000
 func newConfig(opts ...StartOption) (*config, error) {
 	c := new(config)
 	c.sampler = NewAllSampler()
-	sampleRate := math.NaN()
-	if r := getDDorOtelConfig("sampleRate"); r != "" {
-		var err error
-		sampleRate, err = strconv.ParseFloat(r, 64)
-		if err != nil {
-			log.Warn("ignoring DD_TRACE_SAMPLE_RATE, error: %v", err)
-			sampleRate = math.NaN()
-		} else if sampleRate < 0.0 || sampleRate > 1.0 {
-			log.Warn("ignoring DD_TRACE_SAMPLE_RATE: out of range %f", sampleRate)
-			sampleRate = math.NaN()
+	c.globalSampleRate = globalSampleRateFromEnv()
+	if v := os.Getenv("DD_TRACE_SAMPLER"); v != "" {
+		if strings.EqualFold(v, "parentbased") {
+			c.parentBasedSampling = true
+		} else {
+			log.Warn("ignoring DD_TRACE_SAMPLER: unsupported value %q", v)
 		}
 	}
-	c.globalSampleRate = sampleRate
 	c.httpClientTimeout = time.Second * 10 // 10 seconds
 
-	c.traceRateLimitPerSecond = defaultRateLimit
 	origin := telemetry.OriginDefault
-	if v, ok := os.LookupEnv("DD_TRACE_RATE_LIMIT"); ok {
-		l, err := strconv.ParseFloat(v, 64)
-		if err != nil {
-			log.Warn("DD_TRACE_RATE_LIMIT invalid, using default value %f: %v", defaultRateLimit, err)
-		} else if l < 0.0 {
-			log.Warn("DD_TRACE_RATE_LIMIT negative, using default value %f", defaultRateLimit)
-		} else {
-			c.traceRateLimitPerSecond = l
-			origin = telemetry.OriginEnvVar
-		}
+	var fromEnv bool
+	if c.traceRateLimitPerSecond, fromEnv = rateLimitFromEnv(); fromEnv {
+		origin = telemetry.OriginEnvVar
 	}
 
 	reportTelemetryOnAppStarted(telemetry.Configuration{Name: "trace_rate_limit", Value: c.traceRateLimitPerSecond, Origin: origin})
@@ -433,8 +516,30 @@ func newConfig(opts ...StartOption) (*config, error) {
 		c.logToStdout = true
 	}
 	c.logStartup = internal.BoolEnv("DD_TRACE_STARTUP_LOGS", true)
+	c.flushInterval = internal.DurationEnv("DD_TRACE_FLUSH_INTERVAL", defaultFlushInterval)
+	if c.flushInterval <= 0 {
+		log.Warn("DD_TRACE_FLUSH_INTERVAL=%s is not a valid value, setting to default %s", c.flushInterval, defaultFlushInterval)
+		c.flushInterval = defaultFlushInterval
+	}
+	c.flushJitter = internal.BoolEnv("DD_TRACE_FLUSH_JITTER_ENABLED", true)
+	c.agentFeaturesRefresh = agentFeaturesRefreshInterval
+	switch v := os.Getenv("DD_TRACE_API_VERSION"); v {
+	case "", traceProtocolV04:
+		c.traceProtocol = traceProtocolV04
+	case traceProtocolV05:
+		c.traceProtocol = traceProtocolV05
+	default:
+		log.Warn("DD_TRACE_API_VERSION=%s is not a supported version, using %s", v, traceProtocolV04)
+		c.traceProtocol = traceProtocolV04
+	}
 	c.runtimeMetrics = internal.BoolVal(getDDorOtelConfig("metrics"), false)
 	c.runtimeMetricsV2 = internal.BoolEnv("DD_RUNTIME_METRICS_V2_ENABLED", false)
+	c.runtimeMetricsV2Filter = runtimeMetricsFilter{
+		allow: splitMetricPatterns(os.Getenv("DD_RUNTIME_METRICS_V2_ALLOWLIST")),
+		deny:  splitMetricPatterns(os.Getenv("DD_RUNTIME_METRICS_V2_DENYLIST")),
+	}
+	c.spanDurationMetrics = internal.BoolEnv("DD_TRACE_SPAN_DURATION_METRICS_ENABLED", false)
+	c.runtimeEnvTags = internal.BoolEnv("DD_RUNTIME_ENV_TAGS_ENABLED", true)
 	c.debug = internal.BoolVal(getDDorOtelConfig("debugMode"), false)
 	c.logDirectory = os.Getenv("DD_TRACE_LOG_DIRECTORY")
 	c.enabled = newDynamicConfig("tracing_enabled", internal.BoolVal(getDDorOtelConfig("enabled"), true), func(_ bool) bool { return true }, equal[bool])
@@ -450,6 +555,11 @@ func newConfig(opts ...StartOption) (*config, error) {
 			log.Warn("ignoring DD_TRACE_CLIENT_HOSTNAME_COMPAT, invalid version %q", compatMode)
 		}
 	}
+	c.spanStackDepth = internal.IntEnv("DD_TRACE_SPAN_STACK_TRACES_DEPTH", 0)
+	c.spanStackThreshold = internal.DurationEnv("DD_TRACE_SPAN_STACK_TRACES_THRESHOLD", defaultSpanStackThreshold)
+	c.maxSpanLinks = spanLimitFromEnv("DD_TRACE_SPAN_LINKS_MAX", defaultMaxSpanLinks)
+	c.maxSpanEvents = spanLimitFromEnv("DD_TRACE_SPAN_EVENTS_MAX", defaultMaxSpanEvents)
+	c.fipsMode = internal.BoolEnv("DD_TRACE_FIPS_MODE", false)
 	c.debugAbandonedSpans = internal.BoolEnv("DD_TRACE_DEBUG_ABANDONED_SPANS", false)
 	if c.debugAbandonedSpans {
 		c.spanTimeout = internal.DurationEnv("DD_TRACE_ABANDONED_SPAN_TIMEOUT", 10*time.Minute)
@@ -472,6 +582,7 @@ func newConfig(opts ...StartOption) (*config, error) {
 
 	// TODO: APMAPI-1358
 	c.dynamicInstrumentationEnabled, _, _ = stableconfig.Bool("DD_DYNAMIC_INSTRUMENTATION_ENABLED", false)
+	c.exceptionReplay = internal.BoolEnv(envExceptionReplayEnabled, false)
 
 	schemaVersionStr := os.Getenv("DD_TRACE_SPAN_ATTRIBUTE_SCHEMA")
 	if v, ok := namingschema.ParseVersion(schemaVersionStr); ok {
@@ -502,29 +613,43 @@ func newConfig(opts ...StartOption) (*config, error) {
 		}
 		fn(c)
 	}
+	var agentURLs []*url.URL
 	if c.agentURL == nil {
-		c.agentURL = internal.AgentURLFromEnv()
+		agentURLs = internal.AgentURLsFromEnv()
+		if len(agentURLs) > 0 {
+			c.agentURL = agentURLs[0]
+		} else {
+			c.agentURL = internal.AgentURLFromEnv()
+		}
 	}
 	c.originalAgentURL = c.agentURL // Preserve the original agent URL for logging
-	if c.httpClient == nil || orchestrion.Enabled() {
+	if len(agentURLs) > 1 && c.httpClient != nil && !orchestrion.Enabled() {
+		log.Warn("DD_TRACE_AGENT_URLS is ignored when a custom HTTP client is provided to tracer.Start, only %s is used.", c.agentURL)
+	}
+	ownHTTPClient := c.httpClient == nil || orchestrion.Enabled()
+	if ownHTTPClient {
 		if orchestrion.Enabled() && c.httpClient != nil {
 			// Make sure we don't create http client traces from inside the tracer by using our http client
 			// TODO(eliott.bouhana): remove once dd:no-span is implemented
 			log.Debug("Orchestrion is enabled, but a custom HTTP client was provided to tracer.Start. This is not supported and will be ignored.")
 		}
-		if c.agentURL.Scheme == "unix" {
+		if len(agentURLs) > 1 {
+			// The requests are sent to the first available agent, see agentEndpoints.
+			c.agentEndpoints = newAgentEndpoints(agentURLs, c.httpClientTimeout)
+			c.httpClient = c.agentEndpoints.client(c.httpClientTimeout)
+			c.agentURL = c.agentEndpoints.url()
+		} else if c.agentURL.Scheme == "unix" {
 			// If we're connecting over UDS we can just rely on the agent to provide the hostname
 			log.Debug("connecting to agent over unix, do not set hostname on any traces")
 			c.httpClient = udsClient(c.agentURL.Path, c.httpClientTimeout)
-			// TODO(darccio): use internal.UnixDataSocketURL instead
-			c.agentURL = &url.URL{
-				Scheme: "http",
-				Host:   fmt.Sprintf("UDS_%s", strings.NewReplacer(":", "_", "/", "_", `\`, "_").Replace(c.agentURL.Path)),
-			}
+			c.agentURL = udsURL(c.agentURL.Path)
 		} else {
 			c.httpClient = defaultHTTPClient(c.httpClientTimeout)
 		}
 	}
+	if c.fipsMode {
+		applyFIPSMode(c, ownHTTPClient)
+	}
 	WithGlobalTag(ext.RuntimeID, globalconfig.RuntimeID())(c)
 	globalTags := c.globalTags.get()
 	if c.env == "" {
@@ -557,19 +682,7 @@ func newConfig(opts ...StartOption) (*config, error) {
 		c.transport = newHTTPTransport(c.agentURL.String(), c.httpClient)
 	}
 	if c.propagator == nil {
-		envKey := "DD_TRACE_X_DATADOG_TAGS_MAX_LENGTH"
-		maxLen := internal.IntEnv(envKey, defaultMaxTagsHeaderLen)
-		if maxLen < 0 {
-			log.Warn("Invalid value %d for %s. Setting to 0.", maxLen, envKey)
-			maxLen = 0
-		}
-		if maxLen > maxPropagatedTagsLength {
-			log.Warn("Invalid value %d for %s. Maximum allowed is %d. Setting to %d.", maxLen, envKey, maxPropagatedTagsLength, maxPropagatedTagsLength)
-			maxLen = maxPropagatedTagsLength
-		}
-		c.propagator = NewPropagator(&PropagatorConfig{
-			MaxTagsHeaderLen: maxLen,
-		})
+		c.propagator = propagatorFromEnv()
 	}
 	if c.logger != nil {
 		log.UseLogger(c.logger)
@@ -588,9 +701,7 @@ func newConfig(opts ...StartOption) (*config, error) {
 		c.ciVisibilityAgentless = ciTransport.agentless
 	}
 
-	// if using stdout or traces are disabled or we are in ci visibility agentless mode, agent is disabled
-	agentDisabled := c.logToStdout || !c.enabled.current || c.ciVisibilityAgentless
-	c.agent = loadAgentFeatures(agentDisabled, c.agentURL, c.httpClient)
+	c.agent = loadAgentFeatures(c.agentDisabled(), c.agentURL, c.httpClient)
 	info, ok := debug.ReadBuildInfo()
 	if !ok {
 		c.loadContribIntegrations([]*debug.Module{})
@@ -698,6 +809,16 @@ func udsClient(socketPath string, timeout time.Duration) *http.Client {
 	}
 }
 
+// udsURL returns the URL of the requests sent to the agent listening on the
+// Unix Domain Socket at socketPath, with a client returned by udsClient.
+func udsURL(socketPath string) *url.URL {
+	// TODO(darccio): use internal.UnixDataSocketURL instead
+	return &url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("UDS_%s", strings.NewReplacer(":", "_", "/", "_", `\`, "_").Replace(socketPath)),
+	}
+}
+
 // defaultDogstatsdAddr returns the default connection address for Dogstatsd.
 func defaultDogstatsdAddr() string {
 	envHost, envPort := os.Getenv("DD_DOGSTATSD_HOST"), os.Getenv("DD_DOGSTATSD_PORT")
@@ -757,6 +878,9 @@ type agentFeatures struct {
 
 	// spanEvents reports whether the trace-agent can receive spans with the `span_events` field.
 	spanEventsAvailable bool
+
+	// traceV05 reports whether the trace-agent can receive traces on the /v0.5/traces endpoint.
+	traceV05 bool
 }
 
 // HasFlag reports whether the agent has set the feat feature flag.
@@ -765,6 +889,81 @@ func (a *agentFeatures) HasFlag(feat string) bool {
 	return ok
 }
 
+// globalSampleRateFromEnv returns the sample rate of DD_TRACE_SAMPLE_RATE, or
+// of its OpenTelemetry equivalent, or NaN if it is unset or invalid.
+func globalSampleRateFromEnv() float64 {
+	r := getDDorOtelConfig("sampleRate")
+	if r == "" {
+		return math.NaN()
+	}
+	sampleRate, err := strconv.ParseFloat(r, 64)
+	if err != nil {
+		log.Warn("ignoring DD_TRACE_SAMPLE_RATE, error: %v", err)
+		return math.NaN()
+	}
+	if sampleRate < 0.0 || sampleRate > 1.0 {
+		log.Warn("ignoring DD_TRACE_SAMPLE_RATE: out of range %f", sampleRate)
+		return math.NaN()
+	}
+	return sampleRate
+}
+
+// spanLimitFromEnv returns the limit of the number of span links or events set
+// by the env environment variable, or def if it is unset or invalid.
+func spanLimitFromEnv(env string, def int) int {
+	n := internal.IntEnv(env, def)
+	if n < 0 {
+		log.Warn("ignoring %s: negative value %d", env, n)
+		return def
+	}
+	return n
+}
+
+// rateLimitFromEnv returns the rate limit of DD_TRACE_RATE_LIMIT, and whether
+// it is set and valid, or the default rate limit otherwise.
+func rateLimitFromEnv() (float64, bool) {
+	v, ok := os.LookupEnv("DD_TRACE_RATE_LIMIT")
+	if !ok {
+		return defaultRateLimit, false
+	}
+	l, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Warn("DD_TRACE_RATE_LIMIT invalid, using default value %f: %v", defaultRateLimit, err)
+		return defaultRateLimit, false
+	}
+	if l < 0.0 {
+		log.Warn("DD_TRACE_RATE_LIMIT negative, using default value %f", defaultRateLimit)
+		return defaultRateLimit, false
+	}
+	return l, true
+}
+
+// propagatorFromEnv returns the propagator of the tracer when none is
+// configured, honoring the propagation styles and the maximum length of the
+// propagated tags of the environment.
+func propagatorFromEnv() Propagator {
+	envKey := "DD_TRACE_X_DATADOG_TAGS_MAX_LENGTH"
+	maxLen := internal.IntEnv(envKey, defaultMaxTagsHeaderLen)
+	if maxLen < 0 {
+		log.Warn("Invalid value %d for %s. Setting to 0.", maxLen, envKey)
+		maxLen = 0
+	}
+	if maxLen > maxPropagatedTagsLength {
+		log.Warn("Invalid value %d for %s. Maximum allowed is %d. Setting to %d.", maxLen, envKey, maxPropagatedTagsLength, maxPropagatedTagsLength)
+		maxLen = maxPropagatedTagsLength
+	}
+	return NewPropagator(&PropagatorConfig{
+		MaxTagsHeaderLen: maxLen,
+	})
+}
+
+// agentDisabled reports whether the tracer doesn't send its traces to the
+// trace-agent: when using stdout, when traces are disabled, when in CI
+// visibility agentless mode or when in dev mode without the agent.
+func (c *config) agentDisabled() bool {
+	return c.logToStdout || !c.enabled.current || c.ciVisibilityAgentless || (c.devMode != nil && !c.devMode.agent)
+}
+
 // loadAgentFeatures queries the trace-agent for its capabilities and updates
 // the tracer's behaviour.
 func loadAgentFeatures(agentDisabled bool, agentURL *url.URL, httpClient *http.Client) (features agentFeatures) {
@@ -772,16 +971,26 @@ func loadAgentFeatures(agentDisabled bool, agentURL *url.URL, httpClient *http.C
 		// there is no agent; all features off
 		return
 	}
-	resp, err := httpClient.Get(fmt.Sprintf("%s/info", agentURL))
+	features, err := fetchAgentFeatures(agentURL, httpClient)
 	if err != nil {
 		log.Error("Loading features: %v", err)
-		return
 	}
+	return features
+}
+
+// fetchAgentFeatures queries the /info endpoint of the trace-agent for its
+// capabilities. It returns an error if the agent couldn't be reached or its
+// answer couldn't be decoded.
+func fetchAgentFeatures(agentURL *url.URL, httpClient *http.Client) (features agentFeatures, err error) {
+	resp, err := httpClient.Get(fmt.Sprintf("%s/info", agentURL))
+	if err != nil {
+		return features, err
+	}
+	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusNotFound {
 		// agent is older than 7.28.0, features not discoverable
-		return
+		return features, nil
 	}
-	defer resp.Body.Close()
 	type infoResponse struct {
 		Endpoints          []string `json:"endpoints"`
 		ClientDropP0s      bool     `json:"client_drop_p0s"`
@@ -797,8 +1006,7 @@ func loadAgentFeatures(agentDisabled bool, agentURL *url.URL, httpClient *http.C
 
 	var info infoResponse
 	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
-		log.Error("Decoding features: %v", err)
-		return
+		return features, fmt.Errorf("decoding features: %w", err)
 	}
 
 	features.DropP0s = info.ClientDropP0s
@@ -811,13 +1019,15 @@ func loadAgentFeatures(agentDisabled bool, agentURL *url.URL, httpClient *http.C
 		switch endpoint {
 		case "/v0.6/stats":
 			features.Stats = true
+		case "/v0.5/traces":
+			features.traceV05 = true
 		}
 	}
 	features.featureFlags = make(map[string]struct{}, len(info.FeatureFlags))
 	for _, flag := range info.FeatureFlags {
 		features.featureFlags[flag] = struct{}{}
 	}
-	return features
+	return features, nil
 }
 
 // MarkIntegrationImported labels the given integration as imported
@@ -853,11 +1063,23 @@ func (c *config) loadContribIntegrations(deps []*debug.Module) {
 }
 
 func (c *config) canComputeStats() bool {
-	return c.agent.Stats && (c.HasFeature("discovery") || c.statsComputationEnabled)
+	return c.agentFeatures().Stats && (c.HasFeature("discovery") || c.statsComputationEnabled)
 }
 
 func (c *config) canDropP0s() bool {
-	return c.canComputeStats() && c.agent.DropP0s
+	return c.canComputeStats() && c.agentFeatures().DropP0s
+}
+
+// canUseTraceV05 reports whether the traces can be sent with the v0.5 format:
+// when it is enabled, advertised by the agent, and wasn't rejected by it.
+func (c *config) canUseTraceV05() bool {
+	return c.traceProtocol == traceProtocolV05 && c.agentFeatures().traceV05 && !c.traceV05Rejected.Load()
+}
+
+// spanEventsAvailable reports whether the span events can be sent in their own
+// field, rather than as a meta.
+func (c *config) spanEventsAvailable() bool {
+	return c.agentFeatures().spanEventsAvailable && !c.canUseTraceV05()
 }
 
 func statsTags(c *config) []string {
@@ -910,6 +1132,39 @@ func WithAppSecEnabled(enabled bool) StartOption {
 	}
 }
 
+// AppSecBlockingResponse customizes the response sent by AppSec to the requests it blocks, see
+// WithAppSecBlockingResponse.
+type AppSecBlockingResponse struct {
+	// StatusCode is the status code of the response, 403 when zero. When Location is set, it is
+	// the status code of the redirection, 303 when zero or not a redirection code.
+	StatusCode int
+	// Type is the type of the blocked page: "json", "html", or "auto" (the default) to select it
+	// from the Accept header of the request.
+	Type string
+	// Location, when set, redirects the blocked requests to this URL instead of sending them the
+	// blocked page.
+	Location string
+	// HTMLTemplate and JSONTemplate, when set, replace the default HTML and JSON blocked pages.
+	// They take precedence over the files configured with DD_APPSEC_HTTP_BLOCKED_TEMPLATE_HTML and
+	// DD_APPSEC_HTTP_BLOCKED_TEMPLATE_JSON.
+	HTMLTemplate []byte
+	JSONTemplate []byte
+}
+
+// WithAppSecBlockingResponse customizes the response sent by AppSec to the requests it blocks,
+// instead of the default blocked page with the 403 status code.
+func WithAppSecBlockingResponse(r AppSecBlockingResponse) StartOption {
+	return func(c *config) {
+		c.appsecStartOptions = append(c.appsecStartOptions, appsecconfig.WithBlockingResponse(appsecconfig.BlockingResponse{
+			StatusCode:   r.StatusCode,
+			Type:         r.Type,
+			Location:     r.Location,
+			HTMLTemplate: r.HTMLTemplate,
+			JSONTemplate: r.JSONTemplate,
+		}))
+	}
+}
+
 // WithFeatureFlags specifies a set of feature flags to enable. Please take into account
 // that most, if not all features flags are considered to be experimental and result in
 // unexpected bugs.
@@ -944,6 +1199,31 @@ func WithDebugStack(enabled bool) StartOption {
 	}
 }
 
+// WithExceptionReplay enables or disables the capture of the variables of the errors annotated
+// with CaptureVariables, which is disabled by default. It overrides DD_EXCEPTION_REPLAY_ENABLED.
+func WithExceptionReplay(enabled bool) StartOption {
+	return func(c *config) {
+		c.exceptionReplay = enabled
+	}
+}
+
+// WithDeterministicMode makes the tracer behave the same from a run to another, for
+// reproducible tests and fuzzing of instrumentation code. It is not meant for production:
+//
+//   - The span and trace IDs are generated from seed, so that the same spans started in
+//     the same order get the same IDs. The 128-bit trace IDs have no upper bits, as they
+//     are derived from the start time.
+//   - The sampling rate limiters, whose decisions depend on the time, allow all traces and
+//     spans. The decisions of the sampling rates only depend on the IDs.
+//   - The traces are only flushed when the payload is full, and by Flush and Stop, instead
+//     of every flush interval.
+func WithDeterministicMode(seed uint64) StartOption {
+	return func(c *config) {
+		c.deterministic = true
+		c.deterministicSeed = seed
+	}
+}
+
 // WithDebugMode enables debug mode on the tracer, resulting in more verbose logging.
 func WithDebugMode(enabled bool) StartOption {
 	return func(c *config) {
@@ -1124,6 +1404,18 @@ func WithHTTPClient(client *http.Client) StartOption {
 	}
 }
 
+// WithAPIKeyResolver specifies a function resolving the API key sent to the
+// Datadog intake by the agentless features: CI Visibility agentless mode, and
+// the telemetry sent without an agent. It allows fetching the key from a secret
+// manager and following its rotations, rather than reading it from DD_API_KEY
+// at startup. The key is resolved when it is first needed, and again when it
+// is rejected by the intake.
+func WithAPIKeyResolver(fn func(ctx context.Context) (string, error)) StartOption {
+	return func(c *config) {
+		c.apiKeyResolver = apikey.NewResolver(fn)
+	}
+}
+
 // WithUDS configures the HTTP client to dial the Datadog Agent via the specified Unix Domain Socket path.
 func WithUDS(socketPath string) StartOption {
 	return func(c *config) {
@@ -1158,12 +1450,40 @@ func WithAnalyticsRate(rate float64) StartOption {
 }
 
 // WithRuntimeMetrics enables automatic collection of runtime metrics every 10 seconds.
+// The metrics are read from the runtime/metrics package and reported under the
+// "runtime.go.metrics." prefix, as with DD_RUNTIME_METRICS_V2_ENABLED.
 func WithRuntimeMetrics() StartOption {
 	return func(cfg *config) {
 		cfg.runtimeMetrics = true
 	}
 }
 
+// WithRuntimeMetricsV2Filter selects the runtime metrics v2 which are emitted,
+// when enabled with WithRuntimeMetrics or DD_RUNTIME_METRICS_V2_ENABLED. The
+// metrics are selected by their Datadog name, e.g.
+// "runtime.go.metrics.gc_pauses.seconds", and the patterns may contain "*"
+// wildcards, e.g. "runtime.go.metrics.sched_*". When allow is empty, all the
+// metrics are emitted except the ones matching deny. Otherwise, only the
+// metrics matching allow and not matching deny are emitted. This setting can
+// also be configured with the comma-separated patterns of
+// DD_RUNTIME_METRICS_V2_ALLOWLIST and DD_RUNTIME_METRICS_V2_DENYLIST.
+func WithRuntimeMetricsV2Filter(allow, deny []string) StartOption {
+	return func(cfg *config) {
+		cfg.runtimeMetricsV2Filter = runtimeMetricsFilter{allow: allow, deny: deny}
+	}
+}
+
+// WithSpanDurationMetrics enables the aggregation of the durations of all finished
+// spans, sampled or not, into a sketch per operation name and resource. Their
+// quantiles are sent every 10 seconds as the "datadog.tracer.span_duration" metric,
+// tagged with the span name and a hash of its resource. This setting can also be
+// configured by setting DD_TRACE_SPAN_DURATION_METRICS_ENABLED to true.
+func WithSpanDurationMetrics() StartOption {
+	return func(cfg *config) {
+		cfg.spanDurationMetrics = true
+	}
+}
+
 // WithDogstatsdAddr specifies the address to connect to for sending metrics to the Datadog
 // Agent. It should be a "host:port" string, or the path to a unix domain socket.If not set, it
 // attempts to determine the address of the statsd service according to the following rules:
@@ -1179,6 +1499,19 @@ func WithDogstatsdAddr(addr string) StartOption {
 	}
 }
 
+// WithDogstatsdClient specifies the statsd client used by the tracer to send its
+// health metrics, runtime metrics and Data Streams metrics, instead of creating
+// its own client from the resolved Dogstatsd address. The tracer does not close
+// the provided client when it is stopped.
+func WithDogstatsdClient(client statsd.ClientInterface) StartOption {
+	return func(cfg *config) {
+		if client == nil {
+			return
+		}
+		cfg.statsdClient = internal.WrapStatsdClient(client)
+	}
+}
+
 // WithSamplingRules specifies the sampling rates to apply to spans based on the
 // provided rules.
 func WithSamplingRules(rules []SamplingRule) StartOption {
@@ -1274,6 +1607,77 @@ func WithDebugSpansMode(timeout time.Duration) StartOption {
 	}
 }
 
+// WithStackTraces enables the capture of the stack from which spans are started,
+// up to depth frames, excluding the frames of the tracer itself. The stack is
+// added as the "span.creation_stack" tag to spans lasting longer than the threshold
+// set with WithStackTracesThreshold, which defaults to 1 second. This helps locating
+// which call path produced a given slow span when the same operation is started
+// from many places. This setting can also be configured by setting
+// DD_TRACE_SPAN_STACK_TRACES_DEPTH. Capturing stacks adds overhead to every started
+// span, and is disabled by default.
+func WithStackTraces(depth int) StartOption {
+	return func(c *config) {
+		c.spanStackDepth = depth
+	}
+}
+
+// WithStackTracesThreshold sets the minimum duration of a span for the creation stack
+// captured with WithStackTraces to be added to it. This setting can also be configured
+// by setting DD_TRACE_SPAN_STACK_TRACES_THRESHOLD.
+func WithStackTracesThreshold(d time.Duration) StartOption {
+	return func(c *config) {
+		c.spanStackThreshold = d
+	}
+}
+
+// WithSpanLinksLimit sets the maximum number of span links sent with each span,
+// the links added beyond it being dropped. It defaults to 128, and can also be
+// configured by setting DD_TRACE_SPAN_LINKS_MAX. The number of links dropped
+// from a span is added to it as the "_dd.span_links.dropped" metric.
+func WithSpanLinksLimit(n int) StartOption {
+	return func(c *config) {
+		c.maxSpanLinks = max(n, 0)
+	}
+}
+
+// WithSpanEventsLimit sets the maximum number of span events sent with each
+// span, the events added beyond it being dropped. It defaults to 128, and can
+// also be configured by setting DD_TRACE_SPAN_EVENTS_MAX. The number of events
+// dropped from a span is added to it as the "_dd.span_events.dropped" metric.
+func WithSpanEventsLimit(n int) StartOption {
+	return func(c *config) {
+		c.maxSpanEvents = max(n, 0)
+	}
+}
+
+// WithFlushInterval sets the interval at which finished traces are flushed to the
+// Datadog Agent. It defaults to 2 seconds, and can also be configured by setting
+// DD_TRACE_FLUSH_INTERVAL. Smaller intervals reduce the time it takes for traces
+// to be visible, for example in CI environments, at the cost of more requests.
+// Unless disabled by setting DD_TRACE_FLUSH_JITTER_ENABLED to false, the first
+// flush is delayed by a random duration of up to the interval, so that processes
+// started at the same time do not flush in sync.
+func WithFlushInterval(d time.Duration) StartOption {
+	return func(c *config) {
+		if d <= 0 {
+			log.Warn("WithFlushInterval: %s is not a valid interval, ignoring", d)
+			return
+		}
+		c.flushInterval = d
+	}
+}
+
+// WithParentBasedSampling makes the sampling decisions received from upstream services
+// final: traces continued from an extracted span context keep the propagated sampling
+// priority, and sampling rules, rates and manual overrides only apply to traces started
+// by this service. It prevents partial traces caused by services re-sampling the middle
+// of a trace. It can also be enabled by setting DD_TRACE_SAMPLER to "parentbased".
+func WithParentBasedSampling() StartOption {
+	return func(c *config) {
+		c.parentBasedSampling = true
+	}
+}
+
 // WithPartialFlushing enables flushing of partially finished traces.
 // This is done after "numSpans" have finished in a single local trace at
 // which point all finished spans in that trace will be flushed, freeing up
@@ -241,7 +241,7 @@ func TestLoadAgentFeatures(t *testing.T) {
 
 	t.Run("OK", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-			w.Write([]byte(`{"endpoints":["/v0.6/stats"],"feature_flags":["a","b"],"client_drop_p0s":true,"obfuscation_version":2,"peer_tags":["peer.hostname"],"config": {"statsd_port":8999}}`))
+			w.Write([]byte(`{"endpoints":["/v0.6/stats","/v0.5/traces"],"feature_flags":["a","b"],"client_drop_p0s":true,"obfuscation_version":2,"peer_tags":["peer.hostname"],"config": {"statsd_port":8999}}`))
 		}))
 		defer srv.Close()
 		cfg, err := newConfig(WithAgentAddr(strings.TrimPrefix(srv.URL, "http://")), WithAgentTimeout(2))
@@ -257,6 +257,7 @@ func TestLoadAgentFeatures(t *testing.T) {
 		assert.True(t, cfg.agent.HasFlag("b"))
 		assert.EqualValues(t, cfg.agent.peerTags, []string{"peer.hostname"})
 		assert.Equal(t, 2, cfg.agent.obfuscationVersion)
+		assert.True(t, cfg.agent.traceV05)
 	})
 
 	t.Run("discovery", func(t *testing.T) {
@@ -293,7 +294,7 @@ func TestAgentIntegration(t *testing.T) {
 		defer clearIntegrationsForTests()
 
 		cfg.loadContribIntegrations(nil)
-		assert.Equal(t, 53, len(cfg.integrations))
+		assert.Equal(t, 74, len(cfg.integrations))
 		for integrationName, v := range cfg.integrations {
 			assert.False(t, v.Instrumented, "integrationName=%s", integrationName)
 		}
@@ -2020,3 +2021,40 @@ func TestNoHTTPClientOverride(t *testing.T) {
 		assert.Equal(30*time.Second, c.httpClient.Timeout)
 	})
 }
+
+func TestWithFlushInterval(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		c, err := newConfig()
+		require.NoError(t, err)
+		assert.Equal(t, defaultFlushInterval, c.flushInterval)
+		assert.True(t, c.flushJitter)
+	})
+
+	t.Run("option", func(t *testing.T) {
+		c, err := newConfig(WithFlushInterval(100 * time.Millisecond))
+		require.NoError(t, err)
+		assert.Equal(t, 100*time.Millisecond, c.flushInterval)
+	})
+
+	t.Run("option-invalid", func(t *testing.T) {
+		c, err := newConfig(WithFlushInterval(0))
+		require.NoError(t, err)
+		assert.Equal(t, defaultFlushInterval, c.flushInterval)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("DD_TRACE_FLUSH_INTERVAL", "500ms")
+		t.Setenv("DD_TRACE_FLUSH_JITTER_ENABLED", "false")
+		c, err := newConfig()
+		require.NoError(t, err)
+		assert.Equal(t, 500*time.Millisecond, c.flushInterval)
+		assert.False(t, c.flushJitter)
+	})
+
+	t.Run("env-invalid", func(t *testing.T) {
+		t.Setenv("DD_TRACE_FLUSH_INTERVAL", "-1s")
+		c, err := newConfig()
+		require.NoError(t, err)
+		assert.Equal(t, defaultFlushInterval, c.flushInterval)
+	})
+}
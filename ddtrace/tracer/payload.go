@@ -57,6 +57,17 @@ type payload struct {
 
 	// reader is used for reading the contents of buf.
 	reader *bytes.Reader
+
+	// strings interns the strings of the spans when the traces are encoded
+	// with the v0.5 format, or is nil with the v0.4 format. See newPayloadV05.
+	strings *stringTable
+
+	// prefix holds the first bytes of the v0.5 format: the string table, read
+	// before the header.
+	prefix []byte
+
+	// prefixOff specifies the current read position on the prefix.
+	prefixOff int
 }
 
 var _ io.Reader = (*payload)(nil)
@@ -73,7 +84,11 @@ func newPayload() *payload {
 // push pushes a new item into the stream.
 func (p *payload) push(t spanList) error {
 	p.buf.Grow(t.Msgsize())
-	if err := msgp.Encode(&p.buf, t); err != nil {
+	if p.strings != nil {
+		if err := p.encodeV05(t); err != nil {
+			return err
+		}
+	} else if err := msgp.Encode(&p.buf, t); err != nil {
 		return err
 	}
 	atomic.AddUint32(&p.count, 1)
@@ -89,7 +104,19 @@ func (p *payload) itemCount() int {
 // size returns the payload size in bytes. After the first read the value becomes
 // inaccurate by up to 8 bytes.
 func (p *payload) size() int {
-	return p.buf.Len() + len(p.header) - p.off
+	size := p.buf.Len() + len(p.header) - p.off
+	if p.strings != nil {
+		size += 1 + p.strings.msgsize()
+	}
+	return size
+}
+
+// protocol returns the version of the format the traces are encoded with.
+func (p *payload) protocol() string {
+	if p.strings != nil {
+		return traceProtocolV05
+	}
+	return traceProtocolV04
 }
 
 // reset sets up the payload to be read a second time. It maintains the
@@ -97,6 +124,7 @@ func (p *payload) size() int {
 // reuse the payload for another set of traces.
 func (p *payload) reset() {
 	p.updateHeader()
+	p.prefixOff = 0
 	if p.reader != nil {
 		p.reader.Seek(0, 0)
 	}
@@ -106,6 +134,7 @@ func (p *payload) reset() {
 func (p *payload) clear() {
 	p.buf = bytes.Buffer{}
 	p.reader = nil
+	p.prefix = nil
 }
 
 // https://github.com/msgpack/msgpack/blob/master/spec.md#array-format-family
@@ -141,6 +170,17 @@ func (p *payload) Close() error {
 
 // Read implements io.Reader. It reads from the msgpack-encoded stream.
 func (p *payload) Read(b []byte) (n int, err error) {
+	if p.strings != nil {
+		if p.prefix == nil {
+			// no more traces are pushed once the payload is read.
+			p.prefix = p.strings.appendMsg(append(make([]byte, 0, 1+p.strings.msgsize()), msgpackArrayFix+2))
+		}
+		if p.prefixOff < len(p.prefix) {
+			n = copy(b, p.prefix[p.prefixOff:])
+			p.prefixOff += n
+			return n, nil
+		}
+	}
 	if p.off < len(p.header) {
 		// reading header
 		n = copy(b, p.header[p.off:])
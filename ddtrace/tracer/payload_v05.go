@@ -0,0 +1,184 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Versions of the formats the traces are sent to the agent with.
+const (
+	// traceProtocolV04 encodes the spans as maps, with their strings inline.
+	traceProtocolV04 = "v0.4"
+
+	// traceProtocolV05 encodes the spans as arrays, with their strings
+	// replaced by their index in a string table shared by all the traces of
+	// the payload. It makes the payloads of traces with repeated services,
+	// names, resources and tags much smaller.
+	traceProtocolV05 = "v0.5"
+)
+
+// newPayloadV05 returns a ready to use payload encoding the traces with the
+// v0.5 format: an array holding the string table followed by the traces.
+//
+//	[
+//	    [string, ...],
+//	    [[span, ...], ...],
+//	]
+//
+// Each span is an array of 12 items, the strings being indexes in the string table:
+//
+//	[service, name, resource, trace_id, span_id, parent_id, start, duration,
+//	    error, map[meta key]meta value, map[metric key]float64, type]
+//
+// The format has no fields for the span links, the span events and the meta
+// structs. The span links are always sent as a meta, and the span events are
+// sent as a meta when the tracer uses this format, but the meta structs are
+// dropped.
+func newPayloadV05() *payload {
+	p := newPayload()
+	p.strings = newStringTable()
+	return p
+}
+
+// encodeV05 encodes trace at the end of the payload with the v0.5 format.
+func (p *payload) encodeV05(trace spanList) error {
+	w := msgp.NewWriter(&p.buf)
+	if err := w.WriteArrayHeader(uint32(len(trace))); err != nil {
+		return err
+	}
+	for _, s := range trace {
+		if err := encodeSpanV05(w, s, p.strings); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// encodeSpanV05 encodes s with w, interning its strings in t.
+func encodeSpanV05(w *msgp.Writer, s *Span, t *stringTable) error {
+	if err := w.WriteArrayHeader(12); err != nil {
+		return err
+	}
+	for _, str := range []string{s.service, s.name, s.resource} {
+		if err := w.WriteUint32(t.add(str)); err != nil {
+			return err
+		}
+	}
+	for _, id := range []uint64{s.traceID, s.spanID, s.parentID} {
+		if err := w.WriteUint64(id); err != nil {
+			return err
+		}
+	}
+	for _, n := range []int64{s.start, s.duration} {
+		if err := w.WriteInt64(n); err != nil {
+			return err
+		}
+	}
+	if err := w.WriteInt32(s.error); err != nil {
+		return err
+	}
+	if err := w.WriteMapHeader(uint32(len(s.meta))); err != nil {
+		return err
+	}
+	for k, v := range s.meta {
+		if err := w.WriteUint32(t.add(k)); err != nil {
+			return err
+		}
+		if err := w.WriteUint32(t.add(v)); err != nil {
+			return err
+		}
+	}
+	if err := w.WriteMapHeader(uint32(len(s.metrics))); err != nil {
+		return err
+	}
+	for k, v := range s.metrics {
+		if err := w.WriteUint32(t.add(k)); err != nil {
+			return err
+		}
+		if err := w.WriteFloat64(v); err != nil {
+			return err
+		}
+	}
+	if len(s.metaStruct) > 0 {
+		log.Debug("Dropping the meta structs of span %d, not supported by the %s format.", s.spanID, traceProtocolV05)
+	}
+	return w.WriteUint32(t.add(s.spanType))
+}
+
+// stringTable interns the strings of the spans of a payload, assigning them
+// their index in the table.
+//
+// stringTable is not safe for concurrent use.
+type stringTable struct {
+	index   map[string]uint32
+	strings []string
+
+	// size holds the size of the msgpack encoding of strings.
+	size int
+}
+
+// newStringTable returns a string table holding the empty string at index 0,
+// as expected by the agent.
+func newStringTable() *stringTable {
+	t := &stringTable{index: make(map[string]uint32)}
+	t.add("")
+	return t
+}
+
+// add returns the index of s in the table, adding it if needed.
+func (t *stringTable) add(s string) uint32 {
+	if i, ok := t.index[s]; ok {
+		return i
+	}
+	i := uint32(len(t.strings))
+	t.index[s] = i
+	t.strings = append(t.strings, s)
+	t.size += stringMsgsize(s)
+	return i
+}
+
+// msgsize returns the size of the msgpack encoding of the table.
+func (t *stringTable) msgsize() int {
+	return arrayHeaderMsgsize(len(t.strings)) + t.size
+}
+
+// appendMsg appends the msgpack encoding of the table to b.
+func (t *stringTable) appendMsg(b []byte) []byte {
+	b = msgp.AppendArrayHeader(b, uint32(len(t.strings)))
+	for _, s := range t.strings {
+		b = msgp.AppendString(b, s)
+	}
+	return b
+}
+
+// arrayHeaderMsgsize returns the size of the msgpack header of an array of n items.
+func arrayHeaderMsgsize(n int) int {
+	switch {
+	case n <= 15:
+		return 1
+	case n <= 1<<16-1:
+		return 3
+	default:
+		return 5
+	}
+}
+
+// stringMsgsize returns the size of the msgpack encoding of s.
+func stringMsgsize(s string) int {
+	switch n := len(s); {
+	case n <= 31:
+		return 1 + n
+	case n <= 1<<8-1:
+		return 2 + n
+	case n <= 1<<16-1:
+		return 3 + n
+	default:
+		return 5 + n
+	}
+}
@@ -0,0 +1,206 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// spanV05 is a span decoded from the v0.5 format, with its strings resolved.
+type spanV05 struct {
+	service, name, resource   string
+	traceID, spanID, parentID uint64
+	start, duration           int64
+	error                     int32
+	meta                      map[string]string
+	metrics                   map[string]float64
+	spanType                  string
+}
+
+// decodeV05 decodes a payload encoded with the v0.5 format.
+func decodeV05(t *testing.T, data []byte) (table []string, traces [][]spanV05) {
+	r := msgp.NewReader(bytes.NewReader(data))
+	n, err := r.ReadArrayHeader()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+	n, err = r.ReadArrayHeader()
+	require.NoError(t, err)
+	for i := uint32(0); i < n; i++ {
+		s, err := r.ReadString()
+		require.NoError(t, err)
+		table = append(table, s)
+	}
+	str := func() string {
+		i, err := r.ReadUint32()
+		require.NoError(t, err)
+		require.Less(t, int(i), len(table))
+		return table[i]
+	}
+	ntraces, err := r.ReadArrayHeader()
+	require.NoError(t, err)
+	for i := uint32(0); i < ntraces; i++ {
+		nspans, err := r.ReadArrayHeader()
+		require.NoError(t, err)
+		var trace []spanV05
+		for j := uint32(0); j < nspans; j++ {
+			n, err := r.ReadArrayHeader()
+			require.NoError(t, err)
+			require.EqualValues(t, 12, n)
+			var s spanV05
+			s.service, s.name, s.resource = str(), str(), str()
+			s.traceID, _ = r.ReadUint64()
+			s.spanID, _ = r.ReadUint64()
+			s.parentID, _ = r.ReadUint64()
+			s.start, _ = r.ReadInt64()
+			s.duration, _ = r.ReadInt64()
+			s.error, err = r.ReadInt32()
+			require.NoError(t, err)
+			n, err = r.ReadMapHeader()
+			require.NoError(t, err)
+			s.meta = make(map[string]string, n)
+			for k := uint32(0); k < n; k++ {
+				key := str()
+				s.meta[key] = str()
+			}
+			n, err = r.ReadMapHeader()
+			require.NoError(t, err)
+			s.metrics = make(map[string]float64, n)
+			for k := uint32(0); k < n; k++ {
+				key := str()
+				s.metrics[key], err = r.ReadFloat64()
+				require.NoError(t, err)
+			}
+			s.spanType = str()
+			trace = append(trace, s)
+		}
+		traces = append(traces, trace)
+	}
+	_, err = r.R.Peek(1)
+	assert.Equal(t, io.EOF, err, "trailing bytes")
+	return table, traces
+}
+
+func TestPayloadV05(t *testing.T) {
+	p := newPayloadV05()
+	assert.Equal(t, traceProtocolV05, p.protocol())
+	var pushed []spanList
+	for i := 0; i < 20; i++ {
+		list := newSpanList(i%5 + 1)
+		for _, s := range list {
+			s.service = "web"
+			s.resource = "GET /users"
+			s.spanType = "web"
+			s.meta["http.method"] = "GET"
+			s.metrics["rows"] = float64(i)
+		}
+		list[0].error = 1
+		require.NoError(t, p.push(list))
+		pushed = append(pushed, list)
+	}
+	size := p.size()
+	data, err := io.ReadAll(p)
+	require.NoError(t, err)
+	assert.Equal(t, size, len(data))
+
+	table, traces := decodeV05(t, data)
+	assert.Equal(t, "", table[0])
+	// the strings repeated across the spans are only encoded once.
+	seen := make(map[string]bool, len(table))
+	for _, str := range table {
+		assert.False(t, seen[str], "duplicate string %q", str)
+		seen[str] = true
+	}
+	assert.Less(t, len(table), 20)
+	require.Len(t, traces, len(pushed))
+	for i, trace := range traces {
+		require.Len(t, trace, len(pushed[i]))
+		for j, s := range trace {
+			want := pushed[i][j]
+			assert.Equal(t, want.name, s.name)
+			assert.Equal(t, "web", s.service)
+			assert.Equal(t, "GET /users", s.resource)
+			assert.Equal(t, "web", s.spanType)
+			assert.Equal(t, want.traceID, s.traceID)
+			assert.Equal(t, want.spanID, s.spanID)
+			assert.Equal(t, want.parentID, s.parentID)
+			assert.Equal(t, want.start, s.start)
+			assert.Equal(t, want.duration, s.duration)
+			assert.Equal(t, want.error, s.error)
+			assert.Equal(t, want.meta, s.meta)
+			assert.Equal(t, want.metrics, s.metrics)
+		}
+	}
+
+	// the payload can be read again to retry sending it.
+	p.reset()
+	again, err := io.ReadAll(p)
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestStringTableMsgsize(t *testing.T) {
+	st := newStringTable()
+	for _, n := range []int{0, 1, 31, 32, 255, 256, 1 << 16, 1 << 17} {
+		st.add(strings.Repeat("x", n))
+	}
+	for i := 0; i < 1<<16; i++ {
+		st.add(strings.Repeat("y", i%40) + string(rune('a'+i%26)) + strings.Repeat("z", i/26))
+	}
+	assert.Equal(t, st.msgsize(), len(st.appendMsg(nil)))
+	assert.Equal(t, uint32(0), st.add(""))
+}
+
+func TestTraceWriterV05Fallback(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/info":
+			w.Write([]byte(`{"endpoints":["/v0.4/traces","/v0.5/traces"]}`))
+		case "/v0.5/traces":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("DD_TRACE_API_VERSION", traceProtocolV05)
+	c, err := newConfig(WithAgentAddr(strings.TrimPrefix(srv.URL, "http://")), withNoopStats())
+	require.NoError(t, err)
+	require.True(t, c.canUseTraceV05())
+	assert.False(t, c.spanEventsAvailable())
+
+	var statsd statsdtest.TestStatsdClient
+	h := newAgentTraceWriter(c, newPrioritySampler(), &statsd)
+	assert.Equal(t, traceProtocolV05, h.payload.protocol())
+	h.add([]*Span{makeSpan(0)})
+	h.flush()
+	<-h.flushed()
+	assert.False(t, c.canUseTraceV05())
+
+	h.add([]*Span{makeSpan(0)})
+	assert.Equal(t, traceProtocolV04, h.payload.protocol())
+	h.flush()
+	<-h.flushed()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"/info", "/v0.5/traces", "/v0.4/traces"}, paths)
+}
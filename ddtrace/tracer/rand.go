@@ -8,12 +8,43 @@ package tracer
 import (
 	"math"
 	"math/rand/v2"
+	"sync"
+	"sync/atomic"
 )
 
+// deterministicRand is the source of the IDs while a tracer started with
+// WithDeterministicMode is running, and nil otherwise.
+var deterministicRand atomic.Pointer[lockedRand]
+
+// deterministicMode reports whether a tracer started with WithDeterministicMode
+// is running.
+func deterministicMode() bool {
+	return deterministicRand.Load() != nil
+}
+
+// lockedRand is a seeded source of random numbers safe for concurrent use.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func newLockedRand(seed uint64) *lockedRand {
+	return &lockedRand{r: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (l *lockedRand) Uint64() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Uint64()
+}
+
 func randUint64() uint64 {
+	if r := deterministicRand.Load(); r != nil {
+		return r.Uint64()
+	}
 	return rand.Uint64()
 }
 
 func generateSpanID(_ int64) uint64 {
-	return rand.Uint64() & math.MaxInt64
+	return randUint64() & math.MaxInt64
 }
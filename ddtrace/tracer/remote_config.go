@@ -256,6 +256,13 @@ func (t *tracer) dynamicInstrumentationRCUpdate(u remoteconfig.ProductUpdate) ma
 
 	diRCState.Lock()
 	for k, v := range u {
+		if v == nil {
+			// The probe was removed, stop passing its configuration.
+			log.Debug("Removed dynamic instrumentation RC configuration for %s\n", k)
+			delete(diRCState.state, k)
+			applyStatus[k] = state.ApplyStatus{State: state.ApplyStateAcknowledged}
+			continue
+		}
 		log.Debug("Received dynamic instrumentation RC configuration for %s\n", k)
 		applyStatus[k] = state.ApplyStatus{State: state.ApplyStateUnknown}
 		diRCState.state[k] = dynamicInstrumentationRCProbeConfig{
@@ -322,11 +329,11 @@ func (t *tracer) startRemoteConfig(rcConfig remoteconfig.ClientConfig) error {
 	var dynamicInstrumentationError, apmTracingError error
 
 	if t.config.dynamicInstrumentationEnabled {
+		// The probe configurations are only passed to Go Dynamic Instrumentation when it is enabled.
+		initalizeRC.Do(initalizeDynamicInstrumentationRemoteConfigState)
 		dynamicInstrumentationError = remoteconfig.Subscribe("LIVE_DEBUGGING", t.dynamicInstrumentationRCUpdate)
 	}
 
-	initalizeRC.Do(initalizeDynamicInstrumentationRemoteConfigState)
-
 	apmTracingError = remoteconfig.Subscribe(
 		state.ProductAPMTracing,
 		t.onRemoteConfigUpdate,
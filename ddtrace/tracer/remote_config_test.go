@@ -743,6 +743,27 @@ func TestStartRemoteConfig(t *testing.T) {
 	require.True(t, found)
 }
 
+func TestDynamicInstrumentationRCUpdate(t *testing.T) {
+	initalizeRC.Do(initalizeDynamicInstrumentationRemoteConfigState)
+	tracer, _, _, stop, err := startTestTracer(t)
+	require.Nil(t, err)
+	defer stop()
+
+	const path = "datadog/2/LIVE_DEBUGGING/logProbe_1/config"
+	statuses := tracer.dynamicInstrumentationRCUpdate(remoteconfig.ProductUpdate{path: []byte(`{"id":"1"}`)})
+	assert.Equal(t, state.ApplyStateUnknown, statuses[path].State)
+	diRCState.Lock()
+	assert.Equal(t, `{"id":"1"}`, diRCState.state[path].configContent)
+	diRCState.Unlock()
+
+	// The configuration of a removed probe isn't passed anymore.
+	statuses = tracer.dynamicInstrumentationRCUpdate(remoteconfig.ProductUpdate{path: nil})
+	assert.Equal(t, state.ApplyStateAcknowledged, statuses[path].State)
+	diRCState.Lock()
+	assert.NotContains(t, diRCState.state, path)
+	diRCState.Unlock()
+}
+
 func TestDeadLockIssue3541(t *testing.T) {
 	t.Setenv("DD_REMOTE_CONFIGURATION_ENABLED", "false")
 
@@ -21,6 +21,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 	"github.com/DataDog/dd-trace-go/v2/internal/samplernames"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
 )
 
 // rulesSampler holds instances of trace sampler and single span sampler, that are configured with the given set of rules.
@@ -132,7 +133,8 @@ type SamplingRule struct {
 	Rate float64
 
 	// MaxPerSecond specifies max number of spans per second that can be sampled per the rule.
-	// If not specified, the default is no limit.
+	// If not specified, the default is no limit for single span rules, and the global
+	// limit set by DD_TRACE_RATE_LIMIT for trace rules.
 	MaxPerSecond float64
 
 	// Resource specifies the regex pattern that a span resource must match.
@@ -170,7 +172,7 @@ func (sr *SamplingRule) EqualsFalseNegative(other *SamplingRule) bool {
 	if sr == nil {
 		return true
 	}
-	if sr.Rate != other.Rate || sr.ruleType != other.ruleType ||
+	if sr.Rate != other.Rate || sr.MaxPerSecond != other.MaxPerSecond || sr.ruleType != other.ruleType ||
 		!regexEqualsFalseNegative(sr.Service, other.Service) ||
 		!regexEqualsFalseNegative(sr.Name, other.Name) ||
 		!regexEqualsFalseNegative(sr.Resource, other.Resource) ||
@@ -268,11 +270,13 @@ func TraceSamplingRules(rules ...Rule) []SamplingRule {
 	var typ SamplingRuleType = SamplingRuleTrace
 	for _, r := range rules {
 		sr := SamplingRule{
-			Service:  globMatch(r.ServiceGlob),
-			Name:     globMatch(r.NameGlob),
-			Resource: globMatch(r.ResourceGlob),
-			Rate:     r.Rate,
-			ruleType: SamplingRuleTrace,
+			Service:      globMatch(r.ServiceGlob),
+			Name:         globMatch(r.NameGlob),
+			Resource:     globMatch(r.ResourceGlob),
+			Rate:         r.Rate,
+			ruleType:     SamplingRuleTrace,
+			MaxPerSecond: r.MaxPerSecond,
+			limiter:      newSingleSpanRateLimiter(r.MaxPerSecond),
 			globRule: &jsonRule{
 				Service:      r.ServiceGlob,
 				Name:         r.NameGlob,
@@ -345,7 +349,10 @@ func SpanSamplingRules(rules ...Rule) []SamplingRule {
 // The rate is used to determine if the span should be sampled, but an upper
 // limit can be defined using the DD_TRACE_RATE_LIMIT environment variable.
 // Its value is the number of spans to sample per second.
+// Rules specifying their own max_per_second are limited independently, using
+// that value instead.
 // Spans that matched the rules but exceeded the rate limit are not sampled.
+// The decisions of the rate limiters are reported to telemetry.
 type traceRulesSampler struct {
 	m          sync.RWMutex
 	rules      []SamplingRule // the rules to match spans with
@@ -440,7 +447,7 @@ func (rs *traceRulesSampler) sampleGlobalRate(span *Span) bool {
 	// being deprecated in favor of sampling rules.
 	// Note that this just preserves an existing behavior even though it is not correct.
 	sampler := samplernames.RuleRate
-	rs.applyRate(span, rate, time.Now(), sampler)
+	rs.applyLimitedRate(span, rate, time.Now(), sampler, nil)
 	return true
 }
 
@@ -458,10 +465,14 @@ func (rs *traceRulesSampler) sampleRules(span *Span) bool {
 	rate := rs.globalRate
 	rs.m.RUnlock()
 	sampler := samplernames.RuleRate
+	var limiter *rateLimiter
 	for _, rule := range rs.rules {
 		if rule.match(span) {
 			matched = true
 			rate = rule.Rate
+			if rule.MaxPerSecond > 0 && rule.limiter != nil {
+				limiter = rule.limiter
+			}
 			if rule.Provenance == Customer {
 				sampler = samplernames.RemoteUserRule
 			} else if rule.Provenance == Dynamic {
@@ -476,11 +487,18 @@ func (rs *traceRulesSampler) sampleRules(span *Span) bool {
 		return false
 	}
 
-	rs.applyRate(span, rate, time.Now(), sampler)
+	rs.applyLimitedRate(span, rate, time.Now(), sampler, limiter)
 	return true
 }
 
 func (rs *traceRulesSampler) applyRate(span *Span, rate float64, now time.Time, sampler samplernames.SamplerName) {
+	rs.applyLimitedRate(span, rate, now, sampler, nil)
+}
+
+// applyLimitedRate samples span at the given rate, limiting the volume of sampled
+// spans with limiter, or the global rate limiter if nil. Its decisions are reported
+// to telemetry, tagged with the limiter which took them.
+func (rs *traceRulesSampler) applyLimitedRate(span *Span, rate float64, now time.Time, sampler samplernames.SamplerName, limiter *rateLimiter) {
 	span.mu.Lock()
 	defer span.mu.Unlock()
 
@@ -498,13 +516,21 @@ func (rs *traceRulesSampler) applyRate(span *Span, rate float64, now time.Time,
 		return
 	}
 
-	sampled, rate := rs.limiter.allowOne(now)
+	limiterTag := "limiter:rule"
+	if limiter == nil {
+		limiter = rs.limiter
+		limiterTag = "limiter:global"
+	}
+	sampled, rate := limiter.allowOne(now)
+	decision := "decision:allowed"
 	if sampled {
 		span.setSamplingPriorityLocked(ext.PriorityUserKeep, sampler)
 	} else {
+		decision = "decision:dropped"
 		span.setSamplingPriorityLocked(ext.PriorityUserReject, sampler)
 	}
 	span.setMetric(keyRulesSamplerLimiterRate, rate)
+	telemetry.Count(telemetry.NamespaceTracers, "sampling.rate_limiter", []string{limiterTag, decision}).Submit(1)
 }
 
 // limit returns the rate limit set in the rules sampler, controlled by DD_TRACE_RATE_LIMIT, and
@@ -601,6 +627,10 @@ type rateLimiter struct {
 // effective rate at the time it is called. The effective rate is computed by averaging the rate
 // for the previous second with the current rate
 func (r *rateLimiter) allowOne(now time.Time) (bool, float64) {
+	if deterministicMode() {
+		// The decisions of the limiter depend on the time.
+		return true, 1
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if d := now.Sub(r.prevTime); d >= time.Second {
@@ -645,6 +675,12 @@ func newSingleSpanRateLimiter(mps float64) *rateLimiter {
 // globMatch compiles pattern string into glob format, i.e. regular expressions with only '?'
 // and '*' treated as regex metacharacters.
 func globMatch(pattern string) *regexp.Regexp {
+	return globMatchCase(pattern, false)
+}
+
+// globMatchCase is like globMatch, but the returned expression only ignores the case
+// of the matched strings when caseSensitive is false.
+func globMatchCase(pattern string, caseSensitive bool) *regexp.Regexp {
 	if pattern == "" || pattern == "*" {
 		return nil
 	}
@@ -654,9 +690,32 @@ func globMatch(pattern string) *regexp.Regexp {
 	pattern = strings.Replace(pattern, "\\?", ".", -1)
 	pattern = strings.Replace(pattern, "\\*", ".*", -1)
 	// pattern must match an entire string
+	if caseSensitive {
+		return regexp.MustCompile(fmt.Sprintf("^%s$", pattern))
+	}
 	return regexp.MustCompile(fmt.Sprintf("(?i)^%s$", pattern))
 }
 
+// ruleMatcher returns the expression matching the field of a sampling rule, given
+// either as a glob or as a regular expression, which are mutually exclusive. Globs
+// ignore case and regular expressions don't, unless caseSensitive is set.
+func ruleMatcher(field, glob, expr string, caseSensitive *bool) (*regexp.Regexp, error) {
+	if expr == "" {
+		return globMatchCase(glob, caseSensitive != nil && *caseSensitive), nil
+	}
+	if glob != "" {
+		return nil, fmt.Errorf("%s and %s_re can't be both set", field, field)
+	}
+	if caseSensitive != nil && !*caseSensitive {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s_re %q: %v", field, expr, err)
+	}
+	return re, nil
+}
+
 // samplingRulesFromEnv parses sampling rules from
 // the DD_TRACE_SAMPLING_RULES, DD_TRACE_SAMPLING_RULES_FILE
 // DD_SPAN_SAMPLING_RULES and DD_SPAN_SAMPLING_RULES_FILE environment variables.
@@ -724,14 +783,18 @@ func (sr *SamplingRule) UnmarshalJSON(b []byte) error {
 }
 
 type jsonRule struct {
-	Service      string            `json:"service"`
-	Name         string            `json:"name"`
-	Rate         json.Number       `json:"sample_rate"`
-	MaxPerSecond float64           `json:"max_per_second"`
-	Resource     string            `json:"resource"`
-	Tags         map[string]string `json:"tags"`
-	Type         *SamplingRuleType `json:"type,omitempty"`
-	Provenance   provenance        `json:"provenance,omitempty"`
+	Service       string            `json:"service"`
+	ServiceRe     string            `json:"service_re,omitempty"`
+	Name          string            `json:"name"`
+	NameRe        string            `json:"name_re,omitempty"`
+	Rate          json.Number       `json:"sample_rate"`
+	MaxPerSecond  float64           `json:"max_per_second"`
+	Resource      string            `json:"resource"`
+	ResourceRe    string            `json:"resource_re,omitempty"`
+	Tags          map[string]string `json:"tags"`
+	CaseSensitive *bool             `json:"case_sensitive,omitempty"`
+	Type          *SamplingRuleType `json:"type,omitempty"`
+	Provenance    provenance        `json:"provenance,omitempty"`
 }
 
 func (j jsonRule) String() string {
@@ -739,9 +802,15 @@ func (j jsonRule) String() string {
 	if j.Service != "" {
 		s = append(s, fmt.Sprintf("Service:%s", j.Service))
 	}
+	if j.ServiceRe != "" {
+		s = append(s, fmt.Sprintf("ServiceRe:%s", j.ServiceRe))
+	}
 	if j.Name != "" {
 		s = append(s, fmt.Sprintf("Name:%s", j.Name))
 	}
+	if j.NameRe != "" {
+		s = append(s, fmt.Sprintf("NameRe:%s", j.NameRe))
+	}
 	if j.Rate != "" {
 		s = append(s, fmt.Sprintf("Rate:%s", j.Rate))
 	}
@@ -751,9 +820,15 @@ func (j jsonRule) String() string {
 	if j.Resource != "" {
 		s = append(s, fmt.Sprintf("Resource:%s", j.Resource))
 	}
+	if j.ResourceRe != "" {
+		s = append(s, fmt.Sprintf("ResourceRe:%s", j.ResourceRe))
+	}
 	if len(j.Tags) != 0 {
 		s = append(s, fmt.Sprintf("Tags:%v", j.Tags))
 	}
+	if j.CaseSensitive != nil {
+		s = append(s, fmt.Sprintf("CaseSensitive:%t", *j.CaseSensitive))
+	}
 	if j.Type != nil {
 		s = append(s, fmt.Sprintf("Type: %v", *j.Type))
 	}
@@ -800,16 +875,31 @@ func validateRules(jsonRules []jsonRule, spanType SamplingRuleType) ([]SamplingR
 			)
 			continue
 		}
+		service, err := ruleMatcher("service", v.Service, v.ServiceRe, v.CaseSensitive)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("at index %d: ignoring rule %s: %v", i, v.String(), err))
+			continue
+		}
+		name, err := ruleMatcher("name", v.Name, v.NameRe, v.CaseSensitive)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("at index %d: ignoring rule %s: %v", i, v.String(), err))
+			continue
+		}
+		resource, err := ruleMatcher("resource", v.Resource, v.ResourceRe, v.CaseSensitive)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("at index %d: ignoring rule %s: %v", i, v.String(), err))
+			continue
+		}
 		tagGlobs := make(map[string]*regexp.Regexp, len(v.Tags))
 		for k, g := range v.Tags {
-			tagGlobs[k] = globMatch(g)
+			tagGlobs[k] = globMatchCase(g, v.CaseSensitive != nil && *v.CaseSensitive)
 		}
 		rules = append(rules, SamplingRule{
-			Service:      globMatch(v.Service),
-			Name:         globMatch(v.Name),
+			Service:      service,
+			Name:         name,
 			Rate:         rate,
 			MaxPerSecond: v.MaxPerSecond,
-			Resource:     globMatch(v.Resource),
+			Resource:     resource,
 			Tags:         tagGlobs,
 			Provenance:   v.Provenance,
 			ruleType:     spanType,
@@ -826,19 +916,27 @@ func validateRules(jsonRules []jsonRule, spanType SamplingRuleType) ([]SamplingR
 // MarshalJSON implements the json.Marshaler interface.
 func (sr SamplingRule) MarshalJSON() ([]byte, error) {
 	s := struct {
-		Service      string            `json:"service,omitempty"`
-		Name         string            `json:"name,omitempty"`
-		Resource     string            `json:"resource,omitempty"`
-		Rate         float64           `json:"sample_rate"`
-		Tags         map[string]string `json:"tags,omitempty"`
-		MaxPerSecond *float64          `json:"max_per_second,omitempty"`
-		Provenance   string            `json:"provenance,omitempty"`
+		Service       string            `json:"service,omitempty"`
+		ServiceRe     string            `json:"service_re,omitempty"`
+		Name          string            `json:"name,omitempty"`
+		NameRe        string            `json:"name_re,omitempty"`
+		Resource      string            `json:"resource,omitempty"`
+		ResourceRe    string            `json:"resource_re,omitempty"`
+		Rate          float64           `json:"sample_rate"`
+		Tags          map[string]string `json:"tags,omitempty"`
+		CaseSensitive *bool             `json:"case_sensitive,omitempty"`
+		MaxPerSecond  *float64          `json:"max_per_second,omitempty"`
+		Provenance    string            `json:"provenance,omitempty"`
 	}{}
 	if sr.globRule != nil {
 		s.Service = sr.globRule.Service
+		s.ServiceRe = sr.globRule.ServiceRe
 		s.Name = sr.globRule.Name
+		s.NameRe = sr.globRule.NameRe
 		s.Resource = sr.globRule.Resource
+		s.ResourceRe = sr.globRule.ResourceRe
 		s.Tags = sr.globRule.Tags
+		s.CaseSensitive = sr.globRule.CaseSensitive
 	} else {
 		if sr.Service != nil {
 			s.Service = sr.Service.String()
@@ -0,0 +1,34 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"time"
+
+	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
+)
+
+// runtimeEnvRefresh is how often the runtime environment tags are read again,
+// as GOMAXPROCS, the GC settings and the cgroup limits can change while the
+// process runs.
+const runtimeEnvRefresh = 10 * time.Second
+
+// runtimeEnv holds the runtime environment tags, and when they were read.
+type runtimeEnv struct {
+	read time.Time
+	tags map[string]float64
+}
+
+// runtimeEnvTags returns the runtime settings and the cgroup limits of the
+// process the traces are tagged with, read at most every runtimeEnvRefresh.
+func (t *tracer) runtimeEnvTags() map[string]float64 {
+	if env := t.runtimeEnv.Load(); env != nil && time.Since(env.read) < runtimeEnvRefresh {
+		return env.tags
+	}
+	env := &runtimeEnv{read: time.Now(), tags: globalinternal.RuntimeEnvTags()}
+	t.runtimeEnv.Store(env)
+	return env.tags
+}
@@ -0,0 +1,106 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
+)
+
+// cpuQuotaMetric is the name of the runtime metrics v2 gauge reporting the
+// CPU quota of the cgroup of the process, in CPUs. GOMAXPROCS is reported by
+// runtime/metrics as runtime.go.metrics.sched_gomaxprocs.threads.
+const cpuQuotaMetric = "runtime.go.metrics.cgroup_cpu_quota.cpus"
+
+// runtimeMetricsFilter selects the runtime metrics v2 which are emitted, by
+// their Datadog name, e.g. "runtime.go.metrics.gc_pauses.seconds". The
+// patterns may contain "*" wildcards, as supported by path.Match.
+type runtimeMetricsFilter struct {
+	// allow holds the patterns of the emitted metrics. All the metrics are
+	// emitted when it is empty.
+	allow []string
+	// deny holds the patterns of the metrics which aren't emitted, even if
+	// allowed.
+	deny []string
+}
+
+// splitMetricPatterns splits the comma- or space-separated patterns of v.
+func splitMetricPatterns(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// matchMetric reports whether the metric name matches one of the patterns.
+func matchMetric(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// emit reports whether the metric name is emitted.
+func (f runtimeMetricsFilter) emit(name string) bool {
+	if len(f.allow) > 0 && !matchMetric(f.allow, name) {
+		return false
+	}
+	return !matchMetric(f.deny, name)
+}
+
+// filteredStatsdClient is the statsd client given to the runtime metrics v2
+// collector, dropping the metrics which aren't selected by the filter.
+type filteredStatsdClient struct {
+	globalinternal.StatsdClient
+	filter runtimeMetricsFilter
+}
+
+func (c filteredStatsdClient) GaugeWithTimestamp(name string, value float64, tags []string, rate float64, timestamp time.Time) error {
+	if !c.filter.emit(name) {
+		return nil
+	}
+	return c.StatsdClient.GaugeWithTimestamp(name, value, tags, rate, timestamp)
+}
+
+func (c filteredStatsdClient) CountWithTimestamp(name string, value int64, tags []string, rate float64, timestamp time.Time) error {
+	if !c.filter.emit(name) {
+		return nil
+	}
+	return c.StatsdClient.CountWithTimestamp(name, value, tags, rate, timestamp)
+}
+
+func (c filteredStatsdClient) DistributionSamples(name string, values []float64, tags []string, rate float64) error {
+	if !c.filter.emit(name) {
+		return nil
+	}
+	return c.StatsdClient.DistributionSamples(name, values, tags, rate)
+}
+
+// reportCPUQuota periodically reports the CPU quota of the cgroup of the
+// process, along with the runtime metrics v2, at the given interval. Nothing
+// is reported when the process has no CPU quota.
+func (t *tracer) reportCPUQuota(interval time.Duration) {
+	filter := t.config.runtimeMetricsV2Filter
+	if !filter.emit(cpuQuotaMetric) {
+		return
+	}
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			if quota, ok := globalinternal.CPUQuota(); ok {
+				t.statsd.Gauge(cpuQuotaMetric, quota, nil, 1)
+			}
+		case <-t.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeMetricsFilter(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		filter  runtimeMetricsFilter
+		emitted []string
+	}{
+		{
+			name:    "default",
+			emitted: []string{"runtime.go.metrics.gc_pauses.seconds", "runtime.go.metrics.sched_latencies.seconds", "runtime.go.metrics.gc_heap_allocs.bytes"},
+		},
+		{
+			name:    "allow",
+			filter:  runtimeMetricsFilter{allow: []string{"runtime.go.metrics.gc_*"}},
+			emitted: []string{"runtime.go.metrics.gc_pauses.seconds", "runtime.go.metrics.gc_heap_allocs.bytes"},
+		},
+		{
+			name:    "deny",
+			filter:  runtimeMetricsFilter{deny: []string{"runtime.go.metrics.gc_heap_*"}},
+			emitted: []string{"runtime.go.metrics.gc_pauses.seconds", "runtime.go.metrics.sched_latencies.seconds"},
+		},
+		{
+			name:    "allow-deny",
+			filter:  runtimeMetricsFilter{allow: []string{"runtime.go.metrics.gc_*"}, deny: []string{"*.bytes"}},
+			emitted: []string{"runtime.go.metrics.gc_pauses.seconds"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var statsd statsdtest.TestStatsdClient
+			c := filteredStatsdClient{StatsdClient: &statsd, filter: tt.filter}
+			for _, name := range []string{"runtime.go.metrics.gc_pauses.seconds", "runtime.go.metrics.sched_latencies.seconds", "runtime.go.metrics.gc_heap_allocs.bytes"} {
+				c.GaugeWithTimestamp(name, 1, nil, 1, time.Now())
+			}
+			var emitted []string
+			for _, c := range statsd.GaugeWithTimestampCalls() {
+				emitted = append(emitted, c.Name())
+			}
+			assert.ElementsMatch(t, tt.emitted, emitted)
+		})
+	}
+}
+
+func TestWithRuntimeMetricsV2Filter(t *testing.T) {
+	t.Setenv("DD_RUNTIME_METRICS_V2_ALLOWLIST", "runtime.go.metrics.gc_*, runtime.go.metrics.sched_*")
+	t.Setenv("DD_RUNTIME_METRICS_V2_DENYLIST", "*.bytes")
+	c, err := newConfig(WithAgentTimeout(2))
+	assert.NoError(t, err)
+	assert.Equal(t, runtimeMetricsFilter{
+		allow: []string{"runtime.go.metrics.gc_*", "runtime.go.metrics.sched_*"},
+		deny:  []string{"*.bytes"},
+	}, c.runtimeMetricsV2Filter)
+
+	WithRuntimeMetricsV2Filter(nil, []string{"runtime.go.metrics.cgroup_*"})(c)
+	assert.False(t, c.runtimeMetricsV2Filter.emit(cpuQuotaMetric))
+}
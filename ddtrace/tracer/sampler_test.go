@@ -7,6 +7,7 @@ package tracer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
@@ -18,8 +19,11 @@ import (
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/internal/samplernames"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry/telemetrytest"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/time/rate"
 )
 
@@ -1243,6 +1247,41 @@ func TestSamplingRuleUnmarshal(t *testing.T) {
 		}
 	})
 
+	t.Run("regex", func(t *testing.T) {
+		rules, err := unmarshalSamplingRules([]byte(`[
+			{"resource_re": "^GET /api/v[0-9]+/users", "sample_rate": 0.5},
+			{"service_re": "^web-(a|b)$", "case_sensitive": false, "sample_rate": 0.1},
+			{"name": "HTTP.*", "case_sensitive": true, "sample_rate": 0.2}
+		]`), SamplingRuleTrace)
+		require.NoError(t, err)
+		require.Len(t, rules, 3)
+
+		assert.True(t, rules[0].Resource.MatchString("GET /api/v2/users/42"))
+		assert.False(t, rules[0].Resource.MatchString("get /api/v2/users/42"))
+		assert.False(t, rules[0].Resource.MatchString("GET /api/vX/users"))
+		assert.True(t, rules[1].Service.MatchString("WEB-A"))
+		assert.True(t, rules[2].Name.MatchString("HTTP.request"))
+		assert.False(t, rules[2].Name.MatchString("http.request"))
+
+		b, err := json.Marshal(rules[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `"resource_re":"^GET /api/v[0-9]+/users"`)
+	})
+
+	t.Run("regex-errors", func(t *testing.T) {
+		rules, err := unmarshalSamplingRules([]byte(`[
+			{"resource_re": "^GET /api/(v[0-9]+", "sample_rate": 0.5},
+			{"resource": "GET *", "resource_re": "^GET", "sample_rate": 0.5},
+			{"name": "web.request", "sample_rate": 1.0}
+		]`), SamplingRuleTrace)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at index 0")
+		assert.Contains(t, err.Error(), "invalid resource_re")
+		assert.Contains(t, err.Error(), "at index 1")
+		assert.Contains(t, err.Error(), "resource and resource_re can't be both set")
+		require.Len(t, rules, 1)
+		assert.True(t, rules[0].Name.MatchString("web.request"))
+	})
 }
 
 func TestRulesSamplerConcurrency(t *testing.T) {
@@ -1325,6 +1364,31 @@ func TestRulesSamplerInternals(t *testing.T) {
 		assert.Equal(1.0, span.metrics[keyRulesSamplerAppliedRate])
 		assert.Equal(0.75, span.metrics[keyRulesSamplerLimiterRate])
 	})
+
+	t.Run("rule-limit", func(t *testing.T) {
+		telemetryClient := new(telemetrytest.RecordClient)
+		defer telemetry.MockClient(telemetryClient)()
+		rules := TraceSamplingRules(
+			Rule{ServiceGlob: "limited-service", Rate: 1.0, MaxPerSecond: 1},
+			Rule{ServiceGlob: "test-service", Rate: 1.0},
+		)
+		rs := newRulesSampler(rules, nil, math.NaN(), 100)
+
+		// the rule's own limit applies, independently of the global one
+		span := newSpan("http.request", "limited-service", "", 0, 0, 0)
+		assert.True(t, rs.SampleTrace(span))
+		assert.EqualValues(t, ext.PriorityUserKeep, span.metrics[keySamplingPriority])
+		span = newSpan("http.request", "limited-service", "", 0, 0, 0)
+		assert.True(t, rs.SampleTrace(span))
+		assert.EqualValues(t, ext.PriorityUserReject, span.metrics[keySamplingPriority])
+		span = newSpan("http.request", "test-service", "", 0, 0, 0)
+		assert.True(t, rs.SampleTrace(span))
+		assert.EqualValues(t, ext.PriorityUserKeep, span.metrics[keySamplingPriority])
+
+		assert.Equal(t, 1.0, telemetryClient.Count(telemetry.NamespaceTracers, "sampling.rate_limiter", []string{"limiter:rule", "decision:allowed"}).Get())
+		assert.Equal(t, 1.0, telemetryClient.Count(telemetry.NamespaceTracers, "sampling.rate_limiter", []string{"limiter:rule", "decision:dropped"}).Get())
+		assert.Equal(t, 1.0, telemetryClient.Count(telemetry.NamespaceTracers, "sampling.rate_limiter", []string{"limiter:global", "decision:allowed"}).Get())
+	})
 }
 
 func TestSamplingLimiter(t *testing.T) {
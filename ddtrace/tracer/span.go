@@ -8,6 +8,7 @@
 package tracer
 
 import (
+	"cmp"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -17,6 +18,7 @@ import (
 	"runtime"
 	"runtime/pprof"
 	rt "runtime/trace"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -108,6 +110,149 @@ func (s *Span) spanEventsAsJSONString() string {
 	return string(events)
 }
 
+// SpanFromMap rebuilds a finished span from the map returned by AsMap, e.g.
+// to replay recorded spans. The ext.SpanName, ext.MapSpanID and
+// ext.MapSpanStart keys are required; the trace ID defaults to the span ID.
+// The other keys are set as tags: strings as meta, numbers as metrics and any
+// other value as meta struct. The numeric properties of the span can be of any
+// integer or float type, or strings, so that maps decoded from JSON are
+// accepted too.
+//
+// The returned span is not started: calling Finish submits it through the
+// tracer, keeping its recorded start time and duration. Like AsMap, this is
+// meant to be used in tests.
+func SpanFromMap(m map[string]interface{}) (*Span, error) {
+	name, ok := m[ext.SpanName].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing %s", ext.SpanName)
+	}
+	s := &Span{
+		name:        name,
+		meta:        make(map[string]string),
+		metrics:     make(map[string]float64),
+		integration: "manual",
+	}
+	var err error
+	if s.spanID, err = mapUint64(m, ext.MapSpanID, true); err != nil {
+		return nil, err
+	}
+	if s.start, err = mapInt64(m, ext.MapSpanStart, true); err != nil {
+		return nil, err
+	}
+	if s.traceID, err = mapUint64(m, ext.MapSpanTraceID, false); err != nil {
+		return nil, err
+	}
+	if s.traceID == 0 {
+		s.traceID = s.spanID
+	}
+	if s.parentID, err = mapUint64(m, ext.MapSpanParentID, false); err != nil {
+		return nil, err
+	}
+	if s.duration, err = mapInt64(m, ext.MapSpanDuration, false); err != nil {
+		return nil, err
+	}
+	errs, err := mapInt64(m, ext.MapSpanError, false)
+	if err != nil {
+		return nil, err
+	}
+	s.error = int32(errs)
+	for k, v := range m {
+		switch k {
+		case ext.SpanName, ext.MapSpanID, ext.MapSpanStart, ext.MapSpanTraceID, ext.MapSpanParentID,
+			ext.MapSpanDuration, ext.MapSpanError, ext.MapSpanEvents:
+			continue
+		}
+		if n, ok := v.(json.Number); ok {
+			v, _ = n.Float64()
+		}
+		if str, ok := v.(string); ok {
+			s.setMeta(k, str)
+		} else if f, ok := sharedinternal.ToFloat64(v); ok {
+			s.setMetric(k, f)
+		} else {
+			s.setMetaStruct(k, v)
+		}
+	}
+	if events, ok := m[ext.MapSpanEvents].(string); ok && events != "" {
+		if _, ok := s.meta["events"]; !ok {
+			// the events were natively supported by the agent, and not
+			// serialized as the "events" tag.
+			if err := json.Unmarshal([]byte(events), &s.spanEvents); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", ext.MapSpanEvents, err)
+			}
+			for i := range s.spanEvents {
+				s.spanEvents[i].Attributes = toSpanEventAttributeMsg(s.spanEvents[i].RawAttributes)
+			}
+		}
+	}
+	if t, ok := getGlobalTracer().(*tracer); ok {
+		s.supportsEvents = t.config.spanEventsAvailable()
+	}
+	s.context = newSpanContext(s, nil)
+	// keep the upper bits of the trace ID the span was recorded with, if any.
+	s.context.traceID.SetUpper(0)
+	if tid, ok := s.meta[keyTraceID128]; ok {
+		if err := s.context.traceID.SetUpperFromHex(tid); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", keyTraceID128, err)
+		}
+	}
+	if s.error > 0 {
+		s.context.errors.Add(1)
+	}
+	return s, nil
+}
+
+// mapInt64 returns the integer value of key in m. It returns an error if the
+// value has an invalid type, or if it is missing and required.
+func mapInt64(m map[string]interface{}, key string, required bool) (int64, error) {
+	v, ok := m[key]
+	if !ok {
+		if required {
+			return 0, fmt.Errorf("missing %s", key)
+		}
+		return 0, nil
+	}
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case uint64:
+		return int64(v), nil
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	if f, ok := sharedinternal.ToFloat64(v); ok {
+		return int64(f), nil
+	}
+	return 0, fmt.Errorf("invalid %s: %T", key, v)
+}
+
+// mapUint64 is like mapInt64, for unsigned integers such as IDs.
+func mapUint64(m map[string]interface{}, key string, required bool) (uint64, error) {
+	v, ok := m[key]
+	if !ok {
+		if required {
+			return 0, fmt.Errorf("missing %s", key)
+		}
+		return 0, nil
+	}
+	switch v := v.(type) {
+	case uint64:
+		return v, nil
+	case int64:
+		return uint64(v), nil
+	case json.Number:
+		return strconv.ParseUint(v.String(), 10, 64)
+	case string:
+		return strconv.ParseUint(v, 10, 64)
+	}
+	if f, ok := sharedinternal.ToFloat64(v); ok {
+		return uint64(f), nil
+	}
+	return 0, fmt.Errorf("invalid %s: %T", key, v)
+}
+
 // Span represents a computation. Callers must call Finish when a Span is
 // complete to ensure it's submitted.
 type Span struct {
@@ -140,6 +285,8 @@ type Span struct {
 	pprofCtxRestore context.Context `msg:"-"` // contains pprof.WithLabel labels of the parent span (if any) that need to be restored when this span finishes
 
 	taskEnd func() // ends execution tracer (runtime/trace) task, if started
+
+	creationStack []uintptr `msg:"-"` // program counters of the stack the span was started from, if captured
 }
 
 // Context yields the SpanContext for this Span. Note that the return
@@ -215,6 +362,12 @@ func (s *Span) SetTag(key string, value interface{}) {
 			s.pprofCtxActive = pprof.WithLabels(s.pprofCtxActive, pprof.Labels(traceprof.TraceEndpoint, v))
 			pprof.SetGoroutineLabels(s.pprofCtxActive)
 		}
+		if s.pprofCtxActive != nil && traceprof.IsSpanTagLabel(key) {
+			// The profiler slices the profiles by this tag, update its
+			// label as well.
+			s.pprofCtxActive = pprof.WithLabels(s.pprofCtxActive, pprof.Labels(key, v))
+			pprof.SetGoroutineLabels(s.pprofCtxActive)
+		}
 		s.setMeta(key, v)
 		return
 	}
@@ -391,6 +544,12 @@ func (s *Span) setSamplingPriorityLocked(priority int, sampler samplernames.Samp
 	}
 	s.setMetric(keySamplingPriority, float64(priority))
 	s.context.setSamplingPriority(priority, sampler)
+	if up, ok := s.context.trace.upstreamSamplingPriority(); ok {
+		if p, _ := s.context.trace.samplingPriority(); p != up {
+			// a local decision overrode the one received from upstream
+			s.setMetric(keyUpstreamSamplingPriority, float64(up))
+		}
+	}
 }
 
 // setTagError sets the error tag. It accounts for various valid scenarios.
@@ -439,6 +598,10 @@ func (s *Span) setTagError(value interface{}, cfg errorConfig) {
 			s.setMeta(ext.ErrorStack, takeStacktrace(cfg.stackFrames, cfg.stackSkip))
 		}
 
+		if snapshot, ok := errorSnapshot(v); ok {
+			s.setMeta(keyErrorSnapshot, snapshot)
+		}
+
 		switch v.(type) {
 		case xerrors.Formatter:
 			s.setMeta(ext.ErrorDetails, fmt.Sprintf("%+v", v))
@@ -583,6 +746,37 @@ func (s *Span) AddLink(link SpanLink) {
 	s.spanLinks = append(s.spanLinks, link)
 }
 
+// Default limits of the numbers of span links and span events sent with each span.
+const (
+	defaultMaxSpanLinks  = 128
+	defaultMaxSpanEvents = 128
+)
+
+// limitLinksAndEvents drops the span links and span events of s beyond the
+// limits of the tracer, keeping the first ones, and records how many were
+// dropped as metrics. s must be locked.
+func (s *Span) limitLinksAndEvents() {
+	if len(s.spanLinks) == 0 && len(s.spanEvents) == 0 {
+		return
+	}
+	maxLinks, maxEvents := defaultMaxSpanLinks, defaultMaxSpanEvents
+	if t, ok := getGlobalTracer().(*tracer); ok {
+		maxLinks, maxEvents = t.config.maxSpanLinks, t.config.maxSpanEvents
+	}
+	if n := len(s.spanLinks) - maxLinks; n > 0 {
+		if s.spanLinks = s.spanLinks[:maxLinks:maxLinks]; maxLinks == 0 {
+			s.spanLinks = nil
+		}
+		s.setMetric(keySpanLinksDropped, float64(n))
+	}
+	if n := len(s.spanEvents) - maxEvents; n > 0 {
+		if s.spanEvents = s.spanEvents[:maxEvents:maxEvents]; maxEvents == 0 {
+			s.spanEvents = nil
+		}
+		s.setMetric(keySpanEventsDropped, float64(n))
+	}
+}
+
 // serializeSpanLinksInMeta saves span links as a JSON string under `Span[meta][_dd.span_links]`.
 func (s *Span) serializeSpanLinksInMeta() {
 	if len(s.spanLinks) == 0 {
@@ -712,6 +906,7 @@ func (s *Span) finish(finishTime int64) {
 		return
 	}
 
+	s.limitLinksAndEvents()
 	s.serializeSpanLinksInMeta()
 	s.serializeSpanEvents()
 
@@ -731,6 +926,12 @@ func (s *Span) finish(finishTime int64) {
 		if !tracer.config.enabled.current {
 			return
 		}
+		if s.creationStack != nil {
+			if time.Duration(s.duration) >= tracer.config.spanStackThreshold {
+				s.setMeta(keyCreationStack, formatCreationStack(s.creationStack, tracer.config.spanStackDepth))
+			}
+			s.creationStack = nil
+		}
 		if tracer.config.canDropP0s() {
 			// the agent supports dropping p0's in the client
 			keep = shouldKeep(s)
@@ -740,6 +941,12 @@ func (s *Span) finish(finishTime int64) {
 			tracer.submitAbandonedSpan(s, true)
 		}
 		tracer.spansFinished.Inc(s.integration)
+		tracer.spanDurations.add(s.name, s.resource, s.duration)
+		if tracer.config.profilerEndpoints && s.context.trace.root == s && spanResourcePIISafe(s) {
+			// Inform the profiler of the latency of the endpoint, so that it
+			// can capture an execution trace of a latency spike.
+			traceprof.ObserveEndpointLatency(s.resource, s.spanID, time.Duration(s.duration))
+		}
 	}
 	if keep {
 		// a single kept span keeps the whole trace.
@@ -818,7 +1025,8 @@ func shouldComputeStats(s *Span) bool {
 	return false
 }
 
-// String returns a human readable representation of the span. Not for
+// String returns a human readable representation of the span, on multiple
+// lines, with its IDs in decimal and hexadecimal and its tags sorted. Not for
 // production, just debugging.
 func (s *Span) String() string {
 	if s == nil {
@@ -830,56 +1038,82 @@ func (s *Span) String() string {
 		fmt.Sprintf("Name: %s", s.name),
 		fmt.Sprintf("Service: %s", s.service),
 		fmt.Sprintf("Resource: %s", s.resource),
-		fmt.Sprintf("TraceID: %d", s.traceID),
+		fmt.Sprintf("TraceID: %d (%#x)", s.traceID, s.traceID),
 		fmt.Sprintf("TraceID128: %s", s.context.TraceID()),
-		fmt.Sprintf("SpanID: %d", s.spanID),
-		fmt.Sprintf("ParentID: %d", s.parentID),
+		fmt.Sprintf("SpanID: %d (%#x)", s.spanID, s.spanID),
+		fmt.Sprintf("ParentID: %d (%#x)", s.parentID, s.parentID),
 		fmt.Sprintf("Start: %s", time.Unix(0, s.start)),
 		fmt.Sprintf("Duration: %s", time.Duration(s.duration)),
 		fmt.Sprintf("Error: %d", s.error),
 		fmt.Sprintf("Type: %s", s.spanType),
-		"Tags:",
 	}
+	if p, ok := s.context.SamplingPriority(); ok {
+		lines = append(lines, fmt.Sprintf("SamplingPriority: %d", p))
+	}
+	lines = append(lines, "Tags:")
+	tags := make([]string, 0, len(s.meta)+len(s.metrics))
 	for key, val := range s.meta {
-		lines = append(lines, fmt.Sprintf("\t%s:%s", key, val))
+		tags = append(tags, fmt.Sprintf("\t%s:%s", key, val))
 	}
 	for key, val := range s.metrics {
-		lines = append(lines, fmt.Sprintf("\t%s:%f", key, val))
+		tags = append(tags, fmt.Sprintf("\t%s:%f", key, val))
 	}
-	return strings.Join(lines, "\n")
+	sort.Strings(tags)
+	return strings.Join(append(lines, tags...), "\n")
 }
 
-// Format implements fmt.Formatter.
+// summary returns a human readable representation of the span on a single
+// line, with its IDs in decimal and hexadecimal, and its key tags.
+func (s *Span) summary() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s service=%q resource=%q", s.name, s.service, s.resource)
+	if s.spanType != "" {
+		fmt.Fprintf(&b, " type=%q", s.spanType)
+	}
+	fmt.Fprintf(&b, " trace_id=%d (%s) span_id=%d (%#x) parent_id=%d (%#x)",
+		s.traceID, s.context.TraceID(), s.spanID, s.spanID, s.parentID, s.parentID)
+	if s.finished {
+		fmt.Fprintf(&b, " duration=%s", time.Duration(s.duration))
+	} else {
+		b.WriteString(" unfinished")
+	}
+	if p, ok := s.context.SamplingPriority(); ok {
+		fmt.Fprintf(&b, " priority=%d", p)
+	}
+	for _, key := range []string{ext.Component, ext.SpanKind, ext.HTTPCode, ext.ErrorMsg} {
+		if v, ok := s.meta[key]; ok {
+			fmt.Fprintf(&b, " %s=%q", key, v)
+		}
+	}
+	if s.error != 0 {
+		b.WriteString(" error")
+	}
+	return b.String()
+}
+
+// Format implements fmt.Formatter. The %s verb formats the span like String, on
+// multiple lines, and %#s on a single line, for debugging. The %v verb formats
+// the span for log correlation, with the dd.trace_id, dd.span_id and
+// dd.parent_id keys, along with dd.service, dd.env and dd.version if set.
 func (s *Span) Format(f fmt.State, c rune) {
 	if s == nil {
 		fmt.Fprintf(f, "<nil>")
+		return
 	}
 	switch c {
 	case 's':
+		if f.Flag('#') {
+			fmt.Fprint(f, s.summary())
+			return
+		}
 		fmt.Fprint(f, s.String())
 	case 'v':
-		if svc := globalconfig.ServiceName(); svc != "" {
-			fmt.Fprintf(f, "dd.service=%s ", svc)
-		}
-		if tr := getGlobalTracer(); tr != nil {
-			tc := tr.TracerConf()
-			if tc.EnvTag != "" {
-				fmt.Fprintf(f, "dd.env=%s ", tc.EnvTag)
-			} else if env := os.Getenv("DD_ENV"); env != "" {
-				fmt.Fprintf(f, "dd.env=%s ", env)
-			}
-			if tc.VersionTag != "" {
-				fmt.Fprintf(f, "dd.version=%s ", tc.VersionTag)
-			} else if v := os.Getenv("DD_VERSION"); v != "" {
-				fmt.Fprintf(f, "dd.version=%s ", v)
-			}
-		}
-		var traceID string
-		if sharedinternal.BoolEnv("DD_TRACE_128_BIT_TRACEID_LOGGING_ENABLED", true) && s.context.traceID.HasUpper() {
-			traceID = s.context.TraceID()
-		} else {
-			traceID = fmt.Sprintf("%d", s.traceID)
+		for _, lf := range logServiceFields() {
+			fmt.Fprintf(f, "%s=%s ", lf.Key, lf.Value)
 		}
+		traceID := s.logTraceID(sharedinternal.BoolEnv("DD_TRACE_128_BIT_TRACEID_LOGGING_ENABLED", true))
 		fmt.Fprintf(f, `dd.trace_id=%q `, traceID)
 		fmt.Fprintf(f, `dd.span_id="%d" `, s.spanID)
 		fmt.Fprintf(f, `dd.parent_id="%d"`, s.parentID)
@@ -888,6 +1122,59 @@ func (s *Span) Format(f fmt.State, c rune) {
 	}
 }
 
+// LogField is a field of a log entry correlating it with a span.
+type LogField struct {
+	Key   string
+	Value string
+}
+
+// LogFields returns the fields to add to a log entry to correlate it with s:
+// ext.LogKeyTraceID and ext.LogKeySpanID, followed by ext.LogKeyService,
+// ext.LogKeyEnv and ext.LogKeyVersion when they are set. The trace ID is
+// formatted as the 128-bit hex trace ID if traceID128 is true and the trace
+// has one, or as the lower 64 bits in decimal otherwise. It returns nil if s
+// is nil. This is meant to be used by log integrations.
+func LogFields(s *Span, traceID128 bool) []LogField {
+	if s == nil {
+		return nil
+	}
+	fields := []LogField{
+		{Key: ext.LogKeyTraceID, Value: s.logTraceID(traceID128)},
+		{Key: ext.LogKeySpanID, Value: strconv.FormatUint(s.spanID, 10)},
+	}
+	return append(fields, logServiceFields()...)
+}
+
+// logTraceID returns the trace ID of s, as written in the logs.
+func (s *Span) logTraceID(traceID128 bool) string {
+	if traceID128 && s.context.traceID.HasUpper() {
+		return s.context.TraceID()
+	}
+	return strconv.FormatUint(s.traceID, 10)
+}
+
+// logServiceFields returns the dd.service, dd.env and dd.version log fields
+// that are set.
+func logServiceFields() []LogField {
+	var fields []LogField
+	if svc := globalconfig.ServiceName(); svc != "" {
+		fields = append(fields, LogField{Key: ext.LogKeyService, Value: svc})
+	}
+	env, version := os.Getenv("DD_ENV"), os.Getenv("DD_VERSION")
+	if tr := getGlobalTracer(); tr != nil {
+		tc := tr.TracerConf()
+		env = cmp.Or(tc.EnvTag, env)
+		version = cmp.Or(tc.VersionTag, version)
+	}
+	if env != "" {
+		fields = append(fields, LogField{Key: ext.LogKeyEnv, Value: env})
+	}
+	if version != "" {
+		fields = append(fields, LogField{Key: ext.LogKeyVersion, Value: version})
+	}
+	return fields
+}
+
 // AddEvent attaches a new event to the current span.
 func (s *Span) AddEvent(name string, opts ...SpanEventOption) {
 	if s == nil {
@@ -906,6 +1193,11 @@ func (s *Span) AddEvent(name string, opts ...SpanEventOption) {
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	s.addEvent(name, cfg)
+}
+
+// addEvent appends a new event to the span. This method is not safe for concurrent use.
+func (s *Span) addEvent(name string, cfg SpanEventConfig) {
 	if cfg.Time.IsZero() {
 		cfg.Time = time.Now()
 	}
@@ -921,6 +1213,55 @@ func (s *Span) AddEvent(name string, opts ...SpanEventOption) {
 	s.spanEvents = append(s.spanEvents, event)
 }
 
+// RecordError attaches err to the span as an "exception" span event holding its
+// type, message and stack trace, which makes it visible in Error Tracking. Unless
+// the NonFatal option is used, the span is also marked as erroneous, as it would
+// be when setting the ext.Error tag. It has no effect if err is nil.
+func (s *Span) RecordError(err error, opts ...RecordErrorOption) {
+	if s == nil || err == nil {
+		return
+	}
+	cfg := RecordErrorConfig{
+		NoDebugStack: s.noDebugStack,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// We don't lock spans when flushing, so we could have a data race when
+	// modifying a span as it's being flushed. This protects us against that
+	// race, since spans are marked `finished` before we flush them.
+	if s.finished {
+		return
+	}
+	attrs := map[string]any{
+		"exception.message": err.Error(),
+		"exception.escaped": !cfg.NonFatal,
+	}
+	if v, ok := err.(interface{ ErrorType() string }); ok {
+		attrs["exception.type"] = v.ErrorType()
+	} else {
+		attrs["exception.type"] = reflect.TypeOf(err).String()
+	}
+	if v, ok := err.(interface{ ErrorStack() string }); ok {
+		attrs["exception.stacktrace"] = v.ErrorStack()
+	} else if !cfg.NoDebugStack {
+		// +1 to exclude RecordError itself
+		attrs["exception.stacktrace"] = takeStacktrace(cfg.StackFrames, cfg.SkipStackFrames+1)
+	}
+	s.addEvent("exception", SpanEventConfig{Attributes: attrs})
+	if cfg.NonFatal {
+		return
+	}
+	s.setTagError(err, errorConfig{
+		noDebugStack: cfg.NoDebugStack,
+		stackFrames:  cfg.StackFrames,
+		stackSkip:    cfg.SkipStackFrames + 1,
+	})
+}
+
 // used in internal/civisibility/integrations/manual_api_common.go using linkname
 func getMeta(s *Span, key string) (string, bool) {
 	s.mu.RLock()
@@ -955,6 +1296,9 @@ const (
 	keyTopLevel = "_dd.top_level"
 	// keyPropagationError holds any error from propagated trace tags (if any)
 	keyPropagationError = "_dd.propagation_error"
+	// keyUpstreamSamplingPriority holds the sampling priority received from an upstream
+	// service, set on spans which overrode it.
+	keyUpstreamSamplingPriority = "_dd.upstream_sampling_priority"
 	// keySpanSamplingMechanism specifies the sampling mechanism by which an individual span was sampled
 	keySpanSamplingMechanism = "_dd.span_sampling.mechanism"
 	// keySingleSpanSamplingRuleRate specifies the configured sampling probability for the single span sampling rule.
@@ -979,6 +1323,10 @@ const (
 	keyBaseService = "_dd.base_service"
 	// keyProcessTags contains a list of process tags to indentify the service.
 	keyProcessTags = "_dd.tags.process"
+	// keySpanLinksDropped holds the number of span links dropped from a span, beyond the limit.
+	keySpanLinksDropped = "_dd.span_links.dropped"
+	// keySpanEventsDropped holds the number of span events dropped from a span, beyond the limit.
+	keySpanEventsDropped = "_dd.span_events.dropped"
 )
 
 // The following set of tags is used for user monitoring and set through calls to span.SetUser().
@@ -141,3 +141,44 @@ func WithFinishConfig(cfg *FinishConfig) FinishOption {
 		}
 	}
 }
+
+// RecordErrorOption is a configuration option that can be used with a Span's RecordError method.
+type RecordErrorOption func(cfg *RecordErrorConfig)
+
+// RecordErrorConfig holds the configuration for recording an error on a span.
+type RecordErrorConfig struct {
+	// NonFatal records the error as an event without marking the span as erroneous.
+	NonFatal bool
+
+	// NoDebugStack prevents the recorded error from generating a stack trace.
+	NoDebugStack bool
+
+	// StackFrames specifies the number of stack frames to be attached to the recorded error.
+	StackFrames uint
+
+	// SkipStackFrames specifies the offset at which to start reporting stack frames from the stack.
+	SkipStackFrames uint
+}
+
+// NonFatal records the error as a handled one: it is attached to the span as an
+// exception event, visible in Error Tracking, but the span is not marked as
+// erroneous. This is useful for errors that were retried or recovered from, which
+// should not inflate the error rate of the service.
+func NonFatal() RecordErrorOption {
+	return func(cfg *RecordErrorConfig) {
+		cfg.NonFatal = true
+	}
+}
+
+// RecordErrorStackFrames limits the number of stack frames attached to the recorded
+// error to n, starting from skip. A value of 0 for n disables the stack trace.
+func RecordErrorStackFrames(n, skip uint) RecordErrorOption {
+	return func(cfg *RecordErrorConfig) {
+		if n == 0 {
+			cfg.NoDebugStack = true
+			return
+		}
+		cfg.StackFrames = n
+		cfg.SkipStackFrames = skip
+	}
+}
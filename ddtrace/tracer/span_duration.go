@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"hash/fnv"
+	"strconv"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+
+	"github.com/DataDog/sketches-go/ddsketch"
+	"github.com/DataDog/sketches-go/ddsketch/mapping"
+	"github.com/DataDog/sketches-go/ddsketch/store"
+)
+
+// spanDurationMaxKeys is the maximum number of distinct (name, resource) pairs
+// tracked during a single reporting interval. Durations of spans with new pairs
+// are dropped once the limit is reached.
+const spanDurationMaxKeys = 1000
+
+// spanDurationQuantiles lists the quantiles reported for each tracked pair,
+// along with the value of their "quantile" tag.
+var spanDurationQuantiles = []struct {
+	q   float64
+	tag string
+}{
+	{0.5, "quantile:p50"},
+	{0.75, "quantile:p75"},
+	{0.95, "quantile:p95"},
+	{0.99, "quantile:p99"},
+	{1, "quantile:max"},
+}
+
+// spanDurationMapping is a 1% relative accuracy mapping for span duration sketches.
+var spanDurationMapping, _ = mapping.NewLogarithmicMapping(0.01)
+
+// spanDurationKey identifies the code path a span duration is aggregated for.
+type spanDurationKey struct {
+	name         string
+	resourceHash uint64
+}
+
+// spanDurationAggregator aggregates the durations of all finished spans into
+// one DDSketch per (operation name, resource hash), regardless of whether their
+// trace is sampled. It is reported with the tracer health metrics, allowing
+// latency regressions to be detected in code paths whose traces are sampled out.
+type spanDurationAggregator struct {
+	mu       sync.Mutex
+	sketches map[spanDurationKey]*ddsketch.DDSketch
+	dropped  int64
+}
+
+func newSpanDurationAggregator() *spanDurationAggregator {
+	return &spanDurationAggregator{sketches: make(map[spanDurationKey]*ddsketch.DDSketch)}
+}
+
+// add records the duration, in nanoseconds, of a span with the given name and resource.
+func (a *spanDurationAggregator) add(name, resource string, duration int64) {
+	if a == nil {
+		return
+	}
+	h := fnv.New64a()
+	h.Write([]byte(resource))
+	k := spanDurationKey{name: name, resourceHash: h.Sum64()}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sketch, ok := a.sketches[k]
+	if !ok {
+		if len(a.sketches) >= spanDurationMaxKeys {
+			a.dropped++
+			return
+		}
+		sketch = ddsketch.NewDDSketch(spanDurationMapping, store.DenseStoreConstructor(), store.DenseStoreConstructor())
+		a.sketches[k] = sketch
+	}
+	if err := sketch.Add(float64(duration)); err != nil {
+		log.Debug("Unable to add span duration to sketch: %v", err.Error())
+	}
+}
+
+// report sends the quantiles and counts of the aggregated span durations to
+// statsd and resets the aggregator.
+func (a *spanDurationAggregator) report(statsd internal.StatsdClient) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	sketches := a.sketches
+	dropped := a.dropped
+	a.sketches = make(map[spanDurationKey]*ddsketch.DDSketch, len(sketches))
+	a.dropped = 0
+	a.mu.Unlock()
+
+	for k, sketch := range sketches {
+		tags := []string{
+			"name:" + k.name,
+			"resource_hash:" + strconv.FormatUint(k.resourceHash, 16),
+		}
+		statsd.Count("datadog.tracer.span_duration.count", int64(sketch.GetCount()), tags, 1)
+		for _, q := range spanDurationQuantiles {
+			v, err := sketch.GetValueAtQuantile(q.q)
+			if err != nil {
+				continue
+			}
+			statsd.Gauge("datadog.tracer.span_duration", v, append(tags[:len(tags):len(tags)], q.tag), 1)
+		}
+	}
+	if dropped > 0 {
+		statsd.Count("datadog.tracer.span_duration.dropped", dropped, nil, 1)
+	}
+}
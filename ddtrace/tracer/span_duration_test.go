@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+)
+
+func TestSpanDurationAggregator(t *testing.T) {
+	t.Run("report", func(t *testing.T) {
+		var tg statsdtest.TestStatsdClient
+		a := newSpanDurationAggregator()
+		for i := 1; i <= 100; i++ {
+			a.add("http.request", "GET /users", int64(i*int(time.Millisecond)))
+		}
+		a.add("http.request", "GET /orders", int64(time.Second))
+		a.report(&tg)
+
+		counts := statsdtest.FilterCallsByName(tg.CountCalls(), "datadog.tracer.span_duration.count")
+		require.Len(t, counts, 2)
+		gauges := statsdtest.FilterCallsByName(tg.GaugeCalls(), "datadog.tracer.span_duration")
+		assert.Len(t, gauges, 2*len(spanDurationQuantiles))
+		for _, g := range gauges {
+			assert.Contains(t, g.Tags(), "name:http.request")
+		}
+		assert.Empty(t, a.sketches)
+	})
+
+	t.Run("max-keys", func(t *testing.T) {
+		var tg statsdtest.TestStatsdClient
+		a := newSpanDurationAggregator()
+		for i := 0; i < spanDurationMaxKeys+10; i++ {
+			a.add("op", time.Duration(i).String(), 1)
+		}
+		assert.Len(t, a.sketches, spanDurationMaxKeys)
+		a.report(&tg)
+		dropped := statsdtest.FilterCallsByName(tg.CountCalls(), "datadog.tracer.span_duration.dropped")
+		require.Len(t, dropped, 1)
+		assert.Equal(t, int64(10), dropped[0].IntVal())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var a *spanDurationAggregator
+		a.add("op", "resource", 1)
+		a.report(&statsdtest.TestStatsdClient{})
+	})
+}
+
+func TestSpanDurationMetrics(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t)
+		require.NoError(t, err)
+		defer stop()
+		assert.Nil(t, tracer.spanDurations)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("DD_TRACE_SPAN_DURATION_METRICS_ENABLED", "true")
+		c, err := newConfig()
+		require.NoError(t, err)
+		assert.True(t, c.spanDurationMetrics)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t, WithSpanDurationMetrics())
+		require.NoError(t, err)
+		defer stop()
+
+		tracer.StartSpan("operation", ResourceName("resource")).Finish()
+		tracer.spanDurations.mu.Lock()
+		defer tracer.spanDurations.mu.Unlock()
+		assert.Len(t, tracer.spanDurations.sketches, 1)
+	})
+}
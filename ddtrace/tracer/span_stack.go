@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// keyCreationStack is the tag holding the stack from which a slow span was started.
+	keyCreationStack = "span.creation_stack"
+
+	// defaultSpanStackThreshold is the default minimum duration of a span for its
+	// creation stack to be reported.
+	defaultSpanStackThreshold = time.Second
+
+	// spanStackExtraFrames is the number of additional frames captured to make up
+	// for the tracer frames trimmed from creation stacks.
+	spanStackExtraFrames = 8
+
+	// tracerPkgPrefix is the prefix of the functions of this package, trimmed from
+	// creation stacks.
+	tracerPkgPrefix = "github.com/DataDog/dd-trace-go/v2/ddtrace/tracer."
+)
+
+// captureCreationStack returns the program counters of the calling goroutine,
+// sufficient to later report up to depth frames outside of the tracer.
+func captureCreationStack(depth int) []uintptr {
+	pcs := make([]uintptr, depth+spanStackExtraFrames)
+	// +2 to exclude runtime.Callers and captureCreationStack
+	n := runtime.Callers(2, pcs)
+	return pcs[:n]
+}
+
+// formatCreationStack symbolizes pcs into a stack trace of at most depth frames,
+// skipping the frames of the tracer itself.
+func formatCreationStack(pcs []uintptr, depth int) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for n := 0; n < depth; {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, tracerPkgPrefix) {
+			if n != 0 {
+				builder.WriteByte('\n')
+			}
+			builder.WriteString(frame.Function)
+			builder.WriteByte('\n')
+			builder.WriteByte('\t')
+			builder.WriteString(frame.File)
+			builder.WriteByte(':')
+			builder.WriteString(strconv.Itoa(frame.Line))
+			n++
+		}
+		if !more {
+			break
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanCreationStack(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t)
+		require.NoError(t, err)
+		defer stop()
+
+		span := tracer.StartSpan("op")
+		assert.Nil(t, span.creationStack)
+		span.Finish(FinishTime(time.Now().Add(time.Hour)))
+		assert.NotContains(t, span.meta, keyCreationStack)
+	})
+
+	t.Run("slow", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t, WithStackTraces(2), WithStackTracesThreshold(time.Millisecond))
+		require.NoError(t, err)
+		defer stop()
+
+		span := tracer.StartSpan("op")
+		require.NotNil(t, span.creationStack)
+		span.Finish(FinishTime(time.Now().Add(time.Second)))
+
+		stack := span.meta[keyCreationStack]
+		assert.Contains(t, stack, "testing.tRunner")
+		assert.NotContains(t, stack, tracerPkgPrefix)
+		assert.Equal(t, 2, strings.Count(stack, "\n\t"))
+		assert.Nil(t, span.creationStack)
+	})
+
+	t.Run("fast", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t, WithStackTraces(2), WithStackTracesThreshold(time.Hour))
+		require.NoError(t, err)
+		defer stop()
+
+		span := tracer.StartSpan("op")
+		span.Finish()
+		assert.NotContains(t, span.meta, keyCreationStack)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("DD_TRACE_SPAN_STACK_TRACES_DEPTH", "5")
+		t.Setenv("DD_TRACE_SPAN_STACK_TRACES_THRESHOLD", "100ms")
+		c, err := newConfig()
+		require.NoError(t, err)
+		assert.Equal(t, 5, c.spanStackDepth)
+		assert.Equal(t, 100*time.Millisecond, c.spanStackThreshold)
+	})
+}
@@ -6,10 +6,12 @@
 package tracer
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -73,6 +75,81 @@ func TestSpanAsMap(t *testing.T) {
 	}
 }
 
+func TestSpanFromMap(t *testing.T) {
+	tracer, transport, flush, stop, err := startTestTracer(t)
+	require.NoError(t, err)
+	defer stop()
+
+	root := tracer.StartSpan("web.request", ServiceName("web"), ResourceName("GET /"), SpanType(ext.SpanTypeWeb))
+	child := tracer.StartSpan("db.query", ChildOf(root.Context()), Tag("rows", 3), Tag(ext.DBSystem, "postgresql"))
+	child.AddEvent("retry", WithSpanEventAttributes(map[string]any{"attempt": 2}))
+	child.Finish(WithError(errors.New("timeout")))
+	root.Finish()
+	flush(1)
+	recorded := transport.Traces()[0]
+	require.Len(t, recorded, 2)
+
+	t.Run("round-trip", func(t *testing.T) {
+		for _, want := range recorded {
+			m := want.AsMap()
+			s, err := SpanFromMap(m)
+			require.NoError(t, err)
+			assert.Equal(t, want.name, s.name)
+			assert.Equal(t, want.service, s.service)
+			assert.Equal(t, want.resource, s.resource)
+			assert.Equal(t, want.spanType, s.spanType)
+			assert.Equal(t, want.start, s.start)
+			assert.Equal(t, want.duration, s.duration)
+			assert.Equal(t, want.spanID, s.spanID)
+			assert.Equal(t, want.traceID, s.traceID)
+			assert.Equal(t, want.parentID, s.parentID)
+			assert.Equal(t, want.error, s.error)
+			assert.Equal(t, want.meta, s.meta)
+			assert.Equal(t, want.metrics, s.metrics)
+			if tid, ok := want.meta[keyTraceID128]; ok {
+				assert.Equal(t, tid, s.context.traceID.UpperHex())
+			} else {
+				assert.False(t, s.context.traceID.HasUpper())
+			}
+			assert.Equal(t, m, s.AsMap())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(recorded[0].AsMap())
+		require.NoError(t, err)
+		// decode the numbers as json.Number, as IDs don't fit in a float64.
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var m map[string]any
+		require.NoError(t, dec.Decode(&m))
+		s, err := SpanFromMap(m)
+		require.NoError(t, err)
+		assert.Equal(t, recorded[0].start, s.start)
+		assert.Equal(t, recorded[0].traceID, s.traceID)
+		assert.Equal(t, recorded[0].metrics, s.metrics)
+	})
+
+	t.Run("submit", func(t *testing.T) {
+		s, err := SpanFromMap(recorded[0].AsMap())
+		require.NoError(t, err)
+		s.Finish()
+		flush(1)
+		traces := transport.Traces()
+		require.Len(t, traces, 1)
+		assert.Equal(t, recorded[0].spanID, traces[0][0].spanID)
+		assert.Equal(t, recorded[0].start, traces[0][0].start)
+		assert.Equal(t, recorded[0].duration, traces[0][0].duration)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := SpanFromMap(map[string]any{ext.SpanName: "op", ext.MapSpanStart: int64(1)})
+		assert.ErrorContains(t, err, "missing "+ext.MapSpanID)
+		_, err = SpanFromMap(map[string]any{ext.SpanName: "op", ext.MapSpanID: uint64(1), ext.MapSpanStart: true})
+		assert.ErrorContains(t, err, "invalid "+ext.MapSpanStart)
+	})
+}
+
 func TestNilSpan(t *testing.T) {
 	assertions := assert.New(t)
 	var (
@@ -366,6 +443,61 @@ func TestSpanFinishWithErrorStackFrames(t *testing.T) {
 	assert.Equal(strings.Count(span.meta[ext.ErrorStack], "\n\t"), 2)
 }
 
+func TestSpanRecordError(t *testing.T) {
+	t.Run("fatal", func(t *testing.T) {
+		assert := assert.New(t)
+		span := newBasicSpan("web.request")
+		span.RecordError(errors.New("test error"))
+
+		assert.Equal(int32(1), span.error)
+		assert.Equal("test error", span.meta[ext.ErrorMsg])
+		require.Len(t, span.spanEvents, 1)
+		evt := span.spanEvents[0]
+		assert.Equal("exception", evt.Name)
+		assert.Equal("test error", evt.RawAttributes["exception.message"])
+		assert.Equal("*errors.errorString", evt.RawAttributes["exception.type"])
+		assert.Equal(true, evt.RawAttributes["exception.escaped"])
+		assert.Contains(evt.RawAttributes["exception.stacktrace"], "tracer.TestSpanRecordError")
+		assert.NotContains(evt.RawAttributes["exception.stacktrace"], "tracer.(*Span).RecordError")
+	})
+
+	t.Run("non-fatal", func(t *testing.T) {
+		assert := assert.New(t)
+		span := newBasicSpan("web.request")
+		span.RecordError(errors.New("test error"), NonFatal())
+
+		assert.Equal(int32(0), span.error)
+		assert.Empty(span.meta[ext.ErrorMsg])
+		require.Len(t, span.spanEvents, 1)
+		evt := span.spanEvents[0]
+		assert.Equal("exception", evt.Name)
+		assert.Equal("test error", evt.RawAttributes["exception.message"])
+		assert.Equal(false, evt.RawAttributes["exception.escaped"])
+		assert.Equal(int32(0), span.context.errors.Load())
+	})
+
+	t.Run("no-stack", func(t *testing.T) {
+		span := newBasicSpan("web.request")
+		span.RecordError(errors.New("test error"), NonFatal(), RecordErrorStackFrames(0, 0))
+		require.Len(t, span.spanEvents, 1)
+		assert.NotContains(t, span.spanEvents[0].RawAttributes, "exception.stacktrace")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		span := newBasicSpan("web.request")
+		span.RecordError(nil)
+		assert.Empty(t, span.spanEvents)
+		assert.Equal(t, int32(0), span.error)
+	})
+
+	t.Run("finished", func(t *testing.T) {
+		span := newBasicSpan("web.request")
+		span.Finish()
+		span.RecordError(errors.New("test error"))
+		assert.Empty(t, span.spanEvents)
+	})
+}
+
 // nilStringer is used to test nil detection when setting tags.
 type nilStringer struct {
 	s string
@@ -685,6 +817,42 @@ func TestSpanString(t *testing.T) {
 	assert.NotEqual("", span.String())
 }
 
+func TestSpanFormat(t *testing.T) {
+	tracer, err := newTracer(withTransport(newDefaultTransport()))
+	require.NoError(t, err)
+	defer tracer.Stop()
+
+	span := tracer.newRootSpan("pylons.request", "pylons", "/")
+	span.spanID, span.traceID = 255, 255
+	span.context.spanID = 255
+	span.context.traceID.SetLower(255)
+	span.SetTag(ext.SpanKind, ext.SpanKindServer)
+	span.SetTag("b", "2")
+	span.SetTag("a", "1")
+
+	lines := strings.Split(fmt.Sprintf("%s", span), "\n")
+	assert.Contains(t, lines, "TraceID: 255 (0xff)")
+	assert.Contains(t, lines, "SpanID: 255 (0xff)")
+	assert.Contains(t, lines, "SamplingPriority: 1")
+	assert.Less(t, slices.Index(lines, "\ta:1"), slices.Index(lines, "\tb:2"))
+
+	summary := fmt.Sprintf("%#s", span)
+	assert.NotContains(t, summary, "\n")
+	assert.True(t, strings.HasPrefix(summary, `pylons.request service="pylons" resource="/" `), summary)
+	assert.Contains(t, summary, " span_id=255 (0xff) parent_id=0 (0x0) unfinished priority=1")
+	assert.Contains(t, summary, ` trace_id=255 (`)
+	assert.Contains(t, summary, `span.kind="server"`)
+
+	span.Finish(WithError(errors.New("boom")))
+	summary = fmt.Sprintf("%#s", span)
+	assert.Contains(t, summary, " duration=")
+	assert.True(t, strings.HasSuffix(summary, `error.message="boom" error`), summary)
+
+	var nilSpan *Span
+	assert.Equal(t, "<nil>", fmt.Sprintf("%#s", nilSpan))
+	assert.Equal(t, "<nil>", fmt.Sprintf("%v", nilSpan))
+}
+
 const (
 	intUpperLimit = int64(1) << 53
 	intLowerLimit = -intUpperLimit
@@ -1126,6 +1294,23 @@ func TestSpanLog(t *testing.T) {
 	})
 }
 
+func TestLogFields(t *testing.T) {
+	tracer, _, _, stop, err := startTestTracer(t, WithService("tracer.test"), WithEnv("testenv"), WithServiceVersion("1.2.3"))
+	require.NoError(t, err)
+	defer stop()
+	span := tracer.StartSpan("test.request")
+
+	assert.Nil(t, LogFields(nil, true))
+	assert.Equal(t, []LogField{
+		{Key: ext.LogKeyTraceID, Value: span.context.TraceID()},
+		{Key: ext.LogKeySpanID, Value: fmt.Sprint(span.spanID)},
+		{Key: ext.LogKeyService, Value: "tracer.test"},
+		{Key: ext.LogKeyEnv, Value: "testenv"},
+		{Key: ext.LogKeyVersion, Value: "1.2.3"},
+	}, LogFields(span, true))
+	assert.Equal(t, fmt.Sprint(span.traceID), LogFields(span, false)[0].Value)
+}
+
 func TestRootSpanAccessor(t *testing.T) {
 	tracer, _, _, stop, err := startTestTracer(t)
 	assert.Nil(t, err)
@@ -1434,6 +1619,62 @@ func TestSpanLinksInMeta(t *testing.T) {
 	})
 }
 
+func TestSpanLinksAndEventsLimit(t *testing.T) {
+	tracer, _, _, stop, err := startTestTracer(t, WithSpanLinksLimit(2), WithSpanEventsLimit(1))
+	require.NoError(t, err)
+	defer stop()
+
+	addLinksAndEvents := func(sp *Span) {
+		for i := 1; i <= 5; i++ {
+			sp.AddLink(SpanLink{SpanID: uint64(i), TraceID: uint64(i)})
+		}
+		for _, name := range []string{"first", "second", "third"} {
+			sp.AddEvent(name)
+		}
+	}
+
+	t.Run("limited", func(t *testing.T) {
+		sp := tracer.StartSpan("op")
+		addLinksAndEvents(sp)
+		sp.Finish()
+
+		var links []SpanLink
+		require.NoError(t, json.Unmarshal([]byte(sp.meta["_dd.span_links"]), &links))
+		require.Len(t, links, 2)
+		assert.Equal(t, uint64(1), links[0].SpanID)
+		assert.Equal(t, uint64(2), links[1].SpanID)
+		assert.Equal(t, float64(3), sp.metrics[keySpanLinksDropped])
+
+		var events []spanEvent
+		require.NoError(t, json.Unmarshal([]byte(sp.meta["events"]), &events))
+		require.Len(t, events, 1)
+		assert.Equal(t, "first", events[0].Name)
+		assert.Equal(t, float64(2), sp.metrics[keySpanEventsDropped])
+	})
+
+	t.Run("under", func(t *testing.T) {
+		sp := tracer.StartSpan("op")
+		sp.AddLink(SpanLink{SpanID: 1, TraceID: 1})
+		sp.Finish()
+		assert.Contains(t, sp.meta, "_dd.span_links")
+		assert.NotContains(t, sp.metrics, keySpanLinksDropped)
+		assert.NotContains(t, sp.metrics, keySpanEventsDropped)
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		// the agent may still keep the dropped traces, so they are limited the same way
+		sp := tracer.StartSpan("op")
+		addLinksAndEvents(sp)
+		sp.SetTag(ext.ManualDrop, true)
+		sp.context.trace.setLocked(true)
+		sp.Finish()
+		assert.Contains(t, sp.meta, "_dd.span_links")
+		assert.Contains(t, sp.meta, "events")
+		assert.Equal(t, float64(3), sp.metrics[keySpanLinksDropped])
+		assert.Equal(t, float64(2), sp.metrics[keySpanEventsDropped])
+	})
+}
+
 func TestStatsAfterFinish(t *testing.T) {
 	t.Run("peerServiceDefaults-enabled", func(t *testing.T) {
 		tracer, err := newTracer(
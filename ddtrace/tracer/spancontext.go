@@ -174,7 +174,7 @@ func newSpanContext(span *Span, parent *SpanContext) *SpanContext {
 			context.setBaggageItem(k, v)
 			return true
 		})
-	} else if sharedinternal.BoolEnv("DD_TRACE_128_BIT_TRACEID_GENERATION_ENABLED", true) {
+	} else if sharedinternal.BoolEnv("DD_TRACE_128_BIT_TRACEID_GENERATION_ENABLED", true) && !deterministicMode() {
 		// add 128 bit trace id, if enabled, formatted as big-endian:
 		// <32-bit unix seconds> <32 bits of zero> <64 random bits>
 		id128 := time.Duration(span.start) / time.Second
@@ -328,6 +328,7 @@ type trace struct {
 	finished         int               // the number of finished spans
 	full             bool              // signifies that the span buffer is full
 	priority         *float64          // sampling priority
+	upstreamPriority *float64          // sampling priority received from an upstream service, if any
 	locked           bool              // specifies if the sampling priority can be altered
 	samplingDecision samplingDecision  // samplingDecision indicates whether to send the trace to the agent.
 
@@ -378,6 +379,29 @@ func (t *trace) setSamplingPriority(p int, sampler samplernames.SamplerName) boo
 	return t.setSamplingPriorityLocked(p, sampler)
 }
 
+// setUpstreamPriority records the current sampling priority of the trace, if any,
+// as the decision made by an upstream service.
+func (t *trace) setUpstreamPriority() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.priority == nil || t.upstreamPriority != nil {
+		return
+	}
+	p := *t.priority
+	t.upstreamPriority = &p
+}
+
+// upstreamSamplingPriority returns the sampling priority received from an upstream
+// service, if any.
+func (t *trace) upstreamSamplingPriority() (p int, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.upstreamPriority == nil {
+		return 0, false
+	}
+	return int(*t.upstreamPriority), true
+}
+
 func (t *trace) keep() {
 	atomic.CompareAndSwapUint32((*uint32)(&t.samplingDecision), uint32(decisionNone), uint32(decisionKeep))
 }
@@ -542,6 +566,11 @@ func (t *trace) finishedOne(s *Span) {
 		// the new wire format. We won't need to set the tags on the first span
 		// in the chunk there.
 		t.setTraceTags(s)
+		if tr, ok := tr.(*tracer); ok && tr.config.runtimeEnvTags {
+			for k, v := range tr.runtimeEnvTags() {
+				s.setMetric(k, v)
+			}
+		}
 	}
 
 	// This is here to support the mocktracer. It would be nice to be able to not do this.
@@ -64,7 +64,7 @@ func newConcentrator(c *config, bucketSize int64, statsdClient internal.StatsdCl
 		ComputeStatsBySpanKind: true,
 		BucketInterval:         defaultStatsBucketSize,
 	}
-	env := c.agent.defaultEnv
+	env := c.agentFeatures().defaultEnv
 	if c.env != "" {
 		env = c.env
 	}
@@ -167,7 +167,7 @@ func (c *concentrator) newTracerStatSpan(s *Span, obfuscator *obfuscate.Obfuscat
 		resource = obfuscatedResource(obfuscator, s.spanType, s.resource)
 	}
 	statSpan, ok := c.spanConcentrator.NewStatSpan(s.service, resource,
-		s.name, s.spanType, s.parentID, s.start, s.duration, s.error, s.meta, s.metrics, c.cfg.agent.peerTags)
+		s.name, s.spanType, s.parentID, s.start, s.duration, s.error, s.meta, s.metrics, c.cfg.agentFeatures().peerTags)
 	if !ok {
 		return nil, false
 	}
@@ -180,7 +180,8 @@ func (c *concentrator) newTracerStatSpan(s *Span, obfuscator *obfuscate.Obfuscat
 
 func (c *concentrator) shouldObfuscate() bool {
 	// Obfuscate if agent reports an obfuscation version AND our version is at least as new
-	return c.cfg.agent.obfuscationVersion > 0 && c.cfg.agent.obfuscationVersion <= tracerObfuscationVersion
+	v := c.cfg.agentFeatures().obfuscationVersion
+	return v > 0 && v <= tracerObfuscationVersion
 }
 
 // add s into the concentrator's internal stats buckets.
@@ -222,7 +223,7 @@ func (c *concentrator) flushAndSend(timenow time.Time, includeCurrent bool) {
 	if c.shouldObfuscate() {
 		obfVersion = tracerObfuscationVersion
 	} else {
-		log.Debug("Stats Obfuscation was skipped, agent will obfuscate (tracer %d, agent %d)", tracerObfuscationVersion, c.cfg.agent.obfuscationVersion)
+		log.Debug("Stats Obfuscation was skipped, agent will obfuscate (tracer %d, agent %d)", tracerObfuscationVersion, c.cfg.agentFeatures().obfuscationVersion)
 	}
 
 	if len(csps) == 0 {
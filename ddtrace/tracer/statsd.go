@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+)
+
+// StatsdClientFromContext returns a statsd client which adds the trace_id, env
+// and service tags of the span found in ctx to every metric sent through it, so
+// that metrics emitted while serving a traced request can be correlated with its
+// trace. If ctx carries no span, client is returned unchanged.
+//
+// The returned client is meant to be short-lived and scoped to the request;
+// closing it closes the wrapped client.
+func StatsdClientFromContext(ctx context.Context, client statsd.ClientInterface) statsd.ClientInterface {
+	s, ok := SpanFromContext(ctx)
+	if !ok || client == nil {
+		return client
+	}
+	tags := spanStatsdTags(s)
+	if len(tags) == 0 {
+		return client
+	}
+	return &spanTaggedStatsdClient{ClientInterface: client, tags: tags}
+}
+
+// spanStatsdTags returns the metric tags correlating metrics with span s.
+func spanStatsdTags(s *Span) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var traceID string
+	if s.context != nil && s.context.traceID.HasUpper() {
+		traceID = s.context.TraceID()
+	} else {
+		traceID = strconv.FormatUint(s.traceID, 10)
+	}
+	tags := []string{"trace_id:" + traceID}
+	env := s.meta[ext.Environment]
+	if env == "" {
+		if tr := getGlobalTracer(); tr != nil {
+			env = tr.TracerConf().EnvTag
+		}
+	}
+	if env != "" {
+		tags = append(tags, "env:"+env)
+	}
+	if s.service != "" {
+		tags = append(tags, "service:"+s.service)
+	}
+	return tags
+}
+
+// spanTaggedStatsdClient wraps a statsd client, appending span correlation tags
+// to every metric.
+type spanTaggedStatsdClient struct {
+	statsd.ClientInterface
+	tags []string
+}
+
+// with returns tags extended with the span tags. The input slice is never
+// modified.
+func (c *spanTaggedStatsdClient) with(tags []string) []string {
+	out := make([]string, 0, len(tags)+len(c.tags))
+	out = append(out, tags...)
+	return append(out, c.tags...)
+}
+
+func (c *spanTaggedStatsdClient) Gauge(name string, value float64, tags []string, rate float64) error {
+	return c.ClientInterface.Gauge(name, value, c.with(tags), rate)
+}
+
+func (c *spanTaggedStatsdClient) GaugeWithTimestamp(name string, value float64, tags []string, rate float64, timestamp time.Time) error {
+	return c.ClientInterface.GaugeWithTimestamp(name, value, c.with(tags), rate, timestamp)
+}
+
+func (c *spanTaggedStatsdClient) Count(name string, value int64, tags []string, rate float64) error {
+	return c.ClientInterface.Count(name, value, c.with(tags), rate)
+}
+
+func (c *spanTaggedStatsdClient) CountWithTimestamp(name string, value int64, tags []string, rate float64, timestamp time.Time) error {
+	return c.ClientInterface.CountWithTimestamp(name, value, c.with(tags), rate, timestamp)
+}
+
+func (c *spanTaggedStatsdClient) Histogram(name string, value float64, tags []string, rate float64) error {
+	return c.ClientInterface.Histogram(name, value, c.with(tags), rate)
+}
+
+func (c *spanTaggedStatsdClient) Distribution(name string, value float64, tags []string, rate float64) error {
+	return c.ClientInterface.Distribution(name, value, c.with(tags), rate)
+}
+
+func (c *spanTaggedStatsdClient) Decr(name string, tags []string, rate float64) error {
+	return c.ClientInterface.Decr(name, c.with(tags), rate)
+}
+
+func (c *spanTaggedStatsdClient) Incr(name string, tags []string, rate float64) error {
+	return c.ClientInterface.Incr(name, c.with(tags), rate)
+}
+
+func (c *spanTaggedStatsdClient) Set(name string, value string, tags []string, rate float64) error {
+	return c.ClientInterface.Set(name, value, c.with(tags), rate)
+}
+
+func (c *spanTaggedStatsdClient) Timing(name string, value time.Duration, tags []string, rate float64) error {
+	return c.ClientInterface.Timing(name, value, c.with(tags), rate)
+}
+
+func (c *spanTaggedStatsdClient) TimeInMilliseconds(name string, value float64, tags []string, rate float64) error {
+	return c.ClientInterface.TimeInMilliseconds(name, value, c.with(tags), rate)
+}
@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+)
+
+// recordingStatsdClient is a statsd.ClientInterface recording Incr and Gauge calls.
+type recordingStatsdClient struct {
+	statsd.NoOpClient
+	rec *statsdtest.TestStatsdClient
+}
+
+func (c *recordingStatsdClient) Incr(name string, tags []string, rate float64) error {
+	return c.rec.Incr(name, tags, rate)
+}
+
+func (c *recordingStatsdClient) Gauge(name string, value float64, tags []string, rate float64) error {
+	return c.rec.Gauge(name, value, tags, rate)
+}
+
+func TestWithDogstatsdClient(t *testing.T) {
+	var tg statsdtest.TestStatsdClient
+	client := &recordingStatsdClient{rec: &tg}
+	tracer, _, flush, stop, err := startTestTracer(t, WithDogstatsdClient(client))
+	require.NoError(t, err)
+
+	tracer.StartSpan("operation").Finish()
+	flush(1)
+	stop()
+
+	assert.Contains(t, tg.CallNames(), "datadog.tracer.started")
+	assert.False(t, tg.Closed())
+}
+
+func TestStatsdClientFromContext(t *testing.T) {
+	t.Run("no-span", func(t *testing.T) {
+		client := &recordingStatsdClient{rec: &statsdtest.TestStatsdClient{}}
+		assert.Equal(t, statsd.ClientInterface(client), StatsdClientFromContext(context.Background(), client))
+	})
+
+	t.Run("span", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t, WithEnv("prod"), WithService("svc"))
+		require.NoError(t, err)
+		defer stop()
+
+		var tg statsdtest.TestStatsdClient
+		span := tracer.StartSpan("operation")
+		defer span.Finish()
+		ctx := ContextWithSpan(context.Background(), span)
+		tags := []string{"key:value"}
+
+		client := StatsdClientFromContext(ctx, &recordingStatsdClient{rec: &tg})
+		assert.NoError(t, client.Incr("my.metric", tags, 1))
+		assert.NoError(t, client.Gauge("my.gauge", 1, nil, 1))
+
+		traceID := "trace_id:" + strconv.FormatUint(span.Context().traceID.Lower(), 10)
+		if span.Context().traceID.HasUpper() {
+			traceID = "trace_id:" + span.Context().TraceID()
+		}
+		calls := tg.IncrCalls()
+		require.Len(t, calls, 1)
+		assert.ElementsMatch(t, []string{"key:value", traceID, "env:prod", "service:svc"}, calls[0].Tags())
+		assert.Equal(t, []string{"key:value"}, tags)
+		calls = tg.GaugeCalls()
+		require.Len(t, calls, 1)
+		assert.ElementsMatch(t, []string{traceID, "env:prod", "service:svc"}, calls[0].Tags())
+	})
+}
@@ -43,6 +43,10 @@ func startTelemetry(c *config) {
 		{Name: "lambda_mode", Value: c.logToStdout},
 		{Name: "send_retries", Value: c.sendRetries},
 		{Name: "retry_interval", Value: c.retryInterval},
+		{Name: "trace_flush_interval", Value: c.flushInterval},
+		{Name: "trace_flush_jitter_enabled", Value: c.flushJitter},
+		{Name: "trace_api_version", Value: c.traceProtocol},
+		{Name: "trace_parent_based_sampling_enabled", Value: c.parentBasedSampling},
 		{Name: "trace_startup_logs_enabled", Value: c.logStartup},
 		{Name: "service", Value: c.serviceName},
 		{Name: "universal_version", Value: c.universalVersion},
@@ -52,8 +56,10 @@ func startTelemetry(c *config) {
 		{Name: "agent_hostname", Value: c.hostname},
 		{Name: "runtime_metrics_enabled", Value: c.runtimeMetrics},
 		{Name: "runtime_metrics_v2_enabled", Value: c.runtimeMetricsV2},
+		{Name: "trace_span_duration_metrics_enabled", Value: c.spanDurationMetrics},
 		{Name: "dogstatsd_addr", Value: c.dogstatsdAddr},
 		{Name: "debug_stack_enabled", Value: !c.noDebugStack},
+		{Name: "exception_replay_enabled", Value: c.exceptionReplay},
 		{Name: "profiling_hotspots_enabled", Value: c.profilerHotspots},
 		{Name: "profiling_endpoints_enabled", Value: c.profilerEndpoints},
 		{Name: "trace_span_attribute_schema", Value: c.spanAttributeSchemaVersion},
@@ -61,6 +67,8 @@ func startTelemetry(c *config) {
 		{Name: "orchestrion_enabled", Value: c.orchestrionCfg.Enabled, Origin: telemetry.OriginCode},
 		{Name: "trace_enabled", Value: c.enabled.current, Origin: c.enabled.cfgOrigin},
 		{Name: "trace_log_directory", Value: c.logDirectory},
+		{Name: "trace_fips_mode", Value: c.fipsMode},
+		{Name: "fips_crypto_enabled", Value: fipsCryptoEnabled()},
 		c.traceSampleRate.toTelemetry(),
 		c.headerAsTags.toTelemetry(),
 		c.globalTags.toTelemetry(),
@@ -114,6 +122,7 @@ func startTelemetry(c *config) {
 	}
 	if c.logToStdout || c.ciVisibilityAgentless {
 		cfg.APIKey = os.Getenv("DD_API_KEY")
+		cfg.APIKeyResolver = c.apiKeyResolver
 	}
 	client, err := telemetry.NewClient(c.serviceName, c.env, c.version, cfg)
 	if err != nil {
@@ -68,6 +68,45 @@ func (c TextMapCarrier) ForeachKey(handler func(key, val string) error) error {
 	return nil
 }
 
+// EnvCarrier allows the use of a list of environment variables, in the "key=value" form of
+// os.Environ and exec.Cmd.Env, as both TextMapWriter and TextMapReader, to propagate a trace
+// to a child process. The keys are stored in upper case with their dashes replaced by
+// underscores, e.g. "x-datadog-trace-id" is stored as X_DATADOG_TRACE_ID, and read back in
+// lower case with their underscores replaced by dashes. A child process can continue the trace
+// with:
+//
+//	sctx, err := tracer.Extract(tracer.EnvCarrier(os.Environ()))
+type EnvCarrier []string
+
+var _ TextMapWriter = (*EnvCarrier)(nil)
+var _ TextMapReader = (*EnvCarrier)(nil)
+
+// Set implements TextMapWriter, replacing the variable of the key if it is already set.
+func (c *EnvCarrier) Set(key, val string) {
+	prefix := strings.ToUpper(strings.ReplaceAll(key, "-", "_")) + "="
+	for i, kv := range *c {
+		if strings.HasPrefix(kv, prefix) {
+			(*c)[i] = prefix + val
+			return
+		}
+	}
+	*c = append(*c, prefix+val)
+}
+
+// ForeachKey implements TextMapReader.
+func (c EnvCarrier) ForeachKey(handler func(key, val string) error) error {
+	for _, kv := range c {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		if err := handler(strings.ToLower(strings.ReplaceAll(k, "_", "-")), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 const (
 	headerPropagationStyleInject  = "DD_TRACE_PROPAGATION_STYLE_INJECT"
 	headerPropagationStyleExtract = "DD_TRACE_PROPAGATION_STYLE_EXTRACT"
@@ -105,6 +105,40 @@ func TestTextMapCarrierForeachKeyError(t *testing.T) {
 	assert.Equal(t, got, want)
 }
 
+func TestEnvCarrierSet(t *testing.T) {
+	c := EnvCarrier{"PATH=/bin", "X_DATADOG_TRACE_ID=1"}
+	c.Set("x-datadog-trace-id", "2")
+	c.Set("traceparent", "00-1-2-01")
+	assert.Equal(t, EnvCarrier{"PATH=/bin", "X_DATADOG_TRACE_ID=2", "TRACEPARENT=00-1-2-01"}, c)
+}
+
+func TestEnvCarrierForeachKey(t *testing.T) {
+	got := map[string]string{}
+	err := EnvCarrier{"X_DATADOG_TRACE_ID=1", "EMPTY=", "INVALID", "A=b=c"}.ForeachKey(func(k, v string) error {
+		got[k] = v
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"x-datadog-trace-id": "1", "empty": "", "a": "b=c"}, got)
+}
+
+func TestEnvCarrierInjectExtract(t *testing.T) {
+	tracer, err := newTracer()
+	assert.NoError(t, err)
+	defer tracer.Stop()
+	root := tracer.StartSpan("web.request")
+	root.SetBaggageItem("item", "x")
+	defer root.Finish()
+
+	env := EnvCarrier{"PATH=/bin"}
+	assert.NoError(t, tracer.Inject(root.Context(), &env))
+	sctx, err := tracer.Extract(env)
+	assert.NoError(t, err)
+	assert.Equal(t, root.Context().TraceID(), sctx.TraceID())
+	assert.Equal(t, root.Context().SpanID(), sctx.SpanID())
+	assert.Equal(t, "x", sctx.baggage["item"])
+}
+
 func TestTextMapExtractTracestatePropagation(t *testing.T) {
 	tests := []struct {
 		name, propagationStyle, traceparent string
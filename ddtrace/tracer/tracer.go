@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"math/rand/v2"
 	"os"
 	"runtime/pprof"
 	rt "runtime/trace"
@@ -122,6 +123,13 @@ type tracer struct {
 	// each component, including contribs and "manual" spans.
 	spansStarted, spansFinished globalinternal.XSyncMapCounterMap
 
+	// spanDurations aggregates the durations of finished spans when span
+	// duration metrics are enabled; nil otherwise.
+	spanDurations *spanDurationAggregator
+
+	// runtimeEnv caches the runtime environment tags of the process.
+	runtimeEnv atomic.Pointer[runtimeEnv]
+
 	// Keeps track of the total number of traces dropped for accurate logging.
 	totalTracesDropped uint32
 
@@ -157,12 +165,17 @@ type tracer struct {
 	// logFile is closed when tracer stops
 	// by default, tracer logs to stderr and this setting is unused
 	logFile *log.ManagedFile
+
+	// exceptionReplay and deterministicRand are the global states set by the
+	// tracer, cleared when it stops unless another tracer replaced them.
+	exceptionReplay   *exceptionReplayConfig
+	deterministicRand *lockedRand
 }
 
 const (
-	// flushInterval is the interval at which the payload contents will be flushed
-	// to the transport.
-	flushInterval = 2 * time.Second
+	// defaultFlushInterval is the default interval at which the payload contents
+	// will be flushed to the transport.
+	defaultFlushInterval = 2 * time.Second
 
 	// payloadMaxLimit is the maximum payload size allowed and should indicate the
 	// maximum size of the package that the agent can receive.
@@ -233,7 +246,7 @@ func Start(opts ...StartOption) error {
 	// client is appropriately configured.
 	appsecopts := make([]appsecConfig.StartOption, 0, len(t.config.appsecStartOptions)+1)
 	appsecopts = append(appsecopts, t.config.appsecStartOptions...)
-	appsecopts = append(appsecopts, appsecConfig.WithRCConfig(cfg), appsecConfig.WithMetaStructAvailable(t.config.agent.metaStructAvailable))
+	appsecopts = append(appsecopts, appsecConfig.WithRCConfig(cfg), appsecConfig.WithMetaStructAvailable(t.config.agent.metaStructAvailable && !t.config.canUseTraceV05()))
 
 	appsec.Start(appsecopts...)
 
@@ -330,6 +343,10 @@ func newUnstartedTracer(opts ...StartOption) (*tracer, error) {
 		writer = newCiVisibilityTraceWriter(c)
 	} else if c.logToStdout {
 		writer = newLogTraceWriter(c, statsd)
+	} else if c.devMode != nil && !c.devMode.agent {
+		writer = newDevTraceWriter(c.devMode, nil)
+	} else if c.devMode != nil {
+		writer = newDevTraceWriter(c.devMode, newAgentTraceWriter(c, sampler, statsd))
 	} else {
 		writer = newAgentTraceWriter(c, sampler, statsd)
 	}
@@ -393,6 +410,9 @@ func newUnstartedTracer(opts ...StartOption) (*tracer, error) {
 		dataStreams: dataStreamsProcessor,
 		logFile:     logFile,
 	}
+	if c.spanDurationMetrics {
+		t.spanDurations = newSpanDurationAggregator()
+	}
 	return t, nil
 }
 
@@ -408,22 +428,29 @@ func newTracer(opts ...StartOption) (*tracer, error) {
 	}
 	c := t.config
 	t.statsd.Incr("datadog.tracer.started", nil, 1)
-	if c.runtimeMetrics {
+	if c.runtimeMetrics || c.runtimeMetricsV2 {
 		log.Debug("Runtime metrics enabled.")
-		t.wg.Add(1)
-		go func() {
-			defer t.wg.Done()
-			t.reportRuntimeMetrics(defaultMetricsReportInterval)
-		}()
-	}
-	if c.runtimeMetricsV2 {
 		l := slog.New(slogHandler{})
-		if err := runtimemetrics.Start(t.statsd, l); err == nil {
+		statsd := filteredStatsdClient{StatsdClient: t.statsd, filter: c.runtimeMetricsV2Filter}
+		if err := runtimemetrics.Start(statsd, l); err == nil {
 			l.Debug("Runtime metrics v2 enabled.")
+			t.wg.Add(1)
+			go func() {
+				defer t.wg.Done()
+				t.reportCPUQuota(defaultMetricsReportInterval)
+			}()
 		} else {
 			l.Error("Failed to enable runtime metrics v2", "err", err.Error())
 		}
 	}
+	if c.exceptionReplay {
+		t.exceptionReplay = newExceptionReplayConfig(os.Getenv(envRedactedIdentifiers))
+	}
+	exceptionReplay.Store(t.exceptionReplay)
+	if c.deterministic {
+		t.deterministicRand = newLockedRand(c.deterministicSeed)
+	}
+	deterministicRand.Store(t.deterministicRand)
 	if c.debugAbandonedSpans {
 		log.Info("Abandoned spans logs enabled.")
 		t.abandonedSpansDebugger = newAbandonedSpansDebugger()
@@ -433,8 +460,10 @@ func newTracer(opts ...StartOption) (*tracer, error) {
 	go func() {
 		defer t.wg.Done()
 		tick := t.config.tickChan
-		if tick == nil {
-			ticker := time.NewTicker(flushInterval)
+		if tick == nil && !c.deterministic {
+			// In deterministic mode, traces are only flushed when the payload is
+			// full, and with Flush and Stop.
+			ticker := newFlushTicker(c.flushInterval, c.flushJitter)
 			defer ticker.Stop()
 			tick = ticker.C
 		}
@@ -445,10 +474,60 @@ func newTracer(opts ...StartOption) (*tracer, error) {
 		defer t.wg.Done()
 		t.reportHealthMetricsAtInterval(statsInterval)
 	}()
+	if !c.agentDisabled() && c.agentFeaturesRefresh > 0 {
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.refreshAgentFeatures(c.agentFeaturesRefresh)
+		}()
+	}
 	t.stats.Start()
 	return t, nil
 }
 
+// flushTicker is a ticker whose first tick may happen earlier than the next ones.
+type flushTicker struct {
+	*time.Ticker
+
+	// reset resets the period of the ticker after its first tick, if any.
+	reset *time.Timer
+
+	mu      sync.Mutex // guards stopped
+	stopped bool
+}
+
+// newFlushTicker returns a ticker firing every interval. When jitter is true, the
+// first tick is delayed by a random duration of up to interval, so that flushes
+// of many processes started at the same time, such as the pods of a deployment,
+// are spread out.
+func newFlushTicker(interval time.Duration, jitter bool) *flushTicker {
+	if !jitter {
+		return &flushTicker{Ticker: time.NewTicker(interval)}
+	}
+	first := time.Duration(rand.Int64N(int64(interval))) + 1
+	t := &flushTicker{Ticker: time.NewTicker(first)}
+	t.reset = time.AfterFunc(first, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if !t.stopped {
+			t.Ticker.Reset(interval)
+		}
+	})
+	return t
+}
+
+// Stop stops the ticker. Unlike a Reset of the underlying ticker, the pending
+// reset of its period doesn't restart it.
+func (t *flushTicker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	if t.reset != nil {
+		t.reset.Stop()
+	}
+	t.Ticker.Stop()
+}
+
 // Flush flushes any buffered traces. Flush is in effect only if a tracer
 // is started. Users do not have to call Flush in order to ensure that
 // traces reach Datadog. It is a convenience method dedicated to a specific
@@ -491,31 +570,44 @@ func (t *tracer) worker(tick <-chan time.Time) {
 
 		case done := <-t.flush:
 			t.statsd.Incr("datadog.tracer.flush_triggered", []string{"reason:invoked"}, 1)
+			// include the traces finished before the flush was triggered
+			t.drainPending()
 			t.traceWriter.flush()
 			t.statsd.Flush()
 			if !t.config.tracingAsTransport {
 				t.stats.flushAndSend(time.Now(), withCurrentBucket)
 			}
-			// TODO(x): In reality, the traceWriter.flush() call is not synchronous
-			// when using the agent traceWriter. However, this functionality is used
-			// in Lambda so for that purpose this mechanism should suffice.
-			done <- struct{}{}
+			if w, ok := t.traceWriter.(flushNotifier); ok {
+				// signal the caller once the traces are sent, so that they aren't lost
+				// when the process is frozen or stopped right after flushing, as in
+				// Lambda, without blocking the worker meanwhile.
+				go func(flushed <-chan struct{}) {
+					<-flushed
+					done <- struct{}{}
+				}(w.flushed())
+			} else {
+				done <- struct{}{}
+			}
 
 		case <-t.stop:
-		loop:
-			// the loop ensures that the payload channel is fully drained
+			// ensure that the payload channel is fully drained
 			// before the final flush to ensure no traces are lost (see #526)
-			for {
-				select {
-				case trace := <-t.out:
-					t.sampleChunk(trace)
-					if len(trace.spans) > 0 {
-						t.traceWriter.add(trace.spans)
-					}
-				default:
-					break loop
-				}
+			t.drainPending()
+			return
+		}
+	}
+}
+
+// drainPending adds the traces waiting in the payload channel to the trace writer.
+func (t *tracer) drainPending() {
+	for {
+		select {
+		case trace := <-t.out:
+			t.sampleChunk(trace)
+			if len(trace.spans) > 0 {
+				t.traceWriter.add(trace.spans)
 			}
+		default:
 			return
 		}
 	}
@@ -591,6 +683,9 @@ func spanStart(operationName string, options ...StartSpanOption) *Span {
 		}
 		fn(&opts)
 	}
+	if !integrationEnabled(opts.Tags[ext.Component]) {
+		return nil
+	}
 	var startTime int64
 	if opts.StartTime.IsZero() {
 		startTime = now()
@@ -665,6 +760,10 @@ func spanStart(operationName string, options ...StartSpanOption) *Span {
 
 	}
 	span.context = newSpanContext(span, context)
+	if context != nil && context.span == nil {
+		// the parent is remote: its sampling priority is the upstream decision
+		span.context.trace.setUpstreamPriority()
+	}
 	span.setMeta("language", "go")
 	// add tags from options
 	for k, v := range opts.Tags {
@@ -685,20 +784,43 @@ func spanStart(operationName string, options ...StartSpanOption) *Span {
 	return span
 }
 
+// integrationEnabled reports whether the integration named by the given component
+// tag is enabled, which is the case of spans not started by integrations.
+// Integrations can be disabled by setting DD_TRACE_<INTEGRATION>_ENABLED to false.
+func integrationEnabled(component interface{}) bool {
+	switch c := component.(type) {
+	case nil:
+		return true
+	case string:
+		return globalinternal.IntegrationEnabled(c)
+	case fmt.Stringer:
+		return globalinternal.IntegrationEnabled(c.String())
+	default:
+		return true
+	}
+}
+
 // StartSpan creates, starts, and returns a new Span with the given `operationName`.
 func (t *tracer) StartSpan(operationName string, options ...StartSpanOption) *Span {
 	if !t.config.enabled.current {
 		return nil
 	}
 	span := spanStart(operationName, options...)
+	if span == nil {
+		// the integration starting the span was disabled
+		return nil
+	}
 	if span.service == "" {
 		span.service = t.config.serviceName
 	}
 	span.noDebugStack = t.config.noDebugStack
+	if t.config.spanStackDepth > 0 {
+		span.creationStack = captureCreationStack(t.config.spanStackDepth)
+	}
 	if t.config.hostname != "" {
 		span.setMeta(keyHostname, t.config.hostname)
 	}
-	span.supportsEvents = t.config.agent.spanEventsAvailable
+	span.supportsEvents = t.config.spanEventsAvailable()
 
 	// add global tags
 	for k, v := range t.config.globalTags.get() {
@@ -731,7 +853,7 @@ func (t *tracer) StartSpan(operationName string, options ...StartSpanOption) *Sp
 		log.Debug("Started Span: %v, Operation: %s, Resource: %s, Tags: %v, %v",
 			span, span.name, span.resource, span.meta, span.metrics)
 	}
-	if t.config.profilerHotspots || t.config.profilerEndpoints {
+	if t.config.profilerHotspots || t.config.profilerEndpoints || len(traceprof.SpanTagLabels()) > 0 {
 		t.applyPPROFLabels(span.pprofCtxRestore, span)
 	} else {
 		span.pprofCtxRestore = nil
@@ -756,8 +878,9 @@ func (t *tracer) StartSpan(operationName string, options ...StartSpanOption) *Sp
 
 // applyPPROFLabels applies pprof labels for the profiler's code hotspots and
 // endpoint filtering feature to span. When span finishes, any pprof labels
-// found in ctx are restored. Additionally, this func informs the profiler how
-// many times each endpoint is called.
+// found in ctx are restored. The span tags selected by the profiler are applied
+// as well. Additionally, this func informs the profiler how many times each
+// endpoint is called.
 func (t *tracer) applyPPROFLabels(ctx gocontext.Context, span *Span) {
 	// Important: The label keys are ordered alphabetically to take advantage of
 	// an upstream optimization that landed in go1.24.  This results in ~10%
@@ -786,7 +909,16 @@ func (t *tracer) applyPPROFLabels(ctx gocontext.Context, span *Span) {
 		}
 		localRootSpan.mu.RUnlock()
 	}
-	if len(labels) > 0 {
+	// Apply the span tags selected by the profiler, for slicing the
+	// profiles by business dimensions. The labels are applied even if the
+	// span has none of the tags yet, so that the tags set later are.
+	tagLabels := traceprof.SpanTagLabels()
+	for _, k := range tagLabels {
+		if v, ok := span.meta[k]; ok {
+			labels = append(labels, k, v)
+		}
+	}
+	if len(labels) > 0 || len(tagLabels) > 0 {
 		span.pprofCtxRestore = ctx
 		span.pprofCtxActive = pprof.WithLabels(ctx, pprof.Labels(labels...))
 		pprof.SetGoroutineLabels(span.pprofCtxActive)
@@ -807,10 +939,19 @@ func (t *tracer) Stop() {
 		t.statsd.Incr("datadog.tracer.stopped", nil, 1)
 	})
 	globalconfig.SetServiceName("")
+	if t.exceptionReplay != nil {
+		exceptionReplay.CompareAndSwap(t.exceptionReplay, nil)
+	}
+	if t.deterministicRand != nil {
+		deterministicRand.CompareAndSwap(t.deterministicRand, nil)
+	}
 	t.abandonedSpansDebugger.Stop()
 	t.stats.Stop()
 	t.wg.Wait()
 	t.traceWriter.stop()
+	if t.config.agentEndpoints != nil {
+		t.config.agentEndpoints.stop()
+	}
 	t.statsd.Close()
 	if t.dataStreams != nil {
 		t.dataStreams.Stop()
@@ -883,6 +1024,12 @@ func (t *tracer) Extract(carrier interface{}) (*SpanContext, error) {
 			ctx.trace.priority = nil
 		}
 	}
+	if t.config.parentBasedSampling && ctx != nil && ctx.trace != nil {
+		if _, ok := ctx.trace.samplingPriority(); ok {
+			// the upstream sampling decision is final
+			ctx.trace.setLocked(true)
+		}
+	}
 	return ctx, err
 }
 
@@ -964,6 +1111,26 @@ func (t *tracer) sample(span *Span) {
 	t.prioritySampling.apply(span)
 }
 
+// newSpanSampler returns a function making the sampling decision of the root
+// spans the way a tracer configured from the environment does, i.e. with
+// DD_TRACE_SAMPLE_RATE, DD_TRACE_RATE_LIMIT and the sampling rules, without
+// starting a tracer. The rates of the agent are unknown and default to 1.
+// It is used by the mocktracer, through a go:linkname directive.
+func newSpanSampler() func(*Span) {
+	traceRules, spanRules, err := samplingRulesFromEnv()
+	if err != nil {
+		log.Warn("DIAGNOSTICS Error(s) parsing sampling rules, they are ignored: found errors:%s", err)
+		traceRules, spanRules = nil, nil
+	}
+	rateLimit, _ := rateLimitFromEnv()
+	t := &tracer{
+		config:           &config{sampler: NewAllSampler()},
+		rulesSampling:    newRulesSampler(traceRules, spanRules, globalSampleRateFromEnv(), rateLimit),
+		prioritySampling: newPrioritySampler(),
+	}
+	return t.sample
+}
+
 func startExecutionTracerTask(ctx gocontext.Context, span *Span) (gocontext.Context, func()) {
 	if !rt.IsEnabled() {
 		return ctx, func() {}
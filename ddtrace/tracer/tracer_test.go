@@ -34,6 +34,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
 
 	"github.com/DataDog/datadog-go/v5/statsd"
 	"github.com/stretchr/testify/assert"
@@ -1947,6 +1948,34 @@ func TestTracerReportsHostname(t *testing.T) {
 	})
 }
 
+func TestTracerRuntimeEnvTags(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t)
+		require.NoError(t, err)
+		defer stop()
+
+		root := tracer.StartSpan("root")
+		child := tracer.StartSpan("child", ChildOf(root.Context()))
+		child.Finish()
+		root.Finish()
+
+		assert.Equal(t, float64(runtime.GOMAXPROCS(0)), root.metrics[internal.RuntimeEnvGOMAXPROCS])
+		assert.Contains(t, root.metrics, internal.RuntimeEnvGOGC)
+		assert.NotContains(t, child.metrics, internal.RuntimeEnvGOMAXPROCS)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("DD_RUNTIME_ENV_TAGS_ENABLED", "false")
+		tracer, _, _, stop, err := startTestTracer(t)
+		require.NoError(t, err)
+		defer stop()
+
+		root := tracer.StartSpan("root")
+		root.Finish()
+		assert.NotContains(t, root.metrics, internal.RuntimeEnvGOMAXPROCS)
+	})
+}
+
 func TestVersion(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 		tracer, _, _, stop, err := startTestTracer(t, WithServiceVersion("4.5.6"))
@@ -2475,6 +2504,72 @@ loop:
 	assert.Len(t, transport.Stats(), 1)
 }
 
+func TestFlushSynchronous(t *testing.T) {
+	tr, transport, _, stop, err := startTestTracer(t)
+	require.NoError(t, err)
+	defer stop()
+
+	// the traces finished before flushing are sent by the time Flush returns
+	for i := 0; i < 10; i++ {
+		tr.StartSpan("op").Finish()
+		tr.Flush()
+		assert.Equal(t, i+1, transport.Len())
+	}
+}
+
+// blockingTransport is a transport whose sends block until release is closed.
+// It signals on sending whenever a send starts.
+type blockingTransport struct {
+	*dummyTransport
+	sending chan struct{}
+	release chan struct{}
+}
+
+func (t *blockingTransport) send(p *payload) (io.ReadCloser, error) {
+	select {
+	case t.sending <- struct{}{}:
+	default:
+	}
+	<-t.release
+	return t.dummyTransport.send(p)
+}
+
+func TestFlushDoesNotBlockWorker(t *testing.T) {
+	transport := &blockingTransport{
+		dummyTransport: newDummyTransport(),
+		sending:        make(chan struct{}, 1),
+		release:        make(chan struct{}),
+	}
+	tr, _, _, stop, err := startTestTracer(t, withTransport(transport))
+	require.NoError(t, err)
+	defer stop()
+
+	tr.StartSpan("op").Finish()
+	flushed := make(chan struct{})
+	go func() {
+		tr.Flush()
+		close(flushed)
+	}()
+	// wait for the upload of the first trace to start, so that the traces finished
+	// below aren't part of the flush.
+	<-transport.sending
+
+	// the worker keeps receiving the finished traces while the upload is in progress.
+	for i := 0; i < 10; i++ {
+		tr.StartSpan("op").Finish()
+	}
+	assert.Eventually(t, func() bool { return len(tr.out) == 0 }, 5*time.Second, time.Millisecond)
+	select {
+	case <-flushed:
+		t.Fatal("Flush returned before the traces were sent")
+	default:
+	}
+
+	close(transport.release)
+	<-flushed
+	assert.Equal(t, 1, transport.Len())
+}
+
 func TestTakeStackTrace(t *testing.T) {
 	t.Run("n=12", func(t *testing.T) {
 		val := takeStacktrace(12, 0)
@@ -2753,6 +2848,34 @@ func TestPprofLabels(t *testing.T) {
 	})
 }
 
+func TestSpanTagPprofLabels(t *testing.T) {
+	traceprof.SetSpanTagLabels([]string{"customer.tier"})
+	defer traceprof.SetSpanTagLabels(nil)
+	if err := Start(
+		WithProfilerCodeHotspots(false),
+		WithProfilerEndpoints(false),
+	); err != nil {
+		t.Fatal(err)
+	}
+	defer Stop()
+
+	span, ctx := StartSpanFromContext(context.Background(), "parent", Tag("customer.tier", "gold"), Tag("other", "value"))
+	tier, ok := pprof.Label(span.pprofCtxActive, "customer.tier")
+	assert.True(t, ok)
+	assert.Equal(t, "gold", tier)
+	_, ok = pprof.Label(span.pprofCtxActive, "other")
+	assert.False(t, ok)
+
+	child, _ := StartSpanFromContext(ctx, "child")
+	tier, _ = pprof.Label(child.pprofCtxActive, "customer.tier")
+	assert.Equal(t, "gold", tier, "the label is inherited from the parent span")
+	child.SetTag("customer.tier", "silver")
+	tier, _ = pprof.Label(child.pprofCtxActive, "customer.tier")
+	assert.Equal(t, "silver", tier)
+	child.Finish()
+	span.Finish()
+}
+
 func TestNoopTracerStartSpan(t *testing.T) {
 	r, w, err := os.Pipe()
 	if err != nil {
@@ -2852,3 +2975,183 @@ func TestExecTraceLargeTaskNameRegression(t *testing.T) {
 	s := StartSpan("test", ResourceName(b.String()))
 	s.Finish()
 }
+
+func TestNewFlushTicker(t *testing.T) {
+	t.Run("no-jitter", func(t *testing.T) {
+		ticker := newFlushTicker(10*time.Millisecond, false)
+		defer ticker.Stop()
+		start := time.Now()
+		<-ticker.C
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		interval := 50 * time.Millisecond
+		ticker := newFlushTicker(interval, true)
+		defer ticker.Stop()
+		start := time.Now()
+		<-ticker.C
+		// the first tick happens within the interval, allowing for scheduling delays
+		assert.Less(t, time.Since(start), 2*interval)
+		<-ticker.C
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		interval := 20 * time.Millisecond
+		ticker := newFlushTicker(interval, true)
+		// the ticker is stopped before the reset of its period
+		ticker.Stop()
+		select {
+		case <-ticker.C:
+			t.Fatal("unexpected tick of a stopped ticker")
+		case <-time.After(3 * interval):
+		}
+	})
+}
+
+func TestDeterministicMode(t *testing.T) {
+	t.Setenv("DD_TRACE_RATE_LIMIT", "1")
+	run := func() (ids []uint64) {
+		tracer, _, _, stop, err := startTestTracer(t,
+			WithDeterministicMode(42),
+			WithSamplingRules(TraceSamplingRules(Rule{ServiceGlob: "*", Rate: 0.5})),
+		)
+		require.NoError(t, err)
+		defer stop()
+		assert.True(t, deterministicMode())
+
+		for i := 0; i < 10; i++ {
+			root := tracer.StartSpan("root")
+			child := tracer.StartSpan("child", ChildOf(root.Context()))
+			assert.Zero(t, root.context.traceID.Upper())
+			p, _ := root.context.SamplingPriority()
+			ids = append(ids, root.context.traceID.Lower(), child.context.spanID, uint64(p))
+			child.Finish()
+			root.Finish()
+		}
+		return ids
+	}
+	first := run()
+	assert.False(t, deterministicMode())
+	assert.Equal(t, first, run())
+
+	// The rate limit doesn't apply, so both sampling decisions are taken.
+	var kept, dropped int
+	for i := 2; i < len(first); i += 3 {
+		switch int(first[i]) {
+		case ext.PriorityUserKeep:
+			kept++
+		case ext.PriorityUserReject:
+			dropped++
+		}
+	}
+	assert.Equal(t, 10, kept+dropped)
+	assert.Greater(t, kept, 1)
+}
+
+func TestParentBasedSampling(t *testing.T) {
+	upstream := func(priority string) TextMapCarrier {
+		return TextMapCarrier{
+			DefaultTraceIDHeader:  "1",
+			DefaultParentIDHeader: "2",
+			DefaultPriorityHeader: priority,
+		}
+	}
+
+	t.Run("remote", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t,
+			WithParentBasedSampling(),
+			WithSamplingRules(TraceSamplingRules(Rule{Rate: 0})))
+		require.NoError(t, err)
+		defer stop()
+
+		sctx, err := tracer.Extract(upstream("1"))
+		require.NoError(t, err)
+		span := tracer.StartSpan("op", ChildOf(sctx))
+		span.SetTag(ext.ManualDrop, true)
+		carrier := TextMapCarrier{}
+		require.NoError(t, tracer.Inject(span.Context(), carrier))
+		span.Finish()
+
+		p, ok := span.Context().SamplingPriority()
+		assert.True(t, ok)
+		assert.Equal(t, ext.PriorityAutoKeep, p)
+		assert.Equal(t, "1", carrier[DefaultPriorityHeader])
+		assert.NotContains(t, span.metrics, keyUpstreamSamplingPriority)
+	})
+
+	t.Run("w3c", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t,
+			WithParentBasedSampling(),
+			WithSamplingRules(TraceSamplingRules(Rule{Rate: 1})))
+		require.NoError(t, err)
+		defer stop()
+
+		sctx, err := tracer.Extract(TextMapCarrier{
+			traceparentHeader: "00-00000000000000000000000000000001-0000000000000002-00",
+		})
+		require.NoError(t, err)
+		span := tracer.StartSpan("op", ChildOf(sctx))
+		span.Finish()
+
+		p, ok := span.Context().SamplingPriority()
+		assert.True(t, ok)
+		assert.Equal(t, ext.PriorityAutoReject, p)
+	})
+
+	t.Run("root", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t,
+			WithParentBasedSampling(),
+			WithSamplingRules(TraceSamplingRules(Rule{Rate: 0})))
+		require.NoError(t, err)
+		defer stop()
+
+		span := tracer.StartSpan("op")
+		span.Finish()
+
+		p, ok := span.Context().SamplingPriority()
+		assert.True(t, ok)
+		assert.Equal(t, ext.PriorityUserReject, p)
+	})
+
+	t.Run("overridden", func(t *testing.T) {
+		tracer, _, _, stop, err := startTestTracer(t)
+		require.NoError(t, err)
+		defer stop()
+
+		sctx, err := tracer.Extract(upstream("1"))
+		require.NoError(t, err)
+		span := tracer.StartSpan("op", ChildOf(sctx))
+		span.SetTag(ext.ManualDrop, true)
+		span.Finish()
+
+		p, ok := span.Context().SamplingPriority()
+		assert.True(t, ok)
+		assert.Equal(t, ext.PriorityUserReject, p)
+		assert.Equal(t, float64(ext.PriorityAutoKeep), span.metrics[keyUpstreamSamplingPriority])
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("DD_TRACE_SAMPLER", "parentbased")
+		c, err := newConfig()
+		require.NoError(t, err)
+		assert.True(t, c.parentBasedSampling)
+	})
+}
+
+func TestStartSpanIntegrationDisabled(t *testing.T) {
+	tracer, _, _, stop, err := startTestTracer(t)
+	require.NoError(t, err)
+	defer stop()
+
+	internal.SetIntegrationEnabled("gin-gonic/gin", false)
+	defer internal.SetIntegrationEnabled("gin-gonic/gin", true)
+
+	assert.Nil(t, tracer.StartSpan("http.request", Tag(ext.Component, "gin-gonic/gin")))
+	span := tracer.StartSpan("http.request", Tag(ext.Component, "net/http"))
+	require.NotNil(t, span)
+	span.Finish()
+	span = tracer.StartSpan("op")
+	require.NotNil(t, span)
+	span.Finish()
+}
@@ -7,6 +7,7 @@ package tracer
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -63,6 +64,10 @@ const (
 	obfuscationVersionHeader = "Datadog-Obfuscation-Version" // header containing the version of obfuscation used, if any
 )
 
+// errTraceProtocolUnsupported is returned when the agent doesn't accept the
+// format of the payload.
+var errTraceProtocolUnsupported = errors.New("trace payload format not supported by the agent")
+
 // transport is an interface for communicating data to the agent.
 type transport interface {
 	// send sends the payload p to the agent using the transport set up.
@@ -76,10 +81,11 @@ type transport interface {
 }
 
 type httpTransport struct {
-	traceURL string            // the delivery URL for traces
-	statsURL string            // the delivery URL for stats
-	client   *http.Client      // the HTTP client used in the POST
-	headers  map[string]string // the Transport headers
+	traceURL    string            // the delivery URL for traces
+	traceV05URL string            // the delivery URL for traces encoded with the v0.5 format
+	statsURL    string            // the delivery URL for stats
+	client      *http.Client      // the HTTP client used in the POST
+	headers     map[string]string // the Transport headers
 }
 
 // newTransport returns a new Transport implementation that sends traces to a
@@ -108,10 +114,11 @@ func newHTTPTransport(url string, client *http.Client) *httpTransport {
 		defaultHeaders["Datadog-External-Env"] = extEnv
 	}
 	return &httpTransport{
-		traceURL: fmt.Sprintf("%s/v0.4/traces", url),
-		statsURL: fmt.Sprintf("%s/v0.6/stats", url),
-		client:   client,
-		headers:  defaultHeaders,
+		traceURL:    fmt.Sprintf("%s/v0.4/traces", url),
+		traceV05URL: fmt.Sprintf("%s/v0.5/traces", url),
+		statsURL:    fmt.Sprintf("%s/v0.6/stats", url),
+		client:      client,
+		headers:     defaultHeaders,
 	}
 }
 
@@ -150,7 +157,11 @@ func (t *httpTransport) sendStats(p *pb.ClientStatsPayload, tracerObfuscationVer
 }
 
 func (t *httpTransport) send(p *payload) (body io.ReadCloser, err error) {
-	req, err := http.NewRequest("POST", t.traceURL, p)
+	url := t.traceURL
+	if p.protocol() == traceProtocolV05 {
+		url = t.traceV05URL
+	}
+	req, err := http.NewRequest("POST", url, p)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create http request: %v", err)
 	}
@@ -187,6 +198,10 @@ func (t *httpTransport) send(p *payload) (body io.ReadCloser, err error) {
 	}
 	if code := response.StatusCode; code >= 400 {
 		reportAPIErrorsMetric(response, err)
+		if p.protocol() == traceProtocolV05 && (code == http.StatusNotFound || code == http.StatusUnsupportedMediaType) {
+			response.Body.Close()
+			return nil, errTraceProtocolUnsupported
+		}
 		// error, check the body for context information and
 		// return a nice error.
 		msg := make([]byte, 1000)
@@ -32,6 +32,12 @@ type traceWriter interface {
 	stop()
 }
 
+// flushNotifier is implemented by the trace writers sending traces asynchronously.
+type flushNotifier interface {
+	// flushed returns a channel closed once the traces flushed so far are sent.
+	flushed() <-chan struct{}
+}
+
 type agentTraceWriter struct {
 	// config holds the tracer configuration
 	config *config
@@ -45,6 +51,9 @@ type agentTraceWriter struct {
 	// wg waits for all uploads to finish
 	wg sync.WaitGroup
 
+	// uploads holds the channels closed by the uploads in progress, see flushed.
+	uploads []chan struct{}
+
 	// prioritySampling is the prioritySampler into which agentTraceWriter will
 	// read sampling rates sent by the agent
 	prioritySampling *prioritySampler
@@ -58,7 +67,7 @@ type agentTraceWriter struct {
 func newAgentTraceWriter(c *config, s *prioritySampler, statsdClient globalinternal.StatsdClient) *agentTraceWriter {
 	return &agentTraceWriter{
 		config:           c,
-		payload:          newPayload(),
+		payload:          newPayloadFor(c),
 		climit:           make(chan struct{}, concurrentConnectionLimit),
 		prioritySampling: s,
 		statsd:           statsdClient,
@@ -66,6 +75,10 @@ func newAgentTraceWriter(c *config, s *prioritySampler, statsdClient globalinter
 }
 
 func (h *agentTraceWriter) add(trace []*Span) {
+	if h.payload.itemCount() == 0 && (h.payload.protocol() == traceProtocolV05) != h.config.canUseTraceV05() {
+		// the agent features changed since the payload was created.
+		h.payload = newPayloadFor(h.config)
+	}
 	if err := h.payload.push(trace); err != nil {
 		h.statsd.Incr("datadog.tracer.traces_dropped", []string{"reason:encoding_error"}, 1)
 		log.Error("Error encoding msgpack: %v", err)
@@ -77,6 +90,32 @@ func (h *agentTraceWriter) add(trace []*Span) {
 	}
 }
 
+// flushed implements flushNotifier.
+func (h *agentTraceWriter) flushed() <-chan struct{} {
+	h.pruneUploads()
+	done := make(chan struct{})
+	go func(uploads []chan struct{}) {
+		for _, u := range uploads {
+			<-u
+		}
+		close(done)
+	}(h.uploads)
+	return done
+}
+
+// pruneUploads removes the finished uploads from h.uploads.
+func (h *agentTraceWriter) pruneUploads() {
+	inProgress := h.uploads[:0:0]
+	for _, u := range h.uploads {
+		select {
+		case <-u:
+		default:
+			inProgress = append(inProgress, u)
+		}
+	}
+	h.uploads = inProgress
+}
+
 func (h *agentTraceWriter) stop() {
 	h.statsd.Incr("datadog.tracer.flush_triggered", []string{"reason:shutdown"}, 1)
 	h.flush()
@@ -91,8 +130,12 @@ func (h *agentTraceWriter) flush() {
 	h.wg.Add(1)
 	h.climit <- struct{}{}
 	oldp := h.payload
-	h.payload = newPayload()
+	h.payload = newPayloadFor(h.config)
+	h.pruneUploads()
+	uploaded := make(chan struct{})
+	h.uploads = append(h.uploads, uploaded)
 	go func(p *payload) {
+		defer close(uploaded)
 		defer func(start time.Time) {
 			// Once the payload has been used, clear the buffer for garbage
 			// collection to avoid a memory leak when references to this object
@@ -122,6 +165,13 @@ func (h *agentTraceWriter) flush() {
 				}
 				return
 			}
+			if errors.Is(err, errTraceProtocolUnsupported) {
+				// the payload can't be sent again with another format, but
+				// the next ones are sent with the v0.4 format.
+				h.config.traceV05Rejected.Store(true)
+				log.Warn("The agent rejected traces sent with the %s format, sending them with the %s format.", p.protocol(), traceProtocolV04)
+				break
+			}
 			log.Error("failure sending traces (attempt %d of %d): %v", attempt+1, h.config.sendRetries+1, err)
 			p.reset()
 			time.Sleep(h.config.retryInterval)
@@ -131,6 +181,15 @@ func (h *agentTraceWriter) flush() {
 	}(oldp)
 }
 
+// newPayloadFor returns a payload encoding the traces with the v0.5 format
+// when it can be used with the agent, and with the v0.4 format otherwise.
+func newPayloadFor(c *config) *payload {
+	if c.canUseTraceV05() {
+		return newPayloadV05()
+	}
+	return newPayload()
+}
+
 // logWriter specifies the output target of the logTraceWriter; replaced in tests.
 var logWriter io.Writer = os.Stdout
 
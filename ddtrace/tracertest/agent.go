@@ -0,0 +1,346 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package tracertest provides an in-memory fake of the Datadog Agent, to write
+// end-to-end tests of an instrumentation: unlike the mocktracer, the spans go
+// through the real tracer, its sampling, its buffering and its flushes, and are
+// decoded from the payloads the tracer sends.
+//
+//	agent := tracertest.NewAgent()
+//	defer agent.Close()
+//	tracer.Start(tracer.WithAgentURL(agent.URL()))
+//	// ...run some code generating spans.
+//	tracer.Flush()
+//	spans, err := agent.WaitForSpans(ctx, 2)
+//
+// Failures of the agent, such as rate limiting or timeouts, can be simulated
+// with InjectFault, to test how the application behaves when the agent is
+// overloaded.
+package tracertest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Paths of the endpoints of the agent the tracer sends its data to.
+const (
+	PathInfo      = "/info"
+	PathTraces    = "/v0.4/traces"
+	PathStats     = "/v0.6/stats"
+	PathTelemetry = "/telemetry/proxy/api/v2/apmtelemetry"
+)
+
+// Span is a span as sent by the tracer to the agent.
+type Span struct {
+	Name     string             `json:"name"`
+	Service  string             `json:"service"`
+	Resource string             `json:"resource"`
+	Type     string             `json:"type"`
+	Start    int64              `json:"start"`
+	Duration int64              `json:"duration"`
+	Meta     map[string]string  `json:"meta"`
+	Metrics  map[string]float64 `json:"metrics"`
+	SpanID   uint64             `json:"span_id"`
+	TraceID  uint64             `json:"trace_id"`
+	ParentID uint64             `json:"parent_id"`
+	Error    int32              `json:"error"`
+}
+
+// Request is a request received by the agent.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	// Body is the body of the request, decompressed if it was compressed.
+	Body []byte
+	// Status is the status code the agent responded with.
+	Status int
+}
+
+// TelemetryRequest is a telemetry request forwarded by the agent.
+type TelemetryRequest struct {
+	RequestType string          `json:"request_type"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// Fault is a failure of the agent, injected with InjectFault.
+type Fault struct {
+	// Path is the path of the requests to fail, e.g. PathTraces, or empty
+	// for all of them.
+	Path string
+	// Status is the status code of the responses, e.g. 429 to simulate
+	// rate limiting. It defaults to 200 if Delay is set and to 500 otherwise.
+	Status int
+	// Delay delays the responses, e.g. to trigger the timeouts of the client.
+	Delay time.Duration
+	// Count is the number of requests to fail, or 0 to fail all of them
+	// until ClearFaults is called.
+	Count int
+}
+
+// Agent is a fake Datadog Agent, recording the traces, the stats and the
+// telemetry it receives.
+type Agent struct {
+	srv    *httptest.Server
+	closed chan struct{}
+
+	mu        sync.Mutex // guards below fields
+	requests  []Request
+	traces    [][]Span
+	stats     []map[string]any
+	telemetry []TelemetryRequest
+	faults    []*Fault
+}
+
+// NewAgent starts a fake agent listening on a local port. It must be closed
+// with Close.
+func NewAgent() *Agent {
+	a := &Agent{closed: make(chan struct{})}
+	a.srv = httptest.NewServer(http.HandlerFunc(a.handle))
+	return a
+}
+
+// URL returns the URL of the agent, to be passed to tracer.WithAgentURL.
+func (a *Agent) URL() string {
+	return a.srv.URL
+}
+
+// Addr returns the address of the agent, to be passed to tracer.WithAgentAddr.
+func (a *Agent) Addr() string {
+	return a.srv.Listener.Addr().String()
+}
+
+// Close stops the agent, failing the requests delayed by a fault.
+func (a *Agent) Close() {
+	close(a.closed)
+	a.srv.Close()
+}
+
+// InjectFault makes the agent fail the requests matching f.
+func (a *Agent) InjectFault(f Fault) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.faults = append(a.faults, &f)
+}
+
+// ClearFaults removes the faults injected with InjectFault.
+func (a *Agent) ClearFaults() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.faults = nil
+}
+
+// Reset clears the recorded requests, traces, stats and telemetry.
+func (a *Agent) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests, a.traces, a.stats, a.telemetry = nil, nil, nil, nil
+}
+
+// Requests returns the requests received by the agent, including the failed
+// ones.
+func (a *Agent) Requests() []Request {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Request(nil), a.requests...)
+}
+
+// Traces returns the traces accepted by the agent.
+func (a *Agent) Traces() [][]Span {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([][]Span(nil), a.traces...)
+}
+
+// Spans returns the spans of the traces accepted by the agent.
+func (a *Agent) Spans() []Span {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var spans []Span
+	for _, t := range a.traces {
+		spans = append(spans, t...)
+	}
+	return spans
+}
+
+// Stats returns the stats payloads accepted by the agent, decoded from
+// msgpack as generic JSON values.
+func (a *Agent) Stats() []map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]map[string]any(nil), a.stats...)
+}
+
+// Telemetry returns the telemetry requests accepted by the agent. The
+// messages of the batches are returned as separate requests.
+func (a *Agent) Telemetry() []TelemetryRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]TelemetryRequest(nil), a.telemetry...)
+}
+
+// WaitForSpans waits until the agent accepted at least n spans, and returns
+// them. It returns an error if ctx is done before.
+func (a *Agent) WaitForSpans(ctx context.Context, n int) ([]Span, error) {
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		if spans := a.Spans(); len(spans) >= n {
+			return spans, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("received %d spans out of %d: %w", len(a.Spans()), n, ctx.Err())
+		case <-tick.C:
+		}
+	}
+}
+
+// fault returns the fault matching the request to path, if any.
+func (a *Agent) fault(path string) (Fault, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, f := range a.faults {
+		if f.Path != "" && f.Path != path {
+			continue
+		}
+		if f.Count > 0 {
+			if f.Count--; f.Count == 0 {
+				a.faults = append(a.faults[:i], a.faults[i+1:]...)
+			}
+		}
+		return *f, true
+	}
+	return Fault{}, false
+}
+
+func (a *Agent) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body, Status: http.StatusOK}
+	if f, ok := a.fault(r.URL.Path); ok {
+		if f.Delay > 0 {
+			select {
+			case <-time.After(f.Delay):
+			case <-r.Context().Done():
+			case <-a.closed:
+			}
+		}
+		status := f.Status
+		if status == 0 && f.Delay == 0 {
+			status = http.StatusInternalServerError
+		}
+		if status != 0 && status != http.StatusOK {
+			req.Status = status
+			a.record(req)
+			w.WriteHeader(status)
+			return
+		}
+	}
+
+	var resp any = struct{}{}
+	switch r.URL.Path {
+	case PathInfo:
+		resp = map[string]any{
+			"version":   "tracertest",
+			"endpoints": []string{PathTraces, PathStats, "/telemetry/proxy/"},
+		}
+	case PathTraces:
+		var traces [][]Span
+		if err = decodeMsgpack(body, &traces); err == nil {
+			a.mu.Lock()
+			a.traces = append(a.traces, traces...)
+			a.mu.Unlock()
+		}
+		resp = map[string]any{"rate_by_service": map[string]float64{}}
+	case PathStats:
+		var stats map[string]any
+		if err = decodeMsgpack(body, &stats); err == nil {
+			a.mu.Lock()
+			a.stats = append(a.stats, stats)
+			a.mu.Unlock()
+		}
+	case PathTelemetry:
+		var msgs []TelemetryRequest
+		if msgs, err = decodeTelemetry(body); err == nil {
+			a.mu.Lock()
+			a.telemetry = append(a.telemetry, msgs...)
+			a.mu.Unlock()
+		}
+	default:
+		req.Status = http.StatusNotFound
+	}
+	if err != nil {
+		req.Status = http.StatusBadRequest
+	}
+	a.record(req)
+	if req.Status != http.StatusOK {
+		w.WriteHeader(req.Status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (a *Agent) record(req Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = append(a.requests, req)
+}
+
+// readBody returns the body of r, decompressed if needed.
+func readBody(r *http.Request) ([]byte, error) {
+	var body io.Reader = r.Body
+	if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	return io.ReadAll(body)
+}
+
+// decodeMsgpack decodes the msgpack data into v, through its JSON encoding.
+func decodeMsgpack(data []byte, v any) error {
+	var buf bytes.Buffer
+	if _, err := msgp.CopyToJSON(&buf, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	return json.Unmarshal(buf.Bytes(), v)
+}
+
+// decodeTelemetry decodes a telemetry request, splitting the batches in their
+// messages.
+func decodeTelemetry(data []byte) ([]TelemetryRequest, error) {
+	var req TelemetryRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	if req.RequestType != "message-batch" {
+		return []TelemetryRequest{req}, nil
+	}
+	var batch []TelemetryRequest
+	if err := json.Unmarshal(req.Payload, &batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracertest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentTraces(t *testing.T) {
+	agent := NewAgent()
+	defer agent.Close()
+
+	tracer.Start(tracer.WithAgentURL(agent.URL()), tracer.WithService("svc"), tracer.WithLogStartup(false))
+	defer tracer.Stop()
+
+	root := tracer.StartSpan("http.request", tracer.ResourceName("GET /"), tracer.Tag("rows", 3))
+	child := tracer.StartSpan("db.query", tracer.ChildOf(root.Context()), tracer.Tag("db.system", "postgresql"))
+	child.Finish()
+	root.Finish()
+	tracer.Flush()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	spans, err := agent.WaitForSpans(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, agent.Traces(), 1)
+
+	byName := make(map[string]Span)
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	assert.Equal(t, "svc", byName["http.request"].Service)
+	assert.Equal(t, "GET /", byName["http.request"].Resource)
+	assert.Equal(t, float64(3), byName["http.request"].Metrics["rows"])
+	assert.Equal(t, "postgresql", byName["db.query"].Meta["db.system"])
+	assert.Equal(t, byName["http.request"].SpanID, byName["db.query"].ParentID)
+	assert.Equal(t, byName["http.request"].TraceID, byName["db.query"].TraceID)
+
+	var paths []string
+	for _, r := range agent.Requests() {
+		paths = append(paths, r.Path)
+	}
+	assert.Contains(t, paths, PathTraces)
+}
+
+func TestAgentFaults(t *testing.T) {
+	agent := NewAgent()
+	defer agent.Close()
+	post := func(path string, body string) int {
+		t.Helper()
+		resp, err := http.Post(agent.URL()+path, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	// An empty list of traces, encoded with msgpack.
+	emptyTraces := string([]byte{0x90})
+
+	agent.InjectFault(Fault{Path: PathTraces, Status: http.StatusTooManyRequests, Count: 2})
+	assert.Equal(t, http.StatusOK, post(PathStats, string([]byte{0x80})))
+	assert.Equal(t, http.StatusTooManyRequests, post(PathTraces, emptyTraces))
+	assert.Equal(t, http.StatusTooManyRequests, post(PathTraces, emptyTraces))
+	assert.Equal(t, http.StatusOK, post(PathTraces, emptyTraces))
+
+	agent.InjectFault(Fault{})
+	assert.Equal(t, http.StatusInternalServerError, post(PathInfo, ""))
+	agent.ClearFaults()
+
+	requests := agent.Requests()
+	require.Len(t, requests, 5)
+	assert.Equal(t, http.StatusTooManyRequests, requests[1].Status)
+	assert.Equal(t, http.StatusOK, requests[3].Status)
+	assert.Equal(t, http.StatusInternalServerError, requests[4].Status)
+	assert.Len(t, agent.Stats(), 1)
+	agent.Reset()
+	assert.Empty(t, agent.Requests())
+
+	agent.InjectFault(Fault{Delay: time.Second})
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	_, err := client.Post(agent.URL()+PathTraces, "application/msgpack", bytes.NewReader([]byte{0x90}))
+	assert.Error(t, err)
+}
+
+func TestAgentTelemetry(t *testing.T) {
+	agent := NewAgent()
+	defer agent.Close()
+
+	body := `{"request_type":"message-batch","payload":[{"request_type":"app-started","payload":{}},{"request_type":"generate-metrics","payload":{"series":[]}}]}`
+	resp, err := http.Post(agent.URL()+PathTelemetry, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	telemetry := agent.Telemetry()
+	require.Len(t, telemetry, 2)
+	assert.Equal(t, "app-started", telemetry[0].RequestType)
+	assert.Equal(t, "generate-metrics", telemetry[1].RequestType)
+	assert.JSONEq(t, `{"series":[]}`, string(telemetry[1].Payload))
+}
@@ -47,6 +47,19 @@ func init() {
 	registerActionHandler("block_request", NewBlockAction)
 }
 
+// SetBlockedTemplates replaces the HTML and JSON templates used to write the responses of blocked
+// requests, taking precedence over DD_APPSEC_HTTP_BLOCKED_TEMPLATE_HTML and
+// DD_APPSEC_HTTP_BLOCKED_TEMPLATE_JSON. A nil template leaves the current one unchanged. It must be
+// called before AppSec starts monitoring requests.
+func SetBlockedTemplates(html, json []byte) {
+	if html != nil {
+		blockedTemplateHTML = html
+	}
+	if json != nil {
+		blockedTemplateJSON = json
+	}
+}
+
 type (
 	// blockActionParams are the dynamic parameters to be provided to a "block_request"
 	// action type upon invocation
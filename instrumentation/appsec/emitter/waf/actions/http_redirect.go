@@ -38,7 +38,7 @@ func newRedirectRequestAction(status int, loc string) *BlockHTTP {
 
 	// If location is not set we fall back on a default block action
 	if loc == "" {
-		return &BlockHTTP{Handler: newBlockHandler(http.StatusForbidden, string(blockedTemplateJSON))}
+		return &BlockHTTP{Handler: newBlockHandler(http.StatusForbidden, "auto")}
 	}
 	return &BlockHTTP{Handler: http.RedirectHandler(loc, status)}
 }
@@ -0,0 +1,156 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package cachetrace records the hits, misses and evictions of in-process caches, as span
+// events on the active span and as statsd metrics. It is used by the integrations of cache
+// libraries, which are configured with its options.
+package cachetrace
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+const tracerPrefix = "datadog.tracer."
+
+// The names of the span events added on cache lookups.
+const (
+	EventHit  = "cache.hit"
+	EventMiss = "cache.miss"
+)
+
+// The names of the statsd metrics, tagged with the cache system and name.
+const (
+	MetricHits      = tracerPrefix + "cache.hits"
+	MetricMisses    = tracerPrefix + "cache.misses"
+	MetricEvictions = tracerPrefix + "cache.evictions"
+)
+
+// The attributes of the span events.
+const (
+	AttributeSystem = "cache.system"
+	AttributeName   = "cache.name"
+)
+
+// Config is the configuration of a Recorder.
+type Config struct {
+	// System is the name of the cache library, e.g. "ristretto".
+	System string
+	// Name identifies the cache among the caches of the application.
+	Name string
+	// SpanEvents enables the span events of hits and misses.
+	SpanEvents bool
+	// Metrics enables the statsd metrics of hits, misses and evictions.
+	Metrics bool
+	// Statsd is the client the metrics are sent with. When nil and the metrics are enabled,
+	// NewRecorder creates one.
+	Statsd instrumentation.StatsdClient
+}
+
+// Option describes options for the integrations of cache libraries.
+type Option func(*Config)
+
+// NewConfig returns the default configuration of a cache of the given system, with the given
+// default name, and the given options applied.
+func NewConfig(system, name string, opts ...Option) Config {
+	cfg := Config{System: system, Name: name, SpanEvents: true}
+	for _, fn := range opts {
+		fn(&cfg)
+	}
+	return cfg
+}
+
+// WithName sets the name of the cache, added to its span events and metrics to tell it apart
+// from the other caches of the application.
+func WithName(name string) Option {
+	return func(cfg *Config) {
+		cfg.Name = name
+	}
+}
+
+// WithSpanEvents enables or disables the "cache.hit" and "cache.miss" span events added on the
+// active span by the lookups of the cache, enabled by default.
+func WithSpanEvents(enabled bool) Option {
+	return func(cfg *Config) {
+		cfg.SpanEvents = enabled
+	}
+}
+
+// WithMetrics enables or disables the statsd metrics of the hits, misses and evictions of the
+// cache, disabled by default.
+func WithMetrics(enabled bool) Option {
+	return func(cfg *Config) {
+		cfg.Metrics = enabled
+	}
+}
+
+// Recorder records the hits, misses and evictions of a cache.
+type Recorder struct {
+	cfg   Config
+	attrs map[string]any
+	tags  []string
+}
+
+// NewRecorder returns a Recorder with the given configuration. When the metrics are enabled
+// without a statsd client, it is created with instr, inheriting its address from the tracer's.
+func NewRecorder(instr *instrumentation.Instrumentation, cfg Config) *Recorder {
+	if !cfg.Metrics {
+		cfg.Statsd = nil
+	} else if cfg.Statsd == nil {
+		if sc, err := instr.StatsdClient(nil); err == nil {
+			cfg.Statsd = sc
+		} else {
+			instr.Logger().Warn("cachetrace: Error creating statsd client for %s; cache metrics will be dropped: %v", cfg.System, err)
+		}
+	}
+	attrs := map[string]any{AttributeSystem: cfg.System}
+	tags := []string{"cache_system:" + cfg.System}
+	if cfg.Name != "" {
+		attrs[AttributeName] = cfg.Name
+		tags = append(tags, "cache_name:"+cfg.Name)
+	}
+	return &Recorder{cfg: cfg, attrs: attrs, tags: tags}
+}
+
+// MetricsEnabled reports whether the metrics are sent.
+func (r *Recorder) MetricsEnabled() bool {
+	return r.cfg.Statsd != nil
+}
+
+// Hit records a cache hit, on the span found in ctx.
+func (r *Recorder) Hit(ctx context.Context) {
+	r.record(ctx, EventHit, MetricHits)
+}
+
+// Miss records a cache miss, on the span found in ctx.
+func (r *Recorder) Miss(ctx context.Context) {
+	r.record(ctx, EventMiss, MetricMisses)
+}
+
+func (r *Recorder) record(ctx context.Context, event, metric string) {
+	if r.cfg.SpanEvents {
+		if span, ok := tracer.SpanFromContext(ctx); ok {
+			span.AddEvent(event, tracer.WithSpanEventAttributes(r.attrs))
+		}
+	}
+	if r.cfg.Statsd != nil {
+		r.cfg.Statsd.Incr(metric, r.tags, 1)
+	}
+}
+
+// Evicted records n evictions for the given reason. Evictions happen outside of the requests
+// to the cache, so they are only sent as metrics.
+func (r *Recorder) Evicted(reason string, n int64) {
+	if r.cfg.Statsd == nil || n <= 0 {
+		return
+	}
+	tags := r.tags
+	if reason != "" {
+		tags = append(tags[:len(tags):len(tags)], "reason:"+reason)
+	}
+	r.cfg.Statsd.Count(MetricEvictions, n, tags, 1)
+}
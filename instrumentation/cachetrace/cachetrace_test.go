@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package cachetrace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	statsd := testutils.NewMockStatsdClient()
+	r := NewRecorder(nil, Config{System: "test", Name: "users", SpanEvents: true, Metrics: true, Statsd: statsd})
+
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "request")
+	r.Hit(ctx)
+	r.Miss(ctx)
+	r.Hit(context.Background())
+	r.Evicted("expired", 2)
+	r.Evicted("no_space", 0)
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, EventHit, events[0].Name)
+	assert.Equal(t, EventMiss, events[1].Name)
+	events[0].AssertAttributes(t, map[string]any{AttributeName: "users", AttributeSystem: "test"})
+
+	counts := statsd.Counts()
+	assert.Equal(t, int64(2), counts[MetricHits])
+	assert.Equal(t, int64(1), counts[MetricMisses])
+	assert.Equal(t, int64(2), counts[MetricEvictions])
+	calls := statsd.GetCallsByName(MetricEvictions)
+	require.Len(t, calls, 1)
+	assert.Equal(t, []string{"cache_system:test", "cache_name:users", "reason:expired"}, calls[0].Tags())
+}
+
+func TestRecorderDisabled(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	r := NewRecorder(nil, Config{System: "test", Statsd: testutils.NewMockStatsdClient()})
+	assert.False(t, r.MetricsEnabled())
+	span, ctx := tracer.StartSpanFromContext(context.Background(), "request")
+	r.Hit(ctx)
+	r.Miss(ctx)
+	r.Evicted("expired", 1)
+	span.Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Empty(t, spans[0].Events())
+}
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig("test", "group")
+	assert.Equal(t, Config{System: "test", Name: "group", SpanEvents: true}, cfg)
+
+	cfg = NewConfig("test", "group", WithName("users"), WithSpanEvents(false), WithMetrics(true))
+	assert.Equal(t, Config{System: "test", Name: "users", Metrics: true}, cfg)
+}
@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package instrumentation_test
+
+import (
+	"context"
+	"log"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+// This example instruments the client of an in-house RPC framework, example.com/rpc, with a custom orchestrion
+// aspect. The aspect is defined in the orchestrion.yml file of a package of the application, such as
+// example.com/rpc/rpctrace below, which is imported in the orchestrion.tool.go file of the application:
+//
+//	aspects:
+//	  - id: Client.Invoke
+//	    join-point:
+//	      function-body:
+//	        function:
+//	          - receiver: '*example.com/rpc.Client'
+//	          - name: Invoke
+//	    advice:
+//	      - prepend-statements:
+//	          imports:
+//	            rpctrace: example.com/rpc/rpctrace
+//	          template: |-
+//	            {{- $ctx := .Function.Argument 0 -}}
+//	            {{- $method := .Function.Argument 1 -}}
+//	            {{- $err := .Function.Result 0 -}}
+//	            var __dd_finish func(error)
+//	            {{ $ctx }}, __dd_finish = rpctrace.StartInvoke({{ $ctx }}, {{ $method }})
+//	            defer func() { __dd_finish({{ $err }}) }()
+//
+// The code injected by the aspect calls the helpers of the rpctrace package, which registers the instrumentation of
+// the framework so that it is reported in telemetry and can be disabled with DD_TRACE_EXAMPLE_RPC_ENABLED=false.
+func ExampleRegister() {
+	// Typically done in the init function of the rpctrace package.
+	err := instrumentation.Register("example.com/rpc", instrumentation.PackageInfo{
+		TracedPackage: "example.com/rpc",
+		EnvVarPrefix:  "EXAMPLE_RPC",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	instr := instrumentation.Load("example.com/rpc")
+
+	// StartInvoke is called at the start of every call to (*rpc.Client).Invoke, and the returned function when it
+	// returns.
+	startInvoke := func(ctx context.Context, method string) (context.Context, func(error)) {
+		if !instr.Enabled() {
+			return ctx, func(error) {}
+		}
+		span, ctx := tracer.StartSpanFromContext(ctx, "rpc.client.request",
+			tracer.ResourceName(method),
+			tracer.Tag(ext.Component, "example.com/rpc"),
+			tracer.Tag(ext.SpanKind, ext.SpanKindClient),
+		)
+		return ctx, func(err error) { span.Finish(tracer.WithError(err)) }
+	}
+
+	_, finish := startInvoke(context.Background(), "Greeter.SayHello")
+	finish(nil)
+}
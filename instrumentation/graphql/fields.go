@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package graphql
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+const (
+	// TagOperationDepth is the maximum nesting depth of the selections of an operation.
+	TagOperationDepth = "graphql.operation.depth"
+	// TagOperationComplexity is the complexity, or cost, of an operation.
+	TagOperationComplexity = "graphql.operation.complexity"
+	// TagFieldsDropped is the number of fields of an operation which were resolved without
+	// being traced, because of the limit of traced fields.
+	TagFieldsDropped = "graphql.fields.dropped"
+	// FieldEvent is the name of the span events recording resolved fields on the span of
+	// their operation.
+	FieldEvent = "graphql.field"
+)
+
+// FieldSampler decides which of the resolved fields of an operation are traced, so that
+// large queries don't produce thousands of spans. It is safe for concurrent use, as fields
+// may be resolved concurrently.
+type FieldSampler struct {
+	limit   int64
+	events  bool
+	traced  atomic.Int64
+	dropped atomic.Int64
+}
+
+// NewFieldSampler returns a FieldSampler for an operation, tracing at most limit fields, or
+// all of them if limit is negative. If events is true, the fields are recorded as span
+// events on the span of the operation instead of child spans.
+func NewFieldSampler(limit int, events bool) *FieldSampler {
+	return &FieldSampler{limit: int64(limit), events: events}
+}
+
+// Events reports whether the fields are recorded as span events rather than spans.
+func (s *FieldSampler) Events() bool {
+	return s.events
+}
+
+// Sample reports whether the next resolved field is traced, counting it as dropped if not.
+func (s *FieldSampler) Sample() bool {
+	if s.limit < 0 || s.traced.Add(1) <= s.limit {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// RecordField adds a FieldEvent to the span of an operation for a field resolved between
+// start and now, with the resource and path of the field and the error it failed with.
+func (s *FieldSampler) RecordField(span *tracer.Span, resource, path string, start time.Time, err error) {
+	attrs := map[string]any{
+		"resource":    resource,
+		"path":        path,
+		"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		attrs["error.message"] = err.Error()
+	}
+	span.AddEvent(FieldEvent, tracer.WithSpanEventTimestamp(start), tracer.WithSpanEventAttributes(attrs))
+}
+
+// Finish tags the span of the operation with the number of dropped fields, if any.
+func (s *FieldSampler) Finish(span *tracer.Span) {
+	if n := s.dropped.Load(); n > 0 {
+		span.SetTag(TagFieldsDropped, n)
+	}
+}
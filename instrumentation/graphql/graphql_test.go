@@ -8,6 +8,7 @@ package graphql
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,3 +96,42 @@ func TestAddErrorsAsSpanEvents(t *testing.T) {
 	}
 	events[1].AssertAttributes(t, wantAttrs2)
 }
+
+func TestFieldSampler(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	t.Run("limit", func(t *testing.T) {
+		s := NewFieldSampler(2, false)
+		assert.False(t, s.Events())
+		assert.True(t, s.Sample())
+		assert.True(t, s.Sample())
+		assert.False(t, s.Sample())
+		assert.False(t, s.Sample())
+
+		span := tracer.StartSpan("op")
+		s.Finish(span)
+		span.Finish()
+		assert.Equal(t, float64(2), mocktracer.MockSpan(span).Tag(TagFieldsDropped))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		s := NewFieldSampler(-1, true)
+		for range 100 {
+			assert.True(t, s.Sample())
+		}
+		span := tracer.StartSpan("op")
+		s.RecordField(span, "Query.user", "user", time.Now(), errors.New("oops"))
+		s.Finish(span)
+		span.Finish()
+
+		ms := mocktracer.MockSpan(span)
+		assert.Nil(t, ms.Tag(TagFieldsDropped))
+		events := ms.Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, FieldEvent, events[0].Name)
+		assert.Equal(t, "Query.user", events[0].Attributes["resource"])
+		assert.Equal(t, "user", events[0].Attributes["path"])
+		assert.Equal(t, "oops", events[0].Attributes["error.message"])
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/httpsec"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/options"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec"
@@ -22,7 +23,8 @@ type ServeConfig struct {
 	// Service specifies the service name to use. If left blank, the global service name
 	// will be inherited.
 	Service string
-	// Resource optionally specifies the resource name for this request.
+	// Resource optionally specifies the resource name for this request. When Route is empty, the
+	// unbounded values of its path are collapsed, see GuardResource.
 	Resource string
 	// QueryParams should be true in order to append the URL query values to the  "http.url" tag.
 	QueryParams bool
@@ -38,6 +40,9 @@ type ServeConfig struct {
 	SpanOpts []tracer.StartSpanOption
 	// isStatusError allows customization of error code determination.
 	IsStatusError func(int) bool
+	// ResponseHeaderTags specifies the response headers to set as tags of the request
+	// span (optional). See HeaderTagsFromResponse.
+	ResponseHeaderTags instrumentation.HeaderTags
 }
 
 // BeforeHandle contains functionality that should be executed before a http.Handler runs.
@@ -56,7 +61,12 @@ func BeforeHandle(cfg *ServeConfig, w http.ResponseWriter, r *http.Request) (htt
 		opts = append(opts, tracer.ServiceName(cfg.Service))
 	}
 	if cfg.Resource != "" {
-		opts = append(opts, tracer.ResourceName(cfg.Resource))
+		resource := cfg.Resource
+		if cfg.Route == "" {
+			// the resource is built from the request path when the request matched no route
+			resource = GuardResource(resource)
+		}
+		opts = append(opts, tracer.ResourceName(resource))
 	}
 	if cfg.Route != "" {
 		opts = append(opts, tracer.Tag(ext.HTTPRoute, cfg.Route))
@@ -64,7 +74,25 @@ func BeforeHandle(cfg *ServeConfig, w http.ResponseWriter, r *http.Request) (htt
 	span, ctx, finishSpans := StartRequestSpan(r, opts...)
 	rw, ddrw := wrapResponseWriter(w)
 	rt := r.WithContext(ctx)
+	var body *countingBody
+	if rt.ContentLength < 0 && rt.Body != nil && rt.Body != http.NoBody {
+		// the size of the body is unknown: count the bytes read by the handler
+		body = &countingBody{ReadCloser: rt.Body}
+		rt.Body = body
+	}
 	closeSpan := func() {
+		if body != nil {
+			span.SetTag(ext.HTTPRequestContentLength, body.n.Load())
+		} else {
+			span.SetTag(ext.HTTPRequestContentLength, max(r.ContentLength, 0))
+		}
+		span.SetTag(ext.HTTPResponseContentLength, ddrw.written)
+		if ddrw.streamed() {
+			span.SetTag(ext.HTTPResponseStreamed, true)
+		}
+		for k, v := range HeaderTagsFromResponse(ddrw.Header(), cfg.ResponseHeaderTags) {
+			span.SetTag(k, v)
+		}
 		finishSpans(ddrw.status, cfg.IsStatusError, cfg.FinishOpts...)
 	}
 	afterHandle := closeSpan
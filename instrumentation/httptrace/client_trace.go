@@ -0,0 +1,126 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httptrace
+
+import (
+	"context"
+	"crypto/tls"
+	nethttptrace "net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// The metrics of the phases of an http client request, set by WithClientTrace. The durations
+// are in milliseconds.
+const (
+	// MetricConnReused is 1 when the request was sent on a reused connection, 0 otherwise.
+	MetricConnReused = "http.client.conn_reused"
+	// MetricDNS is the duration of the resolution of the host of the request.
+	MetricDNS = "http.client.dns_ms"
+	// MetricConnect is the duration of the connection to the server, from the first connection
+	// attempt to the first established connection.
+	MetricConnect = "http.client.connect_ms"
+	// MetricTLS is the duration of the TLS handshake.
+	MetricTLS = "http.client.tls_ms"
+	// MetricTTFB is the time to first byte, from the start of the request to the reception of
+	// the first byte of the response headers.
+	MetricTTFB = "http.client.ttfb_ms"
+)
+
+// WithClientTrace returns a copy of ctx holding a net/http/httptrace.ClientTrace recording the
+// phases of the http client request sent with the context on span: whether its connection was
+// reused, and the durations of its DNS resolution, connection, TLS handshake and time to first
+// byte. The phases which didn't happen, such as the resolution of a reused connection, aren't
+// recorded. The hooks of a ClientTrace already held by ctx are still called.
+func WithClientTrace(ctx context.Context, span *tracer.Span) context.Context {
+	t := &clientTimings{span: span, start: time.Now()}
+	return nethttptrace.WithClientTrace(ctx, &nethttptrace.ClientTrace{
+		GotConn:              t.gotConn,
+		DNSStart:             t.dnsStart,
+		DNSDone:              t.dnsDone,
+		ConnectStart:         t.connectStart,
+		ConnectDone:          t.connectDone,
+		TLSHandshakeStart:    t.tlsHandshakeStart,
+		TLSHandshakeDone:     t.tlsHandshakeDone,
+		GotFirstResponseByte: t.gotFirstResponseByte,
+	})
+}
+
+// clientTimings records the phases of a request on its span. The hooks may be called
+// concurrently, such as the connection attempts to the IPv4 and IPv6 addresses of a host.
+type clientTimings struct {
+	span  *tracer.Span
+	start time.Time
+
+	mu        sync.Mutex
+	dns       time.Time
+	connect   time.Time
+	connected bool
+	tls       time.Time
+}
+
+func (t *clientTimings) gotConn(info nethttptrace.GotConnInfo) {
+	if info.Reused {
+		t.span.SetTag(MetricConnReused, 1)
+	} else {
+		t.span.SetTag(MetricConnReused, 0)
+	}
+}
+
+func (t *clientTimings) dnsStart(nethttptrace.DNSStartInfo) {
+	t.mu.Lock()
+	t.dns = time.Now()
+	t.mu.Unlock()
+}
+
+func (t *clientTimings) dnsDone(nethttptrace.DNSDoneInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.dns.IsZero() {
+		t.span.SetTag(MetricDNS, millis(time.Since(t.dns)))
+	}
+}
+
+func (t *clientTimings) connectStart(string, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.connect.IsZero() {
+		t.connect = time.Now()
+	}
+}
+
+func (t *clientTimings) connectDone(_, _ string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil && !t.connected && !t.connect.IsZero() {
+		t.connected = true
+		t.span.SetTag(MetricConnect, millis(time.Since(t.connect)))
+	}
+}
+
+func (t *clientTimings) tlsHandshakeStart() {
+	t.mu.Lock()
+	t.tls = time.Now()
+	t.mu.Unlock()
+}
+
+func (t *clientTimings) tlsHandshakeDone(_ tls.ConnectionState, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil && !t.tls.IsZero() {
+		t.span.SetTag(MetricTLS, millis(time.Since(t.tls)))
+	}
+}
+
+func (t *clientTimings) gotFirstResponseByte() {
+	t.span.SetTag(MetricTTFB, millis(time.Since(t.start)))
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
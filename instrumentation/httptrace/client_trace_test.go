@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httptrace
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithClientTrace(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	client := srv.Client()
+	// the certificate of the server is valid for example.com, resolved as localhost by the
+	// transport so that the request goes through a DNS resolution
+	url := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	transport := client.Transport.(*http.Transport)
+	transport.TLSClientConfig.ServerName = "example.com"
+
+	do := func() {
+		span := tracer.StartSpan("http.request")
+		req, err := http.NewRequestWithContext(WithClientTrace(context.Background(), span), http.MethodGet, url, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		span.Finish()
+	}
+	do()
+	do()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	first, second := spans[0], spans[1]
+	assert.Equal(t, float64(0), first.Tag(MetricConnReused))
+	for _, m := range []string{MetricDNS, MetricConnect, MetricTLS, MetricTTFB} {
+		assert.IsType(t, float64(0), first.Tag(m), m)
+	}
+	assert.Equal(t, float64(1), second.Tag(MetricConnReused))
+	assert.IsType(t, float64(0), second.Tag(MetricTTFB))
+	for _, m := range []string{MetricDNS, MetricConnect, MetricTLS} {
+		assert.Nil(t, second.Tag(m), m)
+	}
+}
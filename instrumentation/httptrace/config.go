@@ -28,6 +28,8 @@ const (
 	envServerErrorStatuses = "DD_TRACE_HTTP_SERVER_ERROR_STATUSES"
 	// envInferredProxyServicesEnabled is the name of the env var used for enabling inferred span tracing
 	envInferredProxyServicesEnabled = "DD_TRACE_INFERRED_PROXY_SERVICES_ENABLED"
+	// envResourceGuardEnabled is the name of the env var used to enable the collapse of unbounded values in resource names
+	envResourceGuardEnabled = "DD_TRACE_HTTP_SERVER_RESOURCE_GUARD_ENABLED"
 )
 
 // defaultQueryStringRegexp is the regexp used for query string obfuscation if [EnvQueryStringRegexp] is empty.
@@ -39,6 +41,8 @@ type config struct {
 	traceClientIP                bool
 	isStatusError                func(statusCode int) bool
 	inferredProxyServicesEnabled bool
+	resourceGuard                bool        // reports whether unbounded values are collapsed in resource names, see GuardResource.
+	ignoreRules                  ignoreRules // matches the server requests which aren't traced, see IgnoreRequest.
 }
 
 // ResetCfg sets local variable cfg back to its defaults (mainly useful for testing)
@@ -53,6 +57,8 @@ func newConfig() config {
 		traceClientIP:                internal.BoolEnv(envTraceClientIPEnabled, false),
 		isStatusError:                isServerError,
 		inferredProxyServicesEnabled: internal.BoolEnv(envInferredProxyServicesEnabled, false),
+		resourceGuard:                internal.BoolEnv(envResourceGuardEnabled, false),
+		ignoreRules:                  ignoreRulesFromEnv(),
 	}
 	v := os.Getenv(envServerErrorStatuses)
 	if fn := GetErrorCodesFromInput(v); fn != nil {
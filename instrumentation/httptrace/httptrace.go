@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,8 +20,9 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
-	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/httpsec"
+	"github.com/DataDog/dd-trace-go/v2/internal/clientip"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/normalizer"
 	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
 )
 
@@ -40,6 +42,15 @@ type inferredSpanCreatedCtxKey struct{}
 
 type FinishSpanFunc = func(status int, errorFn func(int) bool, opts ...tracer.FinishOption)
 
+const (
+	// RouteNotFound is the http.route of the requests which don't match any route of a router,
+	// used instead of their path to keep the cardinality of routes and resources low.
+	RouteNotFound = "not_found"
+	// RouteMethodNotAllowed is the http.route of the requests whose path matches a route of a
+	// router, but not their method.
+	RouteMethodNotAllowed = "method_not_allowed"
+)
+
 // StartRequestSpan starts an HTTP request span with the standard list of HTTP request span tags (http.method, http.url,
 // http.useragent). Any further span start option can be added with opts.
 func StartRequestSpan(r *http.Request, opts ...tracer.StartSpanOption) (*tracer.Span, context.Context, FinishSpanFunc) {
@@ -55,7 +66,7 @@ func StartRequestSpan(r *http.Request, opts ...tracer.StartSpanOption) (*tracer.
 
 	var ipTags map[string]string
 	if cfg.traceClientIP {
-		ipTags, _ = httpsec.ClientIPTags(r.Header, true, r.RemoteAddr)
+		ipTags = clientip.Tags(clientip.Resolve(r.Header, true, r.RemoteAddr))
 	}
 
 	var inferredProxySpan *tracer.Span
@@ -220,25 +231,78 @@ func URLFromRequest(r *http.Request, queryString bool) string {
 }
 
 // HeaderTagsFromRequest matches req headers to user-defined list of header tags
-// and creates span tags based on the header tag target and the req header value
+// and creates span tags based on the header tag target and the req header value.
+// Headers may contain '*' wildcards matching any sequence of characters, in which
+// case the tag target is used as the prefix of the tags of the matching headers.
+// If headerTags were created by instrumentation.RedactHeaderTags, the values of
+// the headers are redacted.
 func HeaderTagsFromRequest(req *http.Request, headerTags instrumentation.HeaderTags) tracer.StartSpanOption {
-	var tags []struct {
-		key string
-		val string
+	tags := headerTagsFrom(req.Header, headerTags, false)
+	return func(cfg *tracer.StartSpanConfig) {
+		if cfg.Tags == nil && len(tags) > 0 {
+			cfg.Tags = make(map[string]interface{}, len(tags))
+		}
+		for k, v := range tags {
+			cfg.Tags[k] = v
+		}
 	}
+}
+
+// HeaderTagsFromResponse matches the given response headers to user-defined list of
+// header tags and returns the span tags based on the header value, like
+// HeaderTagsFromRequest. The tags are named after the http.response.headers prefix
+// whatever their tag target, so that they don't overwrite the tags of the request
+// headers.
+func HeaderTagsFromResponse(header http.Header, headerTags instrumentation.HeaderTags) map[string]string {
+	return headerTagsFrom(header, headerTags, true)
+}
 
+// headerTagsFrom returns the tags for the headers of h matching headerTags.
+func headerTagsFrom(h http.Header, headerTags instrumentation.HeaderTags, response bool) map[string]string {
+	if headerTags == nil {
+		return nil
+	}
+	redactor, _ := headerTags.(interface {
+		Redact(header, value string) string
+	})
+	tags := make(map[string]string)
+	add := func(header, tag string, vs []string) {
+		v := strings.TrimSpace(strings.Join(vs, ","))
+		if redactor != nil {
+			v = redactor.Redact(header, v)
+		}
+		tags[tag] = v
+	}
 	headerTags.Iter(func(header, tag string) {
-		if vs, ok := req.Header[header]; ok {
-			tags = append(tags, struct {
-				key string
-				val string
-			}{tag, strings.TrimSpace(strings.Join(vs, ","))})
+		if !strings.Contains(header, "*") {
+			if vs, ok := h[header]; ok {
+				add(header, headerTagName(header, tag, false, response), vs)
+			}
+			return
+		}
+		for name, vs := range h {
+			if ok, _ := path.Match(header, name); ok {
+				add(name, headerTagName(name, tag, true, response), vs)
+			}
 		}
 	})
+	return tags
+}
 
-	return func(cfg *tracer.StartSpanConfig) {
-		for _, t := range tags {
-			cfg.Tags[t.key] = t.val
-		}
+// headerTagName returns the name of the tag of header, matched by a header tag with
+// the given tag target, or wildcard.
+func headerTagName(header, tag string, wildcard, response bool) string {
+	if !wildcard && !response {
+		return tag
+	}
+	_, defaultTag := normalizer.HeaderTag(header)
+	suffix := strings.TrimPrefix(defaultTag, ext.HTTPRequestHeaders)
+	switch {
+	case response:
+		return ext.HTTPResponseHeaders + suffix
+	case strings.HasPrefix(tag, ext.HTTPRequestHeaders+"."):
+		return ext.HTTPRequestHeaders + suffix
+	default:
+		return tag + suffix
 	}
 }
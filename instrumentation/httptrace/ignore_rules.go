@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httptrace
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// envIgnorePaths is the name of the env var holding the comma-separated paths of the
+	// server requests which aren't traced.
+	envIgnorePaths = "DD_TRACE_HTTP_SERVER_IGNORE_PATHS"
+	// envIgnoreUserAgents is the name of the env var holding the comma-separated user agents
+	// of the server requests which aren't traced.
+	envIgnoreUserAgents = "DD_TRACE_HTTP_SERVER_IGNORE_USER_AGENTS"
+)
+
+// ignoreRules holds the rules matching the server requests which aren't traced, typically
+// health checks and metrics scrapers.
+type ignoreRules struct {
+	paths      []string // exact paths
+	prefixes   []string // path prefixes, from the paths ending with "*"
+	userAgents []string // lower-cased user agent substrings
+}
+
+// newIgnoreRules returns the ignore rules built from the comma-separated paths and user
+// agents. A path ending with "*" matches any path starting with what precedes it, and a
+// user agent matches any User-Agent header containing it, regardless of the case.
+func newIgnoreRules(paths, userAgents string) ignoreRules {
+	var r ignoreRules
+	for _, p := range splitList(paths) {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			r.prefixes = append(r.prefixes, prefix)
+		} else {
+			r.paths = append(r.paths, p)
+		}
+	}
+	for _, ua := range splitList(userAgents) {
+		r.userAgents = append(r.userAgents, strings.ToLower(ua))
+	}
+	return r
+}
+
+// ignoreRulesFromEnv returns the ignore rules configured with DD_TRACE_HTTP_SERVER_IGNORE_PATHS
+// and DD_TRACE_HTTP_SERVER_IGNORE_USER_AGENTS.
+func ignoreRulesFromEnv() ignoreRules {
+	return newIgnoreRules(os.Getenv(envIgnorePaths), os.Getenv(envIgnoreUserAgents))
+}
+
+// splitList splits the comma-separated list s, ignoring the blank entries.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// empty reports whether r matches no request.
+func (r *ignoreRules) empty() bool {
+	return len(r.paths) == 0 && len(r.prefixes) == 0 && len(r.userAgents) == 0
+}
+
+// match reports whether the request r matches one of the rules.
+func (r *ignoreRules) match(req *http.Request) bool {
+	if r.empty() {
+		return false
+	}
+	if req.URL != nil {
+		path := req.URL.Path
+		for _, p := range r.paths {
+			if path == p {
+				return true
+			}
+		}
+		for _, p := range r.prefixes {
+			if strings.HasPrefix(path, p) {
+				return true
+			}
+		}
+	}
+	if len(r.userAgents) > 0 {
+		ua := strings.ToLower(req.UserAgent())
+		if ua == "" {
+			return false
+		}
+		for _, u := range r.userAgents {
+			if strings.Contains(ua, u) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IgnoreRequest reports whether the server request r should not be traced, according to the
+// ignore rules shared by the http server integrations. The rules are configured with:
+//
+//   - DD_TRACE_HTTP_SERVER_IGNORE_PATHS, the comma-separated paths of the ignored requests,
+//     e.g. "/healthz,/metrics". A path ending with "*" matches any path starting with what
+//     precedes it, e.g. "/debug/*".
+//   - DD_TRACE_HTTP_SERVER_IGNORE_USER_AGENTS, the comma-separated user agents of the ignored
+//     requests, e.g. "kube-probe,Prometheus". A user agent matches any User-Agent header
+//     containing it, regardless of the case.
+//
+// No request is ignored by default. The integrations check these rules in addition to their own
+// ignore functions, if any, and call the handler without any span for the ignored requests.
+func IgnoreRequest(r *http.Request) bool {
+	return cfg.ignoreRules.match(r)
+}
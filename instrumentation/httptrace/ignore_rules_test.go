@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httptrace
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules := newIgnoreRules(" /healthz, /debug/*,,", "kube-probe, ELB-HealthChecker")
+	for _, tc := range []struct {
+		path      string
+		userAgent string
+		ignored   bool
+	}{
+		{path: "/healthz", ignored: true},
+		{path: "/healthz/ready", ignored: false},
+		{path: "/debug/pprof/heap", ignored: true},
+		{path: "/debug/", ignored: true},
+		{path: "/debug", ignored: false},
+		{path: "/users", ignored: false},
+		{path: "/users", userAgent: "kube-probe/1.29", ignored: true},
+		{path: "/users", userAgent: "elb-healthchecker/2.0", ignored: true},
+		{path: "/users", userAgent: "Mozilla/5.0", ignored: false},
+	} {
+		r := httptest.NewRequest("GET", tc.path, nil)
+		if tc.userAgent != "" {
+			r.Header.Set("User-Agent", tc.userAgent)
+		}
+		assert.Equal(t, tc.ignored, rules.match(r), "path %q, user agent %q", tc.path, tc.userAgent)
+	}
+}
+
+func TestIgnoreRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	r.Header.Set("User-Agent", "Prometheus/2.51.0")
+
+	t.Run("default", func(t *testing.T) {
+		ResetCfg()
+		assert.False(t, IgnoreRequest(r))
+	})
+
+	t.Run("paths", func(t *testing.T) {
+		t.Setenv(envIgnorePaths, "/healthz,/metrics")
+		ResetCfg()
+		defer ResetCfg()
+		assert.True(t, IgnoreRequest(r))
+	})
+
+	t.Run("user-agents", func(t *testing.T) {
+		t.Setenv(envIgnoreUserAgents, "prometheus")
+		ResetCfg()
+		defer ResetCfg()
+		assert.True(t, IgnoreRequest(r))
+	})
+}
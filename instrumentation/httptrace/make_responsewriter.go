@@ -97,6 +97,9 @@ func wrapResponseWriter(w http.ResponseWriter) (http.ResponseWriter, *responseWr
 {{- end }}
 
 	mw := newResponseWriter(w)
+	if okFlusher {
+		hFlusher = flusher{hFlusher, mw}
+	}
 	type monitoredResponseWriter interface {
 		http.ResponseWriter
 		Status() int
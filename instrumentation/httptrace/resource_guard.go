@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httptrace
+
+import (
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+)
+
+// collapsedPlaceholder replaces the path segments of resource names holding unbounded values.
+const collapsedPlaceholder = "?"
+
+// GuardResource guards against the infinite cardinality of the resource names of http server
+// spans built from request paths, which happens when a request doesn't match any route. The
+// path segments of resource that look like unbounded values, namely numeric IDs, UUIDs and
+// long hexadecimal identifiers, are replaced with "?", e.g. "GET /users/123" becomes
+// "GET /users/?". The guard is disabled by default, as the resource may be supplied by the user,
+// and is enabled with DD_TRACE_HTTP_SERVER_RESOURCE_GUARD_ENABLED=true. The resource is returned
+// unchanged when the guard is disabled or when it holds no such segment.
+//
+// The resource may be a path or a method followed by a space and a path. BeforeHandle applies
+// it to the resources of the requests without route. The number of collapsed resource names is
+// reported as telemetry.
+func GuardResource(resource string) string {
+	if !cfg.resourceGuard {
+		return resource
+	}
+	start := strings.IndexByte(resource, '/')
+	// unbounded values hold digits in practice, skip the resources without any
+	if start < 0 || !strings.ContainsAny(resource[start:], "0123456789") {
+		return resource
+	}
+	segments := strings.Split(resource[start:], "/")
+	collapsed := false
+	for i, s := range segments {
+		if isUnboundedSegment(s) {
+			segments[i] = collapsedPlaceholder
+			collapsed = true
+		}
+	}
+	if !collapsed {
+		return resource
+	}
+	telemetry.Count(telemetry.NamespaceTracers, "http.server.resource_name.collapsed", nil).Submit(1)
+	return resource[:start] + strings.Join(segments, "/")
+}
+
+// isUnboundedSegment reports whether the path segment s looks like a value with an unbounded
+// cardinality.
+func isUnboundedSegment(s string) bool {
+	switch {
+	case s == "":
+		return false
+	case isDigits(s):
+		return true
+	case isUUID(s):
+		return true
+	case len(s) >= 16 && isHex(s):
+		// hashes and identifiers such as MongoDB ObjectIDs
+		return true
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') && !(c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// isUUID reports whether s is a UUID in its canonical textual form.
+func isUUID(s string) bool {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return false
+	}
+	return isHex(s[:8]) && isHex(s[9:13]) && isHex(s[14:18]) && isHex(s[19:23]) && isHex(s[24:])
+}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httptrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry/telemetrytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enableResourceGuard enables GuardResource for the duration of the test.
+func enableResourceGuard(t *testing.T) {
+	// the cleanups run in reverse order, so the config is reset once the environment
+	// is restored.
+	t.Cleanup(ResetCfg)
+	t.Setenv(envResourceGuardEnabled, "true")
+	ResetCfg()
+}
+
+func TestGuardResource(t *testing.T) {
+	enableResourceGuard(t)
+	for _, tc := range []struct {
+		resource string
+		expected string
+	}{
+		{resource: "", expected: ""},
+		{resource: "GET", expected: "GET"},
+		{resource: "GET /", expected: "GET /"},
+		{resource: "GET /users", expected: "GET /users"},
+		{resource: "GET /users/{id}", expected: "GET /users/{id}"},
+		{resource: "GET /api/v2/users", expected: "GET /api/v2/users"},
+		{resource: "GET /users/123", expected: "GET /users/?"},
+		{resource: "GET /users/123/orders/456/", expected: "GET /users/?/orders/?/"},
+		{resource: "/users/123", expected: "/users/?"},
+		{resource: "DELETE /sessions/3f2504e0-4f89-41d3-9a0c-0305e82c3301", expected: "DELETE /sessions/?"},
+		{resource: "GET /objects/507f1f77bcf86cd799439011", expected: "GET /objects/?"},
+		{resource: "GET /files/report-2024.pdf", expected: "GET /files/report-2024.pdf"},
+		{resource: "GET /files/abc123", expected: "GET /files/abc123"},
+	} {
+		t.Run(tc.resource, func(t *testing.T) {
+			assert.Equal(t, tc.expected, GuardResource(tc.resource))
+		})
+	}
+}
+
+func TestGuardResourceTelemetry(t *testing.T) {
+	enableResourceGuard(t)
+	telemetryClient := new(telemetrytest.RecordClient)
+	defer telemetry.MockClient(telemetryClient)()
+
+	GuardResource("GET /users/123")
+	GuardResource("GET /users/456")
+	GuardResource("GET /users")
+	assert.Equal(t, 2.0, telemetryClient.Count(telemetry.NamespaceTracers, "http.server.resource_name.collapsed", nil).Get())
+}
+
+func TestGuardResourceDisabled(t *testing.T) {
+	// the guard is disabled by default
+	ResetCfg()
+	assert.Equal(t, "GET /users/123", GuardResource("GET /users/123"))
+
+	t.Cleanup(ResetCfg)
+	t.Setenv(envResourceGuardEnabled, "false")
+	ResetCfg()
+	assert.Equal(t, "GET /users/123", GuardResource("GET /users/123"))
+}
+
+func TestBeforeHandleResourceGuard(t *testing.T) {
+	enableResourceGuard(t)
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	r := httptest.NewRequest(http.MethodGet, "/users/123", nil)
+	_, _, afterHandle, _ := BeforeHandle(&ServeConfig{Resource: "GET /users/123"}, httptest.NewRecorder(), r)
+	afterHandle()
+	// the resource of a matched route is kept as is
+	r = httptest.NewRequest(http.MethodGet, "/status/200", nil)
+	_, _, afterHandle, _ = BeforeHandle(&ServeConfig{Resource: "GET /status/200", Route: "/status/200"}, httptest.NewRecorder(), r)
+	afterHandle()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "GET /users/?", spans[0].Tag(ext.ResourceName))
+	assert.Equal(t, "GET /status/200", spans[1].Tag(ext.ResourceName))
+}
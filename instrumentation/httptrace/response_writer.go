@@ -7,13 +7,24 @@ package httptrace
 
 //go:generate sh -c "go run make_responsewriter.go | gofmt > trace_gen.go"
 
-import "net/http"
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// bufferBeforeChunking is the size of the response body buffered by the net/http server
+// before it starts sending the body in chunks, when its Content-Length isn't set.
+const bufferBeforeChunking = 2048
 
 // responseWriter is a small wrapper around an http response writer that will
 // intercept and store the status of a request.
 type responseWriter struct {
 	http.ResponseWriter
-	status int
+	status  int
+	written int64 // the number of bytes of the response body written
+	sized   bool  // whether the Content-Length of the response was set with its header
+	flushed bool  // whether the response was flushed before being complete
 }
 
 // ResetStatusCode resets the status code of the response writer.
@@ -24,7 +35,7 @@ func ResetStatusCode(w http.ResponseWriter) {
 }
 
 func newResponseWriter(w http.ResponseWriter) *responseWriter {
-	return &responseWriter{w, 0}
+	return &responseWriter{ResponseWriter: w}
 }
 
 // Status returns the status code that was monitored.
@@ -39,7 +50,9 @@ func (w *responseWriter) Write(b []byte) (int, error) {
 	if w.status == 0 {
 		w.WriteHeader(http.StatusOK)
 	}
-	return w.ResponseWriter.Write(b)
+	n, err := w.ResponseWriter.Write(b)
+	w.written += int64(n)
+	return n, err
 }
 
 // WriteHeader sends an HTTP response header with status code.
@@ -48,6 +61,7 @@ func (w *responseWriter) WriteHeader(status int) {
 	if w.status != 0 {
 		return
 	}
+	w.sized = w.Header().Get("Content-Length") != ""
 	w.ResponseWriter.WriteHeader(status)
 	w.status = status
 }
@@ -56,3 +70,35 @@ func (w *responseWriter) WriteHeader(status int) {
 func (w *responseWriter) Unwrap() http.ResponseWriter {
 	return w.ResponseWriter
 }
+
+// streamed reports whether the response was streamed to the client rather than
+// sent at once, because it was flushed, or because its size wasn't known when its
+// header was written and its body was too large to be buffered, in which case the
+// net/http server sends it in chunks.
+func (w *responseWriter) streamed() bool {
+	return w.flushed || (!w.sized && w.written > bufferBeforeChunking)
+}
+
+// flusher wraps the http.Flusher of a responseWriter to record flushes.
+type flusher struct {
+	http.Flusher
+	rw *responseWriter
+}
+
+// Flush sends any buffered data to the client.
+func (f flusher) Flush() {
+	f.rw.flushed = true
+	f.Flusher.Flush()
+}
+
+// countingBody wraps the body of a request to count the bytes read from it.
+type countingBody struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	return n, err
+}
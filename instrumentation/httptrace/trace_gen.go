@@ -28,6 +28,9 @@ func wrapResponseWriter(w http.ResponseWriter) (http.ResponseWriter, *responseWr
 	hHijacker, okHijacker := w.(http.Hijacker)
 
 	mw := newResponseWriter(w)
+	if okFlusher {
+		hFlusher = flusher{hFlusher, mw}
+	}
 	type monitoredResponseWriter interface {
 		http.ResponseWriter
 		Status() int
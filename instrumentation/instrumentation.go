@@ -7,6 +7,7 @@ package instrumentation
 
 import (
 	"context"
+	"fmt"
 	"math"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -25,7 +26,9 @@ type OperationContext map[string]string
 
 // Load attempts to load the requested package instrumentation. It panics if the package has not been registered.
 func Load(pkg Package) *Instrumentation {
+	packagesMu.RLock()
 	info, ok := packages[pkg]
+	packagesMu.RUnlock()
 	if !ok {
 		panic("instrumentation package: " + pkg + " was not found. If this is an external package, you must " +
 			"call instrumentation.Register first")
@@ -34,11 +37,33 @@ func Load(pkg Package) *Instrumentation {
 	telemetry.LoadIntegration(string(pkg))
 	tracer.MarkIntegrationImported(info.TracedPackage)
 
-	return &Instrumentation{
+	instr := &Instrumentation{
 		pkg:    pkg,
 		logger: newLogger(pkg),
 		info:   info,
 	}
+	if !instr.Enabled() {
+		instr.logger.Info("Integration %s disabled by DD_TRACE_%s_ENABLED", pkg, info.EnvVarPrefix)
+		internal.SetIntegrationEnabled(string(pkg), false)
+	}
+	return instr
+}
+
+// Register registers the instrumentation of an external package, such as an in-house framework instrumented with
+// custom orchestrion aspects, so that it can be loaded with Load like the integrations of dd-trace-go: it is reported
+// in telemetry and its spans can be disabled with DD_TRACE_<EnvVarPrefix>_ENABLED=false. As external packages have no
+// naming schema, their service name is DD_SERVICE and their operation names are chosen by the instrumentation.
+// Register is meant to be called from an init function. It returns an error if pkg is already registered.
+func Register(pkg Package, info PackageInfo) error {
+	packagesMu.Lock()
+	defer packagesMu.Unlock()
+	if _, ok := packages[pkg]; ok {
+		return fmt.Errorf("instrumentation package: %s is already registered", pkg)
+	}
+	info.external = true
+	info.naming = nil
+	packages[pkg] = info
+	return nil
 }
 
 // ReloadConfig reloads config read from environment variables. This is useful for tests.
@@ -89,6 +114,16 @@ func (i *Instrumentation) OperationName(component Component, opCtx OperationCont
 	}
 }
 
+// Enabled reports whether the instrumentation is enabled. It can be disabled at
+// runtime by setting DD_TRACE_<INTEGRATION>_ENABLED to false, in which case the
+// spans started by the integration are not created.
+func (i *Instrumentation) Enabled() bool {
+	if i.info.EnvVarPrefix == "" {
+		return true
+	}
+	return internal.BoolEnv("DD_TRACE_"+i.info.EnvVarPrefix+"_ENABLED", true)
+}
+
 func (i *Instrumentation) Logger() Logger {
 	return i.logger
 }
@@ -161,6 +196,23 @@ func NewHeaderTags(headers []string) HeaderTags {
 	return internal.NewLockMap(headerTagsMap)
 }
 
+// RedactHeaderTags returns header tags setting the values of the headers of
+// headerTags as returned by redact, for example to only keep part of the value
+// of sensitive headers. The header passed to redact is in canonical format.
+func RedactHeaderTags(headerTags HeaderTags, redact func(header, value string) string) HeaderTags {
+	return redactedHeaderTags{headerTags, redact}
+}
+
+type redactedHeaderTags struct {
+	HeaderTags
+	redact func(header, value string) string
+}
+
+// Redact returns the value to set as a tag for the given header.
+func (h redactedHeaderTags) Redact(header, value string) string {
+	return h.redact(header, value)
+}
+
 func (i *Instrumentation) HTTPHeadersAsTags() HeaderTags {
 	return globalconfig.HeaderTagMap()
 }
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	const pkg Package = "example.com/rpc"
+	t.Cleanup(func() {
+		packagesMu.Lock()
+		delete(packages, pkg)
+		packagesMu.Unlock()
+	})
+
+	assert.Panics(t, func() { Load(pkg) })
+
+	err := Register(pkg, PackageInfo{TracedPackage: "example.com/rpc", EnvVarPrefix: "EXAMPLE_RPC"})
+	require.NoError(t, err)
+	assert.Error(t, Register(pkg, PackageInfo{TracedPackage: "example.com/rpc"}))
+	assert.Error(t, Register(PackageNetHTTP, PackageInfo{TracedPackage: "net/http"}))
+	assert.True(t, GetPackages()[pkg].external)
+
+	instr := Load(pkg)
+	assert.True(t, instr.Enabled())
+	assert.Empty(t, instr.OperationName(ComponentClient, nil))
+
+	t.Setenv("DD_TRACE_EXAMPLE_RPC_ENABLED", "false")
+	assert.False(t, instr.Enabled())
+}
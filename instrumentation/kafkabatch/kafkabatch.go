@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package kafkabatch builds the spans of the processing of batches of Kafka messages. It is
+// shared by the Kafka integrations, which add their own tags to the spans.
+package kafkabatch
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+const (
+	// MaxLinks is the maximum number of messages linked from the span of a batch.
+	MaxLinks = 128
+
+	// TagLag holds the time elapsed since the timestamp of the oldest message of a batch,
+	// in milliseconds.
+	TagLag = "kafka.batch.lag_ms"
+)
+
+// Message describes a message of a batch.
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	// Time is the timestamp of the message, zero if unknown.
+	Time time.Time
+	// Carrier reads the trace context propagated in the headers of the message.
+	Carrier tracer.TextMapReader
+}
+
+// SpanOptions returns the options of the span of a batch of n messages, where msg describes
+// the i-th message. Instead of continuing the trace of one of the messages, the span links to
+// the trace of each message, up to MaxLinks of them. It is tagged with the size of the batch,
+// with its topic if all its messages have the same and, if the messages carry their timestamp,
+// with the lag of its oldest message.
+func SpanOptions(n int, msg func(i int) Message) []tracer.StartSpanOption {
+	var (
+		topic       string
+		singleTopic = n > 0
+		links       []tracer.SpanLink
+		oldest      time.Time
+	)
+	for i := 0; i < n; i++ {
+		m := msg(i)
+		if i == 0 {
+			topic = m.Topic
+		} else if m.Topic != topic {
+			singleTopic = false
+		}
+		if !m.Time.IsZero() && (oldest.IsZero() || m.Time.Before(oldest)) {
+			oldest = m.Time
+		}
+		if len(links) == MaxLinks || m.Carrier == nil {
+			continue
+		}
+		spanctx, err := tracer.Extract(m.Carrier)
+		if err != nil {
+			continue
+		}
+		links = append(links, spanLink(spanctx, m))
+	}
+	resource := "Consume Batch"
+	opts := []tracer.StartSpanOption{tracer.Tag(ext.MessagingBatchMessageCount, n)}
+	if singleTopic {
+		resource += " Topic " + topic
+		opts = append(opts, tracer.Tag(ext.MessagingDestinationName, topic))
+	}
+	opts = append(opts, tracer.ResourceName(resource))
+	if len(links) > 0 {
+		opts = append(opts, tracer.WithSpanLinks(links))
+	}
+	if !oldest.IsZero() {
+		opts = append(opts, tracer.Tag(TagLag, time.Since(oldest).Milliseconds()))
+	}
+	return opts
+}
+
+// spanLink returns the link to the span of the message m, whose context is spanctx.
+func spanLink(spanctx *tracer.SpanContext, m Message) tracer.SpanLink {
+	link := tracer.SpanLink{
+		TraceID:     spanctx.TraceIDLower(),
+		TraceIDHigh: spanctx.TraceIDUpper(),
+		SpanID:      spanctx.SpanID(),
+		Attributes: map[string]string{
+			ext.MessagingKafkaPartition: strconv.Itoa(m.Partition),
+			"offset":                    strconv.FormatInt(m.Offset, 10),
+		},
+	}
+	if p, ok := spanctx.SamplingPriority(); ok && p > 0 {
+		link.Flags = 1
+	}
+	return link
+}
@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kafkabatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanOptions(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	parent := tracer.StartSpan("produce")
+	carrier := tracer.TextMapCarrier{}
+	require.NoError(t, tracer.Inject(parent.Context(), carrier))
+	parent.Finish()
+
+	msgs := make([]Message, MaxLinks+2)
+	for i := range msgs {
+		msgs[i] = Message{Topic: "topic", Partition: 1, Offset: int64(i), Carrier: carrier}
+	}
+	msgs[0].Time = time.Now().Add(-time.Minute)
+	// messages without trace context are counted but not linked
+	msgs[1].Carrier = nil
+	tracer.StartSpan("batch", SpanOptions(len(msgs), func(i int) Message { return msgs[i] })...).Finish()
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 2)
+	s := spans[1]
+	assert.Equal(t, "Consume Batch Topic topic", s.Tag(ext.ResourceName))
+	assert.Equal(t, "topic", s.Tag(ext.MessagingDestinationName))
+	assert.Equal(t, float64(len(msgs)), s.Tag(ext.MessagingBatchMessageCount))
+	assert.GreaterOrEqual(t, s.Tag(TagLag), float64(60000))
+	links := s.Links()
+	require.Len(t, links, MaxLinks)
+	assert.Equal(t, parent.Context().SpanID(), links[0].SpanID)
+	assert.Equal(t, map[string]string{ext.MessagingKafkaPartition: "1", "offset": "0"}, links[0].Attributes)
+	assert.Equal(t, "2", links[1].Attributes["offset"])
+
+	t.Run("topics", func(t *testing.T) {
+		msgs := []Message{{Topic: "a"}, {Topic: "b"}}
+		tracer.StartSpan("batch", SpanOptions(len(msgs), func(i int) Message { return msgs[i] })...).Finish()
+		s := mt.FinishedSpans()[2]
+		assert.Equal(t, "Consume Batch", s.Tag(ext.ResourceName))
+		assert.Nil(t, s.Tag(ext.MessagingDestinationName))
+		assert.Nil(t, s.Tag(TagLag))
+		assert.Empty(t, s.Links())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		tracer.StartSpan("batch", SpanOptions(0, nil)...).Finish()
+		s := mt.FinishedSpans()[3]
+		assert.Equal(t, "Consume Batch", s.Tag(ext.ResourceName))
+		assert.Equal(t, float64(0), s.Tag(ext.MessagingBatchMessageCount))
+	})
+}
@@ -8,18 +8,25 @@ package instrumentation
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 )
 
 type Package string
 
+// String returns the name of the package, as found in the component tag of its spans.
+func (p Package) String() string {
+	return string(p)
+}
+
 const (
 	Package99DesignsGQLGen      Package = "99designs/gqlgen"
 	PackageAWSSDKGo             Package = "aws/aws-sdk-go"
 	PackageAWSSDKGoV2           Package = "aws/aws-sdk-go-v2"
 	PackageBradfitzGoMemcache   Package = "bradfitz/gomemcache"
 	PackageGCPPubsub            Package = "cloud.google.com/go/pubsub.v1"
+	PackageGCPSpanner           Package = "cloud.google.com/go/spanner"
 	PackageConfluentKafkaGo     Package = "confluentinc/confluent-kafka-go/kafka"
 	PackageConfluentKafkaGoV2   Package = "confluentinc/confluent-kafka-go/kafka.v2"
 	PackageDatabaseSQL          Package = "database/sql"
@@ -75,6 +82,26 @@ const (
 	PackageEnvoyProxyGoControlPlane Package = "envoyproxy/go-control-plane"
 	PackageOS                       Package = "os"
 	PackageRedisRueidis             Package = "redis/rueidis"
+	PackageNatsIoNatsGo             Package = "nats-io/nats.go"
+	PackageTemporalSDK              Package = "go.temporal.io/sdk"
+	PackageConnectRPC               Package = "connectrpc.com/connect"
+	PackageRiverqueueRiver          Package = "riverqueue/river"
+	PackageHibikenAsynq             Package = "hibiken/asynq"
+	PackageRobfigCronV3             Package = "robfig/cron.v3"
+	PackageNetSMTP                  Package = "net/smtp"
+	PackageClickHouseGoV2           Package = "ClickHouse/clickhouse-go.v2"
+	PackageTwmbFranzGo              Package = "twmb/franz-go"
+	PackageEntgoEnt                 Package = "entgo.io/ent"
+	PackageOpenSearchGoV4           Package = "opensearch-project/opensearch-go.v4"
+	PackageDgraphIoRistretto        Package = "dgraph-io/ristretto"
+	PackageAllegroBigcacheV3        Package = "allegro/bigcache.v3"
+	PackageGolangGroupcache         Package = "golang/groupcache"
+	PackageOSExec                   Package = "os/exec"
+	PackageNet                      Package = "net"
+	PackageSigsK8sControllerRuntime Package = "sigs.k8s.io/controller-runtime"
+	PackageCrypto                   Package = "crypto"
+	PackageUberGoZap                Package = "uber-go/zap"
+	PackageRsZerolog                Package = "rs/zerolog"
 )
 
 // These packages have been removed in v2, but they are kept here for the transitional version.
@@ -114,6 +141,9 @@ type PackageInfo struct {
 	naming map[Component]componentNames
 }
 
+// packagesMu guards packages, to which external packages are added by Register.
+var packagesMu sync.RWMutex
+
 var packages = map[Package]PackageInfo{
 	Package99DesignsGQLGen: {
 		TracedPackage: "github.com/99designs/gqlgen",
@@ -178,6 +208,16 @@ var packages = map[Package]PackageInfo{
 			},
 		},
 	},
+	PackageGCPSpanner: {
+		TracedPackage: "cloud.google.com/go/spanner",
+		EnvVarPrefix:  "GCP_SPANNER",
+		naming: map[Component]componentNames{
+			ComponentDefault: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("spanner"),
+			},
+		},
+	},
 	PackageGCPPubsub: {
 		TracedPackage: "cloud.google.com/go/pubsub",
 		EnvVarPrefix:  "GCP_PUBSUB",
@@ -780,6 +820,14 @@ var packages = map[Package]PackageInfo{
 		TracedPackage: "log/slog",
 		IsStdLib:      true,
 	},
+	PackageUberGoZap: {
+		TracedPackage: "go.uber.org/zap",
+		EnvVarPrefix:  "ZAP",
+	},
+	PackageRsZerolog: {
+		TracedPackage: "github.com/rs/zerolog",
+		EnvVarPrefix:  "ZEROLOG",
+	},
 	PackageValkeyIoValkeyGo: {
 		TracedPackage: "github.com/valkey-io/valkey-go",
 		EnvVarPrefix:  "VALKEY",
@@ -796,6 +844,212 @@ var packages = map[Package]PackageInfo{
 	PackageOS: {
 		TracedPackage: "os",
 	},
+	PackageNatsIoNatsGo: {
+		TracedPackage: "github.com/nats-io/nats.go",
+		EnvVarPrefix:  "NATS",
+		naming: map[Component]componentNames{
+			ComponentConsumer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("nats"),
+				buildOpNameV0:      staticName("nats.consume"),
+				buildOpNameV1:      staticName("nats.process"),
+			},
+			ComponentProducer: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("nats"),
+				buildOpNameV0:      staticName("nats.publish"),
+				buildOpNameV1:      staticName("nats.send"),
+			},
+		},
+	},
+	PackageConnectRPC: {
+		TracedPackage: "connectrpc.com/connect",
+		EnvVarPrefix:  "CONNECT",
+		// Connect RPCs share the naming of gRPC calls, since Connect servers and clients
+		// usually serve and call gRPC services as well.
+		naming: map[Component]componentNames{
+			ComponentServer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("grpc.server"),
+				buildOpNameV0:      staticName("grpc.server"),
+				buildOpNameV1:      staticName("grpc.server.request"),
+			},
+			ComponentClient: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("grpc.client"),
+				buildOpNameV0:      staticName("grpc.client"),
+				buildOpNameV1:      staticName("grpc.client.request"),
+			},
+		},
+	},
+	PackageRiverqueueRiver: {
+		TracedPackage: "github.com/riverqueue/river",
+		EnvVarPrefix:  "RIVER",
+		naming: map[Component]componentNames{
+			ComponentConsumer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("river"),
+				buildOpNameV0:      staticName("river.work"),
+				buildOpNameV1:      staticName("river.process"),
+			},
+			ComponentProducer: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("river"),
+				buildOpNameV0:      staticName("river.insert"),
+				buildOpNameV1:      staticName("river.send"),
+			},
+		},
+	},
+	PackageHibikenAsynq: {
+		TracedPackage: "github.com/hibiken/asynq",
+		EnvVarPrefix:  "ASYNQ",
+		naming: map[Component]componentNames{
+			ComponentConsumer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("asynq"),
+				buildOpNameV0:      staticName("asynq.process"),
+				buildOpNameV1:      staticName("asynq.process"),
+			},
+			ComponentProducer: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("asynq"),
+				buildOpNameV0:      staticName("asynq.enqueue"),
+				buildOpNameV1:      staticName("asynq.send"),
+			},
+		},
+	},
+	PackageRobfigCronV3: {
+		TracedPackage: "github.com/robfig/cron/v3",
+		EnvVarPrefix:  "CRON",
+		naming: map[Component]componentNames{
+			ComponentDefault: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("cron"),
+				buildOpNameV0:      staticName("cron.run"),
+				buildOpNameV1:      staticName("cron.run"),
+			},
+		},
+	},
+	PackageNetSMTP: {
+		TracedPackage: "net/smtp",
+		IsStdLib:      true,
+		EnvVarPrefix:  "SMTP",
+		naming: map[Component]componentNames{
+			ComponentClient: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("smtp"),
+				buildOpNameV0:      staticName("smtp.send"),
+				buildOpNameV1:      staticName("smtp.send"),
+			},
+		},
+	},
+	PackageClickHouseGoV2: {
+		TracedPackage: "github.com/ClickHouse/clickhouse-go/v2",
+		EnvVarPrefix:  "CLICKHOUSE",
+		naming: map[Component]componentNames{
+			ComponentDefault: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("clickhouse"),
+			},
+		},
+	},
+	PackageTwmbFranzGo: {
+		TracedPackage: "github.com/twmb/franz-go",
+		EnvVarPrefix:  "FRANZ_GO",
+		naming: map[Component]componentNames{
+			ComponentConsumer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("kafka"),
+				buildOpNameV0:      staticName("kafka.consume"),
+				buildOpNameV1:      staticName("kafka.process"),
+			},
+			ComponentProducer: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("kafka"),
+				buildOpNameV0:      staticName("kafka.produce"),
+				buildOpNameV1:      staticName("kafka.send"),
+			},
+		},
+	},
+	PackageEntgoEnt: {
+		TracedPackage: "entgo.io/ent",
+		EnvVarPrefix:  "ENT",
+		naming: map[Component]componentNames{
+			ComponentDefault: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("ent.db"),
+			},
+		},
+	},
+	PackageOpenSearchGoV4: {
+		TracedPackage: "github.com/opensearch-project/opensearch-go/v4",
+		EnvVarPrefix:  "OPENSEARCH",
+		naming: map[Component]componentNames{
+			ComponentDefault: {
+				useDDServiceV0:     false,
+				buildServiceNameV0: staticName("opensearch.client"),
+				buildOpNameV0:      staticName("opensearch.query"),
+				buildOpNameV1:      staticName("opensearch.query"),
+			},
+		},
+	},
+	PackageDgraphIoRistretto: {
+		TracedPackage: "github.com/dgraph-io/ristretto",
+		EnvVarPrefix:  "RISTRETTO",
+	},
+	PackageAllegroBigcacheV3: {
+		TracedPackage: "github.com/allegro/bigcache/v3",
+		EnvVarPrefix:  "BIGCACHE",
+	},
+	PackageGolangGroupcache: {
+		TracedPackage: "github.com/golang/groupcache",
+		EnvVarPrefix:  "GROUPCACHE",
+	},
+	PackageOSExec: {
+		TracedPackage: "os/exec",
+		IsStdLib:      true,
+		EnvVarPrefix:  "EXEC",
+		naming: map[Component]componentNames{
+			ComponentDefault: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName(""),
+				buildOpNameV0:      staticName("command_execution"),
+				buildOpNameV1:      staticName("command_execution"),
+			},
+		},
+	},
+	PackageNet: {
+		TracedPackage: "net",
+		IsStdLib:      true,
+		EnvVarPrefix:  "NET",
+	},
+	PackageCrypto: {
+		TracedPackage: "crypto",
+		IsStdLib:      true,
+		EnvVarPrefix:  "CRYPTO",
+	},
+	PackageSigsK8sControllerRuntime: {
+		TracedPackage: "sigs.k8s.io/controller-runtime",
+		EnvVarPrefix:  "CONTROLLER_RUNTIME",
+		naming: map[Component]componentNames{
+			ComponentDefault: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("controller-runtime"),
+				buildOpNameV0:      staticName("controller_runtime.reconcile"),
+				buildOpNameV1:      staticName("controller_runtime.reconcile"),
+			},
+		},
+	},
+	PackageTemporalSDK: {
+		TracedPackage: "go.temporal.io/sdk",
+		EnvVarPrefix:  "TEMPORAL",
+		naming: map[Component]componentNames{
+			ComponentDefault: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("temporal"),
+			},
+		},
+	},
 	PackageEmickleiGoRestful: {
 		TracedPackage: "github.com/emicklei/go-restful",
 		EnvVarPrefix:  "RESTFUL",
@@ -895,6 +1149,8 @@ func isAWSMessagingSendOp(awsService, awsOperation string) bool {
 
 // GetPackages returns a map of Package to the corresponding instrumented module.
 func GetPackages() map[Package]PackageInfo {
+	packagesMu.RLock()
+	defer packagesMu.RUnlock()
 	cp := make(map[Package]PackageInfo)
 	for pkg, info := range packages {
 		cp[pkg] = info
@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package telemetry lets the libraries and frameworks built on top of dd-trace-go, such as internal platform
+// libraries, report their own adoption and usage metrics and configuration through the instrumentation telemetry
+// of the tracer. Nothing is reported when the instrumentation telemetry is disabled with
+// DD_INSTRUMENTATION_TELEMETRY_ENABLED=false.
+//
+// The metrics and configuration of a product are reported in the general namespace of the instrumentation
+// telemetry, their names being prefixed with the name of the product:
+//
+//	var product = telemetry.MustNewProduct("acme_rpc")
+//
+//	func NewServer(cfg Config) *Server {
+//		product.RegisterConfig("max_conns", cfg.MaxConns)       // reported as acme_rpc.max_conns
+//		product.Count("servers", "transport:http2").Submit(1) // reported as acme_rpc.servers
+//		...
+//	}
+//
+// The backend only keeps the metrics it knows, so the metrics of a product must be declared in the known metrics
+// of the backend, like the ones of dd-trace-go.
+package telemetry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	internaltelemetry "github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+)
+
+// productNameRegexp matches the valid product names.
+var productNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Metric is a metric of a product, whose values are aggregated and reported periodically.
+type Metric interface {
+	// Submit submits a value to the metric.
+	Submit(value float64)
+}
+
+// Product is a library or framework reporting its metrics and configuration through the instrumentation
+// telemetry.
+type Product struct {
+	name string
+}
+
+// NewProduct returns the Product of the given name. The name must start with a lower-case letter and only contain
+// lower-case letters, digits and underscores. It must not be the name of a product of dd-trace-go.
+func NewProduct(name string) (*Product, error) {
+	if !productNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("telemetry: invalid product name %q: it must match %s", name, productNameRegexp)
+	}
+	switch internaltelemetry.Namespace(name) {
+	case internaltelemetry.NamespaceGeneral, internaltelemetry.NamespaceTracers, internaltelemetry.NamespaceProfilers,
+		internaltelemetry.NamespaceAppSec, internaltelemetry.NamespaceIAST, internaltelemetry.NamespaceCIVisibility,
+		internaltelemetry.NamespaceMLOps, internaltelemetry.NamespaceRUM:
+		return nil, fmt.Errorf("telemetry: product name %q is reserved", name)
+	}
+	return &Product{name: name}, nil
+}
+
+// MustNewProduct is like NewProduct but panics if the name is invalid. It simplifies the initialization of global
+// variables.
+func MustNewProduct(name string) *Product {
+	p, err := NewProduct(name)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Name returns the name of the product.
+func (p *Product) Name() string {
+	return p.name
+}
+
+// Count returns the count metric of the given name and tags, in the "key:value" format. The values submitted
+// during a telemetry interval are summed.
+func (p *Product) Count(name string, tags ...string) Metric {
+	return internaltelemetry.Count(internaltelemetry.NamespaceGeneral, p.metricName(name), sanitizeTags(tags))
+}
+
+// Gauge returns the gauge metric of the given name and tags, in the "key:value" format. The last value submitted
+// during a telemetry interval is reported.
+func (p *Product) Gauge(name string, tags ...string) Metric {
+	return internaltelemetry.Gauge(internaltelemetry.NamespaceGeneral, p.metricName(name), sanitizeTags(tags))
+}
+
+// Distribution returns the distribution metric of the given name and tags, in the "key:value" format. All the
+// values submitted during a telemetry interval are reported.
+func (p *Product) Distribution(name string, tags ...string) Metric {
+	return internaltelemetry.Distribution(internaltelemetry.NamespaceGeneral, p.metricName(name), sanitizeTags(tags))
+}
+
+// RegisterConfig reports the value of a configuration key of the product, set by code. value must be JSON
+// serializable.
+func (p *Product) RegisterConfig(key string, value any) {
+	internaltelemetry.RegisterAppConfig(p.metricName(key), value, internaltelemetry.OriginCode)
+}
+
+func (p *Product) metricName(name string) string {
+	return p.name + "." + name
+}
+
+// sanitizeTags drops the empty tags and replaces the commas, which aren't allowed in tags.
+func sanitizeTags(tags []string) []string {
+	sanitized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			sanitized = append(sanitized, strings.ReplaceAll(tag, ",", "_"))
+		}
+	}
+	return sanitized
+}
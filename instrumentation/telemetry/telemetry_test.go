@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package telemetry
+
+import (
+	"testing"
+
+	internaltelemetry "github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry/telemetrytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProduct(t *testing.T) {
+	for _, name := range []string{"acme_rpc", "platform2"} {
+		p, err := NewProduct(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, p.Name())
+	}
+	for _, name := range []string{"", "Acme", "acme.rpc", "2fa", "acme rpc", "tracers", "appsec"} {
+		_, err := NewProduct(name)
+		assert.Error(t, err, name)
+	}
+	assert.Panics(t, func() { MustNewProduct("ACME") })
+}
+
+func TestProduct(t *testing.T) {
+	client := new(telemetrytest.RecordClient)
+	defer internaltelemetry.MockClient(client)()
+
+	p := MustNewProduct("acme_rpc")
+	p.Count("servers", "transport:http2", "", "ids:1,2").Submit(1)
+	p.Count("servers", "transport:http2", "", "ids:1,2").Submit(2)
+	p.Gauge("pool_size").Submit(10)
+	p.Distribution("latency", "route:/users").Submit(0.5)
+	p.RegisterConfig("max_conns", 100)
+
+	assert.Equal(t, 3.0, client.Metrics[telemetrytest.MetricKey{
+		Namespace: internaltelemetry.NamespaceGeneral, Name: "acme_rpc.servers", Tags: "ids:1_2,transport:http2", Kind: "count",
+	}].Get())
+	assert.Equal(t, 10.0, client.Metrics[telemetrytest.MetricKey{
+		Namespace: internaltelemetry.NamespaceGeneral, Name: "acme_rpc.pool_size", Kind: "gauge",
+	}].Get())
+	assert.Contains(t, client.Metrics, telemetrytest.MetricKey{
+		Namespace: internaltelemetry.NamespaceGeneral, Name: "acme_rpc.latency", Tags: "route:/users", Kind: "distribution",
+	})
+	assert.Contains(t, client.Configuration, internaltelemetry.Configuration{
+		Name: "acme_rpc.max_conns", Value: 100, Origin: internaltelemetry.OriginCode,
+	})
+}
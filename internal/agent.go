@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 )
@@ -46,6 +47,8 @@ func AgentURLFromEnv() *url.URL {
 	}
 
 	host, providedHost := os.LookupEnv("DD_AGENT_HOST")
+	// IPv6 addresses may be set with their brackets, which are added by net.JoinHostPort.
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
 	port, providedPort := os.LookupEnv("DD_TRACE_AGENT_PORT")
 	if host == "" {
 		// We treat set but empty the same as unset
@@ -73,3 +76,36 @@ func AgentURLFromEnv() *url.URL {
 	}
 	return httpURL
 }
+
+// AgentURLsFromEnv returns the URLs of the trace agents set with the
+// comma-separated DD_TRACE_AGENT_URLS environment variable, in order of
+// preference, or nil if it is unset. Each URL must use the http, https or unix
+// scheme, uds being accepted as an alias of unix; the invalid ones are skipped.
+func AgentURLsFromEnv() []*url.URL {
+	env := os.Getenv("DD_TRACE_AGENT_URLS")
+	if env == "" {
+		return nil
+	}
+	var urls []*url.URL
+	for _, s := range strings.Split(env, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			log.Warn("Failed to parse Agent URL %q in DD_TRACE_AGENT_URLS: %v", s, err)
+			continue
+		}
+		switch u.Scheme {
+		case "uds":
+			u.Scheme = "unix"
+			fallthrough
+		case "unix", "http", "https":
+			urls = append(urls, u)
+		default:
+			log.Warn("Unsupported protocol %q in Agent URL %q. Must be one of: http, https, unix.", u.Scheme, s)
+		}
+	}
+	return urls
+}
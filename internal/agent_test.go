@@ -92,3 +92,22 @@ func TestAgentURLPriorityOrder(t *testing.T) {
 		assert.Equal(t, "localhost:8126", url.Host)
 	})
 }
+
+func TestAgentURLFromEnvIPv6(t *testing.T) {
+	for _, host := range []string{"::1", "[::1]"} {
+		t.Setenv("DD_AGENT_HOST", host)
+		assert.Equal(t, "http://[::1]:8126", AgentURLFromEnv().String())
+	}
+}
+
+func TestAgentURLsFromEnv(t *testing.T) {
+	t.Setenv("DD_TRACE_AGENT_URLS", "")
+	assert.Nil(t, AgentURLsFromEnv())
+
+	t.Setenv("DD_TRACE_AGENT_URLS", "uds:///var/run/datadog/apm.socket, http://agent-a:8126,,bad://agent-b:8126,http://[fd00::1]:8126")
+	var urls []string
+	for _, u := range AgentURLsFromEnv() {
+		urls = append(urls, u.String())
+	}
+	assert.Equal(t, []string{"unix:///var/run/datadog/apm.socket", "http://agent-a:8126", "http://[fd00::1]:8126"}, urls)
+}
@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package apikey resolves the API key sent to the Datadog intake by the
+// agentless features with a function supplied by the user, to fetch it from a
+// secret manager and follow its rotations.
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Header is the HTTP header holding the API key.
+const Header = "DD-API-KEY"
+
+var errEmptyKey = errors.New("resolved an empty API key")
+
+// Resolver resolves the API key with a user-supplied function, caching it until
+// it is rejected by the intake.
+//
+// Resolver is safe for concurrent use.
+type Resolver struct {
+	fn func(context.Context) (string, error)
+
+	mu  sync.Mutex
+	key string
+}
+
+// NewResolver returns a resolver calling fn to resolve the API key the first
+// time it is needed, and again after the key is rejected. fn is not called
+// concurrently, and must return before ctx is done.
+func NewResolver(fn func(context.Context) (string, error)) *Resolver {
+	return &Resolver{fn: fn}
+}
+
+// Key returns the API key, resolving it if it isn't cached.
+func (r *Resolver) Key(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.key != "" {
+		return r.key, nil
+	}
+	key, err := r.fn(ctx)
+	if err != nil {
+		return "", fmt.Errorf("resolving API key: %w", err)
+	}
+	if key == "" {
+		return "", errEmptyKey
+	}
+	r.key = key
+	return key, nil
+}
+
+// Invalidate forgets key, so that the next call to Key resolves it again. It
+// does nothing if key was already replaced, so that the requests rejected
+// concurrently resolve the key only once.
+func (r *Resolver) Invalidate(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.key == key {
+		r.key = ""
+	}
+}
+
+// Do sends req with client, with the API key set in its Header. When the
+// intake rejects the key with 403 Forbidden, the key is invalidated and, if it
+// resolves to a new key and the body of req can be read again with
+// req.GetBody, req is sent once more with the new key.
+//
+// Like http.Client.Do, Do closes the body of req, even on errors.
+func (r *Resolver) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	key, err := r.Key(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	req.Header.Set(Header, key)
+	resp, err := client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		return resp, err
+	}
+	r.Invalidate(key)
+	if req.GetBody == nil {
+		return resp, nil
+	}
+	newKey, err := r.Key(req.Context())
+	if err != nil || newKey == key {
+		return resp, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	retry.Header.Set(Header, newKey)
+	return client.Do(retry)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx holding r.
+func NewContext(ctx context.Context, r *Resolver) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the resolver held by ctx, if any.
+func FromContext(ctx context.Context) (*Resolver, bool) {
+	r, ok := ctx.Value(contextKey{}).(*Resolver)
+	return r, ok
+}
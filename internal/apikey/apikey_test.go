@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rotatingKeys returns a function resolving keys to the given values in turn,
+// and the number of times it was called.
+func rotatingKeys(keys ...string) (func(context.Context) (string, error), *int) {
+	var calls int
+	return func(context.Context) (string, error) {
+		key := keys[min(calls, len(keys)-1)]
+		calls++
+		return key, nil
+	}, &calls
+}
+
+func TestResolverKey(t *testing.T) {
+	fn, calls := rotatingKeys("key1", "key2")
+	r := NewResolver(fn)
+	for i := 0; i < 3; i++ {
+		key, err := r.Key(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "key1", key)
+	}
+	assert.Equal(t, 1, *calls)
+
+	r.Invalidate("stale")
+	key, _ := r.Key(context.Background())
+	assert.Equal(t, "key1", key)
+
+	r.Invalidate("key1")
+	key, _ = r.Key(context.Background())
+	assert.Equal(t, "key2", key)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestResolverKeyError(t *testing.T) {
+	errVault := errors.New("vault is sealed")
+	r := NewResolver(func(context.Context) (string, error) { return "", errVault })
+	_, err := r.Key(context.Background())
+	assert.ErrorIs(t, err, errVault)
+
+	r = NewResolver(func(context.Context) (string, error) { return "", nil })
+	_, err = r.Key(context.Background())
+	assert.Equal(t, errEmptyKey, err)
+}
+
+func TestResolverDo(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+		valid  = "key2"
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, r.Header.Get(Header)+":"+string(body))
+		mu.Unlock()
+		if r.Header.Get(Header) != valid {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("rotated", func(t *testing.T) {
+		bodies = nil
+		fn, _ := rotatingKeys("key1", "key2")
+		r := NewResolver(fn)
+		req, err := http.NewRequest("POST", srv.URL, bytes.NewBufferString("payload"))
+		require.NoError(t, err)
+		resp, err := r.Do(srv.Client(), req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"key1:payload", "key2:payload"}, bodies)
+	})
+
+	t.Run("not-rotated", func(t *testing.T) {
+		bodies = nil
+		fn, calls := rotatingKeys("key1")
+		r := NewResolver(fn)
+		req, err := http.NewRequest("POST", srv.URL, bytes.NewBufferString("payload"))
+		require.NoError(t, err)
+		resp, err := r.Do(srv.Client(), req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, []string{"key1:payload"}, bodies)
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("not-rewindable", func(t *testing.T) {
+		bodies = nil
+		fn, _ := rotatingKeys("key1", "key2")
+		r := NewResolver(fn)
+		req, err := http.NewRequest("POST", srv.URL, io.NopCloser(bytes.NewBufferString("payload")))
+		require.NoError(t, err)
+		resp, err := r.Do(srv.Client(), req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, []string{"key1:payload"}, bodies)
+
+		// the next request is sent with the new key.
+		req, err = http.NewRequest("POST", srv.URL, io.NopCloser(bytes.NewBufferString("payload")))
+		require.NoError(t, err)
+		resp, err = r.Do(srv.Client(), req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+	r := NewResolver(func(context.Context) (string, error) { return "key", nil })
+	got, ok := FromContext(NewContext(context.Background(), r))
+	assert.True(t, ok)
+	assert.Same(t, r, got)
+}
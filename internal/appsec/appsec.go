@@ -15,6 +15,7 @@ import (
 	"github.com/DataDog/go-libddwaf/v4"
 
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/actions"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
@@ -111,6 +112,7 @@ func Start(opts ...config.StartOption) {
 	}
 
 	registerAppsecStartTelemetry(mode, modeOrigin)
+	registerAPISecTelemetry(cfg.APISec)
 	setActiveAppSec(appsec)
 }
 
@@ -173,6 +175,10 @@ func (a *appsec) start() error {
 		return err
 	}
 
+	if r := a.cfg.BlockingResponse; r != nil {
+		actions.SetBlockedTemplates(r.HTMLTemplate, r.JSONTemplate)
+	}
+
 	a.enableRCBlocking()
 	a.enableRASP()
 
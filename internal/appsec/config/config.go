@@ -60,6 +60,49 @@ type StartConfig struct {
 
 	// BlockingUnavailable is true when the application run in an environment where blocking is not possible
 	BlockingUnavailable bool
+
+	// BlockingResponse customizes the response of the blocked requests, when not nil.
+	BlockingResponse *BlockingResponse
+}
+
+// BlockingResponse customizes the response sent to the requests blocked by the default "block"
+// action of the WAF rules, instead of the default 403 blocked page.
+type BlockingResponse struct {
+	// StatusCode is the status code of the response, 403 when zero. When Location is set, it is
+	// the status code of the redirection, 303 when zero or not a redirection code.
+	StatusCode int
+	// Type is the type of the blocked page: "json", "html", or "auto" (the default) to select it
+	// from the Accept header of the request.
+	Type string
+	// Location, when set, redirects the blocked requests to this URL instead of sending them the
+	// blocked page.
+	Location string
+	// HTMLTemplate and JSONTemplate, when set, replace the default HTML and JSON blocked pages.
+	HTMLTemplate []byte
+	JSONTemplate []byte
+}
+
+// blockAction returns the WAF action replacing the default "block" action, or nil when neither
+// the status code, the type nor the location of the response is customized.
+func (r *BlockingResponse) blockAction() map[string]any {
+	if r == nil || (r.StatusCode == 0 && r.Type == "" && r.Location == "") {
+		return nil
+	}
+	if r.Location != "" {
+		params := map[string]any{"location": r.Location}
+		if r.StatusCode != 0 {
+			params["status_code"] = r.StatusCode
+		}
+		return map[string]any{"id": "block", "type": "redirect_request", "parameters": params}
+	}
+	params := map[string]any{"status_code": 403, "type": "auto"}
+	if r.StatusCode != 0 {
+		params["status_code"] = r.StatusCode
+	}
+	if r.Type != "" {
+		params["type"] = r.Type
+	}
+	return map[string]any{"id": "block", "type": "block_request", "parameters": params}
 }
 
 type EnablementMode int8
@@ -132,6 +175,13 @@ func WithBlockingUnavailable(unavailable bool) StartOption {
 	}
 }
 
+// WithBlockingResponse customizes the response sent to the blocked requests.
+func WithBlockingResponse(r BlockingResponse) StartOption {
+	return func(c *StartConfig) {
+		c.BlockingResponse = &r
+	}
+}
+
 // Config is the AppSec configuration.
 type Config struct {
 	*WAFManager
@@ -154,6 +204,8 @@ type Config struct {
 	BlockingUnavailable bool
 	// TracingAsTransport is true if APM is disabled and manually force keeping a trace is the only way for it to be sent.
 	TracingAsTransport bool
+	// BlockingResponse customizes the response of the blocked requests, when not nil.
+	BlockingResponse *BlockingResponse
 }
 
 // AddressSet is a set of WAF addresses.
@@ -202,6 +254,11 @@ func (c *StartConfig) NewConfig() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if action := c.BlockingResponse.blockAction(); action != nil {
+		if err := manager.SetLocalActions(action); err != nil {
+			return nil, fmt.Errorf("configuring the blocking response: %w", err)
+		}
+	}
 
 	return &Config{
 		WAFManager:          manager,
@@ -213,5 +270,6 @@ func (c *StartConfig) NewConfig() (*Config, error) {
 		MetaStructAvailable: c.MetaStructAvailable,
 		BlockingUnavailable: c.BlockingUnavailable,
 		TracingAsTransport:  !sharedinternal.BoolEnv("DD_APM_TRACING_ENABLED", true),
+		BlockingResponse:    c.BlockingResponse,
 	}, nil
 }
@@ -8,6 +8,8 @@ package config
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
 	"github.com/DataDog/dd-trace-go/v2/internal/telemetry/telemetrytest"
 )
@@ -72,3 +74,55 @@ func TestSCAEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockingResponse(t *testing.T) {
+	t.Run("block-action", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			response *BlockingResponse
+			expected map[string]any
+		}{
+			{name: "nil"},
+			{name: "templates-only", response: &BlockingResponse{HTMLTemplate: []byte("<p>blocked</p>")}},
+			{
+				name:     "status-code",
+				response: &BlockingResponse{StatusCode: 451},
+				expected: map[string]any{"id": "block", "type": "block_request", "parameters": map[string]any{"status_code": 451, "type": "auto"}},
+			},
+			{
+				name:     "type",
+				response: &BlockingResponse{Type: "json"},
+				expected: map[string]any{"id": "block", "type": "block_request", "parameters": map[string]any{"status_code": 403, "type": "json"}},
+			},
+			{
+				name:     "redirect",
+				response: &BlockingResponse{Location: "https://example.com/blocked"},
+				expected: map[string]any{"id": "block", "type": "redirect_request", "parameters": map[string]any{"location": "https://example.com/blocked"}},
+			},
+			{
+				name:     "redirect-status-code",
+				response: &BlockingResponse{Location: "/blocked", StatusCode: 302},
+				expected: map[string]any{"id": "block", "type": "redirect_request", "parameters": map[string]any{"location": "/blocked", "status_code": 302}},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				action := tc.response.blockAction()
+				if tc.expected == nil {
+					assert.Nil(t, action)
+					return
+				}
+				assert.Equal(t, tc.expected, action)
+			})
+		}
+	})
+
+	t.Run("merge-actions", func(t *testing.T) {
+		rulesActions := []any{
+			map[string]any{"id": "block", "type": "block_request"},
+			map[string]any{"id": "custom", "type": "block_request"},
+		}
+		local := map[string]any{"id": "block", "type": "redirect_request"}
+		assert.Equal(t, []any{rulesActions[1], local}, mergeActions(rulesActions, []any{local}))
+		assert.Equal(t, []any{local}, mergeActions(nil, []any{local}))
+	})
+}
@@ -22,6 +22,7 @@ type (
 	WAFManager struct {
 		builder      *libddwaf.Builder
 		initRules    []byte
+		localActions []any
 		rulesVersion string
 		closed       bool
 		mu           sync.RWMutex
@@ -141,6 +142,15 @@ func (m *WAFManager) AddOrUpdateConfig(path string, fragment any) (libddwaf.Diag
 	return diag, err
 }
 
+// SetLocalActions sets the actions added to the initial rules, replacing the actions of the rules
+// having the same IDs, and restores the initial configuration with them.
+func (m *WAFManager) SetLocalActions(actions ...any) error {
+	m.mu.Lock()
+	m.localActions = actions
+	m.mu.Unlock()
+	return m.RestoreDefaultConfig()
+}
+
 // RestoreDefaultConfig restores the initial configurations to the receiving [WAFManager].
 func (m *WAFManager) RestoreDefaultConfig() error {
 	if m.initRules == nil {
@@ -152,11 +162,39 @@ func (m *WAFManager) RestoreDefaultConfig() error {
 	if err := dec.Decode(&rules); err != nil {
 		return err
 	}
+	m.mu.RLock()
+	localActions := m.localActions
+	m.mu.RUnlock()
+	if len(localActions) > 0 {
+		rules["actions"] = mergeActions(rules["actions"], localActions)
+	}
 	diag, err := m.AddOrUpdateConfig(defaultRulesPath, rules)
 	diag.EachFeature(logLocalDiagnosticMessages)
 	return err
 }
 
+// mergeActions returns the actions of the rules followed by the local actions, the local actions
+// replacing the actions of the rules having the same IDs.
+func mergeActions(rulesActions any, localActions []any) []any {
+	ids := make(map[any]struct{}, len(localActions))
+	for _, a := range localActions {
+		if a, ok := a.(map[string]any); ok {
+			ids[a["id"]] = struct{}{}
+		}
+	}
+	list, _ := rulesActions.([]any)
+	merged := make([]any, 0, len(list)+len(localActions))
+	for _, a := range list {
+		if a, ok := a.(map[string]any); ok {
+			if _, replaced := ids[a["id"]]; replaced {
+				continue
+			}
+		}
+		merged = append(merged, a)
+	}
+	return append(merged, localActions...)
+}
+
 func logLocalDiagnosticMessages(name string, feature *libddwaf.Feature) {
 	if feature.Error != "" {
 		telemetryLog.Error("%s", feature.Error, telemetry.WithTags([]string{"appsec_config_key:" + name, "log_type:local::diagnostic"}))
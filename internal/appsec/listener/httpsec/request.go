@@ -8,37 +8,17 @@ package httpsec
 import (
 	"net/http"
 	"net/netip"
-	"os"
 	"strings"
 
-	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/trace"
-)
-
-const (
-	// envClientIPHeader is the name of the env var used to specify the IP header to be used for client IP collection.
-	envClientIPHeader = "DD_TRACE_CLIENT_IP_HEADER"
+	"github.com/DataDog/dd-trace-go/v2/internal/clientip"
 )
 
 var (
-	// defaultIPHeaders is the default list of IP-related headers leveraged to
-	// retrieve the public client IP address in RemoteAddr.
-	defaultIPHeaders = []string{
-		"x-forwarded-for",
-		"x-real-ip",
-		"true-client-ip",
-		"x-client-ip",
-		"x-forwarded",
-		"forwarded-for",
-		"x-cluster-client-ip",
-		"fastly-client-ip",
-		"cf-connecting-ip",
-		"cf-connecting-ipv6",
-	}
-
 	// defaultCollectedHeaders is the default list of HTTP headers collected as
-	// request span tags when appsec is enabled.
-	defaultCollectedHeaders = append([]string{
+	// request span tags when appsec is enabled, along with the IP-related
+	// headers leveraged to resolve the client IP.
+	defaultCollectedHeaders = []string{
 		"host",
 		"content-length",
 		"content-type",
@@ -58,45 +38,21 @@ var (
 		"akamai-user-risk",
 		"x-sigsci-requestid",
 		"x-sigsci-tags",
-	}, defaultIPHeaders...)
+	}
 
 	// collectedHeadersLookupMap is a helper lookup map of HTTP headers to
 	// collect as request span tags when appsec is enabled. It is computed at
 	// init-time based on defaultCollectedHeaders and leveraged by NormalizeHTTPHeaders.
 	collectedHeadersLookupMap map[string]struct{}
-
-	// monitoredClientIPHeadersCfg is the list of IP-related headers leveraged to
-	// retrieve the public client IP address in RemoteAddr. This is defined at init
-	// time in function of the value of the envClientIPHeader environment variable.
-	monitoredClientIPHeadersCfg []string
 )
 
 // ClientIPTags returns the resulting Datadog span tags `http.client_ip`
-// containing the client IP and `network.client.ip` containing the remote IP.
-// The tags are present only if a valid ip address has been returned by
-// RemoteAddr().
+// containing the client IP and `network.client.ip` containing the remote IP,
+// resolved by the default clientip.Resolver. The tags are present only if a
+// valid ip address has been resolved.
 func ClientIPTags(headers map[string][]string, hasCanonicalHeaders bool, remoteAddr string) (tags map[string]string, clientIP netip.Addr) {
-	remoteIP, clientIP := ClientIP(headers, hasCanonicalHeaders, remoteAddr, monitoredClientIPHeadersCfg)
-	return ClientIPTagsFor(remoteIP, clientIP), clientIP
-}
-
-func ClientIPTagsFor(remoteIP netip.Addr, clientIP netip.Addr) map[string]string {
-	remoteIPValid := remoteIP.IsValid()
-	clientIPValid := clientIP.IsValid()
-
-	if !remoteIPValid && !clientIPValid {
-		return nil
-	}
-
-	tags := make(map[string]string, 2)
-	if remoteIPValid {
-		tags[ext.NetworkClientIP] = remoteIP.String()
-	}
-	if clientIPValid {
-		tags[ext.HTTPClientIP] = clientIP.String()
-	}
-
-	return tags
+	remoteIP, clientIP := clientip.Resolve(headers, hasCanonicalHeaders, remoteAddr)
+	return clientip.Tags(remoteIP, clientIP), clientIP
 }
 
 // NormalizeHTTPHeaders returns the HTTP headers following Datadog's
@@ -142,27 +98,19 @@ func normalizeHTTPHeaderValue(values []string) string {
 
 func init() {
 	makeCollectedHTTPHeadersLookupMap()
-	readMonitoredClientIPHeadersConfig()
 }
 
 func makeCollectedHTTPHeadersLookupMap() {
-	collectedHeadersLookupMap = make(map[string]struct{}, len(defaultCollectedHeaders))
+	collectedHeadersLookupMap = make(map[string]struct{}, len(defaultCollectedHeaders)+len(clientip.DefaultHeaders)+1)
 	for _, h := range defaultCollectedHeaders {
 		collectedHeadersLookupMap[h] = struct{}{}
 	}
-}
-
-func readMonitoredClientIPHeadersConfig() {
-	if header := os.Getenv(envClientIPHeader); header != "" {
-		// Make this header the only one to consider in RemoteAddr
-		monitoredClientIPHeadersCfg = []string{header}
-
-		// Add this header to the list of collected headers
-		header = normalizeHTTPHeaderName(header)
-		collectedHeadersLookupMap[header] = struct{}{}
-	} else {
-		// No specific IP header was configured, use the default list
-		monitoredClientIPHeadersCfg = defaultIPHeaders
+	// The IP-related headers are collected too, including the one configured with DD_TRACE_CLIENT_IP_HEADER
+	for _, h := range clientip.DefaultHeaders {
+		collectedHeadersLookupMap[h] = struct{}{}
+	}
+	for _, h := range clientip.Default().Headers() {
+		collectedHeadersLookupMap[h] = struct{}{}
 	}
 }
 
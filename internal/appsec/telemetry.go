@@ -14,6 +14,7 @@ import (
 	"runtime"
 	"sync"
 
+	internal "github.com/DataDog/appsec-internal-go/appsec"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
@@ -56,6 +57,24 @@ func registerAppsecStartTelemetry(mode config.EnablementMode, origin telemetry.O
 	detectLibDLOnce.Do(detectLibDL)
 }
 
+// envAPISecSampleDelay is the env var used to set the interval between the schema extractions of
+// requests sharing the same method, route and status code.
+const envAPISecSampleDelay = "DD_API_SECURITY_SAMPLE_DELAY"
+
+// registerAPISecTelemetry reports the configuration of API Security, which samples requests to
+// attach the schemas of their bodies, headers and parameters (the _dd.appsec.s.* tags) to their
+// spans.
+func registerAPISecTelemetry(cfg internal.APISecConfig) {
+	origin := telemetry.OriginDefault
+	if _, ok := os.LookupEnv(internal.EnvAPISecEnabled); ok {
+		origin = telemetry.OriginEnvVar
+	}
+	telemetry.RegisterAppConfig(internal.EnvAPISecEnabled, cfg.Enabled, origin)
+	if v, ok := os.LookupEnv(envAPISecSampleDelay); ok {
+		telemetry.RegisterAppConfig(envAPISecSampleDelay, v, telemetry.OriginEnvVar)
+	}
+}
+
 func detectLibDL() {
 	if runtime.GOOS != "linux" {
 		return
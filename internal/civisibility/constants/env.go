@@ -38,6 +38,10 @@ const (
 	// CIVisibilityTotalFlakyRetryCountEnvironmentVariable indicates the maximum number of retry attempts for the entire session.
 	CIVisibilityTotalFlakyRetryCountEnvironmentVariable = "DD_CIVISIBILITY_TOTAL_FLAKY_RETRY_COUNT"
 
+	// CIVisibilityEarlyFlakeDetectionEnabledEnvironmentVariable kill-switch that allows to explicitly disable early flake detection even if the remote setting is enabled.
+	// This environment variable should be set to "0" or "false" to disable the early flake detection feature.
+	CIVisibilityEarlyFlakeDetectionEnabledEnvironmentVariable = "DD_CIVISIBILITY_EARLY_FLAKE_DETECTION_ENABLED"
+
 	// CIVisibilityTestManagementEnabledEnvironmentVariable indicates if the test management feature is enabled.
 	CIVisibilityTestManagementEnabledEnvironmentVariable = "DD_TEST_MANAGEMENT_ENABLED"
 
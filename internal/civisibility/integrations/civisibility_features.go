@@ -124,37 +124,50 @@ func ensureSettingsInitialization(serviceName string) {
 			})
 		}
 
-		// check if we need to disable EFD because known tests is not enabled
-		if !ciSettings.KnownTestsEnabled {
-			// "known_tests_enabled" parameter works as a kill-switch for EFD, so if “known_tests_enabled” is false it
-			// will disable EFD even if “early_flake_detection.enabled” is set to true (which should not happen normally,
-			// the backend should disable both of them in that case)
-			ciSettings.EarlyFlakeDetection.Enabled = false
-		}
-
-		// check if flaky test retries is disabled by env-vars
-		if ciSettings.FlakyTestRetriesEnabled && !internal.BoolEnv(constants.CIVisibilityFlakyRetryEnabledEnvironmentVariable, true) {
-			log.Warn("civisibility: flaky test retries was disabled by the environment variable")
-			ciSettings.FlakyTestRetriesEnabled = false
-		}
-
-		// check if test management is disabled by env-vars
-		if ciSettings.TestManagement.Enabled && !internal.BoolEnv(constants.CIVisibilityTestManagementEnabledEnvironmentVariable, true) {
-			log.Warn("civisibility: test management was disabled by the environment variable")
-			ciSettings.TestManagement.Enabled = false
-		}
-
-		// overwrite the test management attempt to fix retries with the env var if set
-		testManagementAttemptToFixRetriesEnv := internal.IntEnv(constants.CIVisibilityTestManagementAttemptToFixRetriesEnvironmentVariable, -1)
-		if testManagementAttemptToFixRetriesEnv != -1 {
-			ciSettings.TestManagement.AttemptToFixRetries = testManagementAttemptToFixRetriesEnv
-		}
+		applySettingsOverrides(ciSettings)
 
 		// set the ciVisibilitySettings with the settings from the backend
 		ciVisibilitySettings = *ciSettings
 	})
 }
 
+// applySettingsOverrides disables the features of ciSettings which are turned off locally, either
+// by another setting or by their kill-switch environment variable, and applies the overrides
+// of the retries set by environment variables.
+func applySettingsOverrides(ciSettings *net.SettingsResponseData) {
+	// check if we need to disable EFD because known tests is not enabled
+	if !ciSettings.KnownTestsEnabled {
+		// "known_tests_enabled" parameter works as a kill-switch for EFD, so if “known_tests_enabled” is false it
+		// will disable EFD even if “early_flake_detection.enabled” is set to true (which should not happen normally,
+		// the backend should disable both of them in that case)
+		ciSettings.EarlyFlakeDetection.Enabled = false
+	}
+
+	// check if early flake detection is disabled by env-vars
+	if ciSettings.EarlyFlakeDetection.Enabled && !internal.BoolEnv(constants.CIVisibilityEarlyFlakeDetectionEnabledEnvironmentVariable, true) {
+		log.Warn("civisibility: early flake detection was disabled by the environment variable")
+		ciSettings.EarlyFlakeDetection.Enabled = false
+	}
+
+	// check if flaky test retries is disabled by env-vars
+	if ciSettings.FlakyTestRetriesEnabled && !internal.BoolEnv(constants.CIVisibilityFlakyRetryEnabledEnvironmentVariable, true) {
+		log.Warn("civisibility: flaky test retries was disabled by the environment variable")
+		ciSettings.FlakyTestRetriesEnabled = false
+	}
+
+	// check if test management is disabled by env-vars
+	if ciSettings.TestManagement.Enabled && !internal.BoolEnv(constants.CIVisibilityTestManagementEnabledEnvironmentVariable, true) {
+		log.Warn("civisibility: test management was disabled by the environment variable")
+		ciSettings.TestManagement.Enabled = false
+	}
+
+	// overwrite the test management attempt to fix retries with the env var if set
+	testManagementAttemptToFixRetriesEnv := internal.IntEnv(constants.CIVisibilityTestManagementAttemptToFixRetriesEnvironmentVariable, -1)
+	if testManagementAttemptToFixRetriesEnv != -1 {
+		ciSettings.TestManagement.AttemptToFixRetries = testManagementAttemptToFixRetriesEnv
+	}
+}
+
 // ensureAdditionalFeaturesInitialization initialize all the additional features
 func ensureAdditionalFeaturesInitialization(_ string) {
 	additionalFeaturesInitializationOnce.Do(func() {
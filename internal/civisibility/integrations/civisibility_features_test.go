@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package integrations
+
+import (
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/constants"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils/net"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySettingsOverridesEarlyFlakeDetection(t *testing.T) {
+	newSettings := func() *net.SettingsResponseData {
+		settings := &net.SettingsResponseData{KnownTestsEnabled: true}
+		settings.EarlyFlakeDetection.Enabled = true
+		return settings
+	}
+
+	t.Run("default", func(t *testing.T) {
+		settings := newSettings()
+		applySettingsOverrides(settings)
+		assert.True(t, settings.EarlyFlakeDetection.Enabled)
+	})
+
+	t.Run("kill-switch", func(t *testing.T) {
+		t.Setenv(constants.CIVisibilityEarlyFlakeDetectionEnabledEnvironmentVariable, "false")
+		settings := newSettings()
+		applySettingsOverrides(settings)
+		assert.False(t, settings.EarlyFlakeDetection.Enabled)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv(constants.CIVisibilityEarlyFlakeDetectionEnabledEnvironmentVariable, "true")
+		settings := newSettings()
+		applySettingsOverrides(settings)
+		assert.True(t, settings.EarlyFlakeDetection.Enabled)
+	})
+
+	t.Run("known-tests-disabled", func(t *testing.T) {
+		t.Setenv(constants.CIVisibilityEarlyFlakeDetectionEnabledEnvironmentVariable, "true")
+		settings := newSettings()
+		settings.KnownTestsEnabled = false
+		applySettingsOverrides(settings)
+		assert.False(t, settings.EarlyFlakeDetection.Enabled)
+	})
+}
@@ -32,7 +32,8 @@ type (
 
 	// ciTestCoverageFile represents the coverage data for a single file.
 	ciTestCoverageFile struct {
-		FileName string `msg:"filename"` // name of the file
+		FileName string `msg:"filename"`         // name of the file
+		Bitmap   []byte `msg:"bitmap,omitempty"` // bitmap of the lines covered, the most significant bit of the first byte being the first line
 	}
 )
 
@@ -53,15 +54,16 @@ func newCiTestCoverageData(tCove *testCoverage) *ciTestCoverageData {
 		SessionID: tCove.sessionID,
 		SuiteID:   tCove.suiteID,
 		SpanID:    tCove.testID,
-		Files:     newCiTestCoverageFiles(tCove.filesCovered),
+		Files:     newCiTestCoverageFiles(tCove.filesCovered, tCove.linesCovered),
 	}
 }
 
-// newCiTestCoverageFiles creates a new instance of ciTestCoverageFile array.
-func newCiTestCoverageFiles(files []string) []*ciTestCoverageFile {
+// newCiTestCoverageFiles creates a new instance of ciTestCoverageFile array, with the bitmaps of the lines covered
+// of the files, if any.
+func newCiTestCoverageFiles(files []string, lines map[string][]byte) []*ciTestCoverageFile {
 	ciFiles := make([]*ciTestCoverageFile, 0, len(files))
 	for _, file := range files {
-		ciFiles = append(ciFiles, &ciTestCoverageFile{FileName: file})
+		ciFiles = append(ciFiles, &ciTestCoverageFile{FileName: file, Bitmap: lines[file]})
 	}
 	return ciFiles
 }
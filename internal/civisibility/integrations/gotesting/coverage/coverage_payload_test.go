@@ -98,3 +98,23 @@ func TestCoveragePayloadEnvelope(t *testing.T) {
 	assert.Equal(testCyclePayload.Version, int32(2))
 	assert.Empty(testCyclePayload.Coverages)
 }
+
+func TestCoverageFileBitmapRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	tc := NewTestCoverage(1, 2, 3, 4, "").(*testCoverage)
+	tc.filesCovered = []string{"file1.go", "file2.go"}
+	tc.linesCovered = map[string][]byte{"file1.go": {0x80, 0x01}}
+	want := newCiTestCoverageData(tc)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(msgp.Encode(buf, want))
+	assert.LessOrEqual(buf.Len(), want.Msgsize())
+
+	var got ciTestCoverageData
+	assert.NoError(msgp.Decode(buf, &got))
+	assert.Len(got.Files, 2)
+	assert.Equal("file1.go", got.Files[0].FileName)
+	assert.Equal([]byte{0x80, 0x01}, got.Files[0].Bitmap)
+	assert.Equal("file2.go", got.Files[1].FileName)
+	assert.Empty(got.Files[1].Bitmap)
+}
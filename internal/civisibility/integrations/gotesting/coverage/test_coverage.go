@@ -17,6 +17,7 @@ import (
 
 	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/integrations"
 	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils/filebitmap"
 	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils/telemetry"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 )
@@ -45,6 +46,7 @@ type (
 		preCoverageFilename  string
 		postCoverageFilename string
 		filesCovered         []string
+		linesCovered         map[string][]byte
 	}
 
 	// coverageData holds information about coverage data with their block
@@ -254,6 +256,7 @@ func (t *testCoverage) processCoverageData() {
 	}
 
 	t.filesCovered = getFilesCovered(t.testFile, preCoverage, postCoverage)
+	t.linesCovered = getLinesCovered(preCoverage, postCoverage)
 	telemetry.CodeCoverageFinished(testFramework, telemetry.DefaultCoverageLibraryType)
 	if len(t.filesCovered) == 0 {
 		telemetry.CodeCoverageIsEmpty()
@@ -389,6 +392,42 @@ func getFilesCovered(testFile string, before, after map[string][]coverageBlock)
 	return result
 }
 
+// getLinesCovered subtracts the before profile from the after profile and returns the bitmaps of the lines covered
+// by file, keyed by the same relative paths as the ones returned by getFilesCovered. The lines of a block are
+// covered when its hit count increased.
+func getLinesCovered(before, after map[string][]coverageBlock) map[string][]byte {
+	result := make(map[string][]byte)
+	for fileName, afterBlocks := range after {
+		beforeCounts := make(map[string]int)
+		for _, block := range before[fileName] {
+			key := fmt.Sprintf("%d.%d-%d.%d", block.startLine, block.startCol, block.endLine, block.endCol)
+			beforeCounts[key] = block.count
+		}
+
+		var lines *filebitmap.FileBitmap
+		for _, afterBlock := range afterBlocks {
+			key := fmt.Sprintf("%d.%d-%d.%d", afterBlock.startLine, afterBlock.startCol, afterBlock.endLine, afterBlock.endCol)
+			if afterBlock.count-beforeCounts[key] <= 0 || afterBlock.startLine <= 0 {
+				continue
+			}
+			if lines == nil {
+				lastLine := 0
+				for _, block := range afterBlocks {
+					lastLine = max(lastLine, block.endLine)
+				}
+				lines = filebitmap.FromLineCount(lastLine)
+			}
+			for line := afterBlock.startLine; line <= afterBlock.endLine; line++ {
+				lines.Set(line)
+			}
+		}
+		if lines != nil {
+			result[getRelativePathFromCITagsSourceRootForCoverage(fileName)] = lines.GetBuffer()
+		}
+	}
+	return result
+}
+
 // getRelativePathFromCITagsSourceRootForCoverage returns the relative path from the CI tags source root for coverage
 // by converting a module path to a module directory.
 func getRelativePathFromCITagsSourceRootForCoverage(filePath string) string {
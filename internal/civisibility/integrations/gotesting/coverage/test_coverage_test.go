@@ -219,6 +219,52 @@ func TestGetFilesCovered(t *testing.T) {
 	}
 }
 
+func TestGetLinesCovered(t *testing.T) {
+	before := map[string][]coverageBlock{
+		"file1.go": {
+			{startLine: 1, startCol: 0, endLine: 1, endCol: 10, numStmt: 1, count: 0},
+			{startLine: 9, startCol: 0, endLine: 10, endCol: 10, numStmt: 2, count: 2},
+		},
+		"file2.go": {
+			{startLine: 2, startCol: 0, endLine: 2, endCol: 10, numStmt: 1, count: 0},
+		},
+	}
+
+	after := map[string][]coverageBlock{
+		"file1.go": {
+			{startLine: 1, startCol: 0, endLine: 1, endCol: 10, numStmt: 1, count: 1},
+			{startLine: 9, startCol: 0, endLine: 10, endCol: 10, numStmt: 2, count: 2},
+		},
+		"file2.go": {
+			{startLine: 2, startCol: 0, endLine: 2, endCol: 10, numStmt: 1, count: 0},
+		},
+		"file3.go": {
+			{startLine: 3, startCol: 0, endLine: 4, endCol: 10, numStmt: 2, count: 1},
+		},
+	}
+
+	linesCovered := getLinesCovered(before, after)
+	expected := map[string][]byte{
+		"file1.go": {0x80, 0x00},
+		"file3.go": {0x30},
+	}
+
+	if len(linesCovered) != len(expected) {
+		t.Errorf("Expected %d files with lines covered, got %d", len(expected), len(linesCovered))
+	}
+
+	for file, expectedBitmap := range expected {
+		bitmap, ok := linesCovered[file]
+		if !ok {
+			t.Errorf("Expected file %s to have lines covered", file)
+			continue
+		}
+		if string(bitmap) != string(expectedBitmap) {
+			t.Errorf("Expected bitmap %08b for file %s, got %08b", expectedBitmap, file, bitmap)
+		}
+	}
+}
+
 func TestCollectCoverageBeforeTestExecution(t *testing.T) {
 	// Mock environment
 	tempDir := t.TempDir()
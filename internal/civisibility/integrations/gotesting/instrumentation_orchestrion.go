@@ -184,6 +184,12 @@ func instrumentTestingTFunc(f func(*testing.T)) func(*testing.T) {
 			return
 		}
 
+		// Check if the subtest needs to be skipped by ITR, the backend reporting the skippable
+		// subtests by their full name, e.g. TestParent/subtest.
+		if isSkippableByITR(suiteName, t.Name()) && skipByITR(t, module, suite, test, execMeta) {
+			return
+		}
+
 		defer func() {
 			// Collect and write logs
 			collectAndWriteLogs(t, test)
@@ -417,30 +423,7 @@ func instrumentTestingBFunc(pb *testing.B, name string, f func(*testing.B)) (str
 		results := iPfOfB.result
 
 		// Set benchmark data for CI visibility.
-		test.SetBenchmarkData("duration", map[string]any{
-			"run":  results.N,
-			"mean": results.NsPerOp(),
-		})
-		test.SetBenchmarkData("memory_total_operations", map[string]any{
-			"run":            results.N,
-			"mean":           results.AllocsPerOp(),
-			"statistics.max": results.MemAllocs,
-		})
-		test.SetBenchmarkData("mean_heap_allocations", map[string]any{
-			"run":  results.N,
-			"mean": results.AllocedBytesPerOp(),
-		})
-		test.SetBenchmarkData("total_heap_allocations", map[string]any{
-			"run":  results.N,
-			"mean": iPfOfB.result.MemBytes,
-		})
-		if len(results.Extra) > 0 {
-			mapConverted := map[string]any{}
-			for k, v := range results.Extra {
-				mapConverted[k] = v
-			}
-			test.SetBenchmarkData("extra", mapConverted)
-		}
+		setBenchmarkData(test, results)
 
 		// Define a function to handle panic during benchmark finalization.
 		panicFunc := func(r any) {
@@ -179,21 +179,10 @@ func (ddm *M) executeInternalTest(testInfo *testingTInfo) func(*testing.T) {
 	// Get the settings response for this session
 	settings := integrations.GetSettings()
 	coverageEnabled := settings.CodeCoverage
-	testSkippedByITR := false
 	testIsNew := true
 
 	// Check if the test is going to be skipped by ITR
-	if settings.ItrEnabled && settings.TestsSkipping {
-		// Check if the test is going to be skipped by ITR
-		skippableTests := integrations.GetSkippableTests()
-		if skippableTests != nil {
-			if suitesMap, ok := skippableTests[testInfo.suiteName]; ok {
-				if _, ok := suitesMap[testInfo.testName]; ok {
-					testSkippedByITR = true
-				}
-			}
-		}
-	}
+	testSkippedByITR := isSkippableByITR(testInfo.suiteName, testInfo.testName)
 
 	// Check if the test is known
 	if settings.KnownTestsEnabled {
@@ -232,21 +221,9 @@ func (ddm *M) executeInternalTest(testInfo *testingTInfo) func(*testing.T) {
 			return
 		}
 
-		// Check if the test needs to be skipped by ITR (attempt to fix is excluded)
-		if testSkippedByITR && !execMeta.isAttemptToFix && !execMeta.isAModifiedTest {
-			// check if the test was marked as unskippable
-			if test.Context().Value(constants.TestUnskippable) != true {
-				test.SetTag(constants.TestSkippedByITR, "true")
-				test.Close(integrations.ResultStatusSkip, integrations.WithTestSkipReason(constants.SkippedByITRReason))
-				telemetry.ITRSkipped(telemetry.TestEventType)
-				session.SetTag(constants.ITRTestsSkipped, "true")
-				session.SetTag(constants.ITRTestsSkippingCount, numOfTestsSkipped.Add(1))
-				checkModuleAndSuite(module, suite)
-				t.Skip(constants.SkippedByITRReason)
-				return
-			}
-			test.SetTag(constants.TestForcedToRun, "true")
-			telemetry.ITRForcedRun(telemetry.TestEventType)
+		// Check if the test needs to be skipped by ITR
+		if testSkippedByITR && skipByITR(t, module, suite, test, execMeta) {
+			return
 		}
 
 		// Check if the coverage is enabled
@@ -518,30 +495,7 @@ func (ddm *M) executeInternalBenchmark(benchmarkInfo *testingBInfo) func(*testin
 		results := iPfOfB.result
 
 		// Set benchmark data for CI visibility.
-		test.SetBenchmarkData("duration", map[string]any{
-			"run":  results.N,
-			"mean": results.NsPerOp(),
-		})
-		test.SetBenchmarkData("memory_total_operations", map[string]any{
-			"run":            results.N,
-			"mean":           results.AllocsPerOp(),
-			"statistics.max": results.MemAllocs,
-		})
-		test.SetBenchmarkData("mean_heap_allocations", map[string]any{
-			"run":  results.N,
-			"mean": results.AllocedBytesPerOp(),
-		})
-		test.SetBenchmarkData("total_heap_allocations", map[string]any{
-			"run":  results.N,
-			"mean": iPfOfB.result.MemBytes,
-		})
-		if len(results.Extra) > 0 {
-			mapConverted := map[string]any{}
-			for k, v := range results.Extra {
-				mapConverted[k] = v
-			}
-			test.SetBenchmarkData("extra", mapConverted)
-		}
+		setBenchmarkData(test, results)
 
 		// Define a function to handle panic during benchmark finalization.
 		panicFunc := func(r any) {
@@ -578,6 +532,43 @@ func RunM(m *testing.M) int {
 	return (*M)(m).Run()
 }
 
+// isSkippableByITR reports whether the test of the given suite can be skipped by ITR, i.e. whether
+// the backend reported it as not impacted by the changes, according to its coverage.
+func isSkippableByITR(suiteName string, testName string) bool {
+	settings := integrations.GetSettings()
+	if settings == nil || !settings.ItrEnabled || !settings.TestsSkipping {
+		return false
+	}
+	if suitesMap, ok := integrations.GetSkippableTests()[suiteName]; ok {
+		_, ok = suitesMap[testName]
+		return ok
+	}
+	return false
+}
+
+// skipByITR closes the test as skipped by ITR and skips t, unless the test is unskippable, an attempt
+// to fix, or a modified test. It returns false when the test has to run, in which case it must
+// continue.
+func skipByITR(t *testing.T, module integrations.TestModule, suite integrations.TestSuite, test integrations.Test, execMeta *testExecutionMetadata) bool {
+	if execMeta.isAttemptToFix || execMeta.isAModifiedTest {
+		return false
+	}
+	// check if the test was marked as unskippable
+	if test.Context().Value(constants.TestUnskippable) == true {
+		test.SetTag(constants.TestForcedToRun, "true")
+		telemetry.ITRForcedRun(telemetry.TestEventType)
+		return false
+	}
+	test.SetTag(constants.TestSkippedByITR, "true")
+	test.Close(integrations.ResultStatusSkip, integrations.WithTestSkipReason(constants.SkippedByITRReason))
+	telemetry.ITRSkipped(telemetry.TestEventType)
+	session.SetTag(constants.ITRTestsSkipped, "true")
+	session.SetTag(constants.ITRTestsSkippingCount, numOfTestsSkipped.Add(1))
+	checkModuleAndSuite(module, suite)
+	t.Skip(constants.SkippedByITRReason)
+	return true
+}
+
 // checkModuleAndSuite checks and closes the modules and suites if all tests are executed.
 func checkModuleAndSuite(module integrations.TestModule, suite integrations.TestSuite) {
 	// If all tests in a suite has been executed we can close the suite
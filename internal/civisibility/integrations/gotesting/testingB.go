@@ -198,7 +198,44 @@ func hasCiVisibilityBenchmarkFunc(fn *runtime.Func) bool {
 
 // setCiVisibilityBenchmarkFunc tracks a *runtime.Func as instrumented benchmark.
 func setCiVisibilityBenchmarkFunc(fn *runtime.Func) {
-	civisibilityBenchmarksFuncsMutex.RLock()
-	defer civisibilityBenchmarksFuncsMutex.RUnlock()
+	civisibilityBenchmarksFuncsMutex.Lock()
+	defer civisibilityBenchmarksFuncsMutex.Unlock()
 	civisibilityBenchmarksFuncs[fn] = struct{}{}
 }
+
+// setBenchmarkData sets the results of a benchmark run on its CI Visibility test: the time, the allocations and,
+// when the benchmark called SetBytes, the throughput per operation, along with the custom metrics reported with
+// ReportMetric.
+func setBenchmarkData(test integrations.Test, results *testing.BenchmarkResult) {
+	test.SetBenchmarkData("duration", map[string]any{
+		"run":  results.N,
+		"mean": results.NsPerOp(),
+	})
+	test.SetBenchmarkData("memory_total_operations", map[string]any{
+		"run":            results.N,
+		"mean":           results.AllocsPerOp(),
+		"statistics.max": results.MemAllocs,
+	})
+	test.SetBenchmarkData("mean_heap_allocations", map[string]any{
+		"run":  results.N,
+		"mean": results.AllocedBytesPerOp(),
+	})
+	test.SetBenchmarkData("total_heap_allocations", map[string]any{
+		"run":  results.N,
+		"mean": results.MemBytes,
+	})
+	if results.Bytes > 0 && results.T > 0 {
+		// Same as the MB/s reported by the testing package
+		test.SetBenchmarkData("throughput", map[string]any{
+			"run":  results.N,
+			"mean": (float64(results.Bytes) * float64(results.N) / 1e6) / results.T.Seconds(),
+		})
+	}
+	if len(results.Extra) > 0 {
+		mapConverted := map[string]any{}
+		for k, v := range results.Extra {
+			mapConverted[k] = v
+		}
+		test.SetBenchmarkData("extra", mapConverted)
+	}
+}
@@ -0,0 +1,232 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024 Datadog, Inc.
+
+// Package clientip resolves the IP address of the clients of the http and grpc servers, from
+// the IP-related headers of their requests and their remote address. It is shared by the http
+// server integrations and AppSec so that they all report the same client IP.
+package clientip
+
+import (
+	"net"
+	"net/netip"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+)
+
+// EnvHeader is the name of the env var used to specify the only header to be used for client
+// IP resolution, instead of DefaultHeaders.
+const EnvHeader = "DD_TRACE_CLIENT_IP_HEADER"
+
+// DefaultHeaders is the default list of IP-related headers leveraged to resolve the client IP,
+// by order of precedence.
+var DefaultHeaders = []string{
+	"x-forwarded-for",
+	"x-real-ip",
+	"true-client-ip",
+	"x-client-ip",
+	"x-forwarded",
+	"forwarded-for",
+	"x-cluster-client-ip",
+	"fastly-client-ip",
+	"cf-connecting-ip",
+	"cf-connecting-ipv6",
+	"forwarded",
+}
+
+// Resolver resolves the client IP of requests from a list of IP-related headers.
+type Resolver struct {
+	headers []string
+}
+
+// NewResolver returns a Resolver looking for the client IP in the given headers, by order of
+// precedence, or in DefaultHeaders when none is given.
+func NewResolver(headers ...string) Resolver {
+	if len(headers) == 0 {
+		headers = DefaultHeaders
+	}
+	normalized := make([]string, len(headers))
+	for i, h := range headers {
+		normalized[i] = strings.ToLower(strings.TrimSpace(h))
+	}
+	return Resolver{headers: normalized}
+}
+
+// defaultResolver is the Resolver configured with DD_TRACE_CLIENT_IP_HEADER.
+var defaultResolver = resolverFromEnv()
+
+func resolverFromEnv() Resolver {
+	if h := os.Getenv(EnvHeader); strings.TrimSpace(h) != "" {
+		// Make this header the only one to consider
+		return NewResolver(h)
+	}
+	return NewResolver()
+}
+
+// Default returns the Resolver configured with DD_TRACE_CLIENT_IP_HEADER, or looking for the
+// client IP in DefaultHeaders when the env var isn't set.
+func Default() Resolver {
+	return defaultResolver
+}
+
+// Headers returns the lower-cased names of the headers looked up by r, by order of precedence.
+func (r Resolver) Headers() []string {
+	return r.headers
+}
+
+// Resolve returns the remote IP, parsed from remoteAddr, and the client IP of a request. The
+// client IP is the first public IP address found in the given headers, walking the headers by
+// order of precedence and the comma-separated addresses of each header from the client to the
+// last proxy. If none is present, it is the first valid IP address found, possibly being a private
+// one, unless the remote IP is public. The remote IP is used as fallback when no IP address has
+// been found at all.
+//
+// hasCanonicalHeaders reports whether the keys of hdrs are canonical MIME header keys, as in
+// http.Header, or lower-cased, as in grpc metadata.
+func (r Resolver) Resolve(hdrs map[string][]string, hasCanonicalHeaders bool, remoteAddr string) (remoteIP, clientIP netip.Addr) {
+	// Walk IP-related headers
+	var foundIP netip.Addr
+headersLoop:
+	for _, headerName := range r.headers {
+		key := headerName
+		if hasCanonicalHeaders {
+			key = textproto.CanonicalMIMEHeaderKey(headerName)
+		}
+
+		headerValues, exists := hdrs[key]
+		if !exists {
+			continue // this monitored header is not present
+		}
+
+		// Look for the first valid or global IP address in the lists of addresses
+		for _, value := range headerValues {
+			for _, ipstr := range strings.Split(value, ",") {
+				var ip netip.Addr
+				if headerName == "forwarded" {
+					ip = parseForwardedFor(ipstr)
+				} else {
+					ip = parseIP(strings.TrimSpace(ipstr))
+				}
+				if !ip.IsValid() {
+					continue
+				}
+				// Replace foundIP if still not valid in order to keep the oldest
+				if !foundIP.IsValid() {
+					foundIP = ip
+				}
+				if IsGlobal(ip) {
+					foundIP = ip
+					break headersLoop
+				}
+			}
+		}
+	}
+
+	// Decide which IP address is the client one by starting with the remote IP
+	if ip := parseIP(remoteAddr); ip.IsValid() {
+		remoteIP = ip
+		clientIP = ip
+	}
+
+	// The IP address found in the headers supersedes a private remote IP address.
+	if foundIP.IsValid() && !IsGlobal(remoteIP) || IsGlobal(foundIP) {
+		clientIP = foundIP
+	}
+
+	return remoteIP, clientIP
+}
+
+// Resolve returns the remote IP and the client IP of a request using the Default resolver.
+func Resolve(hdrs map[string][]string, hasCanonicalHeaders bool, remoteAddr string) (remoteIP, clientIP netip.Addr) {
+	return defaultResolver.Resolve(hdrs, hasCanonicalHeaders, remoteAddr)
+}
+
+// Tags returns the span tags `http.client_ip` containing the client IP and `network.client.ip`
+// containing the remote IP. The tags are present only when the corresponding address is valid.
+func Tags(remoteIP, clientIP netip.Addr) map[string]string {
+	remoteIPValid := remoteIP.IsValid()
+	clientIPValid := clientIP.IsValid()
+
+	if !remoteIPValid && !clientIPValid {
+		return nil
+	}
+
+	tags := make(map[string]string, 2)
+	if remoteIPValid {
+		tags[ext.NetworkClientIP] = remoteIP.String()
+	}
+	if clientIPValid {
+		tags[ext.HTTPClientIP] = clientIP.String()
+	}
+
+	return tags
+}
+
+// parseIP parses an IP address optionally followed by a port, IPv6 addresses being enclosed in
+// brackets in that case. IPv4-mapped IPv6 addresses are returned as IPv4 addresses.
+func parseIP(s string) netip.Addr {
+	if ip, err := netip.ParseAddr(s); err == nil {
+		return ip.Unmap()
+	}
+	if h, _, err := net.SplitHostPort(s); err == nil {
+		if ip, err := netip.ParseAddr(h); err == nil {
+			return ip.Unmap()
+		}
+	}
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		if ip, err := netip.ParseAddr(s[1 : len(s)-1]); err == nil {
+			return ip.Unmap()
+		}
+	}
+	return netip.Addr{}
+}
+
+// parseForwardedFor parses the address of the "for" parameter of an element of a Forwarded
+// header (RFC 7239), e.g. `for=192.0.2.60;proto=http` or `for="[2001:db8::1]:4711"`. Unknown
+// and obfuscated identifiers are ignored.
+func parseForwardedFor(element string) netip.Addr {
+	for _, pair := range strings.Split(element, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !strings.EqualFold(k, "for") {
+			continue
+		}
+		return parseIP(strings.Trim(strings.TrimSpace(v), `"`))
+	}
+	return netip.Addr{}
+}
+
+var (
+	ipv6SpecialNetworks = [...]netip.Prefix{
+		netip.MustParsePrefix("fec0::/10"), // site local
+	}
+
+	// This IP block is not routable on internet and an industry standard/trend
+	// is emerging to use it for traditional IT-managed networking environments
+	// with limited RFC1918 space allocations. This is also frequently used by
+	// kubernetes pods' internal networking. It is hence deemed private for the
+	// purpose of Client IP extraction.
+	k8sInternalIPv4Prefix = netip.MustParsePrefix("100.65.0.0/10")
+)
+
+// IsGlobal reports whether ip is a public IP address, i.e. neither private, nor loopback, nor
+// link-local, nor unspecified, nor multicast.
+func IsGlobal(ip netip.Addr) bool {
+	// IsPrivate also checks for ipv6 ULA.
+	// We care to check for these addresses are not considered public, hence not global.
+	// See https://www.rfc-editor.org/rfc/rfc4193.txt for more details.
+	isGlobal := ip.IsValid() && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsUnspecified() && !ip.IsMulticast() && !k8sInternalIPv4Prefix.Contains(ip)
+	if !isGlobal || !ip.Is6() {
+		return isGlobal
+	}
+	for _, n := range ipv6SpecialNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return isGlobal
+}
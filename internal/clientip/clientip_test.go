@@ -3,7 +3,7 @@
 // This product includes software developed at Datadog (https://www.datadoghq.com/).
 // Copyright 2024 Datadog, Inc.
 
-package httpsec
+package clientip
 
 import (
 	"fmt"
@@ -311,8 +311,8 @@ func TestClientIPExtraction(t *testing.T) {
 					if tc.clientIPHeaders != nil {
 						monitoredHeaders = tc.clientIPHeaders
 					}
-					remoteIP, clientIP := ClientIP(headers, hasCanonicalMIMEHeaderKeys, tc.remoteAddr, monitoredHeaders)
-					tags := ClientIPTagsFor(remoteIP, clientIP)
+					remoteIP, clientIP := NewResolver(monitoredHeaders...).Resolve(headers, hasCanonicalMIMEHeaderKeys, tc.remoteAddr)
+					tags := Tags(remoteIP, clientIP)
 					if tc.expectedIP.IsValid() {
 						expectedIP := tc.expectedIP.String()
 						require.Equal(t, expectedIP, clientIP.String())
@@ -333,6 +333,85 @@ func TestClientIPExtraction(t *testing.T) {
 	}
 }
 
+func TestResolveProxies(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		headers    http.Header
+		remoteAddr string
+		expected   string
+	}{
+		{
+			name:       "proxy-chain",
+			headers:    http.Header{"X-Forwarded-For": {"10.0.0.1, 203.0.113.7, 198.51.100.2"}},
+			remoteAddr: "10.1.2.3:8080",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "multiple-header-lines",
+			headers:    http.Header{"X-Forwarded-For": {"10.0.0.1", "203.0.113.7"}},
+			remoteAddr: "10.1.2.3:8080",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "ports",
+			headers:    http.Header{"X-Forwarded-For": {"203.0.113.7:4711, [2001:db8:1::1]:80"}},
+			remoteAddr: "10.1.2.3:8080",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "ipv4-mapped",
+			headers:    http.Header{"X-Forwarded-For": {"::ffff:10.0.0.1"}},
+			remoteAddr: "[::ffff:10.1.2.3]:8080",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "cgnat",
+			headers:    http.Header{"X-Forwarded-For": {"100.64.0.1, 100.127.0.1"}},
+			remoteAddr: "10.1.2.3:8080",
+			expected:   "100.64.0.1",
+		},
+		{
+			name:       "unspecified-and-multicast",
+			headers:    http.Header{"X-Forwarded-For": {"0.0.0.0, 224.0.0.1"}},
+			remoteAddr: "203.0.113.7:8080",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "forwarded",
+			headers:    http.Header{"Forwarded": {`for=unknown, for="_hidden";proto=https, For="[2001:db8:cafe::17]:4711";by=10.0.0.1`}},
+			remoteAddr: "10.1.2.3:8080",
+			expected:   "2001:db8:cafe::17",
+		},
+		{
+			name:       "header-precedence",
+			headers:    http.Header{"Cf-Connecting-Ip": {"198.51.100.2"}, "X-Forwarded-For": {"203.0.113.7"}},
+			remoteAddr: "10.1.2.3:8080",
+			expected:   "203.0.113.7",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, clientIP := NewResolver().Resolve(tc.headers, true, tc.remoteAddr)
+			require.Equal(t, tc.expected, clientIP.String())
+		})
+	}
+}
+
+func TestResolverFromEnv(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		require.Equal(t, DefaultHeaders, resolverFromEnv().Headers())
+	})
+
+	t.Run("custom-header", func(t *testing.T) {
+		t.Setenv(EnvHeader, "X-My-Client-IP")
+		r := resolverFromEnv()
+		require.Equal(t, []string{"x-my-client-ip"}, r.Headers())
+
+		headers := http.Header{"X-Forwarded-For": {"203.0.113.7"}, "X-My-Client-Ip": {"198.51.100.2"}}
+		_, clientIP := r.Resolve(headers, true, "10.1.2.3:8080")
+		require.Equal(t, "198.51.100.2", clientIP.String())
+	})
+}
+
 func randIPv4() netip.Addr {
 	return netip.AddrFrom4([4]byte{byte(rand.Uint32()), byte(rand.Uint32()), byte(rand.Uint32()), byte(rand.Uint32())})
 }
@@ -349,7 +428,7 @@ func randIPv6() netip.Addr {
 func randGlobalIPv4() netip.Addr {
 	for {
 		ip := randIPv4()
-		if isGlobalIP(ip) {
+		if IsGlobal(ip) {
 			return ip
 		}
 	}
@@ -358,7 +437,7 @@ func randGlobalIPv4() netip.Addr {
 func randGlobalIPv6() netip.Addr {
 	for {
 		ip := randIPv6()
-		if isGlobalIP(ip) {
+		if IsGlobal(ip) {
 			return ip
 		}
 	}
@@ -367,7 +446,7 @@ func randGlobalIPv6() netip.Addr {
 func randPrivateIPv4() netip.Addr {
 	for {
 		ip := randIPv4()
-		if !isGlobalIP(ip) && ip.IsPrivate() {
+		if !IsGlobal(ip) && ip.IsPrivate() {
 			return ip
 		}
 	}
@@ -386,7 +465,7 @@ func randK8sPrivate() netip.Addr {
 func randPrivateIPv6() netip.Addr {
 	for {
 		ip := randIPv6()
-		if !isGlobalIP(ip) && ip.IsPrivate() {
+		if !IsGlobal(ip) && ip.IsPrivate() {
 			return ip
 		}
 	}
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"syscall"
 )
@@ -22,6 +23,10 @@ const (
 	// cgroupPath is the path to the cgroup file where we can find the container id if one exists.
 	cgroupPath = "/proc/self/cgroup"
 
+	// mountinfoPath is the path to the mountinfo file where we can find the container id when the
+	// process has its own cgroup namespace, and thus no container id in its cgroup paths.
+	mountinfoPath = "/proc/self/mountinfo"
+
 	// cgroupV1BaseController is the base controller used to identify the cgroup v1 mount point in the cgroupMounts map.
 	cgroupV1BaseController = "memory"
 
@@ -36,6 +41,10 @@ const (
 	// Currently, host namespace inode number are hardcoded, which can be used to detect
 	// if we're running in host namespace or not (does not work when running in DinD)
 	hostCgroupNamespaceInode = 0xEFFFFFFB
+
+	// cgroupV1NoMemoryLimit is the lowest memory.limit_in_bytes of cgroup v1 meaning that there
+	// is no limit: the kernel reports MaxInt64 rounded down to the page size.
+	cgroupV1NoMemoryLimit = 1 << 62
 )
 
 var (
@@ -45,6 +54,11 @@ var (
 	// expContainerID matches contained IDs and sources. Source: https://github.com/Qard/container-info/blob/master/index.js
 	expContainerID = regexp.MustCompile(fmt.Sprintf(`(%s|%s|%s)(?:.scope)?$`, uuidSource, containerSource, taskSource))
 
+	// expMountinfoContainerID matches the mount points of the files the container runtimes bind
+	// mount in the containers, e.g. /var/lib/docker/containers/<id>/hostname. It has a submatch
+	// for the parent directory, and one for the container ID.
+	expMountinfoContainerID = regexp.MustCompile(fmt.Sprintf(`.*/([^\s/]+)/(%s)/[\S]*hostname`, containerSource))
+
 	// containerID is the containerID read at init from /proc/self/cgroup
 	containerID string
 
@@ -56,6 +70,11 @@ var (
 
 func init() {
 	containerID = readContainerID(cgroupPath)
+	if containerID == "" {
+		// With cgroup v2 and a private cgroup namespace, as on Bottlerocket or GKE Autopilot,
+		// /proc/self/cgroup only holds "0::/".
+		containerID = readMountinfoContainerID(mountinfoPath)
+	}
 	entityID = readEntityID(defaultCgroupMountPath, cgroupPath, isHostCgroupNamespace())
 }
 
@@ -85,7 +104,34 @@ func readContainerID(fpath string) string {
 	return parseContainerID(f)
 }
 
-// ContainerID attempts to return the container ID from /proc/self/cgroup or empty on failure.
+// parseMountinfoContainerID finds the first container ID in the mount points read from r and
+// returns it. The mount points of the pod sandboxes are skipped, as their ID is the one of the
+// pause container of the pod rather than the one of the container.
+func parseMountinfoContainerID(r io.Reader) string {
+	scn := bufio.NewScanner(r)
+	for scn.Scan() {
+		m := expMountinfoContainerID.FindStringSubmatch(scn.Text())
+		if len(m) != 3 || m[1] == "sandboxes" {
+			continue
+		}
+		return m[2]
+	}
+	return ""
+}
+
+// readMountinfoContainerID attempts to return the container ID from the provided mountinfo file
+// path or empty on failure.
+func readMountinfoContainerID(fpath string) string {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	return parseMountinfoContainerID(f)
+}
+
+// ContainerID attempts to return the container ID from /proc/self/cgroup, or from
+// /proc/self/mountinfo with cgroup v2, or empty on failure.
 func ContainerID() string {
 	return containerID
 }
@@ -180,3 +226,99 @@ func isHostCgroupNamespace() bool {
 
 	return false
 }
+
+// readCPUQuota returns the CPU quota, in CPUs, of the cgroup mounted at mountPath, and whether
+// the cgroup has one. It supports cgroup v2, whose quota is in cpu.max, and cgroup v1, whose
+// quota is in the cpu.cfs_quota_us and cpu.cfs_period_us files of the cpu controller.
+func readCPUQuota(mountPath string) (float64, bool) {
+	if b, err := os.ReadFile(path.Join(mountPath, "cpu.max")); err == nil {
+		// cpu.max holds "$MAX $PERIOD", MAX being "max" when there is no quota.
+		fields := strings.Fields(string(b))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, false
+		}
+		return cpuQuota(fields[0], fields[1])
+	}
+	for _, controller := range []string{"cpu", "cpu,cpuacct"} {
+		quota, err := os.ReadFile(path.Join(mountPath, controller, "cpu.cfs_quota_us"))
+		if err != nil {
+			continue
+		}
+		period, err := os.ReadFile(path.Join(mountPath, controller, "cpu.cfs_period_us"))
+		if err != nil {
+			continue
+		}
+		return cpuQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+	}
+	return 0, false
+}
+
+// cpuQuota returns the number of CPUs of a quota of quota microseconds per period microseconds.
+// A negative quota means that there is no quota.
+func cpuQuota(quota, period string) (float64, bool) {
+	q, err := strconv.ParseInt(quota, 10, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseInt(period, 10, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return float64(q) / float64(p), true
+}
+
+// CPUQuota returns the CPU quota of the cgroup of the process, in CPUs, e.g. 1.5 for a quota of
+// 150ms every 100ms, and whether the process has one.
+func CPUQuota() (float64, bool) {
+	return readCPUQuota(defaultCgroupMountPath)
+}
+
+// readCgroupLimits returns the CPU quota and period, in microseconds, and the memory limit, in
+// bytes, of the cgroup mounted at mountPath. The values are zero when the cgroup has no limit.
+// Like readCPUQuota, it supports cgroup v2 and cgroup v1.
+func readCgroupLimits(mountPath string) (cpuQuotaUs, cpuPeriodUs, memoryLimit int64) {
+	parse := func(s string) int64 {
+		v, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil || v <= 0 || v >= cgroupV1NoMemoryLimit {
+			// "max", negative quotas and the page-aligned MaxInt64 of
+			// cgroup v1 mean that there is no limit.
+			return 0
+		}
+		return v
+	}
+	if b, err := os.ReadFile(path.Join(mountPath, "cpu.max")); err == nil {
+		if fields := strings.Fields(string(b)); len(fields) == 2 {
+			if cpuQuotaUs = parse(fields[0]); cpuQuotaUs > 0 {
+				cpuPeriodUs = parse(fields[1])
+			}
+		}
+		if b, err := os.ReadFile(path.Join(mountPath, "memory.max")); err == nil {
+			memoryLimit = parse(string(b))
+		}
+		return cpuQuotaUs, cpuPeriodUs, memoryLimit
+	}
+	for _, controller := range []string{"cpu", "cpu,cpuacct"} {
+		quota, err := os.ReadFile(path.Join(mountPath, controller, "cpu.cfs_quota_us"))
+		if err != nil {
+			continue
+		}
+		period, err := os.ReadFile(path.Join(mountPath, controller, "cpu.cfs_period_us"))
+		if err != nil {
+			continue
+		}
+		if cpuQuotaUs = parse(string(quota)); cpuQuotaUs > 0 {
+			cpuPeriodUs = parse(string(period))
+		}
+		break
+	}
+	if b, err := os.ReadFile(path.Join(mountPath, cgroupV1BaseController, "memory.limit_in_bytes")); err == nil {
+		memoryLimit = parse(string(b))
+	}
+	return cpuQuotaUs, cpuPeriodUs, memoryLimit
+}
+
+// CgroupLimits returns the CPU quota and period, in microseconds, and the memory limit, in bytes,
+// of the cgroup of the process. The values are zero when the cgroup has no limit.
+func CgroupLimits() (cpuQuotaUs, cpuPeriodUs, memoryLimit int64) {
+	return readCgroupLimits(defaultCgroupMountPath)
+}
@@ -65,6 +65,47 @@ func TestReadContainerID(t *testing.T) {
 	}
 }
 
+func TestParseMountinfoContainerID(t *testing.T) {
+	for name, tc := range map[string]struct {
+		in, out string
+	}{
+		"docker": {
+			in: `608 554 0:50 / / rw,relatime master:289 - overlay overlay rw,lowerdir=/var/lib/docker/overlay2/l/XQ4ZQ
+622 608 0:59 / /proc rw,nosuid,nodev,noexec,relatime - proc proc rw
+637 608 254:1 /docker/containers/0cfa82bf3ab29da271548d6a044e95c948c6fd2f7578fb41833a44ca23da425f/resolv.conf /etc/resolv.conf rw,relatime - ext4 /dev/vda1 rw
+638 608 254:1 /docker/containers/0cfa82bf3ab29da271548d6a044e95c948c6fd2f7578fb41833a44ca23da425f/hostname /etc/hostname rw,relatime - ext4 /dev/vda1 rw`,
+			out: "0cfa82bf3ab29da271548d6a044e95c948c6fd2f7578fb41833a44ca23da425f",
+		},
+		"containerd": {
+			in: `2200 2122 259:1 /var/lib/containerd/io.containerd.grpc.v1.cri/sandboxes/fc7038bc73a8d3850c66ddbfb0b2901afa378bfcbb942cc384b051767e4ac6b0/hostname /etc/hostname rw,nosuid,nodev,noatime - ext4 /dev/nvme0n1p1 rw
+2201 2122 259:1 /var/lib/kubelet/pods/7ad8c6e0-5b4d-4b9b-a5fb-9f44fbbd8a8a/containers/app/3f4a79d4 /dev/termination-log rw,nosuid,nodev,noatime - ext4 /dev/nvme0n1p1 rw
+2202 2122 259:1 /var/lib/containerd/io.containerd.grpc.v1.cri/containers/2b4f2e3e9c96e6b1f1f7c7fb0e0f58e5be6f0e19b0bb4c76d1b6bb5c17a3fb8f/hostname /etc/hostname rw,nosuid,nodev,noatime - ext4 /dev/nvme0n1p1 rw`,
+			out: "2b4f2e3e9c96e6b1f1f7c7fb0e0f58e5be6f0e19b0bb4c76d1b6bb5c17a3fb8f",
+		},
+		"sandbox only": {
+			in:  `2200 2122 259:1 /var/lib/containerd/io.containerd.grpc.v1.cri/sandboxes/fc7038bc73a8d3850c66ddbfb0b2901afa378bfcbb942cc384b051767e4ac6b0/hostname /etc/hostname rw - ext4 /dev/nvme0n1p1 rw`,
+			out: "",
+		},
+		"none": {
+			in:  `22 1 0:21 / /sys rw,nosuid,nodev,noexec,relatime shared:7 - sysfs sysfs rw`,
+			out: "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.out, parseMountinfoContainerID(strings.NewReader(tc.in)))
+		})
+	}
+}
+
+func TestReadMountinfoContainerID(t *testing.T) {
+	cid := "0cfa82bf3ab29da271548d6a044e95c948c6fd2f7578fb41833a44ca23da425f"
+	fpath := path.Join(t.TempDir(), "mountinfo")
+	contents := "638 608 254:1 /docker/containers/" + cid + "/hostname /etc/hostname rw,relatime - ext4 /dev/vda1 rw\n"
+	require.NoError(t, os.WriteFile(fpath, []byte(contents), 0o644))
+	assert.Equal(t, cid, readMountinfoContainerID(fpath))
+	assert.Empty(t, readMountinfoContainerID(path.Join(t.TempDir(), "missing")))
+}
+
 func TestReadContainerIDFromCgroup(t *testing.T) {
 	cid := "8c046cb0b72cd4c99f51b5591cd5b095967f58ee003710a45280c28ee1a9c7fa"
 	cgroupContents := "10:hugetlb:/kubepods/burstable/podfd52ef25-a87d-11e9-9423-0800271a638e/" + cid
@@ -262,3 +303,60 @@ func TestGetCgroupInode(t *testing.T) {
 		})
 	}
 }
+
+func TestReadCPUQuota(t *testing.T) {
+	writeFile := func(t *testing.T, name, content string) {
+		require.NoError(t, os.MkdirAll(path.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+	}
+
+	for _, tt := range []struct {
+		name  string
+		files map[string]string
+		quota float64
+		ok    bool
+	}{
+		{name: "none"},
+		{name: "v2", files: map[string]string{"cpu.max": "150000 100000\n"}, quota: 1.5, ok: true},
+		{name: "v2-max", files: map[string]string{"cpu.max": "max 100000\n"}},
+		{name: "v1", files: map[string]string{"cpu/cpu.cfs_quota_us": "200000\n", "cpu/cpu.cfs_period_us": "100000\n"}, quota: 2, ok: true},
+		{name: "v1-cpuacct", files: map[string]string{"cpu,cpuacct/cpu.cfs_quota_us": "50000\n", "cpu,cpuacct/cpu.cfs_period_us": "100000\n"}, quota: 0.5, ok: true},
+		{name: "v1-unlimited", files: map[string]string{"cpu/cpu.cfs_quota_us": "-1\n", "cpu/cpu.cfs_period_us": "100000\n"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, path.Join(dir, name), content)
+			}
+			quota, ok := readCPUQuota(dir)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.quota, quota)
+		})
+	}
+}
+
+func TestReadCgroupLimits(t *testing.T) {
+	for _, tt := range []struct {
+		name               string
+		files              map[string]string
+		quota, period, mem int64
+	}{
+		{name: "none"},
+		{name: "v2", files: map[string]string{"cpu.max": "150000 100000\n", "memory.max": "536870912\n"}, quota: 150000, period: 100000, mem: 536870912},
+		{name: "v2-max", files: map[string]string{"cpu.max": "max 100000\n", "memory.max": "max\n"}},
+		{name: "v1", files: map[string]string{"cpu/cpu.cfs_quota_us": "200000\n", "cpu/cpu.cfs_period_us": "100000\n", "memory/memory.limit_in_bytes": "1073741824\n"}, quota: 200000, period: 100000, mem: 1073741824},
+		{name: "v1-unlimited", files: map[string]string{"cpu/cpu.cfs_quota_us": "-1\n", "cpu/cpu.cfs_period_us": "100000\n", "memory/memory.limit_in_bytes": "9223372036854771712\n"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				require.NoError(t, os.MkdirAll(path.Dir(path.Join(dir, name)), 0o755))
+				require.NoError(t, os.WriteFile(path.Join(dir, name), []byte(content), 0o644))
+			}
+			quota, period, mem := readCgroupLimits(dir)
+			assert.Equal(t, tt.quota, quota)
+			assert.Equal(t, tt.period, period)
+			assert.Equal(t, tt.mem, mem)
+		})
+	}
+}
@@ -17,3 +17,15 @@ func ContainerID() string {
 func EntityID() string {
 	return ""
 }
+
+// CPUQuota returns the CPU quota of the cgroup of the process, in CPUs, and whether the process
+// has one. Cgroups are only supported on Linux.
+func CPUQuota() (float64, bool) {
+	return 0, false
+}
+
+// CgroupLimits returns the CPU quota and period, in microseconds, and the memory limit, in bytes,
+// of the cgroup of the process. Cgroups are only supported on Linux.
+func CgroupLimits() (cpuQuotaUs, cpuPeriodUs, memoryLimit int64) {
+	return 0, 0, 0
+}
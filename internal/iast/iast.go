@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package iast reports the code vulnerabilities detected at runtime by the integrations, such as
+// the usage of weak hashing algorithms or ciphers, to Datadog Code Security. The vulnerabilities
+// are reported on the root span of the trace of the context they are detected in.
+package iast
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// EnvEnabled is the name of the env var used to enable the detection of code vulnerabilities.
+// It is disabled by default.
+const EnvEnabled = "DD_IAST_ENABLED"
+
+const (
+	// tagJSON holds the vulnerabilities detected in a trace, as a JSON object.
+	tagJSON = "_dd.iast.json"
+	// tagEnabled is set on the root spans of the traces vulnerabilities were reported for.
+	tagEnabled = "_dd.iast.enabled"
+)
+
+// maxLocations is the maximum number of distinct code locations vulnerabilities are reported
+// for by the process. Vulnerabilities detected at new locations past this limit are dropped.
+const maxLocations = 1000
+
+// Types of the vulnerabilities.
+const (
+	// WeakHash is the usage of a hashing algorithm which is vulnerable to collisions, such as
+	// MD5 or SHA-1.
+	WeakHash = "WEAK_HASH"
+	// WeakCipher is the usage of a cipher which is vulnerable to attacks, such as DES or RC4.
+	WeakCipher = "WEAK_CIPHER"
+)
+
+// Enabled returns whether the detection of code vulnerabilities is enabled with DD_IAST_ENABLED.
+func Enabled() bool {
+	return internal.BoolEnv(EnvEnabled, false)
+}
+
+type (
+	vulnerability struct {
+		Type     string   `json:"type"`
+		Evidence evidence `json:"evidence"`
+		Location location `json:"location"`
+		Hash     uint32   `json:"hash"`
+	}
+
+	evidence struct {
+		Value string `json:"value"`
+	}
+
+	location struct {
+		SpanID uint64 `json:"spanId"`
+		Path   string `json:"path"`
+		Line   int    `json:"line"`
+	}
+
+	report struct {
+		Vulnerabilities []vulnerability `json:"vulnerabilities"`
+	}
+)
+
+var (
+	mu sync.Mutex
+	// reported holds the code locations vulnerabilities were already reported for, so that a
+	// vulnerability is reported once per location.
+	reported = make(map[uint32]struct{})
+	// reports holds the vulnerabilities reported by root span ID, bounded by maxLocations.
+	reports = make(map[uint64]*report)
+)
+
+// Report reports a vulnerability of the given type, e.g. WeakHash, on the root span of the trace
+// of ctx. value is the evidence making the code vulnerable, e.g. the name of the weak hashing
+// algorithm. The location of the vulnerability is the caller of the function calling Report,
+// skip being the number of additional stack frames to skip. Nothing is reported when the
+// detection of vulnerabilities is disabled, when ctx holds no span, or when a vulnerability was
+// already reported for that location.
+func Report(ctx context.Context, typ, value string, skip int) {
+	if !Enabled() {
+		return
+	}
+	span, ok := tracer.SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	root := span.Root()
+	if root == nil {
+		root = span
+	}
+	_, file, line, ok := runtime.Caller(skip + 2)
+	if !ok {
+		return
+	}
+	h := fnv.New32a()
+	h.Write([]byte(typ + ":" + file + ":" + strconv.Itoa(line)))
+	hash := h.Sum32()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := reported[hash]; ok {
+		return
+	}
+	if len(reported) >= maxLocations {
+		log.Debug("iast: too many vulnerable locations, dropping the %s vulnerability at %s:%d", typ, file, line)
+		return
+	}
+	reported[hash] = struct{}{}
+	rootID := root.Context().SpanID()
+	r, ok := reports[rootID]
+	if !ok {
+		r = &report{}
+		reports[rootID] = r
+	}
+	r.Vulnerabilities = append(r.Vulnerabilities, vulnerability{
+		Type:     typ,
+		Evidence: evidence{Value: value},
+		Location: location{SpanID: span.Context().SpanID(), Path: file, Line: line},
+		Hash:     hash,
+	})
+	data, err := json.Marshal(r)
+	if err != nil {
+		log.Debug("iast: could not marshal the vulnerabilities: %s", err.Error())
+		return
+	}
+	root.SetTag(tagJSON, string(data))
+	root.SetTag(tagEnabled, 1)
+	root.SetTag(ext.ManualKeep, true)
+}
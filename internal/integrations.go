@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package internal
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// disabledIntegrations holds the names of the integrations disabled at runtime,
+	// as found in the component tag of their spans.
+	disabledIntegrations sync.Map // map[string]struct{}
+
+	// integrationsDisabled reports whether any integration was ever disabled, to
+	// avoid looking up the registry otherwise.
+	integrationsDisabled atomic.Bool
+)
+
+// SetIntegrationEnabled enables or disables the integration with the given
+// component name. Spans of disabled integrations are not created.
+func SetIntegrationEnabled(component string, enabled bool) {
+	if enabled {
+		disabledIntegrations.Delete(component)
+		return
+	}
+	integrationsDisabled.Store(true)
+	disabledIntegrations.Store(component, struct{}{})
+}
+
+// IntegrationEnabled reports whether the integration with the given component
+// name is enabled. Integrations are enabled unless disabled with SetIntegrationEnabled.
+func IntegrationEnabled(component string) bool {
+	if !integrationsDisabled.Load() {
+		return true
+	}
+	_, disabled := disabledIntegrations.Load(component)
+	return !disabled
+}
@@ -0,0 +1,24 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntegrationEnabled(t *testing.T) {
+	assert.True(t, IntegrationEnabled("gin-gonic/gin"))
+
+	SetIntegrationEnabled("gin-gonic/gin", false)
+	defer SetIntegrationEnabled("gin-gonic/gin", true)
+	assert.False(t, IntegrationEnabled("gin-gonic/gin"))
+	assert.True(t, IntegrationEnabled("net/http"))
+
+	SetIntegrationEnabled("gin-gonic/gin", true)
+	assert.True(t, IntegrationEnabled("gin-gonic/gin"))
+}
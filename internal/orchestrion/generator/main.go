@@ -36,7 +36,10 @@ import (
 	_ "embed" // For go:embed
 )
 
-const orchestrionToolGo = "orchestrion.tool.go"
+const (
+	orchestrionToolGo = "orchestrion.tool.go"
+	integrationsGo    = "integrations.go"
+)
 
 var (
 	//go:embed orchestrion.tool.go.tmpl
@@ -46,6 +49,10 @@ var (
 	//go:embed go.mod.tmpl
 	goModTemplateText string
 	goModTemplate     = template.Must(template.New("go.mod").Parse(goModTemplateText))
+
+	//go:embed integrations.go.tmpl
+	integrationsTemplateText string
+	integrationsTemplate     = template.Must(template.New(integrationsGo).Parse(integrationsTemplateText))
 )
 
 func main() {
@@ -165,11 +172,41 @@ func generateRootConfig(rootDir string, orchestrionLatestVersion string) (map[st
 		return nil, fmt.Errorf("go mod tidy: %w", err)
 	}
 
+	if err := generateIntegrations(rootDir, paths); err != nil {
+		return nil, err
+	}
+
 	// Make sure this is present in the modules map, as it's not a natural part of it...
 	modules["github.com/DataDog/dd-trace-go/orchestrion/all/v2"] = pkgDir
 	return modules, nil
 }
 
+// generateIntegrations writes the list of the packages providing compile-time
+// instrumentation to the `integrations.go` file of the `./orchestrion/report`
+// command, which uses it to tell which integrations could have been applied to
+// a binary.
+func generateIntegrations(rootDir string, paths []string) error {
+	var integrations []string
+	for _, path := range paths {
+		if strings.HasPrefix(path, "github.com/DataDog/dd-trace-go/contrib/") {
+			integrations = append(integrations, path)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := integrationsTemplate.Execute(&buf, integrations); err != nil {
+		return fmt.Errorf("rendering Go code template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting "+integrationsGo+": %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(rootDir, "orchestrion", "report", integrationsGo), src, 0o644); err != nil {
+		return fmt.Errorf("writing "+integrationsGo+" file: %w", err)
+	}
+	return nil
+}
+
 func getLanguageLevel(dir string) (string, error) {
 	cmd := exec.Command("go", "mod", "edit", "-json")
 	cmd.Dir = dir
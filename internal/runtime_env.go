@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package internal
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+)
+
+// Names of the tags describing the runtime environment of the process, see RuntimeEnvTags.
+const (
+	// RuntimeEnvGOMAXPROCS is the GOMAXPROCS of the process.
+	RuntimeEnvGOMAXPROCS = "go.gomaxprocs"
+	// RuntimeEnvGOGC is the GOGC of the process, -1 when the GC is off.
+	RuntimeEnvGOGC = "go.gogc"
+	// RuntimeEnvGOMEMLIMIT is the GOMEMLIMIT of the process, in bytes.
+	RuntimeEnvGOMEMLIMIT = "go.gomemlimit"
+	// RuntimeEnvCgroupCPUQuota is the CPU quota of the cgroup of the process, in microseconds.
+	RuntimeEnvCgroupCPUQuota = "cgroup.cpu_quota_us"
+	// RuntimeEnvCgroupCPUPeriod is the CPU period of the cgroup of the process, in microseconds.
+	RuntimeEnvCgroupCPUPeriod = "cgroup.cpu_period_us"
+	// RuntimeEnvCgroupMemoryLimit is the memory limit of the cgroup of the process, in bytes.
+	RuntimeEnvCgroupMemoryLimit = "cgroup.memory_limit_bytes"
+)
+
+// RuntimeEnvTags returns the settings of the Go runtime and the limits of the cgroup of the
+// process, to tag the traces and the profiles with, so that the latency caused by CPU throttling
+// or GC pressure can be diagnosed: GOMAXPROCS and GOGC, GOMEMLIMIT when set, and the CPU quota
+// and period and the memory limit of the cgroup when it has limits.
+func RuntimeEnvTags() map[string]float64 {
+	tags := map[string]float64{
+		RuntimeEnvGOMAXPROCS: float64(runtime.GOMAXPROCS(0)),
+	}
+	samples := []metrics.Sample{{Name: "/gc/gogc:percent"}, {Name: "/gc/gomemlimit:bytes"}}
+	metrics.Read(samples)
+	if v := samples[0].Value; v.Kind() == metrics.KindUint64 {
+		// GOGC=off is reported as the uint64 value of -1.
+		tags[RuntimeEnvGOGC] = float64(int64(v.Uint64()))
+	}
+	if v := samples[1].Value; v.Kind() == metrics.KindUint64 && v.Uint64() != math.MaxInt64 {
+		tags[RuntimeEnvGOMEMLIMIT] = float64(v.Uint64())
+	}
+	quota, period, memory := CgroupLimits()
+	if quota > 0 {
+		tags[RuntimeEnvCgroupCPUQuota] = float64(quota)
+		tags[RuntimeEnvCgroupCPUPeriod] = float64(period)
+	}
+	if memory > 0 {
+		tags[RuntimeEnvCgroupMemoryLimit] = float64(memory)
+	}
+	return tags
+}
@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package internal
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeEnvTags(t *testing.T) {
+	defer debug.SetGCPercent(debug.SetGCPercent(50))
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(1 << 30))
+
+	tags := RuntimeEnvTags()
+	assert.Equal(t, float64(runtime.GOMAXPROCS(0)), tags[RuntimeEnvGOMAXPROCS])
+	assert.Equal(t, float64(50), tags[RuntimeEnvGOGC])
+	assert.Equal(t, float64(1<<30), tags[RuntimeEnvGOMEMLIMIT])
+
+	debug.SetGCPercent(-1)
+	assert.Equal(t, float64(-1), RuntimeEnvTags()[RuntimeEnvGOGC])
+}
@@ -36,3 +36,35 @@ func NewStatsdClient(addr string, globalTags []string) (StatsdClient, error) {
 	}
 	return client, nil
 }
+
+// WrapStatsdClient adapts a user-provided statsd client to a StatsdClient.
+// The caller keeps ownership of the client: calling Close on the returned
+// value only flushes it.
+func WrapStatsdClient(client statsd.ClientInterface) StatsdClient {
+	return &userStatsdClient{client}
+}
+
+// userStatsdClient implements StatsdClient on top of a statsd.ClientInterface.
+type userStatsdClient struct {
+	statsd.ClientInterface
+}
+
+// DistributionSamples forwards the samples as-is when the wrapped client supports
+// it, and falls back to one Distribution call per sample otherwise.
+func (c *userStatsdClient) DistributionSamples(name string, values []float64, tags []string, rate float64) error {
+	if direct, ok := c.ClientInterface.(statsd.ClientDirectInterface); ok {
+		return direct.DistributionSamples(name, values, tags, rate)
+	}
+	var err error
+	for _, v := range values {
+		if e := c.Distribution(name, v, tags, rate); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Close flushes the wrapped client without closing it.
+func (c *userStatsdClient) Close() error {
+	return c.Flush()
+}
@@ -29,16 +29,17 @@ const (
 var _ internal.StatsdClient = &TestStatsdClient{}
 
 type TestStatsdClient struct {
-	mu          sync.RWMutex
-	gaugeCalls  []TestStatsdCall
-	incrCalls   []TestStatsdCall
-	countCalls  []TestStatsdCall
-	timingCalls []TestStatsdCall
-	counts      map[string]int64
-	tags        []string
-	n           int
-	closed      bool
-	flushed     int
+	mu                      sync.RWMutex
+	gaugeCalls              []TestStatsdCall
+	gaugeWithTimestampCalls []TestStatsdCall
+	incrCalls               []TestStatsdCall
+	countCalls              []TestStatsdCall
+	timingCalls             []TestStatsdCall
+	counts                  map[string]int64
+	tags                    []string
+	n                       int
+	closed                  bool
+	flushed                 int
 }
 
 type TestStatsdCall struct {
@@ -140,6 +141,8 @@ func (tg *TestStatsdClient) addMetric(ct callType, tags []string, c TestStatsdCa
 	switch ct {
 	case callTypeGauge:
 		tg.gaugeCalls = append(tg.gaugeCalls, c)
+	case callTypeGaugeWithTimestamp:
+		tg.gaugeWithTimestampCalls = append(tg.gaugeWithTimestampCalls, c)
 	case callTypeIncr:
 		tg.incrCalls = append(tg.incrCalls, c)
 	case callTypeCount:
@@ -172,6 +175,14 @@ func (tg *TestStatsdClient) GaugeCalls() []TestStatsdCall {
 	return c
 }
 
+func (tg *TestStatsdClient) GaugeWithTimestampCalls() []TestStatsdCall {
+	tg.mu.RLock()
+	defer tg.mu.RUnlock()
+	c := make([]TestStatsdCall, len(tg.gaugeWithTimestampCalls))
+	copy(c, tg.gaugeWithTimestampCalls)
+	return c
+}
+
 func (tg *TestStatsdClient) IncrCalls() []TestStatsdCall {
 	tg.mu.RLock()
 	defer tg.mu.RUnlock()
@@ -307,6 +318,7 @@ func (tg *TestStatsdClient) Reset() {
 	tg.mu.Lock()
 	defer tg.mu.Unlock()
 	tg.gaugeCalls = tg.gaugeCalls[:0]
+	tg.gaugeWithTimestampCalls = tg.gaugeWithTimestampCalls[:0]
 	tg.incrCalls = tg.incrCalls[:0]
 	tg.countCalls = tg.countCalls[:0]
 	tg.timingCalls = tg.timingCalls[:0]
@@ -6,6 +6,7 @@
 package telemetry
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	globalinternal "github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/apikey"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 	"github.com/DataDog/dd-trace-go/v2/internal/telemetry/internal"
 )
@@ -70,6 +72,10 @@ type ClientConfig struct {
 	// APIKey is the API key to use for sending telemetry to the agentless endpoint. (using DD_API_KEY env var by default)
 	APIKey string
 
+	// APIKeyResolver resolves the API key to use for sending telemetry to the agentless endpoint, taking precedence over APIKey.
+	// The key is resolved again when it is rejected by the endpoint.
+	APIKeyResolver *apikey.Resolver
+
 	// EarlyFlushPayloadSize is the size of the payload that will trigger an early flush.
 	// This is necessary because backend won't allow bodies larger than 5MB.
 	// The default value here will be 2MB to take into account the large inaccuracy in estimating the size of bodies
@@ -267,7 +273,16 @@ func newWriterConfig(config ClientConfig, tracerConfig internal.TracerConfig) (i
 		endpoints = append(endpoints, request)
 	}
 
-	if config.AgentlessURL != "" && config.APIKey != "" {
+	if config.AgentlessURL != "" && config.APIKeyResolver != nil {
+		// The writer sets the DD-API-KEY header with the resolver found in the context of the request.
+		ctx := apikey.NewContext(context.Background(), config.APIKeyResolver)
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, config.AgentlessURL, nil)
+		if err != nil {
+			return internal.WriterConfig{}, fmt.Errorf("failed to create request: %v", err)
+		}
+
+		endpoints = append(endpoints, request)
+	} else if config.AgentlessURL != "" && config.APIKey != "" {
 		request, err := http.NewRequest(http.MethodPost, config.AgentlessURL, nil)
 		if err != nil {
 			return internal.WriterConfig{}, fmt.Errorf("failed to create request: %v", err)
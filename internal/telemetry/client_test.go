@@ -545,6 +545,44 @@ func TestClientFlush(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "extended-heartbeat-many-dependencies",
+			clientConfig: ClientConfig{
+				ExtendedHeartbeatInterval: time.Nanosecond,
+				DependencyLoader: func() (*debug.BuildInfo, bool) {
+					modules := make([]*debug.Module, 2001)
+					for i := range modules {
+						modules[i] = &debug.Module{
+							Path:    fmt.Sprintf("test-%d", i),
+							Version: fmt.Sprintf("v%d.0.0", i),
+						}
+					}
+					return &debug.BuildInfo{
+						Deps: modules,
+					}, true
+				},
+			},
+			when: func(c *client) {
+				// The dependencies are sent over two flushes
+				time.Sleep(time.Microsecond)
+				runtime.Gosched()
+				c.Flush()
+				time.Sleep(time.Microsecond)
+				runtime.Gosched()
+			},
+			expect: func(t *testing.T, payloads []transport.Payload) {
+				require.Len(t, payloads, 2)
+				payload := payloads[1]
+				require.IsType(t, transport.MessageBatch{}, payload)
+				batch := payload.(transport.MessageBatch)
+				require.Len(t, batch, 2)
+				assert.Equal(t, transport.RequestTypeAppDependenciesLoaded, batch[0].RequestType)
+				assert.Equal(t, transport.RequestTypeAppExtendedHeartBeat, batch[1].RequestType)
+				deps := batch[1].Payload.(transport.AppExtendedHeartbeat).Dependencies
+				require.Len(t, deps, 2001)
+				assert.Equal(t, "test-2000", deps[2000].Name)
+			},
+		},
 		{
 			name: "single-log-debug",
 			when: func(c *client) {
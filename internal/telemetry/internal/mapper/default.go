@@ -76,9 +76,8 @@ func (t *heartbeatEnricher) Transform(payloads []transport.Payload) ([]transport
 			// Should be sent only once anyway
 			t.extendedHeartbeat.Configuration = payload.Configuration
 		case transport.AppDependenciesLoaded:
-			if t.extendedHeartbeat.Dependencies == nil {
-				t.extendedHeartbeat.Dependencies = payload.Dependencies
-			}
+			// The dependencies are loaded once but may be sent over multiple payloads
+			t.extendedHeartbeat.Dependencies = append(t.extendedHeartbeat.Dependencies, payload.Dependencies...)
 		case transport.AppIntegrationChange:
 			// The number of integrations should be small enough so we can just append to the list
 			t.extendedHeartbeat.Integrations = append(t.extendedHeartbeat.Integrations, payload.Integrations...)
@@ -6,7 +6,6 @@
 package internal
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,6 +18,7 @@ import (
 	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/apikey"
 	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
 	"github.com/DataDog/dd-trace-go/v2/internal/hostname"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
@@ -158,7 +158,7 @@ func NewWriter(config WriterConfig) (Writer, error) {
 // Headers necessary are described here:
 // https://github.com/DataDog/instrumentation-telemetry-api-docs/blob/cf17b41a30fbf31d54e2cfbfc983875d58b02fe1/GeneratedDocumentation/ApiDocs/v2/overview.md#required-http-headers
 func preBakeRequest(body *transport.Body, endpoint *http.Request) *http.Request {
-	clonedEndpoint := endpoint.Clone(context.Background())
+	clonedEndpoint := endpoint.Clone(endpoint.Context())
 	if clonedEndpoint.Header == nil {
 		clonedEndpoint.Header = make(http.Header, 11)
 	}
@@ -175,6 +175,7 @@ func preBakeRequest(body *transport.Body, endpoint *http.Request) *http.Request
 		"DD-Agent-Install-Time":      globalconfig.InstrumentationInstallTime(),
 		"Datadog-Container-ID":       internal.ContainerID(),
 		"Datadog-Entity-ID":          internal.EntityID(),
+		"Datadog-External-Env":       internal.ExternalEnvironment(),
 		// TODO: add support for Cloud provider/resource-type/resource-id headers in another PR and package
 		// Described here: https://github.com/DataDog/instrumentation-telemetry-api-docs/blob/cf17b41a30fbf31d54e2cfbfc983875d58b02fe1/GeneratedDocumentation/ApiDocs/v2/overview.md#setting-the-serverless-telemetry-headers
 	} {
@@ -203,7 +204,7 @@ func (w *writer) setPayloadToBody(payload transport.Payload) {
 
 // newRequest creates a new http.Request with the given payload and the necessary headers.
 func (w *writer) newRequest(endpoint *http.Request, requestType transport.RequestType) *http.Request {
-	request := endpoint.Clone(context.Background())
+	request := endpoint.Clone(endpoint.Context())
 	request.Header.Set("DD-Telemetry-Request-Type", string(requestType))
 
 	pipeReader, pipeWriter := io.Pipe()
@@ -271,7 +272,15 @@ func (w *writer) Flush(payload transport.Payload) ([]EndpointRequestResult, erro
 		)
 
 		request.Body = sumReaderCloser
-		response, err := w.httpClient.Do(request)
+		var response *http.Response
+		var err error
+		if resolver, ok := apikey.FromContext(request.Context()); ok {
+			// The body is streamed, so the request isn't sent again when the key is rejected,
+			// but the next flush uses the key resolved again.
+			response, err = resolver.Do(w.httpClient, request)
+		} else {
+			response, err = w.httpClient.Do(request)
+		}
 		if err != nil {
 			results = append(results, EndpointRequestResult{Error: err})
 			continue
@@ -6,6 +6,7 @@
 package internal
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -17,6 +18,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/DataDog/dd-trace-go/v2/internal/apikey"
 	"github.com/DataDog/dd-trace-go/v2/internal/processtags"
 	"github.com/DataDog/dd-trace-go/v2/internal/telemetry/internal/transport"
 )
@@ -185,6 +187,53 @@ func TestWriter_Flush_Failure(t *testing.T) {
 	assert.True(t, payloadReceived)
 }
 
+func TestWriter_Flush_APIKeyResolver(t *testing.T) {
+	config := WriterConfig{
+		TracerConfig: TracerConfig{
+			Service: "test-service",
+			Env:     "test-env",
+			Version: "1.0.0",
+		},
+	}
+
+	payload := testPayload{
+		RequestTypeValue: "test",
+		marshalJSON: func() ([]byte, error) {
+			return []byte(`{"request_type":"test"}`), nil
+		},
+	}
+
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("DD-API-KEY"))
+		if r.Header.Get("DD-API-KEY") != "rotated-key" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	resolved := []string{"revoked-key", "rotated-key"}
+	resolver := apikey.NewResolver(func(context.Context) (string, error) {
+		key := resolved[0]
+		resolved = resolved[1:]
+		return key, nil
+	})
+	req, err := http.NewRequestWithContext(apikey.NewContext(context.Background(), resolver), http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+
+	config.Endpoints = append(config.Endpoints, req)
+	writer, _ := NewWriter(config)
+
+	results, err := writer.Flush(&payload)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, results[0].StatusCode)
+
+	// the next flush resolves the key again.
+	_, err = writer.Flush(&payload)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"revoked-key", "rotated-key"}, keys)
+}
+
 func TestWriter_Flush_MultipleEndpoints(t *testing.T) {
 	config := WriterConfig{
 		TracerConfig: TracerConfig{
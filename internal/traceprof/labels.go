@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package traceprof
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+var spanTagLabels atomic.Pointer[[]string]
+
+// SetSpanTagLabels sets the keys of the span tags applied by the tracer as
+// pprof labels, or removes them when keys is empty. It is used by the
+// profiler to slice the profiles by span tags.
+func SetSpanTagLabels(keys []string) {
+	if len(keys) == 0 {
+		spanTagLabels.Store(nil)
+		return
+	}
+	keys = slices.Clone(keys)
+	spanTagLabels.Store(&keys)
+}
+
+// SpanTagLabels returns the keys of the span tags to apply as pprof labels.
+// The returned slice must not be modified.
+func SpanTagLabels() []string {
+	if keys := spanTagLabels.Load(); keys != nil {
+		return *keys
+	}
+	return nil
+}
+
+// IsSpanTagLabel reports whether the span tag key is applied as a pprof
+// label.
+func IsSpanTagLabel(key string) bool {
+	return slices.Contains(SpanTagLabels(), key)
+}
@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package traceprof
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// EndpointLatencyObserver is notified by the tracer of the latency of the
+// endpoints, i.e. of the duration of the local root spans, along with the ID
+// of the local root span.
+type EndpointLatencyObserver func(endpoint string, localRootSpanID uint64, latency time.Duration)
+
+var endpointLatencyObserver atomic.Pointer[EndpointLatencyObserver]
+
+// SetEndpointLatencyObserver sets the observer of the latency of the
+// endpoints, or removes it when o is nil. It is used by the profiler to
+// capture execution traces around latency spikes.
+func SetEndpointLatencyObserver(o EndpointLatencyObserver) {
+	if o == nil {
+		endpointLatencyObserver.Store(nil)
+		return
+	}
+	endpointLatencyObserver.Store(&o)
+}
+
+// ObserveEndpointLatency notifies the observer set with
+// SetEndpointLatencyObserver, if any, of the latency of an endpoint. The
+// observer is called synchronously and must not block.
+func ObserveEndpointLatency(endpoint string, localRootSpanID uint64, latency time.Duration) {
+	if o := endpointLatencyObserver.Load(); o != nil {
+		(*o)(endpoint, localRootSpanID, latency)
+	}
+}
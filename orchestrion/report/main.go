@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Command report analyzes a Go binary and reports which integrations of
+// dd-trace-go were applied to it at compile time by orchestrion, and which
+// ones were missed and why, so that adopting compile-time instrumentation
+// isn't guesswork. The analysis relies on the build information embedded in
+// the binary, i.e. the modules it was built with.
+//
+// Usage:
+//
+//	go run github.com/DataDog/dd-trace-go/v2/orchestrion/report [-json] <binary>
+//
+// The integrations whose library is linked in the binary are reported with
+// one of the following statuses:
+//
+//   - instrumented: the integration was applied by orchestrion.
+//   - manual: the integration is linked in the binary but wasn't applied by
+//     orchestrion, it is used through its API.
+//   - missed: the library is linked in the binary but its integration isn't.
+//
+// The command exits with status 1 when the binary can't be analyzed, and with
+// status 2 when an integration was missed.
+package main
+
+import (
+	"cmp"
+	"debug/buildinfo"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-json] <binary>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	info, err := buildinfo.ReadFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reading the build information of %s: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+	r := analyze(info, instrumentation.GetPackages(), orchestrionPackages)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(r)
+	} else {
+		err = r.write(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "writing the report: %v\n", err)
+		os.Exit(1)
+	}
+	if slices.ContainsFunc(r.Integrations, func(ir integrationReport) bool { return ir.Status == statusMissed }) {
+		os.Exit(2)
+	}
+}
+
+// write writes r to w in a human-readable format.
+func (r *report) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Binary:\t%s\n", r.Path)
+	fmt.Fprintf(tw, "Go:\t%s\n", r.GoVersion)
+	fmt.Fprintf(tw, "Tracer:\t%s\n", cmp.Or(r.TracerVersion, "not linked"))
+	fmt.Fprintf(tw, "Orchestrion:\t%s\n", cmp.Or(r.OrchestrionVersion, "not used"))
+	if len(r.BuildTags) > 0 {
+		fmt.Fprintf(tw, "Build tags:\t%s\n", strings.Join(r.BuildTags, ","))
+	}
+	fmt.Fprintln(tw)
+	if len(r.Integrations) == 0 {
+		fmt.Fprintln(tw, "No library with an integration is linked in the binary.")
+		return tw.Flush()
+	}
+	fmt.Fprintln(tw, "INTEGRATION\tLIBRARY\tSTATUS\tREASON")
+	for _, ir := range r.Integrations {
+		lib := ir.Library
+		if ir.LibraryVersion != "" {
+			lib += "@" + ir.LibraryVersion
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ir.Integration, lib, ir.Status, ir.Reason)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,163 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package main
+
+import (
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+const (
+	tracerModule      = "github.com/DataDog/dd-trace-go/v2"
+	orchestrionModule = "github.com/DataDog/orchestrion"
+	contribPrefix     = "github.com/DataDog/dd-trace-go/contrib/"
+)
+
+// status is the instrumentation status of an integration in a binary.
+type status string
+
+const (
+	// statusInstrumented is the status of the integrations applied at
+	// compile time by orchestrion.
+	statusInstrumented status = "instrumented"
+	// statusManual is the status of the integrations linked in the binary
+	// but not applied by orchestrion, i.e. used through their API.
+	statusManual status = "manual"
+	// statusMissed is the status of the integrations whose library is
+	// linked in the binary but which aren't.
+	statusMissed status = "missed"
+)
+
+// integrationReport is the instrumentation status of an integration in a binary.
+type integrationReport struct {
+	Integration    string `json:"integration"`
+	Library        string `json:"library"`
+	LibraryVersion string `json:"library_version,omitempty"`
+	Module         string `json:"module"`
+	ModuleVersion  string `json:"module_version,omitempty"`
+	Status         status `json:"status"`
+	// Reason explains why the integration isn't instrumented, or
+	// what may prevent it from working properly.
+	Reason string `json:"reason,omitempty"`
+}
+
+// report is the compile-time instrumentation coverage of a binary.
+type report struct {
+	Path               string              `json:"path"`
+	GoVersion          string              `json:"go_version"`
+	OrchestrionVersion string              `json:"orchestrion_version,omitempty"`
+	TracerVersion      string              `json:"tracer_version,omitempty"`
+	BuildTags          []string            `json:"build_tags,omitempty"`
+	Integrations       []integrationReport `json:"integrations"`
+}
+
+// analyze returns the compile-time instrumentation coverage of the binary of
+// the given build information, for the given integrations. orchestrionPkgs
+// are the packages of the integrations providing compile-time
+// instrumentation.
+//
+// Only the integrations whose library, or whose package, is linked in the
+// binary are reported. The usage of the integrations of standard library
+// packages can't be known from the build information, so they are reported
+// only when their package is linked.
+func analyze(info *debug.BuildInfo, pkgs map[instrumentation.Package]instrumentation.PackageInfo, orchestrionPkgs []string) report {
+	r := report{
+		Path:         info.Path,
+		GoVersion:    info.GoVersion,
+		Integrations: []integrationReport{},
+	}
+	deps := make(map[string]*debug.Module, len(info.Deps)+1)
+	deps[info.Main.Path] = &info.Main
+	for _, d := range info.Deps {
+		if d.Replace != nil && d.Replace.Version != "" {
+			d = &debug.Module{Path: d.Path, Version: d.Replace.Version}
+		}
+		deps[d.Path] = d
+	}
+	if m, ok := deps[orchestrionModule]; ok {
+		r.OrchestrionVersion = m.Version
+	}
+	if m, ok := deps[tracerModule]; ok {
+		r.TracerVersion = m.Version
+	}
+	for _, s := range info.Settings {
+		if s.Key == "-tags" && s.Value != "" {
+			r.BuildTags = strings.Split(s.Value, ",")
+		}
+	}
+
+	for pkg, pkgInfo := range pkgs {
+		ir := integrationReport{
+			Integration: string(pkg),
+			Library:     pkgInfo.TracedPackage,
+			Module:      contribPrefix + string(pkg) + "/v2",
+		}
+		if pkgInfo.IsStdLib {
+			ir.LibraryVersion = info.GoVersion
+		} else if lib := findModule(deps, pkgInfo.TracedPackage); lib != nil {
+			ir.LibraryVersion = lib.Version
+		}
+		contrib, linked := deps[ir.Module]
+		if !linked && (pkgInfo.IsStdLib || ir.LibraryVersion == "") {
+			// The library isn't used by the binary, or we can't know.
+			continue
+		}
+		supported := slices.ContainsFunc(orchestrionPkgs, func(p string) bool {
+			return p == ir.Module || strings.HasPrefix(p, ir.Module+"/")
+		})
+		switch {
+		case linked:
+			ir.ModuleVersion = contrib.Version
+			switch {
+			case !supported:
+				ir.Status = statusManual
+				ir.Reason = "no compile-time instrumentation is available for this integration"
+			case r.OrchestrionVersion == "":
+				ir.Status = statusManual
+				ir.Reason = "the binary wasn't built with orchestrion"
+			default:
+				ir.Status = statusInstrumented
+			}
+			if ir.ModuleVersion != "" && r.TracerVersion != "" && ir.ModuleVersion != r.TracerVersion {
+				mismatch := "version mismatch: the integration is at " + ir.ModuleVersion + " but the tracer at " + r.TracerVersion
+				ir.Reason = strings.TrimPrefix(ir.Reason+"; "+mismatch, "; ")
+			}
+		case !supported:
+			ir.Status = statusMissed
+			ir.Reason = "no compile-time instrumentation is available for this integration, it must be used manually"
+		case r.OrchestrionVersion == "":
+			ir.Status = statusMissed
+			ir.Reason = "the binary wasn't built with orchestrion"
+		default:
+			ir.Status = statusMissed
+			ir.Reason = "the integration isn't part of the build, import " + ir.Module + " in orchestrion.tool.go or check the build tags"
+		}
+		r.Integrations = append(r.Integrations, ir)
+	}
+	slices.SortFunc(r.Integrations, func(a, b integrationReport) int {
+		return strings.Compare(a.Integration, b.Integration)
+	})
+	return r
+}
+
+// findModule returns the module of deps providing the package or module
+// path, or nil if none does.
+func findModule(deps map[string]*debug.Module, path string) *debug.Module {
+	for p := path; ; {
+		if m, ok := deps[p]; ok {
+			return m
+		}
+		i := strings.LastIndexByte(p, '/')
+		if i < 0 {
+			break
+		}
+		p = p[:i]
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package main
+
+import (
+	"bytes"
+	"runtime/debug"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	pkgs := map[instrumentation.Package]instrumentation.PackageInfo{
+		"net/http":           {TracedPackage: "net/http", IsStdLib: true},
+		"database/sql":       {TracedPackage: "database/sql", IsStdLib: true},
+		"gin-gonic/gin":      {TracedPackage: "github.com/gin-gonic/gin"},
+		"go-redis/redis.v8":  {TracedPackage: "github.com/go-redis/redis/v8"},
+		"gomodule/redigo":    {TracedPackage: "github.com/gomodule/redigo"},
+		"segmentio/kafka-go": {TracedPackage: "github.com/segmentio/kafka-go"},
+		"labstack/echo.v4":   {TracedPackage: "github.com/labstack/echo/v4"},
+	}
+	orchestrionPkgs := []string{
+		"github.com/DataDog/dd-trace-go/contrib/net/http/v2",
+		"github.com/DataDog/dd-trace-go/contrib/gin-gonic/gin/v2",
+		"github.com/DataDog/dd-trace-go/contrib/go-redis/redis.v8/v2",
+		"github.com/DataDog/dd-trace-go/contrib/segmentio/kafka-go/v2",
+	}
+	deps := []*debug.Module{
+		{Path: "github.com/DataDog/dd-trace-go/v2", Version: "v2.2.0"},
+		{Path: "github.com/DataDog/dd-trace-go/contrib/net/http/v2", Version: "v2.2.0"},
+		{Path: "github.com/DataDog/dd-trace-go/contrib/gin-gonic/gin/v2", Version: "v2.1.0"},
+		{Path: "github.com/DataDog/dd-trace-go/contrib/gomodule/redigo/v2", Version: "v2.2.0"},
+		{Path: "github.com/gin-gonic/gin", Version: "v1.10.0"},
+		{Path: "github.com/go-redis/redis/v8", Version: "v8.11.5"},
+		{Path: "github.com/gomodule/redigo", Version: "v1.9.2"},
+		{Path: "github.com/labstack/echo/v4", Version: "v4.13.3"},
+	}
+
+	t.Run("orchestrion", func(t *testing.T) {
+		info := &debug.BuildInfo{
+			GoVersion: "go1.24.0",
+			Path:      "example.com/app",
+			Main:      debug.Module{Path: "example.com/app", Version: "(devel)"},
+			Deps:      append(deps, &debug.Module{Path: "github.com/DataDog/orchestrion", Version: "v1.4.0"}),
+			Settings:  []debug.BuildSetting{{Key: "-tags", Value: "netgo,osusergo"}},
+		}
+		r := analyze(info, pkgs, orchestrionPkgs)
+		assert.Equal(t, "v1.4.0", r.OrchestrionVersion)
+		assert.Equal(t, "v2.2.0", r.TracerVersion)
+		assert.Equal(t, []string{"netgo", "osusergo"}, r.BuildTags)
+
+		require.Len(t, r.Integrations, 5)
+		assert.Equal(t, "gin-gonic/gin", r.Integrations[0].Integration)
+		assert.Equal(t, statusInstrumented, r.Integrations[0].Status)
+		assert.Equal(t, "v1.10.0", r.Integrations[0].LibraryVersion)
+		assert.Contains(t, r.Integrations[0].Reason, "version mismatch")
+		assert.Equal(t, "go-redis/redis.v8", r.Integrations[1].Integration)
+		assert.Equal(t, statusMissed, r.Integrations[1].Status)
+		assert.Contains(t, r.Integrations[1].Reason, "orchestrion.tool.go")
+		assert.Equal(t, "gomodule/redigo", r.Integrations[2].Integration)
+		assert.Equal(t, statusManual, r.Integrations[2].Status)
+		assert.Equal(t, "labstack/echo.v4", r.Integrations[3].Integration)
+		assert.Equal(t, statusMissed, r.Integrations[3].Status)
+		assert.Contains(t, r.Integrations[3].Reason, "no compile-time instrumentation")
+		assert.Equal(t, "net/http", r.Integrations[4].Integration)
+		assert.Equal(t, statusInstrumented, r.Integrations[4].Status)
+		assert.Equal(t, "go1.24.0", r.Integrations[4].LibraryVersion)
+		assert.Empty(t, r.Integrations[4].Reason)
+
+		var buf bytes.Buffer
+		require.NoError(t, r.write(&buf))
+		assert.Contains(t, buf.String(), "github.com/gin-gonic/gin@v1.10.0")
+	})
+
+	t.Run("no-orchestrion", func(t *testing.T) {
+		info := &debug.BuildInfo{
+			GoVersion: "go1.24.0",
+			Path:      "example.com/app",
+			Main:      debug.Module{Path: "example.com/app", Version: "(devel)"},
+			Deps:      deps,
+		}
+		r := analyze(info, pkgs, orchestrionPkgs)
+		assert.Empty(t, r.OrchestrionVersion)
+		require.Len(t, r.Integrations, 5)
+		assert.Equal(t, statusManual, r.Integrations[0].Status)
+		assert.Equal(t, statusMissed, r.Integrations[1].Status)
+		assert.Equal(t, "the binary wasn't built with orchestrion", r.Integrations[1].Reason)
+		assert.Equal(t, statusManual, r.Integrations[4].Status)
+	})
+}
@@ -16,6 +16,7 @@ import (
 
 	kgzip "github.com/klauspost/compress/gzip"
 	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -241,3 +242,20 @@ func BenchmarkRecompression(b *testing.B) {
 		})
 	}
 }
+
+func TestCompressionStrategy(t *testing.T) {
+	for pt := range profileTypes {
+		for _, isDelta := range []bool{false, true} {
+			in, out := compressionStrategy(pt, isDelta, "", false)
+			assert.Equal(t, inputCompression(pt, isDelta), in)
+			assert.Equal(t, legacyOutputCompression(pt, isDelta), out)
+
+			in, out = compressionStrategy(pt, isDelta, "", true)
+			assert.Equal(t, inputCompression(pt, isDelta), in)
+			assert.Equal(t, zstdCompression, out)
+
+			_, out = compressionStrategy(pt, isDelta, "gzip-6", true)
+			assert.Equal(t, gzip6Compression, out, "the debug settings take precedence")
+		}
+	}
+}
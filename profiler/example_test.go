@@ -6,6 +6,7 @@
 package profiler_test
 
 import (
+	"context"
 	"log"
 
 	"github.com/DataDog/dd-trace-go/v2/profiler"
@@ -25,3 +26,15 @@ func Example() {
 
 	// ...
 }
+
+// This example illustrates how to attribute the profiles of a queue consumer
+// to the queue it consumes, like the http requests traced by the tracer.
+func ExampleDoEndpoint() {
+	var messages <-chan string
+	for msg := range messages {
+		profiler.DoEndpoint(context.Background(), "orders-queue", func(ctx context.Context) {
+			// Process msg with ctx...
+			_ = msg
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+)
+
+const (
+	// flightRecorderFilename is the name of the execution traces captured by
+	// the flight recorder in the uploads.
+	flightRecorderFilename = "go.flightrecorder.trace"
+
+	// flightRecorderMinAge is the minimum duration of the execution traces
+	// kept by the flight recorder.
+	flightRecorderMinAge = 10 * time.Second
+
+	// flightRecorderCooldown is the minimum duration between two captures, to
+	// bound the overhead of the captures and the size of the uploads.
+	flightRecorderCooldown = time.Minute
+
+	// latencySpikeFactor is how many times higher than the average latency of
+	// an endpoint the latency of a request must be to be a latency spike.
+	latencySpikeFactor = 4
+	// minLatencySpike is the minimum latency of a latency spike.
+	minLatencySpike = 100 * time.Millisecond
+	// minLatencyObservations is the number of requests to an endpoint needed
+	// to know its average latency, before detecting latency spikes.
+	minLatencyObservations = 20
+	// latencyEWMAAlpha is the weight of the latest latency in the
+	// exponentially weighted moving average of the latency of an endpoint.
+	latencyEWMAAlpha = 0.1
+	// maxLatencyEndpoints bounds the number of endpoints whose latency is
+	// tracked.
+	maxLatencyEndpoints = 1000
+)
+
+// flightRecorder is the interface of runtime/trace.FlightRecorder, which is
+// only available as of Go 1.25, see newFlightRecorder.
+type flightRecorder interface {
+	Start() error
+	Stop()
+	WriteTo(w io.Writer) (int64, error)
+}
+
+// latencySpikeDetector detects the requests whose latency is much higher
+// than the average latency of their endpoint.
+type latencySpikeDetector struct {
+	mu          sync.Mutex
+	endpoints   map[string]*endpointLatency
+	lastCapture time.Time
+}
+
+type endpointLatency struct {
+	avg float64 // exponentially weighted moving average, in nanoseconds
+	n   int
+}
+
+// observe records the latency of a request to the endpoint at time now and
+// reports whether it is a latency spike which should be captured.
+func (d *latencySpikeDetector) observe(endpoint string, latency time.Duration, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.endpoints == nil {
+		d.endpoints = make(map[string]*endpointLatency)
+	}
+	e, ok := d.endpoints[endpoint]
+	if !ok {
+		if len(d.endpoints) >= maxLatencyEndpoints {
+			return false
+		}
+		e = &endpointLatency{avg: float64(latency)}
+		d.endpoints[endpoint] = e
+	}
+	spike := e.n >= minLatencyObservations &&
+		latency >= minLatencySpike &&
+		float64(latency) > latencySpikeFactor*e.avg &&
+		now.Sub(d.lastCapture) >= flightRecorderCooldown
+	e.avg += latencyEWMAAlpha * (float64(latency) - e.avg)
+	e.n++
+	if spike {
+		d.lastCapture = now
+	}
+	return spike
+}
+
+// flightRecording is an execution trace captured by the flight recorder.
+type flightRecording struct {
+	data            []byte
+	localRootSpanID uint64
+	latency         time.Duration
+}
+
+// flightRecordingSession captures execution traces of the latency spikes of the
+// endpoints with the flight recorder. The captures are uploaded along with
+// the next profiles.
+type flightRecordingSession struct {
+	recorder flightRecorder
+	detector latencySpikeDetector
+
+	mu      sync.Mutex
+	pending *flightRecording
+}
+
+// startFlightRecorder starts the flight recorder and the detection of the
+// latency spikes of the endpoints, if enabled and supported.
+func (p *profiler) startFlightRecorder() {
+	if !p.cfg.flightRecorderEnabled {
+		return
+	}
+	rec := newFlightRecorder(flightRecorderMinAge, uint64(p.cfg.traceConfig.Limit))
+	if rec == nil {
+		log.Warn("profiler: the flight recorder requires Go 1.25 or later, latency spikes won't be captured")
+		return
+	}
+	if err := rec.Start(); err != nil {
+		log.Warn("profiler: could not start the flight recorder: %v", err)
+		return
+	}
+	p.flightRecorder = &flightRecordingSession{recorder: rec}
+	traceprof.SetEndpointLatencyObserver(p.flightRecorder.observe)
+}
+
+// stopFlightRecorder stops the flight recorder, if started.
+func (p *profiler) stopFlightRecorder() {
+	if p.flightRecorder == nil {
+		return
+	}
+	traceprof.SetEndpointLatencyObserver(nil)
+	p.flightRecorder.recorder.Stop()
+}
+
+// observe is the traceprof.EndpointLatencyObserver capturing an execution
+// trace on latency spikes.
+func (s *flightRecordingSession) observe(endpoint string, localRootSpanID uint64, latency time.Duration) {
+	if !s.detector.observe(endpoint, latency, now()) {
+		return
+	}
+	// Don't block the request
+	go s.capture(endpoint, localRootSpanID, latency)
+}
+
+func (s *flightRecordingSession) capture(endpoint string, localRootSpanID uint64, latency time.Duration) {
+	var buf bytes.Buffer
+	if _, err := s.recorder.WriteTo(&buf); err != nil {
+		log.Warn("profiler: could not capture the flight recorder execution trace: %v", err)
+		return
+	}
+	log.Debug("profiler: captured a flight recorder execution trace of a %s request to %q", latency, endpoint)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = &flightRecording{
+		data:            buf.Bytes(),
+		localRootSpanID: localRootSpanID,
+		latency:         latency,
+	}
+}
+
+// addTo adds the pending capture, if any, to the batch, with the tags
+// correlating it to the trace of the latency spike.
+func (s *flightRecordingSession) addTo(bat *batch) {
+	s.mu.Lock()
+	rec := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	if rec == nil {
+		return
+	}
+	bat.addProfile(&profile{name: flightRecorderFilename, pt: executionTrace, data: rec.data})
+	bat.extraTags = append(bat.extraTags,
+		"flight_recorder_trigger:latency_spike",
+		fmt.Sprintf("flight_recorder_local_root_span_id:%d", rec.localRootSpanID),
+		fmt.Sprintf("flight_recorder_latency_ms:%d", rec.latency.Milliseconds()),
+	)
+}
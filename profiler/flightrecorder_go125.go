@@ -0,0 +1,22 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build go1.25
+
+package profiler
+
+import (
+	"runtime/trace"
+	"time"
+)
+
+// newFlightRecorder returns a runtime/trace flight recorder keeping at least
+// the last minAge of execution trace, within maxBytes.
+func newFlightRecorder(minAge time.Duration, maxBytes uint64) flightRecorder {
+	return trace.NewFlightRecorder(trace.FlightRecorderConfig{
+		MinAge:   minAge,
+		MaxBytes: maxBytes,
+	})
+}
@@ -0,0 +1,16 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build !go1.25
+
+package profiler
+
+import "time"
+
+// newFlightRecorder returns nil as the runtime/trace flight recorder is only
+// available as of Go 1.25.
+func newFlightRecorder(_ time.Duration, _ uint64) flightRecorder {
+	return nil
+}
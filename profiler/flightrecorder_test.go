@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencySpikeDetector(t *testing.T) {
+	var d latencySpikeDetector
+	start := time.Now()
+	for i := 0; i < minLatencyObservations; i++ {
+		// Not enough observations to detect a spike yet
+		assert.False(t, d.observe("GET /users", time.Duration(i%2+1)*time.Second, start))
+	}
+	assert.False(t, d.observe("GET /users", 2*time.Second, start), "not a spike")
+	assert.False(t, d.observe("GET /orders", 10*time.Second, start), "unknown endpoint")
+	assert.True(t, d.observe("GET /users", 10*time.Second, start))
+	assert.False(t, d.observe("GET /users", 20*time.Second, start.Add(time.Second)), "cooldown")
+	assert.True(t, d.observe("GET /users", 20*time.Second, start.Add(flightRecorderCooldown)))
+
+	var fast latencySpikeDetector
+	for i := 0; i < minLatencyObservations; i++ {
+		fast.observe("GET /health", time.Millisecond, start)
+	}
+	assert.False(t, fast.observe("GET /health", 50*time.Millisecond, start), "below the minimum latency spike")
+}
+
+type fakeFlightRecorder struct {
+	started bool
+}
+
+func (r *fakeFlightRecorder) Start() error { r.started = true; return nil }
+func (r *fakeFlightRecorder) Stop()        { r.started = false }
+func (r *fakeFlightRecorder) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, "execution trace")
+	return int64(n), err
+}
+
+func TestFlightRecorderCapture(t *testing.T) {
+	rec := &fakeFlightRecorder{}
+	s := &flightRecordingSession{recorder: rec}
+	traceprof.SetEndpointLatencyObserver(s.observe)
+	defer traceprof.SetEndpointLatencyObserver(nil)
+
+	for i := 0; i < minLatencyObservations; i++ {
+		traceprof.ObserveEndpointLatency("GET /users", 1, 100*time.Millisecond)
+	}
+	traceprof.ObserveEndpointLatency("GET /users", 42, time.Second)
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.pending != nil
+	}, time.Second, time.Millisecond)
+
+	var bat batch
+	s.addTo(&bat)
+	require.Len(t, bat.profiles, 1)
+	assert.Equal(t, flightRecorderFilename, bat.profiles[0].name)
+	assert.Equal(t, []byte("execution trace"), bat.profiles[0].data)
+	assert.Contains(t, bat.extraTags, "flight_recorder_local_root_span_id:42")
+	assert.Contains(t, bat.extraTags, "flight_recorder_latency_ms:1000")
+
+	// The capture is uploaded once
+	bat = batch{}
+	s.addTo(&bat)
+	assert.Empty(t, bat.profiles)
+}
@@ -0,0 +1,170 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	pprofile "github.com/google/pprof/profile"
+)
+
+const (
+	// goroutineLeakWindow is the number of consecutive profiling cycles over
+	// which the goroutines of a stack must keep growing to be reported as a
+	// leak suspect.
+	goroutineLeakWindow = 5
+
+	// goroutineLeakMinGoroutines is the minimum number of goroutines of a stack
+	// to be reported as a leak suspect, so that slowly starting worker pools
+	// aren't.
+	goroutineLeakMinGoroutines = 10
+
+	// maxGoroutineLeakSuspects is the maximum number of leak suspects
+	// reported per profile, the ones with the most goroutines.
+	maxGoroutineLeakSuspects = 10
+)
+
+// goroutineLeakDetector detects the goroutine stacks whose number of
+// goroutines grows monotonically over consecutive goroutine profiles.
+type goroutineLeakDetector struct {
+	// history holds the last number of goroutines of each stack, by stack
+	// signature
+	history map[string]*goroutineStackHistory
+	// suspects is the number of leak suspects found by the last report
+	suspects int
+}
+
+type goroutineStackHistory struct {
+	frames []string
+	counts []int64
+}
+
+// goroutineLeakSuspect is a goroutine stack suspected to leak.
+type goroutineLeakSuspect struct {
+	// Stack holds the functions of the stack, from the innermost one.
+	Stack []string `json:"stack"`
+	// Goroutines is the current number of goroutines of the stack.
+	Goroutines int64 `json:"goroutines"`
+	// History holds the number of goroutines of the stack over the last
+	// profiling cycles, from the oldest one.
+	History []int64 `json:"history"`
+}
+
+// goroutineLeakReport is the JSON document uploaded as the goroutine leak
+// profile.
+type goroutineLeakReport struct {
+	Goroutines int64                  `json:"goroutines"`
+	Suspects   []goroutineLeakSuspect `json:"suspects"`
+}
+
+// observe records the number of goroutines of every stack of the given
+// goroutine profile, in pprof format, and returns the current leak suspects.
+func (d *goroutineLeakDetector) observe(data []byte) (goroutineLeakReport, error) {
+	prof, err := pprofile.ParseData(data)
+	if err != nil {
+		return goroutineLeakReport{}, err
+	}
+	counts := make(map[string]int64)
+	frames := make(map[string][]string)
+	var total int64
+	for _, s := range prof.Sample {
+		if len(s.Value) == 0 {
+			continue
+		}
+		var stack []string
+		for _, loc := range s.Location {
+			for _, line := range loc.Line {
+				if line.Function != nil {
+					stack = append(stack, line.Function.Name)
+				}
+			}
+		}
+		sig := strings.Join(stack, "\n")
+		counts[sig] += s.Value[0]
+		frames[sig] = stack
+		total += s.Value[0]
+	}
+
+	if d.history == nil {
+		d.history = make(map[string]*goroutineStackHistory)
+	}
+	// Stacks which aren't running anymore are forgotten
+	for sig := range d.history {
+		if _, ok := counts[sig]; !ok {
+			delete(d.history, sig)
+		}
+	}
+	report := goroutineLeakReport{Goroutines: total, Suspects: []goroutineLeakSuspect{}}
+	for sig, n := range counts {
+		h, ok := d.history[sig]
+		if !ok {
+			h = &goroutineStackHistory{frames: frames[sig]}
+			d.history[sig] = h
+		}
+		h.counts = append(h.counts, n)
+		if len(h.counts) > goroutineLeakWindow {
+			h.counts = h.counts[1:]
+		}
+		if h.growing() {
+			report.Suspects = append(report.Suspects, goroutineLeakSuspect{
+				Stack:      h.frames,
+				Goroutines: n,
+				History:    slices.Clone(h.counts),
+			})
+		}
+	}
+	slices.SortFunc(report.Suspects, func(a, b goroutineLeakSuspect) int {
+		return cmp.Or(cmp.Compare(b.Goroutines, a.Goroutines), slices.Compare(a.Stack, b.Stack))
+	})
+	if len(report.Suspects) > maxGoroutineLeakSuspects {
+		report.Suspects = report.Suspects[:maxGoroutineLeakSuspects]
+	}
+	d.suspects = len(report.Suspects)
+	return report, nil
+}
+
+// growing reports whether the number of goroutines of the stack grew over
+// each of the last goroutineLeakWindow profiling cycles.
+func (h *goroutineStackHistory) growing() bool {
+	if len(h.counts) < goroutineLeakWindow || h.counts[len(h.counts)-1] < goroutineLeakMinGoroutines {
+		return false
+	}
+	for i := 1; i < len(h.counts); i++ {
+		if h.counts[i] <= h.counts[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// collectGoroutineLeakProfile collects a goroutine profile at the end of the
+// profiling period and reports the goroutine leak suspects, as JSON.
+func collectGoroutineLeakProfile(p *profiler) ([]byte, error) {
+	p.interruptibleSleep(p.cfg.period)
+
+	var goroutines bytes.Buffer
+	if err := p.lookupProfile("goroutine", &goroutines, 0); err != nil {
+		return nil, err
+	}
+	report, err := p.goroutineLeaks.observe(goroutines.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Suspects) > 0 {
+		tags := append(p.cfg.tags.Slice(), GoroutineLeakProfile.Tag())
+		p.cfg.statsd.Count("datadog.profiling.go.goroutine_leak_suspects", int64(len(report.Suspects)), tags, 1)
+	}
+	var buf bytes.Buffer
+	compressor := p.compressors[GoroutineLeakProfile]
+	compressor.Reset(&buf)
+	err = json.NewEncoder(compressor).Encode(report)
+	err = cmp.Or(err, compressor.Close())
+	return buf.Bytes(), err
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	pprofile "github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goroutineProfile returns a goroutine profile, in pprof format, with the
+// given number of goroutines per innermost function.
+func goroutineProfile(t *testing.T, goroutines map[string]int64) []byte {
+	prof := &pprofile.Profile{
+		SampleType: []*pprofile.ValueType{{Type: "goroutine", Unit: "count"}},
+	}
+	var id uint64
+	for name, n := range goroutines {
+		id++
+		fn := &pprofile.Function{ID: id, Name: name}
+		loc := &pprofile.Location{ID: id, Line: []pprofile.Line{{Function: fn}}}
+		prof.Function = append(prof.Function, fn)
+		prof.Location = append(prof.Location, loc)
+		prof.Sample = append(prof.Sample, &pprofile.Sample{Location: []*pprofile.Location{loc}, Value: []int64{n}})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, prof.Write(&buf))
+	return buf.Bytes()
+}
+
+func TestGoroutineLeakDetector(t *testing.T) {
+	var d goroutineLeakDetector
+	var report goroutineLeakReport
+	for i := int64(0); i < goroutineLeakWindow; i++ {
+		var err error
+		report, err = d.observe(goroutineProfile(t, map[string]int64{
+			"main.leaky":   10 + 5*i,
+			"main.stable":  100,
+			"main.small":   1 + i,
+			"main.growing": 20 + i - i%2, // grows, but not over each cycle
+		}))
+		require.NoError(t, err)
+		if i < goroutineLeakWindow-1 {
+			assert.Empty(t, report.Suspects)
+		}
+	}
+	require.Len(t, report.Suspects, 1)
+	assert.Equal(t, []string{"main.leaky"}, report.Suspects[0].Stack)
+	assert.Equal(t, int64(30), report.Suspects[0].Goroutines)
+	assert.Equal(t, []int64{10, 15, 20, 25, 30}, report.Suspects[0].History)
+	assert.Equal(t, int64(30+100+5+24), report.Goroutines)
+	assert.Equal(t, 1, d.suspects)
+
+	// The leak stops growing
+	report, err := d.observe(goroutineProfile(t, map[string]int64{"main.leaky": 30}))
+	require.NoError(t, err)
+	assert.Empty(t, report.Suspects)
+	assert.Len(t, d.history, 1)
+}
+
+func TestGoroutineLeakProfile(t *testing.T) {
+	var cycle int64
+	p, err := unstartedProfiler(WithProfileTypes(GoroutineLeakProfile))
+	require.NoError(t, err)
+	p.cfg.period = 0
+	p.testHooks.lookupProfile = func(_ string, w io.Writer, _ int) error {
+		cycle++
+		_, err := w.Write(goroutineProfile(t, map[string]int64{"main.leaky": 10 * cycle}))
+		return err
+	}
+	var data []byte
+	for i := 0; i < goroutineLeakWindow; i++ {
+		data, err = collectGoroutineLeakProfile(p)
+		require.NoError(t, err)
+	}
+	var report goroutineLeakReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Len(t, report.Suspects, 1)
+	assert.Equal(t, int64(50), report.Goroutines)
+}
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+)
+
+// WithEndpoint returns a copy of ctx with a profiler label attributing the
+// samples of the goroutines labeled with it to the given endpoint, e.g. the
+// name of a queue or of a batch job. This is the label set by the tracer
+// for the http and rpc requests, so that non-http units of work can be
+// filtered by endpoint in the Datadog profiler UI the same way. A hit of the
+// endpoint is also counted when DD_PROFILING_ENDPOINT_COUNT_ENABLED is set.
+//
+// The returned context must be applied to the current goroutine with
+// pprof.SetGoroutineLabels. Use DoEndpoint to label the goroutine for the
+// duration of a function call instead.
+func WithEndpoint(ctx context.Context, endpoint string) context.Context {
+	traceprof.GlobalEndpointCounter().Inc(endpoint)
+	return pprof.WithLabels(ctx, pprof.Labels(traceprof.TraceEndpoint, endpoint))
+}
+
+// DoEndpoint calls f with a copy of ctx attributing the profile samples to
+// the given endpoint, as WithEndpoint, and labels the current goroutine
+// with it during the call, as pprof.Do. The goroutines started by f inherit
+// the label.
+func DoEndpoint(ctx context.Context, endpoint string, f func(context.Context)) {
+	traceprof.GlobalEndpointCounter().Inc(endpoint)
+	pprof.Do(ctx, pprof.Labels(traceprof.TraceEndpoint, endpoint), f)
+}
+
+// SetLabel returns a copy of ctx with the profiler label key set to value,
+// and applies the labels of the returned context to the current goroutine.
+// The key must be passed to WithCustomProfilerLabelKeys for the label to be
+// available as an attribute for filtering in the Datadog profiler UI.
+func SetLabel(ctx context.Context, key, value string) context.Context {
+	ctx = pprof.WithLabels(ctx, pprof.Labels(key, value))
+	pprof.SetGoroutineLabels(ctx)
+	return ctx
+}
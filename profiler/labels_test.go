@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointLabels(t *testing.T) {
+	counter := traceprof.GlobalEndpointCounter()
+	defer counter.SetEnabled(counter.SetEnabled(true))
+	counter.GetAndReset()
+
+	t.Run("WithEndpoint", func(t *testing.T) {
+		ctx := WithEndpoint(context.Background(), "orders-queue")
+		endpoint, ok := pprof.Label(ctx, traceprof.TraceEndpoint)
+		assert.True(t, ok)
+		assert.Equal(t, "orders-queue", endpoint)
+	})
+
+	t.Run("DoEndpoint", func(t *testing.T) {
+		var called bool
+		DoEndpoint(context.Background(), "nightly-batch", func(ctx context.Context) {
+			called = true
+			endpoint, _ := pprof.Label(ctx, traceprof.TraceEndpoint)
+			assert.Equal(t, "nightly-batch", endpoint)
+		})
+		assert.True(t, called)
+	})
+
+	t.Run("SetLabel", func(t *testing.T) {
+		defer pprof.SetGoroutineLabels(context.Background())
+		ctx := SetLabel(WithEndpoint(context.Background(), "orders-queue"), "tenant", "acme")
+		tenant, _ := pprof.Label(ctx, "tenant")
+		assert.Equal(t, "acme", tenant)
+		endpoint, _ := pprof.Label(ctx, traceprof.TraceEndpoint)
+		assert.Equal(t, "orders-queue", endpoint)
+	})
+
+	assert.Equal(t, map[string]uint64{"orders-queue": 2, "nightly-batch": 1}, counter.GetAndReset())
+}
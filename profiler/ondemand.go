@@ -0,0 +1,145 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/remoteconfig"
+
+	rc "github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+const (
+	// onDemandCPUProfileRate is the CPU profile rate used during on-demand
+	// sessions, five times the default rate of runtime/pprof.
+	onDemandCPUProfileRate = 500
+
+	// defaultOnDemandDuration is the duration of on-demand sessions which
+	// don't specify one.
+	defaultOnDemandDuration = time.Minute
+
+	// maxOnDemandDuration bounds the duration of on-demand sessions, to
+	// limit the overhead of a session which would be forgotten.
+	maxOnDemandDuration = 10 * time.Minute
+)
+
+// onDemandProfileTypes are the profile types collected during on-demand
+// sessions, in addition to the enabled ones.
+var onDemandProfileTypes = []ProfileType{CPUProfile, HeapProfile, GoroutineProfile}
+
+// onDemandSession is a short profiling session triggered through remote
+// configuration, during which more profile types are collected with higher
+// rates.
+type onDemandSession struct {
+	mu    sync.Mutex
+	id    string
+	until time.Time
+}
+
+// start starts the session with the given ID for the given duration,
+// replacing any ongoing session. It returns false when the session was
+// already started, as remote configuration may deliver a request again.
+func (s *onDemandSession) start(id string, d time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id != "" && id == s.id {
+		return false
+	}
+	s.id = id
+	s.until = now().Add(d)
+	return true
+}
+
+// active returns the ID of the session, and whether it is active at t.
+func (s *onDemandSession) active(t time.Time) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id, t.Before(s.until)
+}
+
+// onDemandRequest is the on-demand profiling request received in the
+// lib_config of the APM_TRACING remote configuration product.
+type onDemandRequest struct {
+	ID              string `json:"id"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+func (r *onDemandRequest) duration() time.Duration {
+	d := time.Duration(r.DurationSeconds) * time.Second
+	if d <= 0 {
+		return defaultOnDemandDuration
+	}
+	return min(d, maxOnDemandDuration)
+}
+
+// onDemandRegistered reports whether onDemandUpdate is registered to the
+// remote config client. It is guarded by mu.
+var onDemandRegistered bool
+
+// startOnDemand starts the remote config client, unless the tracer already
+// did, and registers the callback of the on-demand profiling requests. The
+// requests are received through the APM_TRACING product, which is
+// subscribed to by the tracer, so that on-demand profiling requires the
+// tracer to be started with remote configuration enabled.
+func startOnDemand(cfg *config) {
+	if onDemandRegistered {
+		return
+	}
+	rcCfg := remoteconfig.DefaultClientConfig()
+	rcCfg.AgentURL = strings.TrimSuffix(cfg.agentURL, "/profiling/v1/input")
+	rcCfg.HTTP = &http.Client{Transport: cfg.httpClient.Transport, Timeout: rcCfg.HTTP.Timeout}
+	rcCfg.ServiceName = cfg.service
+	rcCfg.Env = cfg.env
+	rcCfg.AppVersion = cfg.version
+	if err := remoteconfig.Start(rcCfg); err != nil {
+		log.Warn("profiler: on-demand profiling: could not start remote configuration: %v", err)
+		return
+	}
+	if err := remoteconfig.RegisterCallback(onDemandUpdate); err != nil {
+		log.Warn("profiler: on-demand profiling: could not register to remote configuration: %v", err)
+		return
+	}
+	onDemandRegistered = true
+}
+
+// onDemandUpdate is the remote config callback starting on-demand sessions
+// on the active profiler.
+func onDemandUpdate(updates map[string]remoteconfig.ProductUpdate) map[string]rc.ApplyStatus {
+	statuses := make(map[string]rc.ApplyStatus)
+	for path, raw := range updates[rc.ProductAPMTracing] {
+		if raw == nil {
+			continue
+		}
+		var c struct {
+			LibConfig struct {
+				OnDemand *onDemandRequest `json:"profiling_on_demand"`
+			} `json:"lib_config"`
+		}
+		if err := json.Unmarshal(raw, &c); err != nil || c.LibConfig.OnDemand == nil {
+			// Not an on-demand profiling request, the tracer reports the
+			// status of the configuration.
+			continue
+		}
+		req := c.LibConfig.OnDemand
+		mu.Lock()
+		p := activeProfiler
+		mu.Unlock()
+		if p == nil || !p.cfg.onDemandEnabled {
+			continue
+		}
+		if p.onDemand.start(req.ID, req.duration()) {
+			log.Info("profiler: started on-demand profiling session %q for %s", req.ID, req.duration())
+		}
+		statuses[path] = rc.ApplyStatus{State: rc.ApplyStateAcknowledged}
+	}
+	return statuses
+}
@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/remoteconfig"
+
+	rc "github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnDemandRequestDuration(t *testing.T) {
+	for _, tc := range []struct {
+		seconds int
+		want    time.Duration
+	}{
+		{seconds: 0, want: defaultOnDemandDuration},
+		{seconds: -1, want: defaultOnDemandDuration},
+		{seconds: 30, want: 30 * time.Second},
+		{seconds: 3600, want: maxOnDemandDuration},
+	} {
+		r := onDemandRequest{DurationSeconds: tc.seconds}
+		assert.Equal(t, tc.want, r.duration())
+	}
+}
+
+func TestOnDemandSession(t *testing.T) {
+	t.Setenv("DD_REMOTE_CONFIGURATION_ENABLED", "false")
+	profiles := startTestProfiler(t, 10,
+		WithOnDemand(true),
+		WithProfileTypes(),
+		WithPeriod(10*time.Millisecond),
+	)
+
+	p := <-profiles
+	assert.NotContains(t, p.tags, "profile_trigger:on_demand")
+	assert.NotContains(t, p.event.Attachments, "cpu.pprof")
+
+	update := remoteconfig.ProductUpdate{
+		"datadog/2/APM_TRACING/config/config": []byte(`{"lib_config":{"profiling_on_demand":{"id":"incident-42","duration_seconds":60}}}`),
+	}
+	statuses := onDemandUpdate(map[string]remoteconfig.ProductUpdate{rc.ProductAPMTracing: update})
+	require.Equal(t, rc.ApplyStateAcknowledged, statuses["datadog/2/APM_TRACING/config/config"].State)
+
+	for {
+		p = <-profiles
+		if sliceContains(p.tags, "profile_trigger:on_demand") {
+			break
+		}
+	}
+	assert.Contains(t, p.tags, "on_demand_id:incident-42")
+	assert.Subset(t, p.event.Attachments, []string{"cpu.pprof", "delta-heap.pprof", "goroutines.pprof", "metrics.json"})
+}
+
+func TestOnDemandSessionDisabled(t *testing.T) {
+	t.Setenv("DD_REMOTE_CONFIGURATION_ENABLED", "false")
+	startTestProfiler(t, 1, WithProfileTypes(), WithPeriod(10*time.Millisecond))
+
+	update := remoteconfig.ProductUpdate{
+		"datadog/2/APM_TRACING/config/config": []byte(`{"lib_config":{"profiling_on_demand":{"id":"incident-42"}}}`),
+	}
+	statuses := onDemandUpdate(map[string]remoteconfig.ProductUpdate{rc.ProductAPMTracing: update})
+	assert.Empty(t, statuses)
+}
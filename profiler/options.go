@@ -21,6 +21,7 @@ import (
 	"unicode"
 
 	"github.com/DataDog/dd-trace-go/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/internal/apikey"
 	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 	"github.com/DataDog/dd-trace-go/v2/internal/osinfo"
@@ -87,6 +88,9 @@ var defaultProfileTypes = []ProfileType{MetricsProfile, CPUProfile, HeapProfile}
 type config struct {
 	apiKey    string
 	agentless bool
+	// apiKeyResolver resolves the API key in agentless mode, when set with
+	// WithAPIKeyResolver. It takes precedence over apiKey.
+	apiKeyResolver *apikey.Resolver
 	// targetURL is the upload destination URL. It will be set by the profiler on start to either apiURL or agentURL
 	// based on the other options.
 	targetURL            string
@@ -99,6 +103,7 @@ type config struct {
 	httpClient           *http.Client
 	tags                 immutable.StringSlice
 	customProfilerLabels []string
+	spanTagLabels        []string
 	types                map[ProfileType]struct{}
 	period               time.Duration
 	cpuDuration          time.Duration
@@ -107,6 +112,8 @@ type config struct {
 	maxGoroutinesWait    int
 	mutexFraction        int
 	blockRate            int
+	memProfileRate       int
+	maxProfileStacks     int
 	outputDir            string
 	deltaProfiles        bool
 	logStartup           bool
@@ -115,6 +122,14 @@ type config struct {
 	enabled              bool
 	flushOnExit          bool
 	compressionConfig    string
+	zstdCompression      bool
+	onDemandEnabled      bool
+	// flightRecorderEnabled enables the capture of execution traces of
+	// latency spikes with the flight recorder
+	flightRecorderEnabled bool
+	// runtimeEnvTags tags the profiles with the runtime settings and the
+	// cgroup limits of the process
+	runtimeEnvTags bool
 }
 
 // logStartup records the configuration to the configured logger in JSON format
@@ -182,10 +197,14 @@ func defaultConfig() (*config, error) {
 		mutexFraction:        DefaultMutexFraction,
 		uploadTimeout:        DefaultUploadTimeout,
 		maxGoroutinesWait:    1000, // arbitrary value, should limit STW to ~30ms
+		memProfileRate:       internal.IntEnv("DD_PROFILING_MEM_PROFILE_RATE", 0),
+		maxProfileStacks:     internal.IntEnv("DD_PROFILING_MAX_PROFILE_STACKS", 0),
 		deltaProfiles:        internal.BoolEnv("DD_PROFILING_DELTA", true),
 		logStartup:           internal.BoolEnv("DD_TRACE_STARTUP_LOGS", true),
 		endpointCountEnabled: internal.BoolEnv(traceprof.EndpointCountEnvVar, false),
 		compressionConfig:    os.Getenv("DD_PROFILING_DEBUG_COMPRESSION_SETTINGS"),
+		zstdCompression:      internal.BoolEnv("DD_PROFILING_ZSTD_COMPRESSION_ENABLED", false),
+		runtimeEnvTags:       internal.BoolEnv("DD_RUNTIME_ENV_TAGS_ENABLED", true),
 		traceConfig: executionTraceConfig{
 			Enabled: internal.BoolEnv("DD_PROFILING_EXECUTION_TRACE_ENABLED", executionTraceEnabledDefault),
 			Period:  internal.DurationEnv("DD_PROFILING_EXECUTION_TRACE_PERIOD", 15*time.Minute),
@@ -236,6 +255,8 @@ func defaultConfig() (*config, error) {
 		WithVersion(v)(&c)
 	}
 	c.flushOnExit = internal.BoolEnv("DD_PROFILING_FLUSH_ON_EXIT", false)
+	c.onDemandEnabled = internal.BoolEnv("DD_PROFILING_ON_DEMAND_ENABLED", false)
+	c.flightRecorderEnabled = internal.BoolEnv("DD_PROFILING_FLIGHT_RECORDER_ENABLED", false)
 
 	tags := make(map[string]string)
 	if v := os.Getenv("DD_TAGS"); v != "" {
@@ -300,6 +321,17 @@ func WithDeltaProfiles(enabled bool) Option {
 	}
 }
 
+// WithZstdCompression specifies if the profiles are compressed with zstd
+// before being uploaded, instead of gzip, or not at all for some profile
+// types. zstd compression significantly reduces the upload bandwidth, at a
+// similar CPU cost. The default value is false. This option takes precedence
+// over the DD_PROFILING_ZSTD_COMPRESSION_ENABLED environment variable.
+func WithZstdCompression(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.zstdCompression = enabled
+	}
+}
+
 // WithURL specifies the HTTP URL for the Datadog Profiling API.
 func WithURL(url string) Option {
 	return func(cfg *config) {
@@ -307,6 +339,18 @@ func WithURL(url string) Option {
 	}
 }
 
+// WithAPIKeyResolver specifies a function resolving the API key used to upload
+// the profiles in agentless mode, for instance from a secret manager. It is
+// called before the first upload and again when the key is rejected by the
+// intake, the upload being retried with the new key. The context passed to fn
+// is done when the upload times out. This option takes precedence over the
+// DD_API_KEY environment variable.
+func WithAPIKeyResolver(fn func(ctx context.Context) (string, error)) Option {
+	return func(cfg *config) {
+		cfg.apiKeyResolver = apikey.NewResolver(fn)
+	}
+}
+
 // WithPeriod specifies the interval at which to collect profiles.
 func WithPeriod(d time.Duration) Option {
 	return func(cfg *config) {
@@ -348,6 +392,41 @@ func MutexProfileFraction(rate int) Option {
 	}
 }
 
+// WithMemProfileRate sets runtime.MemProfileRate, the average number of bytes
+// allocated between two samples of the heap profile, when the profiler is
+// started. Lower rates make the heap profile more accurate, at the cost of a
+// higher overhead and of larger profiles, while higher rates make it
+// cheaper. A rate of 0 or less, the default, leaves runtime.MemProfileRate
+// unchanged (512KiB unless the program changes it). This option takes
+// precedence over the DD_PROFILING_MEM_PROFILE_RATE environment variable.
+//
+// The heap profile is accurate only for the allocations made after the rate
+// was changed, so the profiler should be started as early as possible when
+// using this option.
+func WithMemProfileRate(rate int) Option {
+	return func(cfg *config) {
+		cfg.memProfileRate = rate
+	}
+}
+
+// WithMaxProfileStacks caps the size of the heap, block, mutex and goroutine
+// profiles by keeping only the n stacks with the largest values for each
+// sample type, e.g. for each of the allocated and in-use bytes and objects of
+// the heap profile. The values of the other stacks are attributed to a single
+// "[trimmed stacks]" frame, so that the totals of the profiles are preserved.
+// The number of trimmed stacks is reported with the
+// datadog.profiling.go.trimmed_stacks metric. A value of 0 or less, the
+// default, disables the trimming. This option takes precedence over the
+// DD_PROFILING_MAX_PROFILE_STACKS environment variable.
+//
+// Trimming is meant for very large services whose profiles exceed the intake
+// limits, as it drops the stacks of the smaller contributors.
+func WithMaxProfileStacks(n int) Option {
+	return func(cfg *config) {
+		cfg.maxProfileStacks = n
+	}
+}
+
 // BlockProfileRate turns on block profiles with the given rate. We do not
 // recommend enabling this profile type, see DefaultBlockRate for more
 // information. The rate is given in nanoseconds and a block event with a given
@@ -532,3 +611,57 @@ func WithCustomProfilerLabelKeys(keys ...string) Option {
 		cfg.customProfilerLabels = append(cfg.customProfilerLabels, keys...)
 	}
 }
+
+// WithSpanTagsAsLabels specifies span tags which should be applied by the
+// tracer as [profiler labels] of the goroutines running the spans, so that
+// the CPU and goroutine profiles can be sliced by business dimensions, e.g.
+// "customer.tier" or "job.name", in the Datadog profiler UI. Only the tags
+// with string values are applied. The labels are updated when the tags are
+// set after the span is started, and are inherited by the child spans.
+//
+// The keys are also passed to WithCustomProfilerLabelKeys, and count
+// against its limit of 10 label keys.
+//
+// [profiler labels]: https://rakyll.org/profiler-labels/
+func WithSpanTagsAsLabels(keys ...string) Option {
+	return func(cfg *config) {
+		cfg.spanTagLabels = append(cfg.spanTagLabels, keys...)
+		cfg.customProfilerLabels = append(cfg.customProfilerLabels, keys...)
+	}
+}
+
+// WithOnDemand enables or disables on-demand profiling sessions, triggered
+// through remote configuration. During a session, which lasts one minute by
+// default and ten minutes at most, the CPU, heap and goroutine profiles are
+// collected regardless of the enabled profile types, the CPU profile is
+// collected over the whole profiling period with a higher sampling rate, and
+// the uploaded profiles are tagged with "profile_trigger:on_demand" and the
+// ID of the session.
+//
+// On-demand profiling requires the tracer to be started, with remote
+// configuration enabled. It is disabled by default, and can also be enabled
+// with the DD_PROFILING_ON_DEMAND_ENABLED environment variable.
+func WithOnDemand(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.onDemandEnabled = enabled
+	}
+}
+
+// WithFlightRecorder enables or disables the capture of execution traces
+// around the latency spikes of the endpoints, using the runtime/trace flight
+// recorder available as of Go 1.25. A latency spike is a request whose
+// latency, as measured by the duration of its local root span, is higher
+// than 100ms and four times the average latency of its endpoint. At most one
+// execution trace, of the last ten seconds or so, is captured per minute and
+// uploaded along with the next profiles, tagged with the ID of the local root
+// span of the request.
+//
+// This requires the tracer to be started with endpoint profiling enabled,
+// see DD_PROFILING_ENDPOINT_COLLECTION_ENABLED. It is disabled by default,
+// and can also be enabled with the DD_PROFILING_FLIGHT_RECORDER_ENABLED
+// environment variable.
+func WithFlightRecorder(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.flightRecorderEnabled = enabled
+	}
+}
@@ -133,6 +133,34 @@ func TestOptions(t *testing.T) {
 		assert.Contains(t, cfg.types, BlockProfile)
 	})
 
+	t.Run("WithSpanTagsAsLabels", func(t *testing.T) {
+		var cfg config
+		WithSpanTagsAsLabels("customer.tier", "job.name")(&cfg)
+		assert.Equal(t, []string{"customer.tier", "job.name"}, cfg.spanTagLabels)
+		assert.Equal(t, []string{"customer.tier", "job.name"}, cfg.customProfilerLabels)
+	})
+
+	t.Run("WithZstdCompression", func(t *testing.T) {
+		t.Setenv("DD_PROFILING_ZSTD_COMPRESSION_ENABLED", "true")
+		cfg, err := defaultConfig()
+		require.NoError(t, err)
+		assert.True(t, cfg.zstdCompression)
+		WithZstdCompression(false)(cfg)
+		assert.False(t, cfg.zstdCompression)
+	})
+
+	t.Run("WithMemProfileRate", func(t *testing.T) {
+		var cfg config
+		WithMemProfileRate(64 * 1024)(&cfg)
+		assert.Equal(t, 64*1024, cfg.memProfileRate)
+	})
+
+	t.Run("WithMaxProfileStacks", func(t *testing.T) {
+		var cfg config
+		WithMaxProfileStacks(500)(&cfg)
+		assert.Equal(t, 500, cfg.maxProfileStacks)
+	})
+
 	t.Run("WithProfileTypes", func(t *testing.T) {
 		var cfg config
 		WithProfileTypes(HeapProfile)(&cfg)
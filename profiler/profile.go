@@ -51,6 +51,12 @@ const (
 	expGoroutineWaitProfile
 	// MetricsProfile reports top-line metrics associated with user-specified profiles
 	MetricsProfile
+	// GoroutineLeakProfile reports the goroutine stacks suspected to leak,
+	// i.e. whose number of goroutines grew over each of the last five
+	// profiling cycles, with at least 10 goroutines. The uploads of the
+	// profiling cycles are tagged with the number of suspects, up to 10. This
+	// profile is not enabled by default.
+	GoroutineLeakProfile
 
 	// executionTrace is the runtime/trace execution tracer.
 	// This is private, as this trace requires special explicit configuration and
@@ -92,13 +98,19 @@ var profileTypes = map[ProfileType]profileType{
 			// Start the CPU profiler at the end of the profiling
 			// period so that we're sure to capture the CPU usage of
 			// this library, which mostly happens at the end
-			p.interruptibleSleep(p.cfg.period - p.cfg.cpuDuration)
-			if p.cfg.cpuProfileRate != 0 {
+			cpuDuration, cpuProfileRate := p.cfg.cpuDuration, p.cfg.cpuProfileRate
+			if p.onDemandActive.Load() {
+				// On-demand sessions profile the whole period
+				// with a higher rate.
+				cpuDuration, cpuProfileRate = p.cfg.period, onDemandCPUProfileRate
+			}
+			p.interruptibleSleep(p.cfg.period - cpuDuration)
+			if cpuProfileRate != 0 {
 				// The profile has to be set each time before
 				// profiling is started. Otherwise,
 				// runtime/pprof.StartCPUProfile will set the
 				// rate itself.
-				runtime.SetCPUProfileRate(p.cfg.cpuProfileRate)
+				runtime.SetCPUProfileRate(cpuProfileRate)
 			}
 
 			compressor := p.compressors[CPUProfile]
@@ -106,7 +118,7 @@ var profileTypes = map[ProfileType]profileType{
 			if err := p.startCPUProfile(compressor); err != nil {
 				return nil, err
 			}
-			p.interruptibleSleep(p.cfg.cpuDuration)
+			p.interruptibleSleep(cpuDuration)
 
 			// We want the CPU profiler to finish last so that it can
 			// properly record all of our profile processing work for
@@ -198,6 +210,11 @@ var profileTypes = map[ProfileType]profileType{
 			return buf.Bytes(), err
 		},
 	},
+	GoroutineLeakProfile: {
+		Name:     "goroutineleak",
+		Filename: "goroutineleaks.json",
+		Collect:  collectGoroutineLeakProfile,
+	},
 	executionTrace: {
 		Name:     "execution-trace",
 		Filename: "go.trace",
@@ -282,13 +299,27 @@ func collectGenericProfile(name string, pt ProfileType) func(p *profiler) ([]byt
 		p.interruptibleSleep(p.cfg.period)
 
 		var buf bytes.Buffer
+		tags := append(p.cfg.tags.Slice(), fmt.Sprintf("profile_type:%s", name))
 		dp, ok := p.deltas[pt]
 		if !ok || !p.cfg.deltaProfiles {
 			compressor := p.compressors[pt]
 			compressor.Reset(&buf)
-			err := p.lookupProfile(name, compressor, 0)
+			if p.cfg.maxProfileStacks <= 0 {
+				err := p.lookupProfile(name, compressor, 0)
+				err = cmp.Or(err, compressor.Close())
+				return buf.Bytes(), err
+			}
+			var full bytes.Buffer
+			if err := p.lookupProfile(name, &full, 0); err != nil {
+				return nil, err
+			}
+			trimmed, err := trimProfile(full.Bytes(), p.cfg.maxProfileStacks, compressor)
 			err = cmp.Or(err, compressor.Close())
-			return buf.Bytes(), err
+			if err != nil {
+				return nil, fmt.Errorf("trim profile error: %s", err)
+			}
+			p.cfg.statsd.Count("datadog.profiling.go.trimmed_stacks", int64(trimmed), tags, 1)
+			return buf.Bytes(), nil
 		}
 
 		if err := p.lookupProfile(name, &buf, 0); err != nil {
@@ -297,11 +328,13 @@ func collectGenericProfile(name string, pt ProfileType) func(p *profiler) ([]byt
 
 		start := time.Now()
 		delta, err := dp.Delta(buf.Bytes())
-		tags := append(p.cfg.tags.Slice(), fmt.Sprintf("profile_type:%s", name))
 		p.cfg.statsd.Timing("datadog.profiling.go.delta_time", time.Since(start), tags, 1)
 		if err != nil {
 			return nil, fmt.Errorf("delta profile error: %s", err)
 		}
+		if dp.maxStacks > 0 {
+			p.cfg.statsd.Count("datadog.profiling.go.trimmed_stacks", int64(dp.trimmed), tags, 1)
+		}
 		return delta, err
 	}
 }
@@ -389,6 +422,13 @@ type fastDeltaProfiler struct {
 	buf        bytes.Buffer
 	gzr        gzip.Reader
 	compressor compressor
+	// maxStacks is the number of stacks the delta profiles are trimmed to,
+	// see trimProfile. No trimming is done when it is 0.
+	maxStacks int
+	// trimmed is the number of stacks trimmed from the last delta profile
+	trimmed int
+	// untrimmed holds the delta profile before trimming
+	untrimmed bytes.Buffer
 }
 
 func newFastDeltaProfiler(compressor compressor, v ...pprofutils.ValueType) *fastDeltaProfiler {
@@ -417,8 +457,18 @@ func (fdp *fastDeltaProfiler) Delta(data []byte) (b []byte, err error) {
 	fdp.buf.Reset()
 	fdp.compressor.Reset(&fdp.buf)
 
-	if err = fdp.dc.Delta(data, fdp.compressor); err != nil {
-		return nil, fmt.Errorf("error computing delta: %v", err)
+	if fdp.maxStacks <= 0 {
+		if err = fdp.dc.Delta(data, fdp.compressor); err != nil {
+			return nil, fmt.Errorf("error computing delta: %v", err)
+		}
+	} else {
+		fdp.untrimmed.Reset()
+		if err = fdp.dc.Delta(data, &fdp.untrimmed); err != nil {
+			return nil, fmt.Errorf("error computing delta: %v", err)
+		}
+		if fdp.trimmed, err = trimProfile(fdp.untrimmed.Bytes(), fdp.maxStacks, fdp.compressor); err != nil {
+			return nil, fmt.Errorf("error trimming delta: %v", err)
+		}
 	}
 	if err = fdp.compressor.Close(); err != nil {
 		return nil, fmt.Errorf("error flushing gzip writer: %v", err)
@@ -18,6 +18,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/DataDog/dd-trace-go/v2/internal"
@@ -36,8 +37,9 @@ const customProfileLabelLimit = 10
 var (
 	mu             sync.Mutex
 	activeProfiler *profiler
-	containerID    = internal.ContainerID() // replaced in tests
-	entityID       = internal.EntityID()    // replaced in tests
+	containerID    = internal.ContainerID()         // replaced in tests
+	entityID       = internal.EntityID()            // replaced in tests
+	externalEnv    = internal.ExternalEnvironment() // replaced in tests
 
 	// errProfilerStopped is a sentinel for suppressng errors if we are
 	// about to stop the profiler
@@ -69,6 +71,7 @@ func Start(opts ...Option) error {
 	activeProfiler = p
 	activeProfiler.run()
 	traceprof.SetProfilerEnabled(true)
+	traceprof.SetSpanTagLabels(p.cfg.spanTagLabels)
 	return nil
 }
 
@@ -80,6 +83,7 @@ func Stop() {
 		activeProfiler.stop()
 		activeProfiler = nil
 		traceprof.SetProfilerEnabled(false)
+		traceprof.SetSpanTagLabels(nil)
 	}
 	mu.Unlock()
 }
@@ -103,6 +107,19 @@ type profiler struct {
 
 	// lastTrace is the last time an execution trace was collected
 	lastTrace time.Time
+
+	// goroutineLeaks detects the goroutine leaks reported by the
+	// GoroutineLeakProfile
+	goroutineLeaks goroutineLeakDetector
+
+	// flightRecorder captures execution traces of latency spikes, if enabled
+	flightRecorder *flightRecordingSession
+
+	// onDemand is the on-demand profiling session, if any
+	onDemand onDemandSession
+	// onDemandActive reports whether the current profiling cycle is part of
+	// an on-demand session
+	onDemandActive atomic.Bool
 }
 
 // testHooks are functions that are replaced during testing which would normally
@@ -172,7 +189,7 @@ func newProfiler(opts ...Option) (*profiler, error) {
 	// Agentless upload is disabled by default as of v1.30.0, but
 	// DD_PROFILING_AGENTLESS can be set to enable it for testing and debugging.
 	if cfg.agentless {
-		if !isAPIKeyValid(cfg.apiKey) {
+		if cfg.apiKeyResolver == nil && !isAPIKeyValid(cfg.apiKey) {
 			return nil, errAgentlessUploadRequiresAPIKey
 		}
 		// Always warn people against using this mode for now. All customers should
@@ -258,9 +275,18 @@ func newProfiler(opts ...Option) (*profiler, error) {
 	if p.cfg.traceConfig.Enabled {
 		types = append(types, executionTrace)
 	}
+	// The profile types collected during on-demand sessions must be
+	// initialized as well, even if they aren't enabled.
+	if p.cfg.onDemandEnabled {
+		for _, pt := range onDemandProfileTypes {
+			if !slices.Contains(types, pt) {
+				types = append(types, pt)
+			}
+		}
+	}
 	for _, pt := range types {
 		isDelta := len(profileTypes[pt].DeltaValues) > 0
-		in, out := compressionStrategy(pt, isDelta, p.cfg.compressionConfig)
+		in, out := compressionStrategy(pt, isDelta, p.cfg.compressionConfig, p.cfg.zstdCompression)
 		compressor, err := newCompressionPipeline(in, out)
 		if err != nil {
 			return nil, err
@@ -269,6 +295,7 @@ func newProfiler(opts ...Option) (*profiler, error) {
 
 		if isDelta {
 			p.deltas[pt] = newFastDeltaProfiler(compressor, profileTypes[pt].DeltaValues...)
+			p.deltas[pt].maxStacks = p.cfg.maxProfileStacks
 		}
 	}
 	p.uploadFunc = p.upload
@@ -287,7 +314,14 @@ func (p *profiler) run() {
 	if profileEnabled(BlockProfile) {
 		runtime.SetBlockProfileRate(p.cfg.blockRate)
 	}
+	if p.cfg.memProfileRate > 0 {
+		runtime.MemProfileRate = p.cfg.memProfileRate
+	}
 	startTelemetry(p.cfg)
+	if p.cfg.onDemandEnabled {
+		startOnDemand(p.cfg)
+	}
+	p.startFlightRecorder()
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
@@ -341,6 +375,9 @@ func (p *profiler) collect(ticker <-chan time.Time) {
 			},
 			customAttributes: p.cfg.customProfilerLabels,
 		}
+		if p.cfg.runtimeEnvTags {
+			bat.extraTags = append(bat.extraTags, runtimeEnvTags()...)
+		}
 		p.seq++
 
 		clear(completed)
@@ -354,6 +391,13 @@ func (p *profiler) collect(ticker <-chan time.Time) {
 
 		profileTypes := p.enabledProfileTypes()
 
+		id, onDemand := p.onDemand.active(bat.start)
+		p.onDemandActive.Store(onDemand)
+		if onDemand {
+			profileTypes = p.onDemandProfileTypes(profileTypes)
+			bat.extraTags = append(bat.extraTags, "profile_trigger:on_demand", "on_demand_id:"+id)
+		}
+
 		// Decide whether we should record an execution trace.
 		// Randomly record a trace with probability (profile period) / (trace period).
 		// Note that if the trace period is equal to or less than the profile period,
@@ -403,6 +447,9 @@ func (p *profiler) collect(ticker <-chan time.Time) {
 				// that the uploads are more easily discoverable in the UI.
 				bat.extraTags = append(bat.extraTags, "go_execution_traced:yes")
 			}
+			if prof.pt == GoroutineLeakProfile {
+				bat.extraTags = append(bat.extraTags, fmt.Sprintf("goroutine_leak_suspects:%d", p.goroutineLeaks.suspects))
+			}
 			bat.addProfile(prof)
 		}
 
@@ -435,29 +482,36 @@ func (p *profiler) collect(ticker <-chan time.Time) {
 		// The default configuration of the profiler (cpu duration = profiling
 		// period) results in a factor of 1.
 		bat.end = time.Now()
+		if p.flightRecorder != nil {
+			p.flightRecorder.addTo(&bat)
+		}
 		// Upload profiling data.
 		p.enqueueUpload(bat)
 	}
 }
 
+// profileTypesOrder is the order in which the profile types are collected,
+// see enabledProfileTypes.
+var profileTypesOrder = []ProfileType{
+	CPUProfile,
+	HeapProfile,
+	BlockProfile,
+	MutexProfile,
+	GoroutineProfile,
+	expGoroutineWaitProfile,
+	GoroutineLeakProfile,
+	MetricsProfile,
+	executionTrace,
+}
+
 // enabledProfileTypes returns the enabled profile types in a deterministic
 // order. The CPU profile always comes first because people might spot
 // interesting events in there and then try to look for the counter-part event
 // in the mutex/heap/block profile. Deterministic ordering is also important
 // for delta profiles, otherwise they'd cover varying profiling periods.
 func (p *profiler) enabledProfileTypes() []ProfileType {
-	order := []ProfileType{
-		CPUProfile,
-		HeapProfile,
-		BlockProfile,
-		MutexProfile,
-		GoroutineProfile,
-		expGoroutineWaitProfile,
-		MetricsProfile,
-		executionTrace,
-	}
 	enabled := []ProfileType{}
-	for _, t := range order {
+	for _, t := range profileTypesOrder {
 		if _, ok := p.cfg.types[t]; ok {
 			enabled = append(enabled, t)
 		}
@@ -465,6 +519,18 @@ func (p *profiler) enabledProfileTypes() []ProfileType {
 	return enabled
 }
 
+// onDemandProfileTypes returns the given enabled profile types with the ones
+// collected during on-demand sessions, in the order of enabledProfileTypes.
+func (p *profiler) onDemandProfileTypes(enabled []ProfileType) []ProfileType {
+	types := []ProfileType{}
+	for _, t := range profileTypesOrder {
+		if slices.Contains(enabled, t) || slices.Contains(onDemandProfileTypes, t) {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
 // enqueueUpload pushes a batch of profiles onto the queue to be uploaded. If there is no room, it will
 // evict the oldest profile to make some. Typically a batch would be one of each enabled profile.
 func (p *profiler) enqueueUpload(bat batch) {
@@ -549,6 +615,7 @@ func (p *profiler) stop() {
 		close(p.exit)
 	})
 	p.wg.Wait()
+	p.stopFlightRecorder()
 	if p.cfg.logStartup {
 		log.Info("Profiling stopped")
 	}
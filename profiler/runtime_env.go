@@ -0,0 +1,26 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/DataDog/dd-trace-go/v2/internal"
+)
+
+// runtimeEnvTags returns the runtime settings and the cgroup limits of the
+// process as sorted profile tags, e.g. "go.gomaxprocs:4", to correlate the
+// profiles with CPU throttling or GC pressure.
+func runtimeEnvTags() []string {
+	env := internal.RuntimeEnvTags()
+	tags := make([]string, 0, len(env))
+	for k, v := range env {
+		tags = append(tags, k+":"+strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,20 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeEnvTags(t *testing.T) {
+	tags := runtimeEnvTags()
+	assert.Contains(t, tags, fmt.Sprintf("go.gomaxprocs:%d", runtime.GOMAXPROCS(0)))
+	assert.IsIncreasing(t, tags)
+}
@@ -25,9 +25,10 @@ func startTelemetry(c *config) {
 	telemetry.RegisterAppConfigs(telemetryConfiguration(c)...)
 	if telemetry.GlobalClient() == nil {
 		client, err := telemetry.NewClient(c.service, c.env, c.version, telemetry.ClientConfig{
-			HTTPClient: c.httpClient,
-			APIKey:     c.apiKey,
-			AgentURL:   c.agentURL,
+			HTTPClient:     c.httpClient,
+			APIKey:         c.apiKey,
+			APIKeyResolver: c.apiKeyResolver,
+			AgentURL:       c.agentURL,
 		})
 		if err != nil {
 			log.Debug("profiler: failed to create telemetry client: %v", err)
@@ -51,20 +52,27 @@ func telemetryConfiguration(c *config) []telemetry.Configuration {
 		{Name: "block_profile_rate", Value: c.blockRate},
 		{Name: "mutex_profile_fraction", Value: c.mutexFraction},
 		{Name: "max_goroutines_wait", Value: c.maxGoroutinesWait},
+		{Name: "mem_profile_rate", Value: c.memProfileRate},
+		{Name: "max_profile_stacks", Value: c.maxProfileStacks},
 		{Name: "cpu_profile_enabled", Value: profileEnabled(CPUProfile)},
 		{Name: "heap_profile_enabled", Value: profileEnabled(HeapProfile)},
 		{Name: "block_profile_enabled", Value: profileEnabled(BlockProfile)},
 		{Name: "mutex_profile_enabled", Value: profileEnabled(MutexProfile)},
 		{Name: "goroutine_profile_enabled", Value: profileEnabled(GoroutineProfile)},
 		{Name: "goroutine_wait_profile_enabled", Value: profileEnabled(expGoroutineWaitProfile)},
+		{Name: "goroutine_leak_profile_enabled", Value: profileEnabled(GoroutineLeakProfile)},
 		{Name: "upload_timeout", Value: c.uploadTimeout.String()},
 		{Name: "execution_trace_enabled", Value: c.traceConfig.Enabled},
 		{Name: "execution_trace_period", Value: c.traceConfig.Period.String()},
 		{Name: "execution_trace_size_limit", Value: c.traceConfig.Limit},
 		{Name: "endpoint_count_enabled", Value: c.endpointCountEnabled},
 		{Name: "num_custom_profiler_label_keys", Value: len(c.customProfilerLabels)},
+		{Name: "num_span_tag_label_keys", Value: len(c.spanTagLabels)},
 		{Name: "enabled", Value: c.enabled},
 		{Name: "flush_on_exit", Value: c.flushOnExit},
 		{Name: "debug_compression_settings", Value: c.compressionConfig},
+		{Name: "zstd_compression_enabled", Value: c.zstdCompression},
+		{Name: "on_demand_enabled", Value: c.onDemandEnabled},
+		{Name: "flight_recorder_enabled", Value: c.flightRecorderEnabled},
 	}
 }
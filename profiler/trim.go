@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"cmp"
+	"io"
+	"slices"
+
+	pprofile "github.com/google/pprof/profile"
+)
+
+// trimmedFunctionName is the name of the synthetic frame of the sample
+// holding the values of the samples removed by trimProfile.
+const trimmedFunctionName = "[trimmed stacks]"
+
+// trimProfile keeps the maxStacks samples with the largest values of the
+// pprof profile data, for each of its sample types, and writes the resulting
+// profile to w. The values of the removed samples are summed into a single
+// sample of a synthetic frame, so that the totals of the profile are
+// preserved. The profile is written gzip-compressed if data is, and
+// uncompressed otherwise. It returns the number of removed samples.
+func trimProfile(data []byte, maxStacks int, w io.Writer) (int, error) {
+	prof, err := pprofile.ParseData(data)
+	if err != nil {
+		return 0, err
+	}
+	write := prof.WriteUncompressed
+	if isGzipData(data) {
+		write = prof.Write
+	}
+	if len(prof.Sample) <= maxStacks {
+		_, err := w.Write(data)
+		return 0, err
+	}
+
+	keep := make([]bool, len(prof.Sample))
+	order := make([]int, len(prof.Sample))
+	for v := range prof.SampleType {
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(abs(prof.Sample[b].Value[v]), abs(prof.Sample[a].Value[v]))
+		})
+		for _, i := range order[:maxStacks] {
+			keep[i] = true
+		}
+	}
+
+	trimmed := &pprofile.Sample{Value: make([]int64, len(prof.SampleType))}
+	samples := prof.Sample[:0]
+	var removed int
+	for i, s := range prof.Sample {
+		if keep[i] {
+			samples = append(samples, s)
+			continue
+		}
+		for v, n := range s.Value {
+			trimmed.Value[v] += n
+		}
+		removed++
+	}
+	fn := &pprofile.Function{Name: trimmedFunctionName, SystemName: trimmedFunctionName}
+	for _, f := range prof.Function {
+		fn.ID = max(fn.ID, f.ID)
+	}
+	fn.ID++
+	loc := &pprofile.Location{Line: []pprofile.Line{{Function: fn}}}
+	for _, l := range prof.Location {
+		loc.ID = max(loc.ID, l.ID)
+	}
+	loc.ID++
+	prof.Function = append(prof.Function, fn)
+	prof.Location = append(prof.Location, loc)
+	trimmed.Location = []*pprofile.Location{loc}
+	prof.Sample = append(samples, trimmed)
+	return removed, write(w)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
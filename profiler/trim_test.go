@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	pprofile "github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimProfile(t *testing.T) {
+	// Each sample has a distinct function, with the values
+	// {alloc_space, inuse_space}.
+	values := [][]int64{{100, 1}, {50, 2}, {10, 300}, {5, 4}, {1, 5}}
+	prof := &pprofile.Profile{
+		SampleType: []*pprofile.ValueType{{Type: "alloc_space", Unit: "bytes"}, {Type: "inuse_space", Unit: "bytes"}},
+	}
+	for i, v := range values {
+		fn := &pprofile.Function{ID: uint64(i + 1), Name: fmt.Sprintf("fn%d", i)}
+		loc := &pprofile.Location{ID: uint64(i + 1), Line: []pprofile.Line{{Function: fn}}}
+		prof.Function = append(prof.Function, fn)
+		prof.Location = append(prof.Location, loc)
+		prof.Sample = append(prof.Sample, &pprofile.Sample{Location: []*pprofile.Location{loc}, Value: v})
+	}
+
+	for _, compressed := range []bool{true, false} {
+		t.Run(fmt.Sprintf("compressed=%v", compressed), func(t *testing.T) {
+			var data bytes.Buffer
+			if compressed {
+				require.NoError(t, prof.Write(&data))
+			} else {
+				require.NoError(t, prof.WriteUncompressed(&data))
+			}
+
+			var out bytes.Buffer
+			removed, err := trimProfile(data.Bytes(), 2, &out)
+			require.NoError(t, err)
+			assert.Equal(t, 1, removed)
+			assert.Equal(t, compressed, isGzipData(out.Bytes()))
+
+			got, err := pprofile.ParseData(out.Bytes())
+			require.NoError(t, err)
+			stacks := make(map[string][]int64)
+			for _, s := range got.Sample {
+				stacks[s.Location[0].Line[0].Function.Name] = s.Value
+			}
+			// fn0 and fn1 have the largest alloc_space, fn2 and fn4 the
+			// largest inuse_space.
+			assert.Equal(t, map[string][]int64{
+				"fn0":               {100, 1},
+				"fn1":               {50, 2},
+				"fn2":               {10, 300},
+				"fn4":               {1, 5},
+				trimmedFunctionName: {5, 4},
+			}, stacks)
+		})
+	}
+
+	t.Run("small", func(t *testing.T) {
+		var data, out bytes.Buffer
+		require.NoError(t, prof.Write(&data))
+		removed, err := trimProfile(data.Bytes(), len(values), &out)
+		require.NoError(t, err)
+		assert.Zero(t, removed)
+		assert.Equal(t, data.Bytes(), out.Bytes())
+	})
+}
@@ -98,7 +98,7 @@ func (p *profiler) doRequest(bat batch) error {
 	if err != nil {
 		return err
 	}
-	if p.cfg.apiKey != "" {
+	if p.cfg.apiKey != "" && p.cfg.apiKeyResolver == nil {
 		req.Header.Set("DD-API-KEY", p.cfg.apiKey)
 	}
 	if containerID != "" {
@@ -107,9 +107,18 @@ func (p *profiler) doRequest(bat batch) error {
 	if entityID != "" {
 		req.Header.Set("Datadog-Entity-ID", entityID)
 	}
+	if externalEnv != "" {
+		req.Header.Set("Datadog-External-Env", externalEnv)
+	}
 	req.Header.Set("Content-Type", contentType)
 
-	resp, err := p.cfg.httpClient.Do(req)
+	var resp *http.Response
+	if p.cfg.apiKeyResolver != nil && p.cfg.targetURL == p.cfg.apiURL {
+		// The upload is retried with the key resolved again if it is rejected.
+		resp, err = p.cfg.apiKeyResolver.Do(p.cfg.httpClient, req)
+	} else {
+		resp, err = p.cfg.httpClient.Do(req)
+	}
 	if err != nil {
 		return &retriableError{err}
 	}
@@ -6,6 +6,7 @@
 package profiler
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -100,6 +101,31 @@ func TestOldAgent(t *testing.T) {
 	assert.Equal(t, errOldAgent, err)
 }
 
+func TestAPIKeyResolver(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("DD-API-KEY"))
+		if r.Header.Get("DD-API-KEY") != "rotated-key" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("DD_PROFILING_AGENTLESS", "true")
+	resolved := []string{"revoked-key", "rotated-key"}
+	p, err := unstartedProfiler(
+		WithURL(server.URL),
+		WithAPIKeyResolver(func(context.Context) (string, error) {
+			key := resolved[0]
+			resolved = resolved[1:]
+			return key, nil
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.doRequest(testBatch))
+	require.NoError(t, p.doRequest(testBatch))
+	assert.Equal(t, []string{"revoked-key", "rotated-key", "rotated-key"}, keys)
+}
+
 func TestEntityContainerIDHeaders(t *testing.T) {
 	t.Run("set", func(t *testing.T) {
 		defer func(cid, eid string) { containerID = cid; entityID = eid }(containerID, entityID)
@@ -120,6 +146,13 @@ func TestEntityContainerIDHeaders(t *testing.T) {
 	})
 }
 
+func TestExternalEnvHeader(t *testing.T) {
+	defer func(env string) { externalEnv = env }(externalEnv)
+	externalEnv = "it-false,cn-app,pu-7ad8c6e0-5b4d-4b9b-a5fb-9f44fbbd8a8a"
+	profile := doOneShortProfileUpload(t)
+	assert.Equal(t, externalEnv, profile.headers.Get("Datadog-External-Env"))
+}
+
 func TestGitMetadata(t *testing.T) {
 	t.Run("git-metadata-from-dd-tags", func(t *testing.T) {
 		t.Setenv(maininternal.EnvDDTags, "git.commit.sha:123456789ABCD git.repository_url:github.com/user/repo go_path:somepath")